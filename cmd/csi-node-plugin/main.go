@@ -0,0 +1,250 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command csi-node-plugin is the node-only variant of the PanFS CSI plugin. It serves the
+// identity and node services only and is built with the nodeonly tag, which excludes the
+// controller service and the SSH client of the realm:
+//
+//	go build -tags nodeonly ./cmd/csi-node-plugin
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/logging"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/realm"
+
+	"k8s.io/klog/v2"
+)
+
+var version = "unversioned"
+
+// config holds the configuration for the CSI node plugin.
+type config struct {
+	endpoint         string
+	driverName       string
+	mode             string
+	sanity           bool
+	metricsAddress   string
+	slowRPCThreshold time.Duration
+	sloThreshold     time.Duration
+	sloWindow        time.Duration
+
+	mountProfilesFile  string
+	canaryVolume       string
+	verifyMounts       bool
+	dataPathCheckPort  string
+	dataPathCheckTTL   time.Duration
+	stagedMounts       bool
+	enableEphemeral    bool
+	ephemeralParent    string
+	targetDirMode      string
+	targetDirUID       int
+	targetDirGID       int
+	topologyRealms     string
+	topologyRealmsFile string
+	topologyNodeLabels bool
+	defaultSecretPath  string
+	forbidPasswordAuth bool
+	realmConfig        string
+	unmountConcurrency int
+	unmountFromCPU     bool
+
+	errorAggregationWindow time.Duration
+
+	quotaRefresh             bool
+	quotaRefreshFixedVersion string
+}
+
+var (
+	cfg           config
+	log           klog.Logger
+	logAggregator *logging.Aggregator
+)
+
+// init initializes the command-line flags and logging.
+func init() {
+	// init klog flags. See klog docs for details
+	klog.InitFlags(nil)
+
+	flag.StringVar(&cfg.endpoint, "endpoint", "/tmp/csi.sock", "CSI endpoint: unix socket path, unix:// or tcp:// URL")
+	flag.StringVar(&cfg.driverName, "driverName", driver.DefaultDriverName, "Name of CSI driver")
+	flag.StringVar(&cfg.mode, "mode", string(driver.PluginModeNode), "Component the plugin runs as, reported in the plugin info, metrics and logs: only node is supported by the node plugin")
+	flag.StringVar(&cfg.metricsAddress, "metrics-address", "", "Address to expose Prometheus metrics on, e.g. ':9090' (disabled if empty)")
+	flag.DurationVar(&cfg.slowRPCThreshold, "slow-rpc-threshold", driver.DefaultSlowRPCThreshold, "Duration after which an RPC is logged as slow (0 disables)")
+	flag.DurationVar(&cfg.sloThreshold, "slo-threshold", driver.DefaultSLOThreshold, "Duration within which volume operations must succeed to count towards the provisioning SLO (0 disables the SLO)")
+	flag.DurationVar(&cfg.sloWindow, "slo-window", driver.DefaultSLOWindow, "Rolling window of the provisioning SLO ratio, also the interval of its summary log")
+	flag.StringVar(&cfg.mountProfilesFile, "mount-profiles", "", "JSON file with named sets of PanFS mount options selectable by storage classes")
+	flag.StringVar(&cfg.canaryVolume, "canary-volume", "", "Volume '<realm>/<volume>' mounted read-only at node plugin start to verify the PanFS client (disabled if empty)")
+	flag.BoolVar(&cfg.verifyMounts, "verify-mounts", false, "Verify IO on published volumes (statfs and read of the mount root) and fail the publish if it fails, overridden by the verifyMount volume parameter")
+	flag.StringVar(&cfg.dataPathCheckPort, "data-path-check-port", "", "TCP port of the realm data address probed from the node before mounting a volume (disabled if empty)")
+	flag.DurationVar(&cfg.dataPathCheckTTL, "data-path-check-ttl", driver.DefaultDataPathCheckTTL, "How long the result of a realm data path probe is reused")
+	flag.BoolVar(&cfg.stagedMounts, "staged-mounts", false, "Mount volumes once per node in NodeStageVolume and bind mount them into the pods, reading the realm credentials from the node-stage secret")
+	flag.BoolVar(&cfg.enableEphemeral, "enable-ephemeral", false, "Publish CSI inline ephemeral volumes as directories of the parent volume, removed on unpublish")
+	flag.StringVar(&cfg.ephemeralParent, "ephemeral-parent-volume", "", "Realm volume holding the directories of inline ephemeral volumes, required with -enable-ephemeral")
+	flag.StringVar(&cfg.targetDirMode, "target-dir-mode", "0755", "Octal mode of publish target directories created by the node plugin, overridden by the targetDirMode volume parameter")
+	flag.IntVar(&cfg.targetDirUID, "target-dir-uid", -1, "Owner of publish target directories created by the node plugin (-1 keeps the owner of the plugin), overridden by the targetDirUID volume parameter")
+	flag.IntVar(&cfg.targetDirGID, "target-dir-gid", -1, "Group of publish target directories created by the node plugin (-1 keeps the group of the plugin), overridden by the targetDirGID volume parameter")
+	flag.StringVar(&cfg.topologyRealms, "topology-realms", "", "Comma-separated addresses of the realms reachable by the node, reported as topology segments")
+	flag.StringVar(&cfg.topologyRealmsFile, "topology-realms-file", "", "File with the addresses of the realms reachable by the node, one per line, in addition to --topology-realms")
+	flag.BoolVar(&cfg.topologyNodeLabels, "topology-node-labels", false, "Report the realm topology labels of the node, e.g. set per node pool, as reachable realms")
+	flag.StringVar(&cfg.defaultSecretPath, "default-secret-path", "", "Directory of a mounted Secret, or key=value file, with the realm secret used by requests without secrets, e.g. if the storage class sets no secret parameters (disabled if empty)")
+	flag.BoolVar(&cfg.quotaRefresh, "quota-refresh", false, "Request NodeExpandVolume after volume expansions and remount expanded volumes on the nodes, so PanFS clients caching the quota report the new size")
+	flag.StringVar(&cfg.quotaRefreshFixedVersion, "quota-refresh-fixed-version", "", "Oldest PanFS client version not caching the quota, whose volumes are not remounted by --quota-refresh (remount with all versions if empty)")
+	flag.StringVar(&cfg.realmConfig, "realm-config", "", "YAML or JSON file of the realm registry with the connection settings of the realms selected by the realm storage class parameter (disabled if empty)")
+	flag.BoolVar(&cfg.forbidPasswordAuth, "forbid-password-auth", false, "Reject realm secrets authenticating with a password only, once private keys are rolled out")
+	flag.IntVar(&cfg.unmountConcurrency, "unmount-concurrency", driver.DefaultUnmountConcurrency, "Maximum number of concurrent unmounts of the node plugin (negative disables the limit)")
+	flag.BoolVar(&cfg.unmountFromCPU, "unmount-concurrency-from-cpu", false, "Size the concurrent unmounts of the node plugin by the CPU quota of the container instead of --unmount-concurrency")
+	flag.DurationVar(&cfg.errorAggregationWindow, "error-aggregation-window", logging.DefaultAggregationWindow, "Window in which repeated identical errors are logged once with a repeat count (0 disables, the default)")
+	flag.Parse()
+
+	logAggregator = logging.NewAggregator(cfg.errorAggregationWindow)
+	log = logAggregator.Wrap(klog.NewKlogr())
+	log.Info("Klog logger initialized", "verbosity", flag.Lookup("v").Value.String())
+}
+
+// main is the entry point for the CSI node plugin.
+func main() {
+	defer klog.Flush()
+
+	if os.Getenv("CSI_SANITY_MODE") == "true" {
+		cfg.sanity = true
+	}
+
+	var mounter driver.PanMounter
+	if cfg.sanity {
+		klog.Info("CSI sanity mode enabled: using mock mounter")
+		mounter = driver.NewPanFSFakeMounter()
+	} else {
+		klog.Info("Starting node plugin in default operation mode")
+		mounter = driver.NewPanFSMounter()
+	}
+
+	if cfg.metricsAddress != "" {
+		go func() {
+			log.Info("serving metrics", "address", cfg.metricsAddress)
+			if err := metrics.ListenAndServe(cfg.metricsAddress); err != nil {
+				log.Error(err, "metrics server stopped", "address", cfg.metricsAddress)
+			}
+		}()
+	}
+
+	mode, err := driver.ParsePluginMode(cfg.mode)
+	if err != nil {
+		klog.Exit(err)
+	}
+	if mode != driver.PluginModeNode {
+		klog.Exitf("invalid --mode %q: the node plugin only runs as %s", mode, driver.PluginModeNode)
+	}
+
+	targetDirPerms, err := driver.NewTargetDirPermissions(cfg.targetDirMode, cfg.targetDirUID, cfg.targetDirGID)
+	if err != nil {
+		klog.Exit(err)
+	}
+
+	topologyRealms := strings.Split(cfg.topologyRealms, ",")
+	if cfg.topologyRealmsFile != "" {
+		realms, err := driver.LoadTopologyRealms(cfg.topologyRealmsFile)
+		if err != nil {
+			klog.Exit(err)
+		}
+		topologyRealms = append(topologyRealms, realms...)
+	}
+	topologyRealmKeys, err := driver.NewTopologyRealmKeys(topologyRealms)
+	if err != nil {
+		klog.Exit(err)
+	}
+
+	opts := []driver.Option{
+		driver.WithPluginMode(mode),
+		driver.WithSlowRPCThreshold(cfg.slowRPCThreshold),
+		driver.WithProvisioningSLO(cfg.sloThreshold, cfg.sloWindow),
+		driver.WithUnmountConcurrency(cfg.unmountConcurrency),
+		driver.WithUnmountConcurrencyFromCPU(cfg.unmountFromCPU),
+		driver.WithMountVerification(cfg.verifyMounts),
+		driver.WithDataPathCheck(cfg.dataPathCheckPort, cfg.dataPathCheckTTL),
+		driver.WithStagedMounts(cfg.stagedMounts),
+		driver.WithTargetDirPermissions(targetDirPerms),
+		driver.WithTopologyRealms(topologyRealmKeys),
+		driver.WithTopologyNodeLabels(cfg.topologyNodeLabels),
+		driver.WithForbidPasswordAuth(cfg.forbidPasswordAuth),
+		driver.WithQuotaRefresh(cfg.quotaRefresh, cfg.quotaRefreshFixedVersion),
+	}
+	if cfg.defaultSecretPath != "" {
+		if err := driver.ValidateDefaultSecret(cfg.defaultSecretPath); err != nil {
+			klog.Exit(err)
+		}
+		log.Info("using the default secret for requests without secrets", "path", cfg.defaultSecretPath)
+		opts = append(opts, driver.WithDefaultSecret(cfg.defaultSecretPath))
+	}
+	if cfg.realmConfig != "" {
+		registry, err := realm.LoadFile(cfg.realmConfig)
+		if err != nil {
+			klog.Exit(err)
+		}
+		log.Info("loaded the realm registry", "realms", registry.Names())
+		opts = append(opts, driver.WithRealmRegistry(registry))
+	}
+	if cfg.mountProfilesFile != "" {
+		profiles, err := driver.LoadMountProfiles(cfg.mountProfilesFile)
+		if err != nil {
+			klog.Exit(err)
+		}
+		log.Info("loaded mount profiles", "file", cfg.mountProfilesFile, "count", len(profiles))
+		opts = append(opts, driver.WithMountProfiles(profiles))
+	}
+
+	if cfg.enableEphemeral {
+		if cfg.ephemeralParent == "" {
+			klog.Exit("-ephemeral-parent-volume must be set with -enable-ephemeral")
+		}
+		opts = append(opts, driver.WithEphemeralVolumes(cfg.ephemeralParent))
+	}
+
+	if cfg.canaryVolume != "" {
+		opts = append(opts, driver.WithCanaryVolume(cfg.canaryVolume))
+	}
+
+	// the node service never calls the realm, so no storage provider client is configured
+	d := driver.CreateDriver(version, cfg.driverName, cfg.endpoint, nil, log, mounter, opts...)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go logAggregator.Run(ctx)
+	defer logAggregator.Flush()
+
+	// dump the driver state on SIGQUIT instead of terminating the process
+	dump := make(chan os.Signal, 1)
+	signal.Notify(dump, syscall.SIGQUIT)
+	go func() {
+		for range dump {
+			d.DumpDebugSnapshot()
+		}
+	}()
+
+	err = d.Run(ctx)
+	if err != nil {
+		klog.Exit(err)
+		os.Exit(1)
+	}
+}
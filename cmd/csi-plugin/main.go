@@ -12,15 +12,29 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+//go:build !nodeonly
+
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/logging"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
 	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/realm"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 )
 
@@ -28,14 +42,89 @@ var version = "unversioned"
 
 // config holds the configuration for the CSI driver.
 type config struct {
-	endpoint   string
-	driverName string
-	sanity     bool
+	endpoint         string
+	driverName       string
+	mode             string
+	sanity           bool
+	metricsAddress   string
+	debugEndpoints   bool
+	slowRPCThreshold time.Duration
+	sloThreshold     time.Duration
+	sloWindow        time.Duration
+
+	realmProvider          string
+	restPort               string
+	restCAFile             string
+	restInsecureSkipVerify bool
+	restTimeout            time.Duration
+	knownHostsFile         string
+	strictHostKeys         bool
+	sshPool                pancli.SSHPoolConfig
+
+	retryAttempts        int
+	retryBackoff         time.Duration
+	createVerifyAttempts int
+	createVerifyInterval time.Duration
+	idempotencyTokens    bool
+	errorPatternsFile    string
+	mountProfilesFile    string
+
+	pvcAnnotationParameters string
+	namespacePolicyFile     string
+	canaryVolume            string
+	verifyMounts            bool
+	stagedMounts            bool
+	dataPathCheckPort       string
+	dataPathCheckTTL        time.Duration
+	enableEphemeral         bool
+	ephemeralParent         string
+	targetDirMode           string
+	targetDirUID            int
+	targetDirGID            int
+	topology                bool
+	topologyRealms          string
+	topologyRealmsFile      string
+	topologyNodeLabels      bool
+	defaultSecretPath       string
+	forbidPasswordAuth      bool
+	realmConfig             string
+	realmConfigSecret       string
+
+	deleteVerifyAttempts int
+	deleteVerifyInterval time.Duration
+	maxVolumeContextSize int
+	maxVolumeNameLength  int
+	expansionStep        string
+	realmConcurrency     int
+	realmQueueWait       time.Duration
+	unmountConcurrency   int
+	unmountFromCPU       bool
+	encryptionMismatch   string
+	kmipSecretCheck      string
+	staleNodeCleanup     bool
+	attachmentTracking   bool
+	contractCheck        string
+
+	credentialProvider string
+	credentialCacheTTL time.Duration
+	credentialsDir     string
+	vaultAddress       string
+	vaultKVMount       string
+	vaultTokenFile     string
+	defaultCredentials string
+	capabilityRealms   string
+
+	errorAggregationWindow time.Duration
+	volumeStatsInterval    time.Duration
+
+	quotaRefresh             bool
+	quotaRefreshFixedVersion string
 }
 
 var (
-	cfg config
-	log klog.Logger
+	cfg           config
+	log           klog.Logger
+	logAggregator *logging.Aggregator
 )
 
 // init initializes the command-line flags and logging.
@@ -43,22 +132,456 @@ func init() {
 	// init klog flags. See klog docs for details
 	klog.InitFlags(nil)
 
-	flag.StringVar(&cfg.endpoint, "endpoint", "/tmp/csi.sock", "CSI endpoint")
+	flag.StringVar(&cfg.endpoint, "endpoint", "/tmp/csi.sock", "CSI endpoint: unix socket path, unix:// or tcp:// URL")
 	flag.StringVar(&cfg.driverName, "driverName", driver.DefaultDriverName, "Name of CSI driver")
+	flag.StringVar(&cfg.mode, "mode", string(driver.PluginModeAll), "Component the plugin runs as, reported in the plugin info, metrics and logs: controller, node or all")
+	flag.StringVar(&cfg.metricsAddress, "metrics-address", "", "Address to expose Prometheus metrics on, e.g. ':9090' (disabled if empty)")
+	flag.BoolVar(&cfg.debugEndpoints, "debug-endpoints", false, "Serve debug endpoints on the metrics address, e.g. "+driver.ValidateVolumesPath+" validating many volumes with one realm listing")
+	flag.DurationVar(&cfg.slowRPCThreshold, "slow-rpc-threshold", driver.DefaultSlowRPCThreshold, "Duration after which an RPC is logged as slow (0 disables)")
+	flag.DurationVar(&cfg.sloThreshold, "slo-threshold", driver.DefaultSLOThreshold, "Duration within which volume operations must succeed to count towards the provisioning SLO (0 disables the SLO)")
+	flag.DurationVar(&cfg.sloWindow, "slo-window", driver.DefaultSLOWindow, "Rolling window of the provisioning SLO ratio, also the interval of its summary log")
+	flag.StringVar(&cfg.realmProvider, "provider", pancli.RealmProviderSSH, "Backend managing volumes on the realm: ssh (pancli over SSH) or rest (realm REST API over HTTPS)")
+	flag.StringVar(&cfg.restPort, "rest-port", pancli.DefaultRESTPort, "Port of the realm REST API of the rest provider")
+	flag.StringVar(&cfg.restCAFile, "rest-ca-file", "", "PEM file with the certificate authorities of the realm REST API (system certificates if empty)")
+	flag.BoolVar(&cfg.restInsecureSkipVerify, "rest-insecure-skip-verify", false, "Skip the verification of the realm certificate of the rest provider, for test realms only")
+	flag.DurationVar(&cfg.restTimeout, "rest-timeout", pancli.DefaultRESTTimeout, "Timeout of a single request to the realm REST API")
+	flag.StringVar(&cfg.knownHostsFile, "known-hosts-file", "", "known_hosts file with the SSH host keys of the realms, in addition to the host_key of the realm secrets (disabled if empty)")
+	flag.IntVar(&cfg.sshPool.MaxSessions, "ssh-max-sessions", pancli.DefaultSSHPoolConfig.MaxSessions, "Maximum number of concurrent SSH sessions per realm, further commands wait for a free session (0 for no limit)")
+	flag.DurationVar(&cfg.sshPool.IdleTimeout, "ssh-idle-timeout", pancli.DefaultSSHPoolConfig.IdleTimeout, "Close SSH connections to realms unused for this long (0 keeps them open)")
+	flag.DurationVar(&cfg.sshPool.KeepaliveInterval, "ssh-keepalive-interval", pancli.DefaultSSHPoolConfig.KeepaliveInterval, "Interval of keepalive checks and idle expiry of SSH connections to realms (0 disables both)")
+	flag.IntVar(&cfg.sshPool.ReconnectAttempts, "ssh-reconnect-attempts", pancli.DefaultSSHPoolConfig.ReconnectAttempts, "Number of attempts to reconnect an SSH connection found dead by a keepalive check (0 leaves reconnecting to the next command)")
+	flag.DurationVar(&cfg.sshPool.ReconnectBackoff, "ssh-reconnect-backoff", pancli.DefaultSSHPoolConfig.ReconnectBackoff, "Delay before the first reconnect attempt, doubled for each further attempt")
+	flag.BoolVar(&cfg.strictHostKeys, "strict-host-key-checking", false, "Refuse realms whose SSH host key is not configured or does not match, instead of logging a warning")
+	flag.IntVar(&cfg.retryAttempts, "realm-retry-attempts", pancli.DefaultRetryAttempts, "Maximum number of attempts of realm commands failing transiently, e.g. on connection resets (1 disables retries)")
+	flag.DurationVar(&cfg.retryBackoff, "realm-retry-backoff", pancli.DefaultRetryBackoff, "Delay before the first retry of a realm command, doubled with jitter for each further retry")
+	flag.IntVar(&cfg.createVerifyAttempts, "create-verify-attempts", pancli.DefaultCreateVerifyAttempts, "Number of reads of a created volume while the realm reports it as not found")
+	flag.DurationVar(&cfg.createVerifyInterval, "create-verify-interval", pancli.DefaultCreateVerifyInterval, "Delay between reads of a created volume")
+	flag.BoolVar(&cfg.idempotencyTokens, "idempotency-tokens", true, "Tag the description of created volumes with a token, so creations whose connection failed can be verified before retrying (rest provider: send an Idempotency-Key header and resend such requests once)")
+	flag.StringVar(&cfg.errorPatternsFile, "error-patterns", "", "JSON file with additional realm error message patterns, e.g. for localized realms")
+	flag.StringVar(&cfg.mountProfilesFile, "mount-profiles", "", "JSON file with named sets of PanFS mount options selectable by storage classes")
+	flag.StringVar(&cfg.pvcAnnotationParameters, "pvc-annotation-parameters", "", "Comma separated volume parameters which may be set by PVC annotations, e.g. 'user,group' (requires --extra-create-metadata on the provisioner)")
+	flag.StringVar(&cfg.namespacePolicyFile, "namespace-policy", "", "JSON file with rules restricting volume parameters to namespaces (requires --extra-create-metadata on the provisioner)")
+	flag.StringVar(&cfg.canaryVolume, "canary-volume", "", "Volume '<realm>/<volume>' mounted read-only at node plugin start to verify the PanFS client (disabled if empty)")
+	flag.BoolVar(&cfg.verifyMounts, "verify-mounts", false, "Verify IO on published volumes (statfs and read of the mount root) and fail the publish if it fails, overridden by the verifyMount volume parameter")
+	flag.BoolVar(&cfg.stagedMounts, "staged-mounts", false, "Mount volumes once per node in NodeStageVolume and bind mount them into the pods, reading the realm credentials from the node-stage secret")
+	flag.StringVar(&cfg.dataPathCheckPort, "data-path-check-port", "", "TCP port of the realm data address probed from the node before mounting a volume (disabled if empty)")
+	flag.DurationVar(&cfg.dataPathCheckTTL, "data-path-check-ttl", driver.DefaultDataPathCheckTTL, "How long the result of a realm data path probe is reused")
+	flag.BoolVar(&cfg.enableEphemeral, "enable-ephemeral", false, "Publish CSI inline ephemeral volumes as directories of the parent volume, removed on unpublish")
+	flag.StringVar(&cfg.ephemeralParent, "ephemeral-parent-volume", "", "Realm volume holding the directories of inline ephemeral volumes, required with -enable-ephemeral")
+	flag.StringVar(&cfg.targetDirMode, "target-dir-mode", "0755", "Octal mode of publish target directories created by the node plugin, overridden by the targetDirMode volume parameter")
+	flag.IntVar(&cfg.targetDirUID, "target-dir-uid", -1, "Owner of publish target directories created by the node plugin (-1 keeps the owner of the plugin), overridden by the targetDirUID volume parameter")
+	flag.IntVar(&cfg.targetDirGID, "target-dir-gid", -1, "Group of publish target directories created by the node plugin (-1 keeps the group of the plugin), overridden by the targetDirGID volume parameter")
+	flag.BoolVar(&cfg.topology, "topology", false, "Advertise volume accessibility constraints and constrain created volumes to the nodes reporting their realm as reachable (requires the Topology feature of the provisioner)")
+	flag.StringVar(&cfg.topologyRealms, "topology-realms", "", "Comma-separated addresses of the realms reachable by the node, reported as topology segments")
+	flag.StringVar(&cfg.topologyRealmsFile, "topology-realms-file", "", "File with the addresses of the realms reachable by the node, one per line, in addition to --topology-realms")
+	flag.BoolVar(&cfg.topologyNodeLabels, "topology-node-labels", false, "Report the realm topology labels of the node, e.g. set per node pool, as reachable realms")
+	flag.StringVar(&cfg.defaultSecretPath, "default-secret-path", "", "Directory of a mounted Secret, or key=value file, with the realm secret used by requests without secrets, e.g. if the storage class sets no secret parameters (disabled if empty)")
+	flag.BoolVar(&cfg.quotaRefresh, "quota-refresh", false, "Request NodeExpandVolume after volume expansions and remount expanded volumes on the nodes, so PanFS clients caching the quota report the new size")
+	flag.StringVar(&cfg.quotaRefreshFixedVersion, "quota-refresh-fixed-version", "", "Oldest PanFS client version not caching the quota, whose volumes are not remounted by --quota-refresh (remount with all versions if empty)")
+	flag.StringVar(&cfg.realmConfig, "realm-config", "", "YAML or JSON file of the realm registry with the connection settings of the realms selected by the realm storage class parameter (disabled if empty)")
+	flag.StringVar(&cfg.realmConfigSecret, "realm-config-secret", "", "Secret in the namespace of the driver whose "+realm.SecretKey+" key holds the realm registry, instead of --realm-config (requires POD_NAMESPACE)")
+	flag.BoolVar(&cfg.forbidPasswordAuth, "forbid-password-auth", false, "Reject realm secrets authenticating with a password only and never offer passwords over SSH, once private keys (or API tokens of the REST provider) are rolled out")
+	flag.IntVar(&cfg.deleteVerifyAttempts, "delete-verify-attempts", 0, "Number of reads of a deleted volume before DeleteVolume fails while the volume still exists (0 disables)")
+	flag.DurationVar(&cfg.deleteVerifyInterval, "delete-verify-interval", driver.DefaultDeleteVerifyInterval, "Delay between reads of a deleted volume")
+	flag.StringVar(&cfg.expansionStep, "expansion-step", "", "Maximum quota increase per realm command of volume expansions, e.g. 10Ti; larger expansions are applied in steps (disabled if empty)")
+	flag.IntVar(&cfg.realmConcurrency, "realm-concurrency-limit", 0, "Maximum number of concurrent controller requests per realm, polled requests such as GetCapacity yield to provisioning (0 disables the limit)")
+	flag.DurationVar(&cfg.realmQueueWait, "realm-queue-wait", driver.DefaultRealmQueueWait, "Maximum time a controller request waits for a free realm slot before failing with Unavailable")
+	flag.IntVar(&cfg.unmountConcurrency, "unmount-concurrency", driver.DefaultUnmountConcurrency, "Maximum number of concurrent unmounts of the node plugin (negative disables the limit)")
+	flag.BoolVar(&cfg.unmountFromCPU, "unmount-concurrency-from-cpu", false, "Size the concurrent unmounts of the node plugin by the CPU quota of the container instead of --unmount-concurrency")
+	flag.StringVar(&cfg.kmipSecretCheck, "kmip-secret-check", driver.KMIPSecretCheckWarn, "Handling of encrypted volumes whose storage class has no node-publish KMIP secret: off, warn or fail (requires --extra-create-metadata on the provisioner)")
+	flag.StringVar(&cfg.contractCheck, "contract-check", driver.ContractCheckWarn, "Startup check of the CSI sidecar flags and versions of the controller pod: off, warn or fail (requires POD_NAME and POD_NAMESPACE)")
+	flag.BoolVar(&cfg.staleNodeCleanup, "stale-node-cleanup", false, "Remove driver-owned records of nodes deleted from the cluster (requires POD_NAMESPACE)")
+	flag.BoolVar(&cfg.attachmentTracking, "attachment-tracking", false, "Serve ControllerPublishVolume, recording the nodes volumes are published to and publishing single node volumes to one node at a time (requires POD_NAMESPACE and attachRequired in the CSIDriver object)")
+	flag.StringVar(&cfg.credentialProvider, "credential-provider", "", "Provider resolving realm credentials referenced by handles: kubernetes-secret, vault or file (disabled if empty)")
+	flag.DurationVar(&cfg.credentialCacheTTL, "credential-cache-ttl", driver.DefaultCredentialCacheTTL, "Time resolved realm credentials are cached (0 disables caching)")
+	flag.StringVar(&cfg.credentialsDir, "credentials-dir", "", "Base directory of the credential directories of the file credential provider")
+	flag.StringVar(&cfg.vaultAddress, "vault-address", os.Getenv("VAULT_ADDR"), "Address of the Vault server of the vault credential provider")
+	flag.StringVar(&cfg.vaultKVMount, "vault-kv-mount", "secret", "Mount path of the KV version 2 secrets engine of the vault credential provider")
+	flag.StringVar(&cfg.vaultTokenFile, "vault-token-file", "", "File holding the Vault token of the vault credential provider, re-read on every lookup")
+	flag.StringVar(&cfg.defaultCredentials, "default-credentials", "", "Credential handle of the realm used by requests without secrets, e.g. ListVolumes (requires --credential-provider)")
+	flag.StringVar(&cfg.capabilityRealms, "capability-realms", "", "Comma-separated credential handles of the realms whose common features determine the advertised controller capabilities, e.g. snapshots (requires --credential-provider)")
+	flag.StringVar(&cfg.encryptionMismatch, "encryption-mismatch-policy", driver.EncryptionMismatchDelete, "Handling of volumes created with a different encryption mode than requested: delete or fail")
+	flag.IntVar(&cfg.maxVolumeContextSize, "max-volume-context-size", driver.DefaultMaxVolumeContextSize, "Maximum size in bytes of the volume context returned by CreateVolume (0 disables the limit)")
+	flag.IntVar(&cfg.maxVolumeNameLength, "max-volume-name-length", driver.DefaultMaxVolumeNameLength, "Maximum length of the names of realm volumes; longer volume names are shortened and suffixed with a hash of the name")
+	flag.DurationVar(&cfg.errorAggregationWindow, "error-aggregation-window", logging.DefaultAggregationWindow, "Window in which repeated identical errors are logged once with a repeat count (0 disables, the default)")
+	flag.DurationVar(&cfg.volumeStatsInterval, "volume-stats-interval", 0, "Interval of reads of the volume performance counters of the realms, exported as per-volume throughput and latency metrics (0 disables)")
 	flag.Parse()
 
-	log = klog.NewKlogr()
+	logAggregator = logging.NewAggregator(cfg.errorAggregationWindow)
+	log = logAggregator.Wrap(klog.NewKlogr())
+	pancli.SetLogger(log)
 	log.Info("Klog logger initialized", "verbosity", flag.Lookup("v").Value.String())
 }
 
+// checkSidecarContracts verifies that the CSI sidecars of the controller pod run with the
+// flags and versions the driver relies on, as configured by --contract-check.
+//
+// Returns:
+//
+//	error - Error if the check fails in fail mode.
+func checkSidecarContracts() error {
+	if cfg.contractCheck == driver.ContractCheckOff {
+		return nil
+	}
+
+	// PVC metadata is read for PVC annotation parameters, namespace policies and the KMIP secret check
+	extraCreateMetadata := cfg.pvcAnnotationParameters != "" || cfg.namespacePolicyFile != "" || cfg.kmipSecretCheck != driver.KMIPSecretCheckOff
+
+	var kubeClient kubernetes.Interface
+	if clientset, err := driver.NewInClusterKubeClient(); err != nil {
+		log.Error(err, "failed to create kube client for the sidecar contract check")
+	} else {
+		kubeClient = clientset
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return driver.CheckSidecarContracts(ctx, kubeClient, os.Getenv("POD_NAMESPACE"), os.Getenv("POD_NAME"), cfg.contractCheck,
+		driver.ControllerSidecarContracts(extraCreateMetadata), log)
+}
+
+// runCleanup implements the "cleanup" subcommand which removes the cluster state left
+// behind by the driver. It is meant to be run as a Helm pre-delete hook Job.
+//
+// Parameters:
+//
+//	args - The subcommand arguments.
+//
+// Returns:
+//
+//	error - Error if the arguments are invalid or any cleanup step fails.
+func runCleanup(args []string) error {
+	fs := flag.NewFlagSet("cleanup", flag.ContinueOnError)
+	opts := driver.CleanupOptions{}
+	fs.BoolVar(&opts.NodeLabels, "node-labels", false, "Remove the driver readiness label from all nodes")
+	fs.BoolVar(&opts.Attachments, "attachments", false, "Remove driver-owned attachment records")
+	fs.StringVar(&opts.Namespace, "namespace", os.Getenv("POD_NAMESPACE"), "Namespace the driver is installed in")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	kubeClient, err := driver.NewInClusterKubeClient()
+	if err != nil {
+		return err
+	}
+
+	return driver.Cleanup(context.Background(), kubeClient, cfg.driverName, opts, log)
+}
+
+// runVerifySecret implements the "verify-secret" subcommand which checks a realm secret
+// manifest, e.g. in a secret rotation pipeline, before it is rolled out. The result is
+// written to stdout as JSON.
+//
+// Parameters:
+//
+//	args - The subcommand arguments.
+//
+// Returns:
+//
+//	bool  - True if the secret passed all checks.
+//	error - Error if the arguments are invalid or the manifest cannot be read.
+func runVerifySecret(args []string) (bool, error) {
+	fs := flag.NewFlagSet("verify-secret", flag.ContinueOnError)
+	file := fs.String("f", "", "Path of the Secret manifest to verify, - for stdin")
+	if err := fs.Parse(args); err != nil {
+		return false, err
+	}
+	secrets, err := readSecretManifest(*file)
+	if err != nil {
+		return false, err
+	}
+
+	var verifier driver.CredentialVerifier = newSSHClient()
+	if cfg.realmProvider == pancli.RealmProviderREST {
+		if verifier, err = newRESTClient(); err != nil {
+			return false, err
+		}
+	}
+
+	result := driver.VerifySecret(secrets, verifier)
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(result); err != nil {
+		return false, err
+	}
+	return result.Valid, nil
+}
+
+// runDryRun implements the "dry-run" subcommand which renders the pancli commands of a
+// volume deletion or expansion and predicts its outcome without changing the realm. The
+// result is written to stdout as JSON.
+//
+// Parameters:
+//
+//	args - The subcommand arguments.
+//
+// Returns:
+//
+//	error - Error if the arguments are invalid or the manifest cannot be read.
+func runDryRun(args []string) error {
+	fs := flag.NewFlagSet("dry-run", flag.ContinueOnError)
+	file := fs.String("f", "", "Path of the Secret manifest of the realm, - for stdin")
+	req := driver.DryRunRequest{}
+	fs.StringVar(&req.Operation, "operation", driver.DryRunDelete, "Predicted operation: delete or expand")
+	fs.StringVar(&req.VolumeID, "volume", "", "Volume handle of the PV")
+	size := fs.String("size", "", "Requested capacity of an expansion, e.g. 20Gi")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if req.VolumeID == "" {
+		return fmt.Errorf("a volume must be provided with -volume")
+	}
+	if *size != "" {
+		quantity, err := resource.ParseQuantity(*size)
+		if err != nil {
+			return fmt.Errorf("invalid -size %q: %w", *size, err)
+		}
+		req.RequiredBytes = quantity.Value()
+	}
+	if cfg.realmProvider != pancli.RealmProviderSSH {
+		return fmt.Errorf("dry-run renders pancli commands and requires the %s provider", pancli.RealmProviderSSH)
+	}
+
+	secrets, err := readSecretManifest(*file)
+	if err != nil {
+		return err
+	}
+	req.Secrets = secrets
+
+	runner := pancli.NewDryRunRunner(newSSHClient())
+	panfs := pancli.NewPancliSSHClient(runner, pancli.WithIdempotencyTokens(cfg.idempotencyTokens))
+	result, err := driver.DryRun(context.Background(), panfs, runner, req, log)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+// readSecretManifest reads the realm secrets from a Secret manifest.
+//
+// Parameters:
+//
+//	file - The path of the manifest, - for stdin.
+//
+// Returns:
+//
+//	map[string]string - The secret keys and values.
+//	error             - Error if no file is given or the manifest cannot be read.
+func readSecretManifest(file string) (map[string]string, error) {
+	if file == "" {
+		return nil, fmt.Errorf("a secret manifest must be provided with -f")
+	}
+
+	in := os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = f.Close() }()
+		in = f
+	}
+	return driver.ParseSecretManifest(in)
+}
+
+// printMountOptions implements the "mount-options" subcommand which prints the mount options
+// recognized by the driver as JSON.
+//
+// Returns:
+//
+//	error - Error if the options cannot be written.
+func printMountOptions() error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(driver.MountOptions)
+}
+
+// loadErrorPatterns adds the realm error message patterns from the given file.
+//
+// Parameters:
+//
+//	path - The path of the JSON pattern file.
+//
+// Returns:
+//
+//	error - Error if the file cannot be read or parsed.
+func loadErrorPatterns(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	if err := pancli.LoadErrorPatterns(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	log.Info("loaded realm error patterns", "file", path)
+	return nil
+}
+
+// newSSHClient creates the SSH client of the realm pancli configured by the flags.
+//
+// Returns:
+//
+//	*pancli.SSHClient - The SSH client.
+func newSSHClient() *pancli.SSHClient {
+	return pancli.NewSSHClient(
+		pancli.WithKnownHostsFile(cfg.knownHostsFile),
+		pancli.WithStrictHostKeyChecking(cfg.strictHostKeys),
+		pancli.WithSSHPool(cfg.sshPool),
+		pancli.WithForbidPasswordAuth(cfg.forbidPasswordAuth),
+	)
+}
+
+// newRESTClient creates the client of the realm REST API configured by the flags.
+//
+// Returns:
+//
+//	*pancli.PancliRESTClient - The REST client.
+//	error                    - Error if the CA file cannot be loaded.
+func newRESTClient() (*pancli.PancliRESTClient, error) {
+	tlsConfig, err := pancli.NewRESTTLSConfig(cfg.restCAFile, cfg.restInsecureSkipVerify)
+	if err != nil {
+		return nil, err
+	}
+	return pancli.NewPancliRESTClient(
+		pancli.WithRESTPort(cfg.restPort),
+		pancli.WithRESTTimeout(cfg.restTimeout),
+		pancli.WithRESTTLSConfig(tlsConfig),
+		pancli.WithRESTIdempotencyKeys(cfg.idempotencyTokens),
+		pancli.WithRESTForbidPasswordAuth(cfg.forbidPasswordAuth),
+	), nil
+}
+
+// newCredentialProvider creates the credential provider selected by the flags.
+//
+// Returns:
+//
+//	driver.CredentialProvider - The credential provider, nil if disabled.
+//	error                     - Error if the provider is unknown or misconfigured.
+func newCredentialProvider() (driver.CredentialProvider, error) {
+	if err := driver.ValidateCredentialProvider(cfg.credentialProvider); err != nil {
+		return nil, err
+	}
+
+	switch cfg.credentialProvider {
+	case driver.CredentialProviderKubernetesSecret:
+		kubeClient, err := driver.NewInClusterKubeClient()
+		if err != nil {
+			return nil, err
+		}
+		return driver.NewKubernetesSecretCredentialProvider(kubeClient), nil
+	case driver.CredentialProviderVault:
+		if cfg.vaultAddress == "" || cfg.vaultTokenFile == "" {
+			return nil, fmt.Errorf("the vault credential provider requires --vault-address and --vault-token-file")
+		}
+		return driver.NewVaultCredentialProvider(cfg.vaultAddress, cfg.vaultKVMount, cfg.vaultTokenFile), nil
+	case driver.CredentialProviderFile:
+		if cfg.credentialsDir == "" {
+			return nil, fmt.Errorf("the file credential provider requires --credentials-dir")
+		}
+		return driver.NewFileCredentialProvider(cfg.credentialsDir), nil
+	default:
+		return nil, nil
+	}
+}
+
+// newRealmRegistry loads the realm registry selected by the flags.
+//
+// Returns:
+//
+//	*realm.Registry - The realm registry, nil if disabled.
+//	error           - Error if the registry cannot be loaded.
+func newRealmRegistry() (*realm.Registry, error) {
+	switch {
+	case cfg.realmConfig != "" && cfg.realmConfigSecret != "":
+		return nil, fmt.Errorf("--realm-config and --realm-config-secret are mutually exclusive")
+	case cfg.realmConfig != "":
+		return realm.LoadFile(cfg.realmConfig)
+	case cfg.realmConfigSecret != "":
+		namespace := os.Getenv("POD_NAMESPACE")
+		if namespace == "" {
+			return nil, fmt.Errorf("POD_NAMESPACE must be set to read the realm registry secret")
+		}
+		kubeClient, err := driver.NewInClusterKubeClient()
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return realm.LoadSecret(ctx, kubeClient, namespace, cfg.realmConfigSecret)
+	default:
+		return nil, nil
+	}
+}
+
 // main is the entry point for the CSI driver application.
 func main() {
 	defer klog.Flush()
 
+	if flag.Arg(0) == "cleanup" {
+		if err := runCleanup(flag.Args()[1:]); err != nil {
+			log.Error(err, "cleanup failed")
+			klog.Flush()
+			os.Exit(1)
+		}
+		log.Info("cleanup completed")
+		return
+	}
+
+	if flag.Arg(0) == "mount-options" {
+		if err := printMountOptions(); err != nil {
+			klog.Exit(err)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "verify-secret" {
+		valid, err := runVerifySecret(flag.Args()[1:])
+		if err != nil {
+			log.Error(err, "secret verification failed")
+			klog.Flush()
+			os.Exit(2)
+		}
+		if !valid {
+			klog.Flush()
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "dry-run" {
+		if err := runDryRun(flag.Args()[1:]); err != nil {
+			log.Error(err, "dry run failed")
+			klog.Flush()
+			os.Exit(1)
+		}
+		return
+	}
+
 	if os.Getenv("CSI_SANITY_MODE") == "true" {
 		cfg.sanity = true
 	}
 
+	if err := pancli.ValidateSSHPoolConfig(cfg.sshPool); err != nil {
+		klog.Exit(err)
+	}
+	if cfg.retryAttempts < 1 {
+		klog.Exitf("invalid --realm-retry-attempts %d: must be at least 1", cfg.retryAttempts)
+	}
+	if cfg.retryBackoff < 0 {
+		klog.Exitf("invalid --realm-retry-backoff %s: must not be negative", cfg.retryBackoff)
+	}
+
+	if cfg.errorPatternsFile != "" {
+		if err := loadErrorPatterns(cfg.errorPatternsFile); err != nil {
+			klog.Exit(err)
+		}
+	}
+
 	var panfs driver.StorageProviderClient
 	var mounter driver.PanMounter
 	if cfg.sanity {
@@ -67,13 +590,225 @@ func main() {
 		mounter = driver.NewPanFSFakeMounter()
 	} else {
 		klog.Info("Starting driver in default operation mode")
-		panfs = pancli.NewPancliSSHClient(pancli.NewSSHClient())
+		switch cfg.realmProvider {
+		case pancli.RealmProviderSSH:
+			runner := pancli.NewRetryRunner(newSSHClient(), cfg.retryAttempts, cfg.retryBackoff)
+			panfs = pancli.NewPancliSSHClient(runner,
+				pancli.WithCreateVerifyRetry(cfg.createVerifyAttempts, cfg.createVerifyInterval),
+				pancli.WithIdempotencyTokens(cfg.idempotencyTokens),
+			)
+		case pancli.RealmProviderREST:
+			rest, err := newRESTClient()
+			if err != nil {
+				klog.Exit(err)
+			}
+			panfs = rest
+		default:
+			klog.Exitf("invalid --provider %q: must be %s or %s", cfg.realmProvider, pancli.RealmProviderSSH, pancli.RealmProviderREST)
+		}
+		log.Info("managing volumes on the realm", "provider", cfg.realmProvider)
 		mounter = driver.NewPanFSMounter()
 	}
 
-	d := driver.CreateDriver(version, cfg.driverName, cfg.endpoint, panfs, log, mounter)
+	if err := driver.ValidateEncryptionMismatchPolicy(cfg.encryptionMismatch); err != nil {
+		klog.Exit(err)
+	}
+	if err := driver.ValidateKMIPSecretCheck(cfg.kmipSecretCheck); err != nil {
+		klog.Exit(err)
+	}
+
+	if err := driver.ValidateContractCheck(cfg.contractCheck); err != nil {
+		klog.Exit(err)
+	}
+	if !cfg.sanity {
+		if err := checkSidecarContracts(); err != nil {
+			klog.Exit(err)
+		}
+	}
+
+	mode, err := driver.ParsePluginMode(cfg.mode)
+	if err != nil {
+		klog.Exit(err)
+	}
+
+	targetDirPerms, err := driver.NewTargetDirPermissions(cfg.targetDirMode, cfg.targetDirUID, cfg.targetDirGID)
+	if err != nil {
+		klog.Exit(err)
+	}
+
+	topologyRealms := strings.Split(cfg.topologyRealms, ",")
+	if cfg.topologyRealmsFile != "" {
+		realms, err := driver.LoadTopologyRealms(cfg.topologyRealmsFile)
+		if err != nil {
+			klog.Exit(err)
+		}
+		topologyRealms = append(topologyRealms, realms...)
+	}
+	topologyRealmKeys, err := driver.NewTopologyRealmKeys(topologyRealms)
+	if err != nil {
+		klog.Exit(err)
+	}
+
+	var expansionStep int64
+	if cfg.expansionStep != "" {
+		step, err := resource.ParseQuantity(cfg.expansionStep)
+		if err != nil {
+			klog.Exitf("invalid --expansion-step %q: %v", cfg.expansionStep, err)
+		}
+		expansionStep = step.Value()
+	}
+
+	volumeNames, err := driver.NewVolumeNameMapper(cfg.maxVolumeNameLength)
+	if err != nil {
+		klog.Exitf("invalid --max-volume-name-length: %v", err)
+	}
+
+	opts := []driver.Option{
+		driver.WithPluginMode(mode),
+		driver.WithSlowRPCThreshold(cfg.slowRPCThreshold),
+		driver.WithProvisioningSLO(cfg.sloThreshold, cfg.sloWindow),
+		driver.WithDeleteVerification(cfg.deleteVerifyAttempts, cfg.deleteVerifyInterval),
+		driver.WithMaxVolumeContextSize(cfg.maxVolumeContextSize),
+		driver.WithVolumeNameMapper(volumeNames),
+		driver.WithVolumeStats(cfg.volumeStatsInterval),
+		driver.WithExpansionStep(expansionStep),
+		driver.WithRealmConcurrencyLimit(cfg.realmConcurrency, cfg.realmQueueWait),
+		driver.WithUnmountConcurrency(cfg.unmountConcurrency),
+		driver.WithUnmountConcurrencyFromCPU(cfg.unmountFromCPU),
+		driver.WithMountVerification(cfg.verifyMounts),
+		driver.WithStagedMounts(cfg.stagedMounts),
+		driver.WithDataPathCheck(cfg.dataPathCheckPort, cfg.dataPathCheckTTL),
+		driver.WithTargetDirPermissions(targetDirPerms),
+		driver.WithTopology(cfg.topology),
+		driver.WithTopologyRealms(topologyRealmKeys),
+		driver.WithTopologyNodeLabels(cfg.topologyNodeLabels),
+		driver.WithEncryptionMismatchPolicy(cfg.encryptionMismatch),
+		driver.WithKMIPSecretCheck(cfg.kmipSecretCheck),
+		driver.WithForbidPasswordAuth(cfg.forbidPasswordAuth),
+		driver.WithNodeQuotaRefresh(cfg.quotaRefresh),
+		driver.WithQuotaRefresh(cfg.quotaRefresh, cfg.quotaRefreshFixedVersion),
+	}
+	if cfg.defaultSecretPath != "" {
+		if err := driver.ValidateDefaultSecret(cfg.defaultSecretPath); err != nil {
+			klog.Exit(err)
+		}
+		log.Info("using the default secret for requests without secrets", "path", cfg.defaultSecretPath)
+		opts = append(opts, driver.WithDefaultSecret(cfg.defaultSecretPath))
+	}
+	registry, err := newRealmRegistry()
+	if err != nil {
+		klog.Exit(err)
+	}
+	if registry != nil {
+		log.Info("loaded the realm registry", "realms", registry.Names())
+		opts = append(opts, driver.WithRealmRegistry(registry))
+	}
+	if cfg.mountProfilesFile != "" {
+		profiles, err := driver.LoadMountProfiles(cfg.mountProfilesFile)
+		if err != nil {
+			klog.Exit(err)
+		}
+		log.Info("loaded mount profiles", "file", cfg.mountProfilesFile, "count", len(profiles))
+		opts = append(opts, driver.WithMountProfiles(profiles))
+	}
+
+	if cfg.namespacePolicyFile != "" {
+		policy, err := driver.LoadNamespacePolicy(cfg.namespacePolicyFile)
+		if err != nil {
+			klog.Exit(err)
+		}
+		log.Info("loaded namespace policy", "file", cfg.namespacePolicyFile, "rules", len(policy))
+		opts = append(opts, driver.WithNamespacePolicy(policy))
+	}
+
+	if cfg.pvcAnnotationParameters != "" {
+		keys, err := driver.ParsePVCAnnotationParameters(cfg.pvcAnnotationParameters)
+		if err != nil {
+			klog.Exit(err)
+		}
+		opts = append(opts, driver.WithPVCAnnotationParameters(keys))
+	}
+
+	if cfg.enableEphemeral {
+		if cfg.ephemeralParent == "" {
+			klog.Exit("-ephemeral-parent-volume must be set with -enable-ephemeral")
+		}
+		opts = append(opts, driver.WithEphemeralVolumes(cfg.ephemeralParent))
+	}
+
+	if cfg.canaryVolume != "" {
+		opts = append(opts, driver.WithCanaryVolume(cfg.canaryVolume))
+	}
+
+	if cfg.staleNodeCleanup {
+		namespace := os.Getenv("POD_NAMESPACE")
+		if namespace == "" {
+			klog.Exit("POD_NAMESPACE must be set to clean up records of deleted nodes")
+		}
+		opts = append(opts, driver.WithStaleNodeCleanup(namespace))
+	}
+
+	if cfg.attachmentTracking {
+		namespace := os.Getenv("POD_NAMESPACE")
+		if namespace == "" {
+			klog.Exit("POD_NAMESPACE must be set to record the attachments of volumes")
+		}
+		opts = append(opts, driver.WithAttachmentTracking(namespace))
+	}
+
+	credentials, err := newCredentialProvider()
+	if err != nil {
+		klog.Exit(err)
+	}
+	if credentials != nil {
+		log.Info("resolving realm credentials by handle", "provider", cfg.credentialProvider, "cache_ttl", cfg.credentialCacheTTL)
+		opts = append(opts, driver.WithCredentialProvider(credentials, cfg.credentialCacheTTL))
+	}
+	if cfg.defaultCredentials != "" {
+		if credentials == nil {
+			klog.Exit("--default-credentials requires --credential-provider")
+		}
+		opts = append(opts, driver.WithDefaultCredentials(cfg.defaultCredentials))
+	}
+	if cfg.capabilityRealms != "" {
+		if credentials == nil {
+			klog.Exit("--capability-realms requires --credential-provider")
+		}
+		opts = append(opts, driver.WithCapabilityRealms(strings.Split(cfg.capabilityRealms, ",")))
+	}
+
+	d := driver.CreateDriver(version, cfg.driverName, cfg.endpoint, panfs, log, mounter, opts...)
+	d.NegotiateCapabilities(context.Background())
+
+	if cfg.metricsAddress != "" {
+		var routes []metrics.Route
+		if cfg.debugEndpoints {
+			routes = append(routes, metrics.Route{Pattern: driver.ValidateVolumesPath, Handler: d.ValidateVolumesHandler()})
+		}
+		go func() {
+			log.Info("serving metrics", "address", cfg.metricsAddress, "debug_endpoints", cfg.debugEndpoints)
+			if err := metrics.ListenAndServe(cfg.metricsAddress, routes...); err != nil {
+				log.Error(err, "metrics server stopped", "address", cfg.metricsAddress)
+			}
+		}()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go logAggregator.Run(ctx)
+	defer logAggregator.Flush()
+
+	// dump the driver state on SIGQUIT instead of terminating the process
+	dump := make(chan os.Signal, 1)
+	signal.Notify(dump, syscall.SIGQUIT)
+	go func() {
+		for range dump {
+			d.DumpDebugSnapshot()
+		}
+	}()
 
-	err := d.Run()
+	err = d.Run(ctx)
 	if err != nil {
 		klog.Exit(err)
 		os.Exit(1)
@@ -0,0 +1,313 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+// Package client provides a stable Go API for managing PanFS volumes without the CSI layer.
+// It uses the same code paths as the CSI driver to create, list, expand and delete volumes
+// on a PanFS realm over SSH.
+//
+// Example:
+//
+//	c, err := client.New(client.Config{
+//		RealmAddress: "panfs-realm.example.com",
+//		Username:     "admin",
+//		Password:     "secret",
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	vol, err := c.CreateVolume(ctx, "data", client.CreateVolumeOptions{SoftQuotaBytes: 10 << 30})
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// Errors returned by Client methods. Use errors.Is to check for them.
+var (
+	// ErrAlreadyExists is returned when a volume with the requested name already exists.
+	ErrAlreadyExists = pancli.ErrorAlreadyExist
+	// ErrNotFound is returned when the requested volume does not exist.
+	ErrNotFound = pancli.ErrorNotFound
+	// ErrInvalidArgument is returned when the realm rejects a parameter.
+	ErrInvalidArgument = pancli.ErrorInvalidArgument
+	// ErrUnauthenticated is returned when the realm credentials are invalid.
+	ErrUnauthenticated = pancli.ErrorUnauthenticated
+	// ErrUnavailable is returned when the realm cannot be reached.
+	ErrUnavailable = pancli.ErrorUnavailable
+	// ErrInternal is returned for unclassified realm errors.
+	ErrInternal = pancli.ErrorInternal
+)
+
+// Config holds the realm connection settings.
+type Config struct {
+	// RealmAddress is the IP address or DNS name of the PanFS realm, or a comma-separated
+	// list of director addresses tried in order if one of them is unreachable.
+	RealmAddress string
+	// Username is the realm user used for SSH connections.
+	Username string
+	// Password of the realm user. Either Password or PrivateKey is required.
+	Password string
+	// PrivateKey is the PEM encoded SSH private key of the realm user.
+	PrivateKey string
+	// PrivateKeyPassphrase decrypts PrivateKey if it is encrypted.
+	PrivateKeyPassphrase string
+	// SerializeOperations serializes mutating commands for realms which cannot handle them concurrently.
+	SerializeOperations bool
+	// CompressOutput compresses the output of realm commands, for realms with many volumes.
+	// The realm shell must provide gzip and support the pipefail option.
+	CompressOutput bool
+	// QuotaUnit is the unit the realm expects quotas in, "GiB" (default) or "GB".
+	QuotaUnit string
+}
+
+// Option configures optional Client behavior in New.
+type Option func(*Client)
+
+// WithSSHRunner sets the runner used to execute pancli commands, e.g. to share
+// SSH connections between clients or to stub the realm in tests.
+//
+// Parameters:
+//
+//	runner - The SSHRunner implementation.
+//
+// Returns:
+//
+//	Option - The client option.
+func WithSSHRunner(runner pancli.SSHRunner) Option {
+	return func(c *Client) {
+		c.runner = runner
+	}
+}
+
+// Client manages volumes on a single PanFS realm. It is safe for concurrent use.
+type Client struct {
+	runner  pancli.SSHRunner
+	panfs   *pancli.PancliSSHClient
+	secrets map[string]string
+}
+
+// New creates a Client for the realm described by cfg.
+//
+// Parameters:
+//
+//	cfg  - The realm connection settings.
+//	opts - Optional client settings.
+//
+// Returns:
+//
+//	*Client - The initialized client.
+//	error   - Error if the configuration is incomplete or the private key cannot be parsed.
+func New(cfg Config, opts ...Option) (*Client, error) {
+	if cfg.RealmAddress == "" {
+		return nil, errors.New("realm address must be provided")
+	}
+	if cfg.Username == "" {
+		return nil, errors.New("username must be provided")
+	}
+	if cfg.Password == "" && cfg.PrivateKey == "" {
+		return nil, errors.New("either password or private key must be provided")
+	}
+	if cfg.PrivateKey != "" {
+		if _, err := pancli.ParsePrivateKey(cfg.PrivateKey, cfg.PrivateKeyPassphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	c := &Client{
+		secrets: map[string]string{
+			utils.RealmConnectionContext.RealmAddress:         cfg.RealmAddress,
+			utils.RealmConnectionContext.Username:             cfg.Username,
+			utils.RealmConnectionContext.Password:             cfg.Password,
+			utils.RealmConnectionContext.PrivateKey:           cfg.PrivateKey,
+			utils.RealmConnectionContext.PrivateKeyPassphrase: cfg.PrivateKeyPassphrase,
+		},
+	}
+	if cfg.SerializeOperations {
+		c.secrets[utils.RealmConnectionContext.SerializeOperations] = "true"
+	}
+	if cfg.CompressOutput {
+		c.secrets[utils.RealmConnectionContext.CompressOutput] = "true"
+	}
+	if cfg.QuotaUnit != "" {
+		if _, err := utils.ParseQuotaUnit(cfg.QuotaUnit); err != nil {
+			return nil, err
+		}
+		c.secrets[utils.RealmConnectionContext.QuotaUnit] = cfg.QuotaUnit
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.runner == nil {
+		c.runner = pancli.NewSSHClient()
+	}
+	c.panfs = pancli.NewPancliSSHClient(c.runner)
+
+	return c, nil
+}
+
+// Volume describes a PanFS volume.
+type Volume struct {
+	Name           string
+	ID             string
+	State          utils.VolumeState
+	Bladeset       string
+	SoftQuotaBytes int64
+	HardQuotaBytes int64
+	Encryption     string
+}
+
+// newVolume converts a parsed pasxml volume to a Volume.
+func newVolume(v *utils.Volume) *Volume {
+	return &Volume{
+		Name:           string(v.Name),
+		ID:             v.ID,
+		State:          v.State,
+		Bladeset:       v.Bset.Name,
+		SoftQuotaBytes: v.GetSoftQuotaBytes(),
+		HardQuotaBytes: v.GetHardQuotaBytes(),
+		Encryption:     v.GetEncryptionMode(),
+	}
+}
+
+// CreateVolumeOptions holds the settings of a new volume. Zero values use the realm defaults.
+type CreateVolumeOptions struct {
+	// SoftQuotaBytes is the soft quota of the volume, 0 means unlimited.
+	SoftQuotaBytes int64
+	// HardQuotaBytes is the hard quota of the volume, 0 means unlimited.
+	HardQuotaBytes int64
+	// Bladeset is the name of the bladeset to create the volume in.
+	Bladeset string
+	// Layout is the RAID layout of the volume.
+	Layout string
+	// Description is the description of the volume.
+	Description string
+	// Encryption requests an encrypted volume.
+	Encryption bool
+	// Parameters holds additional storage class parameters, keyed as in a StorageClass
+	// (e.g. "panfs.csi.vdura.com/stripeunit"). Typed fields take precedence.
+	Parameters map[string]string
+}
+
+// CreateVolume creates a volume and returns its details.
+//
+// Parameters:
+//
+//	ctx  - The context for the operation.
+//	name - The name of the volume.
+//	opts - The volume settings.
+//
+// Returns:
+//
+//	*Volume - The created volume.
+//	error   - ErrAlreadyExists if the volume exists, or another error if creation fails.
+func (c *Client) CreateVolume(ctx context.Context, name string, opts CreateVolumeOptions) (*Volume, error) {
+	params, err := pancli.NewVolumeCreateParamsBuilder().
+		SetParameters(opts.Parameters).
+		SetSoftBytes(opts.SoftQuotaBytes).
+		SetHardBytes(opts.HardQuotaBytes).
+		SetBladeset(opts.Bladeset).
+		SetLayout(opts.Layout).
+		SetDescription(opts.Description).
+		SetEncryption(opts.Encryption || opts.Parameters[utils.VolumeParameters.GetSCKey("encryption")] == "on").
+		Build()
+	if err != nil {
+		return nil, errors.Join(ErrInvalidArgument, err)
+	}
+
+	vol, err := c.panfs.CreateVolume(ctx, name, params, c.secrets)
+	if err != nil {
+		return nil, err
+	}
+	return newVolume(vol), nil
+}
+
+// GetVolume returns the details of a volume.
+//
+// Parameters:
+//
+//	ctx  - The context for the operation.
+//	name - The name of the volume.
+//
+// Returns:
+//
+//	*Volume - The volume.
+//	error   - ErrNotFound if the volume does not exist, or another error if the lookup fails.
+func (c *Client) GetVolume(ctx context.Context, name string) (*Volume, error) {
+	vol, err := c.panfs.GetVolume(ctx, name, c.secrets)
+	if err != nil {
+		return nil, err
+	}
+	return newVolume(vol), nil
+}
+
+// ListVolumes returns all volumes of the realm.
+//
+// Parameters:
+//
+//	ctx - The context for the operation.
+//
+// Returns:
+//
+//	[]*Volume - The volumes.
+//	error     - Error if the volumes cannot be listed.
+func (c *Client) ListVolumes(ctx context.Context) ([]*Volume, error) {
+	list, err := c.panfs.ListVolumes(ctx, c.secrets)
+	if err != nil {
+		return nil, err
+	}
+	vols := make([]*Volume, 0, len(list.Volumes))
+	for i := range list.Volumes {
+		vols = append(vols, newVolume(&list.Volumes[i]))
+	}
+	return vols, nil
+}
+
+// ExpandVolume sets the soft quota of a volume to the given size.
+//
+// Parameters:
+//
+//	ctx       - The context for the operation.
+//	name      - The name of the volume.
+//	sizeBytes - The new soft quota in bytes.
+//
+// Returns:
+//
+//	error - ErrNotFound if the volume does not exist, or another error if expansion fails.
+func (c *Client) ExpandVolume(ctx context.Context, name string, sizeBytes int64) error {
+	if sizeBytes <= 0 {
+		return errors.Join(ErrInvalidArgument, errors.New("size must be greater than zero"))
+	}
+	return c.panfs.ExpandVolume(ctx, name, sizeBytes, c.secrets)
+}
+
+// DeleteVolume deletes a volume.
+//
+// Parameters:
+//
+//	ctx  - The context for the operation.
+//	name - The name of the volume.
+//
+// Returns:
+//
+//	error - ErrNotFound if the volume does not exist, or another error if deletion fails.
+func (c *Client) DeleteVolume(ctx context.Context, name string) error {
+	return c.panfs.DeleteVolume(ctx, name, c.secrets)
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var testConfig = Config{
+	RealmAddress: "testrealm",
+	Username:     "testuser",
+	Password:     "testpass",
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(testConfig)
+	assert.NoError(t, err)
+
+	_, err = New(Config{RealmAddress: "testrealm", Username: "testuser"})
+	assert.EqualError(t, err, "either password or private key must be provided")
+
+	_, err = New(Config{RealmAddress: "testrealm", Username: "testuser", PrivateKey: "corrupt"})
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestClientVolumeOperations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	runnerMock := mock.NewMockSSHRunner(ctrl)
+
+	c, err := New(testConfig, WithSSHRunner(runnerMock))
+	assert.NoError(t, err)
+
+	pasxml, _ := (&utils.Volume{ID: "371", Name: "vol", State: "Online", Soft: 1}).MarshalVolumeToPasXML()
+
+	t.Run("CreateVolume", func(t *testing.T) {
+		runnerMock.EXPECT().RunCommand(gomock.Any(), gomock.Any(), "volume", "create", "vol", gomock.Any(), gomock.Any()).Times(1).Return([]byte{}, nil)
+		runnerMock.EXPECT().RunCommand(gomock.Any(), gomock.Any(), "pasxml", "volumes", "volume", "vol").Times(1).Return(pasxml, nil)
+
+		vol, err := c.CreateVolume(t.Context(), "vol", CreateVolumeOptions{SoftQuotaBytes: utils.GBToBytes(1)})
+		assert.NoError(t, err)
+		assert.Equal(t, &Volume{Name: "vol", ID: "371", State: "Online", SoftQuotaBytes: utils.GBToBytes(1)}, vol)
+	})
+
+	t.Run("CreateVolumeInvalidQuota", func(t *testing.T) {
+		_, err := c.CreateVolume(t.Context(), "vol", CreateVolumeOptions{SoftQuotaBytes: -1})
+		assert.ErrorIs(t, err, ErrInvalidArgument)
+	})
+
+	t.Run("ListVolumes", func(t *testing.T) {
+		runnerMock.EXPECT().RunCommand(gomock.Any(), gomock.Any(), "pasxml", "volumes").Times(1).Return(pasxml, nil)
+
+		vols, err := c.ListVolumes(t.Context())
+		assert.NoError(t, err)
+		assert.Len(t, vols, 1)
+		assert.Equal(t, "vol", vols[0].Name)
+	})
+
+	t.Run("ExpandVolumeNotFound", func(t *testing.T) {
+		runnerMock.EXPECT().RunCommand(gomock.Any(), gomock.Any(), "volume", "set", "soft-quota", "vol", "2.00").Times(1).
+			Return(nil, fmt.Errorf("%w: vol", ErrNotFound))
+
+		err := c.ExpandVolume(t.Context(), "vol", utils.GBToBytes(2))
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("DeleteVolumeCanceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		runnerMock.EXPECT().RunCommand(ctx, gomock.Any(), "volume", "delete", "-f", "vol").Times(1).
+			DoAndReturn(func(ctx context.Context, _ map[string]string, _ ...string) ([]byte, error) {
+				return nil, ctx.Err()
+			})
+
+		err := c.DeleteVolume(ctx, "vol")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
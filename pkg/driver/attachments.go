@@ -0,0 +1,302 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DriverVolumeLabelKey is the label put on attachment records. Its value is a hash of the
+// volume id, as volume ids are not valid label values.
+const DriverVolumeLabelKey = "panfs.csi.vdura.com/volume"
+
+// attachment record data keys
+const (
+	attachmentVolumeIDKey   = "volumeId"
+	attachmentNodeIDKey     = "nodeId"
+	attachmentAccessModeKey = "accessMode"
+	attachmentReadonlyKey   = "readonly"
+)
+
+// singleNodeAccessModes are the access modes allowing a volume to be published to one node
+// at a time.
+var singleNodeAccessModes = []csi.VolumeCapability_AccessMode_Mode{
+	csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+	csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY,
+	csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+	csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER,
+}
+
+// WithAttachmentTracking enables ControllerPublishVolume and ControllerUnpublishVolume, which
+// record the nodes each volume is published to and refuse to publish volumes with a single
+// node access mode to a second node. PanFS itself does not restrict the clients mounting a
+// volume, so this enforces ReadWriteOnce semantics. Records are ConfigMaps in the namespace
+// labeled with DriverOwnerLabelKey, DriverNodeLabelKey and DriverVolumeLabelKey, removed
+// together with deleted nodes by WithStaleNodeCleanup. Requires the CSIDriver object to set
+// attachRequired and the csi-attacher sidecar.
+//
+// Parameters:
+//
+//	namespace - The namespace the driver is installed in. Empty disables the tracking.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithAttachmentTracking(namespace string) Option {
+	return func(d *Driver) {
+		d.attachmentNamespace = namespace
+	}
+}
+
+// attachmentTracking reports whether ControllerPublishVolume is served.
+func (d *Driver) attachmentTracking() bool {
+	return d.attachmentNamespace != "" && d.kubeClient != nil
+}
+
+// volumeLabel returns the value of DriverVolumeLabelKey of a volume.
+func volumeLabel(volumeID string) string {
+	sum := sha256.Sum256([]byte(volumeID))
+	return hex.EncodeToString(sum[:16])
+}
+
+// attachmentName returns the name of the attachment record of a volume on a node.
+func attachmentName(volumeID, nodeID string) string {
+	sum := sha256.Sum256([]byte(volumeID + "\x00" + nodeID))
+	return "panfs-csi-attachment-" + hex.EncodeToString(sum[:16])
+}
+
+// isSingleNodeMode reports whether the access mode allows one node at a time.
+func isSingleNodeMode(mode string) bool {
+	return slices.ContainsFunc(singleNodeAccessModes, func(m csi.VolumeCapability_AccessMode_Mode) bool {
+		return m.String() == mode
+	})
+}
+
+// ControllerPublishVolume handles the CSI ControllerPublishVolume request. The attachment of the
+// volume to the node is recorded; volumes with a single node access mode are published to one
+// node at a time. The realm is not contacted, so the request needs no secrets.
+//
+// Parameters:
+//
+//	ctx - The context for the request.
+//	in  - The ControllerPublishVolumeRequest containing the volume id, node id and capability.
+//
+// Returns:
+//
+//	*csi.ControllerPublishVolumeResponse - The response with an empty publish context.
+//	error - Returns an error if validation fails or the attachment cannot be recorded.
+//
+// Error Cases:
+//   - codes.Unimplemented: If attachment tracking is disabled (see WithAttachmentTracking).
+//   - codes.InvalidArgument: If the volume id, node id or volume capability is missing or unsupported.
+//   - codes.AlreadyExists: If the volume is published to the node with a different capability
+//     or readonly flag.
+//   - codes.FailedPrecondition: If a single node volume is published to another node.
+//   - codes.Internal: If the attachment records cannot be read or written.
+//   - codes.Aborted: If another controller operation on the volume is in progress, e.g. a
+//     racing ControllerUnpublishVolume request.
+func (d *Driver) ControllerPublishVolume(ctx context.Context, in *csi.ControllerPublishVolumeRequest) (*csi.ControllerPublishVolumeResponse, error) {
+	llog := d.requestLogger(ctx).WithValues("method", "ControllerPublishVolume")
+	llog.V(2).Info("ControllerPublishVolume called",
+		"volume_id", in.GetVolumeId(),
+		"node_id", in.GetNodeId(),
+		"volume_capability", in.GetVolumeCapability(),
+		"readonly", in.GetReadonly(),
+	)
+
+	if !d.attachmentTracking() {
+		return nil, status.Error(codes.Unimplemented, "")
+	}
+
+	volumeID, nodeID := in.GetVolumeId(), in.GetNodeId()
+	if volumeID == "" {
+		llog.Error(fmt.Errorf("volume id must be provided"), InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, "volume id must be provided")
+	}
+	if nodeID == "" {
+		llog.Error(fmt.Errorf("node id must be provided"), InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, "node id must be provided")
+	}
+	capability := in.GetVolumeCapability()
+	if capability == nil {
+		llog.Error(fmt.Errorf("volume capability must be provided"), InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, "volume capability must be provided")
+	}
+	if err := d.validateVolumeCapabilities([]*csi.VolumeCapability{capability}); err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	unlock, err := d.volumeLocks.tryLock(volumeID, "publish")
+	if err != nil {
+		llog.Error(err, "volume operation in progress")
+		return nil, err
+	}
+	defer unlock()
+
+	records, err := d.attachments(ctx, volumeID)
+	if err != nil {
+		llog.Error(err, "failed to read the attachments of the volume")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	mode := capability.GetAccessMode().GetMode().String()
+	readonly := strconv.FormatBool(in.GetReadonly())
+	for _, record := range records {
+		node := record.Data[attachmentNodeIDKey]
+		if node == nodeID {
+			if record.Data[attachmentAccessModeKey] != mode || record.Data[attachmentReadonlyKey] != readonly {
+				err := fmt.Errorf("volume %s is published to node %s as %s (readonly %s)", volumeID, nodeID,
+					record.Data[attachmentAccessModeKey], record.Data[attachmentReadonlyKey])
+				llog.Error(err, "incompatible publish request")
+				return nil, status.Error(codes.AlreadyExists, err.Error())
+			}
+			llog.V(4).Info("volume is already published to the node", "volume_id", volumeID, "node_id", nodeID)
+			return &csi.ControllerPublishVolumeResponse{}, nil
+		}
+		if isSingleNodeMode(mode) || isSingleNodeMode(record.Data[attachmentAccessModeKey]) {
+			err := fmt.Errorf("volume %s is published to node %s as %s", volumeID, node, record.Data[attachmentAccessModeKey])
+			llog.Error(err, "single node volume is published to another node")
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+
+	record := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: attachmentName(volumeID, nodeID),
+			Labels: map[string]string{
+				DriverOwnerLabelKey:  d.Name,
+				DriverNodeLabelKey:   nodeID,
+				DriverVolumeLabelKey: volumeLabel(volumeID),
+			},
+		},
+		Data: map[string]string{
+			attachmentVolumeIDKey:   volumeID,
+			attachmentNodeIDKey:     nodeID,
+			attachmentAccessModeKey: mode,
+			attachmentReadonlyKey:   readonly,
+		},
+	}
+	if _, err := d.kubeClient.CoreV1().ConfigMaps(d.attachmentNamespace).Create(ctx, record, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		llog.Error(err, "failed to record the attachment", "volume_id", volumeID, "node_id", nodeID)
+		return nil, status.Errorf(codes.Internal, "failed to record the attachment of volume %s to node %s: %v", volumeID, nodeID, err)
+	}
+
+	llog.Info("volume published", "volume_id", volumeID, "node_id", nodeID, "access_mode", mode)
+	return &csi.ControllerPublishVolumeResponse{}, nil
+}
+
+// ControllerUnpublishVolume handles the CSI ControllerUnpublishVolume request. The attachment
+// record of the volume on the node is removed, or the records on all nodes if no node id is
+// given. Volumes which are not published are reported as unpublished.
+//
+// Parameters:
+//
+//	ctx - The context for the request.
+//	in  - The ControllerUnpublishVolumeRequest containing the volume id and node id.
+//
+// Returns:
+//
+//	*csi.ControllerUnpublishVolumeResponse - The response indicating success.
+//	error - Returns an error if validation fails or the attachment cannot be removed.
+//
+// Error Cases:
+//   - codes.Unimplemented: If attachment tracking is disabled (see WithAttachmentTracking).
+//   - codes.InvalidArgument: If the volume id is missing.
+//   - codes.Internal: If the attachment records cannot be read or removed.
+//   - codes.Aborted: If another controller operation on the volume is in progress, e.g. a
+//     racing ControllerPublishVolume request.
+func (d *Driver) ControllerUnpublishVolume(ctx context.Context, in *csi.ControllerUnpublishVolumeRequest) (*csi.ControllerUnpublishVolumeResponse, error) {
+	llog := d.requestLogger(ctx).WithValues("method", "ControllerUnpublishVolume")
+	llog.V(2).Info("ControllerUnpublishVolume called", "volume_id", in.GetVolumeId(), "node_id", in.GetNodeId())
+
+	if !d.attachmentTracking() {
+		return nil, status.Error(codes.Unimplemented, "")
+	}
+
+	volumeID, nodeID := in.GetVolumeId(), in.GetNodeId()
+	if volumeID == "" {
+		llog.Error(fmt.Errorf("volume id must be provided"), InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, "volume id must be provided")
+	}
+
+	unlock, err := d.volumeLocks.tryLock(volumeID, "unpublish")
+	if err != nil {
+		llog.Error(err, "volume operation in progress")
+		return nil, err
+	}
+	defer unlock()
+
+	records, err := d.attachments(ctx, volumeID)
+	if err != nil {
+		llog.Error(err, "failed to read the attachments of the volume")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	for _, record := range records {
+		if nodeID != "" && record.Data[attachmentNodeIDKey] != nodeID {
+			continue
+		}
+		err := d.kubeClient.CoreV1().ConfigMaps(d.attachmentNamespace).Delete(ctx, record.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			llog.Error(err, "failed to remove the attachment", "volume_id", volumeID, "node_id", record.Data[attachmentNodeIDKey])
+			return nil, status.Errorf(codes.Internal, "failed to remove the attachment of volume %s to node %s: %v",
+				volumeID, record.Data[attachmentNodeIDKey], err)
+		}
+		llog.Info("volume unpublished", "volume_id", volumeID, "node_id", record.Data[attachmentNodeIDKey])
+	}
+
+	return &csi.ControllerUnpublishVolumeResponse{}, nil
+}
+
+// attachments returns the attachment records of a volume.
+//
+// Parameters:
+//
+//	ctx      - The context for the Kubernetes API call.
+//	volumeID - The volume id.
+//
+// Returns:
+//
+//	[]corev1.ConfigMap - The records of the nodes the volume is published to.
+//	error              - Error if the records cannot be listed.
+func (d *Driver) attachments(ctx context.Context, volumeID string) ([]corev1.ConfigMap, error) {
+	cms, err := d.kubeClient.CoreV1().ConfigMaps(d.attachmentNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s,%s=%s", ownerSelector(d.Name), DriverVolumeLabelKey, volumeLabel(volumeID)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the attachments of volume %s: %w", volumeID, err)
+	}
+
+	// a hash collision of the volume label must not mix up volumes
+	var records []corev1.ConfigMap
+	for _, cm := range cms.Items {
+		if cm.Data[attachmentVolumeIDKey] == volumeID {
+			records = append(records, cm)
+		}
+	}
+	return records, nil
+}
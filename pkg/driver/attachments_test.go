@@ -0,0 +1,156 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+)
+
+// newAttachmentTestDriver returns a driver tracking attachments in the csi-panfs namespace.
+func newAttachmentTestDriver() *Driver {
+	d := &Driver{Name: DefaultDriverName, log: klog.Background(), kubeClient: fake.NewClientset()}
+	WithAttachmentTracking("csi-panfs")(d)
+	return d
+}
+
+// publishRequest returns a ControllerPublishVolumeRequest of the volume to the node.
+func publishRequest(volumeID, nodeID string, mode csi.VolumeCapability_AccessMode_Mode) *csi.ControllerPublishVolumeRequest {
+	return &csi.ControllerPublishVolumeRequest{
+		VolumeId: volumeID,
+		NodeId:   nodeID,
+		VolumeCapability: &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+			AccessMode: &csi.VolumeCapability_AccessMode{Mode: mode},
+		},
+	}
+}
+
+// TestControllerPublishSingleNode verifies that single node volumes are published to one node
+// at a time, and to another node once unpublished.
+func TestControllerPublishSingleNode(t *testing.T) {
+	d := newAttachmentTestDriver()
+	rwo := csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER
+
+	_, err := d.ControllerPublishVolume(t.Context(), publishRequest("vol1", "node1", rwo))
+	require.NoError(t, err)
+
+	// publishing again is idempotent
+	_, err = d.ControllerPublishVolume(t.Context(), publishRequest("vol1", "node1", rwo))
+	require.NoError(t, err)
+
+	_, err = d.ControllerPublishVolume(t.Context(), publishRequest("vol1", "node2", rwo))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// other volumes are independent
+	_, err = d.ControllerPublishVolume(t.Context(), publishRequest("vol2", "node2", rwo))
+	require.NoError(t, err)
+
+	_, err = d.ControllerUnpublishVolume(t.Context(), &csi.ControllerUnpublishVolumeRequest{VolumeId: "vol1", NodeId: "node1"})
+	require.NoError(t, err)
+	_, err = d.ControllerPublishVolume(t.Context(), publishRequest("vol1", "node2", rwo))
+	require.NoError(t, err)
+
+	cms, err := d.kubeClient.CoreV1().ConfigMaps("csi-panfs").List(t.Context(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, cms.Items, 2)
+	for _, cm := range cms.Items {
+		assert.Equal(t, "node2", cm.Labels[DriverNodeLabelKey])
+		assert.Equal(t, DefaultDriverName, cm.Labels[DriverOwnerLabelKey])
+	}
+}
+
+// TestControllerPublishMultiNode verifies that multi node volumes are published to many nodes
+// and unpublished from all nodes without a node id.
+func TestControllerPublishMultiNode(t *testing.T) {
+	d := newAttachmentTestDriver()
+	rwx := csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER
+
+	for _, node := range []string{"node1", "node2", "node3"} {
+		_, err := d.ControllerPublishVolume(t.Context(), publishRequest("vol1", node, rwx))
+		require.NoError(t, err)
+	}
+
+	// a single node publish of a volume published to other nodes is refused
+	_, err := d.ControllerPublishVolume(t.Context(), publishRequest("vol1", "node4", csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// the same node with a different capability is incompatible
+	readonly := publishRequest("vol1", "node1", rwx)
+	readonly.Readonly = true
+	_, err = d.ControllerPublishVolume(t.Context(), readonly)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	records, err := d.attachments(t.Context(), "vol1")
+	require.NoError(t, err)
+	assert.Len(t, records, 3)
+
+	_, err = d.ControllerUnpublishVolume(t.Context(), &csi.ControllerUnpublishVolumeRequest{VolumeId: "vol1"})
+	require.NoError(t, err)
+	records, err = d.attachments(t.Context(), "vol1")
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	// unpublishing a volume which is not published succeeds
+	_, err = d.ControllerUnpublishVolume(t.Context(), &csi.ControllerUnpublishVolumeRequest{VolumeId: "vol1", NodeId: "node1"})
+	assert.NoError(t, err)
+}
+
+// TestControllerPublishInvalid verifies the validation of publish requests.
+func TestControllerPublishInvalid(t *testing.T) {
+	d := newAttachmentTestDriver()
+	rwo := csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER
+
+	tests := map[string]*csi.ControllerPublishVolumeRequest{
+		"MissingVolumeID":   publishRequest("", "node1", rwo),
+		"MissingNodeID":     publishRequest("vol1", "", rwo),
+		"MissingCapability": {VolumeId: "vol1", NodeId: "node1"},
+		"BlockVolume": {
+			VolumeId: "vol1",
+			NodeId:   "node1",
+			VolumeCapability: &csi.VolumeCapability{
+				AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}},
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: rwo},
+			},
+		},
+	}
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := d.ControllerPublishVolume(t.Context(), req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+
+	_, err := d.ControllerUnpublishVolume(t.Context(), &csi.ControllerUnpublishVolumeRequest{NodeId: "node1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// TestAttachmentTrackingCapability verifies that PUBLISH_UNPUBLISH_VOLUME is only advertised if
+// attachments are tracked.
+func TestAttachmentTrackingCapability(t *testing.T) {
+	publish := csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME
+
+	assert.NotContains(t, (&Driver{}).advertisedControllerCapabilities(), publish)
+	assert.Contains(t, newAttachmentTestDriver().advertisedControllerCapabilities(), publish)
+	assert.NotContains(t, controllerCapabilities, publish)
+}
@@ -0,0 +1,265 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// bulkValidationConcurrency is the number of directory volumes read concurrently by
+// ValidateVolumes.
+const bulkValidationConcurrency = 8
+
+// ValidateVolumesPath is the path of the debug endpoint validating volumes in bulk.
+const ValidateVolumesPath = "/debug/validate-volumes"
+
+// VolumeValidation is the result of the validation of a single volume by ValidateVolumes.
+type VolumeValidation struct {
+	// VolumeID is the validated volume ID.
+	VolumeID string `json:"volume_id"`
+	// Confirmed is true if the volume exists and supports the capabilities.
+	Confirmed bool `json:"confirmed"`
+	// Code is the gRPC code ValidateVolumeCapabilities returns for the volume, "OK" if confirmed.
+	Code string `json:"code"`
+	// Message describes why the volume is not confirmed.
+	Message string `json:"message,omitempty"`
+}
+
+// ValidateVolumes validates the capabilities of many volumes of a realm like
+// ValidateVolumeCapabilities, e.g. for tooling checking all PVs of a cluster before a migration
+// or garbage collection. Instead of reading each volume, the volumes of the realm are listed
+// once; directory volumes are read concurrently.
+//
+// Parameters:
+//
+//	ctx          - The context of the validation.
+//	volumeIDs    - The IDs of the volumes to validate.
+//	capabilities - The capabilities to validate.
+//	parameters   - The parameters with a credential handle, nil if the secrets are complete.
+//	secrets      - The secrets of the realm, nil to use the default credentials.
+//
+// Returns:
+//
+//	[]VolumeValidation - The result of each volume in the order of the IDs.
+//	error              - The gRPC status error if no volume can be validated,
+//	                     codes.InvalidArgument if the capabilities or secrets are invalid,
+//	                     codes.FailedPrecondition if no credentials are configured, the realm
+//	                     error of listing the volumes otherwise.
+func (d *Driver) ValidateVolumes(ctx context.Context, volumeIDs []string, capabilities []*csi.VolumeCapability, parameters, secrets map[string]string) ([]VolumeValidation, error) {
+	llog := d.requestLogger(ctx).WithValues("method", "ValidateVolumes")
+	llog.V(2).Info("ValidateVolumes called", "volumes", len(volumeIDs), "capabilities", capabilities)
+
+	if len(capabilities) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "volume capabilities must be provided")
+	}
+	if err := d.validateVolumeCapabilities(capabilities); err != nil {
+		return nil, status.Error(codes.InvalidArgument, VolumeCapabilitiesDoNotMatchErrorStr)
+	}
+
+	var err error
+	if len(secrets) == 0 {
+		secrets, err = d.defaultSecrets(ctx, parameters)
+	} else {
+		secrets, err = d.resolveCredentials(ctx, secrets, parameters)
+	}
+	if err != nil {
+		llog.Error(err, "failed to resolve realm credentials")
+		return nil, err
+	}
+	if _, err := d.parseRealmSecrets(secrets); err != nil {
+		llog.Error(err, InvalidRequestSecretsErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	release, err := d.realmLimiter.acquire(ctx, secrets[utils.RealmConnectionContext.RealmAddress], priorityBackground)
+	if err != nil {
+		llog.Error(err, "realm session slots are busy")
+		return nil, err
+	}
+	defer release()
+
+	list, err := d.realm(ctx).ListVolumes(ctx, secrets)
+	if err != nil {
+		llog.Error(err, "failed to list volumes")
+		return nil, realmError(err)
+	}
+	volumes := make(map[string]bool, len(list.Volumes))
+	for _, vol := range list.Volumes {
+		volumes[string(vol.Name)] = true
+	}
+
+	results := make([]VolumeValidation, len(volumeIDs))
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, bulkValidationConcurrency)
+	for i, volumeID := range volumeIDs {
+		if volumeID == "" {
+			results[i] = volumeValidation(volumeID, status.Error(codes.InvalidArgument, "volume id must not be empty"))
+			continue
+		}
+
+		parentVolume, directory, isDirectory := utils.ParseDirectoryVolumeID(volumeID)
+		if !isDirectory {
+			parentVolume = volumeID
+		}
+		// the volume, or the parent volume of a directory volume, must be listed
+		if !volumes[parentVolume] {
+			results[i] = volumeValidation(volumeID, pancli.ErrorNotFound)
+			continue
+		}
+		if !isDirectory {
+			results[i] = volumeValidation(volumeID, nil)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			_, err := d.realm(ctx).GetDirectory(ctx, parentVolume, directory, secrets)
+			results[i] = volumeValidation(volumeID, err)
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// volumeValidation returns the validation result of a volume with the error of reading it,
+// mapped like ValidateVolumeCapabilities.
+//
+// Parameters:
+//
+//	volumeID - The ID of the volume.
+//	err      - The error of reading the volume, or a gRPC status error, nil if it exists.
+//
+// Returns:
+//
+//	VolumeValidation - The validation result.
+func volumeValidation(volumeID string, err error) VolumeValidation {
+	if _, ok := status.FromError(err); !ok {
+		switch {
+		case errors.Is(err, pancli.ErrorNotFound):
+			err = status.Error(codes.NotFound, VolumeNotFoundErrorStr)
+		case errors.Is(err, pancli.ErrorUnauthenticated):
+			err = status.Error(codes.Unauthenticated, err.Error())
+		default:
+			err = status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	s := status.Convert(err)
+	return VolumeValidation{
+		VolumeID:  volumeID,
+		Confirmed: s.Code() == codes.OK,
+		Code:      s.Code().String(),
+		Message:   s.Message(),
+	}
+}
+
+// validateVolumesRequest is the body of a request to the ValidateVolumesPath debug endpoint.
+type validateVolumesRequest struct {
+	// VolumeIDs are the IDs of the volumes to validate.
+	VolumeIDs []string `json:"volume_ids"`
+	// AccessModes are the names of the CSI access modes to validate with a mount access type,
+	// e.g. "MULTI_NODE_MULTI_WRITER".
+	AccessModes []string `json:"access_modes"`
+	// Credentials is the credential handle of the realm, the default credentials if empty.
+	Credentials string `json:"credentials,omitempty"`
+}
+
+// validateVolumesResponse is the body of a response of the ValidateVolumesPath debug endpoint.
+type validateVolumesResponse struct {
+	Volumes []VolumeValidation `json:"volumes"`
+}
+
+// ValidateVolumesHandler returns the HTTP handler of the ValidateVolumesPath debug endpoint,
+// validating volumes in bulk with ValidateVolumes. The realm is accessed with a credential
+// handle or the default credentials, so the request carries no secrets. POST requests carry
+// a JSON body like {"volume_ids": ["pvc-1"], "access_modes": ["MULTI_NODE_MULTI_WRITER"]}.
+//
+// Returns:
+//
+//	http.Handler - The debug endpoint handler.
+func (d *Driver) ValidateVolumesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req validateVolumesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		capabilities := make([]*csi.VolumeCapability, 0, len(req.AccessModes))
+		for _, name := range req.AccessModes {
+			mode, ok := csi.VolumeCapability_AccessMode_Mode_value[name]
+			if !ok {
+				http.Error(w, fmt.Sprintf("invalid access mode %q", name), http.StatusBadRequest)
+				return
+			}
+			capabilities = append(capabilities, &csi.VolumeCapability{
+				AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_Mode(mode)},
+			})
+		}
+		var parameters map[string]string
+		if req.Credentials != "" {
+			parameters = map[string]string{utils.VolumeParameters.GetSCKey("credentials"): req.Credentials}
+		}
+
+		volumes, err := d.ValidateVolumes(r.Context(), req.VolumeIDs, capabilities, parameters, nil)
+		if err != nil {
+			http.Error(w, err.Error(), httpStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(validateVolumesResponse{Volumes: volumes}); err != nil {
+			d.log.Error(err, "failed to write the response of the debug endpoint", "path", ValidateVolumesPath)
+		}
+	})
+}
+
+// httpStatus returns the HTTP status code of a gRPC status error of a debug endpoint.
+func httpStatus(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.Canceled, codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
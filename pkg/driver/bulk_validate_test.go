@@ -0,0 +1,121 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestValidateVolumes verifies that volumes are validated with a single listing of the realm
+// and directory volumes are read individually.
+func TestValidateVolumes(t *testing.T) {
+	list := &utils.VolumeList{Volumes: []utils.Volume{{Name: "vol-a"}, {Name: "shared"}}}
+
+	t.Run("Volumes", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().ListVolumes(gomock.Any(), defaultSecrets).Return(list, nil)
+		pancliMock.EXPECT().GetDirectory(gomock.Any(), "shared", "dir-a", defaultSecrets).Return(&utils.Directory{Name: "dir-a", VolumeName: "shared"}, nil)
+		pancliMock.EXPECT().GetDirectory(gomock.Any(), "shared", "dir-b", defaultSecrets).Return(nil, pancli.ErrorNotFound)
+
+		results, err := d.ValidateVolumes(t.Context(), []string{"vol-a", "vol-b", "", "shared/dir-a", "shared/dir-b", "missing/dir-c"},
+			[]*csi.VolumeCapability{mountCapability()}, nil, defaultSecrets)
+		require.NoError(t, err)
+		assert.Equal(t, []VolumeValidation{
+			{VolumeID: "vol-a", Confirmed: true, Code: "OK"},
+			{VolumeID: "vol-b", Code: "NotFound", Message: VolumeNotFoundErrorStr},
+			{VolumeID: "", Code: "InvalidArgument", Message: "volume id must not be empty"},
+			{VolumeID: "shared/dir-a", Confirmed: true, Code: "OK"},
+			{VolumeID: "shared/dir-b", Code: "NotFound", Message: VolumeNotFoundErrorStr},
+			{VolumeID: "missing/dir-c", Code: "NotFound", Message: VolumeNotFoundErrorStr},
+		}, results)
+	})
+
+	t.Run("DefaultCredentials", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		WithCredentialProvider(&staticCredentials{values: map[string]map[string]string{"csi-panfs/realm": defaultSecrets}}, 0)(d)
+		WithDefaultCredentials("csi-panfs/realm")(d)
+		pancliMock.EXPECT().ListVolumes(gomock.Any(), defaultSecrets).Return(list, nil)
+
+		results, err := d.ValidateVolumes(t.Context(), []string{"vol-a"}, []*csi.VolumeCapability{mountCapability()}, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []VolumeValidation{{VolumeID: "vol-a", Confirmed: true, Code: "OK"}}, results)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+
+		_, err := d.ValidateVolumes(t.Context(), []string{"vol-a"}, nil, nil, defaultSecrets)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		block := &csi.VolumeCapability{AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}}}
+		_, err = d.ValidateVolumes(t.Context(), []string{"vol-a"}, []*csi.VolumeCapability{block}, nil, defaultSecrets)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = d.ValidateVolumes(t.Context(), []string{"vol-a"}, []*csi.VolumeCapability{mountCapability()}, nil, nil)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		pancliMock.EXPECT().ListVolumes(gomock.Any(), defaultSecrets).Return(nil, pancli.ErrorUnavailable)
+		_, err = d.ValidateVolumes(t.Context(), []string{"vol-a"}, []*csi.VolumeCapability{mountCapability()}, nil, defaultSecrets)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
+
+// TestValidateVolumesHandler verifies the requests and responses of the bulk validation debug
+// endpoint.
+func TestValidateVolumesHandler(t *testing.T) {
+	d, pancliMock := newSnapshotTestDriver(t)
+	WithCredentialProvider(&staticCredentials{values: map[string]map[string]string{"csi-panfs/realm": defaultSecrets}}, 0)(d)
+	handler := d.ValidateVolumesHandler()
+
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, ValidateVolumesPath, strings.NewReader(body)))
+		return rec
+	}
+
+	t.Run("Validated", func(t *testing.T) {
+		pancliMock.EXPECT().ListVolumes(gomock.Any(), defaultSecrets).Return(&utils.VolumeList{Volumes: []utils.Volume{{Name: "vol-a"}}}, nil)
+
+		rec := serve(http.MethodPost, `{"volume_ids": ["vol-a", "vol-b"], "access_modes": ["MULTI_NODE_MULTI_WRITER"], "credentials": "csi-panfs/realm"}`)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var resp validateVolumesResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, []VolumeValidation{
+			{VolumeID: "vol-a", Confirmed: true, Code: "OK"},
+			{VolumeID: "vol-b", Code: "NotFound", Message: VolumeNotFoundErrorStr},
+		}, resp.Volumes)
+	})
+
+	t.Run("InvalidRequests", func(t *testing.T) {
+		assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodGet, "").Code)
+		assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "{").Code)
+		assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, `{"volume_ids": ["vol-a"], "access_modes": ["ANY"]}`).Code)
+		// neither a credential handle nor default credentials
+		assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, `{"volume_ids": ["vol-a"], "access_modes": ["SINGLE_NODE_WRITER"]}`).Code)
+	})
+}
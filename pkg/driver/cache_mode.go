@@ -0,0 +1,130 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// Values of the "panfs.csi.vdura.com/cacheMode" storage class parameter.
+const (
+	// CacheModeNone disables the node-local cache of the PanFS client.
+	CacheModeNone = "none"
+	// CacheModeReadOnly caches file data read by the node.
+	CacheModeReadOnly = "readonly"
+	// CacheModeWriteBack caches file data read and written by the node, writing it back asynchronously.
+	CacheModeWriteBack = "writeback"
+)
+
+// CacheModeMinClientVersion is the oldest PanFS client version supporting the node-local cache.
+const CacheModeMinClientVersion = "11.0"
+
+// clientVersionFile is the sysfs file reporting the version of the loaded PanFS client module.
+const clientVersionFile = "/sys/module/panfs/version"
+
+// cacheModeList lists the supported cache modes.
+var cacheModeList = []string{CacheModeNone, CacheModeReadOnly, CacheModeWriteBack}
+
+// readClientVersion returns the version of the PanFS client loaded on the node. It is a
+// variable so tests can replace it.
+var readClientVersion = func() (string, error) {
+	data, err := os.ReadFile(clientVersionFile)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// cacheModeOptions returns the mount options for the cache mode selected in the volume context.
+// Modes other than none are only accepted if the PanFS client on the node supports caching.
+//
+// Parameters:
+//
+//	volumeContext - The volume context of the volume.
+//
+// Returns:
+//
+//	[]string - The mount options, nil if no cache mode or none is selected.
+//	error    - Error if the mode is unknown or not supported by the PanFS client of the node.
+func cacheModeOptions(volumeContext map[string]string) ([]string, error) {
+	mode := volumeContext[utils.VolumeParameters.GetSCKey("cacheMode")]
+	if mode == "" || mode == CacheModeNone {
+		return nil, nil
+	}
+	if !utils.In(mode, cacheModeList...) {
+		return nil, fmt.Errorf("cache mode %q is not one of: %v", mode, cacheModeList)
+	}
+
+	version, err := readClientVersion()
+	if err != nil {
+		return nil, fmt.Errorf("cache mode %q requires PanFS client %s or later, failed to detect the client version: %w",
+			mode, CacheModeMinClientVersion, err)
+	}
+	if compareVersions(version, CacheModeMinClientVersion) < 0 {
+		return nil, fmt.Errorf("cache mode %q requires PanFS client %s or later, the node runs %s",
+			mode, CacheModeMinClientVersion, version)
+	}
+
+	return []string{"cachemode=" + mode}, nil
+}
+
+// compareVersions compares the leading numeric components of two dotted versions, e.g.
+// "11.1.0.a-1234567.1" and "11.0". Missing components count as zero.
+//
+// Parameters:
+//
+//	a - The first version.
+//	b - The second version.
+//
+// Returns:
+//
+//	int - -1 if a is older than b, 1 if a is newer than b, 0 otherwise.
+func compareVersions(a, b string) int {
+	va, vb := versionNumbers(a), versionNumbers(b)
+	for i := 0; i < max(len(va), len(vb)); i++ {
+		var x, y int
+		if i < len(va) {
+			x = va[i]
+		}
+		if i < len(vb) {
+			y = vb[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionNumbers returns the leading numeric components of a dotted version.
+func versionNumbers(version string) []int {
+	var numbers []int
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"k8s.io/klog/v2"
+)
+
+// TestCacheModeOptions verifies the translation of cache modes to mount options and the
+// check of the PanFS client version.
+func TestCacheModeOptions(t *testing.T) {
+	origReadClientVersion := readClientVersion
+	t.Cleanup(func() { readClientVersion = origReadClientVersion })
+
+	tests := []struct {
+		name        string
+		mode        string
+		version     string
+		versionErr  error
+		wantOptions []string
+		wantErr     string
+	}{
+		{name: "NotSet"},
+		{name: "None", mode: CacheModeNone, versionErr: errors.New("not loaded")},
+		{name: "ReadOnly", mode: CacheModeReadOnly, version: "11.0.0", wantOptions: []string{"cachemode=readonly"}},
+		{name: "WriteBack", mode: CacheModeWriteBack, version: "11.1.0.a-1234567.1", wantOptions: []string{"cachemode=writeback"}},
+		{name: "Unknown", mode: "always", wantErr: `cache mode "always" is not one of`},
+		{name: "OldClient", mode: CacheModeReadOnly, version: "10.3.1", wantErr: "the node runs 10.3.1"},
+		{name: "VersionUnknown", mode: CacheModeReadOnly, versionErr: errors.New("no such file"), wantErr: "failed to detect the client version: no such file"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			readClientVersion = func() (string, error) { return tc.version, tc.versionErr }
+
+			volumeContext := map[string]string{}
+			if tc.mode != "" {
+				volumeContext[utils.VolumeParameters.GetSCKey("cacheMode")] = tc.mode
+			}
+
+			options, err := cacheModeOptions(volumeContext)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantOptions, options)
+		})
+	}
+}
+
+// TestCompareVersions verifies the comparison of dotted PanFS client versions.
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("11.0", "11.0.0"))
+	assert.Equal(t, 1, compareVersions("11.1.0.a-1234567.1", "11.0"))
+	assert.Equal(t, -1, compareVersions("10.12", "11.0"))
+	assert.Equal(t, -1, compareVersions("", "11.0"))
+}
+
+// TestCreateVolumeCacheMode verifies that the cache mode of the storage class is passed to
+// the node plugin in the volume context.
+func TestCreateVolumeCacheMode(t *testing.T) {
+	cacheModeKey := utils.VolumeParameters.GetSCKey("cacheMode")
+
+	ctrl := gomock.NewController(t)
+	pancliMock := mock.NewMockStorageProviderClient(ctrl)
+	d := &Driver{panfs: pancliMock, log: klog.Background()}
+	pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), defaultSecrets).
+		Return(&utils.Volume{Name: utils.VolumeName(validVolumeName)}, nil)
+
+	resp, err := d.CreateVolume(t.Context(), &csi.CreateVolumeRequest{
+		Name:       validVolumeName,
+		Parameters: map[string]string{cacheModeKey: CacheModeWriteBack},
+		Secrets:    defaultSecrets,
+		VolumeCapabilities: []*csi.VolumeCapability{
+			{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, CacheModeWriteBack, resp.GetVolume().GetVolumeContext()[cacheModeKey])
+}
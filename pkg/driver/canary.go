@@ -0,0 +1,98 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// WithCanaryVolume enables the node startup self-test. Before serving requests the driver
+// mounts the canary volume read-only, stats the mount and unmounts it again. If the self-test
+// fails Run returns an error, so the node is never registered and never labeled as ready.
+//
+// Parameters:
+//
+//	volume - The canary volume as "<realm>/<volume>", a small volume dedicated to the self-test.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithCanaryVolume(volume string) Option {
+	return func(d *Driver) {
+		d.canaryVolume = volume
+	}
+}
+
+// canarySelfTest mounts the canary volume, if configured, to verify that the PanFS client of
+// the node works.
+//
+// Returns:
+//
+//	error - Error if the canary volume cannot be mounted, read or unmounted.
+func (d *Driver) canarySelfTest() error {
+	if d.canaryVolume == "" {
+		return nil
+	}
+
+	llog := d.log.WithValues("canary_volume", d.canaryVolume)
+	llog.Info("running node self-test")
+	ctx := klog.NewContext(context.Background(), llog)
+
+	// the realm address may be an IPv6 address, split at the last separator
+	sep := strings.LastIndex(d.canaryVolume, "/")
+	if sep < 0 {
+		return fmt.Errorf("invalid canary volume %q: expected <realm>/<volume>", d.canaryVolume)
+	}
+	realm, volume := d.canaryVolume[:sep], d.canaryVolume[sep+1:]
+	if _, err := utils.ParseRealmAddresses(realm); err != nil {
+		return fmt.Errorf("invalid canary volume %q: %w", d.canaryVolume, err)
+	}
+
+	target, err := os.MkdirTemp("", "panfs-canary-")
+	if err != nil {
+		return fmt.Errorf("failed to create canary mount point: %w", err)
+	}
+	defer func() {
+		if err := osRemove(target); err != nil && !os.IsNotExist(err) {
+			llog.Error(err, "failed to remove canary mount point", "target_path", target)
+		}
+	}()
+
+	if err := d.mountRealmVolume(ctx, realm, volume, target, []string{"ro"}); err != nil {
+		return fmt.Errorf("failed to mount canary volume %s: %w", d.canaryVolume, err)
+	}
+
+	statErr := d.mounts.checkMount(target)
+	if statErr != nil {
+		statErr = fmt.Errorf("canary volume %s is not accessible: %w", d.canaryVolume, statErr)
+	}
+
+	if err := d.mounterV2.Unmount(ctx, target); err != nil {
+		return errors.Join(statErr, fmt.Errorf("failed to unmount canary volume %s: %w", d.canaryVolume, err))
+	}
+	if statErr != nil {
+		return statErr
+	}
+
+	llog.Info("node self-test passed")
+	return nil
+}
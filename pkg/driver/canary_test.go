@@ -0,0 +1,84 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"k8s.io/klog/v2"
+)
+
+func TestCanarySelfTest(t *testing.T) {
+	const source = "panfs://realm/canary"
+
+	newDriver := func(t *testing.T, stat func(string) error) (*Driver, *mock.MockPanMounter) {
+		mounter := mock.NewMockPanMounter(gomock.NewController(t))
+		return &Driver{
+			log:          klog.Background(),
+			mounterV2:    mounter,
+			canaryVolume: "realm/canary",
+			mounts:       mountTracker{stat: stat},
+		}, mounter
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		d, _ := newDriver(t, nil)
+		d.canaryVolume = ""
+		assert.NoError(t, d.canarySelfTest())
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		var target string
+		d, mounter := newDriver(t, func(path string) error {
+			target = path
+			return nil
+		})
+		gomock.InOrder(
+			mounter.EXPECT().Mount(gomock.Any(), source, gomock.Any(), []string{"ro"}).Return(nil),
+			mounter.EXPECT().Unmount(gomock.Any(), gomock.Any()).Return(nil),
+		)
+
+		assert.NoError(t, d.canarySelfTest())
+		_, err := os.Stat(target)
+		assert.True(t, os.IsNotExist(err), "canary mount point must be removed")
+	})
+
+	t.Run("MountFailed", func(t *testing.T) {
+		d, mounter := newDriver(t, nil)
+		mounter.EXPECT().Mount(gomock.Any(), source, gomock.Any(), []string{"ro"}).Return(errors.New("unknown filesystem type 'panfs'"))
+
+		assert.ErrorContains(t, d.canarySelfTest(), "unknown filesystem type 'panfs'")
+	})
+
+	t.Run("StatFailed", func(t *testing.T) {
+		d, mounter := newDriver(t, func(string) error { return errors.New("stale file handle") })
+		mounter.EXPECT().Mount(gomock.Any(), source, gomock.Any(), []string{"ro"}).Return(nil)
+		mounter.EXPECT().Unmount(gomock.Any(), gomock.Any()).Return(nil)
+
+		assert.ErrorContains(t, d.canarySelfTest(), "canary volume realm/canary is not accessible: stale file handle")
+	})
+
+	t.Run("RunFails", func(t *testing.T) {
+		d, mounter := newDriver(t, nil)
+		mounter.EXPECT().Mount(gomock.Any(), source, gomock.Any(), []string{"ro"}).Return(errors.New("mount failed"))
+
+		assert.ErrorContains(t, d.Run(t.Context()), "node self-test failed")
+	})
+}
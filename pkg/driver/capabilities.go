@@ -0,0 +1,145 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	csi "github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// featureCapabilities returns the controller capabilities which require a realm feature
+// missing from the feature set.
+func featureCapabilities(features utils.RealmFeatures) []csi.ControllerServiceCapability_RPC_Type {
+	var unsupported []csi.ControllerServiceCapability_RPC_Type
+	if !features.Snapshots {
+		// clones are created from temporary snapshots of the source volume
+		unsupported = append(unsupported,
+			csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+			csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+			csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
+		)
+	}
+	if !features.Capacity {
+		unsupported = append(unsupported, csi.ControllerServiceCapability_RPC_GET_CAPACITY)
+	}
+	return unsupported
+}
+
+// WithCapabilityRealms sets the realms whose common feature set determines the advertised
+// controller capabilities. ControllerGetCapabilities carries no secrets, so the capabilities
+// cannot be negotiated per request; instead capabilities requiring a feature missing on any
+// of the realms, e.g. snapshots, are not advertised. The features are detected by
+// NegotiateCapabilities. Requests to other realms lacking a feature fail with
+// codes.FailedPrecondition.
+//
+// Parameters:
+//
+//	handles - The credential handles of the realms, resolved by the credential provider.
+//	          Surrounding whitespace is trimmed and empty handles are ignored.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithCapabilityRealms(handles []string) Option {
+	return func(d *Driver) {
+		d.capabilityRealms = nil
+		for _, handle := range handles {
+			if handle = strings.TrimSpace(handle); handle != "" {
+				d.capabilityRealms = append(d.capabilityRealms, handle)
+			}
+		}
+	}
+}
+
+// NegotiateCapabilities detects the features of the realms configured with
+// WithCapabilityRealms and restricts the advertised controller capabilities to those
+// supported by all of them. Realms whose features cannot be detected, e.g. because they
+// are unreachable, are logged and do not restrict the capabilities. It is intended to run
+// once at startup, before the driver serves requests.
+//
+// Parameters:
+//
+//	ctx - The context for resolving the realm credentials.
+//
+// Returns:
+//
+//	utils.RealmFeatures - The features common to all detected realms.
+func (d *Driver) NegotiateCapabilities(ctx context.Context) utils.RealmFeatures {
+	common := utils.AllRealmFeatures
+	if len(d.capabilityRealms) == 0 {
+		return common
+	}
+
+	for _, handle := range d.capabilityRealms {
+		secrets, err := d.resolveCredentials(ctx, map[string]string{utils.RealmConnectionContext.CredentialsHandle: handle}, nil)
+		if err != nil {
+			d.log.Error(err, "failed to resolve realm credentials, the realm does not restrict the advertised capabilities", "credentials", handle)
+			continue
+		}
+
+		features, err := d.realm(ctx).GetRealmFeatures(ctx, secrets)
+		if err != nil {
+			d.log.Error(err, "failed to detect realm features, the realm does not restrict the advertised capabilities", "credentials", handle)
+			continue
+		}
+		d.log.Info("detected realm features", "credentials", handle,
+			"realm", secrets[utils.RealmConnectionContext.RealmAddress],
+			"snapshots", features.Snapshots, "capacity", features.Capacity)
+		common = common.Intersect(*features)
+	}
+
+	unsupported := featureCapabilities(common)
+	var advertised []csi.ControllerServiceCapability_RPC_Type
+	for _, capability := range controllerCapabilities {
+		if !containsCapability(unsupported, capability) {
+			advertised = append(advertised, capability)
+		}
+	}
+	d.advertisedCapabilities = advertised
+
+	if len(unsupported) > 0 {
+		d.log.Info("WARNING: controller capabilities not advertised, not all realms support them",
+			"disabled", unsupported, "realms", len(d.capabilityRealms))
+	}
+	d.log.Info("negotiated controller capabilities", "capabilities", advertised)
+	return common
+}
+
+// advertisedControllerCapabilities returns the controller capabilities advertised by
+// ControllerGetCapabilities.
+func (d *Driver) advertisedControllerCapabilities() []csi.ControllerServiceCapability_RPC_Type {
+	capabilities := controllerCapabilities
+	if d.advertisedCapabilities != nil {
+		capabilities = d.advertisedCapabilities
+	}
+	if d.attachmentTracking() {
+		capabilities = append(slices.Clone(capabilities), csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME)
+	}
+	return capabilities
+}
+
+// containsCapability reports whether the capability is in the list.
+func containsCapability(list []csi.ControllerServiceCapability_RPC_Type, capability csi.ControllerServiceCapability_RPC_Type) bool {
+	for _, c := range list {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"errors"
+	"testing"
+
+	csi "github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// newCapabilityTestDriver returns a driver negotiating its capabilities over the realms
+// "csi-panfs/a" and "csi-panfs/b".
+func newCapabilityTestDriver(t *testing.T) (*Driver, *mock.MockStorageProviderClient) {
+	pancliMock := mock.NewMockStorageProviderClient(gomock.NewController(t))
+	d := &Driver{Name: DefaultDriverName, log: klog.Background(), panfs: pancliMock}
+	WithCredentialProvider(&staticCredentials{values: map[string]map[string]string{
+		"csi-panfs/a": {utils.RealmConnectionContext.RealmAddress: "a.example.com"},
+		"csi-panfs/b": {utils.RealmConnectionContext.RealmAddress: "b.example.com"},
+	}}, 0)(d)
+	WithCapabilityRealms([]string{"csi-panfs/a", " csi-panfs/b", ""})(d)
+	return d, pancliMock
+}
+
+// advertisedTypes returns the capability types of the ControllerGetCapabilities response.
+func advertisedTypes(t *testing.T, d *Driver) []csi.ControllerServiceCapability_RPC_Type {
+	resp, err := d.ControllerGetCapabilities(t.Context(), &csi.ControllerGetCapabilitiesRequest{})
+	require.NoError(t, err)
+
+	var types []csi.ControllerServiceCapability_RPC_Type
+	for _, capability := range resp.Capabilities {
+		types = append(types, capability.GetRpc().GetType())
+	}
+	return types
+}
+
+func TestNegotiateCapabilities(t *testing.T) {
+	realmA := map[string]string{utils.RealmConnectionContext.RealmAddress: "a.example.com"}
+	realmB := map[string]string{utils.RealmConnectionContext.RealmAddress: "b.example.com"}
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		d, _ := newSnapshotTestDriver(t)
+		assert.Equal(t, utils.AllRealmFeatures, d.NegotiateCapabilities(t.Context()))
+		assert.Equal(t, controllerCapabilities, advertisedTypes(t, d))
+	})
+
+	t.Run("AllSupported", func(t *testing.T) {
+		d, pancliMock := newCapabilityTestDriver(t)
+		pancliMock.EXPECT().GetRealmFeatures(gomock.Any(), realmA).Return(&utils.RealmFeatures{Snapshots: true, Capacity: true}, nil)
+		pancliMock.EXPECT().GetRealmFeatures(gomock.Any(), realmB).Return(&utils.RealmFeatures{Snapshots: true, Capacity: true}, nil)
+
+		assert.Equal(t, utils.AllRealmFeatures, d.NegotiateCapabilities(t.Context()))
+		assert.Equal(t, controllerCapabilities, advertisedTypes(t, d))
+	})
+
+	t.Run("SnapshotsMissingOnOneRealm", func(t *testing.T) {
+		d, pancliMock := newCapabilityTestDriver(t)
+		pancliMock.EXPECT().GetRealmFeatures(gomock.Any(), realmA).Return(&utils.RealmFeatures{Snapshots: true, Capacity: true}, nil)
+		pancliMock.EXPECT().GetRealmFeatures(gomock.Any(), realmB).Return(&utils.RealmFeatures{Capacity: true}, nil)
+
+		assert.Equal(t, utils.RealmFeatures{Capacity: true}, d.NegotiateCapabilities(t.Context()))
+		types := advertisedTypes(t, d)
+		assert.NotContains(t, types, csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT)
+		assert.NotContains(t, types, csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS)
+		assert.NotContains(t, types, csi.ControllerServiceCapability_RPC_CLONE_VOLUME)
+		assert.Contains(t, types, csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME)
+		assert.Contains(t, types, csi.ControllerServiceCapability_RPC_GET_CAPACITY)
+	})
+
+	t.Run("DetectionFailureIgnored", func(t *testing.T) {
+		d, pancliMock := newCapabilityTestDriver(t)
+		pancliMock.EXPECT().GetRealmFeatures(gomock.Any(), realmA).Return(nil, pancli.ErrorUnavailable)
+		pancliMock.EXPECT().GetRealmFeatures(gomock.Any(), realmB).Return(&utils.RealmFeatures{Snapshots: true}, nil)
+
+		assert.Equal(t, utils.RealmFeatures{Snapshots: true}, d.NegotiateCapabilities(t.Context()))
+		types := advertisedTypes(t, d)
+		assert.NotContains(t, types, csi.ControllerServiceCapability_RPC_GET_CAPACITY)
+		assert.Contains(t, types, csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT)
+	})
+
+	t.Run("UnresolvedCredentialsIgnored", func(t *testing.T) {
+		d, pancliMock := newCapabilityTestDriver(t)
+		WithCapabilityRealms([]string{"csi-panfs/a", "csi-panfs/missing"})(d)
+		pancliMock.EXPECT().GetRealmFeatures(gomock.Any(), realmA).Return(&utils.RealmFeatures{Snapshots: true, Capacity: true}, nil)
+
+		assert.Equal(t, utils.AllRealmFeatures, d.NegotiateCapabilities(t.Context()))
+	})
+}
+
+func TestUnsupportedRealmOperation(t *testing.T) {
+	d, pancliMock := newSnapshotTestDriver(t)
+	pancliMock.EXPECT().CreateSnapshot(gomock.Any(), validVolumeName, "snapshot-1", defaultSecrets).
+		Return(nil, errors.Join(pancli.ErrorInternal, errors.New("Error: unknown command 'snapshot'")))
+
+	_, err := d.CreateSnapshot(t.Context(), &csi.CreateSnapshotRequest{Name: "snapshot-1", SourceVolumeId: validVolumeName, Secrets: defaultSecrets})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
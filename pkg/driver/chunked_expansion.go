@@ -0,0 +1,73 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+)
+
+// WithExpansionStep makes ControllerExpandVolume raise the quota of a volume in steps of at
+// most step bytes, e.g. for realms which time out when a quota grows by tens of TB at once.
+// Each step starts from the current quota of the volume on the realm, so a request which
+// failed or timed out part way resumes on retry. A zero or negative step expands in a
+// single command.
+//
+// Parameters:
+//
+//	step - The maximum quota increase in bytes per realm command.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithExpansionStep(step int64) Option {
+	return func(d *Driver) {
+		d.expansionStep = step
+	}
+}
+
+// expandVolumeInSteps raises the quota of the volume from its current size towards the
+// required size in steps of d.expansionStep, with the last command setting the required size.
+//
+// Parameters:
+//
+//	ctx           - The context of the request, no further steps are started when it is done.
+//	volumeID      - The ID of the volume to expand.
+//	current       - The current size of the volume in bytes as read from the realm.
+//	requiredBytes - The target size in bytes.
+//	secrets       - Secrets for authentication.
+//
+// Returns:
+//
+//	error - Error if a step fails, or the context is done before the target is reached.
+func (d *Driver) expandVolumeInSteps(ctx context.Context, volumeID string, current, requiredBytes int64, secrets map[string]string) error {
+	llog := d.requestLogger(ctx).WithValues("volume_id", volumeID, "target_bytes", requiredBytes)
+
+	for current+d.expansionStep < requiredBytes {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("expansion of volume %s stopped at %d of %d bytes: %w", volumeID, current, requiredBytes, err)
+		}
+
+		next := current + d.expansionStep
+		if err := d.realm(ctx).ExpandVolume(ctx, volumeID, next, secrets); err != nil {
+			return err
+		}
+		llog.Info("expanded volume quota step", "quota_bytes", next,
+			"progress", fmt.Sprintf("%.0f%%", float64(next)*100/float64(requiredBytes)))
+		current = next
+	}
+
+	return d.realm(ctx).ExpandVolume(ctx, volumeID, requiredBytes, secrets)
+}
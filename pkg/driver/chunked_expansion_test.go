@@ -0,0 +1,100 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestControllerExpandVolumeInSteps verifies that large expansions are applied in steps
+// starting from the current quota of the volume.
+func TestControllerExpandVolumeInSteps(t *testing.T) {
+	const gib = int64(1) << 30
+	volume := func(softGiB float64) *utils.Volume {
+		return &utils.Volume{Name: utils.VolumeName(validVolumeName), Soft: softGiB, QuotaUnit: utils.QuotaUnitGiB}
+	}
+	request := func(requiredBytes int64) *csi.ControllerExpandVolumeRequest {
+		return &csi.ControllerExpandVolumeRequest{
+			VolumeId:      validVolumeName,
+			CapacityRange: &csi.CapacityRange{RequiredBytes: requiredBytes},
+			Secrets:       defaultSecrets,
+		}
+	}
+
+	t.Run("Steps", func(t *testing.T) {
+		d, panfs := newSnapshotTestDriver(t)
+		d.expansionStep = 10 * gib
+		gomock.InOrder(
+			panfs.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(5), nil),
+			panfs.EXPECT().ExpandVolume(gomock.Any(), validVolumeName, 15*gib, defaultSecrets).Return(nil),
+			panfs.EXPECT().ExpandVolume(gomock.Any(), validVolumeName, 25*gib, defaultSecrets).Return(nil),
+			panfs.EXPECT().ExpandVolume(gomock.Any(), validVolumeName, 30*gib, defaultSecrets).Return(nil),
+			panfs.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(30), nil),
+		)
+
+		resp, err := d.ControllerExpandVolume(t.Context(), request(30*gib))
+		require.NoError(t, err)
+		assert.Equal(t, 30*gib, resp.CapacityBytes)
+	})
+
+	t.Run("ResumesFromCurrentQuota", func(t *testing.T) {
+		// a previous attempt already applied the first step
+		d, panfs := newSnapshotTestDriver(t)
+		d.expansionStep = 10 * gib
+		gomock.InOrder(
+			panfs.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(25), nil),
+			panfs.EXPECT().ExpandVolume(gomock.Any(), validVolumeName, 30*gib, defaultSecrets).Return(nil),
+			panfs.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(30), nil),
+		)
+
+		_, err := d.ControllerExpandVolume(t.Context(), request(30*gib))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Interrupted", func(t *testing.T) {
+		d, panfs := newSnapshotTestDriver(t)
+		d.expansionStep = 10 * gib
+		ctx, cancel := context.WithCancel(t.Context())
+		gomock.InOrder(
+			panfs.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(5), nil),
+			panfs.EXPECT().ExpandVolume(gomock.Any(), validVolumeName, 15*gib, defaultSecrets).DoAndReturn(func(context.Context, string, int64, map[string]string) error {
+				cancel()
+				return nil
+			}),
+		)
+
+		_, err := d.ControllerExpandVolume(ctx, request(30*gib))
+		assert.Equal(t, codes.Canceled, status.Code(err))
+	})
+
+	t.Run("VolumeNotFound", func(t *testing.T) {
+		d, panfs := newSnapshotTestDriver(t)
+		d.expansionStep = 10 * gib
+		panfs.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(nil, pancli.ErrorNotFound)
+
+		_, err := d.ControllerExpandVolume(t.Context(), request(30*gib))
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
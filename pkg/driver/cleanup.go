@@ -0,0 +1,128 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// DriverOwnerLabelKey is the label put on Kubernetes objects created and owned by the driver.
+// Its value is the name of the CSI driver owning the object.
+const DriverOwnerLabelKey = "panfs.csi.vdura.com/owned-by"
+
+// DriverNodeLabelKey is the label put on driver-owned Kubernetes objects which belong to a node,
+// e.g. attachment records. Its value is the name of the node.
+const DriverNodeLabelKey = "panfs.csi.vdura.com/node"
+
+// CleanupOptions selects the cluster state removed by Cleanup.
+type CleanupOptions struct {
+	// NodeLabels removes the driver readiness label from all nodes.
+	NodeLabels bool
+	// Attachments removes driver-owned ConfigMaps (attachment records) from Namespace.
+	Attachments bool
+	// Namespace is the namespace the driver is installed in.
+	Namespace string
+}
+
+// Cleanup removes the cluster state left behind by the driver. It is intended to run on
+// uninstall, e.g. from a Helm pre-delete hook Job. All selected steps are attempted even
+// if one of them fails.
+//
+// Parameters:
+//
+//	ctx        - The context for the Kubernetes API calls.
+//	kubeClient - The Kubernetes client.
+//	driverName - The name of the CSI driver owning the objects.
+//	opts       - The cleanup steps to run.
+//	log        - The logger instance.
+//
+// Returns:
+//
+//	error - The joined errors of all failed cleanup steps.
+func Cleanup(ctx context.Context, kubeClient kubernetes.Interface, driverName string, opts CleanupOptions, log klog.Logger) error {
+	var errs []error
+
+	if opts.NodeLabels {
+		if err := cleanupNodeLabels(ctx, kubeClient, log); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if opts.Attachments {
+		if opts.Namespace == "" {
+			errs = append(errs, fmt.Errorf("namespace must be provided to clean up attachments"))
+		} else if err := deleteOwnedConfigMaps(ctx, kubeClient, opts.Namespace, ownerSelector(driverName), log); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// cleanupNodeLabels removes the readiness label from all nodes which carry it.
+func cleanupNodeLabels(ctx context.Context, kubeClient kubernetes.Interface, log klog.Logger) error {
+	nodes, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: NodeLabelKey})
+	if err != nil {
+		return fmt.Errorf("failed to list labeled nodes: %w", err)
+	}
+
+	patch := []byte(fmt.Sprintf(`{"metadata":{"labels":{"%s":null}}}`, NodeLabelKey))
+
+	var errs []error
+	for _, node := range nodes.Items {
+		_, err := kubeClient.CoreV1().Nodes().Patch(ctx, node.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to remove label from node %s: %w", node.Name, err))
+			continue
+		}
+		log.Info("removed node label", "label", NodeLabelKey, "node", node.Name)
+	}
+
+	return errors.Join(errs...)
+}
+
+// ownerSelector returns the label selector of the objects owned by the driver.
+func ownerSelector(driverName string) string {
+	return fmt.Sprintf("%s=%s", DriverOwnerLabelKey, driverName)
+}
+
+// deleteOwnedConfigMaps deletes all ConfigMaps in the namespace matching the selector of
+// driver-owned objects.
+func deleteOwnedConfigMaps(ctx context.Context, kubeClient kubernetes.Interface, namespace, selector string, log klog.Logger) error {
+	cms, err := kubeClient.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return fmt.Errorf("failed to list driver-owned config maps: %w", err)
+	}
+
+	var errs []error
+	for _, cm := range cms.Items {
+		err := kubeClient.CoreV1().ConfigMaps(namespace).Delete(ctx, cm.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete config map %s/%s: %w", namespace, cm.Name, err))
+			continue
+		}
+		log.Info("deleted driver-owned config map", "namespace", namespace, "name", cm.Name)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+)
+
+// TestCleanup verifies that Cleanup removes node labels and driver-owned config maps only.
+func TestCleanup(t *testing.T) {
+	newClient := func() *fake.Clientset {
+		return fake.NewClientset(
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "labeled", Labels: map[string]string{NodeLabelKey: "true", "keep": "me"}}},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled", Labels: map[string]string{"keep": "me"}}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owned", Namespace: "csi-panfs", Labels: map[string]string{DriverOwnerLabelKey: DefaultDriverName}}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foreign", Namespace: "csi-panfs"}},
+		)
+	}
+
+	t.Run("NodeLabelsAndAttachments", func(t *testing.T) {
+		client := newClient()
+		err := Cleanup(t.Context(), client, DefaultDriverName, CleanupOptions{NodeLabels: true, Attachments: true, Namespace: "csi-panfs"}, klog.Background())
+		assert.NoError(t, err)
+
+		node, err := client.CoreV1().Nodes().Get(t.Context(), "labeled", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.NotContains(t, node.Labels, NodeLabelKey)
+		assert.Equal(t, "me", node.Labels["keep"])
+
+		cms, err := client.CoreV1().ConfigMaps("csi-panfs").List(t.Context(), metav1.ListOptions{})
+		assert.NoError(t, err)
+		assert.Len(t, cms.Items, 1)
+		assert.Equal(t, "foreign", cms.Items[0].Name)
+	})
+
+	t.Run("NothingSelected", func(t *testing.T) {
+		client := newClient()
+		assert.NoError(t, Cleanup(t.Context(), client, DefaultDriverName, CleanupOptions{}, klog.Background()))
+
+		node, err := client.CoreV1().Nodes().Get(t.Context(), "labeled", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Contains(t, node.Labels, NodeLabelKey)
+	})
+
+	t.Run("AttachmentsWithoutNamespace", func(t *testing.T) {
+		err := Cleanup(t.Context(), newClient(), DefaultDriverName, CleanupOptions{Attachments: true}, klog.Background())
+		assert.ErrorContains(t, err, "namespace must be provided")
+	})
+}
@@ -0,0 +1,264 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// Handling of CSI sidecars of the controller pod which do not meet the expectations of the driver.
+const (
+	// ContractCheckOff skips the check.
+	ContractCheckOff = "off"
+	// ContractCheckWarn logs a warning for each violation and starts the driver.
+	ContractCheckWarn = "warn"
+	// ContractCheckFail refuses to start the driver on violations.
+	ContractCheckFail = "fail"
+)
+
+// MinSidecarTimeout is the shortest --timeout of the sidecars which leaves realm commands
+// enough time to complete. Realm commands still running when the sidecar times out the
+// request are aborted and retried.
+const MinSidecarTimeout = 30 * time.Second
+
+// SidecarContract is what the driver expects of a CSI sidecar container of its pod.
+type SidecarContract struct {
+	// Image is the name of the sidecar image without registry and tag, e.g. "csi-provisioner".
+	Image string
+	// MinVersion is the oldest supported image tag, e.g. "v3.0.0".
+	MinVersion string
+	// Flags are the boolean flags the sidecar must be run with.
+	Flags []string
+	// DefaultTimeout is the timeout of the sidecar without --timeout.
+	DefaultTimeout time.Duration
+}
+
+// ControllerSidecarContracts returns the expectations of the controller to the sidecars of its pod.
+//
+// Parameters:
+//
+//	extraCreateMetadata - Whether the driver reads PVC metadata, e.g. for PVC annotation
+//	                      parameters, namespace policies or the KMIP secret check.
+//
+// Returns:
+//
+//	[]SidecarContract - The contracts of the provisioner, attacher, resizer and snapshotter.
+func ControllerSidecarContracts(extraCreateMetadata bool) []SidecarContract {
+	provisioner := SidecarContract{Image: "csi-provisioner", MinVersion: "v3.0.0", DefaultTimeout: 10 * time.Second}
+	if extraCreateMetadata {
+		provisioner.Flags = []string{"extra-create-metadata"}
+	}
+	return []SidecarContract{
+		provisioner,
+		{Image: "csi-attacher", MinVersion: "v3.0.0", DefaultTimeout: 15 * time.Second},
+		{Image: "csi-resizer", MinVersion: "v1.0.0", DefaultTimeout: 10 * time.Second},
+		{Image: "csi-snapshotter", MinVersion: "v4.0.0", DefaultTimeout: time.Minute},
+	}
+}
+
+// ValidateContractCheck checks that the contract check mode is supported.
+//
+// Parameters:
+//
+//	mode - The contract check mode.
+//
+// Returns:
+//
+//	error - Error if the mode is unknown.
+func ValidateContractCheck(mode string) error {
+	switch mode {
+	case ContractCheckOff, ContractCheckWarn, ContractCheckFail:
+		return nil
+	default:
+		return fmt.Errorf("invalid contract check %q: must be %q, %q or %q", mode, ContractCheckOff, ContractCheckWarn, ContractCheckFail)
+	}
+}
+
+// CheckSidecarContracts verifies at startup that the sidecars of the pod the driver runs in
+// are configured as the driver expects, e.g. that the provisioner passes PVC metadata. In
+// warn mode violations, and pods which cannot be read, are logged; in fail mode they are
+// returned as error.
+//
+// Parameters:
+//
+//	ctx        - The context for the Kubernetes API calls.
+//	kubeClient - The Kubernetes client.
+//	namespace  - The namespace of the pod.
+//	podName    - The name of the pod.
+//	mode       - The contract check mode, see ContractCheckOff, ContractCheckWarn and ContractCheckFail.
+//	contracts  - The expectations to the sidecars.
+//	log        - The logger instance.
+//
+// Returns:
+//
+//	error - Error in fail mode if the pod cannot be read or a contract is violated.
+func CheckSidecarContracts(ctx context.Context, kubeClient kubernetes.Interface, namespace, podName, mode string, contracts []SidecarContract, log klog.Logger) error {
+	if mode == ContractCheckOff {
+		return nil
+	}
+
+	violations, err := sidecarContractViolations(ctx, kubeClient, namespace, podName, contracts)
+	if err != nil {
+		if mode == ContractCheckFail {
+			return err
+		}
+		log.Error(err, "skipping the sidecar contract check")
+		return nil
+	}
+
+	for _, violation := range violations {
+		log.Error(nil, "CSI sidecar contract violated, volume operations may fail or ignore parameters", "pod", podName, "violation", violation)
+	}
+	if len(violations) > 0 && mode == ContractCheckFail {
+		return fmt.Errorf("CSI sidecar contract violated: %s", strings.Join(violations, "; "))
+	}
+	if len(violations) == 0 {
+		log.V(4).Info("CSI sidecars meet the driver contract", "pod", podName)
+	}
+	return nil
+}
+
+// sidecarContractViolations reads the pod and returns the violations of the contracts by its containers.
+func sidecarContractViolations(ctx context.Context, kubeClient kubernetes.Interface, namespace, podName string, contracts []SidecarContract) ([]string, error) {
+	if kubeClient == nil || namespace == "" || podName == "" {
+		return nil, fmt.Errorf("cannot read the driver pod: kube client, POD_NAMESPACE and POD_NAME are required")
+	}
+	pod, err := kubeClient.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the driver pod %s/%s: %w", namespace, podName, err)
+	}
+
+	var violations []string
+	for _, contract := range contracts {
+		container := findSidecar(pod.Spec.Containers, contract.Image)
+		if container == nil {
+			violations = append(violations, fmt.Sprintf("no %s container", contract.Image))
+			continue
+		}
+		violations = append(violations, contract.violations(container)...)
+	}
+	return violations, nil
+}
+
+// findSidecar returns the container running the image, or nil.
+func findSidecar(containers []corev1.Container, image string) *corev1.Container {
+	for i := range containers {
+		if name, _ := splitImage(containers[i].Image); name == image {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+// violations returns the violations of the contract by the container.
+func (c SidecarContract) violations(container *corev1.Container) []string {
+	var violations []string
+	flags := parseContainerFlags(append(append([]string(nil), container.Command...), container.Args...))
+
+	for _, flag := range c.Flags {
+		if enabled, _ := strconv.ParseBool(flags[flag]); !enabled {
+			violations = append(violations, fmt.Sprintf("%s runs without --%s", container.Name, flag))
+		}
+	}
+
+	timeout := c.DefaultTimeout
+	if value, ok := flags["timeout"]; ok {
+		var err error
+		if timeout, err = time.ParseDuration(value); err != nil {
+			violations = append(violations, fmt.Sprintf("%s has an invalid --timeout %q", container.Name, value))
+		}
+	}
+	if timeout > 0 && timeout < MinSidecarTimeout {
+		violations = append(violations, fmt.Sprintf("%s times out requests after %s, realm commands need at least %s", container.Name, timeout, MinSidecarTimeout))
+	}
+
+	if _, tag := splitImage(container.Image); olderVersion(tag, c.MinVersion) {
+		violations = append(violations, fmt.Sprintf("%s runs %s %s, at least %s is required", container.Name, c.Image, tag, c.MinVersion))
+	}
+	return violations
+}
+
+// parseContainerFlags returns the values of the flags of a container command line, with
+// boolean flags without value set to "true".
+func parseContainerFlags(args []string) map[string]string {
+	flags := make(map[string]string)
+	for i, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, value, ok := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !ok {
+			value = "true"
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				value = args[i+1]
+			}
+		}
+		flags[name] = value
+	}
+	return flags
+}
+
+// splitImage returns the name of an image without registry, and its tag, e.g. "csi-provisioner"
+// and "v5.3.0" for "registry.k8s.io/sig-storage/csi-provisioner:v5.3.0".
+func splitImage(image string) (string, string) {
+	image, _, _ = strings.Cut(image, "@")
+	name := image[strings.LastIndex(image, "/")+1:]
+	name, tag, _ := strings.Cut(name, ":")
+	return name, tag
+}
+
+// olderVersion reports whether the "vX.Y.Z" tag is older than the minimum version. Tags which
+// are not semantic versions, e.g. "latest", are not reported.
+func olderVersion(tag, minVersion string) bool {
+	version, ok := parseVersion(tag)
+	if !ok {
+		return false
+	}
+	minimum, _ := parseVersion(minVersion)
+	for i := range version {
+		if version[i] != minimum[i] {
+			return version[i] < minimum[i]
+		}
+	}
+	return false
+}
+
+// parseVersion parses the major, minor and patch numbers of a "vX.Y.Z" tag, ignoring
+// pre-release and build suffixes.
+func parseVersion(tag string) ([3]int, bool) {
+	var version [3]int
+	tag, _, _ = strings.Cut(strings.TrimPrefix(tag, "v"), "-")
+	parts := strings.Split(tag, ".")
+	if len(parts) != 3 {
+		return version, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return version, false
+		}
+		version[i] = n
+	}
+	return version, true
+}
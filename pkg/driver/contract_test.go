@@ -0,0 +1,133 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+)
+
+// newControllerPod returns a controller pod with the sidecars of the Helm chart, with the
+// args of the provisioner replaced.
+func newControllerPod(provisionerArgs ...string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "controller-0", Namespace: "csi-panfs"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "csi-panfs-plugin", Image: "ghcr.io/panasasinc/panfs-csi-driver:1.0.0"},
+			{Name: "csi-provisioner", Image: "registry.k8s.io/sig-storage/csi-provisioner:v5.3.0", Args: provisionerArgs},
+			{Name: "csi-attacher", Image: "registry.k8s.io/sig-storage/csi-attacher:v4.9.0", Args: []string{"--timeout=60s"}},
+			{Name: "csi-resizer", Image: "registry.k8s.io/sig-storage/csi-resizer:v1.13.2", Args: []string{"--timeout", "60s"}},
+			{Name: "csi-snapshotter", Image: "registry.k8s.io/sig-storage/csi-snapshotter:v8.2.0@sha256:0123"},
+		}},
+	}
+}
+
+// TestCheckSidecarContracts verifies the violations reported for the sidecars of the
+// controller pod and the handling of the check modes.
+func TestCheckSidecarContracts(t *testing.T) {
+	contracts := ControllerSidecarContracts(true)
+
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		violations []string
+	}{
+		{
+			name: "Valid",
+			pod:  newControllerPod("--csi-address=$(ADDRESS)", "--timeout=60s", "--extra-create-metadata"),
+		},
+		{
+			name:       "MissingExtraCreateMetadata",
+			pod:        newControllerPod("--timeout=60s"),
+			violations: []string{"csi-provisioner runs without --extra-create-metadata"},
+		},
+		{
+			name:       "DisabledExtraCreateMetadata",
+			pod:        newControllerPod("--timeout=60s", "--extra-create-metadata=false"),
+			violations: []string{"csi-provisioner runs without --extra-create-metadata"},
+		},
+		{
+			name:       "DefaultTimeout",
+			pod:        newControllerPod("--extra-create-metadata"),
+			violations: []string{"csi-provisioner times out requests after 10s, realm commands need at least 30s"},
+		},
+		{
+			name:       "InvalidTimeout",
+			pod:        newControllerPod("--extra-create-metadata", "-timeout=soon"),
+			violations: []string{`csi-provisioner has an invalid --timeout "soon"`},
+		},
+		{
+			name: "OldVersionAndMissingSidecar",
+			pod: func() *corev1.Pod {
+				pod := newControllerPod("--timeout=60s", "--extra-create-metadata")
+				pod.Spec.Containers[1].Image = "k8s.gcr.io/sig-storage/csi-provisioner:v2.2.2"
+				pod.Spec.Containers = pod.Spec.Containers[:4]
+				return pod
+			}(),
+			violations: []string{
+				"csi-provisioner runs csi-provisioner v2.2.2, at least v3.0.0 is required",
+				"no csi-snapshotter container",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewClientset(tc.pod)
+
+			violations, err := sidecarContractViolations(t.Context(), client, "csi-panfs", "controller-0", contracts)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.violations, violations)
+
+			err = CheckSidecarContracts(t.Context(), client, "csi-panfs", "controller-0", ContractCheckWarn, contracts, klog.Background())
+			assert.NoError(t, err)
+
+			err = CheckSidecarContracts(t.Context(), client, "csi-panfs", "controller-0", ContractCheckFail, contracts, klog.Background())
+			if len(tc.violations) > 0 {
+				assert.ErrorContains(t, err, tc.violations[0])
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("WithoutMetadataRequirement", func(t *testing.T) {
+		client := fake.NewClientset(newControllerPod("--timeout=60s"))
+		violations, err := sidecarContractViolations(t.Context(), client, "csi-panfs", "controller-0", ControllerSidecarContracts(false))
+		assert.NoError(t, err)
+		assert.Empty(t, violations)
+	})
+
+	t.Run("PodNotReadable", func(t *testing.T) {
+		client := fake.NewClientset()
+		assert.NoError(t, CheckSidecarContracts(t.Context(), client, "csi-panfs", "controller-0", ContractCheckWarn, contracts, klog.Background()))
+		assert.ErrorContains(t, CheckSidecarContracts(t.Context(), client, "csi-panfs", "controller-0", ContractCheckFail, contracts, klog.Background()), "failed to read the driver pod")
+		assert.ErrorContains(t, CheckSidecarContracts(t.Context(), client, "csi-panfs", "", ContractCheckFail, contracts, klog.Background()), "POD_NAME")
+		assert.NoError(t, CheckSidecarContracts(t.Context(), nil, "", "", ContractCheckOff, contracts, klog.Background()))
+	})
+}
+
+// TestValidateContractCheck verifies the supported contract check modes.
+func TestValidateContractCheck(t *testing.T) {
+	for _, mode := range []string{ContractCheckOff, ContractCheckWarn, ContractCheckFail} {
+		assert.NoError(t, ValidateContractCheck(mode))
+	}
+	assert.ErrorContains(t, ValidateContractCheck("strict"), `invalid contract check "strict"`)
+}
@@ -18,12 +18,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
 	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var (
@@ -42,9 +47,27 @@ var (
 		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
 		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
 		csi.ControllerServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
+		csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+		csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
+		csi.ControllerServiceCapability_RPC_LIST_VOLUMES,
+		csi.ControllerServiceCapability_RPC_GET_CAPACITY,
+		csi.ControllerServiceCapability_RPC_MODIFY_VOLUME,
 	}
 )
 
+// CloneSnapshotPrefix prefixes the names of the temporary snapshots volumes are cloned from.
+// The snapshots are deleted once the clone is created and are not listed by ListSnapshots.
+const CloneSnapshotPrefix = "csi-clone-"
+
+// QuotaLimitExceededReason is the reason of the ErrorInfo detail of ControllerExpandVolume
+// errors for capacities exceeding the limits of the realm.
+const QuotaLimitExceededReason = "QUOTA_LIMIT_EXCEEDED"
+
+// QuotaBelowMinimumReason is the reason of the ErrorInfo detail of CreateVolume errors for
+// capacities below the minimum volume size of the realm.
+const QuotaBelowMinimumReason = "QUOTA_BELOW_MINIMUM"
+
 // Error definition strings
 var (
 	InvalidRequestErrorStr               = "Invalid request"
@@ -71,11 +94,33 @@ var (
 //	        or if volume creation encounters an internal error.
 //
 // Error Cases:
-//   - codes.InvalidArgument: If the request, capabilities, or secrets are invalid.
+//   - codes.InvalidArgument: If the request, capabilities, secrets, or mutable parameters (see
+//     ControllerModifyVolume) are invalid, or if a volume provisioned as a directory of a parent
+//     volume (parentVolume parameter) has a content source or mutable parameters.
+//   - codes.PermissionDenied: If the volume parameters are restricted to other namespaces
+//     than the one of the PVC (see WithNamespacePolicy).
 //   - codes.Internal: For unexpected internal errors during volume creation or verification.
-//   - codes.AlreadyExists: If the volume already exists but does not match requested capabilities.
+//   - codes.AlreadyExists: If the volume already exists but does not match requested capabilities
+//     or encryption mode.
+//   - codes.FailedPrecondition, codes.Internal: If the realm created the volume with a different
+//     encryption mode than requested (see WithEncryptionMismatchPolicy).
+//   - codes.FailedPrecondition: If encryption is requested but the storage class has no usable
+//     node-publish KMIP secret (see WithKMIPSecretCheck).
+//   - codes.FailedPrecondition: If the parent volume of the parentVolume parameter does not exist.
+//   - codes.Unavailable: If all session slots of the realm stay busy (see WithRealmConcurrencyLimit).
+//   - codes.ResourceExhausted: If the realm is not reachable from any of the topologies of the
+//     accessibility requirements (see WithTopology).
+//   - codes.NotFound: If the snapshot or volume of the volume content source does not exist.
+//   - codes.OutOfRange: If the capacity range is smaller than the snapshotted or cloned volume,
+//     exceeds the limits of the realm, or is below the minimum volume size of the storage class
+//     (minCapacity) or the realm and cannot be rounded up to it (roundUpCapacity).
+//   - codes.Canceled, codes.DeadlineExceeded: If the request ends while the realm command runs,
+//     the command is aborted.
+//   - codes.Aborted: If another CreateVolume, DeleteVolume, ControllerExpandVolume or
+//     ControllerModifyVolume request on the volume is in progress, or if a volume with the same
+//     name is being created or deleted on the realm.
 func (d *Driver) CreateVolume(ctx context.Context, in *csi.CreateVolumeRequest) (*csi.CreateVolumeResponse, error) {
-	llog := d.log.WithValues("method", "CreateVolume")
+	llog := d.requestLogger(ctx).WithValues("method", "CreateVolume")
 	llog.V(2).Info("CreateVolume called",
 		"volume_name", in.Name,
 		"capacity_range", in.CapacityRange,
@@ -90,78 +135,294 @@ func (d *Driver) CreateVolume(ctx context.Context, in *csi.CreateVolumeRequest)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	// the volume id is the name of the realm volume, requests with invalid names are mapped to valid ones
+	volumeName := d.volumeNames.RealmName(in.GetName())
+	if volumeName != in.GetName() {
+		llog.Info("volume name mapped to a valid realm volume name", "volume_name", in.GetName(), "volume_id", volumeName)
+	}
+
+	unlock, err := d.volumeLocks.tryLock(volumeName, "create")
+	if err != nil {
+		llog.Error(err, "volume operation in progress")
+		return nil, err
+	}
+	defer unlock()
+
+	// parameters set by PVC annotations are validated like storage class parameters
+	requestParameters, err := d.pvcParameters(ctx, in.GetParameters())
+	if err != nil {
+		llog.Error(err, "failed to read PVC annotations")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err := validateVolumeParameters(requestParameters); err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	mutableParameters, err := mutableVolumeParameters(in.GetMutableParameters())
+	if err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := d.namespacePolicy.check(in.GetParameters()[PVCNamespaceParameterKey], requestParameters); err != nil {
+		llog.Error(err, "volume parameters are not allowed in the PVC namespace")
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	if err := d.checkKMIPSecret(ctx, in.GetName(), requestParameters); err != nil {
+		if d.kmipSecretCheck == KMIPSecretCheckFail {
+			llog.Error(err, "encrypted volume cannot be mounted")
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		llog.Info("WARNING: encrypted volume cannot be mounted", "volume_name", in.GetName(), "reason", err.Error())
+	}
+
 	if err := d.validateVolumeCapabilities(in.GetVolumeCapabilities()); err != nil {
 		llog.Error(err, VolumeCapabilitiesUnsuportedErrorStr, "capabilities", in.VolumeCapabilities)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	secrets := in.GetSecrets()
-	if err := validateReqSecrets(secrets); err != nil {
+	secrets, err := d.resolveCredentials(ctx, in.GetSecrets(), in.GetParameters())
+	if err != nil {
+		llog.Error(err, "failed to resolve realm credentials")
+		return nil, err
+	}
+	if _, err := d.parseRealmSecrets(secrets); err != nil {
 		llog.Error(err, InvalidRequestSecretsErrorStr)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	volumeName := in.GetName()
-	parameters := in.GetParameters()
-	if parameters == nil {
-		parameters = make(map[string]string)
+	// the volume is only accessible from the nodes reaching its realm
+	topology, err := d.accessibleTopology(in.GetAccessibilityRequirements(), secrets)
+	if err != nil {
+		llog.Error(err, "volume cannot be provisioned in the requested topology")
+		return nil, err
+	}
+
+	release, err := d.realmLimiter.acquire(ctx, secrets[utils.RealmConnectionContext.RealmAddress], priorityForeground)
+	if err != nil {
+		llog.Error(err, "realm session slots are busy")
+		return nil, err
+	}
+	defer release()
+
+	// the mount profile is applied by the node plugin, make sure it exists before creating the volume
+	if _, err := d.mountProfileOptions(requestParameters); err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if parentVolume := requestParameters[utils.VolumeParameters.GetSCKey("parentVolume")]; parentVolume != "" {
+		return d.createDirectoryVolume(ctx, in, parentVolume, requestParameters, topology, secrets)
 	}
 
 	// handle capacity range
 	cr := in.GetCapacityRange()
-	soft, hard := int64(0), int64(0)
 
-	if cr != nil {
-		soft = cr.GetRequiredBytes()
-		hard = cr.GetLimitBytes()
+	var snapshot *utils.Snapshot
+	if source := in.GetVolumeContentSource().GetSnapshot(); source != nil {
+		snapshot, cr, err = d.snapshotSource(ctx, source.GetSnapshotId(), cr, secrets)
+		if err != nil {
+			llog.Error(err, "invalid volume content source", "snapshot_id", source.GetSnapshotId())
+			return nil, err
+		}
+	}
+	if source := in.GetVolumeContentSource().GetVolume(); source != nil {
+		snapshot, cr, err = d.cloneSource(ctx, volumeName, source.GetVolumeId(), cr, secrets)
+		if err != nil {
+			llog.Error(err, "invalid volume content source", "source_volume_id", source.GetVolumeId())
+			return nil, err
+		}
+		// the snapshot only carries the content of the source volume into the clone
+		defer d.deleteCloneSnapshot(ctx, snapshot, secrets)
+	}
+
+	// the minimum volume size of the storage class
+	cr, err = minCapacityRange(cr, requestParameters)
+	if err != nil {
+		llog.Error(err, InvalidCapacityRangeErrorStr)
+		return nil, err
 	}
 
-	parameters[utils.VolumeParameters.GetSCKey("soft")] = fmt.Sprintf("%d", soft)
-	parameters[utils.VolumeParameters.GetSCKey("hard")] = fmt.Sprintf("%d", hard)
+	// the parameters of the VolumeAttributesClass of the PVC override the storage class
+	requestParameters, cr = withMutableParameters(requestParameters, cr, mutableParameters)
+	// the description, user and group must fit the limits of the realm version, keeping room
+	// for the tags appended to the description
+	reserved := descriptionTokenLength + descriptionTagsLength(requestParameters[utils.VolumeParameters.GetSCKey("protectionTier")])
+	requestParameters, err = limitStringParameters(requestParameters, secrets[utils.RealmConnectionContext.RealmVersion], reserved)
+	if err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// the protection tier is kept by the realm in the description of the volume
+	requestParameters = withProtectionTier(requestParameters)
 
-	vol, err := d.panfs.CreateVolume(volumeName, parameters, secrets)
+	buildParameters := func(cr *csi.CapacityRange) (pancli.VolumeCreateParams, error) {
+		return pancli.NewVolumeCreateParamsBuilder().
+			SetParameters(requestParameters).
+			SetSoftBytes(cr.GetRequiredBytes()).
+			SetHardBytes(cr.GetLimitBytes()).
+			Build()
+	}
+	create := func(parameters pancli.VolumeCreateParams) (*utils.Volume, error) {
+		if snapshot != nil {
+			return d.realm(ctx).CreateVolumeFromSnapshot(ctx, volumeName, string(snapshot.VolumeName), snapshot.Name, parameters, secrets)
+		}
+		return d.realm(ctx).CreateVolume(ctx, volumeName, parameters, secrets)
+	}
+
+	parameters, err := buildParameters(cr)
+	if err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	vol, err := create(parameters)
+	// the minimum volume size of the realm
+	if rounded, ok := realmMinimumCapacityRange(err, cr, requestParameters); ok {
+		if parameters, buildErr := buildParameters(rounded); buildErr == nil {
+			llog.Info("requested capacity is below the minimum volume size of the realm, rounding it up", "volume_id", volumeName, "required_bytes", cr.GetRequiredBytes(), "min_bytes", rounded.GetRequiredBytes())
+			cr = rounded
+			vol, err = create(parameters)
+		}
+	}
 	if err != nil {
 		// if error happens and it is not ErrorAlreadyExist, we return error
 		if !errors.Is(err, pancli.ErrorAlreadyExist) {
-			d.log.Error(err, "failed to create volume", "volume_id", volumeName)
+			llog.Error(err, "failed to create volume", "volume_id", volumeName)
+			if abortErr := abortedError(err); abortErr != nil {
+				return nil, abortErr
+			}
+			if errors.Is(err, pancli.ErrorUnauthenticated) {
+				return nil, status.Error(codes.Unauthenticated, err.Error())
+			}
+			if snapshot != nil && errors.Is(err, pancli.ErrorNotFound) {
+				// the snapshot was deleted since it was read
+				return nil, status.Error(codes.NotFound, err.Error())
+			}
+			if errors.Is(err, pancli.ErrorOutOfRange) {
+				return nil, d.quotaLimitError(err)
+			}
 			return nil, status.Error(codes.Internal, UnexpectedErrorInternalStr)
 		}
 
 		// this is ErrorAlreadyExist error - need to check volume matches capabilities
-		vol, err := d.panfs.GetVolume(volumeName, secrets)
+		vol, err := d.realm(ctx).GetVolume(ctx, volumeName, secrets)
 		if err != nil || vol == nil {
 			llog.Error(err, "volume already exists but failed to verify capabilities", "volume_id", volumeName)
 			return nil, status.Error(codes.Internal, UnexpectedErrorInternalStr)
 		}
 
+		// the quotas of a volume in transition are stale, a volume being deleted vanishes once
+		// the deletion completes
+		if vol.State.IsTransient() {
+			llog.Info("volume with the same name is in transition", "volume_id", volumeName, "state", vol.State)
+			return nil, status.Error(codes.Aborted, transientVolumeMessage(vol.State))
+		}
+
+		capacity := vol.GetSoftQuotaBytes()
+
 		// if volume is not match requested capabilities
-		if err := validateVolumeCapacity(in.GetCapacityRange(), vol); err != nil {
-			llog.Error(err, "volume already exists, but the capacity does not match", "volume_id", volumeName)
-			return nil, status.Error(codes.AlreadyExists, "Volume capacity does not match: "+err.Error())
+		if err := validateVolumeCapacity(cr, vol); err != nil {
+			if requestParameters[utils.VolumeParameters.GetSCKey("reconcileCapacity")] != ReconcileCapacityExpand || !expandableVolume(cr, vol) {
+				llog.Error(err, "volume already exists, but the capacity does not match", "volume_id", volumeName)
+				return nil, status.Error(codes.AlreadyExists, "Volume capacity does not match: "+err.Error())
+			}
+
+			// the volume was likely left behind by a partially failed request, expand it to the requested size
+			llog.Info("volume already exists with lower capacity, expanding it", "volume_id", volumeName, "capacity", capacity, "required_bytes", cr.GetRequiredBytes())
+			if err := d.realm(ctx).ExpandVolume(ctx, volumeName, cr.GetRequiredBytes(), secrets); err != nil {
+				llog.Error(err, "failed to expand existing volume", "volume_id", volumeName)
+				if errors.Is(err, pancli.ErrorUnauthenticated) {
+					return nil, status.Error(codes.Unauthenticated, err.Error())
+				}
+				return nil, status.Error(codes.Internal, UnexpectedErrorInternalStr)
+			}
+			capacity = cr.GetRequiredBytes()
+		}
+
+		if err := encryptionMismatch(requestParameters, vol); err != nil {
+			llog.Error(err, "volume already exists, but the encryption mode does not match", "volume_id", volumeName)
+			return nil, status.Error(codes.AlreadyExists, "Volume encryption does not match: "+err.Error())
 		}
 
 		// existing volume matches requested capabilities - return OK with existing volume info
-		llog.Info("volume already exists", "volume_name", volumeName, "capacity", vol.GetSoftQuotaBytes(), "encryption", vol.GetEncryptionMode())
+		llog.Info("volume already exists", "volume_name", volumeName, "capacity", capacity, "encryption", vol.GetEncryptionMode())
 		return &csi.CreateVolumeResponse{
 			Volume: &csi.Volume{
-				CapacityBytes: vol.GetSoftQuotaBytes(),
-				VolumeId:      volumeName,
-				VolumeContext: vol.VolumeContext(),
+				CapacityBytes:      capacity,
+				VolumeId:           volumeName,
+				VolumeContext:      d.volumeContext(vol, volumeNameParameters(requestParameters, in.GetName(), volumeName)),
+				ContentSource:      in.GetVolumeContentSource(),
+				AccessibleTopology: topology,
 			},
 		}, nil
 	}
 
+	if err := encryptionMismatch(requestParameters, vol); err != nil {
+		llog.Error(err, "realm created the volume with a different encryption mode", "volume_id", volumeName, "policy", d.encryptionMismatchPolicy)
+		if d.encryptionMismatchPolicy == EncryptionMismatchFail {
+			return nil, status.Error(codes.FailedPrecondition, "Volume encryption does not match: "+err.Error())
+		}
+		if deleteErr := d.realm(ctx).DeleteVolume(ctx, volumeName, secrets); deleteErr != nil {
+			llog.Error(deleteErr, "failed to delete volume with mismatching encryption mode", "volume_id", volumeName)
+		}
+		return nil, status.Error(codes.Internal, "Volume encryption does not match, the volume was deleted: "+err.Error())
+	}
+
 	llog.Info("volume created", "volume_name", volumeName, "capacity", vol.GetSoftQuotaBytes(), "encryption", vol.GetEncryptionMode())
 
 	return &csi.CreateVolumeResponse{
 		Volume: &csi.Volume{
-			CapacityBytes: vol.GetSoftQuotaBytes(),
-			VolumeId:      volumeName,
-			VolumeContext: vol.VolumeContext(),
+			CapacityBytes:      vol.GetSoftQuotaBytes(),
+			VolumeId:           volumeName,
+			VolumeContext:      d.volumeContext(vol, volumeNameParameters(requestParameters, in.GetName(), volumeName)),
+			ContentSource:      in.GetVolumeContentSource(),
+			AccessibleTopology: topology,
 		},
 	}, nil
 }
 
+// transientVolumeMessage returns the message of the error returned for an existing volume
+// in a transitional state, asking the caller to retry.
+//
+// Parameters:
+//
+//	state - The transitional state of the existing volume.
+//
+// Returns:
+//
+//	string - The error message.
+func transientVolumeMessage(state utils.VolumeState) string {
+	if state == utils.VolumeStateDeleting {
+		return "volume with same name is being deleted, retry later"
+	}
+	return "volume with same name is being created, retry later"
+}
+
+// expandableVolume reports whether the capacity of an existing volume differs from the requested
+// capacity range only by a lower soft quota, which can be resolved by expanding the volume.
+//
+// Parameters:
+//
+//	capacity - The requested capacity range for the volume.
+//	vol      - The existing volume.
+//
+// Returns:
+//
+//	bool - True if raising the soft quota to the required bytes satisfies the request.
+func expandableVolume(capacity *csi.CapacityRange, vol *utils.Volume) bool {
+	required := capacity.GetRequiredBytes()
+	if required <= vol.GetSoftQuotaBytes() {
+		return false
+	}
+	if vol.Hard > 0 && required > vol.GetHardQuotaBytes() {
+		return false
+	}
+	limit := capacity.GetLimitBytes()
+	return limit == 0 || limit == vol.GetHardQuotaBytes()
+}
+
 // DeleteVolume handles the CSI DeleteVolume request.
 //
 // Parameters:
@@ -176,9 +437,15 @@ func (d *Driver) CreateVolume(ctx context.Context, in *csi.CreateVolumeRequest)
 //
 // Error Cases:
 //   - codes.InvalidArgument: If the volume ID or secrets are invalid.
-//   - codes.Internal: For unexpected internal errors during volume deletion.
+//   - codes.Internal: For unexpected internal errors during volume deletion, or if the volume
+//     still exists after deletion when verification is enabled (see WithDeleteVerification).
+//   - codes.Unavailable: If all session slots of the realm stay busy (see WithRealmConcurrencyLimit).
+//   - codes.Canceled, codes.DeadlineExceeded: If the request ends while the realm command runs,
+//     the command is aborted.
+//   - codes.Aborted: If another CreateVolume, DeleteVolume, ControllerExpandVolume or
+//     ControllerModifyVolume request on the volume is in progress.
 func (d *Driver) DeleteVolume(ctx context.Context, in *csi.DeleteVolumeRequest) (*csi.DeleteVolumeResponse, error) {
-	llog := d.log.WithValues("method", "DeleteVolume")
+	llog := d.requestLogger(ctx).WithValues("method", "DeleteVolume")
 	llog.V(2).Info("DeleteVolume called", "volume_id", in.VolumeId)
 
 	volumeID := in.GetVolumeId()
@@ -187,52 +454,58 @@ func (d *Driver) DeleteVolume(ctx context.Context, in *csi.DeleteVolumeRequest)
 		return nil, status.Error(codes.InvalidArgument, "volume id must be provided")
 	}
 
-	secrets := in.GetSecrets()
-	if err := validateReqSecrets(secrets); err != nil {
+	unlock, err := d.volumeLocks.tryLock(volumeID, "delete")
+	if err != nil {
+		llog.Error(err, "volume operation in progress")
+		return nil, err
+	}
+	defer unlock()
+
+	secrets, err := d.resolveCredentials(ctx, in.GetSecrets(), nil)
+	if err != nil {
+		llog.Error(err, "failed to resolve realm credentials")
+		return nil, err
+	}
+	if _, err := d.parseRealmSecrets(secrets); err != nil {
 		llog.Error(err, InvalidRequestSecretsErrorStr)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	err := d.panfs.DeleteVolume(volumeID, secrets)
+	release, err := d.realmLimiter.acquire(ctx, secrets[utils.RealmConnectionContext.RealmAddress], priorityForeground)
+	if err != nil {
+		llog.Error(err, "realm session slots are busy")
+		return nil, err
+	}
+	defer release()
+
+	parentVolume, directory, isDirectory := utils.ParseDirectoryVolumeID(volumeID)
+	if isDirectory {
+		err = d.realm(ctx).DeleteDirectory(ctx, parentVolume, directory, secrets)
+	} else {
+		err = d.realm(ctx).DeleteVolume(ctx, volumeID, secrets)
+	}
 	// If volume does not exist, we return OK status
 	if err != nil && !errors.Is(err, pancli.ErrorNotFound) {
 		llog.Error(err, "failed to delete volume", "volume_id", volumeID)
+		if abortErr := abortedError(err); abortErr != nil {
+			return nil, abortErr
+		}
+		if errors.Is(err, pancli.ErrorUnauthenticated) {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
 		return nil, status.Error(codes.Internal, UnexpectedErrorInternalStr)
 	}
-	llog.Info("volume deleted", "volume_id", volumeID)
-	return &csi.DeleteVolumeResponse{}, nil
-}
 
-// ControllerPublishVolume handles the CSI ControllerPublishVolume request (unimplemented).
-//
-// Parameters:
-//
-//	ctx - The context for the request.
-//	in  - The ControllerPublishVolumeRequest.
-//
-// Returns:
-//
-//	*csi.ControllerPublishVolumeResponse - Always nil.
-//	error - Always returns codes.Unimplemented.
-func (d *Driver) ControllerPublishVolume(ctx context.Context, in *csi.ControllerPublishVolumeRequest) (*csi.ControllerPublishVolumeResponse, error) {
-	d.log.V(2).Info("ControllerPublishVolume called")
-	return nil, status.Error(codes.Unimplemented, "")
-}
+	// the realm may report success while the volume still exists, keep the PV until it is gone
+	if err == nil && d.deleteVerifyAttempts > 0 && !isDirectory {
+		if err := d.verifyVolumeDeleted(ctx, volumeID, secrets); err != nil {
+			llog.Error(err, "volume deletion not confirmed", "volume_id", volumeID)
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
 
-// ControllerUnpublishVolume handles the CSI ControllerUnpublishVolume request (unimplemented).
-//
-// Parameters:
-//
-//	ctx - The context for the request.
-//	in  - The ControllerUnpublishVolumeRequest.
-//
-// Returns:
-//
-//	*csi.ControllerUnpublishVolumeResponse - Always nil.
-//	error - Always returns codes.Unimplemented.
-func (d *Driver) ControllerUnpublishVolume(ctx context.Context, in *csi.ControllerUnpublishVolumeRequest) (*csi.ControllerUnpublishVolumeResponse, error) {
-	d.log.V(2).Info("ControllerUnpublishVolume called")
-	return nil, status.Error(codes.Unimplemented, "")
+	llog.Info("volume deleted", "volume_id", volumeID)
+	return &csi.DeleteVolumeResponse{}, nil
 }
 
 // validateVolumeCapabilities checks if all provided volume capabilities are supported.
@@ -285,8 +558,9 @@ func (d *Driver) isSupportedCapability(capability *csi.VolumeCapability) bool {
 //   - codes.InvalidArgument: If the volume ID, capabilities, or secrets are invalid.
 //   - codes.NotFound: If the volume does not exist.
 //   - codes.Internal: For unexpected internal errors during validation.
+//   - codes.Unavailable: If all session slots of the realm stay busy (see WithRealmConcurrencyLimit).
 func (d *Driver) ValidateVolumeCapabilities(ctx context.Context, in *csi.ValidateVolumeCapabilitiesRequest) (*csi.ValidateVolumeCapabilitiesResponse, error) {
-	llog := d.log.WithValues("method", "ValidateVolumeCapabilities")
+	llog := d.requestLogger(ctx).WithValues("method", "ValidateVolumeCapabilities")
 	llog.V(2).Info("ValidateVolumeCapabilities called",
 		"volume_id", in.VolumeId,
 		"capabilities", in.VolumeCapabilities,
@@ -306,22 +580,40 @@ func (d *Driver) ValidateVolumeCapabilities(ctx context.Context, in *csi.Validat
 		return nil, status.Error(codes.InvalidArgument, "volume capabilities must be provided")
 	}
 
-	secrets := in.GetSecrets()
-	if err := validateReqSecrets(secrets); err != nil {
+	secrets, err := d.resolveCredentials(ctx, in.GetSecrets(), in.GetParameters())
+	if err != nil {
+		llog.Error(err, "failed to resolve realm credentials")
+		return nil, err
+	}
+	if _, err := d.parseRealmSecrets(secrets); err != nil {
 		llog.Error(err, InvalidRequestSecretsErrorStr)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	release, err := d.realmLimiter.acquire(ctx, secrets[utils.RealmConnectionContext.RealmAddress], priorityForeground)
+	if err != nil {
+		llog.Error(err, "realm session slots are busy")
+		return nil, err
+	}
+	defer release()
+
 	if err := d.validateVolumeCapabilities(capabilitiesRequested); err != nil {
 		llog.Error(err, VolumeCapabilitiesDoNotMatchErrorStr, "volume_id", volumeID)
 		return nil, status.Error(codes.InvalidArgument, VolumeCapabilitiesDoNotMatchErrorStr)
 	}
 
-	_, err := d.panfs.GetVolume(volumeID, secrets)
+	if parentVolume, directory, ok := utils.ParseDirectoryVolumeID(volumeID); ok {
+		_, err = d.realm(ctx).GetDirectory(ctx, parentVolume, directory, secrets)
+	} else {
+		_, err = d.realm(ctx).GetVolume(ctx, volumeID, secrets)
+	}
 	if err != nil {
 		switch {
 		case errors.Is(err, pancli.ErrorNotFound):
 			return nil, status.Error(codes.NotFound, VolumeNotFoundErrorStr)
+		case errors.Is(err, pancli.ErrorUnauthenticated):
+			llog.Error(err, "failed to get volume", "volume_id", volumeID)
+			return nil, status.Error(codes.Unauthenticated, err.Error())
 		default:
 			llog.Error(err, "failed to get volume", "volume_id", volumeID)
 			return nil, status.Error(codes.Internal, err.Error())
@@ -335,23 +627,91 @@ func (d *Driver) ValidateVolumeCapabilities(ctx context.Context, in *csi.Validat
 	}, nil
 }
 
-// ListVolumes handles the CSI ListVolumes request (unimplemented).
+// ListVolumes handles the CSI ListVolumes request. The request carries no secrets, the realm
+// is accessed with the default credentials (see WithDefaultCredentials). Volumes are listed
+// ordered by ID, the starting token is the index of the first returned entry. The volume
+// context of an entry holds the protection tier of the volume, if any.
 //
 // Parameters:
 //
 //	ctx - The context for the request.
-//	in  - The ListVolumesRequest.
+//	in  - The ListVolumesRequest with the pagination of the response.
 //
 // Returns:
 //
-//	*csi.ListVolumesResponse - Always nil.
-//	error - Always returns codes.Unimplemented.
+//	*csi.ListVolumesResponse - The response containing the volumes of the realm.
+//	error - Returns an error if the volumes cannot be listed.
+//
+// Error Cases:
+//   - codes.FailedPrecondition: If no default credentials are configured.
+//   - codes.InvalidArgument: If max_entries is negative, or the default credentials cannot be
+//     resolved or are invalid.
+//   - codes.Aborted: If the starting token is invalid.
+//   - codes.Unauthenticated: If the realm rejects the credentials.
+//   - codes.Unavailable: If the realm cannot be reached or all session slots of the realm stay
+//     busy (see WithRealmConcurrencyLimit).
+//   - codes.Internal: For unexpected internal errors while listing volumes.
 func (d *Driver) ListVolumes(ctx context.Context, in *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
-	d.log.V(2).Info("ListVolumes called",
+	llog := d.requestLogger(ctx).WithValues("method", "ListVolumes")
+	llog.V(2).Info("ListVolumes called",
 		"max_entries", in.MaxEntries,
 		"starting_token", in.StartingToken,
 	)
-	return nil, status.Error(codes.Unimplemented, "")
+
+	if in.GetMaxEntries() < 0 {
+		err := fmt.Errorf("max_entries must not be negative, got %d", in.GetMaxEntries())
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	secrets, err := d.defaultSecrets(ctx, nil)
+	if err != nil {
+		llog.Error(err, "failed to resolve realm credentials")
+		return nil, err
+	}
+	if _, err := d.parseRealmSecrets(secrets); err != nil {
+		llog.Error(err, InvalidRequestSecretsErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	release, err := d.realmLimiter.acquire(ctx, secrets[utils.RealmConnectionContext.RealmAddress], priorityBackground)
+	if err != nil {
+		llog.Error(err, "realm session slots are busy")
+		return nil, err
+	}
+	defer release()
+
+	list, err := d.realm(ctx).ListVolumes(ctx, secrets)
+	if err != nil {
+		llog.Error(err, "failed to list volumes")
+		return nil, realmError(err)
+	}
+
+	entries := make([]*csi.ListVolumesResponse_Entry, 0, len(list.Volumes))
+	for i := range list.Volumes {
+		vol := &list.Volumes[i]
+		entries = append(entries, &csi.ListVolumesResponse_Entry{
+			Volume: &csi.Volume{
+				VolumeId:      string(vol.Name),
+				CapacityBytes: vol.GetSoftQuotaBytes(),
+				VolumeContext: listVolumeContext(vol),
+			},
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Volume.VolumeId < entries[j].Volume.VolumeId
+	})
+
+	entries, nextToken, err := paginate(entries, in.GetStartingToken(), in.GetMaxEntries())
+	if err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+
+	return &csi.ListVolumesResponse{
+		Entries:   entries,
+		NextToken: nextToken,
+	}, nil
 }
 
 // ControllerGetVolume handles the CSI ControllerGetVolume request (unimplemented).
@@ -372,27 +732,83 @@ func (d *Driver) ControllerGetVolume(ctx context.Context, in *csi.ControllerGetV
 	return nil, status.Error(codes.Unimplemented, "")
 }
 
-// GetCapacity handles the CSI GetCapacity request (unimplemented).
+// GetCapacity handles the CSI GetCapacity request. The capacity is the free space of the
+// bladeset in the storage class parameters, or of all bladesets of the realm. The request
+// carries no secrets, the realm is accessed with the credentials referenced by the storage
+// class parameters or the default credentials (see WithDefaultCredentials).
 //
 // Parameters:
 //
 //	ctx - The context for the request.
-//	in  - The GetCapacityRequest.
+//	in  - The GetCapacityRequest with the volume capabilities and storage class parameters.
 //
 // Returns:
 //
-//	*csi.GetCapacityResponse - Always nil.
-//	error - Always returns codes.Unimplemented.
+//	*csi.GetCapacityResponse - The response containing the available capacity, 0 if volumes
+//	                           with the capabilities or in the bladeset cannot be created.
+//	error - Returns an error if the capacity cannot be read.
+//
+// Error Cases:
+//   - codes.FailedPrecondition: If no credentials are referenced by the parameters and no default
+//     credentials are configured.
+//   - codes.InvalidArgument: If the credentials cannot be resolved or are invalid.
+//   - codes.Unauthenticated: If the realm rejects the credentials.
+//   - codes.Unavailable: If the realm cannot be reached or all session slots of the realm stay
+//     busy (see WithRealmConcurrencyLimit).
+//   - codes.Internal: For unexpected internal errors while reading the capacity.
 func (d *Driver) GetCapacity(ctx context.Context, in *csi.GetCapacityRequest) (*csi.GetCapacityResponse, error) {
-	d.log.V(2).Info("GetCapacity called",
+	llog := d.requestLogger(ctx).WithValues("method", "GetCapacity")
+	llog.V(2).Info("GetCapacity called",
 		"volume_capabilities", in.VolumeCapabilities,
 		"parameters", in.Parameters,
 		"accessible_topology", in.AccessibleTopology,
 	)
-	return nil, status.Error(codes.Unimplemented, "")
+
+	if caps := in.GetVolumeCapabilities(); len(caps) > 0 {
+		if err := d.validateVolumeCapabilities(caps); err != nil {
+			// no volume with the capabilities can be created
+			llog.V(4).Info("volume capabilities are not supported", "reason", err.Error())
+			return &csi.GetCapacityResponse{}, nil
+		}
+	}
+
+	secrets, err := d.defaultSecrets(ctx, in.GetParameters())
+	if err != nil {
+		llog.Error(err, "failed to resolve realm credentials")
+		return nil, err
+	}
+	if _, err := d.parseRealmSecrets(secrets); err != nil {
+		llog.Error(err, InvalidRequestSecretsErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	release, err := d.realmLimiter.acquire(ctx, secrets[utils.RealmConnectionContext.RealmAddress], priorityBackground)
+	if err != nil {
+		llog.Error(err, "realm session slots are busy")
+		return nil, err
+	}
+	defer release()
+
+	bladeset := in.GetParameters()[utils.VolumeParameters.GetSCKey("bladeset")]
+	available, err := d.realm(ctx).GetCapacity(ctx, bladeset, secrets)
+	if errors.Is(err, pancli.ErrorNotFound) {
+		// no volume can be created in a bladeset which does not exist
+		llog.Info("bladeset does not exist", "bladeset", bladeset)
+		return &csi.GetCapacityResponse{}, nil
+	}
+	if err != nil {
+		llog.Error(err, "failed to read realm capacity", "bladeset", bladeset)
+		return nil, realmError(err)
+	}
+
+	return &csi.GetCapacityResponse{
+		AvailableCapacity: available,
+	}, nil
 }
 
 // ControllerGetCapabilities handles the CSI ControllerGetCapabilities request.
+// Capabilities requiring a realm feature missing on any of the realms configured with
+// WithCapabilityRealms are not advertised, see NegotiateCapabilities.
 //
 // Parameters:
 //
@@ -406,7 +822,7 @@ func (d *Driver) ControllerGetCapabilities(ctx context.Context, in *csi.Controll
 	d.log.V(2).Info("ControllerGetCapabilities called")
 
 	var supportedCapabilities []*csi.ControllerServiceCapability
-	for _, capability := range controllerCapabilities {
+	for _, capability := range d.advertisedControllerCapabilities() {
 		c := &csi.ControllerServiceCapability{
 			Type: &csi.ControllerServiceCapability_Rpc{
 				Rpc: &csi.ControllerServiceCapability_RPC{
@@ -424,7 +840,9 @@ func (d *Driver) ControllerGetCapabilities(ctx context.Context, in *csi.Controll
 	return resp, nil
 }
 
-// ControllerExpandVolume handles the CSI ControllerExpandVolume request.
+// ControllerExpandVolume handles the CSI ControllerExpandVolume request. The soft quota of the
+// volume is set to the required bytes; a limit above the hard quota of the volume raises the
+// hard quota as well. The returned capacity is the size read back from the realm.
 //
 // Parameters:
 //
@@ -437,11 +855,20 @@ func (d *Driver) ControllerGetCapabilities(ctx context.Context, in *csi.Controll
 //	error - Returns an error if validation fails, volume not found, or expansion fails.
 //
 // Error Cases:
-//   - codes.InvalidArgument: If the volume ID, capacity range, or secrets are invalid.
+//   - codes.InvalidArgument: If the volume ID, capacity range, or secrets are invalid. Requests
+//     without secrets use the provisioner secret of the storage class of the volume.
 //   - codes.NotFound: If the volume does not exist.
+//   - codes.OutOfRange: If the capacity exceeds the limits of the realm or bladeset, with the
+//     maximum allowed capacity in an ErrorInfo detail if the realm reports it, or if the
+//     required bytes are below the current size of the volume.
+//   - codes.DeadlineExceeded: If the request ends before all quota steps are applied (see
+//     WithExpansionStep); a retry resumes from the current quota.
 //   - codes.Internal: For unexpected internal errors during expansion.
+//   - codes.Unavailable: If all session slots of the realm stay busy (see WithRealmConcurrencyLimit).
+//   - codes.Aborted: If another CreateVolume, DeleteVolume, ControllerExpandVolume or
+//     ControllerModifyVolume request on the volume is in progress.
 func (d *Driver) ControllerExpandVolume(ctx context.Context, in *csi.ControllerExpandVolumeRequest) (*csi.ControllerExpandVolumeResponse, error) {
-	llog := d.log.WithValues("method", "ControllerExpandVolume")
+	llog := d.requestLogger(ctx).WithValues("method", "ControllerExpandVolume")
 	llog.V(2).Info("ControllerExpandVolume called",
 		"volume_id", in.VolumeId,
 		"capacity_range", in.CapacityRange,
@@ -456,118 +883,702 @@ func (d *Driver) ControllerExpandVolume(ctx context.Context, in *csi.ControllerE
 
 	capacityRange := in.GetCapacityRange()
 	if capacityRange == nil {
-		d.log.Error(fmt.Errorf("volume capacity range must be provided"), InvalidCapacityRangeErrorStr)
+		llog.Error(fmt.Errorf("volume capacity range must be provided"), InvalidCapacityRangeErrorStr)
 		return nil, status.Error(codes.InvalidArgument, "volume capacity range must be provided")
 	}
 
-	secrets := in.GetSecrets()
-	if err := validateReqSecrets(secrets); err != nil {
+	unlock, err := d.volumeLocks.tryLock(volumeID, "expand")
+	if err != nil {
+		llog.Error(err, "volume operation in progress")
+		return nil, err
+	}
+	defer unlock()
+
+	requestSecrets := in.GetSecrets()
+	if len(requestSecrets) == 0 {
+		// the storage class has no controller-expand secret, fall back to its provisioner secret
+		fallback, err := d.expansionSecrets(ctx, volumeID)
+		if err != nil {
+			llog.Error(err, "failed to read the provisioner secret of the volume", "volume_id", volumeID)
+		} else {
+			llog.V(4).Info("using the provisioner secret of the storage class", "volume_id", volumeID)
+			requestSecrets = fallback
+		}
+	}
+
+	secrets, err := d.resolveCredentials(ctx, requestSecrets, nil)
+	if err != nil {
+		llog.Error(err, "failed to resolve realm credentials")
+		return nil, err
+	}
+	if _, err := d.parseRealmSecrets(secrets); err != nil {
 		llog.Error(err, InvalidRequestSecretsErrorStr)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	release, err := d.realmLimiter.acquire(ctx, secrets[utils.RealmConnectionContext.RealmAddress], priorityForeground)
+	if err != nil {
+		llog.Error(err, "realm session slots are busy")
+		return nil, err
+	}
+	defer release()
+
 	if capacityRange.GetRequiredBytes() <= 0 {
 		llog.Error(fmt.Errorf("invalid volume capacity range provided"), "required_bytes must be greater than zero",
 			"required", capacityRange.GetRequiredBytes())
 		return nil, status.Error(codes.InvalidArgument, InvalidCapacityRangeErrorStr)
 	}
+	if limit := capacityRange.GetLimitBytes(); limit < 0 || (limit > 0 && limit < capacityRange.GetRequiredBytes()) {
+		llog.Error(fmt.Errorf("invalid volume capacity range provided"), "limit_bytes must not be lower than required_bytes",
+			"required", capacityRange.GetRequiredBytes(), "limit", limit)
+		return nil, status.Error(codes.InvalidArgument, InvalidCapacityRangeErrorStr)
+	}
 
-	err := d.expandVolume(volumeID, capacityRange, secrets)
+	capacityBytes, err := d.expandVolume(ctx, volumeID, capacityRange, secrets)
 	if err != nil {
 		switch {
+		case errors.Is(err, errVolumeShrink):
+			llog.Error(err, "requested capacity is below the volume size", "volume_id", volumeID)
+			return nil, status.Error(codes.OutOfRange, err.Error())
 		case errors.Is(err, pancli.ErrorNotFound):
 			llog.Error(err, VolumeNotFoundErrorStr, "volume_id", volumeID)
 			return nil, status.Error(codes.NotFound, VolumeNotFoundErrorStr)
+		case errors.Is(err, pancli.ErrorUnauthenticated):
+			llog.Error(err, "failed to expand volume capacity", "volume_id", volumeID)
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		case errors.Is(err, pancli.ErrorOutOfRange):
+			llog.Error(err, "requested capacity exceeds the limits of the realm", "volume_id", volumeID)
+			return nil, d.quotaLimitError(err)
+		case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+			// the quota steps applied so far are kept, a retry resumes from the current quota
+			llog.Error(err, "volume expansion interrupted", "volume_id", volumeID)
+			return nil, status.FromContextError(err).Err()
 		default:
 			llog.Error(err, "failed to expand volume capacity: "+err.Error(), "volume_id", volumeID)
 			return nil, status.Error(codes.Internal, UnexpectedErrorInternalStr)
 		}
 	}
 
-	requiredBytes := capacityRange.GetRequiredBytes()
-	llog.Info("volume expanded successfully", "volume_id", volumeID, "volume_capacity", requiredBytes)
-	// Return expanded volume capacity and indicate that volume expansion on the
-	// node is not required
+	llog.Info("volume expanded successfully", "volume_id", volumeID, "volume_capacity", capacityBytes)
+	// Return expanded volume capacity; node expansion only refreshes the quota cached by
+	// the PanFS clients, see WithNodeQuotaRefresh
 	return &csi.ControllerExpandVolumeResponse{
-		CapacityBytes:         requiredBytes,
-		NodeExpansionRequired: false,
+		CapacityBytes:         capacityBytes,
+		NodeExpansionRequired: d.quotaRefresh.request,
 	}, nil
 }
 
-// expandVolume performs the volume expansion operation.
+// errVolumeShrink is returned by expandVolume for capacities below the current size.
+var errVolumeShrink = errors.New("volumes cannot be shrunk")
+
+// expandVolume raises the hard quota of the volume to the limit of the capacity range, if the
+// volume has a lower hard quota, and its soft quota to the required size. Volumes are never
+// shrunk; requests at the current size only read it back.
 //
 // Parameters:
 //
+//	ctx           - The context of the request.
 //	volumeID      - The ID of the volume to expand.
 //	capacityRange - The requested capacity range.
 //	secrets       - Secrets for authentication.
 //
 // Returns:
 //
-//	error - Returns an error if expansion fails.
-func (d *Driver) expandVolume(volumeID string, capacityRange *csi.CapacityRange, secrets map[string]string) error {
-	// validate required bytes
+//	int64 - The soft quota of the volume in bytes as read back from the realm.
+//	error - Returns errVolumeShrink if the required size is below the current size, or an
+//	        error if the volume cannot be read or expansion fails.
+func (d *Driver) expandVolume(ctx context.Context, volumeID string, capacityRange *csi.CapacityRange, secrets map[string]string) (int64, error) {
+	if parentVolume, directory, ok := utils.ParseDirectoryVolumeID(volumeID); ok {
+		return d.expandDirectoryVolume(ctx, volumeID, parentVolume, directory, capacityRange, secrets)
+	}
+
+	llog := d.requestLogger(ctx).WithValues("volume_id", volumeID)
+
+	volume, err := d.realm(ctx).GetVolume(ctx, volumeID, secrets)
+	if err != nil {
+		return 0, err
+	}
+
+	// quotas are kept with two decimals, smaller differences are rounding
 	requiredBytes := capacityRange.GetRequiredBytes()
+	current := volume.GetSoftQuotaBytes()
+	if requiredBytes < current-volume.QuotaUnit.ToBytes(0.01) {
+		return 0, fmt.Errorf("%w: volume %s has %d bytes, %d bytes requested", errVolumeShrink, volumeID, current, requiredBytes)
+	}
+
+	// the hard quota is raised first, so the soft quota never exceeds it
+	if limitBytes := capacityRange.GetLimitBytes(); limitBytes > 0 && volume.Hard > 0 && limitBytes > volume.GetHardQuotaBytes() {
+		hard := pancli.VolumeModifyParams{utils.VolumeParameters.GetSCKey("hard"): fmt.Sprintf("%.2f", utils.BytesToGB(limitBytes))}
+		if err := d.realm(ctx).ModifyVolume(ctx, volumeID, hard, secrets); err != nil {
+			return 0, err
+		}
+		llog.Info("raised volume hard quota", "hard_quota_bytes", limitBytes)
+	}
 
-	err := d.panfs.ExpandVolume(volumeID, requiredBytes, secrets)
+	if requiredBytes > current {
+		if d.expansionStep > 0 {
+			err = d.expandVolumeInSteps(ctx, volumeID, current, requiredBytes, secrets)
+		} else {
+			err = d.realm(ctx).ExpandVolume(ctx, volumeID, requiredBytes, secrets)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	volume, err = d.realm(ctx).GetVolume(ctx, volumeID, secrets)
 	if err != nil {
-		return err
+		// the volume is expanded, only its size cannot be confirmed
+		llog.Error(err, "failed to read the size of the expanded volume, reporting the requested size")
+		return requiredBytes, nil
 	}
-	return nil
+	return volume.GetSoftQuotaBytes(), nil
 }
 
-// CreateSnapshot handles the CSI CreateSnapshot request (unimplemented).
+// CreateSnapshot handles the CSI CreateSnapshot request. The snapshot is created on the realm
+// with the requested name, its ID is "<source volume>@<snapshot name>".
 //
 // Parameters:
 //
 //	ctx - The context for the request.
-//	in  - The CreateSnapshotRequest.
+//	in  - The CreateSnapshotRequest containing the snapshot name, source volume ID and secrets.
 //
 // Returns:
 //
-//	*csi.CreateSnapshotResponse - Always nil.
-//	error - Always returns codes.Unimplemented.
+//	*csi.CreateSnapshotResponse - The response containing the created or existing snapshot.
+//	error - Returns an error if validation fails or snapshot creation fails.
+//
+// Error Cases:
+//   - codes.InvalidArgument: If the snapshot name, source volume ID or secrets are invalid, or
+//     if the source volume is a directory of a parent volume.
+//   - codes.NotFound: If the source volume does not exist.
+//   - codes.AlreadyExists: If a snapshot with the name exists but cannot be read back.
+//   - codes.FailedPrecondition: If the realm does not support snapshots.
+//   - codes.Unauthenticated: If the realm rejects the credentials.
+//   - codes.Unavailable: If the realm cannot be reached or all session slots of the realm stay
+//     busy (see WithRealmConcurrencyLimit).
+//   - codes.Internal: For unexpected internal errors during snapshot creation.
 func (d *Driver) CreateSnapshot(ctx context.Context, in *csi.CreateSnapshotRequest) (*csi.CreateSnapshotResponse, error) {
-	d.log.V(2).Info("CreateSnapshot called",
+	llog := d.requestLogger(ctx).WithValues("method", "CreateSnapshot")
+	llog.V(2).Info("CreateSnapshot called",
 		"source_volume_id", in.SourceVolumeId,
 		"parameters", in.Parameters,
 		"snapshot_name", in.Name)
 
-	return nil, status.Error(codes.Unimplemented, "")
+	snapshotName, volumeID := in.GetName(), in.GetSourceVolumeId()
+	if snapshotName == "" {
+		llog.Error(fmt.Errorf("snapshot name must be provided"), InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, "snapshot name must be provided")
+	}
+	if volumeID == "" {
+		llog.Error(fmt.Errorf("source volume id must be provided"), InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, "source volume id must be provided")
+	}
+	if _, _, ok := utils.ParseDirectoryVolumeID(volumeID); ok {
+		llog.Error(fmt.Errorf("snapshots of directory volumes are not supported"), InvalidRequestErrorStr, "source_volume_id", volumeID)
+		return nil, directoryVolumeUnsupported("snapshots", volumeID)
+	}
+
+	secrets, err := d.resolveCredentials(ctx, in.GetSecrets(), in.GetParameters())
+	if err != nil {
+		llog.Error(err, "failed to resolve realm credentials")
+		return nil, err
+	}
+	if _, err := d.parseRealmSecrets(secrets); err != nil {
+		llog.Error(err, InvalidRequestSecretsErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	release, err := d.realmLimiter.acquire(ctx, secrets[utils.RealmConnectionContext.RealmAddress], priorityForeground)
+	if err != nil {
+		llog.Error(err, "realm session slots are busy")
+		return nil, err
+	}
+	defer release()
+
+	snapshot, err := d.realm(ctx).CreateSnapshot(ctx, volumeID, snapshotName, secrets)
+	if errors.Is(err, pancli.ErrorAlreadyExist) {
+		// a retried request, return the existing snapshot
+		snapshot, err = d.findSnapshot(ctx, volumeID, snapshotName, secrets)
+		if errors.Is(err, pancli.ErrorNotFound) {
+			err = fmt.Errorf("%w: snapshot %s exists but cannot be read", pancli.ErrorAlreadyExist, snapshotName)
+		}
+	}
+	if err != nil {
+		llog.Error(err, "failed to create snapshot", "source_volume_id", volumeID, "snapshot_name", snapshotName)
+		return nil, realmError(err)
+	}
+
+	llog.Info("snapshot created", "snapshot_id", snapshot.SnapshotID(), "source_volume_id", volumeID)
+	return &csi.CreateSnapshotResponse{
+		Snapshot: csiSnapshot(snapshot),
+	}, nil
 }
 
-// DeleteSnapshot handles the CSI DeleteSnapshot request (unimplemented).
+// DeleteSnapshot handles the CSI DeleteSnapshot request. Snapshots which do not exist,
+// including snapshots with IDs not created by the driver, are reported as deleted.
 //
 // Parameters:
 //
 //	ctx - The context for the request.
-//	in  - The DeleteSnapshotRequest.
+//	in  - The DeleteSnapshotRequest containing the snapshot ID and secrets.
 //
 // Returns:
 //
-//	*csi.DeleteSnapshotResponse - Always nil.
-//	error - Always returns codes.Unimplemented.
+//	*csi.DeleteSnapshotResponse - The response indicating success.
+//	error - Returns an error if validation fails or snapshot deletion fails.
+//
+// Error Cases:
+//   - codes.InvalidArgument: If the snapshot ID or secrets are invalid.
+//   - codes.FailedPrecondition: If the realm does not support snapshots.
+//   - codes.Unauthenticated: If the realm rejects the credentials.
+//   - codes.Unavailable: If the realm cannot be reached or all session slots of the realm stay
+//     busy (see WithRealmConcurrencyLimit).
+//   - codes.Internal: For unexpected internal errors during snapshot deletion.
 func (d *Driver) DeleteSnapshot(ctx context.Context, in *csi.DeleteSnapshotRequest) (*csi.DeleteSnapshotResponse, error) {
-	d.log.V(2).Info("DeleteSnapshot called", "snapshot_id", in.SnapshotId)
-	return nil, status.Error(codes.Unimplemented, "")
+	llog := d.requestLogger(ctx).WithValues("method", "DeleteSnapshot")
+	llog.V(2).Info("DeleteSnapshot called", "snapshot_id", in.SnapshotId)
+
+	snapshotID := in.GetSnapshotId()
+	if snapshotID == "" {
+		llog.Error(fmt.Errorf("snapshot id must be provided"), InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, "snapshot id must be provided")
+	}
+
+	secrets, err := d.resolveCredentials(ctx, in.GetSecrets(), nil)
+	if err != nil {
+		llog.Error(err, "failed to resolve realm credentials")
+		return nil, err
+	}
+	if _, err := d.parseRealmSecrets(secrets); err != nil {
+		llog.Error(err, InvalidRequestSecretsErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	volumeID, snapshotName, err := utils.ParseSnapshotID(snapshotID)
+	if err != nil {
+		// the snapshot cannot exist, deletion is idempotent
+		llog.Info("snapshot id was not created by the driver, nothing to delete", "snapshot_id", snapshotID)
+		return &csi.DeleteSnapshotResponse{}, nil
+	}
+
+	release, err := d.realmLimiter.acquire(ctx, secrets[utils.RealmConnectionContext.RealmAddress], priorityForeground)
+	if err != nil {
+		llog.Error(err, "realm session slots are busy")
+		return nil, err
+	}
+	defer release()
+
+	err = d.realm(ctx).DeleteSnapshot(ctx, volumeID, snapshotName, secrets)
+	// If snapshot does not exist, we return OK status
+	if err != nil && !errors.Is(err, pancli.ErrorNotFound) {
+		llog.Error(err, "failed to delete snapshot", "snapshot_id", snapshotID)
+		return nil, realmError(err)
+	}
+
+	llog.Info("snapshot deleted", "snapshot_id", snapshotID)
+	return &csi.DeleteSnapshotResponse{}, nil
 }
 
-// ListSnapshots handles the CSI ListSnapshots request (unimplemented).
+// ListSnapshots handles the CSI ListSnapshots request. Snapshots are listed ordered by ID, the
+// starting token is the index of the first returned entry.
 //
 // Parameters:
 //
 //	ctx - The context for the request.
-//	in  - The ListSnapshotsRequest.
+//	in  - The ListSnapshotsRequest with optional snapshot ID and source volume ID filters.
 //
 // Returns:
 //
-//	*csi.ListSnapshotsResponse - Always nil.
-//	error - Always returns codes.Unimplemented.
+//	*csi.ListSnapshotsResponse - The response containing the matching snapshots.
+//	error - Returns an error if validation fails or the snapshots cannot be listed.
+//
+// Error Cases:
+//   - codes.InvalidArgument: If max_entries is negative or the secrets are invalid.
+//   - codes.Aborted: If the starting token is invalid.
+//   - codes.FailedPrecondition: If the realm does not support snapshots.
+//   - codes.Unauthenticated: If the realm rejects the credentials.
+//   - codes.Unavailable: If the realm cannot be reached or all session slots of the realm stay
+//     busy (see WithRealmConcurrencyLimit).
+//   - codes.Internal: For unexpected internal errors while listing snapshots.
 func (d *Driver) ListSnapshots(ctx context.Context, in *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
-	d.log.V(2).Info("ListSnapshots called",
+	llog := d.requestLogger(ctx).WithValues("method", "ListSnapshots")
+	llog.V(2).Info("ListSnapshots called",
 		"max_entries", in.MaxEntries,
 		"starting_token", in.StartingToken,
 		"snapshot_id", in.SnapshotId,
 		"source_volume_id", in.SourceVolumeId)
-	return nil, status.Error(codes.Unimplemented, "")
+
+	if in.GetMaxEntries() < 0 {
+		err := fmt.Errorf("max_entries must not be negative, got %d", in.GetMaxEntries())
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	secrets, err := d.resolveCredentials(ctx, in.GetSecrets(), nil)
+	if err != nil {
+		llog.Error(err, "failed to resolve realm credentials")
+		return nil, err
+	}
+	if _, err := d.parseRealmSecrets(secrets); err != nil {
+		llog.Error(err, InvalidRequestSecretsErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	volumeID := in.GetSourceVolumeId()
+	if snapshotID := in.GetSnapshotId(); snapshotID != "" {
+		snapshotVolumeID, _, err := utils.ParseSnapshotID(snapshotID)
+		if err != nil || volumeID != "" && volumeID != snapshotVolumeID {
+			// no snapshot can match the filters
+			return &csi.ListSnapshotsResponse{}, nil
+		}
+		volumeID = snapshotVolumeID
+	}
+
+	release, err := d.realmLimiter.acquire(ctx, secrets[utils.RealmConnectionContext.RealmAddress], priorityBackground)
+	if err != nil {
+		llog.Error(err, "realm session slots are busy")
+		return nil, err
+	}
+	defer release()
+
+	list, err := d.realm(ctx).ListSnapshots(ctx, volumeID, secrets)
+	if errors.Is(err, pancli.ErrorNotFound) {
+		// the source volume does not exist, so it has no snapshots
+		return &csi.ListSnapshotsResponse{}, nil
+	}
+	if err != nil {
+		llog.Error(err, "failed to list snapshots", "source_volume_id", volumeID)
+		return nil, realmError(err)
+	}
+
+	var entries []*csi.ListSnapshotsResponse_Entry
+	for i := range list.Snapshots {
+		snapshot := &list.Snapshots[i]
+		if in.GetSnapshotId() != "" && snapshot.SnapshotID() != in.GetSnapshotId() {
+			continue
+		}
+		if strings.HasPrefix(snapshot.Name, CloneSnapshotPrefix) {
+			continue
+		}
+		entries = append(entries, &csi.ListSnapshotsResponse_Entry{Snapshot: csiSnapshot(snapshot)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Snapshot.SnapshotId < entries[j].Snapshot.SnapshotId
+	})
+
+	entries, nextToken, err := paginate(entries, in.GetStartingToken(), in.GetMaxEntries())
+	if err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+
+	return &csi.ListSnapshotsResponse{
+		Entries:   entries,
+		NextToken: nextToken,
+	}, nil
+}
+
+// findSnapshot reads an existing snapshot of a volume.
+//
+// Parameters:
+//
+//	ctx          - The context of the request.
+//	volumeID     - The ID of the snapshotted volume.
+//	snapshotName - The name of the snapshot.
+//	secrets      - Secrets for authentication.
+//
+// Returns:
+//
+//	*utils.Snapshot - The snapshot.
+//	error           - pancli.ErrorNotFound if the snapshot does not exist, or the error of the realm.
+func (d *Driver) findSnapshot(ctx context.Context, volumeID, snapshotName string, secrets map[string]string) (*utils.Snapshot, error) {
+	list, err := d.realm(ctx).ListSnapshots(ctx, volumeID, secrets)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Snapshots {
+		if list.Snapshots[i].Name == snapshotName {
+			return &list.Snapshots[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: snapshot %s of volume %s", pancli.ErrorNotFound, snapshotName, volumeID)
+}
+
+// snapshotSource reads the snapshot a volume is created from and resolves the capacity range of
+// the volume. The volume must not be smaller than the snapshotted volume, a request without
+// required bytes gets the capacity of the snapshotted volume.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	snapshotID - The ID of the snapshot of the volume content source.
+//	capacity   - The requested capacity range for the volume.
+//	secrets    - Secrets for authentication.
+//
+// Returns:
+//
+//	*utils.Snapshot    - The snapshot.
+//	*csi.CapacityRange - The capacity range of the volume.
+//	error              - The gRPC status error, codes.NotFound if the snapshot does not exist or
+//	                     codes.OutOfRange if the capacity range is smaller than the snapshotted volume.
+func (d *Driver) snapshotSource(ctx context.Context, snapshotID string, capacity *csi.CapacityRange, secrets map[string]string) (*utils.Snapshot, *csi.CapacityRange, error) {
+	volumeID, snapshotName, err := utils.ParseSnapshotID(snapshotID)
+	if err != nil {
+		// the snapshot was not created by the driver
+		return nil, nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	snapshot, err := d.findSnapshot(ctx, volumeID, snapshotName, secrets)
+	if err != nil {
+		return nil, nil, realmError(err)
+	}
+
+	source, err := d.realm(ctx).GetVolume(ctx, volumeID, secrets)
+	if err != nil {
+		return nil, nil, realmError(err)
+	}
+
+	capacity, err = sourceCapacityRange(capacity, source.GetSoftQuotaBytes(), "snapshotted volume")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return snapshot, capacity, nil
+}
+
+// cloneSource takes the snapshot a volume is cloned from and resolves the capacity range of the
+// clone. The snapshot is named after the clone, so retried requests reuse it. It is temporary
+// and must be deleted with deleteCloneSnapshot once the clone is created.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the clone.
+//	sourceID   - The ID of the volume of the volume content source.
+//	capacity   - The requested capacity range for the clone.
+//	secrets    - Secrets for authentication.
+//
+// Returns:
+//
+//	*utils.Snapshot    - The snapshot of the source volume.
+//	*csi.CapacityRange - The capacity range of the clone.
+//	error              - The gRPC status error, codes.NotFound if the source volume does not exist or
+//	                     codes.OutOfRange if the capacity range is smaller than the source volume.
+func (d *Driver) cloneSource(ctx context.Context, volumeName, sourceID string, capacity *csi.CapacityRange, secrets map[string]string) (*utils.Snapshot, *csi.CapacityRange, error) {
+	if _, _, ok := utils.ParseDirectoryVolumeID(sourceID); ok {
+		return nil, nil, directoryVolumeUnsupported("clones", sourceID)
+	}
+
+	source, err := d.realm(ctx).GetVolume(ctx, sourceID, secrets)
+	if err != nil {
+		return nil, nil, realmError(err)
+	}
+
+	capacity, err = sourceCapacityRange(capacity, source.GetSoftQuotaBytes(), "source volume")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snapshotName := cloneSnapshotName(volumeName)
+	snapshot, err := d.realm(ctx).CreateSnapshot(ctx, sourceID, snapshotName, secrets)
+	if errors.Is(err, pancli.ErrorAlreadyExist) {
+		// a retried request, reuse the snapshot
+		snapshot, err = d.findSnapshot(ctx, sourceID, snapshotName, secrets)
+	}
+	if err != nil {
+		return nil, nil, realmError(err)
+	}
+
+	return snapshot, capacity, nil
+}
+
+// deleteCloneSnapshot deletes the temporary snapshot taken by cloneSource. Failures are only
+// logged, the snapshot is reused and deleted again by retried requests of the clone.
+func (d *Driver) deleteCloneSnapshot(ctx context.Context, snapshot *utils.Snapshot, secrets map[string]string) {
+	err := d.realm(ctx).DeleteSnapshot(ctx, string(snapshot.VolumeName), snapshot.Name, secrets)
+	if err != nil && !errors.Is(err, pancli.ErrorNotFound) {
+		d.requestLogger(ctx).Error(err, "failed to delete clone snapshot", "snapshot_id", snapshot.SnapshotID())
+	}
+}
+
+// cloneSnapshotName returns the name of the temporary snapshot a volume is cloned from.
+func cloneSnapshotName(volumeName string) string {
+	return CloneSnapshotPrefix + volumeName
+}
+
+// sourceCapacityRange resolves the capacity range of a volume created from the content of
+// another volume. The volume must not be smaller than the source volume, a request without
+// required bytes gets the capacity of the source volume.
+//
+// Parameters:
+//
+//	capacity    - The requested capacity range for the volume.
+//	sourceBytes - The soft quota of the source volume, 0 if it is unlimited.
+//	source      - The description of the source volume used in errors.
+//
+// Returns:
+//
+//	*csi.CapacityRange - The capacity range of the volume.
+//	error              - codes.OutOfRange if the capacity range is smaller than the source volume.
+func sourceCapacityRange(capacity *csi.CapacityRange, sourceBytes int64, source string) (*csi.CapacityRange, error) {
+	// an unlimited source volume fits into volumes of any size
+	if sourceBytes == 0 {
+		return capacity, nil
+	}
+
+	required, limit := capacity.GetRequiredBytes(), capacity.GetLimitBytes()
+	if limit != 0 && limit < sourceBytes {
+		return nil, status.Errorf(codes.OutOfRange, "limit_bytes (%d) is smaller than the %s (%d)", limit, source, sourceBytes)
+	}
+	if required != 0 && required < sourceBytes {
+		return nil, status.Errorf(codes.OutOfRange, "required_bytes (%d) is smaller than the %s (%d)", required, source, sourceBytes)
+	}
+	if required == 0 {
+		capacity = &csi.CapacityRange{RequiredBytes: sourceBytes, LimitBytes: limit}
+	}
+
+	return capacity, nil
+}
+
+// csiSnapshot converts a realm snapshot into a CSI snapshot. PanFS snapshots are usable as soon
+// as they are created, the size is not reported by the realm.
+func csiSnapshot(snapshot *utils.Snapshot) *csi.Snapshot {
+	return &csi.Snapshot{
+		SnapshotId:     snapshot.SnapshotID(),
+		SourceVolumeId: string(snapshot.VolumeName),
+		CreationTime:   timestamppb.New(snapshot.CreatedAt()),
+		ReadyToUse:     true,
+	}
+}
+
+// realmError maps a realm error of a snapshot, listing or capacity operation to a gRPC status error.
+// Commands the realm version does not support fail with codes.FailedPrecondition.
+// Commands aborted because the request ended fail with codes.Canceled or codes.DeadlineExceeded.
+//
+// Parameters:
+//
+//	err - The error returned by the realm.
+//
+// Returns:
+//
+//	error - The gRPC status error.
+func realmError(err error) error {
+	if abortErr := abortedError(err); abortErr != nil {
+		return abortErr
+	}
+
+	switch {
+	case pancli.IsUnsupportedCommand(err):
+		return status.Errorf(codes.FailedPrecondition, "the realm does not support the operation: %v", err)
+	case errors.Is(err, pancli.ErrorNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, pancli.ErrorAlreadyExist):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, pancli.ErrorInvalidArgument):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, pancli.ErrorUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, pancli.ErrorUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, UnexpectedErrorInternalStr)
+	}
+}
+
+// abortedError returns the gRPC status error of a realm call aborted because the request was
+// cancelled or exceeded its deadline.
+//
+// Parameters:
+//
+//	err - The error returned by the realm.
+//
+// Returns:
+//
+//	error - The codes.Canceled or codes.DeadlineExceeded status error, nil if the call was
+//	        not aborted.
+func abortedError(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return nil
+}
+
+// quotaLimitError returns the gRPC status error of a capacity exceeding the limits of the realm
+// or bladeset. The maximum allowed capacity, if reported by the realm, is attached as the
+// max_bytes metadata of an ErrorInfo detail with reason QuotaLimitExceededReason, so
+// automation can clamp its requests. Likewise, the minimum volume size is attached as the
+// min_bytes metadata with reason QuotaBelowMinimumReason.
+//
+// Parameters:
+//
+//	err - The error returned by the realm.
+//
+// Returns:
+//
+//	error - The codes.OutOfRange status error.
+func (d *Driver) quotaLimitError(err error) error {
+	st := status.New(codes.OutOfRange, err.Error())
+
+	var limitErr *pancli.QuotaLimitError
+	if !errors.As(err, &limitErr) {
+		return st.Err()
+	}
+
+	var info *errdetails.ErrorInfo
+	switch {
+	case limitErr.MinBytes != 0:
+		info = &errdetails.ErrorInfo{
+			Reason:   QuotaBelowMinimumReason,
+			Domain:   d.Name,
+			Metadata: map[string]string{"min_bytes": strconv.FormatInt(limitErr.MinBytes, 10)},
+		}
+	case limitErr.MaxBytes != 0:
+		info = &errdetails.ErrorInfo{
+			Reason:   QuotaLimitExceededReason,
+			Domain:   d.Name,
+			Metadata: map[string]string{"max_bytes": strconv.FormatInt(limitErr.MaxBytes, 10)},
+		}
+	default:
+		return st.Err()
+	}
+	if detailed, detailsErr := st.WithDetails(info); detailsErr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// paginate returns the page of entries starting at the index in the token.
+//
+// Parameters:
+//
+//	entries       - All entries, in a stable order.
+//	startingToken - The index of the first entry to return, empty for the first page.
+//	maxEntries    - The maximum number of entries to return, 0 for all remaining entries.
+//
+// Returns:
+//
+//	[]T    - The entries of the page.
+//	string - The token of the next page, empty if there are no more entries.
+//	error  - Error if the token is not a valid index.
+func paginate[T any](entries []T, startingToken string, maxEntries int32) ([]T, string, error) {
+	start := 0
+	if startingToken != "" {
+		var err error
+		start, err = strconv.Atoi(startingToken)
+		if err != nil || start < 0 || start > len(entries) {
+			return nil, "", fmt.Errorf("invalid starting token %q", startingToken)
+		}
+	}
+
+	end := len(entries)
+	if maxEntries > 0 && start+int(maxEntries) < end {
+		end = start + int(maxEntries)
+	}
+
+	nextToken := ""
+	if end < len(entries) {
+		nextToken = strconv.Itoa(end)
+	}
+	return entries[start:end], nextToken, nil
 }
@@ -0,0 +1,53 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package driver
+
+import (
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc"
+)
+
+// List of supported plugin capabilities.
+// Now we support the following capabilities:
+// - Controller Service
+// - Online Volume Expansion
+var pluginCapabilities = []*csi.PluginCapability{
+	{
+		Type: &csi.PluginCapability_Service_{
+			Service: &csi.PluginCapability_Service{
+				Type: csi.PluginCapability_Service_CONTROLLER_SERVICE,
+			},
+		},
+	},
+	{
+		Type: &csi.PluginCapability_VolumeExpansion_{
+			VolumeExpansion: &csi.PluginCapability_VolumeExpansion{
+				Type: csi.PluginCapability_VolumeExpansion_ONLINE,
+			},
+		},
+	},
+}
+
+// registerControllerServer registers the controller service of the driver with the gRPC server.
+//
+// Parameters:
+//
+//	s - The gRPC server.
+//	d - The driver serving the controller service.
+func registerControllerServer(s *grpc.Server, d *Driver) {
+	csi.RegisterControllerServer(s, d)
+}
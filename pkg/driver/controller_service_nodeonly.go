@@ -0,0 +1,35 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build nodeonly
+
+package driver
+
+import (
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc"
+)
+
+// Node-only builds serve neither the controller service nor volume expansion, so no plugin
+// capabilities are advertised.
+var pluginCapabilities = []*csi.PluginCapability{}
+
+// registerControllerServer does not register the controller service in node-only builds.
+// The controller service is served by the full plugin binary only.
+//
+// Parameters:
+//
+//	s - The gRPC server.
+//	d - The driver.
+func registerControllerServer(s *grpc.Server, d *Driver) {}
@@ -0,0 +1,451 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// newSnapshotTestDriver creates a driver backed by a mocked storage provider.
+func newSnapshotTestDriver(t *testing.T) (*Driver, *mock.MockStorageProviderClient) {
+	pancliMock := mock.NewMockStorageProviderClient(gomock.NewController(t))
+	return &Driver{
+		Name:  DefaultDriverName,
+		log:   klog.Background(),
+		panfs: pancliMock,
+	}, pancliMock
+}
+
+func testSnapshot(volumeName, snapshotName string) *utils.Snapshot {
+	return &utils.Snapshot{Name: snapshotName, VolumeName: utils.VolumeName(volumeName), CreationTime: 1700000000}
+}
+
+func TestControllerCreateSnapshot(t *testing.T) {
+	req := &csi.CreateSnapshotRequest{Name: "snapshot-1", SourceVolumeId: validVolumeName, Secrets: defaultSecrets}
+
+	t.Run("Success", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().CreateSnapshot(gomock.Any(), validVolumeName, "snapshot-1", defaultSecrets).Return(testSnapshot(validVolumeName, "snapshot-1"), nil)
+
+		resp, err := d.CreateSnapshot(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, validVolumeName+"@snapshot-1", resp.Snapshot.SnapshotId)
+		assert.Equal(t, validVolumeName, resp.Snapshot.SourceVolumeId)
+		assert.Equal(t, int64(1700000000), resp.Snapshot.CreationTime.GetSeconds())
+		assert.True(t, resp.Snapshot.ReadyToUse)
+	})
+
+	t.Run("AlreadyExistsIsIdempotent", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().CreateSnapshot(gomock.Any(), validVolumeName, "snapshot-1", defaultSecrets).Return(nil, pancli.ErrorAlreadyExist)
+		pancliMock.EXPECT().ListSnapshots(gomock.Any(), validVolumeName, defaultSecrets).Return(&utils.SnapshotList{
+			Snapshots: []utils.Snapshot{*testSnapshot(validVolumeName, "snapshot-1")},
+		}, nil)
+
+		resp, err := d.CreateSnapshot(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, validVolumeName+"@snapshot-1", resp.Snapshot.SnapshotId)
+	})
+
+	t.Run("AlreadyExistsNotListed", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().CreateSnapshot(gomock.Any(), validVolumeName, "snapshot-1", defaultSecrets).Return(nil, pancli.ErrorAlreadyExist)
+		pancliMock.EXPECT().ListSnapshots(gomock.Any(), validVolumeName, defaultSecrets).Return(&utils.SnapshotList{}, nil)
+
+		_, err := d.CreateSnapshot(t.Context(), req)
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	errorCases := []struct {
+		name     string
+		req      *csi.CreateSnapshotRequest
+		realmErr error
+		code     codes.Code
+	}{
+		{name: "EmptyName", req: &csi.CreateSnapshotRequest{SourceVolumeId: validVolumeName, Secrets: defaultSecrets}, code: codes.InvalidArgument},
+		{name: "EmptySourceVolume", req: &csi.CreateSnapshotRequest{Name: "snapshot-1", Secrets: defaultSecrets}, code: codes.InvalidArgument},
+		{name: "EmptySecrets", req: &csi.CreateSnapshotRequest{Name: "snapshot-1", SourceVolumeId: validVolumeName}, code: codes.InvalidArgument},
+		{name: "SourceVolumeNotFound", req: req, realmErr: fmt.Errorf("%w: No volume with name %s", pancli.ErrorNotFound, validVolumeName), code: codes.NotFound},
+		{name: "Unauthenticated", req: req, realmErr: pancli.ErrorUnauthenticated, code: codes.Unauthenticated},
+		{name: "Unavailable", req: req, realmErr: pancli.ErrorUnavailable, code: codes.Unavailable},
+		{name: "Internal", req: req, realmErr: pancli.ErrorInternal, code: codes.Internal},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, pancliMock := newSnapshotTestDriver(t)
+			if tc.realmErr != nil {
+				pancliMock.EXPECT().CreateSnapshot(gomock.Any(), validVolumeName, "snapshot-1", defaultSecrets).Return(nil, tc.realmErr)
+			}
+
+			resp, err := d.CreateSnapshot(t.Context(), tc.req)
+			assert.Nil(t, resp)
+			assert.Equal(t, tc.code, status.Code(err))
+		})
+	}
+}
+
+func TestControllerDeleteSnapshot(t *testing.T) {
+	snapshotID := validVolumeName + "@snapshot-1"
+
+	t.Run("Success", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().DeleteSnapshot(gomock.Any(), validVolumeName, "snapshot-1", defaultSecrets).Return(nil)
+
+		_, err := d.DeleteSnapshot(t.Context(), &csi.DeleteSnapshotRequest{SnapshotId: snapshotID, Secrets: defaultSecrets})
+		assert.NoError(t, err)
+	})
+
+	t.Run("NotFoundReturnsOK", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().DeleteSnapshot(gomock.Any(), validVolumeName, "snapshot-1", defaultSecrets).Return(pancli.ErrorNotFound)
+
+		_, err := d.DeleteSnapshot(t.Context(), &csi.DeleteSnapshotRequest{SnapshotId: snapshotID, Secrets: defaultSecrets})
+		assert.NoError(t, err)
+	})
+
+	t.Run("ForeignIDReturnsOK", func(t *testing.T) {
+		d, _ := newSnapshotTestDriver(t)
+		_, err := d.DeleteSnapshot(t.Context(), &csi.DeleteSnapshotRequest{SnapshotId: "foreign", Secrets: defaultSecrets})
+		assert.NoError(t, err)
+	})
+
+	t.Run("EmptyID", func(t *testing.T) {
+		d, _ := newSnapshotTestDriver(t)
+		_, err := d.DeleteSnapshot(t.Context(), &csi.DeleteSnapshotRequest{Secrets: defaultSecrets})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("RealmError", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().DeleteSnapshot(gomock.Any(), validVolumeName, "snapshot-1", defaultSecrets).Return(pancli.ErrorInternal)
+
+		_, err := d.DeleteSnapshot(t.Context(), &csi.DeleteSnapshotRequest{SnapshotId: snapshotID, Secrets: defaultSecrets})
+		assert.ErrorIs(t, err, status.Error(codes.Internal, UnexpectedErrorInternalStr))
+	})
+}
+
+func TestControllerListSnapshots(t *testing.T) {
+	list := &utils.SnapshotList{Snapshots: []utils.Snapshot{
+		*testSnapshot("vol-b", "snapshot-1"),
+		*testSnapshot("vol-a", "snapshot-2"),
+		*testSnapshot("vol-a", "snapshot-1"),
+		// temporary snapshot of a clone, not listed
+		*testSnapshot("vol-a", "csi-clone-vol-c"),
+	}}
+	ids := func(resp *csi.ListSnapshotsResponse) []string {
+		var ids []string
+		for _, e := range resp.Entries {
+			ids = append(ids, e.Snapshot.SnapshotId)
+		}
+		return ids
+	}
+
+	t.Run("Paginated", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().ListSnapshots(gomock.Any(), "", defaultSecrets).Return(list, nil).Times(2)
+
+		resp, err := d.ListSnapshots(t.Context(), &csi.ListSnapshotsRequest{MaxEntries: 2, Secrets: defaultSecrets})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"vol-a@snapshot-1", "vol-a@snapshot-2"}, ids(resp))
+		assert.Equal(t, "2", resp.NextToken)
+
+		resp, err = d.ListSnapshots(t.Context(), &csi.ListSnapshotsRequest{MaxEntries: 2, StartingToken: resp.NextToken, Secrets: defaultSecrets})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"vol-b@snapshot-1"}, ids(resp))
+		assert.Empty(t, resp.NextToken)
+	})
+
+	t.Run("BySnapshotID", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().ListSnapshots(gomock.Any(), "vol-a", defaultSecrets).Return(list, nil)
+
+		resp, err := d.ListSnapshots(t.Context(), &csi.ListSnapshotsRequest{SnapshotId: "vol-a@snapshot-2", Secrets: defaultSecrets})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"vol-a@snapshot-2"}, ids(resp))
+	})
+
+	t.Run("BySourceVolume", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().ListSnapshots(gomock.Any(), "vol-c", defaultSecrets).Return(nil, pancli.ErrorNotFound)
+
+		resp, err := d.ListSnapshots(t.Context(), &csi.ListSnapshotsRequest{SourceVolumeId: "vol-c", Secrets: defaultSecrets})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Entries)
+	})
+
+	t.Run("MismatchingFilters", func(t *testing.T) {
+		d, _ := newSnapshotTestDriver(t)
+		resp, err := d.ListSnapshots(t.Context(), &csi.ListSnapshotsRequest{SnapshotId: "vol-a@snapshot-1", SourceVolumeId: "vol-b", Secrets: defaultSecrets})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Entries)
+	})
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().ListSnapshots(gomock.Any(), "", defaultSecrets).Return(list, nil)
+
+		_, err := d.ListSnapshots(t.Context(), &csi.ListSnapshotsRequest{StartingToken: "10", Secrets: defaultSecrets})
+		assert.Equal(t, codes.Aborted, status.Code(err))
+	})
+
+	t.Run("NegativeMaxEntries", func(t *testing.T) {
+		d, _ := newSnapshotTestDriver(t)
+
+		_, err := d.ListSnapshots(t.Context(), &csi.ListSnapshotsRequest{MaxEntries: -1, Secrets: defaultSecrets})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("EmptySecrets", func(t *testing.T) {
+		d, _ := newSnapshotTestDriver(t)
+		_, err := d.ListSnapshots(t.Context(), &csi.ListSnapshotsRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestControllerCreateVolumeFromSnapshot(t *testing.T) {
+	source := &csi.VolumeContentSource{
+		Type: &csi.VolumeContentSource_Snapshot{
+			Snapshot: &csi.VolumeContentSource_SnapshotSource{SnapshotId: validVolumeName + "@snapshot-1"},
+		},
+	}
+	newRequest := func(capacity *csi.CapacityRange, source *csi.VolumeContentSource) *csi.CreateVolumeRequest {
+		return &csi.CreateVolumeRequest{
+			Name:          "restored",
+			CapacityRange: capacity,
+			Secrets:       defaultSecrets,
+			VolumeCapabilities: []*csi.VolumeCapability{{
+				AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+			}},
+			VolumeContentSource: source,
+		}
+	}
+	expectSnapshot := func(pancliMock *mock.MockStorageProviderClient) {
+		pancliMock.EXPECT().ListSnapshots(gomock.Any(), validVolumeName, defaultSecrets).Return(&utils.SnapshotList{
+			Snapshots: []utils.Snapshot{*testSnapshot(validVolumeName, "snapshot-1")},
+		}, nil)
+		pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(&utils.Volume{Name: utils.VolumeName(validVolumeName), Soft: 10}, nil)
+	}
+
+	t.Run("SizeOfSnapshottedVolume", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		expectSnapshot(pancliMock)
+		pancliMock.EXPECT().CreateVolumeFromSnapshot(gomock.Any(), "restored", validVolumeName, "snapshot-1",
+			createParams(t, pancli.NewVolumeCreateParamsBuilder().SetSoftBytes(GB10Bytes)),
+			defaultSecrets).Return(&utils.Volume{Name: "restored", Soft: 10}, nil)
+
+		resp, err := d.CreateVolume(t.Context(), newRequest(nil, source))
+		require.NoError(t, err)
+		assert.Equal(t, GB10Bytes, resp.Volume.CapacityBytes)
+		assert.Equal(t, source, resp.Volume.ContentSource)
+	})
+
+	t.Run("AlreadyExists", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		expectSnapshot(pancliMock)
+		pancliMock.EXPECT().CreateVolumeFromSnapshot(gomock.Any(), "restored", validVolumeName, "snapshot-1", gomock.Any(), defaultSecrets).Return(nil, pancli.ErrorAlreadyExist)
+		pancliMock.EXPECT().GetVolume(gomock.Any(), "restored", defaultSecrets).Return(&utils.Volume{Name: "restored", Soft: 10}, nil)
+
+		resp, err := d.CreateVolume(t.Context(), newRequest(&csi.CapacityRange{RequiredBytes: GB10Bytes}, source))
+		require.NoError(t, err)
+		assert.Equal(t, source, resp.Volume.ContentSource)
+	})
+
+	errorCases := []struct {
+		name     string
+		capacity *csi.CapacityRange
+		source   *csi.VolumeContentSource
+		mockFunc func(*mock.MockStorageProviderClient)
+		code     codes.Code
+	}{
+		{
+			name: "ForeignSnapshotID",
+			source: &csi.VolumeContentSource{
+				Type: &csi.VolumeContentSource_Snapshot{Snapshot: &csi.VolumeContentSource_SnapshotSource{SnapshotId: "snap-123"}},
+			},
+			code: codes.NotFound,
+		},
+		{
+			name:   "SnapshotNotFound",
+			source: source,
+			mockFunc: func(pancliMock *mock.MockStorageProviderClient) {
+				pancliMock.EXPECT().ListSnapshots(gomock.Any(), validVolumeName, defaultSecrets).Return(&utils.SnapshotList{}, nil)
+			},
+			code: codes.NotFound,
+		},
+		{
+			name:     "RequiredBytesTooSmall",
+			capacity: &csi.CapacityRange{RequiredBytes: GB10Bytes / 2},
+			source:   source,
+			mockFunc: expectSnapshot,
+			code:     codes.OutOfRange,
+		},
+		{
+			name:     "LimitBytesTooSmall",
+			capacity: &csi.CapacityRange{LimitBytes: GB10Bytes / 2},
+			source:   source,
+			mockFunc: expectSnapshot,
+			code:     codes.OutOfRange,
+		},
+		{
+			name:   "SnapshotDeletedWhileCreating",
+			source: source,
+			mockFunc: func(pancliMock *mock.MockStorageProviderClient) {
+				expectSnapshot(pancliMock)
+				pancliMock.EXPECT().CreateVolumeFromSnapshot(gomock.Any(), "restored", validVolumeName, "snapshot-1", gomock.Any(), defaultSecrets).
+					Return(nil, fmt.Errorf("%w: snapshot-1", pancli.ErrorNotFound))
+			},
+			code: codes.NotFound,
+		},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, pancliMock := newSnapshotTestDriver(t)
+			if tc.mockFunc != nil {
+				tc.mockFunc(pancliMock)
+			}
+
+			resp, err := d.CreateVolume(t.Context(), newRequest(tc.capacity, tc.source))
+			assert.Nil(t, resp)
+			assert.Equal(t, tc.code, status.Code(err))
+		})
+	}
+}
+
+func TestControllerCloneVolume(t *testing.T) {
+	source := &csi.VolumeContentSource{
+		Type: &csi.VolumeContentSource_Volume{
+			Volume: &csi.VolumeContentSource_VolumeSource{VolumeId: validVolumeName},
+		},
+	}
+	newRequest := func(capacity *csi.CapacityRange) *csi.CreateVolumeRequest {
+		return &csi.CreateVolumeRequest{
+			Name:          "clone",
+			CapacityRange: capacity,
+			Secrets:       defaultSecrets,
+			VolumeCapabilities: []*csi.VolumeCapability{{
+				AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+			}},
+			VolumeContentSource: source,
+		}
+	}
+	expectSource := func(pancliMock *mock.MockStorageProviderClient) {
+		pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(&utils.Volume{Name: utils.VolumeName(validVolumeName), Soft: 10}, nil)
+	}
+	expectSnapshot := func(pancliMock *mock.MockStorageProviderClient) {
+		expectSource(pancliMock)
+		pancliMock.EXPECT().CreateSnapshot(gomock.Any(), validVolumeName, "csi-clone-clone", defaultSecrets).Return(testSnapshot(validVolumeName, "csi-clone-clone"), nil)
+	}
+
+	t.Run("SizeOfSourceVolume", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		gomock.InOrder(
+			pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(&utils.Volume{Name: utils.VolumeName(validVolumeName), Soft: 10}, nil),
+			pancliMock.EXPECT().CreateSnapshot(gomock.Any(), validVolumeName, "csi-clone-clone", defaultSecrets).Return(testSnapshot(validVolumeName, "csi-clone-clone"), nil),
+			pancliMock.EXPECT().CreateVolumeFromSnapshot(gomock.Any(), "clone", validVolumeName, "csi-clone-clone",
+				createParams(t, pancli.NewVolumeCreateParamsBuilder().SetSoftBytes(GB10Bytes)),
+				defaultSecrets).Return(&utils.Volume{Name: "clone", Soft: 10}, nil),
+			pancliMock.EXPECT().DeleteSnapshot(gomock.Any(), validVolumeName, "csi-clone-clone", defaultSecrets).Return(nil),
+		)
+
+		resp, err := d.CreateVolume(t.Context(), newRequest(nil))
+		require.NoError(t, err)
+		assert.Equal(t, GB10Bytes, resp.Volume.CapacityBytes)
+		assert.Equal(t, source, resp.Volume.ContentSource)
+	})
+
+	t.Run("RetriedRequestReusesSnapshot", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		expectSource(pancliMock)
+		pancliMock.EXPECT().CreateSnapshot(gomock.Any(), validVolumeName, "csi-clone-clone", defaultSecrets).Return(nil, pancli.ErrorAlreadyExist)
+		pancliMock.EXPECT().ListSnapshots(gomock.Any(), validVolumeName, defaultSecrets).Return(&utils.SnapshotList{
+			Snapshots: []utils.Snapshot{*testSnapshot(validVolumeName, "csi-clone-clone")},
+		}, nil)
+		pancliMock.EXPECT().CreateVolumeFromSnapshot(gomock.Any(), "clone", validVolumeName, "csi-clone-clone", gomock.Any(), defaultSecrets).Return(nil, pancli.ErrorAlreadyExist)
+		pancliMock.EXPECT().GetVolume(gomock.Any(), "clone", defaultSecrets).Return(&utils.Volume{Name: "clone", Soft: 10}, nil)
+		pancliMock.EXPECT().DeleteSnapshot(gomock.Any(), validVolumeName, "csi-clone-clone", defaultSecrets).Return(pancli.ErrorNotFound)
+
+		resp, err := d.CreateVolume(t.Context(), newRequest(&csi.CapacityRange{RequiredBytes: GB10Bytes}))
+		require.NoError(t, err)
+		assert.Equal(t, source, resp.Volume.ContentSource)
+	})
+
+	t.Run("SnapshotDeletedOnFailure", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		expectSnapshot(pancliMock)
+		pancliMock.EXPECT().CreateVolumeFromSnapshot(gomock.Any(), "clone", validVolumeName, "csi-clone-clone", gomock.Any(), defaultSecrets).Return(nil, fmt.Errorf("failed"))
+		pancliMock.EXPECT().DeleteSnapshot(gomock.Any(), validVolumeName, "csi-clone-clone", defaultSecrets).Return(nil)
+
+		resp, err := d.CreateVolume(t.Context(), newRequest(nil))
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	errorCases := []struct {
+		name     string
+		capacity *csi.CapacityRange
+		mockFunc func(*mock.MockStorageProviderClient)
+		code     codes.Code
+	}{
+		{
+			name: "SourceVolumeNotFound",
+			mockFunc: func(pancliMock *mock.MockStorageProviderClient) {
+				pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(nil, pancli.ErrorNotFound)
+			},
+			code: codes.NotFound,
+		},
+		{
+			name:     "RequiredBytesTooSmall",
+			capacity: &csi.CapacityRange{RequiredBytes: GB10Bytes / 2},
+			mockFunc: expectSource,
+			code:     codes.OutOfRange,
+		},
+		{
+			name:     "LimitBytesTooSmall",
+			capacity: &csi.CapacityRange{LimitBytes: GB10Bytes / 2},
+			mockFunc: expectSource,
+			code:     codes.OutOfRange,
+		},
+		{
+			name: "SnapshotFailed",
+			mockFunc: func(pancliMock *mock.MockStorageProviderClient) {
+				expectSource(pancliMock)
+				pancliMock.EXPECT().CreateSnapshot(gomock.Any(), validVolumeName, "csi-clone-clone", defaultSecrets).Return(nil, pancli.ErrorUnavailable)
+			},
+			code: codes.Unavailable,
+		},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, pancliMock := newSnapshotTestDriver(t)
+			tc.mockFunc(pancliMock)
+
+			resp, err := d.CreateVolume(t.Context(), newRequest(tc.capacity))
+			assert.Nil(t, resp)
+			assert.Equal(t, tc.code, status.Code(err))
+		})
+	}
+}
@@ -15,7 +15,9 @@
 package driver
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
@@ -23,7 +25,9 @@ import (
 	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
 	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -51,6 +55,9 @@ func TestControllerExpandVolume(t *testing.T) {
 		host:     "localhost",
 		panfs:    pancliMock,
 	}
+	volume := func(soft, hard float64) *utils.Volume {
+		return &utils.Volume{Name: utils.VolumeName(validVolumeName), Soft: soft, Hard: hard}
+	}
 
 	testCases := []struct {
 		name             string
@@ -72,9 +79,134 @@ func TestControllerExpandVolume(t *testing.T) {
 			},
 			nil,
 			func() {
-				pancliMock.EXPECT().ExpandVolume(validVolumeName, GB10Bytes, defaultSecrets).Return(nil)
+				gomock.InOrder(
+					pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(5, 0), nil),
+					pancliMock.EXPECT().ExpandVolume(gomock.Any(), validVolumeName, GB10Bytes, defaultSecrets).Return(nil),
+					pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(10, 0), nil),
+				)
+			},
+		},
+		{
+			"RaiseHardQuota",
+			&csi.ControllerExpandVolumeRequest{
+				VolumeId:      validVolumeName,
+				CapacityRange: &csi.CapacityRange{RequiredBytes: GB10Bytes, LimitBytes: 2 * GB10Bytes},
+				Secrets:       defaultSecrets,
+			},
+			&csi.ControllerExpandVolumeResponse{
+				CapacityBytes:         GB10Bytes,
+				NodeExpansionRequired: false,
+			},
+			nil,
+			func() {
+				gomock.InOrder(
+					pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(5, 10), nil),
+					pancliMock.EXPECT().ModifyVolume(gomock.Any(), validVolumeName, pancli.VolumeModifyParams{utils.VolumeParameters.GetSCKey("hard"): "20.00"}, defaultSecrets).Return(nil),
+					pancliMock.EXPECT().ExpandVolume(gomock.Any(), validVolumeName, GB10Bytes, defaultSecrets).Return(nil),
+					pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(10, 20), nil),
+				)
+			},
+		},
+		{
+			"UnlimitedHardQuotaKept",
+			&csi.ControllerExpandVolumeRequest{
+				VolumeId:      validVolumeName,
+				CapacityRange: &csi.CapacityRange{RequiredBytes: GB10Bytes, LimitBytes: 2 * GB10Bytes},
+				Secrets:       defaultSecrets,
+			},
+			&csi.ControllerExpandVolumeResponse{
+				CapacityBytes:         GB10Bytes,
+				NodeExpansionRequired: false,
+			},
+			nil,
+			func() {
+				gomock.InOrder(
+					pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(5, 0), nil),
+					pancliMock.EXPECT().ExpandVolume(gomock.Any(), validVolumeName, GB10Bytes, defaultSecrets).Return(nil),
+					pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(10, 0), nil),
+				)
+			},
+		},
+		{
+			"CurrentSize",
+			&csi.ControllerExpandVolumeRequest{
+				VolumeId:      validVolumeName,
+				CapacityRange: &csi.CapacityRange{RequiredBytes: GB10Bytes},
+				Secrets:       defaultSecrets,
+			},
+			&csi.ControllerExpandVolumeResponse{
+				CapacityBytes:         GB10Bytes,
+				NodeExpansionRequired: false,
+			},
+			nil,
+			func() {
+				pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(10, 0), nil).Times(2)
+			},
+		},
+		{
+			"RealmSizeReported",
+			&csi.ControllerExpandVolumeRequest{
+				VolumeId:      validVolumeName,
+				CapacityRange: &csi.CapacityRange{RequiredBytes: GB10Bytes},
+				Secrets:       defaultSecrets,
+			},
+			&csi.ControllerExpandVolumeResponse{
+				CapacityBytes:         utils.GBToBytes(10.5),
+				NodeExpansionRequired: false,
+			},
+			nil,
+			func() {
+				gomock.InOrder(
+					pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(5, 0), nil),
+					pancliMock.EXPECT().ExpandVolume(gomock.Any(), validVolumeName, GB10Bytes, defaultSecrets).Return(nil),
+					pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(10.5, 0), nil),
+				)
+			},
+		},
+		{
+			"ReadBackFailure",
+			&csi.ControllerExpandVolumeRequest{
+				VolumeId:      validVolumeName,
+				CapacityRange: &csi.CapacityRange{RequiredBytes: GB10Bytes},
+				Secrets:       defaultSecrets,
+			},
+			&csi.ControllerExpandVolumeResponse{
+				CapacityBytes:         GB10Bytes,
+				NodeExpansionRequired: false,
+			},
+			nil,
+			func() {
+				gomock.InOrder(
+					pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(5, 0), nil),
+					pancliMock.EXPECT().ExpandVolume(gomock.Any(), validVolumeName, GB10Bytes, defaultSecrets).Return(nil),
+					pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(nil, pancli.ErrorUnavailable),
+				)
 			},
 		},
+		{
+			"ShrinkError",
+			&csi.ControllerExpandVolumeRequest{
+				VolumeId:      validVolumeName,
+				CapacityRange: &csi.CapacityRange{RequiredBytes: GB10Bytes},
+				Secrets:       defaultSecrets,
+			},
+			nil,
+			status.Error(codes.OutOfRange, fmt.Sprintf("volumes cannot be shrunk: volume %s has %d bytes, %d bytes requested", validVolumeName, 2*GB10Bytes, GB10Bytes)),
+			func() {
+				pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(20, 0), nil)
+			},
+		},
+		{
+			"LimitBelowRequiredError",
+			&csi.ControllerExpandVolumeRequest{
+				VolumeId:      validVolumeName,
+				CapacityRange: &csi.CapacityRange{RequiredBytes: GB10Bytes, LimitBytes: GB10Bytes / 2},
+				Secrets:       defaultSecrets,
+			},
+			nil,
+			status.Error(codes.InvalidArgument, InvalidCapacityRangeErrorStr),
+			nil,
+		},
 		{
 			"EmptyVolumeIdError",
 			&csi.ControllerExpandVolumeRequest{
@@ -85,7 +217,7 @@ func TestControllerExpandVolume(t *testing.T) {
 			nil,
 			status.Error(codes.InvalidArgument, "volume id must be provided"),
 			func() {
-				pancliMock.EXPECT().ExpandVolume(gomock.Any(), gomock.Any(), defaultSecrets).Times(0)
+				pancliMock.EXPECT().ExpandVolume(gomock.Any(), gomock.Any(), gomock.Any(), defaultSecrets).Times(0)
 			},
 		},
 		{
@@ -98,7 +230,7 @@ func TestControllerExpandVolume(t *testing.T) {
 			nil,
 			status.Error(codes.InvalidArgument, "volume capacity range must be provided"),
 			func() {
-				pancliMock.EXPECT().ExpandVolume(gomock.Any(), gomock.Any(), defaultSecrets).Times(0)
+				pancliMock.EXPECT().ExpandVolume(gomock.Any(), gomock.Any(), gomock.Any(), defaultSecrets).Times(0)
 			},
 		},
 		{
@@ -111,7 +243,7 @@ func TestControllerExpandVolume(t *testing.T) {
 			nil,
 			status.Error(codes.InvalidArgument, "secrets must be provided"),
 			func() {
-				pancliMock.EXPECT().ExpandVolume(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				pancliMock.EXPECT().ExpandVolume(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
 		},
 		{
@@ -124,7 +256,7 @@ func TestControllerExpandVolume(t *testing.T) {
 			nil,
 			status.Error(codes.NotFound, VolumeNotFoundErrorStr),
 			func() {
-				pancliMock.EXPECT().ExpandVolume(validVolumeName, GB10Bytes, defaultSecrets).Return(pancli.ErrorNotFound)
+				pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(nil, pancli.ErrorNotFound)
 			},
 		},
 		{
@@ -137,7 +269,8 @@ func TestControllerExpandVolume(t *testing.T) {
 			nil,
 			status.Error(codes.Internal, UnexpectedErrorInternalStr),
 			func() {
-				pancliMock.EXPECT().ExpandVolume(validVolumeName, GB10Bytes, defaultSecrets).Return(pancli.ErrorInternal)
+				pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(volume(5, 0), nil)
+				pancliMock.EXPECT().ExpandVolume(gomock.Any(), validVolumeName, GB10Bytes, defaultSecrets).Return(pancli.ErrorInternal)
 			},
 		},
 		{
@@ -150,7 +283,7 @@ func TestControllerExpandVolume(t *testing.T) {
 			nil,
 			status.Error(codes.InvalidArgument, InvalidCapacityRangeErrorStr),
 			func() {
-				pancliMock.EXPECT().ExpandVolume(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				pancliMock.EXPECT().ExpandVolume(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
 		},
 	}
@@ -171,6 +304,54 @@ func TestControllerExpandVolume(t *testing.T) {
 	}
 }
 
+// TestControllerExpandVolumeQuotaLimit verifies that sizes exceeding the limits of the realm fail
+// with OutOfRange and the maximum allowed size in the error details.
+func TestControllerExpandVolumeQuotaLimit(t *testing.T) {
+	req := &csi.ControllerExpandVolumeRequest{
+		VolumeId:      validVolumeName,
+		CapacityRange: &csi.CapacityRange{RequiredBytes: GB10Bytes},
+		Secrets:       defaultSecrets,
+	}
+
+	t.Run("MaximumReported", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(&utils.Volume{Name: utils.VolumeName(validVolumeName), Soft: 1}, nil)
+		pancliMock.EXPECT().ExpandVolume(gomock.Any(), validVolumeName, GB10Bytes, defaultSecrets).
+			Return(&pancli.QuotaLimitError{MaxBytes: GB10Bytes / 2, Err: pancli.ErrorOutOfRange})
+
+		_, err := d.ControllerExpandVolume(t.Context(), req)
+		st := status.Convert(err)
+		assert.Equal(t, codes.OutOfRange, st.Code())
+		require.Len(t, st.Details(), 1)
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		require.True(t, ok)
+		assert.Equal(t, QuotaLimitExceededReason, info.Reason)
+		assert.Equal(t, DefaultDriverName, info.Domain)
+		assert.Equal(t, strconv.FormatInt(GB10Bytes/2, 10), info.Metadata["max_bytes"])
+	})
+
+	t.Run("MaximumUnknown", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(&utils.Volume{Name: utils.VolumeName(validVolumeName), Soft: 1}, nil)
+		pancliMock.EXPECT().ExpandVolume(gomock.Any(), validVolumeName, GB10Bytes, defaultSecrets).
+			Return(&pancli.QuotaLimitError{Err: pancli.ErrorOutOfRange})
+
+		_, err := d.ControllerExpandVolume(t.Context(), req)
+		st := status.Convert(err)
+		assert.Equal(t, codes.OutOfRange, st.Code())
+		assert.Empty(t, st.Details())
+	})
+}
+
+// createParams builds the volume creation parameters the controller is expected to pass to
+// the realm client.
+func createParams(t *testing.T, builder *pancli.VolumeCreateParamsBuilder) pancli.VolumeCreateParams {
+	t.Helper()
+	params, err := builder.Build()
+	require.NoError(t, err)
+	return params
+}
+
 // TestControllerCreateVolume tests the CreateVolume method of the Driver struct.
 func TestControllerCreateVolume(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -214,7 +395,9 @@ func TestControllerCreateVolume(t *testing.T) {
 			},
 			nil,
 			func() {
-				pancliMock.EXPECT().CreateVolume(validVolumeName, gomock.Any(), defaultSecrets).Times(1).Return(
+				pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName,
+					createParams(t, pancli.NewVolumeCreateParamsBuilder().SetSoftBytes(GB10Bytes)),
+					defaultSecrets).Times(1).Return(
 					&utils.Volume{
 						Name: utils.VolumeName(validVolumeName),
 						Soft: 10.00,
@@ -248,7 +431,7 @@ func TestControllerCreateVolume(t *testing.T) {
 			},
 			nil,
 			func() {
-				pancliMock.EXPECT().CreateVolume(validVolumeName, gomock.Any(), defaultSecrets).Times(1).Return(
+				pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), defaultSecrets).Times(1).Return(
 					&utils.Volume{
 						Name:       utils.VolumeName(validVolumeName),
 						Encryption: "aes-xts-256",
@@ -280,14 +463,14 @@ func TestControllerCreateVolume(t *testing.T) {
 			},
 			nil,
 			func() {
-				pancliMock.EXPECT().CreateVolume(validVolumeName, gomock.Any(), defaultSecrets).Times(1).Return(
+				pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), defaultSecrets).Times(1).Return(
 					&utils.Volume{
 						Name: utils.VolumeName(validVolumeName),
 						Soft: 10.00,
 					},
 					pancli.ErrorAlreadyExist,
 				)
-				pancliMock.EXPECT().GetVolume(validVolumeName, defaultSecrets).Times(1).Return(
+				pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Times(1).Return(
 					&utils.Volume{
 						Name: utils.VolumeName(validVolumeName),
 						Soft: 10.00,
@@ -314,11 +497,11 @@ func TestControllerCreateVolume(t *testing.T) {
 			nil,
 			status.Error(codes.AlreadyExists, "Volume capacity does not match: requiredBytes bytes (10737418240) exceeds soft quota bytes (9663676416)"),
 			func() {
-				pancliMock.EXPECT().CreateVolume(validVolumeName, gomock.Any(), defaultSecrets).Times(1).Return(
+				pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), defaultSecrets).Times(1).Return(
 					nil,
 					pancli.ErrorAlreadyExist,
 				)
-				pancliMock.EXPECT().GetVolume(validVolumeName, defaultSecrets).Times(1).Return(
+				pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Times(1).Return(
 					&utils.Volume{
 						Name: utils.VolumeName(validVolumeName),
 						Soft: 9.00, // Different soft quota to simulate capabilities mismatch
@@ -327,6 +510,80 @@ func TestControllerCreateVolume(t *testing.T) {
 				)
 			},
 		},
+		{
+			"VolumeExistsCapacityReconciled",
+			&csi.CreateVolumeRequest{
+				Name:          validVolumeName,
+				CapacityRange: &csi.CapacityRange{RequiredBytes: GB10Bytes},
+				Parameters: map[string]string{
+					utils.VolumeParameters.GetSCKey("reconcileCapacity"): ReconcileCapacityExpand,
+				},
+				Secrets: defaultSecrets,
+				VolumeCapabilities: []*csi.VolumeCapability{
+					{
+						AccessType: &csi.VolumeCapability_Mount{
+							Mount: &csi.VolumeCapability_MountVolume{},
+						},
+					},
+				},
+			},
+			&csi.CreateVolumeResponse{
+				Volume: &csi.Volume{
+					VolumeId:      validVolumeName,
+					CapacityBytes: GB10Bytes,
+					VolumeContext: map[string]string{},
+				},
+			},
+			nil,
+			func() {
+				pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), defaultSecrets).Times(1).Return(
+					nil,
+					pancli.ErrorAlreadyExist,
+				)
+				pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Times(1).Return(
+					&utils.Volume{
+						Name: utils.VolumeName(validVolumeName),
+						Soft: 9.00,
+					},
+					nil,
+				)
+				pancliMock.EXPECT().ExpandVolume(gomock.Any(), validVolumeName, GB10Bytes, defaultSecrets).Times(1).Return(nil)
+			},
+		},
+		{
+			"VolumeExistsCapacityReconcileExceedsHardQuota",
+			&csi.CreateVolumeRequest{
+				Name:          validVolumeName,
+				CapacityRange: &csi.CapacityRange{RequiredBytes: GB10Bytes},
+				Parameters: map[string]string{
+					utils.VolumeParameters.GetSCKey("reconcileCapacity"): ReconcileCapacityExpand,
+				},
+				Secrets: defaultSecrets,
+				VolumeCapabilities: []*csi.VolumeCapability{
+					{
+						AccessType: &csi.VolumeCapability_Mount{
+							Mount: &csi.VolumeCapability_MountVolume{},
+						},
+					},
+				},
+			},
+			nil,
+			status.Error(codes.AlreadyExists, "Volume capacity does not match: requiredBytes bytes (10737418240) exceeds soft quota bytes (9663676416)"),
+			func() {
+				pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), defaultSecrets).Times(1).Return(
+					nil,
+					pancli.ErrorAlreadyExist,
+				)
+				pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Times(1).Return(
+					&utils.Volume{
+						Name: utils.VolumeName(validVolumeName),
+						Soft: 9.00,
+						Hard: 9.50, // soft quota cannot be expanded beyond the hard quota
+					},
+					nil,
+				)
+			},
+		},
 		{
 			"UnsupportedVolumeCapabilitiesError",
 			&csi.CreateVolumeRequest{
@@ -349,7 +606,7 @@ func TestControllerCreateVolume(t *testing.T) {
 					"unsupported volume capability: %s",
 					&csi.VolumeCapability{AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}}})),
 			func() {
-				pancliMock.EXPECT().CreateVolume(validVolumeName, gomock.Any(), nil).Times(0)
+				pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), nil).Times(0)
 			},
 		},
 		{
@@ -370,7 +627,7 @@ func TestControllerCreateVolume(t *testing.T) {
 			nil,
 			status.Error(codes.InvalidArgument, "secrets must be provided"),
 			func() {
-				pancliMock.EXPECT().CreateVolume(validVolumeName, gomock.Any(), nil).Times(0)
+				pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), nil).Times(0)
 			},
 		},
 		{
@@ -391,7 +648,7 @@ func TestControllerCreateVolume(t *testing.T) {
 			nil,
 			status.Error(codes.InvalidArgument, "name must be provided"),
 			func() {
-				pancliMock.EXPECT().CreateVolume(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				pancliMock.EXPECT().CreateVolume(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
 		},
 		{
@@ -412,7 +669,7 @@ func TestControllerCreateVolume(t *testing.T) {
 			nil,
 			status.Error(codes.InvalidArgument, fmt.Sprintf("required_bytes (%d) cannot be less than zero", -100)),
 			func() {
-				pancliMock.EXPECT().CreateVolume(validVolumeName, gomock.Any(), gomock.Any()).Times(0)
+				pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), gomock.Any()).Times(0)
 			},
 		},
 		{
@@ -427,7 +684,7 @@ func TestControllerCreateVolume(t *testing.T) {
 			nil,
 			status.Error(codes.InvalidArgument, "volume_capabilities must be provided"),
 			func() {
-				pancliMock.EXPECT().CreateVolume(validVolumeName, gomock.Any(), gomock.Any()).Times(0)
+				pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), gomock.Any()).Times(0)
 			},
 		},
 		{
@@ -448,7 +705,7 @@ func TestControllerCreateVolume(t *testing.T) {
 			nil,
 			status.Error(codes.Internal, UnexpectedErrorInternalStr),
 			func() {
-				pancliMock.EXPECT().CreateVolume(validVolumeName, gomock.Any(), gomock.Any()).Times(1).Return(
+				pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), gomock.Any()).Times(1).Return(
 					nil,
 					pancli.ErrorInternal,
 				)
@@ -472,11 +729,11 @@ func TestControllerCreateVolume(t *testing.T) {
 			nil,
 			status.Error(codes.Internal, UnexpectedErrorInternalStr),
 			func() {
-				pancliMock.EXPECT().CreateVolume(validVolumeName, gomock.Any(), gomock.Any()).Times(1).Return(
+				pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), gomock.Any()).Times(1).Return(
 					nil,
 					pancli.ErrorAlreadyExist,
 				)
-				pancliMock.EXPECT().GetVolume(validVolumeName, defaultSecrets).Times(1).Return(
+				pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Times(1).Return(
 					nil,
 					pancli.ErrorInternal,
 				)
@@ -496,6 +753,146 @@ func TestControllerCreateVolume(t *testing.T) {
 	}
 }
 
+// TestCreateVolumeEncryptionMismatch tests CreateVolume with volumes whose encryption mode differs from the request.
+func TestCreateVolumeEncryptionMismatch(t *testing.T) {
+	encrypted := map[string]string{utils.VolumeParameters.GetSCKey("encryption"): "on"}
+
+	testCases := []struct {
+		name       string
+		policy     string
+		parameters map[string]string
+		volume     *utils.Volume
+		exists     bool
+		wantCode   codes.Code
+		wantDelete bool
+	}{
+		{
+			name:       "UnencryptedDeleted",
+			policy:     EncryptionMismatchDelete,
+			parameters: encrypted,
+			volume:     &utils.Volume{Name: utils.VolumeName(validVolumeName), Encryption: "none"},
+			wantCode:   codes.Internal,
+			wantDelete: true,
+		},
+		{
+			name:       "DefaultPolicyDeletes",
+			parameters: encrypted,
+			volume:     &utils.Volume{Name: utils.VolumeName(validVolumeName)},
+			wantCode:   codes.Internal,
+			wantDelete: true,
+		},
+		{
+			name:       "UnencryptedKept",
+			policy:     EncryptionMismatchFail,
+			parameters: encrypted,
+			volume:     &utils.Volume{Name: utils.VolumeName(validVolumeName), Encryption: "none"},
+			wantCode:   codes.FailedPrecondition,
+		},
+		{
+			name:       "UnexpectedlyEncrypted",
+			policy:     EncryptionMismatchFail,
+			parameters: map[string]string{},
+			volume:     &utils.Volume{Name: utils.VolumeName(validVolumeName), Encryption: "aes-xts-256"},
+			wantCode:   codes.FailedPrecondition,
+		},
+		{
+			name:       "ExistingVolumeNeverDeleted",
+			policy:     EncryptionMismatchDelete,
+			parameters: encrypted,
+			volume:     &utils.Volume{Name: utils.VolumeName(validVolumeName), Encryption: "none"},
+			exists:     true,
+			wantCode:   codes.AlreadyExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			pancliMock := mock.NewMockStorageProviderClient(ctrl)
+			d := &Driver{panfs: pancliMock, encryptionMismatchPolicy: tc.policy}
+
+			if tc.exists {
+				pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), defaultSecrets).Return(nil, pancli.ErrorAlreadyExist)
+				pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(tc.volume, nil)
+			} else {
+				pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), defaultSecrets).Return(tc.volume, nil)
+			}
+			if tc.wantDelete {
+				pancliMock.EXPECT().DeleteVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(nil)
+			}
+
+			_, err := d.CreateVolume(t.Context(), &csi.CreateVolumeRequest{
+				Name:       validVolumeName,
+				Parameters: tc.parameters,
+				Secrets:    defaultSecrets,
+				VolumeCapabilities: []*csi.VolumeCapability{
+					{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}},
+				},
+			})
+			assert.Equal(t, tc.wantCode, status.Code(err))
+			assert.ErrorContains(t, err, "encryption")
+		})
+	}
+}
+
+// TestCreateVolumeExistingVolumeState tests CreateVolume with an existing volume of the same
+// name, which is only reported as created once it is out of transitional states.
+func TestCreateVolumeExistingVolumeState(t *testing.T) {
+	testCases := []struct {
+		name        string
+		state       utils.VolumeState
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{
+			name:        "Deleting",
+			state:       utils.VolumeStateDeleting,
+			wantCode:    codes.Aborted,
+			wantMessage: "volume with same name is being deleted, retry later",
+		},
+		{
+			name:        "Creating",
+			state:       utils.VolumeStateCreating,
+			wantCode:    codes.Aborted,
+			wantMessage: "volume with same name is being created, retry later",
+		},
+		{name: "Online", state: utils.VolumeStateOnline, wantCode: codes.OK},
+		{name: "Offline", state: utils.VolumeStateOffline, wantCode: codes.OK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			pancliMock := mock.NewMockStorageProviderClient(ctrl)
+			d := &Driver{panfs: pancliMock}
+
+			pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), defaultSecrets).Return(nil, pancli.ErrorAlreadyExist)
+			pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(&utils.Volume{
+				Name:  utils.VolumeName(validVolumeName),
+				State: tc.state,
+				Soft:  10,
+			}, nil)
+
+			resp, err := d.CreateVolume(t.Context(), &csi.CreateVolumeRequest{
+				Name:          validVolumeName,
+				Secrets:       defaultSecrets,
+				CapacityRange: &csi.CapacityRange{RequiredBytes: 10 << 30},
+				VolumeCapabilities: []*csi.VolumeCapability{
+					{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}},
+				},
+			})
+			assert.Equal(t, tc.wantCode, status.Code(err))
+			if tc.wantCode != codes.OK {
+				assert.Equal(t, status.Error(tc.wantCode, tc.wantMessage), err)
+				assert.Nil(t, resp)
+				return
+			}
+			require.NotNil(t, resp)
+			assert.Equal(t, int64(10<<30), resp.GetVolume().GetCapacityBytes())
+		})
+	}
+}
+
 // TestControllerDeleteVolume tests the DeleteVolume method of the Driver struct.
 func TestControllerDeleteVolume(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -507,6 +904,7 @@ func TestControllerDeleteVolume(t *testing.T) {
 		host:     "localhost",
 		panfs:    pancliMock,
 	}
+	errAborted := fmt.Errorf("%w: %w: realm command aborted: %w", pancli.ErrorOutcomeUnknown, pancli.ErrorUnavailable, context.DeadlineExceeded)
 
 	testCases := []struct {
 		name             string
@@ -524,7 +922,7 @@ func TestControllerDeleteVolume(t *testing.T) {
 			expectedResponse: &csi.DeleteVolumeResponse{},
 			expectedError:    nil,
 			mockFunc: func() {
-				pancliMock.EXPECT().DeleteVolume(validVolumeName, defaultSecrets).Return(nil)
+				pancliMock.EXPECT().DeleteVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(nil)
 			},
 		},
 		{
@@ -536,7 +934,7 @@ func TestControllerDeleteVolume(t *testing.T) {
 			expectedResponse: &csi.DeleteVolumeResponse{},
 			expectedError:    nil,
 			mockFunc: func() {
-				pancliMock.EXPECT().DeleteVolume(validVolumeName, defaultSecrets).Return(pancli.ErrorNotFound)
+				pancliMock.EXPECT().DeleteVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(pancli.ErrorNotFound)
 			},
 		},
 		{
@@ -548,7 +946,19 @@ func TestControllerDeleteVolume(t *testing.T) {
 			expectedResponse: nil,
 			expectedError:    status.Error(codes.Internal, UnexpectedErrorInternalStr),
 			mockFunc: func() {
-				pancliMock.EXPECT().DeleteVolume(validVolumeName, defaultSecrets).Return(pancli.ErrorInternal)
+				pancliMock.EXPECT().DeleteVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(pancli.ErrorInternal)
+			},
+		},
+		{
+			name: "RealmCommandAborted",
+			req: &csi.DeleteVolumeRequest{
+				VolumeId: validVolumeName,
+				Secrets:  defaultSecrets,
+			},
+			expectedResponse: nil,
+			expectedError:    status.Error(codes.DeadlineExceeded, errAborted.Error()),
+			mockFunc: func() {
+				pancliMock.EXPECT().DeleteVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(errAborted)
 			},
 		},
 		{
@@ -560,7 +970,7 @@ func TestControllerDeleteVolume(t *testing.T) {
 			expectedResponse: nil,
 			expectedError:    status.Error(codes.InvalidArgument, "volume id must be provided"),
 			mockFunc: func() {
-				pancliMock.EXPECT().DeleteVolume(gomock.Any(), gomock.Any()).Times(0)
+				pancliMock.EXPECT().DeleteVolume(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
 		},
 		{
@@ -572,7 +982,7 @@ func TestControllerDeleteVolume(t *testing.T) {
 			expectedResponse: nil,
 			expectedError:    status.Error(codes.InvalidArgument, "secrets must be provided"),
 			mockFunc: func() {
-				pancliMock.EXPECT().DeleteVolume(gomock.Any(), gomock.Any()).Times(0)
+				pancliMock.EXPECT().DeleteVolume(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
 		},
 	}
@@ -610,41 +1020,12 @@ func TestUnimplementedControllerMethods(t *testing.T) {
 		assert.ErrorIs(t, err, status.Error(codes.Unimplemented, ""))
 	})
 
-	t.Run("ListVolumes_Unimplemented", func(t *testing.T) {
-		resp, err := driver.ListVolumes(t.Context(), &csi.ListVolumesRequest{})
-		assert.Nil(t, resp)
-		assert.ErrorIs(t, err, status.Error(codes.Unimplemented, ""))
-	})
-
 	t.Run("ControllerGetVolume_Unimplemented", func(t *testing.T) {
 		resp, err := driver.ControllerGetVolume(t.Context(), &csi.ControllerGetVolumeRequest{})
 		assert.Nil(t, resp)
 		assert.ErrorIs(t, err, status.Error(codes.Unimplemented, ""))
 	})
 
-	t.Run("GetCapacity_Unimplemented", func(t *testing.T) {
-		resp, err := driver.GetCapacity(t.Context(), &csi.GetCapacityRequest{})
-		assert.Nil(t, resp)
-		assert.ErrorIs(t, err, status.Error(codes.Unimplemented, ""))
-	})
-
-	t.Run("CreateSnapshot_Unimplemented", func(t *testing.T) {
-		resp, err := driver.CreateSnapshot(t.Context(), &csi.CreateSnapshotRequest{})
-		assert.Nil(t, resp)
-		assert.ErrorIs(t, err, status.Error(codes.Unimplemented, ""))
-	})
-
-	t.Run("DeleteSnapshot_Unimplemented", func(t *testing.T) {
-		resp, err := driver.DeleteSnapshot(t.Context(), &csi.DeleteSnapshotRequest{})
-		assert.Nil(t, resp)
-		assert.ErrorIs(t, err, status.Error(codes.Unimplemented, ""))
-	})
-
-	t.Run("ListSnapshots_Unimplemented", func(t *testing.T) {
-		resp, err := driver.ListSnapshots(t.Context(), &csi.ListSnapshotsRequest{})
-		assert.Nil(t, resp)
-		assert.ErrorIs(t, err, status.Error(codes.Unimplemented, ""))
-	})
 }
 
 // TestControllerGetCapabilities tests the ControllerGetCapabilities method of the Driver struct.
@@ -679,6 +1060,48 @@ func TestControllerGetCapabilities(t *testing.T) {
 				},
 			},
 		},
+		{
+			Type: &csi.ControllerServiceCapability_Rpc{
+				Rpc: &csi.ControllerServiceCapability_RPC{
+					Type: csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+				},
+			},
+		},
+		{
+			Type: &csi.ControllerServiceCapability_Rpc{
+				Rpc: &csi.ControllerServiceCapability_RPC{
+					Type: csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+				},
+			},
+		},
+		{
+			Type: &csi.ControllerServiceCapability_Rpc{
+				Rpc: &csi.ControllerServiceCapability_RPC{
+					Type: csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
+				},
+			},
+		},
+		{
+			Type: &csi.ControllerServiceCapability_Rpc{
+				Rpc: &csi.ControllerServiceCapability_RPC{
+					Type: csi.ControllerServiceCapability_RPC_LIST_VOLUMES,
+				},
+			},
+		},
+		{
+			Type: &csi.ControllerServiceCapability_Rpc{
+				Rpc: &csi.ControllerServiceCapability_RPC{
+					Type: csi.ControllerServiceCapability_RPC_GET_CAPACITY,
+				},
+			},
+		},
+		{
+			Type: &csi.ControllerServiceCapability_Rpc{
+				Rpc: &csi.ControllerServiceCapability_RPC{
+					Type: csi.ControllerServiceCapability_RPC_MODIFY_VOLUME,
+				},
+			},
+		},
 	}
 
 	resp, err := driver.ControllerGetCapabilities(t.Context(), &csi.ControllerGetCapabilitiesRequest{})
@@ -731,7 +1154,7 @@ func TestValidateVolumeCapabilities(t *testing.T) {
 			},
 			expectedError: nil,
 			mockFunc: func() {
-				pancliMock.EXPECT().GetVolume(validVolumeName, defaultSecrets).Return(&utils.Volume{Name: utils.VolumeName(validVolumeName)}, nil)
+				pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(&utils.Volume{Name: utils.VolumeName(validVolumeName)}, nil)
 			},
 		},
 		{
@@ -744,7 +1167,7 @@ func TestValidateVolumeCapabilities(t *testing.T) {
 			expectedResponse: nil,
 			expectedError:    status.Error(codes.InvalidArgument, "volume id must not be empty"),
 			mockFunc: func() {
-				pancliMock.EXPECT().GetVolume(gomock.Any(), gomock.Any()).Times(0)
+				pancliMock.EXPECT().GetVolume(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
 		},
 		{
@@ -757,7 +1180,7 @@ func TestValidateVolumeCapabilities(t *testing.T) {
 			expectedResponse: nil,
 			expectedError:    status.Error(codes.InvalidArgument, "volume capabilities must be provided"),
 			mockFunc: func() {
-				pancliMock.EXPECT().GetVolume(gomock.Any(), gomock.Any()).Times(0)
+				pancliMock.EXPECT().GetVolume(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
 		},
 		{
@@ -770,7 +1193,7 @@ func TestValidateVolumeCapabilities(t *testing.T) {
 			expectedResponse: nil,
 			expectedError:    status.Error(codes.InvalidArgument, "secrets must be provided"),
 			mockFunc: func() {
-				pancliMock.EXPECT().GetVolume(gomock.Any(), gomock.Any()).Times(0)
+				pancliMock.EXPECT().GetVolume(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
 		},
 		{
@@ -783,7 +1206,7 @@ func TestValidateVolumeCapabilities(t *testing.T) {
 			expectedResponse: nil,
 			expectedError:    status.Error(codes.InvalidArgument, VolumeCapabilitiesDoNotMatchErrorStr),
 			mockFunc: func() {
-				pancliMock.EXPECT().GetVolume(gomock.Any(), gomock.Any()).Times(0)
+				pancliMock.EXPECT().GetVolume(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
 		},
 		{
@@ -796,7 +1219,7 @@ func TestValidateVolumeCapabilities(t *testing.T) {
 			expectedResponse: nil,
 			expectedError:    status.Error(codes.NotFound, VolumeNotFoundErrorStr),
 			mockFunc: func() {
-				pancliMock.EXPECT().GetVolume(validVolumeName, defaultSecrets).Return(nil, pancli.ErrorNotFound)
+				pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(nil, pancli.ErrorNotFound)
 			},
 		},
 		{
@@ -809,7 +1232,7 @@ func TestValidateVolumeCapabilities(t *testing.T) {
 			expectedResponse: nil,
 			expectedError:    status.Error(codes.Internal, pancli.ErrorInternal.Error()),
 			mockFunc: func() {
-				pancliMock.EXPECT().GetVolume(validVolumeName, defaultSecrets).Return(nil, pancli.ErrorInternal)
+				pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(nil, pancli.ErrorInternal)
 			},
 		},
 	}
@@ -825,3 +1248,142 @@ func TestValidateVolumeCapabilities(t *testing.T) {
 		})
 	}
 }
+
+// TestControllerListVolumes tests the ListVolumes method of the Driver struct.
+func TestControllerListVolumes(t *testing.T) {
+	list := &utils.VolumeList{Volumes: []utils.Volume{
+		{Name: "vol-c", Soft: 1},
+		{Name: "vol-a", Soft: 10},
+		{Name: "vol-b", Description: "team data csi-tier:gold"},
+	}}
+	newDriver := func(t *testing.T) (*Driver, *mock.MockStorageProviderClient) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		WithCredentialProvider(&staticCredentials{values: map[string]map[string]string{"csi-panfs/realm": defaultSecrets}}, 0)(d)
+		WithDefaultCredentials("csi-panfs/realm")(d)
+		return d, pancliMock
+	}
+	ids := func(resp *csi.ListVolumesResponse) []string {
+		var ids []string
+		for _, e := range resp.Entries {
+			ids = append(ids, e.Volume.VolumeId)
+		}
+		return ids
+	}
+
+	t.Run("Paginated", func(t *testing.T) {
+		d, pancliMock := newDriver(t)
+		pancliMock.EXPECT().ListVolumes(gomock.Any(), defaultSecrets).Return(list, nil).Times(2)
+
+		resp, err := d.ListVolumes(t.Context(), &csi.ListVolumesRequest{MaxEntries: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"vol-a", "vol-b"}, ids(resp))
+		assert.Equal(t, GB10Bytes, resp.Entries[0].Volume.CapacityBytes)
+		assert.Empty(t, resp.Entries[0].Volume.VolumeContext)
+		assert.Equal(t, map[string]string{utils.VolumeParameters.GetSCKey("protectionTier"): "gold"}, resp.Entries[1].Volume.VolumeContext)
+		assert.Equal(t, "2", resp.NextToken)
+
+		resp, err = d.ListVolumes(t.Context(), &csi.ListVolumesRequest{MaxEntries: 2, StartingToken: resp.NextToken})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"vol-c"}, ids(resp))
+		assert.Empty(t, resp.NextToken)
+	})
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		d, pancliMock := newDriver(t)
+		pancliMock.EXPECT().ListVolumes(gomock.Any(), defaultSecrets).Return(list, nil)
+
+		_, err := d.ListVolumes(t.Context(), &csi.ListVolumesRequest{StartingToken: "10"})
+		assert.Equal(t, codes.Aborted, status.Code(err))
+	})
+
+	t.Run("NegativeMaxEntries", func(t *testing.T) {
+		d, _ := newDriver(t)
+
+		_, err := d.ListVolumes(t.Context(), &csi.ListVolumesRequest{MaxEntries: -1})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("RealmError", func(t *testing.T) {
+		d, pancliMock := newDriver(t)
+		pancliMock.EXPECT().ListVolumes(gomock.Any(), defaultSecrets).Return(nil, pancli.ErrorUnauthenticated)
+
+		_, err := d.ListVolumes(t.Context(), &csi.ListVolumesRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("NoDefaultCredentials", func(t *testing.T) {
+		d, _ := newSnapshotTestDriver(t)
+		_, err := d.ListVolumes(t.Context(), &csi.ListVolumesRequest{})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+// TestControllerGetCapacity tests the GetCapacity method of the Driver struct.
+func TestControllerGetCapacity(t *testing.T) {
+	provider := &staticCredentials{values: map[string]map[string]string{
+		"csi-panfs/realm":   defaultSecrets,
+		"csi-panfs/realm-b": {utils.RealmConnectionContext.RealmAddress: "realm-b", utils.RealmConnectionContext.Username: "user", utils.RealmConnectionContext.Password: "pass"},
+	}}
+	newDriver := func(t *testing.T) (*Driver, *mock.MockStorageProviderClient) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		WithCredentialProvider(provider, 0)(d)
+		WithDefaultCredentials("csi-panfs/realm")(d)
+		return d, pancliMock
+	}
+	bladesetParameters := map[string]string{utils.VolumeParameters.GetSCKey("bladeset"): "Set 1"}
+
+	t.Run("Bladeset", func(t *testing.T) {
+		d, pancliMock := newDriver(t)
+		pancliMock.EXPECT().GetCapacity(gomock.Any(), "Set 1", defaultSecrets).Return(GB10Bytes, nil)
+
+		resp, err := d.GetCapacity(t.Context(), &csi.GetCapacityRequest{Parameters: bladesetParameters})
+		require.NoError(t, err)
+		assert.Equal(t, GB10Bytes, resp.AvailableCapacity)
+	})
+
+	t.Run("StorageClassCredentials", func(t *testing.T) {
+		d, pancliMock := newDriver(t)
+		pancliMock.EXPECT().GetCapacity(gomock.Any(), "", provider.values["csi-panfs/realm-b"]).Return(GB10Bytes, nil)
+
+		resp, err := d.GetCapacity(t.Context(), &csi.GetCapacityRequest{
+			Parameters: map[string]string{utils.VolumeParameters.GetSCKey("credentials"): "csi-panfs/realm-b"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, GB10Bytes, resp.AvailableCapacity)
+	})
+
+	t.Run("UnknownBladeset", func(t *testing.T) {
+		d, pancliMock := newDriver(t)
+		pancliMock.EXPECT().GetCapacity(gomock.Any(), "Set 1", defaultSecrets).Return(int64(0), pancli.ErrorNotFound)
+
+		resp, err := d.GetCapacity(t.Context(), &csi.GetCapacityRequest{Parameters: bladesetParameters})
+		require.NoError(t, err)
+		assert.Zero(t, resp.AvailableCapacity)
+	})
+
+	t.Run("UnsupportedCapabilities", func(t *testing.T) {
+		d, _ := newDriver(t)
+		resp, err := d.GetCapacity(t.Context(), &csi.GetCapacityRequest{
+			VolumeCapabilities: []*csi.VolumeCapability{{
+				AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}},
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+			}},
+		})
+		require.NoError(t, err)
+		assert.Zero(t, resp.AvailableCapacity)
+	})
+
+	t.Run("RealmError", func(t *testing.T) {
+		d, pancliMock := newDriver(t)
+		pancliMock.EXPECT().GetCapacity(gomock.Any(), "", defaultSecrets).Return(int64(0), pancli.ErrorUnavailable)
+
+		_, err := d.GetCapacity(t.Context(), &csi.GetCapacityRequest{})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("NoCredentials", func(t *testing.T) {
+		d, _ := newSnapshotTestDriver(t)
+		_, err := d.GetCapacity(t.Context(), &csi.GetCapacityRequest{})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// capabilityCoverage maps every capability advertised by the driver to the tests exercising it.
+// Referencing the test functions keeps the table in sync at compile time: renaming or removing
+// a test breaks the build, advertising a new capability without tests fails TestCapabilityCoverage.
+var capabilityCoverage = map[string][]func(*testing.T){
+	"plugin/CONTROLLER_SERVICE": {
+		TestControllerCreateVolume,
+		TestControllerDeleteVolume,
+		TestControllerGetCapabilities,
+	},
+	"plugin/VOLUME_ACCESSIBILITY_CONSTRAINTS": {
+		TestNodeGetInfoTopology,
+		TestCreateVolumeTopology,
+	},
+	"plugin/volume_expansion/ONLINE": {
+		TestControllerExpandVolume,
+	},
+	"controller/CREATE_DELETE_VOLUME": {
+		TestControllerCreateVolume,
+		TestControllerDeleteVolume,
+		TestDeleteVolumeVerification,
+		TestCreateVolumePVCAnnotationParameters,
+	},
+	"controller/EXPAND_VOLUME": {
+		TestControllerExpandVolume,
+	},
+	"controller/CREATE_DELETE_SNAPSHOT": {
+		TestControllerCreateSnapshot,
+		TestControllerDeleteSnapshot,
+	},
+	"controller/LIST_SNAPSHOTS": {
+		TestControllerListSnapshots,
+	},
+	"controller/CLONE_VOLUME": {
+		TestControllerCloneVolume,
+	},
+	"controller/LIST_VOLUMES": {
+		TestControllerListVolumes,
+	},
+	"controller/GET_CAPACITY": {
+		TestControllerGetCapacity,
+	},
+	"controller/MODIFY_VOLUME": {
+		TestControllerModifyVolume,
+	},
+	"controller/PUBLISH_UNPUBLISH_VOLUME": {
+		TestControllerPublishSingleNode,
+		TestControllerPublishMultiNode,
+		TestControllerPublishInvalid,
+		TestAttachmentTrackingCapability,
+	},
+	"controller/SINGLE_NODE_MULTI_WRITER": {
+		TestValidateVolumeCapabilities,
+		TestValidateCreateVolumeRequest,
+	},
+	"node/SINGLE_NODE_MULTI_WRITER": {
+		TestNodePublishVolume,
+		TestUnpublishVolume,
+	},
+	"node/STAGE_UNSTAGE_VOLUME": {
+		TestNodeStageVolume,
+		TestNodePublishStagedVolume,
+		TestNodeUnstageVolume,
+		TestNodeGetCapabilitiesStagedMounts,
+	},
+	"node/EXPAND_VOLUME": {
+		TestNodeExpandVolumeRemount,
+		TestNodeExpandVolumeStaged,
+		TestNodeExpandVolumeInvalid,
+		TestQuotaRefreshCapabilities,
+	},
+}
+
+// advertisedCapabilities returns the keys of all capabilities advertised by the driver.
+func advertisedCapabilities(t *testing.T, d *Driver) []string {
+	var keys []string
+
+	plugin, err := d.GetPluginCapabilities(t.Context(), &csi.GetPluginCapabilitiesRequest{})
+	require.NoError(t, err)
+	for _, c := range plugin.Capabilities {
+		switch {
+		case c.GetService() != nil:
+			keys = append(keys, "plugin/"+c.GetService().GetType().String())
+		case c.GetVolumeExpansion() != nil:
+			keys = append(keys, "plugin/volume_expansion/"+c.GetVolumeExpansion().GetType().String())
+		default:
+			t.Errorf("unknown plugin capability %v", c)
+		}
+	}
+
+	controller, err := d.ControllerGetCapabilities(t.Context(), &csi.ControllerGetCapabilitiesRequest{})
+	require.NoError(t, err)
+	for _, c := range controller.Capabilities {
+		keys = append(keys, "controller/"+c.GetRpc().GetType().String())
+	}
+
+	node, err := d.NodeGetCapabilities(t.Context(), &csi.NodeGetCapabilitiesRequest{})
+	require.NoError(t, err)
+	for _, c := range node.Capabilities {
+		keys = append(keys, "node/"+c.GetRpc().GetType().String())
+	}
+
+	return keys
+}
+
+// TestCapabilityCoverage verifies that every advertised capability is covered by tests and
+// that the coverage table does not list capabilities which are no longer advertised. The
+// driver is built with all options enabling capabilities, so optional ones are checked too.
+func TestCapabilityCoverage(t *testing.T) {
+	d := newAttachmentTestDriver()
+	for _, opt := range []Option{
+		WithStagedMounts(true),
+		WithQuotaRefresh(true, ""),
+		WithTopology(true),
+	} {
+		opt(d)
+	}
+
+	advertised := advertisedCapabilities(t, d)
+	require.NotEmpty(t, advertised)
+
+	for _, key := range advertised {
+		assert.NotEmpty(t, capabilityCoverage[key], "capability %s is advertised but not covered by any test", key)
+	}
+	for key := range capabilityCoverage {
+		assert.Contains(t, advertised, key, "capability %s is covered but no longer advertised", key)
+	}
+}
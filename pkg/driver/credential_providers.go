@@ -0,0 +1,250 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// kubernetesSecretCredentials reads credentials from Kubernetes Secrets.
+type kubernetesSecretCredentials struct {
+	client kubernetes.Interface
+}
+
+// NewKubernetesSecretCredentialProvider creates a credential provider reading the credentials
+// from the Kubernetes Secret referenced by the handle "<namespace>/<name>".
+//
+// Parameters:
+//
+//	client - The Kubernetes client.
+//
+// Returns:
+//
+//	CredentialProvider - The credential provider.
+func NewKubernetesSecretCredentialProvider(client kubernetes.Interface) CredentialProvider {
+	return &kubernetesSecretCredentials{client: client}
+}
+
+// Credentials implements CredentialProvider.
+func (p *kubernetesSecretCredentials) Credentials(ctx context.Context, handle string) (map[string]string, error) {
+	namespace, name, ok := strings.Cut(handle, "/")
+	if !ok || namespace == "" || name == "" {
+		return nil, fmt.Errorf("%w: handle %q is not <namespace>/<name>", ErrCredentialsNotFound, handle)
+	}
+
+	secret, err := p.client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: secret %s", ErrCredentialsNotFound, handle)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %s: %w", handle, err)
+	}
+
+	values := make(map[string]string, len(secret.Data)+len(secret.StringData))
+	for k, v := range secret.Data {
+		values[k] = string(v)
+	}
+	for k, v := range secret.StringData {
+		values[k] = v
+	}
+	return values, nil
+}
+
+// fileCredentials reads credentials from directories with one file per key.
+type fileCredentials struct {
+	dir string
+}
+
+// NewFileCredentialProvider creates a credential provider reading the credentials from the
+// directory referenced by the handle, relative to dir. Every file in the directory is a key of
+// the credentials, hidden files are skipped. The files are read on every lookup, so credentials
+// rotated in place, e.g. by a Vault agent or a mounted Secret, are picked up.
+//
+// Parameters:
+//
+//	dir - The base directory of the credential directories.
+//
+// Returns:
+//
+//	CredentialProvider - The credential provider.
+func NewFileCredentialProvider(dir string) CredentialProvider {
+	return &fileCredentials{dir: dir}
+}
+
+// Credentials implements CredentialProvider.
+func (p *fileCredentials) Credentials(_ context.Context, handle string) (map[string]string, error) {
+	if err := validateCredentialPath(handle); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(p.dir, filepath.FromSlash(handle))
+	values, err := readSecretDir(dir)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: directory %s", ErrCredentialsNotFound, dir)
+	}
+	return values, err
+}
+
+// readSecretDir reads a directory with one file per secret key, e.g. a mounted Secret.
+// Hidden files and entries which are no regular files are skipped.
+//
+// Parameters:
+//
+//	dir - The path of the directory.
+//
+// Returns:
+//
+//	map[string]string - The secrets by file name.
+//	error             - Error if the directory or one of its files cannot be read.
+func readSecretDir(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		file := filepath.Join(dir, entry.Name())
+		// mounted Secrets link their keys to files in a hidden directory
+		if info, err := os.Stat(file); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		values[entry.Name()] = string(data)
+	}
+	return values, nil
+}
+
+// vaultCredentials reads credentials from a HashiCorp Vault KV version 2 secrets engine.
+type vaultCredentials struct {
+	address   string
+	mount     string
+	tokenFile string
+	client    *http.Client
+}
+
+// NewVaultCredentialProvider creates a credential provider reading the credentials from the
+// secret at the path referenced by the handle in a HashiCorp Vault KV version 2 secrets engine.
+// The token is read from the token file on every lookup, so a token renewed in place, e.g. by
+// a Vault agent, is picked up.
+//
+// Parameters:
+//
+//	address   - The address of the Vault server, e.g. "https://vault.example.com:8200".
+//	mount     - The mount path of the KV secrets engine, e.g. "secret".
+//	tokenFile - The path of the file holding the Vault token.
+//
+// Returns:
+//
+//	CredentialProvider - The credential provider.
+func NewVaultCredentialProvider(address, mount, tokenFile string) CredentialProvider {
+	return &vaultCredentials{
+		address:   strings.TrimSuffix(address, "/"),
+		mount:     strings.Trim(mount, "/"),
+		tokenFile: tokenFile,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// vaultKVResponse is the response of a Vault KV version 2 read.
+type vaultKVResponse struct {
+	Data struct {
+		Data map[string]any `json:"data"`
+	} `json:"data"`
+}
+
+// Credentials implements CredentialProvider.
+func (p *vaultCredentials) Credentials(ctx context.Context, handle string) (map[string]string, error) {
+	if err := validateCredentialPath(handle); err != nil {
+		return nil, err
+	}
+
+	token, err := os.ReadFile(p.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault token: %w", err)
+	}
+
+	secretURL := fmt.Sprintf("%s/v1/%s/data/%s", p.address, url.PathEscape(p.mount), (&url.URL{Path: handle}).EscapedPath())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, secretURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", strings.TrimSpace(string(token)))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: vault secret %s", ErrCredentialsNotFound, handle)
+	default:
+		return nil, fmt.Errorf("failed to read vault secret %s: %s", handle, resp.Status)
+	}
+
+	var kv vaultKVResponse
+	if err := json.NewDecoder(resp.Body).Decode(&kv); err != nil {
+		return nil, fmt.Errorf("failed to parse vault secret %s: %w", handle, err)
+	}
+
+	values := make(map[string]string, len(kv.Data.Data))
+	for k, v := range kv.Data.Data {
+		if s, ok := v.(string); ok {
+			values[k] = s
+		} else {
+			values[k] = fmt.Sprint(v)
+		}
+	}
+	return values, nil
+}
+
+// validateCredentialPath checks that a handle is a relative path which stays below the base
+// path of the provider.
+//
+// Parameters:
+//
+//	handle - The credential handle.
+//
+// Returns:
+//
+//	error - ErrCredentialsNotFound if the handle is not a valid path.
+func validateCredentialPath(handle string) error {
+	if path.IsAbs(handle) || path.Clean(handle) != handle || handle == ".." || strings.HasPrefix(handle, "../") {
+		return fmt.Errorf("%w: handle %q is not a relative path", ErrCredentialsNotFound, handle)
+	}
+	return nil
+}
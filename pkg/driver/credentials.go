@@ -0,0 +1,246 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Credential providers resolving realm credentials referenced by handles.
+const (
+	// CredentialProviderKubernetesSecret reads the credentials from a Kubernetes Secret,
+	// the handle is "<namespace>/<name>".
+	CredentialProviderKubernetesSecret = "kubernetes-secret"
+	// CredentialProviderVault reads the credentials from a HashiCorp Vault KV version 2
+	// secrets engine, the handle is the path of the secret in the engine.
+	CredentialProviderVault = "vault"
+	// CredentialProviderFile reads the credentials from a directory with one file per key,
+	// e.g. rendered by a Vault agent, the handle is the path of the directory.
+	CredentialProviderFile = "file"
+)
+
+// DefaultCredentialCacheTTL is the default time resolved credentials are cached.
+const DefaultCredentialCacheTTL = 5 * time.Minute
+
+// ErrCredentialsNotFound is returned by credential providers if the handle references no credentials.
+var ErrCredentialsNotFound = errors.New("credentials not found")
+
+// CredentialProvider resolves realm credentials referenced by an opaque handle, e.g. kept in an
+// external secret manager instead of a Kubernetes Secret.
+type CredentialProvider interface {
+	// Credentials returns the realm connection secrets, see utils.RealmConnectionContext,
+	// referenced by the handle. Returns ErrCredentialsNotFound if the handle references
+	// no credentials.
+	Credentials(ctx context.Context, handle string) (map[string]string, error)
+}
+
+// WithCredentialProvider resolves realm credentials referenced by a handle in the
+// panfs.csi.vdura.com/credentials storage class parameter of CreateVolume, or in the
+// credentials_handle key of the request secrets, with the provider. Resolved credentials are
+// cached for the TTL, so rotated credentials are used once the cached entry expires.
+//
+// Parameters:
+//
+//	provider - The credential provider.
+//	ttl      - The time resolved credentials are cached, 0 disables caching.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithCredentialProvider(provider CredentialProvider, ttl time.Duration) Option {
+	return func(d *Driver) {
+		d.credentials = &credentialCache{provider: provider, ttl: ttl}
+	}
+}
+
+// WithDefaultCredentials sets the handle of the realm credentials used by requests which carry
+// no secrets, e.g. ListVolumes. The handle is resolved with the credential provider.
+//
+// Parameters:
+//
+//	handle - The credential handle, empty to disable requests without secrets.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithDefaultCredentials(handle string) Option {
+	return func(d *Driver) {
+		d.defaultCredentials = handle
+	}
+}
+
+// ValidateCredentialProvider checks that the credential provider is supported.
+//
+// Parameters:
+//
+//	name - The name of the credential provider, empty if disabled.
+//
+// Returns:
+//
+//	error - Error if the provider is unknown.
+func ValidateCredentialProvider(name string) error {
+	switch name {
+	case "", CredentialProviderKubernetesSecret, CredentialProviderVault, CredentialProviderFile:
+		return nil
+	default:
+		return fmt.Errorf("invalid credential provider %q: must be %q, %q or %q",
+			name, CredentialProviderKubernetesSecret, CredentialProviderVault, CredentialProviderFile)
+	}
+}
+
+// cachedCredentials are credentials resolved by a credential provider.
+type cachedCredentials struct {
+	values  map[string]string
+	expires time.Time
+}
+
+// credentialCache caches the credentials resolved by a credential provider per handle.
+type credentialCache struct {
+	provider CredentialProvider
+	ttl      time.Duration
+	// now returns the current time, time.Now unless overridden in tests
+	now     func() time.Time
+	entries map[string]cachedCredentials
+	sync.Mutex
+}
+
+// get returns the cached credentials of the handle, or resolves them with the provider if they
+// are not cached or expired. The provider is called without holding the lock, so a slow secret
+// manager does not block requests using other handles.
+//
+// Parameters:
+//
+//	ctx    - The context of the request.
+//	handle - The credential handle.
+//
+// Returns:
+//
+//	map[string]string - The credentials.
+//	error             - The error of the provider.
+func (c *credentialCache) get(ctx context.Context, handle string) (map[string]string, error) {
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+
+	c.Lock()
+	entry, ok := c.entries[handle]
+	c.Unlock()
+	if ok && now().Before(entry.expires) {
+		return entry.values, nil
+	}
+
+	values, err := c.provider.Credentials(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.ttl > 0 {
+		c.Lock()
+		if c.entries == nil {
+			c.entries = make(map[string]cachedCredentials)
+		}
+		c.entries[handle] = cachedCredentials{values: values, expires: now().Add(c.ttl)}
+		c.Unlock()
+	}
+	return values, nil
+}
+
+// resolveCredentials returns the secrets of a request with the settings of the realm selected
+// from the realm registry, see WithRealmRegistry, and the credentials referenced by a
+// credential handle added. Requests without a handle are returned unchanged, or with the
+// default secret if they carry no secrets, see WithDefaultSecret.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	secrets    - The secrets of the request.
+//	parameters - The storage class parameters of the request, nil if the request has none.
+//
+// Returns:
+//
+//	map[string]string - The secrets with the resolved credentials, which take precedence.
+//	error             - The gRPC status error, codes.InvalidArgument if the realm or handle
+//	                    cannot be resolved or codes.Unavailable if the provider fails.
+func (d *Driver) resolveCredentials(ctx context.Context, secrets, parameters map[string]string) (map[string]string, error) {
+	secrets, err := d.routeRealm(secrets, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	handle := parameters[utils.VolumeParameters.GetSCKey("credentials")]
+	if handle == "" {
+		handle = secrets[utils.RealmConnectionContext.CredentialsHandle]
+	}
+	if handle == "" {
+		return d.requestSecrets(secrets)
+	}
+	if d.credentials == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "credential handle %q requires a credential provider", handle)
+	}
+
+	credentials, err := d.credentials.get(ctx, handle)
+	if errors.Is(err, ErrCredentialsNotFound) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to resolve credentials %q: %v", handle, err)
+	}
+
+	resolved := make(map[string]string, len(secrets)+len(credentials))
+	for k, v := range secrets {
+		resolved[k] = v
+	}
+	delete(resolved, utils.RealmConnectionContext.CredentialsHandle)
+	for k, v := range credentials {
+		resolved[k] = v
+	}
+	return resolved, nil
+}
+
+// defaultSecrets resolves the realm credentials of requests which carry no secrets. A realm or
+// handle in the storage class parameters, e.g. of GetCapacity, takes precedence over the default
+// credentials, which take precedence over the default secret.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	parameters - The storage class parameters of the request, nil if the request has none.
+//
+// Returns:
+//
+//	map[string]string - The resolved realm connection secrets.
+//	error             - The gRPC status error, codes.FailedPrecondition if no credentials are
+//	                    referenced, see resolveCredentials otherwise.
+func (d *Driver) defaultSecrets(ctx context.Context, parameters map[string]string) (map[string]string, error) {
+	if parameters[utils.VolumeParameters.GetSCKey("realm")] != "" {
+		return d.resolveCredentials(ctx, nil, parameters)
+	}
+	if d.defaultCredentials == "" && parameters[utils.VolumeParameters.GetSCKey("credentials")] == "" {
+		if d.defaultSecretPath != "" {
+			return d.requestSecrets(nil)
+		}
+		return nil, status.Error(codes.FailedPrecondition, "default realm credentials are not configured")
+	}
+	return d.resolveCredentials(ctx, map[string]string{utils.RealmConnectionContext.CredentialsHandle: d.defaultCredentials}, parameters)
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// staticCredentials is a credential provider counting its lookups.
+type staticCredentials struct {
+	values  map[string]map[string]string
+	err     error
+	lookups int
+}
+
+func (p *staticCredentials) Credentials(_ context.Context, handle string) (map[string]string, error) {
+	p.lookups++
+	if p.err != nil {
+		return nil, p.err
+	}
+	values, ok := p.values[handle]
+	if !ok {
+		return nil, ErrCredentialsNotFound
+	}
+	return values, nil
+}
+
+func TestCredentialCache(t *testing.T) {
+	provider := &staticCredentials{values: map[string]map[string]string{"realm": {"password": "old"}}}
+	now := time.Unix(1700000000, 0)
+	cache := &credentialCache{provider: provider, ttl: time.Minute, now: func() time.Time { return now }}
+
+	values, err := cache.get(t.Context(), "realm")
+	require.NoError(t, err)
+	assert.Equal(t, "old", values["password"])
+
+	// rotated credentials are used once the cached entry expires
+	provider.values["realm"] = map[string]string{"password": "new"}
+	values, _ = cache.get(t.Context(), "realm")
+	assert.Equal(t, "old", values["password"])
+	assert.Equal(t, 1, provider.lookups)
+
+	now = now.Add(time.Minute)
+	values, _ = cache.get(t.Context(), "realm")
+	assert.Equal(t, "new", values["password"])
+	assert.Equal(t, 2, provider.lookups)
+
+	_, err = cache.get(t.Context(), "unknown")
+	assert.ErrorIs(t, err, ErrCredentialsNotFound)
+}
+
+func TestResolveCredentials(t *testing.T) {
+	provider := &staticCredentials{values: map[string]map[string]string{"realm": defaultSecrets}}
+	d := &Driver{credentials: &credentialCache{provider: provider}}
+	handleKey := utils.RealmConnectionContext.CredentialsHandle
+
+	t.Run("WithoutHandle", func(t *testing.T) {
+		secrets, err := d.resolveCredentials(t.Context(), defaultSecrets, nil)
+		require.NoError(t, err)
+		assert.Equal(t, defaultSecrets, secrets)
+	})
+
+	t.Run("StorageClassParameter", func(t *testing.T) {
+		secrets, err := d.resolveCredentials(t.Context(), nil, map[string]string{utils.VolumeParameters.GetSCKey("credentials"): "realm"})
+		require.NoError(t, err)
+		assert.Equal(t, defaultSecrets, secrets)
+	})
+
+	t.Run("SecretKey", func(t *testing.T) {
+		secrets, err := d.resolveCredentials(t.Context(), map[string]string{handleKey: "realm", "extra": "kept"}, nil)
+		require.NoError(t, err)
+		assert.NotContains(t, secrets, handleKey)
+		assert.Equal(t, "kept", secrets["extra"])
+		assert.Equal(t, "realm", secrets[utils.RealmConnectionContext.RealmAddress])
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := d.resolveCredentials(t.Context(), map[string]string{handleKey: "unknown"}, nil)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("ProviderFailure", func(t *testing.T) {
+		d := &Driver{credentials: &credentialCache{provider: &staticCredentials{err: errors.New("connection refused")}}}
+		_, err := d.resolveCredentials(t.Context(), map[string]string{handleKey: "realm"}, nil)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("NoProvider", func(t *testing.T) {
+		_, err := (&Driver{}).resolveCredentials(t.Context(), map[string]string{handleKey: "realm"}, nil)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+// TestControllerResolvesCredentials verifies that controller requests referencing a credential
+// handle are served with the resolved credentials.
+func TestControllerResolvesCredentials(t *testing.T) {
+	d, pancliMock := newSnapshotTestDriver(t)
+	WithCredentialProvider(&staticCredentials{values: map[string]map[string]string{"realm": defaultSecrets}}, time.Minute)(d)
+	pancliMock.EXPECT().DeleteVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(nil)
+
+	_, err := d.DeleteVolume(t.Context(), &csi.DeleteVolumeRequest{
+		VolumeId: validVolumeName,
+		Secrets:  map[string]string{utils.RealmConnectionContext.CredentialsHandle: "realm"},
+	})
+	assert.NoError(t, err)
+}
+
+func TestKubernetesSecretCredentialProvider(t *testing.T) {
+	provider := NewKubernetesSecretCredentialProvider(fake.NewClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "realm", Namespace: "csi-panfs"},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}))
+
+	values, err := provider.Credentials(t.Context(), "csi-panfs/realm")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"password": "secret"}, values)
+
+	_, err = provider.Credentials(t.Context(), "csi-panfs/missing")
+	assert.ErrorIs(t, err, ErrCredentialsNotFound)
+
+	_, err = provider.Credentials(t.Context(), "realm")
+	assert.ErrorIs(t, err, ErrCredentialsNotFound)
+}
+
+func TestFileCredentialProvider(t *testing.T) {
+	dir := t.TempDir()
+	realmDir := filepath.Join(dir, "site-a", "realm")
+	require.NoError(t, os.MkdirAll(filepath.Join(realmDir, "..data"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(realmDir, "..data", "password"), []byte("secret"), 0o600))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "password"), filepath.Join(realmDir, "password")))
+	require.NoError(t, os.WriteFile(filepath.Join(realmDir, "user"), []byte("admin"), 0o600))
+
+	provider := NewFileCredentialProvider(dir)
+	values, err := provider.Credentials(t.Context(), "site-a/realm")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"password": "secret", "user": "admin"}, values)
+
+	for _, handle := range []string{"site-a/missing", "../realm", "/etc", "site-a/../../realm"} {
+		_, err := provider.Credentials(t.Context(), handle)
+		assert.ErrorIs(t, err, ErrCredentialsNotFound, handle)
+	}
+}
+
+func TestVaultCredentialProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv/data/panfs/realm":
+			_, _ = w.Write([]byte(`{"data":{"data":{"user":"admin","password":"secret","port":22},"metadata":{"version":3}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("s.token\n"), 0o600))
+
+	provider := NewVaultCredentialProvider(server.URL+"/", "kv", tokenFile)
+	values, err := provider.Credentials(t.Context(), "panfs/realm")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"user": "admin", "password": "secret", "port": "22"}, values)
+
+	_, err = provider.Credentials(t.Context(), "panfs/missing")
+	assert.ErrorIs(t, err, ErrCredentialsNotFound)
+
+	require.NoError(t, os.WriteFile(tokenFile, []byte("s.revoked"), 0o600))
+	_, err = provider.Credentials(t.Context(), "panfs/realm")
+	assert.ErrorContains(t, err, "403")
+}
+
+func TestValidateCredentialProvider(t *testing.T) {
+	for _, name := range []string{"", CredentialProviderKubernetesSecret, CredentialProviderVault, CredentialProviderFile} {
+		assert.NoError(t, ValidateCredentialProvider(name))
+	}
+	assert.Error(t, ValidateCredentialProvider("aws"))
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultDataPathCheckTTL is how long the result of a data path check is reused unless configured.
+const DefaultDataPathCheckTTL = time.Minute
+
+// dataPathDialTimeout bounds the connection attempt to a single realm address.
+const dataPathDialTimeout = 5 * time.Second
+
+// dataPathResult is the cached result of a data path check of a realm.
+type dataPathResult struct {
+	err     error
+	checked time.Time
+}
+
+// dataPathChecker probes whether the realm data address is reachable from the node before
+// volumes are mounted. The zero value is disabled.
+type dataPathChecker struct {
+	// port of the realm data address, empty disables the check
+	port string
+	ttl  time.Duration
+	// key is the configured realm value
+	results map[string]dataPathResult
+	// dial connects to an address, net.DialTimeout unless overridden in tests
+	dial func(network, address string, timeout time.Duration) (net.Conn, error)
+	sync.Mutex
+}
+
+// WithDataPathCheck probes the realm data address from the node before mounting a volume,
+// since a realm reachable by the controller over SSH may still be unreachable from the node,
+// e.g. due to firewalls or routing of the data network. Volumes of unreachable realms fail
+// with a precise error instead of a generic mount failure. Results are reused per realm for
+// the TTL.
+//
+// Parameters:
+//
+//	port - The TCP port of the realm data address to probe, empty disables the check.
+//	ttl  - How long the result of a check is reused.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithDataPathCheck(port string, ttl time.Duration) Option {
+	return func(d *Driver) {
+		d.dataPath.port = port
+		d.dataPath.ttl = ttl
+	}
+}
+
+// checkDataPath verifies that the realm data address is reachable from the node.
+//
+// Parameters:
+//
+//	realm - The realm address or comma-separated list of addresses from the secrets.
+//
+// Returns:
+//
+//	error - codes.Unavailable naming the node if none of the realm addresses is reachable, nil
+//	        if reachable or the check is disabled.
+func (d *Driver) checkDataPath(realm string) error {
+	c := &d.dataPath
+	if c.port == "" {
+		return nil
+	}
+
+	c.Lock()
+	result, ok := c.results[realm]
+	c.Unlock()
+	if !ok || time.Since(result.checked) >= c.ttl {
+		result = dataPathResult{err: c.probe(realm), checked: time.Now()}
+
+		c.Lock()
+		if c.results == nil {
+			c.results = make(map[string]dataPathResult)
+		}
+		c.results[realm] = result
+		c.Unlock()
+	}
+
+	if result.err != nil {
+		return status.Errorf(codes.Unavailable, "data path to realm %s unreachable from node %s: %v", realm, d.host, result.err)
+	}
+	return nil
+}
+
+// probe connects to the data port of the realm addresses in turn.
+//
+// Parameters:
+//
+//	realm - The realm address or comma-separated list of addresses.
+//
+// Returns:
+//
+//	error - Error if none of the addresses accepts a connection.
+func (c *dataPathChecker) probe(realm string) error {
+	addresses, err := utils.ParseRealmAddresses(realm)
+	if err != nil {
+		return err
+	}
+
+	dial := c.dial
+	if dial == nil {
+		dial = net.DialTimeout
+	}
+
+	var errs []error
+	for _, address := range addresses {
+		host, err := utils.ParseRealmAddress(address)
+		if err != nil {
+			return err
+		}
+		conn, err := dial("tcp", net.JoinHostPort(host, c.port), dataPathDialTimeout)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		_ = conn.Close()
+		return nil
+	}
+	return fmt.Errorf("port %s: %w", c.port, errors.Join(errs...))
+}
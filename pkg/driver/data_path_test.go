@@ -0,0 +1,103 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestCheckDataPath verifies the probing of the realm data addresses and the caching of results.
+func TestCheckDataPath(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		d := &Driver{}
+		assert.NoError(t, d.checkDataPath("realm.example.com"))
+	})
+
+	t.Run("Reachable", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+		_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+		d := &Driver{host: "node-1"}
+		WithDataPathCheck(port, time.Minute)(d)
+		assert.NoError(t, d.checkDataPath("127.0.0.1"))
+	})
+
+	t.Run("UnreachableCached", func(t *testing.T) {
+		var dialed []string
+		d := &Driver{host: "node-1"}
+		WithDataPathCheck("10622", time.Minute)(d)
+		d.dataPath.dial = func(_, address string, _ time.Duration) (net.Conn, error) {
+			dialed = append(dialed, address)
+			return nil, errors.New("connection timed out")
+		}
+
+		err := d.checkDataPath("10.0.0.1,10.0.0.2")
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.ErrorContains(t, err, "data path to realm 10.0.0.1,10.0.0.2 unreachable from node node-1: port 10622: connection timed out")
+		assert.Equal(t, []string{"10.0.0.1:10622", "10.0.0.2:10622"}, dialed)
+
+		// the result is reused within the TTL
+		assert.Error(t, d.checkDataPath("10.0.0.1,10.0.0.2"))
+		assert.Len(t, dialed, 2)
+
+		d.dataPath.ttl = 0
+		assert.Error(t, d.checkDataPath("10.0.0.1,10.0.0.2"))
+		assert.Len(t, dialed, 4)
+	})
+}
+
+// TestNodePublishVolumeDataPathUnreachable verifies that volumes of realms unreachable from
+// the node are not mounted.
+func TestNodePublishVolumeDataPathUnreachable(t *testing.T) {
+	d, mockMounter := newStagingTestDriver(t)
+	d.host = "node-1"
+	WithDataPathCheck("10622", time.Minute)(d)
+	d.dataPath.dial = func(string, string, time.Duration) (net.Conn, error) {
+		return nil, errors.New("no route to host")
+	}
+	mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := d.NodeStageVolume(t.Context(), &csi.NodeStageVolumeRequest{
+		VolumeId:          validVolumeName,
+		StagingTargetPath: validStagingPath,
+		VolumeCapability:  mountCapability(),
+		Secrets:           defaultSecrets,
+	})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.ErrorContains(t, err, "unreachable from node node-1")
+
+	WithStagedMounts(false)(d)
+	_, err = d.NodePublishVolume(t.Context(), &csi.NodePublishVolumeRequest{
+		VolumeId:         validVolumeName,
+		TargetPath:       validPublishTargetPath,
+		VolumeCapability: mountCapability(),
+		VolumeContext:    map[string]string{utils.VolumeParameters.GetSCKey("verifyMount"): "false"},
+		Secrets:          defaultSecrets,
+	})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
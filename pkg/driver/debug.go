@@ -0,0 +1,127 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// DebugStater is implemented by components which expose their internal state
+// for the debug snapshot, e.g. connection pools and caches of a StorageProviderClient.
+type DebugStater interface {
+	DebugState() map[string]any
+}
+
+// inflightOp describes an RPC which is currently being handled.
+type inflightOp struct {
+	method  string
+	started time.Time
+}
+
+// inflightOps tracks the RPCs currently being handled by the driver. The zero value is ready to use.
+type inflightOps struct {
+	ops map[*inflightOp]struct{}
+	sync.Mutex
+}
+
+// unaryInterceptor registers every unary RPC for the duration of its handler.
+func (o *inflightOps) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	op := &inflightOp{method: info.FullMethod, started: time.Now()}
+
+	o.Lock()
+	if o.ops == nil {
+		o.ops = make(map[*inflightOp]struct{})
+	}
+	o.ops[op] = struct{}{}
+	o.Unlock()
+
+	defer func() {
+		o.Lock()
+		delete(o.ops, op)
+		o.Unlock()
+	}()
+
+	return handler(ctx, req)
+}
+
+// snapshot returns the in-flight RPCs, oldest first.
+func (o *inflightOps) snapshot() []map[string]string {
+	o.Lock()
+	ops := make([]*inflightOp, 0, len(o.ops))
+	for op := range o.ops {
+		ops = append(ops, op)
+	}
+	o.Unlock()
+
+	sort.Slice(ops, func(i, j int) bool { return ops[i].started.Before(ops[j].started) })
+
+	out := make([]map[string]string, 0, len(ops))
+	for _, op := range ops {
+		out = append(out, map[string]string{
+			"method":   op.method,
+			"duration": time.Since(op.started).Round(time.Millisecond).String(),
+		})
+	}
+	return out
+}
+
+// debugState collects the internal driver state included in the debug snapshot.
+//
+// Returns:
+//
+//	[]any - Key/value pairs describing the driver state.
+func (d *Driver) debugState() []any {
+	state := []any{
+		"driver_name", d.Name,
+		"version", d.Version,
+		"host", d.host,
+		"endpoint", d.endpoint,
+		"inflight_operations", d.inflight.snapshot(),
+		"options", map[string]string{
+			"slow_rpc_threshold": d.slowRPCThreshold.String(),
+		},
+	}
+
+	if s, ok := d.panfs.(DebugStater); ok {
+		state = append(state, "storage_provider", s.DebugState())
+	}
+
+	return state
+}
+
+// DumpDebugSnapshot logs the goroutine stacks and internal state of the driver.
+// It is triggered by SIGQUIT to allow debugging stuck operations without restarting the pod.
+func (d *Driver) DumpDebugSnapshot() {
+	d.log.Info("debug snapshot", d.debugState()...)
+	d.log.Info("debug snapshot goroutines", "stacks", goroutineStacks())
+}
+
+// goroutineStacks returns the stack traces of all goroutines.
+func goroutineStacks() string {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
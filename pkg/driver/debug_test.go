@@ -0,0 +1,71 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"k8s.io/klog/v2"
+)
+
+// TestInflightOps verifies that RPCs are tracked only while their handler runs.
+func TestInflightOps(t *testing.T) {
+	var ops inflightOps
+	info := &grpc.UnaryServerInfo{FullMethod: "/csi.v1.Controller/CreateVolume"}
+
+	_, err := ops.unaryInterceptor(t.Context(), nil, info, func(context.Context, any) (any, error) {
+		snapshot := ops.snapshot()
+		assert.Len(t, snapshot, 1)
+		assert.Equal(t, info.FullMethod, snapshot[0]["method"])
+		return nil, nil
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, ops.snapshot())
+}
+
+// TestDebugState verifies that the snapshot includes driver and storage provider state.
+func TestDebugState(t *testing.T) {
+	d := &Driver{
+		Name:    DefaultDriverName,
+		Version: "testing",
+		panfs:   pancli.NewPancliSSHClient(pancli.NewSSHClient()),
+		log:     klog.Background(),
+	}
+
+	state := map[string]any{}
+	kv := d.debugState()
+	for i := 0; i+1 < len(kv); i += 2 {
+		state[kv[i].(string)] = kv[i+1]
+	}
+
+	assert.Equal(t, DefaultDriverName, state["driver_name"])
+	assert.Equal(t, "testing", state["version"])
+	assert.Empty(t, state["inflight_operations"])
+	assert.Equal(t, map[string]any{
+		"serialized_realms":           []string{},
+		"ssh_connections":             []string{},
+		"ssh_sessions":                map[string]int{},
+		"unreachable_realm_addresses": []string{},
+	}, state["storage_provider"])
+
+	assert.Contains(t, goroutineStacks(), "TestDebugState")
+	d.DumpDebugSnapshot()
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithDefaultSecret sets a driver-wide realm secret used by requests which carry no secrets,
+// e.g. if the storage class sets no provisioner or node secret parameters. The secret is read
+// from the path on every use, so a rotated secret is picked up. Credential handles take
+// precedence over the default secret.
+//
+// Parameters:
+//
+//	path - The path of the secret, see LoadDefaultSecret, empty to disable the fallback.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithDefaultSecret(path string) Option {
+	return func(d *Driver) {
+		d.defaultSecretPath = path
+	}
+}
+
+// LoadDefaultSecret reads a realm secret from a directory with one file per key, e.g. a
+// mounted Kubernetes Secret, or from a file with one "key=value" line per key, e.g. an
+// environment file. Empty lines and lines starting with "#" are skipped in files.
+//
+// Parameters:
+//
+//	path - The path of the directory or file.
+//
+// Returns:
+//
+//	map[string]string - The realm connection secrets, see utils.RealmConnectionContext.
+//	error             - Error if the path cannot be read or a line of the file is invalid.
+func LoadDefaultSecret(path string) (map[string]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read default secret: %w", err)
+	}
+	if info.IsDir() {
+		secrets, err := readSecretDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read default secret: %w", err)
+		}
+		return secrets, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read default secret: %w", err)
+	}
+	secrets := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid default secret %s: line %d is not a key=value pair", path, line)
+		}
+		secrets[key] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read default secret %s: %w", path, err)
+	}
+	return secrets, nil
+}
+
+// requestSecrets returns the secrets of a request, or the default secret if the request
+// carries no secrets and a default secret is configured with WithDefaultSecret.
+//
+// Parameters:
+//
+//	secrets - The secrets of the request.
+//
+// Returns:
+//
+//	map[string]string - The secrets to use.
+//	error             - The gRPC status error, codes.Unavailable if the default secret cannot be read.
+func (d *Driver) requestSecrets(secrets map[string]string) (map[string]string, error) {
+	if len(secrets) > 0 || d.defaultSecretPath == "" {
+		return secrets, nil
+	}
+	defaults, err := LoadDefaultSecret(d.defaultSecretPath)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return defaults, nil
+}
+
+// ValidateDefaultSecret checks that the default secret can be read and holds a realm address,
+// a user and credentials, e.g. at startup.
+//
+// Parameters:
+//
+//	path - The path of the default secret.
+//
+// Returns:
+//
+//	error - Error if the secret cannot be read or is incomplete.
+func ValidateDefaultSecret(path string) error {
+	secrets, err := LoadDefaultSecret(path)
+	if err != nil {
+		return err
+	}
+	if _, err := utils.ParseRealmSecrets(secrets); err != nil {
+		return fmt.Errorf("invalid default secret %s: %w", path, err)
+	}
+	return nil
+}
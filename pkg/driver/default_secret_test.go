@@ -0,0 +1,143 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// writeDefaultSecretDir writes the secrets as a directory with one file per key.
+func writeDefaultSecretDir(t *testing.T, secrets map[string]string) string {
+	dir := t.TempDir()
+	for key, value := range secrets {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, key), []byte(value), 0o600))
+	}
+	return dir
+}
+
+func TestLoadDefaultSecret(t *testing.T) {
+	t.Run("Directory", func(t *testing.T) {
+		secrets, err := LoadDefaultSecret(writeDefaultSecretDir(t, defaultSecrets))
+		require.NoError(t, err)
+		assert.Equal(t, defaultSecrets, secrets)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "secret.env")
+		require.NoError(t, os.WriteFile(file, []byte("# realm\nrealm_ip = 10.0.0.1\n\nuser=admin\npassword=se=cret\n"), 0o600))
+
+		secrets, err := LoadDefaultSecret(file)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"realm_ip": "10.0.0.1", "user": "admin", "password": "se=cret"}, secrets)
+	})
+
+	t.Run("InvalidLine", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "secret.env")
+		require.NoError(t, os.WriteFile(file, []byte("realm_ip=10.0.0.1\npassword\n"), 0o600))
+
+		_, err := LoadDefaultSecret(file)
+		assert.ErrorContains(t, err, "line 2 is not a key=value pair")
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		_, err := LoadDefaultSecret(filepath.Join(t.TempDir(), "missing"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestValidateDefaultSecret(t *testing.T) {
+	assert.NoError(t, ValidateDefaultSecret(writeDefaultSecretDir(t, defaultSecrets)))
+
+	err := ValidateDefaultSecret(writeDefaultSecretDir(t, map[string]string{utils.RealmConnectionContext.Username: "admin"}))
+	assert.ErrorContains(t, err, "missing realm_ip in secrets")
+}
+
+// TestDefaultSecretFallback verifies that requests without secrets use the default secret,
+// while request secrets and credential handles take precedence.
+func TestDefaultSecretFallback(t *testing.T) {
+	dir := writeDefaultSecretDir(t, defaultSecrets)
+	requestSecrets := map[string]string{utils.RealmConnectionContext.RealmAddress: "other"}
+	handleSecrets := map[string]string{utils.RealmConnectionContext.RealmAddress: "handle"}
+
+	d := &Driver{}
+	WithDefaultSecret(dir)(d)
+	WithCredentialProvider(&staticCredentials{values: map[string]map[string]string{"realm": handleSecrets}}, 0)(d)
+
+	secrets, err := d.resolveCredentials(t.Context(), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, defaultSecrets, secrets)
+
+	secrets, err = d.resolveCredentials(t.Context(), requestSecrets, nil)
+	require.NoError(t, err)
+	assert.Equal(t, requestSecrets, secrets)
+
+	secrets, err = d.resolveCredentials(t.Context(), nil, map[string]string{utils.VolumeParameters.GetSCKey("credentials"): "realm"})
+	require.NoError(t, err)
+	assert.Equal(t, handleSecrets, secrets)
+
+	// requests which never carry secrets, e.g. ListVolumes
+	secrets, err = d.defaultSecrets(t.Context(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, defaultSecrets, secrets)
+
+	WithDefaultCredentials("realm")(d)
+	secrets, err = d.defaultSecrets(t.Context(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, handleSecrets, secrets)
+
+	// without a default secret, requests without secrets are unchanged
+	secrets, err = (&Driver{}).resolveCredentials(t.Context(), nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, secrets)
+
+	WithDefaultSecret(filepath.Join(dir, "missing"))(d)
+	_, err = d.resolveCredentials(t.Context(), nil, nil)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+// TestControllerUsesDefaultSecret verifies that a volume is deleted with the default secret if
+// the storage class sets no provisioner secret.
+func TestControllerUsesDefaultSecret(t *testing.T) {
+	d, pancliMock := newSnapshotTestDriver(t)
+	WithDefaultSecret(writeDefaultSecretDir(t, defaultSecrets))(d)
+	pancliMock.EXPECT().DeleteVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(nil)
+
+	_, err := d.DeleteVolume(t.Context(), &csi.DeleteVolumeRequest{VolumeId: validVolumeName})
+	assert.NoError(t, err)
+}
+
+// TestRequestSecrets verifies that node requests without secrets, e.g. if the storage class
+// sets no node secret, use the default secret.
+func TestRequestSecrets(t *testing.T) {
+	d := &Driver{}
+	secrets, err := d.requestSecrets(map[string]string{})
+	require.NoError(t, err)
+	assert.Empty(t, secrets)
+
+	WithDefaultSecret(writeDefaultSecretDir(t, defaultSecrets))(d)
+	secrets, err = d.requestSecrets(map[string]string{})
+	require.NoError(t, err)
+	assert.Equal(t, defaultSecrets, secrets)
+}
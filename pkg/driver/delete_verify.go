@@ -0,0 +1,81 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+)
+
+// DefaultDeleteVerifyInterval is the default delay between reads of a deleted volume.
+const DefaultDeleteVerifyInterval = time.Second
+
+// WithDeleteVerification makes DeleteVolume read the volume after a successful delete command
+// and fail while the realm still reports it, e.g. because the realm deletes volumes asynchronously.
+// Zero attempts disable the verification.
+//
+// Parameters:
+//
+//	attempts - The number of reads before the deletion is reported as failed.
+//	interval - The delay between reads.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithDeleteVerification(attempts int, interval time.Duration) Option {
+	return func(d *Driver) {
+		d.deleteVerifyAttempts = attempts
+		d.deleteVerifyInterval = interval
+	}
+}
+
+// verifyVolumeDeleted waits until the realm reports the volume as not found.
+//
+// Parameters:
+//
+//	ctx      - The context of the request, reads stop when it is done.
+//	volumeID - The name of the deleted volume.
+//	secrets  - Map of authentication secrets.
+//
+// Returns:
+//
+//	error - Error if the volume still exists after all attempts or cannot be read.
+func (d *Driver) verifyVolumeDeleted(ctx context.Context, volumeID string, secrets map[string]string) error {
+	for attempt := 1; attempt <= d.deleteVerifyAttempts; attempt++ {
+		vol, err := d.realm(ctx).GetVolume(ctx, volumeID, secrets)
+		if errors.Is(err, pancli.ErrorNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to verify deletion of volume %s: %w", volumeID, err)
+		}
+
+		d.log.V(4).Info("deleted volume still exists", "volume_id", volumeID, "state", vol.State, "attempt", attempt)
+		if attempt == d.deleteVerifyAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("volume %s still exists: %w", volumeID, ctx.Err())
+		case <-time.After(d.deleteVerifyInterval):
+		}
+	}
+	return fmt.Errorf("volume %s still exists after %d attempts", volumeID, d.deleteVerifyAttempts)
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+func TestDeleteVolumeVerification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	pancliMock := mock.NewMockStorageProviderClient(ctrl)
+	d := &Driver{
+		log:   klog.Background(),
+		panfs: pancliMock,
+	}
+	WithDeleteVerification(3, time.Millisecond)(d)
+
+	req := &csi.DeleteVolumeRequest{VolumeId: validVolumeName, Secrets: defaultSecrets}
+	deleting := &utils.Volume{Name: utils.VolumeName(validVolumeName), State: utils.VolumeStateDeleting}
+
+	t.Run("GoneAfterRetry", func(t *testing.T) {
+		gomock.InOrder(
+			pancliMock.EXPECT().DeleteVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(nil),
+			pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(deleting, nil),
+			pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(nil, pancli.ErrorNotFound),
+		)
+
+		_, err := d.DeleteVolume(t.Context(), req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("StillExists", func(t *testing.T) {
+		pancliMock.EXPECT().DeleteVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(nil)
+		pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).Times(3).Return(deleting, nil)
+
+		_, err := d.DeleteVolume(t.Context(), req)
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.ErrorContains(t, err, "still exists after 3 attempts")
+	})
+
+	t.Run("AlreadyDeleted", func(t *testing.T) {
+		// nothing to verify if the volume did not exist
+		pancliMock.EXPECT().DeleteVolume(gomock.Any(), validVolumeName, defaultSecrets).Return(pancli.ErrorNotFound)
+
+		_, err := d.DeleteVolume(t.Context(), req)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,222 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// directoryVolumeUnsupported returns the error of operations which are not supported for
+// volumes provisioned as directories of a parent volume.
+//
+// Parameters:
+//
+//	operation - The unsupported operation, e.g. "snapshots".
+//	volumeID  - The ID of the directory volume.
+//
+// Returns:
+//
+//	error - The codes.InvalidArgument status error.
+func directoryVolumeUnsupported(operation, volumeID string) error {
+	return status.Errorf(codes.InvalidArgument, "%s are not supported for volume %s, it is a directory of a parent volume", operation, volumeID)
+}
+
+// directoryQuotaBytes returns the directory quota of a directory volume for a capacity range:
+// the required bytes, or the limit if only the limit is set.
+//
+// Parameters:
+//
+//	capacity - The requested capacity range.
+//
+// Returns:
+//
+//	int64 - The directory quota in bytes.
+//	error - Error if the range sets neither required nor limit bytes.
+func directoryQuotaBytes(capacity *csi.CapacityRange) (int64, error) {
+	if required := capacity.GetRequiredBytes(); required > 0 {
+		return required, nil
+	}
+	if limit := capacity.GetLimitBytes(); limit > 0 {
+		return limit, nil
+	}
+	return 0, errors.New("capacity_range must be provided for volumes provisioned as directories of a parent volume")
+}
+
+// directoryCapacityMatches reports whether the quota of an existing directory satisfies the
+// requested capacity range. Quotas are kept with two decimals, smaller differences are rounding.
+//
+// Parameters:
+//
+//	capacity - The requested capacity range.
+//	dir      - The existing directory.
+//
+// Returns:
+//
+//	error - Error describing the mismatch, nil if the directory matches.
+func directoryCapacityMatches(capacity *csi.CapacityRange, dir *utils.Directory) error {
+	quota := dir.GetHardQuotaBytes()
+	rounding := dir.QuotaUnit.ToBytes(0.01)
+
+	if required := capacity.GetRequiredBytes(); required > 0 && required > quota+rounding {
+		return fmt.Errorf("required bytes (%d) exceed directory quota bytes (%d)", required, quota)
+	}
+	if limit := capacity.GetLimitBytes(); limit > 0 && quota > limit+rounding {
+		return fmt.Errorf("directory quota bytes (%d) exceed limit bytes (%d)", quota, limit)
+	}
+	return nil
+}
+
+// createDirectoryVolume provisions a volume as a directory with a directory quota in an
+// existing parent volume, instead of a realm volume. The directory is named like the volume
+// and the volume ID is "<parent volume>/<name>", the path the node plugin mounts. The volume
+// context holds the encryption mode of the parent volume.
+//
+// Parameters:
+//
+//	ctx          - The context of the request.
+//	in           - The CreateVolumeRequest.
+//	parentVolume - The name of the parent volume, from the parentVolume parameter.
+//	parameters   - The validated storage class parameters of the request.
+//	topology     - The accessible topology of the volume, nil if topology is disabled.
+//	secrets      - Secrets for authentication.
+//
+// Returns:
+//
+//	*csi.CreateVolumeResponse - The response with the created or existing directory volume.
+//	error                     - The gRPC status error, codes.InvalidArgument for volume content
+//	                            sources, mutable parameters or invalid names and capacities,
+//	                            codes.FailedPrecondition if the parent volume does not exist and
+//	                            codes.AlreadyExists if the directory exists with another quota.
+func (d *Driver) createDirectoryVolume(ctx context.Context, in *csi.CreateVolumeRequest, parentVolume string, parameters map[string]string, topology []*csi.Topology, secrets map[string]string) (*csi.CreateVolumeResponse, error) {
+	llog := d.requestLogger(ctx).WithValues("method", "CreateVolume", "parent_volume", parentVolume)
+	volumeName := in.GetName()
+
+	if in.GetVolumeContentSource() != nil {
+		llog.Error(errors.New("volume content sources are not supported"), InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, "volume content sources are not supported for volumes provisioned as directories of a parent volume")
+	}
+	if len(in.GetMutableParameters()) > 0 {
+		llog.Error(errors.New("mutable parameters are not supported"), InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, "mutable parameters are not supported for volumes provisioned as directories of a parent volume")
+	}
+	if err := utils.ValidateDirectoryName(volumeName); err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	cr, err := minCapacityRange(in.GetCapacityRange(), parameters)
+	if err != nil {
+		llog.Error(err, InvalidCapacityRangeErrorStr)
+		return nil, err
+	}
+	quotaBytes, err := directoryQuotaBytes(cr)
+	if err != nil {
+		llog.Error(err, InvalidCapacityRangeErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	parent, err := d.realm(ctx).GetVolume(ctx, parentVolume, secrets)
+	if err != nil {
+		llog.Error(err, "failed to get parent volume")
+		if errors.Is(err, pancli.ErrorNotFound) {
+			return nil, status.Errorf(codes.FailedPrecondition, "parent volume %s does not exist", parentVolume)
+		}
+		return nil, realmError(err)
+	}
+
+	dir, err := d.realm(ctx).CreateDirectory(ctx, parentVolume, volumeName, quotaBytes, secrets)
+	if errors.Is(err, pancli.ErrorAlreadyExist) {
+		// a retried request, or another volume with the same name
+		dir, err = d.realm(ctx).GetDirectory(ctx, parentVolume, volumeName, secrets)
+		if err == nil {
+			if err := directoryCapacityMatches(cr, dir); err != nil {
+				llog.Error(err, "directory already exists, but the capacity does not match", "volume_id", volumeName)
+				return nil, status.Error(codes.AlreadyExists, "Volume capacity does not match: "+err.Error())
+			}
+			llog.Info("directory already exists", "volume_id", dir.VolumeID())
+		}
+	}
+	if err != nil {
+		llog.Error(err, "failed to create directory", "volume_name", volumeName)
+		if errors.Is(err, pancli.ErrorOutOfRange) {
+			return nil, d.quotaLimitError(err)
+		}
+		return nil, realmError(err)
+	}
+
+	volumeID := utils.MakeDirectoryVolumeID(parentVolume, volumeName)
+	llog.Info("directory volume created", "volume_id", volumeID, "capacity", dir.GetHardQuotaBytes())
+	return &csi.CreateVolumeResponse{
+		Volume: &csi.Volume{
+			CapacityBytes:      dir.GetHardQuotaBytes(),
+			VolumeId:           volumeID,
+			VolumeContext:      d.volumeContext(parent, parameters),
+			AccessibleTopology: topology,
+		},
+	}, nil
+}
+
+// expandDirectoryVolume raises the directory quota of a directory volume to the required size.
+// Directories are never shrunk; requests at the current size only read it back.
+//
+// Parameters:
+//
+//	ctx           - The context of the request.
+//	volumeID      - The ID of the directory volume.
+//	parentVolume  - The name of the parent volume.
+//	directory     - The name of the directory.
+//	capacityRange - The requested capacity range.
+//	secrets       - Secrets for authentication.
+//
+// Returns:
+//
+//	int64 - The directory quota in bytes as read back from the realm.
+//	error - Returns errVolumeShrink if the required size is below the current size, or an
+//	        error if the directory cannot be read or the quota cannot be changed.
+func (d *Driver) expandDirectoryVolume(ctx context.Context, volumeID, parentVolume, directory string, capacityRange *csi.CapacityRange, secrets map[string]string) (int64, error) {
+	dir, err := d.realm(ctx).GetDirectory(ctx, parentVolume, directory, secrets)
+	if err != nil {
+		return 0, err
+	}
+
+	requiredBytes := capacityRange.GetRequiredBytes()
+	current := dir.GetHardQuotaBytes()
+	if requiredBytes < current-dir.QuotaUnit.ToBytes(0.01) {
+		return 0, fmt.Errorf("%w: volume %s has %d bytes, %d bytes requested", errVolumeShrink, volumeID, current, requiredBytes)
+	}
+	if requiredBytes <= current {
+		return current, nil
+	}
+
+	if err := d.realm(ctx).SetDirectoryQuota(ctx, parentVolume, directory, requiredBytes, secrets); err != nil {
+		return 0, err
+	}
+
+	dir, err = d.realm(ctx).GetDirectory(ctx, parentVolume, directory, secrets)
+	if err != nil {
+		// the quota is raised, only its size cannot be confirmed
+		d.requestLogger(ctx).Error(err, "failed to read the quota of the expanded directory, reporting the requested size", "volume_id", volumeID)
+		return requiredBytes, nil
+	}
+	return dir.GetHardQuotaBytes(), nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const directoryParentVolume = "shared"
+
+func directoryVolumeRequest(requiredBytes int64) *csi.CreateVolumeRequest {
+	return &csi.CreateVolumeRequest{
+		Name:               validVolumeName,
+		Secrets:            defaultSecrets,
+		CapacityRange:      &csi.CapacityRange{RequiredBytes: requiredBytes},
+		VolumeCapabilities: []*csi.VolumeCapability{mountCapability()},
+		Parameters: map[string]string{
+			utils.VolumeParameters.GetSCKey("parentVolume"): directoryParentVolume,
+		},
+	}
+}
+
+func TestCreateDirectoryVolume(t *testing.T) {
+	parent := &utils.Volume{Name: directoryParentVolume, State: utils.VolumeStateOnline, Encryption: "on"}
+	volumeID := directoryParentVolume + "/" + validVolumeName
+
+	t.Run("Created", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pancliMock := mock.NewMockStorageProviderClient(ctrl)
+		d := &Driver{panfs: pancliMock}
+
+		pancliMock.EXPECT().GetVolume(gomock.Any(), directoryParentVolume, defaultSecrets).Return(parent, nil)
+		pancliMock.EXPECT().CreateDirectory(gomock.Any(), directoryParentVolume, validVolumeName, int64(2<<30), defaultSecrets).
+			Return(&utils.Directory{Name: validVolumeName, VolumeName: directoryParentVolume, Hard: 2}, nil)
+
+		resp, err := d.CreateVolume(t.Context(), directoryVolumeRequest(2<<30))
+		require.NoError(t, err)
+		assert.Equal(t, volumeID, resp.Volume.VolumeId)
+		assert.Equal(t, int64(2<<30), resp.Volume.CapacityBytes)
+		assert.Equal(t, "on", resp.Volume.VolumeContext[utils.VolumeParameters.GetSCKey("encryption")])
+	})
+
+	t.Run("Existing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pancliMock := mock.NewMockStorageProviderClient(ctrl)
+		d := &Driver{panfs: pancliMock}
+
+		pancliMock.EXPECT().GetVolume(gomock.Any(), directoryParentVolume, defaultSecrets).Return(parent, nil).Times(2)
+		pancliMock.EXPECT().CreateDirectory(gomock.Any(), directoryParentVolume, validVolumeName, gomock.Any(), defaultSecrets).
+			Return(nil, pancli.ErrorAlreadyExist).Times(2)
+		pancliMock.EXPECT().GetDirectory(gomock.Any(), directoryParentVolume, validVolumeName, defaultSecrets).
+			Return(&utils.Directory{Name: validVolumeName, VolumeName: directoryParentVolume, Hard: 2}, nil).Times(2)
+
+		resp, err := d.CreateVolume(t.Context(), directoryVolumeRequest(2<<30))
+		require.NoError(t, err)
+		assert.Equal(t, volumeID, resp.Volume.VolumeId)
+
+		_, err = d.CreateVolume(t.Context(), directoryVolumeRequest(3<<30))
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("ParentNotFound", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pancliMock := mock.NewMockStorageProviderClient(ctrl)
+		d := &Driver{panfs: pancliMock}
+
+		pancliMock.EXPECT().GetVolume(gomock.Any(), directoryParentVolume, defaultSecrets).Return(nil, pancli.ErrorNotFound)
+
+		_, err := d.CreateVolume(t.Context(), directoryVolumeRequest(2<<30))
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("InvalidRequests", func(t *testing.T) {
+		d := &Driver{panfs: mock.NewMockStorageProviderClient(gomock.NewController(t))}
+
+		withSource := directoryVolumeRequest(2 << 30)
+		withSource.VolumeContentSource = &csi.VolumeContentSource{Type: &csi.VolumeContentSource_Volume{
+			Volume: &csi.VolumeContentSource_VolumeSource{VolumeId: "source"},
+		}}
+		_, err := d.CreateVolume(t.Context(), withSource)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = d.CreateVolume(t.Context(), directoryVolumeRequest(0))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		nestedParent := directoryVolumeRequest(2 << 30)
+		nestedParent.Parameters[utils.VolumeParameters.GetSCKey("parentVolume")] = "shared/dir"
+		_, err = d.CreateVolume(t.Context(), nestedParent)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestDeleteDirectoryVolume(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	pancliMock := mock.NewMockStorageProviderClient(ctrl)
+	// the deletion of directories is not verified
+	d := &Driver{panfs: pancliMock, deleteVerifyAttempts: 3}
+
+	volumeID := directoryParentVolume + "/" + validVolumeName
+	gomock.InOrder(
+		pancliMock.EXPECT().DeleteDirectory(gomock.Any(), directoryParentVolume, validVolumeName, defaultSecrets).Return(nil),
+		pancliMock.EXPECT().DeleteDirectory(gomock.Any(), directoryParentVolume, validVolumeName, defaultSecrets).Return(pancli.ErrorNotFound),
+	)
+
+	for range 2 {
+		_, err := d.DeleteVolume(t.Context(), &csi.DeleteVolumeRequest{VolumeId: volumeID, Secrets: defaultSecrets})
+		assert.NoError(t, err)
+	}
+}
+
+func TestControllerExpandDirectoryVolume(t *testing.T) {
+	volumeID := directoryParentVolume + "/" + validVolumeName
+	expand := func(d *Driver, requiredBytes int64) (*csi.ControllerExpandVolumeResponse, error) {
+		return d.ControllerExpandVolume(t.Context(), &csi.ControllerExpandVolumeRequest{
+			VolumeId:      volumeID,
+			CapacityRange: &csi.CapacityRange{RequiredBytes: requiredBytes},
+			Secrets:       defaultSecrets,
+		})
+	}
+
+	t.Run("Expanded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pancliMock := mock.NewMockStorageProviderClient(ctrl)
+		d := &Driver{panfs: pancliMock}
+
+		gomock.InOrder(
+			pancliMock.EXPECT().GetDirectory(gomock.Any(), directoryParentVolume, validVolumeName, defaultSecrets).
+				Return(&utils.Directory{Name: validVolumeName, Hard: 2}, nil),
+			pancliMock.EXPECT().SetDirectoryQuota(gomock.Any(), directoryParentVolume, validVolumeName, int64(4<<30), defaultSecrets).Return(nil),
+			pancliMock.EXPECT().GetDirectory(gomock.Any(), directoryParentVolume, validVolumeName, defaultSecrets).
+				Return(&utils.Directory{Name: validVolumeName, Hard: 4}, nil),
+		)
+
+		resp, err := expand(d, 4<<30)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4<<30), resp.CapacityBytes)
+	})
+
+	t.Run("Shrink", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pancliMock := mock.NewMockStorageProviderClient(ctrl)
+		d := &Driver{panfs: pancliMock}
+
+		pancliMock.EXPECT().GetDirectory(gomock.Any(), directoryParentVolume, validVolumeName, defaultSecrets).
+			Return(&utils.Directory{Name: validVolumeName, Hard: 4}, nil)
+
+		_, err := expand(d, 2<<30)
+		assert.Equal(t, codes.OutOfRange, status.Code(err))
+	})
+}
+
+func TestDirectoryVolumeUnsupportedOperations(t *testing.T) {
+	d := &Driver{panfs: mock.NewMockStorageProviderClient(gomock.NewController(t))}
+	volumeID := directoryParentVolume + "/" + validVolumeName
+
+	_, err := d.CreateSnapshot(t.Context(), &csi.CreateSnapshotRequest{Name: "snapshot-1", SourceVolumeId: volumeID, Secrets: defaultSecrets})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = d.ControllerModifyVolume(t.Context(), &csi.ControllerModifyVolumeRequest{
+		VolumeId:          volumeID,
+		MutableParameters: map[string]string{utils.VolumeParameters.GetSCKey("description"): "data"},
+		Secrets:           defaultSecrets,
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -23,14 +23,14 @@ import (
 	"fmt"
 	"net"
 	"os"
-	"os/signal"
-	"syscall"
+	"sync"
+	"time"
 
 	csi "github.com/container-storage-interface/spec/lib/go/csi"
-	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
-	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/interfaces"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/realm"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/reflection"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
@@ -39,23 +39,13 @@ import (
 	"k8s.io/client-go/rest"
 )
 
-//go:generate mockgen -source=driver.go -destination=mock/mock_driver.go -package=mock StorageProviderClient PanMounter
+// StorageProviderClient defines an interface for managing volumes with a storage provider,
+// see interfaces.StorageProviderClient.
+type StorageProviderClient = interfaces.StorageProviderClient
 
-// StorageProviderClient defines an interface for managing volumes with a storage provider.
-type StorageProviderClient interface {
-	CreateVolume(volumeName string, params pancli.VolumeCreateParams, secret map[string]string) (*utils.Volume, error)
-	DeleteVolume(volID string, secret map[string]string) error
-	ExpandVolume(volumeName string, targetSize int64, secret map[string]string) error
-	ListVolumes(secret map[string]string) (*utils.VolumeList, error)
-	GetVolume(volumeName string, secret map[string]string) (*utils.Volume, error)
-}
-
-// PanMounter defines the interface for mounting and unmounting PanFS volumes.
-type PanMounter interface {
-	Mount(source string, target string, options []string) error
-	BindMount(source string, target string, options []string) error
-	Unmount(target string) error
-}
+// PanMounter defines the interface for mounting and unmounting PanFS volumes, see
+// interfaces.PanMounter.
+type PanMounter = interfaces.PanMounter
 
 // Driver represents the CSI driver for PanFS, implementing identity, controller, and node services.
 type Driver struct {
@@ -67,10 +57,61 @@ type Driver struct {
 	log        klog.Logger
 	mounterV2  PanMounter
 	panfs      StorageProviderClient
-	kubeClient *kubernetes.Clientset
+	kubeClient kubernetes.Interface
+	mode       PluginMode
 
 	tempFileFactory TempFileFactory
 
+	slowRPCThreshold time.Duration
+	slo              sloTracker
+	inflight         inflightOps
+	mountProfiles    MountProfiles
+	mounts           mountTracker
+	targetLocks      targetLocks
+	volumeLocks      volumeLocks
+	unmounts         unmountPool
+
+	pvcAnnotationParameters []string
+	namespacePolicy         NamespacePolicy
+	canaryVolume            string
+	verifyMounts            bool
+	dataPath                dataPathChecker
+	stagedMounts            bool
+	ephemeral               *ephemeralVolumes
+	targetDirPermissions    *TargetDirPermissions
+	topology                topologyConfig
+
+	deleteVerifyAttempts int
+	deleteVerifyInterval time.Duration
+
+	maxVolumeContextSize int
+	expansionStep        int64
+	volumeNames          VolumeNameMapper
+
+	realmLimiter realmLimiter
+
+	encryptionMismatchPolicy string
+	kmipSecretCheck          string
+	credentials              *credentialCache
+	defaultCredentials       string
+	defaultSecretPath        string
+	forbidPasswordAuth       bool
+	realms                   *realm.Registry
+	attachmentNamespace      string
+	quotaRefresh             quotaRefreshConfig
+	capabilityRealms         []string
+	advertisedCapabilities   []csi.ControllerServiceCapability_RPC_Type
+
+	labelReconciler nodeLabelReconciler
+	nodeCleaner     staleNodeCleaner
+	volumeStats     volumeStatsCollector
+
+	// server is the gRPC server while the driver is running
+	server        *grpc.Server
+	serverMu      sync.Mutex
+	listen        ListenFunc
+	serverOptions []grpc.ServerOption
+
 	csi.UnimplementedIdentityServer
 	csi.UnimplementedControllerServer
 	csi.UnimplementedNodeServer
@@ -79,15 +120,38 @@ type Driver struct {
 // Exportable constants
 const (
 	// EphemeralK8SVolumeContext is a volume context key which indicating that k8s requests ephemeral volume. CSI PanFS
-	// plugin does not support ephemeral volumes for now
+	// plugin supports inline ephemeral volumes only if enabled with WithEphemeralVolumes
 	EphemeralK8SVolumeContext = "csi.storage.k8s.io/ephemeral"
 )
 
 // Volume parameters constants
 const (
 	DefaultDriverName string = "com.vdura.csi.panfs"
+
+	// ReconcileCapacityExpand is the value of the reconcileCapacity storage class parameter which
+	// makes CreateVolume expand an existing volume with a lower soft quota instead of failing.
+	ReconcileCapacityExpand = "expand"
 )
 
+// Option configures optional Driver behavior in CreateDriver.
+type Option func(*Driver)
+
+// WithSlowRPCThreshold sets the duration after which an RPC is logged as slow and counted
+// in the slow RPC metric. A zero or negative threshold disables slow RPC reporting.
+//
+// Parameters:
+//
+//	threshold - The slow RPC threshold.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithSlowRPCThreshold(threshold time.Duration) Option {
+	return func(d *Driver) {
+		d.slowRPCThreshold = threshold
+	}
+}
+
 // FileWriter defines an interface for writing to files.
 type FileWriter interface {
 	Write([]byte) (int, error)
@@ -130,6 +194,7 @@ func (w *osFileWrapper) Name() string { return w.File.Name() }
 //	panfs      - The StorageProviderClient implementation for PanFS operations.
 //	log        - The logger instance for logging.
 //	mounterV2  - The PanMounter implementation for mount operations.
+//	opts       - Optional driver settings.
 //
 // Returns:
 //
@@ -139,6 +204,7 @@ func CreateDriver(
 	panfs StorageProviderClient,
 	log klog.Logger,
 	mounterV2 PanMounter,
+	opts ...Option,
 ) *Driver {
 	log.Info("creating driver", "driver_name", driverName, "endpoint", endpoint, "version", version)
 	host, err := os.Hostname()
@@ -147,97 +213,114 @@ func CreateDriver(
 		return nil
 	}
 
-	var kubeClient *kubernetes.Clientset
+	var kubeClient kubernetes.Interface
 
 	// If CSI_SANITY_MODE is not set to true, do not initialize kubeClient
 	// This is useful for running csi-sanity tests which do not require kubeClient
 	// and do not have access to in-cluster config
-	if os.Getenv("CSI_SANITY_MODE") == "true" {
-		kubeClient = nil
-	} else {
+	if os.Getenv("CSI_SANITY_MODE") != "true" {
 		// Initialize Kubernetes client
-		config, err := rest.InClusterConfig()
-		if err != nil {
-			log.Error(err, "failed to get in-cluster kubeconfig")
-			return nil
-		}
-		kubeClient, err = kubernetes.NewForConfig(config)
+		clientset, err := NewInClusterKubeClient()
 		if err != nil {
 			log.Error(err, "failed to create kube client")
 			return nil
 		}
+		kubeClient = clientset
+	}
+
+	d := &Driver{
+		Version:              version,
+		Name:                 driverName,
+		endpoint:             endpoint,
+		mounterV2:            mounterV2,
+		log:                  log,
+		host:                 host,
+		panfs:                panfs,
+		kubeClient:           kubeClient,
+		tempFileFactory:      &osTempFileFactory{},
+		slowRPCThreshold:     DefaultSlowRPCThreshold,
+		maxVolumeContextSize: DefaultMaxVolumeContextSize,
+
+		encryptionMismatchPolicy: EncryptionMismatchDelete,
+		kmipSecretCheck:          KMIPSecretCheckWarn,
+	}
+
+	for _, opt := range opts {
+		opt(d)
 	}
 
-	return &Driver{
-		Version:         version,
-		Name:            driverName,
-		endpoint:        endpoint,
-		mounterV2:       mounterV2,
-		log:             log,
-		host:            host,
-		panfs:           panfs,
-		kubeClient:      kubeClient,
-		tempFileFactory: &osTempFileFactory{},
+	return d
+}
+
+// NewInClusterKubeClient creates a Kubernetes client using the in-cluster configuration
+// of the pod the driver runs in.
+//
+// Returns:
+//
+//	*kubernetes.Clientset - The Kubernetes client.
+//	error                 - Error if the in-cluster config cannot be loaded or the client cannot be created.
+func NewInClusterKubeClient() (*kubernetes.Clientset, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get in-cluster kubeconfig: %w", err)
 	}
+	return kubernetes.NewForConfig(config)
 }
 
-// Run starts the gRPC server and listens for incoming CSI requests.
+// ErrDriverRunning is returned by Run if the driver is already serving requests.
+var ErrDriverRunning = errors.New("driver is already running")
+
+// Run starts the gRPC server and serves CSI requests until the context is cancelled or
+// Stop is called. The driver may be run again once Run has returned.
+//
+// Parameters:
+//
+//	ctx - The context controlling the lifetime of the server.
 //
 // Returns:
 //
 //	error - Returns an error if the server fails to start, listen, or shut down gracefully.
 //
 // Error Cases:
+//   - ErrDriverRunning if Run is called while the driver is running.
+//   - Failure of the node self-test, see WithCanaryVolume.
 //   - Failure to remove the endpoint address before starting.
 //   - Failure to listen on the endpoint address.
-//   - Failure to serve or gracefully stop the gRPC server.
-func (d *Driver) Run() error {
-	d.log.Info("starting gRPC server")
-
-	if err := os.Remove(d.endpoint); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove address %s: %v", d.endpoint, err)
+//   - Failure to serve the gRPC server.
+func (d *Driver) Run(ctx context.Context) error {
+	if err := d.canarySelfTest(); err != nil {
+		return fmt.Errorf("node self-test failed: %w", err)
 	}
 
-	lis, err := net.Listen("unix", d.endpoint)
+	grpcServer, lis, err := d.startServer()
 	if err != nil {
-		return fmt.Errorf("failed to listen: %v", err)
+		return err
 	}
 
-	grpcServer := grpc.NewServer()
-	csi.RegisterIdentityServer(grpcServer, d)
-	csi.RegisterControllerServer(grpcServer, d)
-	csi.RegisterNodeServer(grpcServer, d)
-
-	reflection.Register(grpcServer)
+	d.log.Info("successfully registered services", "address", d.endpoint)
 
-	shutdownError := make(chan error)
+	d.startStaleNodeCleaner()
+	d.startSLOReporter()
+	d.startVolumeStatsCollector()
 
+	served := make(chan struct{})
+	defer close(served)
 	go func() {
-		quit := make(chan os.Signal, 1)
-		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-		s := <-quit
-
-		d.log.Info("shutting down server", "signal", s.String())
-
-		// Unset the node label when shutting down
-		if err := d.updateNodeLabel(NodeLabelKey, ""); err != nil {
-			d.log.Error(err, "failed to remove node label")
+		select {
+		case <-ctx.Done():
+			d.log.Info("shutting down server", "reason", context.Cause(ctx).Error())
+			d.Stop()
+		case <-served:
 		}
-
-		grpcServer.GracefulStop()
-		shutdownError <- nil
 	}()
 
-	d.log.Info("successfully registered services", "address", d.endpoint)
-
-	// Serve gRPC server
 	err = grpcServer.Serve(lis)
-	if !errors.Is(err, grpc.ErrServerStopped) {
-		return err
-	}
 
-	err = <-shutdownError
-	if err != nil {
+	d.serverMu.Lock()
+	d.server = nil
+	d.serverMu.Unlock()
+
+	if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
 		return err
 	}
 
@@ -246,6 +329,56 @@ func (d *Driver) Run() error {
 	return nil
 }
 
+// startServer creates the gRPC server and the endpoint listener, marking the driver as running.
+func (d *Driver) startServer() (*grpc.Server, net.Listener, error) {
+	d.serverMu.Lock()
+	defer d.serverMu.Unlock()
+
+	if d.server != nil {
+		return nil, nil, ErrDriverRunning
+	}
+
+	d.log.Info("starting gRPC server", "driver_name", d.Name, "version", d.Version, "mode", d.pluginMode())
+
+	lis, err := d.listenEndpoint()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d.registerMountMetrics()
+	metrics.PluginInfo.Reset()
+	metrics.PluginInfo.WithLabelValues(d.Name, d.Version, string(d.pluginMode())).Set(1)
+
+	grpcServer := d.newServer()
+	d.server = grpcServer
+	return grpcServer, lis, nil
+}
+
+// Stop removes the node readiness label and gracefully stops the gRPC server, waiting for
+// pending RPCs to finish. Run returns once the server is stopped. Stop does nothing if the
+// driver is not running.
+func (d *Driver) Stop() {
+	d.serverMu.Lock()
+	grpcServer := d.server
+	d.serverMu.Unlock()
+
+	if grpcServer == nil {
+		return
+	}
+
+	d.stopStaleNodeCleaner()
+	d.stopSLOReporter()
+	d.stopVolumeStatsCollector()
+
+	// Unset the node label when shutting down, without re-applying it
+	d.stopNodeLabelReconciler()
+	if err := d.updateNodeLabel(NodeLabelKey, ""); err != nil {
+		d.log.Error(err, "failed to remove node label")
+	}
+
+	grpcServer.GracefulStop()
+}
+
 // updateNodeLabel sets or removes a label on the Kubernetes node where the driver is running.
 //
 // Parameters:
@@ -0,0 +1,89 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/klog/v2"
+)
+
+// TestDriverRunStop verifies that the driver serves requests until its context is cancelled
+// or Stop is called, rejects concurrent runs and can be run again after stopping.
+func TestDriverRunStop(t *testing.T) {
+	// unix socket paths are limited in length, t.TempDir may be too long
+	dir, err := os.MkdirTemp("", "csi")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	d := &Driver{
+		Name:     DefaultDriverName,
+		Version:  "testing",
+		endpoint: filepath.Join(dir, "csi.sock"),
+		log:      klog.Background(),
+	}
+
+	start := func(ctx context.Context) <-chan error {
+		done := make(chan error, 1)
+		go func() { done <- d.Run(ctx) }()
+		assert.Eventually(t, func() bool {
+			conn, err := grpc.NewClient("unix://"+d.endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				return false
+			}
+			defer func() { _ = conn.Close() }()
+			_, err = csi.NewIdentityClient(conn).Probe(ctx, &csi.ProbeRequest{})
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		return done
+	}
+
+	wait := func(done <-chan error) {
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("driver did not stop")
+		}
+	}
+
+	t.Run("ContextCancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		done := start(ctx)
+
+		assert.ErrorIs(t, d.Run(ctx), ErrDriverRunning)
+
+		cancel()
+		wait(done)
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		done := start(t.Context())
+		d.Stop()
+		wait(done)
+
+		// stopping a stopped driver is a no-op
+		d.Stop()
+	})
+}
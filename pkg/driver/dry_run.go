@@ -0,0 +1,140 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// Operations supported by DryRun.
+const (
+	// DryRunDelete predicts a DeleteVolume request.
+	DryRunDelete = "delete"
+	// DryRunExpand predicts a ControllerExpandVolume request.
+	DryRunExpand = "expand"
+)
+
+// CommandRecorder records the realm commands which were not executed, e.g. a
+// pancli.DryRunRunner.
+type CommandRecorder interface {
+	Commands() []string
+}
+
+// DryRunRequest describes the operation predicted by DryRun.
+type DryRunRequest struct {
+	// Operation is DryRunDelete or DryRunExpand.
+	Operation string
+	// VolumeID is the volume handle of the PV.
+	VolumeID string
+	// RequiredBytes is the requested capacity of an expansion.
+	RequiredBytes int64
+	// Secrets are the realm secrets of the request.
+	Secrets map[string]string
+}
+
+// DryRunResult is the machine-readable prediction of DryRun.
+type DryRunResult struct {
+	Operation     string   `json:"operation"`
+	Realm         string   `json:"realm"`
+	VolumeID      string   `json:"volume_id"`
+	VolumeExists  bool     `json:"volume_exists"`
+	CurrentBytes  int64    `json:"current_bytes,omitempty"`
+	RequiredBytes int64    `json:"required_bytes,omitempty"`
+	Commands      []string `json:"commands"`
+	Code          string   `json:"code"`
+	Message       string   `json:"message,omitempty"`
+}
+
+// DryRun predicts the outcome of a DeleteVolume or ControllerExpandVolume request by
+// running the production request handling against a storage provider which does not
+// execute mutating realm commands, e.g. a PancliSSHClient using a pancli.DryRunRunner. The
+// volume is read from the realm, the commands which would change it are reported as
+// rendered by the storage provider. The predicted outcome assumes the realm accepts these
+// commands.
+//
+// Parameters:
+//
+//	ctx      - The context of the prediction.
+//	panfs    - The storage provider, which must not execute mutating commands.
+//	recorder - The recorder of the commands which were not executed.
+//	req      - The predicted operation.
+//	log      - The logger of the request handling.
+//
+// Returns:
+//
+//	DryRunResult - The rendered commands and the predicted gRPC status of the request.
+//	error        - Error if the operation is not supported.
+func DryRun(ctx context.Context, panfs StorageProviderClient, recorder CommandRecorder, req DryRunRequest, log klog.Logger) (DryRunResult, error) {
+	if req.Operation != DryRunDelete && req.Operation != DryRunExpand {
+		return DryRunResult{}, fmt.Errorf("invalid dry-run operation %q: must be %s or %s", req.Operation, DryRunDelete, DryRunExpand)
+	}
+
+	result := DryRunResult{
+		Operation:     req.Operation,
+		Realm:         req.Secrets[utils.RealmConnectionContext.RealmAddress],
+		VolumeID:      req.VolumeID,
+		RequiredBytes: req.RequiredBytes,
+		Commands:      []string{},
+	}
+
+	volume, err := panfs.GetVolume(ctx, req.VolumeID, req.Secrets)
+	switch {
+	case err == nil:
+		result.VolumeExists = true
+		result.CurrentBytes = volume.GetSoftQuotaBytes()
+	case errors.Is(err, pancli.ErrorNotFound):
+		if req.Operation == DryRunExpand {
+			// the realm rejects the expansion of a missing volume
+			result.Code = codes.NotFound.String()
+			result.Message = VolumeNotFoundErrorStr
+			return result, nil
+		}
+	case errors.Is(err, pancli.ErrorUnauthenticated):
+		result.Code = codes.Unauthenticated.String()
+		result.Message = err.Error()
+		return result, nil
+	default:
+		result.Code = codes.Unavailable.String()
+		result.Message = err.Error()
+		return result, nil
+	}
+
+	d := &Driver{panfs: panfs, log: log}
+	switch req.Operation {
+	case DryRunDelete:
+		_, err = d.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: req.VolumeID, Secrets: req.Secrets})
+	case DryRunExpand:
+		_, err = d.ControllerExpandVolume(ctx, &csi.ControllerExpandVolumeRequest{
+			VolumeId:      req.VolumeID,
+			CapacityRange: &csi.CapacityRange{RequiredBytes: req.RequiredBytes},
+			Secrets:       req.Secrets,
+		})
+	}
+
+	st := status.Convert(err)
+	result.Code = st.Code().String()
+	result.Message = st.Message()
+	result.Commands = append(result.Commands, recorder.Commands()...)
+	return result, nil
+}
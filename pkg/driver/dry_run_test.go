@@ -0,0 +1,124 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package driver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli/fake"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/klog/v2"
+)
+
+// TestDryRun verifies that DryRun renders the realm commands of the request handling and
+// predicts its outcome without running the commands.
+func TestDryRun(t *testing.T) {
+	volume := &utils.Volume{ID: "372", Name: utils.VolumeName(validVolumeName), State: utils.VolumeStateOnline, Soft: 1}
+	out, err := volume.MarshalVolumeToPasXML()
+	require.NoError(t, err)
+	getCmd := "pasxml volumes volume " + validVolumeName
+	notFound := fmt.Errorf("%w: No volume with name %s", pancli.ErrorNotFound, validVolumeName)
+
+	tests := []struct {
+		name      string
+		req       DryRunRequest
+		volumeErr error
+		// times the volume is read, 1 if unset
+		volumeReads  int
+		wantCode     string
+		wantCommands []string
+	}{
+		{
+			name:         "Delete",
+			req:          DryRunRequest{Operation: DryRunDelete},
+			wantCode:     "OK",
+			wantCommands: []string{"volume delete -f " + validVolumeName},
+		},
+		{
+			name:         "DeleteMissingVolume",
+			req:          DryRunRequest{Operation: DryRunDelete},
+			volumeErr:    notFound,
+			wantCode:     "OK",
+			wantCommands: []string{"volume delete -f " + validVolumeName},
+		},
+		{
+			name:         "Expand",
+			req:          DryRunRequest{Operation: DryRunExpand, RequiredBytes: 2 << 30},
+			volumeReads:  3,
+			wantCode:     "OK",
+			wantCommands: []string{"volume set soft-quota " + validVolumeName + " 2.00"},
+		},
+		{
+			name:         "ExpandShrink",
+			req:          DryRunRequest{Operation: DryRunExpand, RequiredBytes: 1 << 29},
+			volumeReads:  2,
+			wantCode:     "OutOfRange",
+			wantCommands: []string{},
+		},
+		{
+			name:         "ExpandMissingVolume",
+			req:          DryRunRequest{Operation: DryRunExpand, RequiredBytes: 2 << 30},
+			volumeErr:    notFound,
+			wantCode:     "NotFound",
+			wantCommands: []string{},
+		},
+		{
+			name:         "ExpandWithoutCapacity",
+			req:          DryRunRequest{Operation: DryRunExpand},
+			wantCode:     "InvalidArgument",
+			wantCommands: []string{},
+		},
+		{
+			name:         "Unauthenticated",
+			req:          DryRunRequest{Operation: DryRunDelete},
+			volumeErr:    fmt.Errorf("%w: permission denied", pancli.ErrorUnauthenticated),
+			wantCode:     "Unauthenticated",
+			wantCommands: []string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			runner := fake.NewRunner(t)
+			if tc.volumeErr != nil {
+				runner.Expect(getCmd).Return("", tc.volumeErr)
+			} else {
+				runner.Expect(getCmd).Return(string(out), nil).Times(max(tc.volumeReads, 1))
+			}
+			dryRun := pancli.NewDryRunRunner(runner)
+
+			req := tc.req
+			req.VolumeID = validVolumeName
+			req.Secrets = defaultSecrets
+			result, err := DryRun(t.Context(), pancli.NewPancliSSHClient(dryRun), dryRun, req, klog.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantCode, result.Code, result.Message)
+			assert.Equal(t, tc.wantCommands, result.Commands)
+			assert.Equal(t, "realm", result.Realm)
+			assert.Equal(t, tc.volumeErr == nil, result.VolumeExists)
+		})
+	}
+
+	t.Run("InvalidOperation", func(t *testing.T) {
+		_, err := DryRun(t.Context(), nil, nil, DryRunRequest{Operation: "create"}, klog.Background())
+		assert.ErrorContains(t, err, "invalid dry-run operation")
+	})
+}
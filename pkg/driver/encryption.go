@@ -0,0 +1,89 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"fmt"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// Handling of volumes created by the realm with a different encryption mode than requested.
+const (
+	// EncryptionMismatchDelete deletes the created volume and fails CreateVolume with codes.Internal,
+	// so that the request is retried.
+	EncryptionMismatchDelete = "delete"
+	// EncryptionMismatchFail keeps the created volume for inspection and fails CreateVolume
+	// with codes.FailedPrecondition.
+	EncryptionMismatchFail = "fail"
+)
+
+// WithEncryptionMismatchPolicy sets how CreateVolume handles a volume created by the realm with
+// a different encryption mode than requested, see EncryptionMismatchDelete and EncryptionMismatchFail.
+//
+// Parameters:
+//
+//	policy - The encryption mismatch policy.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithEncryptionMismatchPolicy(policy string) Option {
+	return func(d *Driver) {
+		d.encryptionMismatchPolicy = policy
+	}
+}
+
+// ValidateEncryptionMismatchPolicy checks that the encryption mismatch policy is supported.
+//
+// Parameters:
+//
+//	policy - The encryption mismatch policy.
+//
+// Returns:
+//
+//	error - Error if the policy is unknown.
+func ValidateEncryptionMismatchPolicy(policy string) error {
+	switch policy {
+	case EncryptionMismatchDelete, EncryptionMismatchFail:
+		return nil
+	default:
+		return fmt.Errorf("invalid encryption mismatch policy %q: must be %q or %q", policy, EncryptionMismatchDelete, EncryptionMismatchFail)
+	}
+}
+
+// encryptionMismatch compares the encryption mode of a volume with the requested one.
+//
+// Parameters:
+//
+//	parameters - The volume parameters of the request.
+//	vol        - The volume reported by the realm.
+//
+// Returns:
+//
+//	error - Error describing the mismatch, nil if the encryption mode matches the request.
+func encryptionMismatch(parameters map[string]string, vol *utils.Volume) error {
+	requested := parameters[utils.VolumeParameters.GetSCKey("encryption")] == "on"
+	encrypted := vol.GetEncryptionMode() != "" && vol.GetEncryptionMode() != "none"
+
+	switch {
+	case requested && !encrypted:
+		return fmt.Errorf("encryption was requested but volume %s has encryption mode %q", vol.Name, vol.GetEncryptionMode())
+	case !requested && encrypted:
+		return fmt.Errorf("encryption was not requested but volume %s has encryption mode %q", vol.Name, vol.GetEncryptionMode())
+	default:
+		return nil
+	}
+}
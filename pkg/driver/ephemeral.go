@@ -0,0 +1,289 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	csi "github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// DefaultEphemeralDir is the directory where the node plugin mounts the parent volumes of
+// CSI inline ephemeral volumes. It is below the kubelet directory, which the node plugin
+// mounts with bidirectional propagation, so the bind mounts of the pods see the parent mounts.
+const DefaultEphemeralDir = "/var/lib/kubelet/plugins/com.vdura.csi.panfs/ephemeral"
+
+// osRemoveAll removes a directory tree, replaced in tests.
+var osRemoveAll = os.RemoveAll
+
+// ephemeralVolumes is the configuration of CSI inline ephemeral volumes.
+type ephemeralVolumes struct {
+	// parentVolume is the realm volume holding the directories of the ephemeral volumes.
+	parentVolume string
+	// dir is the directory where the parent volumes are mounted, one per realm.
+	dir string
+	// mu serializes the mounts of the parent volumes.
+	mu sync.Mutex
+}
+
+// WithEphemeralVolumes enables CSI inline ephemeral volumes. The node plugin has no access
+// to the realm management interface, so an ephemeral volume is a directory of the parent
+// volume named after the volume id: NodePublishVolume mounts the parent volume once per
+// realm, creates the directory and bind mounts it into the pod, and NodeUnpublishVolume
+// removes the directory with its content. Without this option ephemeral volumes are
+// rejected.
+//
+// Parameters:
+//
+//	parentVolume - The realm volume holding the ephemeral volumes, e.g. "ephemeral".
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithEphemeralVolumes(parentVolume string) Option {
+	return func(d *Driver) {
+		d.ephemeral = &ephemeralVolumes{parentVolume: parentVolume, dir: DefaultEphemeralDir}
+	}
+}
+
+// isEphemeralVolume reports whether kubelet requests the publish of an inline ephemeral
+// volume. Kubelet only passes the ephemeral flag if the CSIDriver enables podInfoOnMount.
+func isEphemeralVolume(volumeContext map[string]string) bool {
+	return volumeContext[EphemeralK8SVolumeContext] == "true"
+}
+
+// validEphemeralVolumeID reports whether the volume id can name the directory of an
+// ephemeral volume, i.e. it is a single path element.
+func validEphemeralVolumeID(volumeID string) bool {
+	return volumeID != "" && volumeID != "." && volumeID != ".." && filepath.Base(volumeID) == volumeID
+}
+
+// parentPath returns the mount point of the parent volume on a realm.
+//
+// Parameters:
+//
+//	realmAddress - The realm address of the node-publish secret.
+//
+// Returns:
+//
+//	string - The mount point, below the ephemeral directory.
+//	error  - Error if the realm address is invalid.
+func (e *ephemeralVolumes) parentPath(realmAddress string) (string, error) {
+	addresses, err := utils.ParseRealmAddresses(realmAddress)
+	if err != nil {
+		return "", err
+	}
+	host, err := utils.ParseRealmAddress(addresses[0])
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(e.dir, host, e.parentVolume), nil
+}
+
+// publishEphemeralVolume publishes an inline ephemeral volume by bind mounting a directory
+// of the parent volume at the target path. The directory is created with the target
+// directory permissions, so pods may set its owner with the targetDirUID and targetDirGID
+// volume attributes.
+//
+// Parameters:
+//
+//	ctx  - The context of the request.
+//	llog - The logger of the request.
+//	in   - The NodePublishVolumeRequest with a valid volume id.
+//
+// Returns:
+//
+//	*csi.NodePublishVolumeResponse - The response on success.
+//	error - Returns an error for invalid input, unsupported capabilities or mount failures.
+func (d *Driver) publishEphemeralVolume(ctx context.Context, llog klog.Logger, in *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
+	volumeID := in.GetVolumeId()
+	if !validEphemeralVolumeID(volumeID) {
+		llog.Error(fmt.Errorf("invalid ephemeral volume id %q", volumeID), InvalidRequestErrorStr)
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid ephemeral volume id %q", volumeID)
+	}
+
+	secrets, err := d.volumeSecrets(in.GetSecrets(), in.GetVolumeContext())
+	if err != nil {
+		llog.Error(err, "failed to resolve the realm secrets")
+		return nil, err
+	}
+	if _, err := d.parseRealmSecrets(secrets); err != nil {
+		llog.Error(err, InvalidRequestSecretsErrorStr)
+		return nil, status.Error(codes.InvalidArgument, InvalidRequestSecretsErrorStr)
+	}
+
+	publishTargetPath, err := validTargetPath("Target Path", in.GetTargetPath())
+	if err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// publish and unpublish of the same target must not interleave
+	defer d.targetLocks.lock(publishTargetPath, "publish")()
+
+	volumeCapability := in.GetVolumeCapability()
+	if volumeCapability == nil {
+		llog.Error(fmt.Errorf("volume capability must not be empty"), InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, "Volume Capability must be provided")
+	}
+
+	if !d.isSupportedCapability(volumeCapability) {
+		llog.Error(fmt.Errorf("unsupported volume capability"), "unsupported volume capability provided",
+			"volume_capability", volumeCapability)
+		return nil, status.Error(codes.FailedPrecondition, "unsupported volume capability provided")
+	}
+
+	targetDirPerms, err := d.publishTargetDirPermissions(in.GetVolumeContext())
+	if err != nil {
+		llog.Error(err, "invalid target directory permissions requested")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	realmAddress := secrets[utils.RealmConnectionContext.RealmAddress]
+	parentPath, err := d.ephemeral.parentPath(realmAddress)
+	if err != nil {
+		llog.Error(err, InvalidRequestSecretsErrorStr)
+		return nil, status.Error(codes.InvalidArgument, InvalidRequestSecretsErrorStr)
+	}
+
+	if err := d.checkDataPath(realmAddress); err != nil {
+		llog.Error(err, "realm data path check failed", "volume_id", volumeID)
+		return nil, err
+	}
+
+	if err := d.mountEphemeralParent(ctx, realmAddress, parentPath); err != nil {
+		d.mounts.failed("publish")
+		llog.Error(err, "failed to mount parent volume of ephemeral volumes",
+			"parent_volume", d.ephemeral.parentVolume,
+			"parent_path", parentPath)
+		return nil, status.Errorf(codes.Internal, "Failed to mount parent volume %s: %v", d.ephemeral.parentVolume, err)
+	}
+
+	volumePath := filepath.Join(parentPath, volumeID)
+	if err := prepareTargetDir(volumePath, targetDirPerms); err != nil {
+		llog.Error(err, "failed to create ephemeral volume directory", "volume_path", volumePath)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := prepareTargetDir(publishTargetPath, targetDirPerms); err != nil {
+		llog.Error(err, "failed to create target directory", "publish_target_path", publishTargetPath)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	var mountOptions []string
+	if in.GetReadonly() {
+		mountOptions = append(mountOptions, "ro")
+	}
+	if err := d.mounterV2.BindMount(ctx, volumePath, publishTargetPath, mountOptions); err != nil {
+		d.mounts.failed("publish")
+		llog.Error(err, "failed to publish ephemeral volume",
+			"volume_id", volumeID,
+			"volume_path", volumePath,
+			"publish_target_path", publishTargetPath,
+			"mount_options", mountOptions)
+		return nil, status.Error(codes.Internal, "Failed to publish volume: "+err.Error())
+	}
+
+	d.mounts.published(volumeID, publishTargetPath)
+
+	llog.Info("successfully published ephemeral volume",
+		"volume_id", volumeID,
+		"volume_path", volumePath,
+		"publish_path", publishTargetPath)
+	return &csi.NodePublishVolumeResponse{}, nil
+}
+
+// mountEphemeralParent mounts the parent volume of the ephemeral volumes of a realm unless
+// it is mounted already.
+//
+// Parameters:
+//
+//	ctx          - The context of the request.
+//	realmAddress - The realm address of the node-publish secret.
+//	parentPath   - The mount point of the parent volume.
+//
+// Returns:
+//
+//	error - Error if the mount point cannot be checked or the volume cannot be mounted.
+func (d *Driver) mountEphemeralParent(ctx context.Context, realmAddress, parentPath string) error {
+	d.ephemeral.mu.Lock()
+	defer d.ephemeral.mu.Unlock()
+
+	mounted, err := d.mounterV2.IsMountPoint(ctx, parentPath)
+	if err != nil {
+		return err
+	}
+	if mounted {
+		return nil
+	}
+
+	return d.mountRealmVolume(ctx, realmAddress, d.ephemeral.parentVolume, parentPath, nil)
+}
+
+// removeEphemeralVolume removes the directories of an ephemeral volume from the mounted
+// parent volumes. NodeUnpublishVolume does not tell ephemeral volumes apart, so volumes
+// without a directory are skipped. Directories of unmounted parent volumes, e.g. after a
+// node reboot, are left behind on the realm.
+//
+// Parameters:
+//
+//	ctx      - The context of the request.
+//	llog     - The logger of the request.
+//	volumeID - The id of the unpublished volume.
+//
+// Returns:
+//
+//	error - Error if a directory cannot be removed.
+func (d *Driver) removeEphemeralVolume(ctx context.Context, llog klog.Logger, volumeID string) error {
+	if !validEphemeralVolumeID(volumeID) {
+		return nil
+	}
+
+	parents, err := filepath.Glob(filepath.Join(d.ephemeral.dir, "*", d.ephemeral.parentVolume))
+	if err != nil {
+		return err
+	}
+	for _, parentPath := range parents {
+		volumePath := filepath.Join(parentPath, volumeID)
+		if _, err := os.Lstat(volumePath); os.IsNotExist(err) {
+			continue
+		}
+
+		mounted, err := d.mounterV2.IsMountPoint(ctx, parentPath)
+		if err != nil {
+			return fmt.Errorf("failed to check parent volume mount %s: %w", parentPath, err)
+		}
+		if !mounted {
+			llog.Info("parent volume is not mounted, leaving ephemeral volume directory behind",
+				"volume_id", volumeID,
+				"parent_path", parentPath)
+			continue
+		}
+
+		if err := osRemoveAll(volumePath); err != nil {
+			return fmt.Errorf("failed to remove ephemeral volume directory %s: %w", volumePath, err)
+		}
+		llog.Info("removed ephemeral volume directory", "volume_id", volumeID, "volume_path", volumePath)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+const ephemeralVolumeID = "csi-0123456789abcdef"
+
+// newEphemeralTestDriver returns a driver with ephemeral volumes in a temporary directory
+// and a mock mounter.
+func newEphemeralTestDriver(t *testing.T) (*Driver, *mock.MockPanMounter) {
+	mockMounter := mock.NewMockPanMounter(gomock.NewController(t))
+	d := &Driver{Name: DefaultDriverName, log: klog.Background(), mounterV2: mockMounter}
+	WithEphemeralVolumes("scratch")(d)
+	d.ephemeral.dir = t.TempDir()
+	return d, mockMounter
+}
+
+// ephemeralPublishRequest returns a publish request of an inline ephemeral volume.
+func ephemeralPublishRequest(target string) *csi.NodePublishVolumeRequest {
+	return &csi.NodePublishVolumeRequest{
+		VolumeId:         ephemeralVolumeID,
+		TargetPath:       target,
+		VolumeCapability: mountCapability(),
+		VolumeContext:    map[string]string{EphemeralK8SVolumeContext: "true"},
+		Secrets:          defaultSecrets,
+	}
+}
+
+func TestNodePublishEphemeralVolume(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		d := &Driver{log: klog.Background()}
+		WithStagedMounts(true)(d)
+
+		_, err := d.NodePublishVolume(t.Context(), ephemeralPublishRequest(filepath.Join(t.TempDir(), "target")))
+		assert.Equal(t, status.Error(codes.FailedPrecondition, "Ephemeral volumes are not supported by this driver"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		d, mockMounter := newEphemeralTestDriver(t)
+		parentPath := filepath.Join(d.ephemeral.dir, "realm", "scratch")
+		volumePath := filepath.Join(parentPath, ephemeralVolumeID)
+		target := filepath.Join(t.TempDir(), "target")
+
+		gomock.InOrder(
+			mockMounter.EXPECT().IsMountPoint(gomock.Any(), parentPath).Return(false, nil),
+			mockMounter.EXPECT().Mount(gomock.Any(), "panfs://realm/scratch", parentPath, nil),
+			mockMounter.EXPECT().BindMount(gomock.Any(), volumePath, target, []string{"ro"}),
+		)
+
+		req := ephemeralPublishRequest(target)
+		req.Readonly = true
+		req.VolumeContext[utils.VolumeParameters.GetSCKey("targetDirMode")] = "0770"
+		_, err := d.NodePublishVolume(t.Context(), req)
+		require.NoError(t, err)
+
+		info, err := os.Stat(volumePath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o770), info.Mode().Perm())
+		assert.DirExists(t, target)
+	})
+
+	t.Run("ParentMounted", func(t *testing.T) {
+		d, mockMounter := newEphemeralTestDriver(t)
+		parentPath := filepath.Join(d.ephemeral.dir, "realm", "scratch")
+		target := filepath.Join(t.TempDir(), "target")
+
+		mockMounter.EXPECT().IsMountPoint(gomock.Any(), parentPath).Return(true, nil)
+		mockMounter.EXPECT().BindMount(gomock.Any(), filepath.Join(parentPath, ephemeralVolumeID), target, nil)
+
+		_, err := d.NodePublishVolume(t.Context(), ephemeralPublishRequest(target))
+		assert.NoError(t, err)
+	})
+
+	t.Run("ParentMountFailure", func(t *testing.T) {
+		d, mockMounter := newEphemeralTestDriver(t)
+		parentPath := filepath.Join(d.ephemeral.dir, "realm", "scratch")
+
+		mockMounter.EXPECT().IsMountPoint(gomock.Any(), parentPath).Return(false, nil)
+		mockMounter.EXPECT().Mount(gomock.Any(), "panfs://realm/scratch", parentPath, nil).Return(errors.New("no such volume"))
+
+		_, err := d.NodePublishVolume(t.Context(), ephemeralPublishRequest(filepath.Join(t.TempDir(), "target")))
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.ErrorContains(t, err, "Failed to mount parent volume scratch: no such volume")
+	})
+
+	t.Run("BindMountFailure", func(t *testing.T) {
+		d, mockMounter := newEphemeralTestDriver(t)
+		mockMounter.EXPECT().IsMountPoint(gomock.Any(), gomock.Any()).Return(true, nil)
+		mockMounter.EXPECT().BindMount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("permission denied"))
+
+		_, err := d.NodePublishVolume(t.Context(), ephemeralPublishRequest(filepath.Join(t.TempDir(), "target")))
+		assert.Equal(t, status.Error(codes.Internal, "Failed to publish volume: permission denied"), err)
+	})
+
+	t.Run("InvalidRequests", func(t *testing.T) {
+		d, _ := newEphemeralTestDriver(t)
+		target := filepath.Join(t.TempDir(), "target")
+
+		tests := map[string]func(*csi.NodePublishVolumeRequest){
+			"VolumeIDWithSeparator": func(req *csi.NodePublishVolumeRequest) { req.VolumeId = "../escape" },
+			"DotDotVolumeID":        func(req *csi.NodePublishVolumeRequest) { req.VolumeId = ".." },
+			"MissingSecrets":        func(req *csi.NodePublishVolumeRequest) { req.Secrets = nil },
+			"MissingTargetPath":     func(req *csi.NodePublishVolumeRequest) { req.TargetPath = "" },
+			"MissingCapability":     func(req *csi.NodePublishVolumeRequest) { req.VolumeCapability = nil },
+			"InvalidTargetDirMode": func(req *csi.NodePublishVolumeRequest) {
+				req.VolumeContext[utils.VolumeParameters.GetSCKey("targetDirMode")] = "999"
+			},
+		}
+		for name, modify := range tests {
+			t.Run(name, func(t *testing.T) {
+				req := ephemeralPublishRequest(target)
+				modify(req)
+				_, err := d.NodePublishVolume(t.Context(), req)
+				assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			})
+		}
+	})
+}
+
+func TestNodeUnpublishEphemeralVolume(t *testing.T) {
+	t.Run("RemovesDirectory", func(t *testing.T) {
+		d, mockMounter := newEphemeralTestDriver(t)
+		parentPath := filepath.Join(d.ephemeral.dir, "realm", "scratch")
+		volumePath := filepath.Join(parentPath, ephemeralVolumeID)
+		require.NoError(t, os.MkdirAll(filepath.Join(volumePath, "data"), 0o755))
+
+		mockMounter.EXPECT().Unmount(gomock.Any(), validPublishTargetPath)
+		mockMounter.EXPECT().IsMountPoint(gomock.Any(), parentPath).Return(true, nil)
+
+		_, err := d.NodeUnpublishVolume(t.Context(), &csi.NodeUnpublishVolumeRequest{
+			VolumeId:   ephemeralVolumeID,
+			TargetPath: validPublishTargetPath,
+		})
+		require.NoError(t, err)
+		assert.NoDirExists(t, volumePath)
+		assert.DirExists(t, parentPath)
+	})
+
+	t.Run("ParentNotMounted", func(t *testing.T) {
+		d, mockMounter := newEphemeralTestDriver(t)
+		parentPath := filepath.Join(d.ephemeral.dir, "realm", "scratch")
+		volumePath := filepath.Join(parentPath, ephemeralVolumeID)
+		require.NoError(t, os.MkdirAll(volumePath, 0o755))
+
+		mockMounter.EXPECT().Unmount(gomock.Any(), validPublishTargetPath)
+		mockMounter.EXPECT().IsMountPoint(gomock.Any(), parentPath).Return(false, nil)
+
+		_, err := d.NodeUnpublishVolume(t.Context(), &csi.NodeUnpublishVolumeRequest{
+			VolumeId:   ephemeralVolumeID,
+			TargetPath: validPublishTargetPath,
+		})
+		require.NoError(t, err)
+		assert.DirExists(t, volumePath)
+	})
+
+	t.Run("PersistentVolume", func(t *testing.T) {
+		d, mockMounter := newEphemeralTestDriver(t)
+		require.NoError(t, os.MkdirAll(filepath.Join(d.ephemeral.dir, "realm", "scratch"), 0o755))
+
+		// volumes without a directory in the parent volumes are only unmounted
+		mockMounter.EXPECT().Unmount(gomock.Any(), validPublishTargetPath)
+
+		_, err := d.NodeUnpublishVolume(t.Context(), &csi.NodeUnpublishVolumeRequest{
+			VolumeId:   validVolumeName,
+			TargetPath: validPublishTargetPath,
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("RemoveFailure", func(t *testing.T) {
+		d, mockMounter := newEphemeralTestDriver(t)
+		parentPath := filepath.Join(d.ephemeral.dir, "realm", "scratch")
+		require.NoError(t, os.MkdirAll(filepath.Join(parentPath, ephemeralVolumeID), 0o755))
+
+		origRemoveAll := osRemoveAll
+		t.Cleanup(func() { osRemoveAll = origRemoveAll })
+		osRemoveAll = func(string) error { return errors.New("device busy") }
+
+		mockMounter.EXPECT().Unmount(gomock.Any(), validPublishTargetPath)
+		mockMounter.EXPECT().IsMountPoint(gomock.Any(), parentPath).Return(true, nil)
+
+		_, err := d.NodeUnpublishVolume(t.Context(), &csi.NodeUnpublishVolumeRequest{
+			VolumeId:   ephemeralVolumeID,
+			TargetPath: validPublishTargetPath,
+		})
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.ErrorContains(t, err, "device busy")
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Storage class parameters referencing the secret passed to CreateVolume by the external provisioner.
+const (
+	ProvisionerSecretNameKey      = "csi.storage.k8s.io/provisioner-secret-name"
+	ProvisionerSecretNamespaceKey = "csi.storage.k8s.io/provisioner-secret-namespace"
+)
+
+// expansionSecrets reads the provisioner secret of the storage class of a volume. It is used by
+// ControllerExpandVolume for volumes whose storage class has no controller-expand secret, so
+// the external resizer sends no secrets. The ${pv.name}, ${pvc.name} and ${pvc.namespace}
+// templates of the secret reference are resolved from the PersistentVolume.
+//
+// Parameters:
+//
+//	ctx      - The context for the Kubernetes API calls.
+//	volumeID - The ID of the volume, which is the name of its PersistentVolume.
+//
+// Returns:
+//
+//	map[string]string - The data of the provisioner secret.
+//	error             - Error if the secret reference cannot be resolved or the secret cannot be read.
+func (d *Driver) expansionSecrets(ctx context.Context, volumeID string) (map[string]string, error) {
+	if d.kubeClient == nil {
+		return nil, fmt.Errorf("kubernetes client is not available to read the provisioner secret of volume %s", volumeID)
+	}
+
+	pv, err := d.kubeClient.CoreV1().PersistentVolumes().Get(ctx, volumeID, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get persistent volume %s: %w", volumeID, err)
+	}
+	if pv.Spec.StorageClassName == "" {
+		return nil, fmt.Errorf("persistent volume %s has no storage class", volumeID)
+	}
+
+	sc, err := d.kubeClient.StorageV1().StorageClasses().Get(ctx, pv.Spec.StorageClassName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage class %s: %w", pv.Spec.StorageClassName, err)
+	}
+
+	templates := []string{"${pv.name}", pv.Name}
+	if claim := pv.Spec.ClaimRef; claim != nil {
+		templates = append(templates, "${pvc.name}", claim.Name, "${pvc.namespace}", claim.Namespace)
+	}
+	replacer := strings.NewReplacer(templates...)
+	name := replacer.Replace(sc.Parameters[ProvisionerSecretNameKey])
+	namespace := replacer.Replace(sc.Parameters[ProvisionerSecretNamespaceKey])
+	if name == "" || namespace == "" {
+		return nil, fmt.Errorf("storage class %s has no provisioner secret", sc.Name)
+	}
+	if strings.Contains(name+namespace, "${") {
+		return nil, fmt.Errorf("provisioner secret %s/%s of storage class %s uses unsupported templates", namespace, name, sc.Name)
+	}
+
+	secret, err := d.kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provisioner secret %s/%s: %w", namespace, name, err)
+	}
+
+	secrets := make(map[string]string, len(secret.Data))
+	for key, value := range secret.Data {
+		secrets[key] = string(value)
+	}
+	return secrets, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestExpansionSecrets verifies that the provisioner secret of the storage class of a volume is read.
+func TestExpansionSecrets(t *testing.T) {
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: validVolumeName},
+		Spec: corev1.PersistentVolumeSpec{
+			StorageClassName: "panfs",
+			ClaimRef:         &corev1.ObjectReference{Name: "data", Namespace: "team-a"},
+		},
+	}
+	storageClass := func(name, namespace string) *storagev1.StorageClass {
+		return &storagev1.StorageClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "panfs"},
+			Parameters: map[string]string{
+				ProvisionerSecretNameKey:      name,
+				ProvisionerSecretNamespaceKey: namespace,
+			},
+		}
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "realm", Namespace: "team-a"},
+		Data:       map[string][]byte{"realm_ip": []byte("realm"), "user": []byte("user")},
+	}
+
+	t.Run("TemplatedReference", func(t *testing.T) {
+		d := &Driver{kubeClient: fake.NewClientset(pv, storageClass("realm", "${pvc.namespace}"), secret)}
+		secrets, err := d.expansionSecrets(t.Context(), validVolumeName)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"realm_ip": "realm", "user": "user"}, secrets)
+	})
+
+	t.Run("UnsupportedTemplate", func(t *testing.T) {
+		d := &Driver{kubeClient: fake.NewClientset(pv, storageClass("${pvc.annotations['realm']}", "team-a"), secret)}
+		_, err := d.expansionSecrets(t.Context(), validVolumeName)
+		assert.ErrorContains(t, err, "unsupported templates")
+	})
+
+	t.Run("NoProvisionerSecret", func(t *testing.T) {
+		d := &Driver{kubeClient: fake.NewClientset(pv, storageClass("", ""))}
+		_, err := d.expansionSecrets(t.Context(), validVolumeName)
+		assert.ErrorContains(t, err, "has no provisioner secret")
+	})
+
+	t.Run("PersistentVolumeNotFound", func(t *testing.T) {
+		d := &Driver{kubeClient: fake.NewClientset()}
+		_, err := d.expansionSecrets(t.Context(), validVolumeName)
+		assert.ErrorContains(t, err, "failed to get persistent volume")
+	})
+
+	t.Run("NoKubeClient", func(t *testing.T) {
+		_, err := (&Driver{}).expansionSecrets(t.Context(), validVolumeName)
+		assert.ErrorContains(t, err, "kubernetes client is not available")
+	})
+
+	t.Run("ControllerExpandVolume", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		d.kubeClient = fake.NewClientset(pv, storageClass("realm", "team-a"), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "realm", Namespace: "team-a"},
+			Data:       map[string][]byte{"realm_ip": []byte("realm"), "user": []byte("user"), "password": []byte("pass")},
+		})
+		secrets := map[string]string{"realm_ip": "realm", "user": "user", "password": "pass"}
+		gomock.InOrder(
+			pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, secrets).Return(&utils.Volume{Name: utils.VolumeName(validVolumeName), Soft: 5}, nil),
+			pancliMock.EXPECT().ExpandVolume(gomock.Any(), validVolumeName, GB10Bytes, secrets).Return(nil),
+			pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, secrets).Return(&utils.Volume{Name: utils.VolumeName(validVolumeName), Soft: 10}, nil),
+		)
+
+		resp, err := d.ControllerExpandVolume(t.Context(), &csi.ControllerExpandVolumeRequest{
+			VolumeId:      validVolumeName,
+			CapacityRange: &csi.CapacityRange{RequiredBytes: GB10Bytes},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, GB10Bytes, resp.CapacityBytes)
+	})
+}
@@ -17,6 +17,7 @@ package driver
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -25,27 +26,6 @@ import (
 	"github.com/container-storage-interface/spec/lib/go/csi"
 )
 
-// List of supported plugin capabilities.
-// Now we support the following capabilities:
-// - Controller Service
-// - Online Volume Expansion
-var pluginCapabilities = []*csi.PluginCapability{
-	{
-		Type: &csi.PluginCapability_Service_{
-			Service: &csi.PluginCapability_Service{
-				Type: csi.PluginCapability_Service_CONTROLLER_SERVICE,
-			},
-		},
-	},
-	{
-		Type: &csi.PluginCapability_VolumeExpansion_{
-			VolumeExpansion: &csi.PluginCapability_VolumeExpansion{
-				Type: csi.PluginCapability_VolumeExpansion_ONLINE,
-			},
-		},
-	},
-}
-
 // GetPluginInfo returns the name and version of the CSI plugin.
 //
 // Parameters:
@@ -66,10 +46,15 @@ func (d *Driver) GetPluginInfo(ctx context.Context, in *csi.GetPluginInfoRequest
 	return &csi.GetPluginInfoResponse{
 		Name:          d.Name,
 		VendorVersion: d.Version,
+		Manifest: map[string]string{
+			MountOptionsManifestKey: mountOptionsManifest(),
+			PluginModeManifestKey:   string(d.pluginMode()),
+		},
 	}, nil
 }
 
-// GetPluginCapabilities returns available capabilities of the plugin.
+// GetPluginCapabilities returns available capabilities of the plugin, including volume
+// accessibility constraints if enabled with WithTopology.
 //
 // Parameters:
 //   ctx - The context for the request.
@@ -81,8 +66,20 @@ func (d *Driver) GetPluginInfo(ctx context.Context, in *csi.GetPluginInfoRequest
 func (d *Driver) GetPluginCapabilities(ctx context.Context, in *csi.GetPluginCapabilitiesRequest) (*csi.GetPluginCapabilitiesResponse, error) {
 	klog.V(2).Info("GetPluginCapabilities called")
 
+	capabilities := pluginCapabilities
+	if d.topology.enabled {
+		// volumes are constrained to the nodes reaching their realm
+		capabilities = append(slices.Clip(capabilities), &csi.PluginCapability{
+			Type: &csi.PluginCapability_Service_{
+				Service: &csi.PluginCapability_Service{
+					Type: csi.PluginCapability_Service_VOLUME_ACCESSIBILITY_CONSTRAINTS,
+				},
+			},
+		})
+	}
+
 	return &csi.GetPluginCapabilitiesResponse{
-		Capabilities: pluginCapabilities,
+		Capabilities: capabilities,
 	}, nil
 }
 
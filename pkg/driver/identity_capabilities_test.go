@@ -0,0 +1,98 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package driver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver"
+)
+
+// TestDriver_GetPluginCapabilities tests the GetPluginCapabilities method of the Driver.
+// It verifies that the default plugin capabilities are returned as expected, and the volume
+// accessibility constraints if topology is enabled.
+func TestDriver_GetPluginCapabilities(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   *driver.Driver
+		wantCaps []*csi.PluginCapability
+	}{
+		{
+			name:   "default capabilities",
+			driver: &driver.Driver{},
+			wantCaps: []*csi.PluginCapability{
+				{
+					Type: &csi.PluginCapability_Service_{
+						Service: &csi.PluginCapability_Service{
+							Type: csi.PluginCapability_Service_CONTROLLER_SERVICE,
+						},
+					},
+				},
+				{
+					Type: &csi.PluginCapability_VolumeExpansion_{
+						VolumeExpansion: &csi.PluginCapability_VolumeExpansion{
+							Type: csi.PluginCapability_VolumeExpansion_ONLINE,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "topology",
+			driver: func() *driver.Driver {
+				d := &driver.Driver{}
+				driver.WithTopology(true)(d)
+				return d
+			}(),
+			wantCaps: []*csi.PluginCapability{
+				{
+					Type: &csi.PluginCapability_Service_{
+						Service: &csi.PluginCapability_Service{
+							Type: csi.PluginCapability_Service_CONTROLLER_SERVICE,
+						},
+					},
+				},
+				{
+					Type: &csi.PluginCapability_VolumeExpansion_{
+						VolumeExpansion: &csi.PluginCapability_VolumeExpansion{
+							Type: csi.PluginCapability_VolumeExpansion_ONLINE,
+						},
+					},
+				},
+				{
+					Type: &csi.PluginCapability_Service_{
+						Service: &csi.PluginCapability_Service{
+							Type: csi.PluginCapability_Service_VOLUME_ACCESSIBILITY_CONSTRAINTS,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.driver.GetPluginCapabilities(context.Background(), &csi.GetPluginCapabilitiesRequest{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCaps, resp.Capabilities)
+		})
+	}
+}
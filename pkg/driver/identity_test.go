@@ -16,6 +16,7 @@ package driver_test
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
@@ -27,10 +28,14 @@ import (
 // TestDriver_GetPluginInfo tests the GetPluginInfo method of the Driver.
 // It verifies correct plugin info is returned and error handling for missing driver name.
 func TestDriver_GetPluginInfo(t *testing.T) {
+	mountOptions, err := json.Marshal(driver.MountOptions)
+	assert.NoError(t, err)
+
 	tests := []struct {
 		name       string
 		driverName string
 		driverVer  string
+		opts       []driver.Option
 		wantErr    bool
 		wantResp   *csi.GetPluginInfoResponse
 	}{
@@ -42,6 +47,24 @@ func TestDriver_GetPluginInfo(t *testing.T) {
 			wantResp: &csi.GetPluginInfoResponse{
 				Name:          "test-driver",
 				VendorVersion: "v1.0.0",
+				Manifest: map[string]string{
+					driver.MountOptionsManifestKey: string(mountOptions),
+					driver.PluginModeManifestKey:   "all",
+				},
+			},
+		},
+		{
+			name:       "node plugin",
+			driverName: "test-driver",
+			driverVer:  "v1.0.0",
+			opts:       []driver.Option{driver.WithPluginMode(driver.PluginModeNode)},
+			wantResp: &csi.GetPluginInfoResponse{
+				Name:          "test-driver",
+				VendorVersion: "v1.0.0",
+				Manifest: map[string]string{
+					driver.MountOptionsManifestKey: string(mountOptions),
+					driver.PluginModeManifestKey:   "node",
+				},
 			},
 		},
 		{
@@ -59,6 +82,9 @@ func TestDriver_GetPluginInfo(t *testing.T) {
 				Name:    tt.driverName,
 				Version: tt.driverVer,
 			}
+			for _, opt := range tt.opts {
+				opt(d)
+			}
 			resp, err := d.GetPluginInfo(context.Background(), &csi.GetPluginInfoRequest{})
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -71,43 +97,18 @@ func TestDriver_GetPluginInfo(t *testing.T) {
 	}
 }
 
-// TestDriver_GetPluginCapabilities tests the GetPluginCapabilities method of the Driver.
-// It verifies that the default plugin capabilities are returned as expected.
-func TestDriver_GetPluginCapabilities(t *testing.T) {
-	tests := []struct {
-		name     string
-		driver   *driver.Driver
-		wantCaps []*csi.PluginCapability
-	}{
-		{
-			name:   "default capabilities",
-			driver: &driver.Driver{},
-			wantCaps: []*csi.PluginCapability{
-				{
-					Type: &csi.PluginCapability_Service_{
-						Service: &csi.PluginCapability_Service{
-							Type: csi.PluginCapability_Service_CONTROLLER_SERVICE,
-						},
-					},
-				},
-				{
-					Type: &csi.PluginCapability_VolumeExpansion_{
-						VolumeExpansion: &csi.PluginCapability_VolumeExpansion{
-							Type: csi.PluginCapability_VolumeExpansion_ONLINE,
-						},
-					},
-				},
-			},
-		},
+// TestParsePluginMode verifies the supported plugin modes.
+func TestParsePluginMode(t *testing.T) {
+	for _, value := range []string{"controller", "node", "all"} {
+		mode, err := driver.ParsePluginMode(value)
+		assert.NoError(t, err)
+		assert.Equal(t, driver.PluginMode(value), mode)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			resp, err := tt.driver.GetPluginCapabilities(context.Background(), &csi.GetPluginCapabilitiesRequest{})
-			assert.NoError(t, err)
-			assert.Equal(t, tt.wantCaps, resp.Capabilities)
-		})
-	}
+	_, err := driver.ParsePluginMode("Node")
+	assert.EqualError(t, err, `invalid plugin mode "Node": must be controller, node or all`)
+	_, err = driver.ParsePluginMode("")
+	assert.Error(t, err)
 }
 
 // TestDriver_Probe tests the Probe method of the Driver.
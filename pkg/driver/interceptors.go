@@ -0,0 +1,127 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/klog/v2"
+)
+
+// unaryInterceptors returns the interceptors of unary RPCs, outermost first.
+func (d *Driver) unaryInterceptors() []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		rpcMetricsInterceptor,
+		d.slo.unaryInterceptor,
+		d.loggingInterceptor,
+		d.recoveryInterceptor,
+		d.inflight.unaryInterceptor,
+		d.responseMetadataInterceptor,
+	}
+}
+
+// requestLogger returns the logger of the request, which logs its request ID.
+//
+// Parameters:
+//
+//	ctx - The context of the request.
+//
+// Returns:
+//
+//	klog.Logger - The logger of the driver with the request ID, if the request has one.
+func (d *Driver) requestLogger(ctx context.Context) klog.Logger {
+	if requestID, ok := utils.RequestIDFromContext(ctx); ok {
+		return d.log.WithValues("request_id", requestID)
+	}
+	return d.log
+}
+
+// incomingRequestID returns the request ID sent by the client in the MetadataRequestID
+// metadata key, e.g. by a tool replaying a failed request.
+//
+// Parameters:
+//
+//	ctx - The context of the request.
+//
+// Returns:
+//
+//	string - The request ID of the client.
+//	bool   - False if the client sent no valid request ID.
+func incomingRequestID(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	values := md.Get(MetadataRequestID)
+	if len(values) == 0 || !utils.ValidRequestID(values[0]) {
+		return "", false
+	}
+	return values[0], true
+}
+
+// loggingInterceptor assigns a request ID to every RPC and logs the request with its
+// secrets redacted and the outcome of the RPC. The request ID sent by the client is used
+// if valid. The request ID and a logger logging it are added to the context, so the log
+// lines of the realm clients and the mounter can be correlated with the RPC.
+func (d *Driver) loggingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	requestID, ok := incomingRequestID(ctx)
+	if !ok {
+		requestID = newRequestID()
+	}
+	timer := &realmTimer{}
+	ctx = utils.ContextWithRequestID(ctx, requestID)
+	ctx = context.WithValue(ctx, realmTimerKey{}, timer)
+	ctx = klog.NewContext(ctx, d.log.WithValues("request_id", requestID))
+
+	llog := d.log.WithValues("method", info.FullMethod, "request_id", requestID)
+	if llog.V(4).Enabled() {
+		msg, _ := req.(proto.Message)
+		llog.V(4).Info("RPC called", "request", redactedString(msg))
+	}
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	llog.V(2).Info("RPC completed",
+		"code", status.Code(err).String(),
+		"duration", time.Since(start),
+		"realm_latency", time.Duration(timer.elapsed.Load()))
+	return resp, err
+}
+
+// recoveryInterceptor turns a panic of an RPC handler into a codes.Internal error, so a
+// single faulty request does not terminate the plugin.
+func (d *Driver) recoveryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			metrics.RecoveredPanics.WithLabelValues(info.FullMethod).Inc()
+			d.requestLogger(ctx).Error(fmt.Errorf("panic: %v", r), "RPC handler panicked",
+				"method", info.FullMethod,
+				"stack", string(debug.Stack()))
+			resp, err = nil, status.Error(codes.Internal, UnexpectedErrorInternalStr)
+		}
+	}()
+	return handler(ctx, req)
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/go-logr/logr/funcr"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// chainInterceptors runs the handler through the interceptors, outermost first.
+func chainInterceptors(interceptors []grpc.UnaryServerInterceptor, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) grpc.UnaryHandler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, req any) (any, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+	return handler
+}
+
+// TestUnaryInterceptors verifies that every RPC gets a request ID and that panics of
+// handlers are turned into codes.Internal errors.
+func TestUnaryInterceptors(t *testing.T) {
+	d := &Driver{Version: "1.2.3", log: klog.Background()}
+
+	t.Run("RequestID", func(t *testing.T) {
+		info := &grpc.UnaryServerInfo{FullMethod: "/csi.v1.Node/NodeGetInfo"}
+		var requestID string
+		handler := chainInterceptors(d.unaryInterceptors(), info, func(ctx context.Context, _ any) (any, error) {
+			requestID, _ = utils.RequestIDFromContext(ctx)
+			return &csi.NodeGetInfoResponse{}, nil
+		})
+
+		_, err := handler(t.Context(), &csi.NodeGetInfoRequest{})
+		require.NoError(t, err)
+		assert.Len(t, requestID, 16)
+	})
+
+	t.Run("IncomingRequestID", func(t *testing.T) {
+		var lines []string
+		d := &Driver{log: funcr.New(func(_, args string) { lines = append(lines, args) }, funcr.Options{})}
+		info := &grpc.UnaryServerInfo{FullMethod: "/csi.v1.Node/NodeGetInfo"}
+		var requestID string
+		handler := chainInterceptors(d.unaryInterceptors(), info, func(ctx context.Context, _ any) (any, error) {
+			requestID, _ = utils.RequestIDFromContext(ctx)
+			klog.FromContext(ctx).Info("mounting")
+			return &csi.NodeGetInfoResponse{}, nil
+		})
+
+		ctx := metadata.NewIncomingContext(t.Context(), metadata.Pairs(MetadataRequestID, "replay-42"))
+		_, err := handler(ctx, &csi.NodeGetInfoRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, "replay-42", requestID)
+		assert.Contains(t, lines, `"level"=0 "msg"="mounting" "request_id"="replay-42"`)
+	})
+
+	t.Run("InvalidIncomingRequestID", func(t *testing.T) {
+		info := &grpc.UnaryServerInfo{FullMethod: "/csi.v1.Node/NodeGetInfo"}
+		var requestID string
+		handler := chainInterceptors(d.unaryInterceptors(), info, func(ctx context.Context, _ any) (any, error) {
+			requestID, _ = utils.RequestIDFromContext(ctx)
+			return &csi.NodeGetInfoResponse{}, nil
+		})
+
+		ctx := metadata.NewIncomingContext(t.Context(), metadata.Pairs(MetadataRequestID, "not a valid\nid"))
+		_, err := handler(ctx, &csi.NodeGetInfoRequest{})
+		require.NoError(t, err)
+		assert.Len(t, requestID, 16)
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		method := "/csi.v1.Controller/TestUnaryInterceptorsPanic"
+		info := &grpc.UnaryServerInfo{FullMethod: method}
+		handler := chainInterceptors(d.unaryInterceptors(), info, func(context.Context, any) (any, error) {
+			var volume *csi.Volume
+			return volume.VolumeId, nil
+		})
+
+		resp, err := handler(t.Context(), &csi.DeleteVolumeRequest{VolumeId: validVolumeName, Secrets: defaultSecrets})
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.RecoveredPanics.WithLabelValues(method)))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.RPCs.WithLabelValues(method, "Internal")))
+
+		// the panicked request is no longer in flight
+		assert.Empty(t, d.inflight.snapshot())
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// kmipDir is the directory of the KMIP files passed to the PanFS client while mounting.
+const kmipDir = "/var/tmp/kmip/"
+
+// kmipCertificateFile is a PEM secret key materialized as a file for the PanFS client.
+type kmipCertificateFile struct {
+	// secretKey is the key of the node secret holding the PEM data.
+	secretKey string
+	// description names the file in errors.
+	description string
+	// pattern is the pattern of the temporary file name.
+	pattern string
+	// option is the mount option referencing the file, registered in MountOptions.
+	option string
+}
+
+// kmipCertificateFiles lists the optional certificate files of encrypted mounts, in the
+// order of their mount options.
+var kmipCertificateFiles = []kmipCertificateFile{
+	{utils.RealmConnectionContext.KMIPCABundle, "KMIP CA bundle", "ca_*.pem", "kmip-ca-file"},
+	{utils.RealmConnectionContext.KMIPClientCert, "KMIP client certificate", "client_*.crt", "kmip-client-cert-file"},
+	{utils.RealmConnectionContext.KMIPClientKey, "KMIP client key", "client_*.key", "kmip-client-key-file"},
+}
+
+// validateKMIPCertificates checks the optional certificate keys of the node secret: the CA
+// bundle must contain certificates, and the client certificate and key must be a matching
+// pair provided together.
+//
+// Parameters:
+//
+//	secrets - The node secrets of the request.
+//
+// Returns:
+//
+//	error - The joined errors of all failed checks.
+func validateKMIPCertificates(secrets map[string]string) error {
+	var errs []error
+	if bundle := secrets[utils.RealmConnectionContext.KMIPCABundle]; bundle != "" {
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(bundle)) {
+			errs = append(errs, fmt.Errorf("%s does not contain PEM encoded certificates", utils.RealmConnectionContext.KMIPCABundle))
+		}
+	}
+
+	cert, key := secrets[utils.RealmConnectionContext.KMIPClientCert], secrets[utils.RealmConnectionContext.KMIPClientKey]
+	switch {
+	case cert == "" && key == "":
+	case cert == "" || key == "":
+		errs = append(errs, fmt.Errorf("%s and %s must be provided together",
+			utils.RealmConnectionContext.KMIPClientCert, utils.RealmConnectionContext.KMIPClientKey))
+	default:
+		if _, err := tls.X509KeyPair([]byte(cert), []byte(key)); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s and %s: %w",
+				utils.RealmConnectionContext.KMIPClientCert, utils.RealmConnectionContext.KMIPClientKey, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// kmipCertificateOptions writes the certificate keys of the node secret, if any, to files
+// next to the KMIP configuration, readable by the node plugin only, and returns the mount
+// options referencing them. Like the KMIP configuration, the files only exist while the
+// volume is mounted.
+//
+// Parameters:
+//
+//	llog    - The logger of the request.
+//	secrets - The node secrets of the request.
+//
+// Returns:
+//
+//	[]string - The mount options of the written files.
+//	func()   - Removes the files, to be called once the volume is mounted.
+//	error    - A gRPC status error if the certificates are invalid or cannot be written.
+func (d *Driver) kmipCertificateOptions(llog klog.Logger, secrets map[string]string) ([]string, func(), error) {
+	if err := validateKMIPCertificates(secrets); err != nil {
+		llog.Error(err, "invalid KMIP certificates")
+		return nil, nil, status.Errorf(codes.InvalidArgument, "Invalid KMIP certificates in the node secret: %v", err)
+	}
+
+	var files []FileWriter
+	cleanup := func() {
+		for _, file := range files {
+			if err := osRemove(file.Name()); err != nil {
+				llog.Error(err, "failed to remove KMIP certificate file")
+			}
+			if err := file.Close(); err != nil {
+				llog.Error(err, "failed to close KMIP certificate file")
+			}
+		}
+	}
+
+	var options []string
+	for _, cf := range kmipCertificateFiles {
+		data := secrets[cf.secretKey]
+		if data == "" {
+			continue
+		}
+
+		file, err := d.tempFileFactory.CreateTemp(kmipDir, cf.pattern)
+		if err != nil {
+			llog.Error(err, "failed to create KMIP certificate file", "secret_key", cf.secretKey)
+			cleanup()
+			return nil, nil, status.Errorf(codes.Internal, "Failed to create %s file: %v", cf.description, err)
+		}
+		files = append(files, file)
+
+		if err := osChmod(file.Name(), 0o600); err != nil {
+			llog.Error(err, "failed to restrict permissions of KMIP certificate file", "secret_key", cf.secretKey)
+			cleanup()
+			return nil, nil, status.Errorf(codes.Internal, "Failed to set '0600' permissions on %s file: %v", cf.description, err)
+		}
+		if _, err := file.Write([]byte(data)); err != nil {
+			llog.Error(err, "failed to write KMIP certificate file", "secret_key", cf.secretKey)
+			cleanup()
+			return nil, nil, status.Errorf(codes.Internal, "Failed to write %s file: %v", cf.description, err)
+		}
+		options = append(options, fmt.Sprintf("%s=%s", cf.option, file.Name()))
+	}
+	return options, cleanup, nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// dirTempFileFactory creates temporary files in a fixed directory instead of the requested one.
+type dirTempFileFactory struct {
+	dir string
+}
+
+// CreateTemp creates a temporary file in the directory of the factory.
+func (f *dirTempFileFactory) CreateTemp(_, pattern string) (FileWriter, error) {
+	return osCreateTemp(f.dir, pattern)
+}
+
+// newTestCertificate returns a PEM encoded self-signed certificate and its private key.
+func newTestCertificate(t *testing.T, name string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestValidateKMIPCertificates(t *testing.T) {
+	caCert, _ := newTestCertificate(t, "kmip-ca")
+	clientCert, clientKey := newTestCertificate(t, "node")
+	_, otherKey := newTestCertificate(t, "other")
+
+	tests := []struct {
+		name    string
+		secrets map[string]string
+		wantErr string
+	}{
+		{name: "None", secrets: map[string]string{}},
+		{name: "CABundle", secrets: map[string]string{utils.RealmConnectionContext.KMIPCABundle: caCert + clientCert}},
+		{
+			name: "ClientPair",
+			secrets: map[string]string{
+				utils.RealmConnectionContext.KMIPClientCert: clientCert,
+				utils.RealmConnectionContext.KMIPClientKey:  clientKey,
+			},
+		},
+		{
+			name:    "InvalidCABundle",
+			secrets: map[string]string{utils.RealmConnectionContext.KMIPCABundle: "not a certificate"},
+			wantErr: "kmip_ca_bundle does not contain PEM encoded certificates",
+		},
+		{
+			name:    "CertificateWithoutKey",
+			secrets: map[string]string{utils.RealmConnectionContext.KMIPClientCert: clientCert},
+			wantErr: "kmip_client_cert and kmip_client_key must be provided together",
+		},
+		{
+			name:    "KeyWithoutCertificate",
+			secrets: map[string]string{utils.RealmConnectionContext.KMIPClientKey: clientKey},
+			wantErr: "kmip_client_cert and kmip_client_key must be provided together",
+		},
+		{
+			name: "MismatchedPair",
+			secrets: map[string]string{
+				utils.RealmConnectionContext.KMIPClientCert: clientCert,
+				utils.RealmConnectionContext.KMIPClientKey:  otherKey,
+			},
+			wantErr: "invalid kmip_client_cert and kmip_client_key",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateKMIPCertificates(tc.secrets)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestKMIPMountOptionsCertificates(t *testing.T) {
+	caCert, _ := newTestCertificate(t, "kmip-ca")
+	clientCert, clientKey := newTestCertificate(t, "node")
+	volumeContext := map[string]string{utils.VolumeParameters.GetSCKey("encryption"): "on"}
+
+	origMkdirAll := osMkdirAll
+	t.Cleanup(func() { osMkdirAll = origMkdirAll })
+	osMkdirAll = func(string, os.FileMode) error { return nil }
+
+	t.Run("Success", func(t *testing.T) {
+		dir := t.TempDir()
+		d := &Driver{tempFileFactory: &dirTempFileFactory{dir: dir}}
+
+		options, cleanup, err := d.kmipMountOptions(klog.Background(), volumeContext, map[string]string{
+			utils.RealmConnectionContext.KMIPConfigData: validKMIPConfigData,
+			utils.RealmConnectionContext.KMIPCABundle:   caCert,
+			utils.RealmConnectionContext.KMIPClientCert: clientCert,
+			utils.RealmConnectionContext.KMIPClientKey:  clientKey,
+		})
+		require.NoError(t, err)
+		require.Len(t, options, 4)
+
+		contents := []string{validKMIPConfigData, caCert, clientCert, clientKey}
+		for i, prefix := range []string{"kmip-config-file=", "kmip-ca-file=", "kmip-client-cert-file=", "kmip-client-key-file="} {
+			require.True(t, strings.HasPrefix(options[i], prefix), options[i])
+			path := strings.TrimPrefix(options[i], prefix)
+			assert.Equal(t, dir, filepath.Dir(path))
+
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, contents[i], string(data))
+		}
+
+		cleanup()
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("InvalidCertificates", func(t *testing.T) {
+		dir := t.TempDir()
+		d := &Driver{tempFileFactory: &dirTempFileFactory{dir: dir}}
+
+		_, _, err := d.kmipMountOptions(klog.Background(), volumeContext, map[string]string{
+			utils.RealmConnectionContext.KMIPConfigData: validKMIPConfigData,
+			utils.RealmConnectionContext.KMIPClientCert: clientCert,
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.ErrorContains(t, err, "must be provided together")
+
+		// the KMIP configuration is removed with the failed request
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("CreateFailure", func(t *testing.T) {
+		d := &Driver{tempFileFactory: &errorTempFileFactory{}}
+
+		_, _, err := d.kmipCertificateOptions(klog.Background(), map[string]string{
+			utils.RealmConnectionContext.KMIPCABundle: caCert,
+		})
+		assert.Equal(t, status.Error(codes.Internal, "Failed to create KMIP CA bundle file: create temp error"), err)
+	})
+
+	t.Run("NoCertificates", func(t *testing.T) {
+		d := &Driver{tempFileFactory: &errorTempFileFactory{}}
+
+		options, cleanup, err := d.kmipCertificateOptions(klog.Background(), map[string]string{})
+		require.NoError(t, err)
+		assert.Empty(t, options)
+		cleanup()
+	})
+}
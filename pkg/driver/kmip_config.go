@@ -0,0 +1,139 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// kmipChecksumPrefix starts the optional comment line of a KMIP configuration holding the
+// SHA-256 checksum of all other lines, as computed by
+// `grep -v '^# sha256sum:' kmip.conf | sha256sum`.
+const kmipChecksumPrefix = "# sha256sum:"
+
+// kmipCertificateKeys lists the server keys holding base64 encoded PEM data.
+var kmipCertificateKeys = []string{"ca-cert-data", "client-cert-data", "client-key-data"}
+
+// validateKMIPConfig checks that KMIP configuration data is complete before it is passed to the
+// PanFS client, so that corrupted or truncated configurations fail with a clear message instead
+// of an opaque mount error. It checks that the primary server has a host and a numeric port, that
+// the certificate data decodes to PEM blocks and, if present, the embedded checksum.
+//
+// Parameters:
+//
+//	data - The INI formatted KMIP configuration.
+//
+// Returns:
+//
+//	error - The joined errors of all failed checks.
+func validateKMIPConfig(data string) error {
+	var errs []error
+	if err := verifyKMIPChecksum(data); err != nil {
+		errs = append(errs, err)
+	}
+
+	sections := parseINI(data)
+	primary := sections["global"]["primary-server"]
+	if primary == "" {
+		return errors.Join(append(errs, fmt.Errorf("missing primary-server in [global] section"))...)
+	}
+
+	server, ok := sections[primary]
+	if !ok {
+		return errors.Join(append(errs, fmt.Errorf("missing [%s] section of the primary server", primary))...)
+	}
+	if server["host"] == "" {
+		errs = append(errs, fmt.Errorf("missing host in [%s] section", primary))
+	}
+	if port, err := strconv.Atoi(server["port"]); err != nil || port <= 0 || port > 65535 {
+		errs = append(errs, fmt.Errorf("invalid port %q in [%s] section", server["port"], primary))
+	}
+	for _, key := range kmipCertificateKeys {
+		value, ok := server[key]
+		if !ok {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s in [%s] section is not valid base64: %w", key, primary, err))
+			continue
+		}
+		if block, _ := pem.Decode(decoded); block == nil {
+			errs = append(errs, fmt.Errorf("%s in [%s] section does not contain a complete PEM block", key, primary))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// verifyKMIPChecksum compares the embedded checksum of a KMIP configuration, if any, with the
+// SHA-256 checksum of all other lines.
+func verifyKMIPChecksum(data string) error {
+	var expected string
+	h := sha256.New()
+	for _, line := range strings.SplitAfter(data, "\n") {
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, kmipChecksumPrefix) {
+			expected = strings.TrimSpace(strings.TrimPrefix(line, kmipChecksumPrefix))
+			continue
+		}
+		h.Write([]byte(strings.TrimSuffix(line, "\n") + "\n"))
+	}
+
+	if expected == "" {
+		return nil
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("checksum mismatch: configuration has sha256 %s, expected %s", actual, expected)
+	}
+	return nil
+}
+
+// parseINI parses INI formatted data into sections of key/value pairs. Keys before the first
+// section are stored in the "" section. Comment lines start with '#' or ';'.
+func parseINI(data string) map[string]map[string]string {
+	sections := map[string]map[string]string{"": {}}
+	section := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "", strings.HasPrefix(line, "#"), strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if sections[section] == nil {
+				sections[section] = map[string]string{}
+			}
+		default:
+			if key, value, ok := strings.Cut(line, "="); ok {
+				sections[section][strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+	}
+	return sections
+}
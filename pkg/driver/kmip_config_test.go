@@ -0,0 +1,144 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// validKMIPConfigData is a minimal KMIP configuration passing validateKMIPConfig.
+const validKMIPConfigData = `config_version = 1
+
+[global]
+primary-server = server:dev
+
+[server:dev]
+host = kmip.example.com
+port = 5696
+ca-cert-data = LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCkFBQUEKLS0tLS1FTkQgQ0VSVElGSUNBVEUtLS0tLQo=
+`
+
+func TestValidateKMIPConfig(t *testing.T) {
+	sum := sha256.Sum256([]byte(validKMIPConfigData))
+	checksum := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "Valid",
+			data: validKMIPConfigData,
+		},
+		{
+			name: "ValidChecksum",
+			data: kmipChecksumPrefix + " " + checksum + "\n" + validKMIPConfigData,
+		},
+		{
+			name:    "ChecksumMismatch",
+			data:    kmipChecksumPrefix + " " + checksum + "\n" + strings.Replace(validKMIPConfigData, "5696", "5697", 1),
+			wantErr: "checksum mismatch",
+		},
+		{
+			name:    "MissingPrimaryServer",
+			data:    strings.Replace(validKMIPConfigData, "primary-server = server:dev", "", 1),
+			wantErr: "missing primary-server in [global] section",
+		},
+		{
+			name:    "MissingPrimarySection",
+			data:    strings.Replace(validKMIPConfigData, "[server:dev]", "[server:prod]", 1),
+			wantErr: "missing [server:dev] section of the primary server",
+		},
+		{
+			name:    "MissingHost",
+			data:    strings.Replace(validKMIPConfigData, "host = kmip.example.com", "host =", 1),
+			wantErr: "missing host in [server:dev] section",
+		},
+		{
+			name:    "InvalidPort",
+			data:    strings.Replace(validKMIPConfigData, "port = 5696", "port = kmip", 1),
+			wantErr: `invalid port "kmip" in [server:dev] section`,
+		},
+		{
+			name:    "TruncatedBase64",
+			data:    strings.Replace(validKMIPConfigData, "LQo=", "LQ", 1),
+			wantErr: "ca-cert-data in [server:dev] section is not valid base64",
+		},
+		{
+			name:    "TruncatedPEM",
+			data:    strings.Replace(validKMIPConfigData, "CkFBQUEKLS0tLS1FTkQgQ0VSVElGSUNBVEUtLS0tLQo=", "CkFBQUEK", 1),
+			wantErr: "ca-cert-data in [server:dev] section does not contain a complete PEM block",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateKMIPConfig(tc.data)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestNodePublishVolume_InvalidKMIPConfig(t *testing.T) {
+	driver := &Driver{
+		Name: DefaultDriverName,
+		tempFileFactory: &fakeTempFileFactory{
+			file: &fakeFileWriter{name: "/var/tmp/kmip/config_test.conf"},
+		},
+	}
+
+	origChmod := osChmod
+	defer func() { osChmod = origChmod }()
+	osChmod = func(name string, mode os.FileMode) error { return nil }
+
+	req := &csi.NodePublishVolumeRequest{
+		VolumeId:   validVolumeName,
+		TargetPath: validPublishTargetPath,
+		VolumeCapability: &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+		},
+		Secrets: map[string]string{
+			utils.RealmConnectionContext.RealmAddress:   "realm",
+			utils.RealmConnectionContext.Username:       "user",
+			utils.RealmConnectionContext.Password:       "password",
+			utils.RealmConnectionContext.KMIPConfigData: "some data",
+		},
+		VolumeContext: map[string]string{
+			utils.VolumeParameters.GetSCKey("encryption"): "on",
+		},
+	}
+
+	resp, err := driver.NodePublishVolume(t.Context(), req)
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.ErrorContains(t, err, "missing primary-server in [global] section")
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Handling of encrypted volumes whose storage class lacks a usable node-publish KMIP secret.
+const (
+	// KMIPSecretCheckOff skips the check.
+	KMIPSecretCheckOff = "off"
+	// KMIPSecretCheckWarn logs a warning and creates the volume.
+	KMIPSecretCheckWarn = "warn"
+	// KMIPSecretCheckFail fails CreateVolume with codes.FailedPrecondition.
+	KMIPSecretCheckFail = "fail"
+)
+
+// Storage class parameters referencing the secret passed to NodePublishVolume.
+const (
+	nodePublishSecretNameKey      = "csi.storage.k8s.io/node-publish-secret-name"
+	nodePublishSecretNamespaceKey = "csi.storage.k8s.io/node-publish-secret-namespace"
+)
+
+// secretTemplateRegexp matches the placeholders of templated secret references, e.g. "${pvc.name}".
+var secretTemplateRegexp = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// WithKMIPSecretCheck sets how CreateVolume handles encrypted volumes whose storage class has
+// no node-publish secret with KMIP configuration, see KMIPSecretCheckOff, KMIPSecretCheckWarn
+// and KMIPSecretCheckFail. The check requires the external provisioner to run with
+// --extra-create-metadata.
+//
+// Parameters:
+//
+//	policy - The KMIP secret check policy.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithKMIPSecretCheck(policy string) Option {
+	return func(d *Driver) {
+		d.kmipSecretCheck = policy
+	}
+}
+
+// ValidateKMIPSecretCheck checks that the KMIP secret check policy is supported.
+//
+// Parameters:
+//
+//	policy - The KMIP secret check policy.
+//
+// Returns:
+//
+//	error - Error if the policy is unknown.
+func ValidateKMIPSecretCheck(policy string) error {
+	switch policy {
+	case KMIPSecretCheckOff, KMIPSecretCheckWarn, KMIPSecretCheckFail:
+		return nil
+	default:
+		return fmt.Errorf("invalid KMIP secret check %q: must be %q, %q or %q", policy, KMIPSecretCheckOff, KMIPSecretCheckWarn, KMIPSecretCheckFail)
+	}
+}
+
+// checkKMIPSecret verifies that the node-publish secret of the storage class of an encrypted
+// volume exists and contains KMIP configuration, so that the volume can be mounted. References
+// which cannot be resolved or read, e.g. without PVC metadata or permission to read the secret,
+// are logged and not reported as errors.
+//
+// Parameters:
+//
+//	ctx        - The context for the Kubernetes API calls.
+//	volumeName - The name of the volume, used as PV name in templated secret references.
+//	parameters - The parameters of the CreateVolume request.
+//
+// Returns:
+//
+//	error - Error if the storage class has no usable node-publish KMIP secret.
+func (d *Driver) checkKMIPSecret(ctx context.Context, volumeName string, parameters map[string]string) error {
+	if d.kmipSecretCheck != KMIPSecretCheckWarn && d.kmipSecretCheck != KMIPSecretCheckFail {
+		return nil
+	}
+	if parameters[utils.VolumeParameters.GetSCKey("encryption")] != "on" {
+		return nil
+	}
+
+	pvcName, pvcNamespace := parameters[PVCNameParameterKey], parameters[PVCNamespaceParameterKey]
+	if pvcName == "" || pvcNamespace == "" || d.kubeClient == nil {
+		d.log.V(4).Info("skipping KMIP secret check without PVC metadata", "volume_name", volumeName)
+		return nil
+	}
+
+	pvc, err := d.kubeClient.CoreV1().PersistentVolumeClaims(pvcNamespace).Get(ctx, pvcName, metav1.GetOptions{})
+	if err != nil || pvc.Spec.StorageClassName == nil {
+		d.log.V(4).Info("skipping KMIP secret check without storage class", "pvc", pvcNamespace+"/"+pvcName, "error", err)
+		return nil
+	}
+
+	className := *pvc.Spec.StorageClassName
+	class, err := d.kubeClient.StorageV1().StorageClasses().Get(ctx, className, metav1.GetOptions{})
+	if err != nil {
+		d.log.V(4).Info("skipping KMIP secret check, failed to read storage class", "storage_class", className, "error", err.Error())
+		return nil
+	}
+
+	name, namespace := class.Parameters[nodePublishSecretNameKey], class.Parameters[nodePublishSecretNamespaceKey]
+	if name == "" || namespace == "" {
+		return fmt.Errorf("storage class %s requests encryption but has no node-publish secret with %s, volumes cannot be mounted",
+			className, utils.RealmConnectionContext.KMIPConfigData)
+	}
+
+	values := map[string]string{
+		"pv.name":       volumeName,
+		"pvc.name":      pvcName,
+		"pvc.namespace": pvcNamespace,
+	}
+	for key, value := range pvc.Annotations {
+		values["pvc.annotations['"+key+"']"] = value
+	}
+	name, nameOK := resolveSecretTemplate(name, values)
+	namespace, namespaceOK := resolveSecretTemplate(namespace, values)
+	if !nameOK || !namespaceOK {
+		d.log.V(4).Info("skipping KMIP secret check, failed to resolve node-publish secret reference", "storage_class", className)
+		return nil
+	}
+
+	secret, err := d.kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("node-publish secret %s/%s of storage class %s does not exist, encrypted volumes cannot be mounted",
+			namespace, name, className)
+	}
+	if err != nil {
+		d.log.V(4).Info("skipping KMIP secret check, failed to read node-publish secret", "secret", namespace+"/"+name, "error", err.Error())
+		return nil
+	}
+
+	if len(secret.Data[utils.RealmConnectionContext.KMIPConfigData]) == 0 && secret.StringData[utils.RealmConnectionContext.KMIPConfigData] == "" {
+		return fmt.Errorf("node-publish secret %s/%s of storage class %s has no %s, encrypted volumes cannot be mounted",
+			namespace, name, className, utils.RealmConnectionContext.KMIPConfigData)
+	}
+	return nil
+}
+
+// resolveSecretTemplate replaces the placeholders of a templated secret reference.
+//
+// Parameters:
+//
+//	template - The secret reference, e.g. "${pvc.namespace}-kmip".
+//	values   - The values of the supported placeholders.
+//
+// Returns:
+//
+//	string - The resolved reference.
+//	bool   - False if the reference contains an unknown placeholder or an annotation
+//	         which is not set on the PVC.
+func resolveSecretTemplate(template string, values map[string]string) (string, bool) {
+	ok := true
+	resolved := secretTemplateRegexp.ReplaceAllStringFunc(template, func(match string) string {
+		key := secretTemplateRegexp.FindStringSubmatch(match)[1]
+		value, found := values[key]
+		if !found {
+			ok = false
+			return match
+		}
+		return value
+	})
+	return resolved, ok
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+)
+
+// TestCheckKMIPSecret verifies the check of the node-publish KMIP secret of encrypted volumes.
+func TestCheckKMIPSecret(t *testing.T) {
+	className := "panfs-encrypted"
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "apps", Annotations: map[string]string{"team": "blue"}},
+		Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &className},
+	}
+	class := func(name, namespace string) *storagev1.StorageClass {
+		params := map[string]string{}
+		if name != "" {
+			params[nodePublishSecretNameKey] = name
+			params[nodePublishSecretNamespaceKey] = namespace
+		}
+		return &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: className}, Parameters: params}
+	}
+	kmipSecret := func(name, namespace, data string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Data:       map[string][]byte{utils.RealmConnectionContext.KMIPConfigData: []byte(data)},
+		}
+	}
+	encrypted := map[string]string{
+		utils.VolumeParameters.GetSCKey("encryption"): "on",
+		PVCNameParameterKey:                           pvc.Name,
+		PVCNamespaceParameterKey:                      pvc.Namespace,
+	}
+
+	tests := []struct {
+		name       string
+		policy     string
+		parameters map[string]string
+		objects    []runtime.Object
+		wantErr    string
+	}{
+		{
+			name:       "Valid",
+			policy:     KMIPSecretCheckFail,
+			parameters: encrypted,
+			objects:    []runtime.Object{pvc, class("kmip", "csi-panfs"), kmipSecret("kmip", "csi-panfs", "# config")},
+		},
+		{
+			name:       "TemplatedReference",
+			policy:     KMIPSecretCheckWarn,
+			parameters: encrypted,
+			objects:    []runtime.Object{pvc, class("${pvc.annotations['team']}-kmip", "${pvc.namespace}"), kmipSecret("blue-kmip", "apps", "# config")},
+		},
+		{
+			name:       "NoNodePublishSecret",
+			policy:     KMIPSecretCheckWarn,
+			parameters: encrypted,
+			objects:    []runtime.Object{pvc, class("", "")},
+			wantErr:    "storage class panfs-encrypted requests encryption but has no node-publish secret",
+		},
+		{
+			name:       "SecretMissing",
+			policy:     KMIPSecretCheckWarn,
+			parameters: encrypted,
+			objects:    []runtime.Object{pvc, class("kmip", "csi-panfs")},
+			wantErr:    "node-publish secret csi-panfs/kmip of storage class panfs-encrypted does not exist",
+		},
+		{
+			name:       "KMIPConfigMissing",
+			policy:     KMIPSecretCheckWarn,
+			parameters: encrypted,
+			objects:    []runtime.Object{pvc, class("kmip", "csi-panfs"), kmipSecret("kmip", "csi-panfs", "")},
+			wantErr:    "node-publish secret csi-panfs/kmip of storage class panfs-encrypted has no kmip_config_data",
+		},
+		{
+			name:       "UnresolvedReference",
+			policy:     KMIPSecretCheckWarn,
+			parameters: encrypted,
+			objects:    []runtime.Object{pvc, class("${pvc.annotations['missing']}", "csi-panfs")},
+		},
+		{
+			name:       "NoPVCMetadata",
+			policy:     KMIPSecretCheckWarn,
+			parameters: map[string]string{utils.VolumeParameters.GetSCKey("encryption"): "on"},
+		},
+		{
+			name:       "NotEncrypted",
+			policy:     KMIPSecretCheckWarn,
+			parameters: map[string]string{PVCNameParameterKey: pvc.Name, PVCNamespaceParameterKey: pvc.Namespace},
+			objects:    []runtime.Object{pvc, class("", "")},
+		},
+		{
+			name:       "Disabled",
+			policy:     KMIPSecretCheckOff,
+			parameters: encrypted,
+			objects:    []runtime.Object{pvc, class("", "")},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &Driver{kubeClient: fake.NewClientset(tc.objects...), kmipSecretCheck: tc.policy, log: klog.Background()}
+			err := d.checkKMIPSecret(t.Context(), "pvc-1234", tc.parameters)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestCreateVolumeKMIPSecretCheck verifies that CreateVolume fails before calling the realm
+// if the check is enforced.
+func TestCreateVolumeKMIPSecretCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	pancliMock := mock.NewMockStorageProviderClient(ctrl)
+	pancliMock.EXPECT().CreateVolume(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	className := "panfs-encrypted"
+	d := &Driver{
+		Name:  DefaultDriverName,
+		panfs: pancliMock,
+		kubeClient: fake.NewClientset(
+			&corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "apps"},
+				Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &className},
+			},
+			&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: className}},
+		),
+		kmipSecretCheck: KMIPSecretCheckFail,
+		log:             klog.Background(),
+	}
+
+	_, err := d.CreateVolume(t.Context(), &csi.CreateVolumeRequest{
+		Name: validVolumeName,
+		Parameters: map[string]string{
+			utils.VolumeParameters.GetSCKey("encryption"): "on",
+			PVCNameParameterKey:                           "data",
+			PVCNamespaceParameterKey:                      "apps",
+		},
+		Secrets:            defaultSecrets,
+		VolumeCapabilities: []*csi.VolumeCapability{{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}}},
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.ErrorContains(t, err, "has no node-publish secret")
+}
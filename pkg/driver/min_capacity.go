@@ -0,0 +1,140 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// validateMinCapacityParameters validates the minCapacity and roundUpCapacity storage class
+// parameters.
+func validateMinCapacityParameters(parameters map[string]string) error {
+	if _, err := minCapacity(parameters); err != nil {
+		return err
+	}
+	if val, exist := parameters[utils.VolumeParameters.GetSCKey("roundUpCapacity")]; exist {
+		if _, err := strconv.ParseBool(val); err != nil {
+			return fmt.Errorf("%s must be 'true' or 'false'", utils.VolumeParameters.GetSCKey("roundUpCapacity"))
+		}
+	}
+	return nil
+}
+
+// minCapacity returns the minimum volume size of the minCapacity storage class parameter.
+//
+// Parameters:
+//
+//	parameters - The volume parameters.
+//
+// Returns:
+//
+//	int64 - The minimum size in bytes, 0 if not set.
+//	error - Error if the parameter is not a positive quantity, e.g. "1Gi".
+func minCapacity(parameters map[string]string) (int64, error) {
+	val, exist := parameters[utils.VolumeParameters.GetSCKey("minCapacity")]
+	if !exist {
+		return 0, nil
+	}
+	quantity, err := resource.ParseQuantity(val)
+	if err != nil || quantity.Sign() <= 0 {
+		return 0, fmt.Errorf("%s must be a positive quantity, e.g. 1Gi", utils.VolumeParameters.GetSCKey("minCapacity"))
+	}
+	return quantity.Value(), nil
+}
+
+// roundUpCapacity reports whether requests below the minimum volume size are rounded up to it.
+func roundUpCapacity(parameters map[string]string) bool {
+	roundUp, _ := strconv.ParseBool(parameters[utils.VolumeParameters.GetSCKey("roundUpCapacity")])
+	return roundUp
+}
+
+// minCapacityRange applies the minimum volume size of the storage class to a capacity range.
+// A range without required bytes gets the minimum size. A range below the minimum is rounded
+// up to it if the roundUpCapacity parameter is set, and rejected otherwise.
+//
+// Parameters:
+//
+//	capacity   - The requested capacity range.
+//	parameters - The volume parameters.
+//
+// Returns:
+//
+//	*csi.CapacityRange - The capacity range of the volume to create.
+//	error              - codes.OutOfRange if the range is below the minimum size and cannot be
+//	                     rounded up.
+func minCapacityRange(capacity *csi.CapacityRange, parameters map[string]string) (*csi.CapacityRange, error) {
+	minBytes, err := minCapacity(parameters)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if minBytes == 0 || capacity.GetRequiredBytes() >= minBytes {
+		return capacity, nil
+	}
+	if capacity.GetRequiredBytes() != 0 && !roundUpCapacity(parameters) {
+		return nil, status.Errorf(codes.OutOfRange, "required_bytes (%d) is smaller than the minimum volume size (%d bytes) of the storage class", capacity.GetRequiredBytes(), minBytes)
+	}
+	return roundUpCapacityRange(capacity, minBytes)
+}
+
+// roundUpCapacityRange raises the required bytes of a capacity range to a minimum size.
+//
+// Parameters:
+//
+//	capacity - The requested capacity range.
+//	minBytes - The minimum volume size.
+//
+// Returns:
+//
+//	*csi.CapacityRange - The capacity range requiring the minimum size.
+//	error              - codes.OutOfRange if the limit of the range is below the minimum size.
+func roundUpCapacityRange(capacity *csi.CapacityRange, minBytes int64) (*csi.CapacityRange, error) {
+	limit := capacity.GetLimitBytes()
+	if limit != 0 && limit < minBytes {
+		return nil, status.Errorf(codes.OutOfRange, "limit_bytes (%d) is smaller than the minimum volume size (%d bytes)", limit, minBytes)
+	}
+	return &csi.CapacityRange{RequiredBytes: minBytes, LimitBytes: limit}, nil
+}
+
+// realmMinimumCapacityRange returns the capacity range rounded up to the minimum volume size
+// reported by the realm, if the creation failed because the range is below it and the
+// roundUpCapacity parameter is set.
+//
+// Parameters:
+//
+//	err        - The error of the volume creation.
+//	capacity   - The requested capacity range.
+//	parameters - The volume parameters.
+//
+// Returns:
+//
+//	*csi.CapacityRange - The rounded up capacity range.
+//	bool               - False if the creation cannot be retried with a rounded up range.
+func realmMinimumCapacityRange(err error, capacity *csi.CapacityRange, parameters map[string]string) (*csi.CapacityRange, bool) {
+	var limitErr *pancli.QuotaLimitError
+	if !errors.As(err, &limitErr) || limitErr.MinBytes <= capacity.GetRequiredBytes() || !roundUpCapacity(parameters) {
+		return nil, false
+	}
+	rounded, roundErr := roundUpCapacityRange(capacity, limitErr.MinBytes)
+	return rounded, roundErr == nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestMinCapacityRange verifies the minimum volume size of storage classes.
+func TestMinCapacityRange(t *testing.T) {
+	minKey := utils.VolumeParameters.GetSCKey("minCapacity")
+	roundUpKey := utils.VolumeParameters.GetSCKey("roundUpCapacity")
+
+	tests := []struct {
+		name       string
+		capacity   *csi.CapacityRange
+		parameters map[string]string
+		want       *csi.CapacityRange
+		wantCode   codes.Code
+	}{
+		{
+			name:     "NoMinimum",
+			capacity: &csi.CapacityRange{RequiredBytes: 1 << 20},
+			want:     &csi.CapacityRange{RequiredBytes: 1 << 20},
+		},
+		{
+			name:       "AboveMinimum",
+			capacity:   &csi.CapacityRange{RequiredBytes: 2 << 30},
+			parameters: map[string]string{minKey: "1Gi"},
+			want:       &csi.CapacityRange{RequiredBytes: 2 << 30},
+		},
+		{
+			name:       "BelowMinimum",
+			capacity:   &csi.CapacityRange{RequiredBytes: 1 << 20},
+			parameters: map[string]string{minKey: "1Gi"},
+			wantCode:   codes.OutOfRange,
+		},
+		{
+			name:       "RoundUp",
+			capacity:   &csi.CapacityRange{RequiredBytes: 1 << 20, LimitBytes: 4 << 30},
+			parameters: map[string]string{minKey: "1Gi", roundUpKey: "true"},
+			want:       &csi.CapacityRange{RequiredBytes: 1 << 30, LimitBytes: 4 << 30},
+		},
+		{
+			name:       "RequiredBytesUnset",
+			capacity:   &csi.CapacityRange{},
+			parameters: map[string]string{minKey: "1Gi"},
+			want:       &csi.CapacityRange{RequiredBytes: 1 << 30},
+		},
+		{
+			name:       "LimitBelowMinimum",
+			capacity:   &csi.CapacityRange{RequiredBytes: 1 << 20, LimitBytes: 2 << 20},
+			parameters: map[string]string{minKey: "1Gi", roundUpKey: "true"},
+			wantCode:   codes.OutOfRange,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := minCapacityRange(tc.capacity, tc.parameters)
+			if tc.wantCode != codes.OK {
+				assert.Equal(t, tc.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want.GetRequiredBytes(), got.GetRequiredBytes())
+			assert.Equal(t, tc.want.GetLimitBytes(), got.GetLimitBytes())
+		})
+	}
+}
+
+// TestValidateMinCapacityParameters verifies the validation of the minimum volume size parameters.
+func TestValidateMinCapacityParameters(t *testing.T) {
+	minKey := utils.VolumeParameters.GetSCKey("minCapacity")
+	roundUpKey := utils.VolumeParameters.GetSCKey("roundUpCapacity")
+
+	assert.NoError(t, validateVolumeParameters(map[string]string{minKey: "500M", roundUpKey: "false"}))
+	assert.Error(t, validateVolumeParameters(map[string]string{minKey: "0"}))
+	assert.Error(t, validateVolumeParameters(map[string]string{minKey: "big"}))
+	assert.Error(t, validateVolumeParameters(map[string]string{roundUpKey: "yes"}))
+}
+
+// TestCreateVolumeRealmMinimum verifies the handling of capacities below the minimum volume
+// size reported by the realm.
+func TestCreateVolumeRealmMinimum(t *testing.T) {
+	newRequest := func(parameters map[string]string) *csi.CreateVolumeRequest {
+		return &csi.CreateVolumeRequest{
+			Name:          validVolumeName,
+			CapacityRange: &csi.CapacityRange{RequiredBytes: 1 << 20},
+			Parameters:    parameters,
+			Secrets:       defaultSecrets,
+			VolumeCapabilities: []*csi.VolumeCapability{
+				{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}},
+			},
+		}
+	}
+	belowMinimum := &pancli.QuotaLimitError{MinBytes: 1 << 30, Err: pancli.ErrorOutOfRange}
+
+	t.Run("OutOfRange", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), defaultSecrets).Return(nil, belowMinimum)
+
+		_, err := d.CreateVolume(t.Context(), newRequest(map[string]string{}))
+		st := status.Convert(err)
+		assert.Equal(t, codes.OutOfRange, st.Code())
+		assert.Contains(t, st.Message(), "minimum 1073741824 bytes")
+		require.Len(t, st.Details(), 1)
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		require.True(t, ok)
+		assert.Equal(t, QuotaBelowMinimumReason, info.Reason)
+		assert.Equal(t, strconv.Itoa(1<<30), info.Metadata["min_bytes"])
+	})
+
+	t.Run("RoundUp", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		gomock.InOrder(
+			pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), defaultSecrets).Return(nil, belowMinimum),
+			pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), defaultSecrets).
+				DoAndReturn(func(_ context.Context, _ string, params pancli.VolumeCreateParams, _ map[string]string) (*utils.Volume, error) {
+					assert.Equal(t, "1.00", params[utils.VolumeParameters.GetSCKey("soft")])
+					return &utils.Volume{Name: utils.VolumeName(validVolumeName), Soft: 1}, nil
+				}),
+		)
+
+		resp, err := d.CreateVolume(t.Context(), newRequest(map[string]string{utils.VolumeParameters.GetSCKey("roundUpCapacity"): "true"}))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1<<30), resp.GetVolume().GetCapacityBytes())
+	})
+}
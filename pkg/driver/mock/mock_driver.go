@@ -1,15 +1,11 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: driver.go
-//
-// Generated by this command:
-//
-//	mockgen -source=driver.go -destination=mock/mock_driver.go -package=mock StorageProviderClient PanMounter
-//
+// Source: github.com/panasasinc/panfs-container-storage-interface-oss/pkg/interfaces (interfaces: StorageProviderClient,PanMounter)
 
 // Package mock is a generated GoMock package.
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 
 	pancli "github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
@@ -41,77 +37,238 @@ func (m *MockStorageProviderClient) EXPECT() *MockStorageProviderClientMockRecor
 	return m.recorder
 }
 
+// CreateDirectory mocks base method.
+func (m *MockStorageProviderClient) CreateDirectory(ctx context.Context, volumeName, directory string, hardBytes int64, secret map[string]string) (*utils.Directory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDirectory", ctx, volumeName, directory, hardBytes, secret)
+	ret0, _ := ret[0].(*utils.Directory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDirectory indicates an expected call of CreateDirectory.
+func (mr *MockStorageProviderClientMockRecorder) CreateDirectory(ctx, volumeName, directory, hardBytes, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDirectory", reflect.TypeOf((*MockStorageProviderClient)(nil).CreateDirectory), ctx, volumeName, directory, hardBytes, secret)
+}
+
+// CreateSnapshot mocks base method.
+func (m *MockStorageProviderClient) CreateSnapshot(ctx context.Context, volumeName, snapshotName string, secret map[string]string) (*utils.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSnapshot", ctx, volumeName, snapshotName, secret)
+	ret0, _ := ret[0].(*utils.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSnapshot indicates an expected call of CreateSnapshot.
+func (mr *MockStorageProviderClientMockRecorder) CreateSnapshot(ctx, volumeName, snapshotName, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnapshot", reflect.TypeOf((*MockStorageProviderClient)(nil).CreateSnapshot), ctx, volumeName, snapshotName, secret)
+}
+
 // CreateVolume mocks base method.
-func (m *MockStorageProviderClient) CreateVolume(volumeName string, params pancli.VolumeCreateParams, secret map[string]string) (*utils.Volume, error) {
+func (m *MockStorageProviderClient) CreateVolume(ctx context.Context, volumeName string, params pancli.VolumeCreateParams, secret map[string]string) (*utils.Volume, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateVolume", volumeName, params, secret)
+	ret := m.ctrl.Call(m, "CreateVolume", ctx, volumeName, params, secret)
 	ret0, _ := ret[0].(*utils.Volume)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateVolume indicates an expected call of CreateVolume.
-func (mr *MockStorageProviderClientMockRecorder) CreateVolume(volumeName, params, secret any) *gomock.Call {
+func (mr *MockStorageProviderClientMockRecorder) CreateVolume(ctx, volumeName, params, secret any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVolume", reflect.TypeOf((*MockStorageProviderClient)(nil).CreateVolume), volumeName, params, secret)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVolume", reflect.TypeOf((*MockStorageProviderClient)(nil).CreateVolume), ctx, volumeName, params, secret)
+}
+
+// CreateVolumeFromSnapshot mocks base method.
+func (m *MockStorageProviderClient) CreateVolumeFromSnapshot(ctx context.Context, volumeName, sourceVolume, snapshotName string, params pancli.VolumeCreateParams, secret map[string]string) (*utils.Volume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVolumeFromSnapshot", ctx, volumeName, sourceVolume, snapshotName, params, secret)
+	ret0, _ := ret[0].(*utils.Volume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVolumeFromSnapshot indicates an expected call of CreateVolumeFromSnapshot.
+func (mr *MockStorageProviderClientMockRecorder) CreateVolumeFromSnapshot(ctx, volumeName, sourceVolume, snapshotName, params, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVolumeFromSnapshot", reflect.TypeOf((*MockStorageProviderClient)(nil).CreateVolumeFromSnapshot), ctx, volumeName, sourceVolume, snapshotName, params, secret)
+}
+
+// DeleteDirectory mocks base method.
+func (m *MockStorageProviderClient) DeleteDirectory(ctx context.Context, volumeName, directory string, secret map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDirectory", ctx, volumeName, directory, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDirectory indicates an expected call of DeleteDirectory.
+func (mr *MockStorageProviderClientMockRecorder) DeleteDirectory(ctx, volumeName, directory, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDirectory", reflect.TypeOf((*MockStorageProviderClient)(nil).DeleteDirectory), ctx, volumeName, directory, secret)
+}
+
+// DeleteSnapshot mocks base method.
+func (m *MockStorageProviderClient) DeleteSnapshot(ctx context.Context, volumeName, snapshotName string, secret map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSnapshot", ctx, volumeName, snapshotName, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSnapshot indicates an expected call of DeleteSnapshot.
+func (mr *MockStorageProviderClientMockRecorder) DeleteSnapshot(ctx, volumeName, snapshotName, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSnapshot", reflect.TypeOf((*MockStorageProviderClient)(nil).DeleteSnapshot), ctx, volumeName, snapshotName, secret)
 }
 
 // DeleteVolume mocks base method.
-func (m *MockStorageProviderClient) DeleteVolume(volID string, secret map[string]string) error {
+func (m *MockStorageProviderClient) DeleteVolume(ctx context.Context, volID string, secret map[string]string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteVolume", volID, secret)
+	ret := m.ctrl.Call(m, "DeleteVolume", ctx, volID, secret)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteVolume indicates an expected call of DeleteVolume.
-func (mr *MockStorageProviderClientMockRecorder) DeleteVolume(volID, secret any) *gomock.Call {
+func (mr *MockStorageProviderClientMockRecorder) DeleteVolume(ctx, volID, secret any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVolume", reflect.TypeOf((*MockStorageProviderClient)(nil).DeleteVolume), volID, secret)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVolume", reflect.TypeOf((*MockStorageProviderClient)(nil).DeleteVolume), ctx, volID, secret)
 }
 
 // ExpandVolume mocks base method.
-func (m *MockStorageProviderClient) ExpandVolume(volumeName string, targetSize int64, secret map[string]string) error {
+func (m *MockStorageProviderClient) ExpandVolume(ctx context.Context, volumeName string, targetSize int64, secret map[string]string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExpandVolume", volumeName, targetSize, secret)
+	ret := m.ctrl.Call(m, "ExpandVolume", ctx, volumeName, targetSize, secret)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ExpandVolume indicates an expected call of ExpandVolume.
-func (mr *MockStorageProviderClientMockRecorder) ExpandVolume(volumeName, targetSize, secret any) *gomock.Call {
+func (mr *MockStorageProviderClientMockRecorder) ExpandVolume(ctx, volumeName, targetSize, secret any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpandVolume", reflect.TypeOf((*MockStorageProviderClient)(nil).ExpandVolume), volumeName, targetSize, secret)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpandVolume", reflect.TypeOf((*MockStorageProviderClient)(nil).ExpandVolume), ctx, volumeName, targetSize, secret)
+}
+
+// GetCapacity mocks base method.
+func (m *MockStorageProviderClient) GetCapacity(ctx context.Context, bladeset string, secret map[string]string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCapacity", ctx, bladeset, secret)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCapacity indicates an expected call of GetCapacity.
+func (mr *MockStorageProviderClientMockRecorder) GetCapacity(ctx, bladeset, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCapacity", reflect.TypeOf((*MockStorageProviderClient)(nil).GetCapacity), ctx, bladeset, secret)
+}
+
+// GetDirectory mocks base method.
+func (m *MockStorageProviderClient) GetDirectory(ctx context.Context, volumeName, directory string, secret map[string]string) (*utils.Directory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDirectory", ctx, volumeName, directory, secret)
+	ret0, _ := ret[0].(*utils.Directory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDirectory indicates an expected call of GetDirectory.
+func (mr *MockStorageProviderClientMockRecorder) GetDirectory(ctx, volumeName, directory, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDirectory", reflect.TypeOf((*MockStorageProviderClient)(nil).GetDirectory), ctx, volumeName, directory, secret)
+}
+
+// GetRealmFeatures mocks base method.
+func (m *MockStorageProviderClient) GetRealmFeatures(ctx context.Context, secret map[string]string) (*utils.RealmFeatures, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRealmFeatures", ctx, secret)
+	ret0, _ := ret[0].(*utils.RealmFeatures)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRealmFeatures indicates an expected call of GetRealmFeatures.
+func (mr *MockStorageProviderClientMockRecorder) GetRealmFeatures(ctx, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRealmFeatures", reflect.TypeOf((*MockStorageProviderClient)(nil).GetRealmFeatures), ctx, secret)
 }
 
 // GetVolume mocks base method.
-func (m *MockStorageProviderClient) GetVolume(volumeName string, secret map[string]string) (*utils.Volume, error) {
+func (m *MockStorageProviderClient) GetVolume(ctx context.Context, volumeName string, secret map[string]string) (*utils.Volume, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetVolume", volumeName, secret)
+	ret := m.ctrl.Call(m, "GetVolume", ctx, volumeName, secret)
 	ret0, _ := ret[0].(*utils.Volume)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetVolume indicates an expected call of GetVolume.
-func (mr *MockStorageProviderClientMockRecorder) GetVolume(volumeName, secret any) *gomock.Call {
+func (mr *MockStorageProviderClientMockRecorder) GetVolume(ctx, volumeName, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolume", reflect.TypeOf((*MockStorageProviderClient)(nil).GetVolume), ctx, volumeName, secret)
+}
+
+// ListSnapshots mocks base method.
+func (m *MockStorageProviderClient) ListSnapshots(ctx context.Context, volumeName string, secret map[string]string) (*utils.SnapshotList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSnapshots", ctx, volumeName, secret)
+	ret0, _ := ret[0].(*utils.SnapshotList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSnapshots indicates an expected call of ListSnapshots.
+func (mr *MockStorageProviderClientMockRecorder) ListSnapshots(ctx, volumeName, secret any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolume", reflect.TypeOf((*MockStorageProviderClient)(nil).GetVolume), volumeName, secret)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSnapshots", reflect.TypeOf((*MockStorageProviderClient)(nil).ListSnapshots), ctx, volumeName, secret)
 }
 
 // ListVolumes mocks base method.
-func (m *MockStorageProviderClient) ListVolumes(secret map[string]string) (*utils.VolumeList, error) {
+func (m *MockStorageProviderClient) ListVolumes(ctx context.Context, secret map[string]string) (*utils.VolumeList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListVolumes", secret)
+	ret := m.ctrl.Call(m, "ListVolumes", ctx, secret)
 	ret0, _ := ret[0].(*utils.VolumeList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListVolumes indicates an expected call of ListVolumes.
-func (mr *MockStorageProviderClientMockRecorder) ListVolumes(secret any) *gomock.Call {
+func (mr *MockStorageProviderClientMockRecorder) ListVolumes(ctx, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVolumes", reflect.TypeOf((*MockStorageProviderClient)(nil).ListVolumes), ctx, secret)
+}
+
+// ModifyVolume mocks base method.
+func (m *MockStorageProviderClient) ModifyVolume(ctx context.Context, volumeName string, params pancli.VolumeModifyParams, secret map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifyVolume", ctx, volumeName, params, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModifyVolume indicates an expected call of ModifyVolume.
+func (mr *MockStorageProviderClientMockRecorder) ModifyVolume(ctx, volumeName, params, secret any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVolumes", reflect.TypeOf((*MockStorageProviderClient)(nil).ListVolumes), secret)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyVolume", reflect.TypeOf((*MockStorageProviderClient)(nil).ModifyVolume), ctx, volumeName, params, secret)
+}
+
+// SetDirectoryQuota mocks base method.
+func (m *MockStorageProviderClient) SetDirectoryQuota(ctx context.Context, volumeName, directory string, hardBytes int64, secret map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDirectoryQuota", ctx, volumeName, directory, hardBytes, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDirectoryQuota indicates an expected call of SetDirectoryQuota.
+func (mr *MockStorageProviderClientMockRecorder) SetDirectoryQuota(ctx, volumeName, directory, hardBytes, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDirectoryQuota", reflect.TypeOf((*MockStorageProviderClient)(nil).SetDirectoryQuota), ctx, volumeName, directory, hardBytes, secret)
 }
 
 // MockPanMounter is a mock of PanMounter interface.
@@ -139,43 +296,72 @@ func (m *MockPanMounter) EXPECT() *MockPanMounterMockRecorder {
 }
 
 // BindMount mocks base method.
-func (m *MockPanMounter) BindMount(source, target string, options []string) error {
+func (m *MockPanMounter) BindMount(ctx context.Context, source, target string, options []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BindMount", source, target, options)
+	ret := m.ctrl.Call(m, "BindMount", ctx, source, target, options)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // BindMount indicates an expected call of BindMount.
-func (mr *MockPanMounterMockRecorder) BindMount(source, target, options any) *gomock.Call {
+func (mr *MockPanMounterMockRecorder) BindMount(ctx, source, target, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BindMount", reflect.TypeOf((*MockPanMounter)(nil).BindMount), ctx, source, target, options)
+}
+
+// IsMountPoint mocks base method.
+func (m *MockPanMounter) IsMountPoint(ctx context.Context, target string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsMountPoint", ctx, target)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsMountPoint indicates an expected call of IsMountPoint.
+func (mr *MockPanMounterMockRecorder) IsMountPoint(ctx, target any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BindMount", reflect.TypeOf((*MockPanMounter)(nil).BindMount), source, target, options)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsMountPoint", reflect.TypeOf((*MockPanMounter)(nil).IsMountPoint), ctx, target)
 }
 
 // Mount mocks base method.
-func (m *MockPanMounter) Mount(source, target string, options []string) error {
+func (m *MockPanMounter) Mount(ctx context.Context, source, target string, options []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Mount", source, target, options)
+	ret := m.ctrl.Call(m, "Mount", ctx, source, target, options)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Mount indicates an expected call of Mount.
-func (mr *MockPanMounterMockRecorder) Mount(source, target, options any) *gomock.Call {
+func (mr *MockPanMounterMockRecorder) Mount(ctx, source, target, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mount", reflect.TypeOf((*MockPanMounter)(nil).Mount), ctx, source, target, options)
+}
+
+// Remount mocks base method.
+func (m *MockPanMounter) Remount(ctx context.Context, target string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remount", ctx, target)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remount indicates an expected call of Remount.
+func (mr *MockPanMounterMockRecorder) Remount(ctx, target any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mount", reflect.TypeOf((*MockPanMounter)(nil).Mount), source, target, options)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remount", reflect.TypeOf((*MockPanMounter)(nil).Remount), ctx, target)
 }
 
 // Unmount mocks base method.
-func (m *MockPanMounter) Unmount(target string) error {
+func (m *MockPanMounter) Unmount(ctx context.Context, target string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Unmount", target)
+	ret := m.ctrl.Call(m, "Unmount", ctx, target)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Unmount indicates an expected call of Unmount.
-func (mr *MockPanMounterMockRecorder) Unmount(target any) *gomock.Call {
+func (mr *MockPanMounterMockRecorder) Unmount(ctx, target any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unmount", reflect.TypeOf((*MockPanMounter)(nil).Unmount), target)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unmount", reflect.TypeOf((*MockPanMounter)(nil).Unmount), ctx, target)
 }
@@ -1,10 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: mounter.go
-//
-// Generated by this command:
-//
-//	mockgen -source=mounter.go -destination=mock/mock_kmounter.go -package=mock kMounter
-//
+// Source: github.com/panasasinc/panfs-container-storage-interface-oss/pkg/interfaces (interfaces: KMounter)
 
 // Package mock is a generated GoMock package.
 package mock
@@ -16,32 +11,32 @@ import (
 	mount "k8s.io/mount-utils"
 )
 
-// MockkMounter is a mock of kMounter interface.
-type MockkMounter struct {
+// MockKMounter is a mock of KMounter interface.
+type MockKMounter struct {
 	ctrl     *gomock.Controller
-	recorder *MockkMounterMockRecorder
+	recorder *MockKMounterMockRecorder
 	isgomock struct{}
 }
 
-// MockkMounterMockRecorder is the mock recorder for MockkMounter.
-type MockkMounterMockRecorder struct {
-	mock *MockkMounter
+// MockKMounterMockRecorder is the mock recorder for MockKMounter.
+type MockKMounterMockRecorder struct {
+	mock *MockKMounter
 }
 
-// NewMockkMounter creates a new mock instance.
-func NewMockkMounter(ctrl *gomock.Controller) *MockkMounter {
-	mock := &MockkMounter{ctrl: ctrl}
-	mock.recorder = &MockkMounterMockRecorder{mock}
+// NewMockKMounter creates a new mock instance.
+func NewMockKMounter(ctrl *gomock.Controller) *MockKMounter {
+	mock := &MockKMounter{ctrl: ctrl}
+	mock.recorder = &MockKMounterMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockkMounter) EXPECT() *MockkMounterMockRecorder {
+func (m *MockKMounter) EXPECT() *MockKMounterMockRecorder {
 	return m.recorder
 }
 
 // CanSafelySkipMountPointCheck mocks base method.
-func (m *MockkMounter) CanSafelySkipMountPointCheck() bool {
+func (m *MockKMounter) CanSafelySkipMountPointCheck() bool {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CanSafelySkipMountPointCheck")
 	ret0, _ := ret[0].(bool)
@@ -49,13 +44,13 @@ func (m *MockkMounter) CanSafelySkipMountPointCheck() bool {
 }
 
 // CanSafelySkipMountPointCheck indicates an expected call of CanSafelySkipMountPointCheck.
-func (mr *MockkMounterMockRecorder) CanSafelySkipMountPointCheck() *gomock.Call {
+func (mr *MockKMounterMockRecorder) CanSafelySkipMountPointCheck() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanSafelySkipMountPointCheck", reflect.TypeOf((*MockkMounter)(nil).CanSafelySkipMountPointCheck))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanSafelySkipMountPointCheck", reflect.TypeOf((*MockKMounter)(nil).CanSafelySkipMountPointCheck))
 }
 
 // GetMountRefs mocks base method.
-func (m *MockkMounter) GetMountRefs(pathname string) ([]string, error) {
+func (m *MockKMounter) GetMountRefs(pathname string) ([]string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetMountRefs", pathname)
 	ret0, _ := ret[0].([]string)
@@ -64,13 +59,13 @@ func (m *MockkMounter) GetMountRefs(pathname string) ([]string, error) {
 }
 
 // GetMountRefs indicates an expected call of GetMountRefs.
-func (mr *MockkMounterMockRecorder) GetMountRefs(pathname any) *gomock.Call {
+func (mr *MockKMounterMockRecorder) GetMountRefs(pathname any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMountRefs", reflect.TypeOf((*MockkMounter)(nil).GetMountRefs), pathname)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMountRefs", reflect.TypeOf((*MockKMounter)(nil).GetMountRefs), pathname)
 }
 
 // IsLikelyNotMountPoint mocks base method.
-func (m *MockkMounter) IsLikelyNotMountPoint(file string) (bool, error) {
+func (m *MockKMounter) IsLikelyNotMountPoint(file string) (bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "IsLikelyNotMountPoint", file)
 	ret0, _ := ret[0].(bool)
@@ -79,13 +74,13 @@ func (m *MockkMounter) IsLikelyNotMountPoint(file string) (bool, error) {
 }
 
 // IsLikelyNotMountPoint indicates an expected call of IsLikelyNotMountPoint.
-func (mr *MockkMounterMockRecorder) IsLikelyNotMountPoint(file any) *gomock.Call {
+func (mr *MockKMounterMockRecorder) IsLikelyNotMountPoint(file any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLikelyNotMountPoint", reflect.TypeOf((*MockkMounter)(nil).IsLikelyNotMountPoint), file)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLikelyNotMountPoint", reflect.TypeOf((*MockKMounter)(nil).IsLikelyNotMountPoint), file)
 }
 
 // IsMountPoint mocks base method.
-func (m *MockkMounter) IsMountPoint(file string) (bool, error) {
+func (m *MockKMounter) IsMountPoint(file string) (bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "IsMountPoint", file)
 	ret0, _ := ret[0].(bool)
@@ -94,13 +89,13 @@ func (m *MockkMounter) IsMountPoint(file string) (bool, error) {
 }
 
 // IsMountPoint indicates an expected call of IsMountPoint.
-func (mr *MockkMounterMockRecorder) IsMountPoint(file any) *gomock.Call {
+func (mr *MockKMounterMockRecorder) IsMountPoint(file any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsMountPoint", reflect.TypeOf((*MockkMounter)(nil).IsMountPoint), file)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsMountPoint", reflect.TypeOf((*MockKMounter)(nil).IsMountPoint), file)
 }
 
 // List mocks base method.
-func (m *MockkMounter) List() ([]mount.MountPoint, error) {
+func (m *MockKMounter) List() ([]mount.MountPoint, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "List")
 	ret0, _ := ret[0].([]mount.MountPoint)
@@ -109,13 +104,13 @@ func (m *MockkMounter) List() ([]mount.MountPoint, error) {
 }
 
 // List indicates an expected call of List.
-func (mr *MockkMounterMockRecorder) List() *gomock.Call {
+func (mr *MockKMounterMockRecorder) List() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockkMounter)(nil).List))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockKMounter)(nil).List))
 }
 
 // Mount mocks base method.
-func (m *MockkMounter) Mount(source, target, fstype string, options []string) error {
+func (m *MockKMounter) Mount(source, target, fstype string, options []string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Mount", source, target, fstype, options)
 	ret0, _ := ret[0].(error)
@@ -123,13 +118,13 @@ func (m *MockkMounter) Mount(source, target, fstype string, options []string) er
 }
 
 // Mount indicates an expected call of Mount.
-func (mr *MockkMounterMockRecorder) Mount(source, target, fstype, options any) *gomock.Call {
+func (mr *MockKMounterMockRecorder) Mount(source, target, fstype, options any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mount", reflect.TypeOf((*MockkMounter)(nil).Mount), source, target, fstype, options)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mount", reflect.TypeOf((*MockKMounter)(nil).Mount), source, target, fstype, options)
 }
 
 // MountSensitive mocks base method.
-func (m *MockkMounter) MountSensitive(source, target, fstype string, options, sensitiveOptions []string) error {
+func (m *MockKMounter) MountSensitive(source, target, fstype string, options, sensitiveOptions []string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "MountSensitive", source, target, fstype, options, sensitiveOptions)
 	ret0, _ := ret[0].(error)
@@ -137,13 +132,13 @@ func (m *MockkMounter) MountSensitive(source, target, fstype string, options, se
 }
 
 // MountSensitive indicates an expected call of MountSensitive.
-func (mr *MockkMounterMockRecorder) MountSensitive(source, target, fstype, options, sensitiveOptions any) *gomock.Call {
+func (mr *MockKMounterMockRecorder) MountSensitive(source, target, fstype, options, sensitiveOptions any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MountSensitive", reflect.TypeOf((*MockkMounter)(nil).MountSensitive), source, target, fstype, options, sensitiveOptions)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MountSensitive", reflect.TypeOf((*MockKMounter)(nil).MountSensitive), source, target, fstype, options, sensitiveOptions)
 }
 
 // MountSensitiveWithoutSystemd mocks base method.
-func (m *MockkMounter) MountSensitiveWithoutSystemd(source, target, fstype string, options, sensitiveOptions []string) error {
+func (m *MockKMounter) MountSensitiveWithoutSystemd(source, target, fstype string, options, sensitiveOptions []string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "MountSensitiveWithoutSystemd", source, target, fstype, options, sensitiveOptions)
 	ret0, _ := ret[0].(error)
@@ -151,13 +146,13 @@ func (m *MockkMounter) MountSensitiveWithoutSystemd(source, target, fstype strin
 }
 
 // MountSensitiveWithoutSystemd indicates an expected call of MountSensitiveWithoutSystemd.
-func (mr *MockkMounterMockRecorder) MountSensitiveWithoutSystemd(source, target, fstype, options, sensitiveOptions any) *gomock.Call {
+func (mr *MockKMounterMockRecorder) MountSensitiveWithoutSystemd(source, target, fstype, options, sensitiveOptions any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MountSensitiveWithoutSystemd", reflect.TypeOf((*MockkMounter)(nil).MountSensitiveWithoutSystemd), source, target, fstype, options, sensitiveOptions)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MountSensitiveWithoutSystemd", reflect.TypeOf((*MockKMounter)(nil).MountSensitiveWithoutSystemd), source, target, fstype, options, sensitiveOptions)
 }
 
 // MountSensitiveWithoutSystemdWithMountFlags mocks base method.
-func (m *MockkMounter) MountSensitiveWithoutSystemdWithMountFlags(source, target, fstype string, options, sensitiveOptions, mountFlags []string) error {
+func (m *MockKMounter) MountSensitiveWithoutSystemdWithMountFlags(source, target, fstype string, options, sensitiveOptions, mountFlags []string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "MountSensitiveWithoutSystemdWithMountFlags", source, target, fstype, options, sensitiveOptions, mountFlags)
 	ret0, _ := ret[0].(error)
@@ -165,13 +160,13 @@ func (m *MockkMounter) MountSensitiveWithoutSystemdWithMountFlags(source, target
 }
 
 // MountSensitiveWithoutSystemdWithMountFlags indicates an expected call of MountSensitiveWithoutSystemdWithMountFlags.
-func (mr *MockkMounterMockRecorder) MountSensitiveWithoutSystemdWithMountFlags(source, target, fstype, options, sensitiveOptions, mountFlags any) *gomock.Call {
+func (mr *MockKMounterMockRecorder) MountSensitiveWithoutSystemdWithMountFlags(source, target, fstype, options, sensitiveOptions, mountFlags any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MountSensitiveWithoutSystemdWithMountFlags", reflect.TypeOf((*MockkMounter)(nil).MountSensitiveWithoutSystemdWithMountFlags), source, target, fstype, options, sensitiveOptions, mountFlags)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MountSensitiveWithoutSystemdWithMountFlags", reflect.TypeOf((*MockKMounter)(nil).MountSensitiveWithoutSystemdWithMountFlags), source, target, fstype, options, sensitiveOptions, mountFlags)
 }
 
 // Unmount mocks base method.
-func (m *MockkMounter) Unmount(target string) error {
+func (m *MockKMounter) Unmount(target string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Unmount", target)
 	ret0, _ := ret[0].(error)
@@ -179,7 +174,7 @@ func (m *MockkMounter) Unmount(target string) error {
 }
 
 // Unmount indicates an expected call of Unmount.
-func (mr *MockkMounterMockRecorder) Unmount(target any) *gomock.Call {
+func (mr *MockKMounterMockRecorder) Unmount(target any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unmount", reflect.TypeOf((*MockkMounter)(nil).Unmount), target)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unmount", reflect.TypeOf((*MockKMounter)(nil).Unmount), target)
 }
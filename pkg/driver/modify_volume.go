@@ -0,0 +1,231 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ControllerModifyVolume handles the CSI ControllerModifyVolume request, changing the mutable
+// parameters of a volume set by a Kubernetes VolumeAttributesClass, see
+// pancli.MutableVolumeParameters. The hard parameter is a quantity, e.g. "20Gi", or "0" for an
+// unlimited hard quota; the soft quota is the capacity of the volume and is changed by
+// expanding it. A changed description keeps the protection tier of the volume.
+//
+// Parameters:
+//
+//	ctx - The context for the request.
+//	in  - The ControllerModifyVolumeRequest containing volume ID, mutable parameters and secrets.
+//
+// Returns:
+//
+//	*csi.ControllerModifyVolumeResponse - The empty response once all parameters are applied.
+//	error - Returns an error if validation fails or a parameter cannot be applied.
+//
+// Error Cases:
+//   - codes.InvalidArgument: If the volume ID or secrets are invalid, or a parameter is not
+//     mutable or has an invalid value, or if the volume is a directory of a parent volume.
+//     Requests without secrets use the provisioner secret of the storage class of the volume.
+//   - codes.NotFound: If the volume does not exist.
+//   - codes.OutOfRange: If the hard quota exceeds the limits of the realm.
+//   - codes.FailedPrecondition: If the realm does not support changing a parameter.
+//   - codes.Aborted: If another CreateVolume, DeleteVolume, ControllerExpandVolume or
+//     ControllerModifyVolume request on the volume is in progress.
+//   - codes.Unavailable: If all session slots of the realm stay busy (see WithRealmConcurrencyLimit).
+//   - codes.Canceled, codes.DeadlineExceeded: If the request ends while a realm command runs;
+//     the parameters applied so far are kept and a retry applies all of them again.
+//   - codes.Internal: For unexpected internal errors.
+func (d *Driver) ControllerModifyVolume(ctx context.Context, in *csi.ControllerModifyVolumeRequest) (*csi.ControllerModifyVolumeResponse, error) {
+	llog := d.requestLogger(ctx).WithValues("method", "ControllerModifyVolume")
+	llog.V(2).Info("ControllerModifyVolume called",
+		"volume_id", in.VolumeId,
+		"mutable_parameters", in.MutableParameters,
+	)
+
+	volumeID := in.GetVolumeId()
+	if volumeID == "" {
+		llog.Error(fmt.Errorf("volume id must be provided"), InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, "volume id must be provided")
+	}
+
+	if _, _, ok := utils.ParseDirectoryVolumeID(volumeID); ok {
+		llog.Error(fmt.Errorf("mutable parameters of directory volumes are not supported"), InvalidRequestErrorStr, "volume_id", volumeID)
+		return nil, directoryVolumeUnsupported("mutable parameters", volumeID)
+	}
+
+	params, err := mutableVolumeParameters(in.GetMutableParameters())
+	if err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if len(params) == 0 {
+		return &csi.ControllerModifyVolumeResponse{}, nil
+	}
+
+	unlock, err := d.volumeLocks.tryLock(volumeID, "modify")
+	if err != nil {
+		llog.Error(err, "volume operation in progress")
+		return nil, err
+	}
+	defer unlock()
+
+	requestSecrets := in.GetSecrets()
+	if len(requestSecrets) == 0 {
+		// the storage class has no controller-modify secret, fall back to its provisioner secret
+		fallback, err := d.expansionSecrets(ctx, volumeID)
+		if err != nil {
+			llog.Error(err, "failed to read the provisioner secret of the volume", "volume_id", volumeID)
+		} else {
+			llog.V(4).Info("using the provisioner secret of the storage class", "volume_id", volumeID)
+			requestSecrets = fallback
+		}
+	}
+
+	secrets, err := d.resolveCredentials(ctx, requestSecrets, nil)
+	if err != nil {
+		llog.Error(err, "failed to resolve realm credentials")
+		return nil, err
+	}
+	if _, err := d.parseRealmSecrets(secrets); err != nil {
+		llog.Error(err, InvalidRequestSecretsErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	release, err := d.realmLimiter.acquire(ctx, secrets[utils.RealmConnectionContext.RealmAddress], priorityForeground)
+	if err != nil {
+		llog.Error(err, "realm session slots are busy")
+		return nil, err
+	}
+	defer release()
+
+	if description, ok := params[utils.VolumeParameters.GetSCKey("description")]; ok {
+		// the protection tier is tagged in the description, keep it
+		vol, err := d.realm(ctx).GetVolume(ctx, volumeID, secrets)
+		if err != nil {
+			llog.Error(err, "failed to get volume", "volume_id", volumeID)
+			return nil, realmError(err)
+		}
+		tier := utils.ProtectionTier(vol.Description)
+		if _, err := limitStringParameters(params, secrets[utils.RealmConnectionContext.RealmVersion], descriptionTagsLength(tier)); err != nil {
+			llog.Error(err, InvalidRequestErrorStr)
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		params[utils.VolumeParameters.GetSCKey("description")] = utils.TagProtectionTier(description, tier)
+	}
+
+	if err := d.realm(ctx).ModifyVolume(ctx, volumeID, params, secrets); err != nil {
+		llog.Error(err, "failed to modify volume", "volume_id", volumeID)
+		if errors.Is(err, pancli.ErrorOutOfRange) {
+			return nil, d.quotaLimitError(err)
+		}
+		return nil, realmError(err)
+	}
+
+	llog.Info("volume modified", "volume_id", volumeID, "mutable_parameters", params)
+	return &csi.ControllerModifyVolumeResponse{}, nil
+}
+
+// mutableVolumeParameters validates the mutable parameters of a request and converts them
+// into the changes of a volume.
+//
+// Parameters:
+//
+//	parameters - The mutable parameters, keyed by storage class parameter keys.
+//
+// Returns:
+//
+//	pancli.VolumeModifyParams - The changed parameters, with the hard quota in gigabytes.
+//	error                     - Error if a parameter is not mutable or has an invalid value.
+func mutableVolumeParameters(parameters map[string]string) (pancli.VolumeModifyParams, error) {
+	params := make(pancli.VolumeModifyParams, len(parameters))
+	var errs []error
+
+	for key, value := range parameters {
+		name := strings.TrimPrefix(key, utils.VendorPrefix)
+		if utils.VolumeParameters.GetSCKey(key) != key || !utils.In(name, pancli.MutableVolumeParameters...) {
+			errs = append(errs, fmt.Errorf("%s cannot be modified, mutable parameters are %v", key, pancli.MutableVolumeParameters))
+			continue
+		}
+
+		switch name {
+		case "hard":
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil || quantity.Sign() < 0 {
+				errs = append(errs, fmt.Errorf("%s must be a non-negative quantity, e.g. 20Gi, got %q", key, value))
+				continue
+			}
+			value = fmt.Sprintf("%.2f", utils.BytesToGB(quantity.Value()))
+		case "recovery":
+			if value == "" {
+				errs = append(errs, fmt.Errorf("%s must be provided", key))
+				continue
+			}
+		}
+		params[key] = value
+	}
+
+	if err := validateVolumeParameters(params); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// withMutableParameters applies the mutable parameters of a CreateVolume request, e.g. of the
+// VolumeAttributesClass of the PVC, over the storage class parameters. The hard quota replaces
+// the limit of the capacity range.
+//
+// Parameters:
+//
+//	parameters - The volume parameters of the request.
+//	cr         - The capacity range of the request.
+//	mutable    - The validated mutable parameters.
+//
+// Returns:
+//
+//	map[string]string  - A copy of the parameters with the mutable parameters applied.
+//	*csi.CapacityRange - The capacity range with the hard quota as limit.
+func withMutableParameters(parameters map[string]string, cr *csi.CapacityRange, mutable pancli.VolumeModifyParams) (map[string]string, *csi.CapacityRange) {
+	if len(mutable) == 0 {
+		return parameters, cr
+	}
+
+	merged := maps.Clone(parameters)
+	if merged == nil {
+		merged = make(map[string]string, len(mutable))
+	}
+	for key, value := range mutable {
+		if key != utils.VolumeParameters.GetSCKey("hard") {
+			merged[key] = value
+		}
+	}
+	if hardGB, ok := mutable.HardGB(); ok {
+		cr = &csi.CapacityRange{RequiredBytes: cr.GetRequiredBytes(), LimitBytes: utils.GBToBytes(hardGB)}
+	}
+	return merged, cr
+}
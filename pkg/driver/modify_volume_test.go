@@ -0,0 +1,176 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestControllerModifyVolume verifies the validation of mutable parameters and the mapping of
+// realm errors of ControllerModifyVolume.
+func TestControllerModifyVolume(t *testing.T) {
+	efsaKey := utils.VolumeParameters.GetSCKey("efsa")
+	hardKey := utils.VolumeParameters.GetSCKey("hard")
+	descriptionKey := utils.VolumeParameters.GetSCKey("description")
+
+	tests := []struct {
+		name       string
+		parameters map[string]string
+		wantParams pancli.VolumeModifyParams
+		realmErr   error
+		wantCode   codes.Code
+	}{
+		{
+			name:       "Success",
+			parameters: map[string]string{efsaKey: "file-unavailable", hardKey: "20Gi", descriptionKey: "team data"},
+			wantParams: pancli.VolumeModifyParams{efsaKey: "file-unavailable", hardKey: "20.00", descriptionKey: "team data csi-tier:gold"},
+			wantCode:   codes.OK,
+		},
+		{
+			name:       "UnlimitedHardQuota",
+			parameters: map[string]string{hardKey: "0"},
+			wantParams: pancli.VolumeModifyParams{hardKey: "0.00"},
+			wantCode:   codes.OK,
+		},
+		{
+			name:     "NoParameters",
+			wantCode: codes.OK,
+		},
+		{
+			name:       "ImmutableParameter",
+			parameters: map[string]string{utils.VolumeParameters.GetSCKey("bladeset"): "Set 2"},
+			wantCode:   codes.InvalidArgument,
+		},
+		{
+			name:       "SoftQuota",
+			parameters: map[string]string{utils.VolumeParameters.GetSCKey("soft"): "20Gi"},
+			wantCode:   codes.InvalidArgument,
+		},
+		{
+			name:       "UnqualifiedKey",
+			parameters: map[string]string{"efsa": "retry"},
+			wantCode:   codes.InvalidArgument,
+		},
+		{
+			name:       "InvalidEFSAMode",
+			parameters: map[string]string{efsaKey: "sometimes"},
+			wantCode:   codes.InvalidArgument,
+		},
+		{
+			name:       "InvalidHardQuota",
+			parameters: map[string]string{hardKey: "-1Gi"},
+			wantCode:   codes.InvalidArgument,
+		},
+		{
+			name:       "VolumeNotFound",
+			parameters: map[string]string{efsaKey: "retry"},
+			wantParams: pancli.VolumeModifyParams{efsaKey: "retry"},
+			realmErr:   pancli.ErrorNotFound,
+			wantCode:   codes.NotFound,
+		},
+		{
+			name:       "HardQuotaExceedsLimit",
+			parameters: map[string]string{hardKey: "2Pi"},
+			wantParams: pancli.VolumeModifyParams{hardKey: "2097152.00"},
+			realmErr:   &pancli.QuotaLimitError{Err: pancli.ErrorOutOfRange, MaxBytes: 1 << 50},
+			wantCode:   codes.OutOfRange,
+		},
+		{
+			name:       "UnsupportedByRealm",
+			parameters: map[string]string{efsaKey: "retry"},
+			wantParams: pancli.VolumeModifyParams{efsaKey: "retry"},
+			realmErr:   pancli.ErrorNotImplemented,
+			wantCode:   codes.FailedPrecondition,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d, pancliMock := newSnapshotTestDriver(t)
+			if _, ok := tc.parameters[descriptionKey]; ok {
+				pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).
+					Return(&utils.Volume{Name: utils.VolumeName(validVolumeName), Description: "old csi-tier:gold"}, nil)
+			}
+			if tc.wantParams != nil {
+				pancliMock.EXPECT().ModifyVolume(gomock.Any(), validVolumeName, tc.wantParams, defaultSecrets).Return(tc.realmErr)
+			}
+
+			resp, err := d.ControllerModifyVolume(t.Context(), &csi.ControllerModifyVolumeRequest{
+				VolumeId:          validVolumeName,
+				MutableParameters: tc.parameters,
+				Secrets:           defaultSecrets,
+			})
+			assert.Equal(t, tc.wantCode, status.Code(err), err)
+			if tc.wantCode == codes.OK {
+				assert.NotNil(t, resp)
+			}
+		})
+	}
+
+	t.Run("MissingVolumeID", func(t *testing.T) {
+		d, _ := newSnapshotTestDriver(t)
+		_, err := d.ControllerModifyVolume(t.Context(), &csi.ControllerModifyVolumeRequest{Secrets: defaultSecrets})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("DescriptionExceedsRealmLimit", func(t *testing.T) {
+		secrets := maps.Clone(defaultSecrets)
+		secrets[utils.RealmConnectionContext.RealmVersion] = "9.3"
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().GetVolume(gomock.Any(), validVolumeName, secrets).
+			Return(&utils.Volume{Name: utils.VolumeName(validVolumeName), Description: "old csi-tier:gold"}, nil)
+
+		// the realm version limits the description to 127 bytes including the tier tag of the volume
+		_, err := d.ControllerModifyVolume(t.Context(), &csi.ControllerModifyVolumeRequest{
+			VolumeId:          validVolumeName,
+			MutableParameters: map[string]string{descriptionKey: strings.Repeat("ü", 60)},
+			Secrets:           secrets,
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.ErrorContains(t, err, `description must be at most 113 bytes for realm version "9.3", got 120`)
+	})
+
+	t.Run("AppliedOnCreate", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), defaultSecrets).DoAndReturn(
+			func(_ any, _ string, params pancli.VolumeCreateParams, _ map[string]string) (*utils.Volume, error) {
+				assert.Equal(t, "file-unavailable", params[efsaKey])
+				assert.Equal(t, "Set 1", params[utils.VolumeParameters.GetSCKey("bladeset")])
+				assert.Equal(t, 20.0, params.HardGB())
+				return &utils.Volume{Name: utils.VolumeName(validVolumeName), Soft: 10, Hard: 20, State: utils.VolumeStateOnline}, nil
+			})
+
+		_, err := d.CreateVolume(t.Context(), &csi.CreateVolumeRequest{
+			Name:               validVolumeName,
+			CapacityRange:      &csi.CapacityRange{RequiredBytes: GB10Bytes},
+			Parameters:         map[string]string{utils.VolumeParameters.GetSCKey("bladeset"): "Set 1", efsaKey: "retry"},
+			MutableParameters:  map[string]string{efsaKey: "file-unavailable", hardKey: "20Gi"},
+			Secrets:            defaultSecrets,
+			VolumeCapabilities: []*csi.VolumeCapability{{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}}},
+		})
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// mountStatTimeout bounds the health check of a single mount, stale mounts may block stat
+// calls. Replaced in tests.
+var mountStatTimeout = 5 * time.Second
+
+var (
+	mountUpDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "node", "mount_up"),
+		"Whether the published volume mount is healthy (1) or stale (0).",
+		[]string{"volume_id", "target_path"}, nil,
+	)
+	mountStartTimeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "node", "mount_start_time_seconds"),
+		"Unix time the volume was first published at the target path.",
+		[]string{"volume_id", "target_path"}, nil,
+	)
+	mountLastRemountTimeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "node", "mount_last_remount_time_seconds"),
+		"Unix time the volume was last published again at an already published target path.",
+		[]string{"volume_id", "target_path"}, nil,
+	)
+)
+
+// publishedMount describes a volume published by the node plugin.
+type publishedMount struct {
+	volumeID    string
+	targetPath  string
+	mountedAt   time.Time
+	remountedAt time.Time
+}
+
+// mountCheck is a health check of a mount in flight.
+type mountCheck struct {
+	// done is closed once the stat returned
+	done chan struct{}
+	err  error
+}
+
+// mountTracker keeps track of the volumes published by the node plugin and exposes
+// their health as Prometheus metrics. The zero value is ready to use.
+type mountTracker struct {
+	// key is the target path
+	mounts map[string]*publishedMount
+	// checks are the health checks in flight, the key is the target path
+	checks map[string]*mountCheck
+	// stat checks the target path, os.Stat is used if nil
+	stat func(path string) error
+	sync.Mutex
+}
+
+// published records a successful publish of the volume at the target path.
+func (t *mountTracker) published(volumeID, targetPath string) {
+	metrics.NodeVolumeOperations.WithLabelValues(volumeID, "publish").Inc()
+
+	t.Lock()
+	defer t.Unlock()
+
+	if t.mounts == nil {
+		t.mounts = make(map[string]*publishedMount)
+	}
+	now := time.Now()
+	if m, ok := t.mounts[targetPath]; ok && m.volumeID == volumeID {
+		m.remountedAt = now
+		return
+	}
+	t.mounts[targetPath] = &publishedMount{volumeID: volumeID, targetPath: targetPath, mountedAt: now}
+}
+
+// unpublished records a successful unpublish of the volume from the target path.
+func (t *mountTracker) unpublished(volumeID, targetPath string) {
+	metrics.NodeVolumeOperations.WithLabelValues(volumeID, "unpublish").Inc()
+
+	t.Lock()
+	defer t.Unlock()
+	delete(t.mounts, targetPath)
+}
+
+// failed records a failed mount or unmount of the node operation, e.g. "publish".
+func (t *mountTracker) failed(operation string) {
+	metrics.NodeMountFailures.WithLabelValues(operation).Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (t *mountTracker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- mountUpDesc
+	ch <- mountStartTimeDesc
+	ch <- mountLastRemountTimeDesc
+}
+
+// Collect implements prometheus.Collector. The health of each mount is checked on collection.
+func (t *mountTracker) Collect(ch chan<- prometheus.Metric) {
+	t.Lock()
+	mounts := make([]publishedMount, 0, len(t.mounts))
+	for _, m := range t.mounts {
+		mounts = append(mounts, *m)
+	}
+	t.Unlock()
+
+	for _, m := range mounts {
+		up := 0.0
+		if t.checkMount(m.targetPath) == nil {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(mountUpDesc, prometheus.GaugeValue, up, m.volumeID, m.targetPath)
+		ch <- prometheus.MustNewConstMetric(mountStartTimeDesc, prometheus.GaugeValue, float64(m.mountedAt.Unix()), m.volumeID, m.targetPath)
+		if !m.remountedAt.IsZero() {
+			ch <- prometheus.MustNewConstMetric(mountLastRemountTimeDesc, prometheus.GaugeValue, float64(m.remountedAt.Unix()), m.volumeID, m.targetPath)
+		}
+	}
+}
+
+// checkMount stats the target path, treating a stat which does not return in time as stale.
+// Only one stat per target path is in flight: concurrent checks, e.g. of scrapes and the
+// canary, and checks while the stat of a hung mount is still blocked share its result
+// instead of piling up further blocked goroutines.
+func (t *mountTracker) checkMount(targetPath string) error {
+	t.Lock()
+	check, ok := t.checks[targetPath]
+	if !ok {
+		if t.checks == nil {
+			t.checks = make(map[string]*mountCheck)
+		}
+		check = &mountCheck{done: make(chan struct{})}
+		t.checks[targetPath] = check
+		go t.runCheck(targetPath, check)
+	}
+	t.Unlock()
+
+	select {
+	case <-check.done:
+		return check.err
+	case <-time.After(mountStatTimeout):
+		return errors.New("stat timed out")
+	}
+}
+
+// runCheck stats the target path and publishes the result of the check once it returns.
+func (t *mountTracker) runCheck(targetPath string, check *mountCheck) {
+	stat := t.stat
+	if stat == nil {
+		stat = func(path string) error {
+			_, err := os.Stat(path)
+			return err
+		}
+	}
+	check.err = stat(targetPath)
+
+	t.Lock()
+	delete(t.checks, targetPath)
+	t.Unlock()
+	close(check.done)
+}
+
+// registerMountMetrics registers the per-mount metrics of the driver.
+func (d *Driver) registerMountMetrics() {
+	err := metrics.Registry.Register(&d.mounts)
+	var already prometheus.AlreadyRegisteredError
+	if err != nil && !errors.As(err, &already) {
+		d.log.Error(err, "failed to register mount metrics")
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMountTracker(t *testing.T) {
+	tracker := &mountTracker{
+		stat: func(path string) error {
+			if path == "/stale" {
+				return errors.New("stale file handle")
+			}
+			return nil
+		},
+	}
+
+	publishes := testutil.ToFloat64(metrics.NodeVolumeOperations.WithLabelValues("pvc-1", "publish"))
+	unpublishes := testutil.ToFloat64(metrics.NodeVolumeOperations.WithLabelValues("pvc-1", "unpublish"))
+
+	tracker.published("pvc-1", "/healthy")
+	tracker.published("pvc-2", "/stale")
+	assert.Equal(t, publishes+1, testutil.ToFloat64(metrics.NodeVolumeOperations.WithLabelValues("pvc-1", "publish")))
+
+	// mount up, start time for both mounts, no remount yet
+	assert.Equal(t, 4, testutil.CollectAndCount(tracker))
+	expected := `
+# HELP panfs_csi_node_mount_up Whether the published volume mount is healthy (1) or stale (0).
+# TYPE panfs_csi_node_mount_up gauge
+panfs_csi_node_mount_up{target_path="/healthy",volume_id="pvc-1"} 1
+panfs_csi_node_mount_up{target_path="/stale",volume_id="pvc-2"} 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(tracker, strings.NewReader(expected), "panfs_csi_node_mount_up"))
+
+	tracker.published("pvc-1", "/healthy")
+	assert.Equal(t, 5, testutil.CollectAndCount(tracker, "panfs_csi_node_mount_last_remount_time_seconds", "panfs_csi_node_mount_up", "panfs_csi_node_mount_start_time_seconds"))
+	assert.False(t, tracker.mounts["/healthy"].remountedAt.IsZero())
+
+	tracker.unpublished("pvc-1", "/healthy")
+	assert.Equal(t, unpublishes+1, testutil.ToFloat64(metrics.NodeVolumeOperations.WithLabelValues("pvc-1", "unpublish")))
+	assert.Equal(t, 2, testutil.CollectAndCount(tracker))
+
+	failures := testutil.ToFloat64(metrics.NodeMountFailures.WithLabelValues("publish"))
+	tracker.failed("publish")
+	assert.Equal(t, failures+1, testutil.ToFloat64(metrics.NodeMountFailures.WithLabelValues("publish")))
+}
+
+// TestCheckMountHung verifies that checks of a hung mount share the blocked stat instead of
+// starting one goroutine per check.
+func TestCheckMountHung(t *testing.T) {
+	origTimeout := mountStatTimeout
+	defer func() { mountStatTimeout = origTimeout }()
+	mountStatTimeout = 10 * time.Millisecond
+
+	var stats atomic.Int32
+	release := make(chan struct{})
+	tracker := &mountTracker{
+		stat: func(string) error {
+			stats.Add(1)
+			<-release
+			return nil
+		},
+	}
+
+	for range 3 {
+		assert.EqualError(t, tracker.checkMount("/hung"), "stat timed out")
+	}
+	assert.Equal(t, int32(1), stats.Load())
+
+	// once the stat returns, the next check stats the mount again
+	close(release)
+	assert.Eventually(t, func() bool {
+		tracker.Lock()
+		defer tracker.Unlock()
+		return len(tracker.checks) == 0
+	}, time.Second, time.Millisecond)
+	assert.NoError(t, tracker.checkMount("/hung"))
+	assert.Equal(t, int32(2), stats.Load())
+}
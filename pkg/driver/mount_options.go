@@ -0,0 +1,130 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// MountOptionsManifestKey is the GetPluginInfo manifest key listing the recognized mount options as JSON.
+const MountOptionsManifestKey = utils.VendorPrefix + "mount-options"
+
+// Types of recognized mount options.
+const (
+	// MountOptionFlag is an option without a value, e.g. "ro".
+	MountOptionFlag = "flag"
+	// MountOptionString is an option with a free-form value.
+	MountOptionString = "string"
+	// MountOptionPath is an option with an absolute file path value, e.g. "kmip-config-file=/path".
+	MountOptionPath = "path"
+	// MountOptionEnum is an option with a value from a fixed list, e.g. "cachemode=readonly".
+	MountOptionEnum = "enum"
+)
+
+// MountOption describes a mount option recognized by the driver.
+type MountOption struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Values      []string `json:"values,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Description string   `json:"description"`
+}
+
+// MountOptions is the registry of recognized mount options. It is the single source of truth
+// for mount option validation, the GetPluginInfo manifest and the mount-options subcommand.
+var MountOptions = []MountOption{
+	{Name: "ro", Type: MountOptionFlag, Description: "Mount the volume read-only"},
+	{Name: "rw", Type: MountOptionFlag, Default: "rw", Description: "Mount the volume read-write"},
+	{Name: "noatime", Type: MountOptionFlag, Description: "Do not update file access times"},
+	{Name: "nodiratime", Type: MountOptionFlag, Description: "Do not update directory access times"},
+	{Name: "relatime", Type: MountOptionFlag, Description: "Update access times relative to modification times"},
+	{Name: "nosuid", Type: MountOptionFlag, Description: "Ignore set-user-ID and set-group-ID bits"},
+	{Name: "nodev", Type: MountOptionFlag, Description: "Do not interpret device files"},
+	{Name: "noexec", Type: MountOptionFlag, Description: "Do not allow direct execution of binaries"},
+	{Name: "cachemode", Type: MountOptionEnum, Values: cacheModeList, Default: CacheModeNone, Description: "Node-local cache of the PanFS client, set by the cacheMode parameter"},
+	{Name: "kmip-config-file", Type: MountOptionPath, Description: "KMIP configuration of encrypted volumes, set by the driver"},
+	{Name: "kmip-ca-file", Type: MountOptionPath, Description: "KMIP CA bundle of encrypted volumes, set by the driver from the node secret"},
+	{Name: "kmip-client-cert-file", Type: MountOptionPath, Description: "KMIP client certificate of encrypted volumes, set by the driver from the node secret"},
+	{Name: "kmip-client-key-file", Type: MountOptionPath, Description: "KMIP client key of encrypted volumes, set by the driver from the node secret"},
+}
+
+// mountOptionsManifest returns the recognized mount options as JSON for the plugin manifest.
+func mountOptionsManifest() string {
+	data, err := json.Marshal(MountOptions)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// validateMountOptions checks the values of recognized mount options. Options which are not
+// in the registry are passed to the PanFS client unchecked.
+//
+// Parameters:
+//
+//	options - The mount options, e.g. ["ro", "cachemode=readonly"].
+//
+// Returns:
+//
+//	error - The joined errors of all invalid options.
+func validateMountOptions(options []string) error {
+	var errs []error
+	for _, option := range options {
+		name, value, hasValue := strings.Cut(option, "=")
+		known := lookupMountOption(name)
+		if known == nil {
+			continue
+		}
+
+		switch known.Type {
+		case MountOptionFlag:
+			if hasValue {
+				errs = append(errs, fmt.Errorf("mount option %s does not take a value", name))
+			}
+		case MountOptionString:
+			if value == "" {
+				errs = append(errs, fmt.Errorf("mount option %s requires a value", name))
+			}
+		case MountOptionPath:
+			switch {
+			case value == "":
+				errs = append(errs, fmt.Errorf("mount option %s requires a value", name))
+			case !filepath.IsAbs(value) || filepath.Clean(value) != value:
+				errs = append(errs, fmt.Errorf("mount option %s must be a clean absolute path", name))
+			}
+		case MountOptionEnum:
+			if !utils.In(value, known.Values...) {
+				errs = append(errs, fmt.Errorf("mount option %s must be one of: %v", name, known.Values))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// lookupMountOption returns the registry entry of a mount option, nil if it is not recognized.
+func lookupMountOption(name string) *MountOption {
+	for i := range MountOptions {
+		if MountOptions[i].Name == name {
+			return &MountOptions[i]
+		}
+	}
+	return nil
+}
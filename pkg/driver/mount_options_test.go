@@ -0,0 +1,83 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateMountOptions verifies that values of recognized mount options are checked and
+// other options are passed through.
+func TestValidateMountOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []string
+		wantErr string
+	}{
+		{name: "Empty"},
+		{name: "Recognized", options: []string{"ro", "noatime", "cachemode=writeback", "kmip-config-file=/var/tmp/kmip/config.conf", "kmip-ca-file=/var/tmp/kmip/ca.pem"}},
+		{name: "Unrecognized", options: []string{"vendor-option=42", "other"}},
+		{name: "FlagWithValue", options: []string{"ro=1"}, wantErr: "mount option ro does not take a value"},
+		{name: "MissingValue", options: []string{"kmip-config-file"}, wantErr: "mount option kmip-config-file requires a value"},
+		{name: "RelativePath", options: []string{"kmip-ca-file=ca.pem"}, wantErr: "mount option kmip-ca-file must be a clean absolute path"},
+		{name: "UncleanPath", options: []string{"kmip-client-key-file=/var/tmp/kmip/../client.key"}, wantErr: "mount option kmip-client-key-file must be a clean absolute path"},
+		{name: "InvalidEnum", options: []string{"cachemode=always"}, wantErr: "mount option cachemode must be one of: [none readonly writeback]"},
+		{
+			name:    "AllErrorsReported",
+			options: []string{"ro=1", "cachemode="},
+			wantErr: "mount option ro does not take a value\nmount option cachemode must be one of: [none readonly writeback]",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateMountOptions(tc.options)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestLoadMountProfilesInvalidOptions verifies that mount profiles are checked against the
+// mount option registry.
+func TestLoadMountProfilesInvalidOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"throughput": ["cachemode=fast"]}`), 0o600))
+
+	_, err := LoadMountProfiles(path)
+	assert.ErrorContains(t, err, `invalid mount profile "throughput"`)
+}
+
+// TestKMIPMountOptionsRegistered verifies that the mount options set by the driver for
+// encrypted volumes are in the registry.
+func TestKMIPMountOptionsRegistered(t *testing.T) {
+	names := []string{"kmip-config-file"}
+	for _, cf := range kmipCertificateFiles {
+		names = append(names, cf.option)
+	}
+	for _, name := range names {
+		option := lookupMountOption(name)
+		if assert.NotNil(t, option, name) {
+			assert.Equal(t, MountOptionPath, option.Type, name)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// DefaultMountProfile is the name of the mount profile used when a volume does not request one.
+const DefaultMountProfile = "default"
+
+// MountProfiles maps mount profile names, e.g. "throughput" or "metadata", to the PanFS
+// client mount options they expand to.
+type MountProfiles map[string][]string
+
+// LoadMountProfiles reads mount profiles from a JSON file, e.g.
+// {"default": [], "throughput": ["opt1", "opt2=value"]}.
+//
+// Parameters:
+//
+//	path - The path of the mount profiles file.
+//
+// Returns:
+//
+//	MountProfiles - The mount profiles.
+//	error         - Error if the file cannot be read or parsed.
+func LoadMountProfiles(path string) (MountProfiles, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := MountProfiles{}
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse mount profiles %s: %w", path, err)
+	}
+	for name, options := range profiles {
+		if err := validateMountOptions(options); err != nil {
+			return nil, fmt.Errorf("invalid mount profile %q in %s: %w", name, path, err)
+		}
+	}
+	return profiles, nil
+}
+
+// WithMountProfiles sets the mount profiles volumes can select with the
+// "panfs.csi.vdura.com/profile" storage class parameter.
+//
+// Parameters:
+//
+//	profiles - The mount profiles.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithMountProfiles(profiles MountProfiles) Option {
+	return func(d *Driver) {
+		d.mountProfiles = profiles
+	}
+}
+
+// mountProfileOptions returns the mount options of the profile selected in the volume context.
+//
+// Parameters:
+//
+//	volumeContext - The volume context of the volume.
+//
+// Returns:
+//
+//	[]string - The mount options of the profile, nil if no profile is selected.
+//	error    - Error if the selected profile is not configured.
+func (d *Driver) mountProfileOptions(volumeContext map[string]string) ([]string, error) {
+	name, ok := volumeContext[utils.VolumeParameters.GetSCKey("profile")]
+	if !ok || name == "" {
+		name = DefaultMountProfile
+	}
+
+	options, ok := d.mountProfiles[name]
+	if !ok {
+		if name == DefaultMountProfile {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("mount profile %q is not configured", name)
+	}
+	return options, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLoadMountProfiles verifies that mount profiles are read from a JSON file.
+func TestLoadMountProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"default": [], "throughput": ["opt1", "opt2=2"]}`), 0o600))
+
+	profiles, err := LoadMountProfiles(path)
+	assert.NoError(t, err)
+	assert.Equal(t, MountProfiles{"default": {}, "throughput": {"opt1", "opt2=2"}}, profiles)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`not json`), 0o600))
+	_, err = LoadMountProfiles(path)
+	assert.ErrorContains(t, err, "failed to parse mount profiles")
+
+	_, err = LoadMountProfiles(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+// TestMountProfileOptions verifies the selection of mount profiles from the volume context.
+func TestMountProfileOptions(t *testing.T) {
+	profileKey := utils.VolumeParameters.GetSCKey("profile")
+	d := &Driver{mountProfiles: MountProfiles{"default": {"def"}, "metadata": {"meta1", "meta2"}}}
+
+	options, err := d.mountProfileOptions(map[string]string{profileKey: "metadata"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"meta1", "meta2"}, options)
+
+	options, err = d.mountProfileOptions(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"def"}, options)
+
+	_, err = d.mountProfileOptions(map[string]string{profileKey: "throughput"})
+	assert.EqualError(t, err, `mount profile "throughput" is not configured`)
+
+	// without configured profiles only the default profile may be used
+	d = &Driver{}
+	options, err = d.mountProfileOptions(map[string]string{profileKey: DefaultMountProfile})
+	assert.NoError(t, err)
+	assert.Nil(t, options)
+
+	assert.Equal(t,
+		map[string]string{profileKey: "metadata"},
+		d.volumeContext(&utils.Volume{}, map[string]string{profileKey: "metadata", "other": "value"}))
+}
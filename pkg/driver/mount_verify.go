@@ -0,0 +1,111 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// mountVerifyTimeout bounds the IO verification of a published volume, dead mounts may block IO.
+const mountVerifyTimeout = 10 * time.Second
+
+// statfs returns the file system statistics of the path, replaced in tests.
+var statfs = func(path string) error {
+	var st syscall.Statfs_t
+	return syscall.Statfs(path, &st)
+}
+
+// readMountRoot opens the root of the mount and reads one directory entry, replaced in tests.
+var readMountRoot = func(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	if _, err := dir.Readdirnames(1); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// WithMountVerification enables the IO verification of published volumes. Some failures of
+// the PanFS client surface only at the first IO after a successful mount; with verification a
+// publish fails, and the volume is unmounted again, if the mount root cannot be read, so that
+// pods are not started against a dead mount. The verifyMount storage class parameter overrides
+// this setting per volume.
+//
+// Parameters:
+//
+//	enabled - Whether published volumes are verified by default.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithMountVerification(enabled bool) Option {
+	return func(d *Driver) {
+		d.verifyMounts = enabled
+	}
+}
+
+// shouldVerifyMount reports whether the volume is verified after publish.
+func (d *Driver) shouldVerifyMount(volumeContext map[string]string) bool {
+	value, ok := volumeContext[utils.VolumeParameters.GetSCKey("verifyMount")]
+	if !ok {
+		return d.verifyMounts
+	}
+	// the value is validated on volume creation
+	verify, _ := strconv.ParseBool(value)
+	return verify
+}
+
+// verifyMountIO performs a cheap read of the mounted volume: statfs and a read of the mount root.
+//
+// Parameters:
+//
+//	targetPath - The path the volume is mounted at.
+//
+// Returns:
+//
+//	error - Error describing the failed step, or a timeout if the IO does not return in time.
+func verifyMountIO(targetPath string) error {
+	done := make(chan error, 1)
+	go func() {
+		if err := statfs(targetPath); err != nil {
+			done <- fmt.Errorf("statfs %s: %w", targetPath, err)
+			return
+		}
+		if err := readMountRoot(targetPath); err != nil {
+			done <- fmt.Errorf("read of mount root %s: %w", targetPath, err)
+			return
+		}
+		done <- nil
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(mountVerifyTimeout):
+		return fmt.Errorf("IO on %s did not complete within %s", targetPath, mountVerifyTimeout)
+	}
+}
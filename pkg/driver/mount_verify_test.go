@@ -0,0 +1,96 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// TestVerifyMountIO verifies the IO checks of a published volume.
+func TestVerifyMountIO(t *testing.T) {
+	origStatfs, origReadMountRoot := statfs, readMountRoot
+	t.Cleanup(func() { statfs, readMountRoot = origStatfs, origReadMountRoot })
+
+	t.Run("RealDirectory", func(t *testing.T) {
+		statfs, readMountRoot = origStatfs, origReadMountRoot
+		assert.NoError(t, verifyMountIO(t.TempDir()))
+	})
+
+	t.Run("StatfsFails", func(t *testing.T) {
+		statfs = func(string) error { return errors.New("transport endpoint is not connected") }
+		readMountRoot = func(string) error { return nil }
+		assert.EqualError(t, verifyMountIO("/mnt/vol"), "statfs /mnt/vol: transport endpoint is not connected")
+	})
+
+	t.Run("ReadFails", func(t *testing.T) {
+		statfs = func(string) error { return nil }
+		readMountRoot = func(string) error { return errors.New("input/output error") }
+		assert.EqualError(t, verifyMountIO("/mnt/vol"), "read of mount root /mnt/vol: input/output error")
+	})
+}
+
+// TestShouldVerifyMount verifies that the volume parameter overrides the driver setting.
+func TestShouldVerifyMount(t *testing.T) {
+	key := utils.VolumeParameters.GetSCKey("verifyMount")
+
+	assert.False(t, (&Driver{}).shouldVerifyMount(nil))
+	assert.True(t, (&Driver{verifyMounts: true}).shouldVerifyMount(nil))
+	assert.True(t, (&Driver{}).shouldVerifyMount(map[string]string{key: "true"}))
+	assert.False(t, (&Driver{verifyMounts: true}).shouldVerifyMount(map[string]string{key: "false"}))
+}
+
+// TestNodePublishVolume_MountVerificationFails verifies that a volume failing IO verification
+// is unmounted again and the publish fails.
+func TestNodePublishVolume_MountVerificationFails(t *testing.T) {
+	origStatfs := statfs
+	t.Cleanup(func() { statfs = origStatfs })
+	statfs = func(string) error { return errors.New("input/output error") }
+
+	ctrl := gomock.NewController(t)
+	mockMounter := mock.NewMockPanMounter(ctrl)
+	driver := &Driver{
+		Name:         DefaultDriverName,
+		log:          klog.Background(),
+		mounterV2:    mockMounter,
+		verifyMounts: true,
+	}
+
+	gomock.InOrder(
+		mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), validPublishTargetPath, gomock.Any()).Return(nil),
+		mockMounter.EXPECT().Unmount(gomock.Any(), validPublishTargetPath).Return(nil),
+	)
+
+	resp, err := driver.NodePublishVolume(t.Context(), &csi.NodePublishVolumeRequest{
+		VolumeId:   validVolumeName,
+		TargetPath: validPublishTargetPath,
+		VolumeCapability: &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+		},
+		Secrets: defaultSecrets,
+	})
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.ErrorContains(t, err, "failed IO verification: statfs "+validPublishTargetPath+": input/output error")
+}
@@ -15,21 +15,19 @@
 package driver
 
 import (
+	"context"
 	"fmt"
 	"os"
 
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/interfaces"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"k8s.io/klog/v2"
 	"k8s.io/mount-utils"
 )
 
-//go:generate mockgen -source=mounter.go -destination=mock/mock_kmounter.go -package=mock kMounter
-//lint:ignore U1000 This interface is intentionally kept for future use and should be ignored by the linter
-type kMounter interface {
-	mount.Interface
-}
-
 // PanFSMounter provides methods to mount PanFS volumes.
 type PanFSMounter struct {
-	mounter mount.Interface
+	mounter interfaces.KMounter
 }
 
 // Mount mounts the PanFS volume at the target path with the given options.
@@ -37,6 +35,7 @@ type PanFSMounter struct {
 //
 // Parameters:
 //
+//	ctx     - The context of the request, carrying its logger.
 //	source  - The source path to mount.
 //	target  - The target mount point.
 //	options - Slice of mount options.
@@ -44,8 +43,8 @@ type PanFSMounter struct {
 // Returns:
 //
 //	error - Returns an error if mount fails or target cannot be created.
-func (p *PanFSMounter) Mount(source, target string, options []string) error {
-	// Custom mount logic can be added here if needed
+func (p *PanFSMounter) Mount(ctx context.Context, source, target string, options []string) error {
+	target = utils.CleanPath(target)
 	notMnt, err := p.mounter.IsLikelyNotMountPoint(target)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -59,6 +58,7 @@ func (p *PanFSMounter) Mount(source, target string, options []string) error {
 	}
 
 	if notMnt {
+		klog.FromContext(ctx).V(4).Info("mounting volume", "source", source, "target", target, "options", options)
 		err = p.mounter.Mount(source, target, "panfs", options)
 		if err != nil {
 			return err
@@ -73,6 +73,7 @@ func (p *PanFSMounter) Mount(source, target string, options []string) error {
 //
 // Parameters:
 //
+//	ctx     - The context of the request, carrying its logger.
 //	source  - The source path to bind mount.
 //	target  - The target mount point.
 //	options - Slice of mount options.
@@ -80,22 +81,59 @@ func (p *PanFSMounter) Mount(source, target string, options []string) error {
 // Returns:
 //
 //	error - Returns an error if bind mount fails.
-func (p *PanFSMounter) BindMount(source, target string, options []string) error {
+func (p *PanFSMounter) BindMount(ctx context.Context, source, target string, options []string) error {
 	options = append(options, "bind")
-	return p.Mount(source, target, options)
+	return p.Mount(ctx, source, target, options)
 }
 
 // Unmount unmounts the PanFS volume from the target path.
 //
 // Parameters:
 //
+//	ctx    - The context of the request, carrying its logger.
 //	target - The target mount point to unmount.
 //
 // Returns:
 //
 //	error - Returns an error if unmount fails.
-func (p *PanFSMounter) Unmount(target string) error {
-	return mount.CleanupMountPoint(target, p.mounter, false)
+func (p *PanFSMounter) Unmount(ctx context.Context, target string) error {
+	klog.FromContext(ctx).V(4).Info("unmounting volume", "target", target)
+	return mount.CleanupMountPoint(utils.CleanPath(target), p.mounter, false)
+}
+
+// IsMountPoint reports whether a volume is mounted at the target path, including bind mounts.
+//
+// Parameters:
+//
+//	ctx    - The context of the request, carrying its logger.
+//	target - The path to check.
+//
+// Returns:
+//
+//	bool  - True if the path is a mount point, false if it is not or does not exist.
+//	error - Returns an error if the mount points cannot be checked.
+func (p *PanFSMounter) IsMountPoint(ctx context.Context, target string) (bool, error) {
+	isMnt, err := p.mounter.IsMountPoint(utils.CleanPath(target))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return isMnt, err
+}
+
+// Remount remounts the PanFS volume mounted at the target path with its current options,
+// e.g. to drop the quota cached by the PanFS client.
+//
+// Parameters:
+//
+//	ctx    - The context of the request, carrying its logger.
+//	target - The mount point to remount.
+//
+// Returns:
+//
+//	error - Returns an error if the remount fails.
+func (p *PanFSMounter) Remount(ctx context.Context, target string) error {
+	klog.FromContext(ctx).V(4).Info("remounting volume", "target", target)
+	return p.mounter.Mount("", utils.CleanPath(target), "panfs", []string{"remount"})
 }
 
 // NewPanFSMounter creates a new PanFSMounter instance using the default mount interface.
@@ -104,8 +142,22 @@ func (p *PanFSMounter) Unmount(target string) error {
 //
 //	*PanFSMounter - The initialized PanFSMounter.
 func NewPanFSMounter() *PanFSMounter {
+	return NewPanFSMounterWithInterface(mount.New(""))
+}
+
+// NewPanFSMounterWithInterface creates a new PanFSMounter running the mount operations with
+// the given mount interface, e.g. a mount.FakeMounter in tests.
+//
+// Parameters:
+//
+//	mounter - The mount interface.
+//
+// Returns:
+//
+//	*PanFSMounter - The initialized PanFSMounter.
+func NewPanFSMounterWithInterface(mounter mount.Interface) *PanFSMounter {
 	return &PanFSMounter{
-		mounter: mount.New(""),
+		mounter: mounter,
 	}
 }
 
@@ -137,6 +189,7 @@ func NewPanFSFakeMounter() *PanFSFakeMounter {
 //
 // Parameters:
 //
+//	ctx     - The context of the request, carrying its logger.
 //	source  - The source path to mount.
 //	target  - The target mount point.
 //	options - Slice of mount options.
@@ -144,7 +197,8 @@ func NewPanFSFakeMounter() *PanFSFakeMounter {
 // Returns:
 //
 //	error - Returns an error if mount fails or target cannot be created.
-func (p *PanFSFakeMounter) Mount(source, target string, options []string) error {
+func (p *PanFSFakeMounter) Mount(ctx context.Context, source, target string, options []string) error {
+	target = utils.CleanPath(target)
 	realMounter := mount.New("")
 	isMnt, err := realMounter.IsMountPoint(target)
 	if err != nil {
@@ -168,6 +222,7 @@ func (p *PanFSFakeMounter) Mount(source, target string, options []string) error
 //
 // Parameters:
 //
+//	ctx     - The context of the request, carrying its logger.
 //	source  - The source path to bind mount.
 //	target  - The target mount point.
 //	options - Slice of mount options.
@@ -175,22 +230,71 @@ func (p *PanFSFakeMounter) Mount(source, target string, options []string) error
 // Returns:
 //
 //	error - Returns an error if bind mount fails.
-func (p *PanFSFakeMounter) BindMount(source, target string, options []string) error {
+func (p *PanFSFakeMounter) BindMount(ctx context.Context, source, target string, options []string) error {
 	options = append(options, "bind")
-	return p.Mount(source, target, options)
+	return p.Mount(ctx, source, target, options)
 }
 
 // Unmount unmounts the PanFS volume from the target path using the fake mounter.
 //
 // Parameters:
 //
+//	ctx    - The context of the request, carrying its logger.
 //	target - The target mount point to unmount.
 //
 // Returns:
 //
 //	error - Returns an error if unmount fails.
-func (p *PanFSFakeMounter) Unmount(target string) error {
-	return p.fakeMounter.Unmount(target)
+func (p *PanFSFakeMounter) Unmount(ctx context.Context, target string) error {
+	return p.fakeMounter.Unmount(utils.CleanPath(target))
+}
+
+// IsMountPoint reports whether a volume is mounted at the target path by the fake mounter.
+// Mount points are compared as normalized paths, so differently formatted paths of the same
+// target are detected as mounted.
+//
+// Parameters:
+//
+//	ctx    - The context of the request, carrying its logger.
+//	target - The path to check.
+//
+// Returns:
+//
+//	bool  - True if the path is a mount point, false if it is not or does not exist.
+//	error - Returns an error if the mount points cannot be checked.
+func (p *PanFSFakeMounter) IsMountPoint(ctx context.Context, target string) (bool, error) {
+	mountPoints, err := p.fakeMounter.List()
+	if err != nil {
+		return false, err
+	}
+	for _, mp := range mountPoints {
+		if utils.SamePath(mp.Path, target) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Remount checks that the target path is mounted by the fake mounter, remounts leave the fake
+// mount points unchanged.
+//
+// Parameters:
+//
+//	ctx    - The context of the request, carrying its logger.
+//	target - The mount point to remount.
+//
+// Returns:
+//
+//	error - Returns an error if the target is not mounted.
+func (p *PanFSFakeMounter) Remount(ctx context.Context, target string) error {
+	isMnt, err := p.IsMountPoint(ctx, target)
+	if err != nil {
+		return err
+	}
+	if !isMnt {
+		return fmt.Errorf("%s is not mounted", target)
+	}
+	return nil
 }
 
 // makeDir creates a directory at the specified path with 0755 permissions.
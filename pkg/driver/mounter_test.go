@@ -0,0 +1,237 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/mount-utils"
+)
+
+// failingMounter is a fake mount interface failing Mount with err.
+type failingMounter struct {
+	*mount.FakeMounter
+	err error
+}
+
+// Mount fails with the configured error.
+func (f *failingMounter) Mount(_, _, _ string, _ []string) error {
+	return f.err
+}
+
+// TestPanFSMounterMount verifies that Mount creates missing targets, mounts targets which are
+// not mount points yet and leaves existing mounts untouched.
+func TestPanFSMounterMount(t *testing.T) {
+	errMount := errors.New("mount failed")
+
+	tests := []struct {
+		name string
+		// setup returns the target and the mount interface
+		setup       func(t *testing.T) (string, mount.Interface)
+		wantErr     string
+		wantMounted bool
+		wantActions int
+	}{
+		{
+			name: "NotMountPoint",
+			setup: func(t *testing.T) (string, mount.Interface) {
+				return t.TempDir(), mount.NewFakeMounter(nil)
+			},
+			wantMounted: true,
+			wantActions: 1,
+		},
+		{
+			name: "MissingTarget",
+			setup: func(t *testing.T) (string, mount.Interface) {
+				return filepath.Join(t.TempDir(), "a", "b"), mount.NewFakeMounter(nil)
+			},
+			wantMounted: true,
+			wantActions: 1,
+		},
+		{
+			name: "AlreadyMounted",
+			setup: func(t *testing.T) (string, mount.Interface) {
+				target := t.TempDir()
+				return target, mount.NewFakeMounter([]mount.MountPoint{{Device: "panfs://realm/other", Path: target, Type: "panfs"}})
+			},
+			wantMounted: true,
+		},
+		{
+			name: "PermissionDenied",
+			setup: func(t *testing.T) (string, mount.Interface) {
+				target := t.TempDir()
+				mounter := mount.NewFakeMounter(nil)
+				mounter.MountCheckErrors = map[string]error{target: os.ErrPermission}
+				return target, mounter
+			},
+			wantErr: "failed to check mount path: permission denied",
+		},
+		{
+			name: "TargetCannotBeCreated",
+			setup: func(t *testing.T) (string, mount.Interface) {
+				file := filepath.Join(t.TempDir(), "file")
+				require.NoError(t, os.WriteFile(file, nil, 0o600))
+				return filepath.Join(file, "target"), mount.NewFakeMounter(nil)
+			},
+			wantErr: "not a directory",
+		},
+		{
+			name: "MountFailure",
+			setup: func(t *testing.T) (string, mount.Interface) {
+				return t.TempDir(), &failingMounter{FakeMounter: mount.NewFakeMounter(nil), err: errMount}
+			},
+			wantErr: errMount.Error(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			target, mounter := tc.setup(t)
+
+			err := NewPanFSMounterWithInterface(mounter).Mount(t.Context(), "panfs://realm/volume", target, []string{"noatime"})
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.DirExists(t, target)
+
+			isMnt, err := mounter.IsMountPoint(target)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantMounted, isMnt)
+
+			actions := mounter.(*mount.FakeMounter).GetLog()
+			require.Len(t, actions, tc.wantActions)
+			for _, action := range actions {
+				assert.Equal(t, mount.FakeAction{Action: mount.FakeActionMount, Target: target, Source: "panfs://realm/volume", FSType: "panfs"}, action)
+			}
+		})
+	}
+}
+
+// TestPanFSMounterBindMount verifies that BindMount adds the bind option.
+func TestPanFSMounterBindMount(t *testing.T) {
+	target := t.TempDir()
+	mounter := mount.NewFakeMounter(nil)
+
+	require.NoError(t, NewPanFSMounterWithInterface(mounter).BindMount(t.Context(), "/staging", target, []string{"ro"}))
+	require.Len(t, mounter.MountPoints, 1)
+	assert.Equal(t, []string{"ro", "bind"}, mounter.MountPoints[0].Opts)
+}
+
+// TestPanFSMounterUnmount verifies that Unmount unmounts mounted targets and removes the
+// target directory.
+func TestPanFSMounterUnmount(t *testing.T) {
+	errUnmount := errors.New("device busy")
+
+	tests := []struct {
+		name string
+		// setup returns the target and the mount interface
+		setup   func(t *testing.T) (string, *mount.FakeMounter)
+		wantErr string
+		// whether the target directory is kept
+		wantKept bool
+	}{
+		{
+			name: "Mounted",
+			setup: func(t *testing.T) (string, *mount.FakeMounter) {
+				target := t.TempDir()
+				return target, mount.NewFakeMounter([]mount.MountPoint{{Device: "panfs://realm/volume", Path: target, Type: "panfs"}})
+			},
+		},
+		{
+			name: "NotMounted",
+			setup: func(t *testing.T) (string, *mount.FakeMounter) {
+				return t.TempDir(), mount.NewFakeMounter(nil)
+			},
+		},
+		{
+			name: "MissingTarget",
+			setup: func(t *testing.T) (string, *mount.FakeMounter) {
+				return filepath.Join(t.TempDir(), "missing"), mount.NewFakeMounter(nil)
+			},
+		},
+		{
+			name: "UnmountFailure",
+			setup: func(t *testing.T) (string, *mount.FakeMounter) {
+				target := t.TempDir()
+				mounter := mount.NewFakeMounter([]mount.MountPoint{{Device: "panfs://realm/volume", Path: target, Type: "panfs"}})
+				mounter.UnmountFunc = func(string) error { return errUnmount }
+				return target, mounter
+			},
+			wantErr:  errUnmount.Error(),
+			wantKept: true,
+		},
+		{
+			name: "PermissionDenied",
+			setup: func(t *testing.T) (string, *mount.FakeMounter) {
+				target := t.TempDir()
+				mounter := mount.NewFakeMounter(nil)
+				mounter.MountCheckErrors = map[string]error{target: os.ErrPermission}
+				return target, mounter
+			},
+			wantErr:  "permission denied",
+			wantKept: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			target, mounter := tc.setup(t)
+
+			err := NewPanFSMounterWithInterface(mounter).Unmount(t.Context(), target)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Empty(t, mounter.MountPoints)
+			}
+			if tc.wantKept {
+				assert.DirExists(t, target)
+			} else {
+				assert.NoDirExists(t, target)
+			}
+		})
+	}
+}
+
+// TestPanFSMounterIsMountPoint verifies that missing targets are reported as not mounted.
+func TestPanFSMounterIsMountPoint(t *testing.T) {
+	target := t.TempDir()
+	denied := t.TempDir()
+	mounter := mount.NewFakeMounter([]mount.MountPoint{{Device: "panfs://realm/volume", Path: target, Type: "panfs"}})
+	mounter.MountCheckErrors = map[string]error{denied: os.ErrPermission}
+	panfsMounter := NewPanFSMounterWithInterface(mounter)
+
+	isMnt, err := panfsMounter.IsMountPoint(t.Context(), target)
+	assert.NoError(t, err)
+	assert.True(t, isMnt)
+
+	isMnt, err = panfsMounter.IsMountPoint(t.Context(), t.TempDir())
+	assert.NoError(t, err)
+	assert.False(t, isMnt)
+
+	isMnt, err = panfsMounter.IsMountPoint(t.Context(), filepath.Join(target, "missing"))
+	assert.NoError(t, err)
+	assert.False(t, isMnt)
+
+	_, err = panfsMounter.IsMountPoint(t.Context(), denied)
+	assert.ErrorIs(t, err, os.ErrPermission)
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// NamespacePolicyRule restricts volumes with the given parameters to a set of namespaces.
+type NamespacePolicyRule struct {
+	// Name identifies the rule in error messages.
+	Name string `json:"name"`
+	// Parameters are the storage class parameters, e.g. {"encryption": "on"}, a volume must
+	// have for the rule to apply. Keys are parameter names as in utils.VolumeParameters.
+	Parameters map[string]string `json:"parameters"`
+	// Namespaces lists the namespaces, or path.Match patterns like "team-*", allowed to
+	// create matching volumes.
+	Namespaces []string `json:"namespaces"`
+}
+
+// NamespacePolicy restricts the namespaces PVCs with certain volume parameters may be created in.
+// Volumes matching none of the rules are not restricted.
+type NamespacePolicy []NamespacePolicyRule
+
+// LoadNamespacePolicy reads a namespace policy from a JSON file, e.g.
+// [{"name": "encrypted", "parameters": {"encryption": "on"}, "namespaces": ["secure-*"]}].
+//
+// Parameters:
+//
+//	path - The path of the policy file.
+//
+// Returns:
+//
+//	NamespacePolicy - The namespace policy.
+//	error           - Error if the file cannot be read or contains invalid rules.
+func LoadNamespacePolicy(path string) (NamespacePolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := NamespacePolicy{}
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse namespace policy %s: %w", path, err)
+	}
+	if err := policy.validate(); err != nil {
+		return nil, fmt.Errorf("invalid namespace policy %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+// WithNamespacePolicy makes CreateVolume reject volumes whose parameters are restricted to
+// other namespaces than the one of the PVC. It requires the external provisioner to run
+// with --extra-create-metadata.
+//
+// Parameters:
+//
+//	policy - The namespace policy.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithNamespacePolicy(policy NamespacePolicy) Option {
+	return func(d *Driver) {
+		d.namespacePolicy = policy
+	}
+}
+
+// validate checks that all rules have parameters and valid namespace patterns.
+func (p NamespacePolicy) validate() error {
+	for i, rule := range p {
+		if len(rule.Parameters) == 0 {
+			return fmt.Errorf("rule %d (%s) has no parameters", i, rule.Name)
+		}
+		for name := range rule.Parameters {
+			if utils.VolumeParameters.GetSCKey(name) == "" {
+				return fmt.Errorf("rule %d (%s) has unknown parameter %q", i, rule.Name, name)
+			}
+		}
+		for _, pattern := range rule.Namespaces {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("rule %d (%s) has invalid namespace pattern %q: %w", i, rule.Name, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// check verifies that volumes with the given parameters may be created in the namespace.
+//
+// Parameters:
+//
+//	namespace  - The namespace of the PVC, empty if unknown.
+//	parameters - The volume parameters, including parameters set by PVC annotations.
+//
+// Returns:
+//
+//	error - Error naming the first rule the request violates.
+func (p NamespacePolicy) check(namespace string, parameters map[string]string) error {
+	for _, rule := range p {
+		if !rule.matches(parameters) {
+			continue
+		}
+		if namespace == "" {
+			return fmt.Errorf("volumes matching policy %q require the PVC namespace, run the provisioner with --extra-create-metadata", rule.Name)
+		}
+		if !rule.allows(namespace) {
+			return fmt.Errorf("volumes matching policy %q may not be created in namespace %s", rule.Name, namespace)
+		}
+	}
+	return nil
+}
+
+// matches reports whether the parameters contain all parameters of the rule.
+func (r NamespacePolicyRule) matches(parameters map[string]string) bool {
+	for name, value := range r.Parameters {
+		if v, ok := parameters[utils.VolumeParameters.GetSCKey(name)]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// allows reports whether the namespace matches one of the namespaces of the rule.
+func (r NamespacePolicyRule) allows(namespace string) bool {
+	for _, pattern := range r.Namespaces {
+		if ok, _ := path.Match(pattern, namespace); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestLoadNamespacePolicy verifies that namespace policies are read from a JSON file and validated.
+func TestLoadNamespacePolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`[{"name": "encrypted", "parameters": {"encryption": "on"}, "namespaces": ["secure-*"]}]`), 0o600))
+
+	policy, err := LoadNamespacePolicy(path)
+	assert.NoError(t, err)
+	assert.Equal(t, NamespacePolicy{{Name: "encrypted", Parameters: map[string]string{"encryption": "on"}, Namespaces: []string{"secure-*"}}}, policy)
+
+	for content, wantErr := range map[string]string{
+		`not json`: "failed to parse namespace policy",
+		`[{"name": "empty", "namespaces": ["a"]}]`:                                 "rule 0 (empty) has no parameters",
+		`[{"name": "x", "parameters": {"speed": "fast"}}]`:                         `rule 0 (x) has unknown parameter "speed"`,
+		`[{"name": "x", "parameters": {"layout": "raid6+"}, "namespaces": ["["]}]`: `rule 0 (x) has invalid namespace pattern "["`,
+	} {
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		_, err = LoadNamespacePolicy(path)
+		assert.ErrorContains(t, err, wantErr)
+	}
+}
+
+// TestNamespacePolicyCheck verifies that only volumes matching a rule are restricted.
+func TestNamespacePolicyCheck(t *testing.T) {
+	policy := NamespacePolicy{
+		{Name: "encrypted", Parameters: map[string]string{"encryption": "on"}, Namespaces: []string{"secure", "team-*"}},
+		{Name: "fast", Parameters: map[string]string{"bladeset": "Fast", "layout": "raid10+"}, Namespaces: []string{"hpc"}},
+	}
+	encrypted := map[string]string{utils.VolumeParameters.GetSCKey("encryption"): "on"}
+	fast := map[string]string{utils.VolumeParameters.GetSCKey("bladeset"): "Fast", utils.VolumeParameters.GetSCKey("layout"): "raid10+"}
+	fastSet := map[string]string{utils.VolumeParameters.GetSCKey("bladeset"): "Fast"}
+
+	assert.NoError(t, policy.check("secure", encrypted))
+	assert.NoError(t, policy.check("team-a", encrypted))
+	assert.EqualError(t, policy.check("default", encrypted), `volumes matching policy "encrypted" may not be created in namespace default`)
+	assert.NoError(t, policy.check("hpc", fast))
+	assert.ErrorContains(t, policy.check("team-a", fast), `policy "fast"`)
+	assert.NoError(t, policy.check("default", fastSet), "rules apply only if all parameters match")
+	assert.NoError(t, policy.check("", nil))
+	assert.ErrorContains(t, policy.check("", encrypted), "--extra-create-metadata")
+	assert.NoError(t, NamespacePolicy(nil).check("default", encrypted))
+}
+
+// TestCreateVolumeNamespacePolicy verifies that CreateVolume rejects volumes restricted to
+// other namespaces before calling the realm.
+func TestCreateVolumeNamespacePolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	pancliMock := mock.NewMockStorageProviderClient(ctrl)
+	pancliMock.EXPECT().CreateVolume(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	d := &Driver{
+		Name:  DefaultDriverName,
+		panfs: pancliMock,
+		namespacePolicy: NamespacePolicy{
+			{Name: "encrypted", Parameters: map[string]string{"encryption": "on"}, Namespaces: []string{"secure"}},
+		},
+	}
+
+	_, err := d.CreateVolume(t.Context(), &csi.CreateVolumeRequest{
+		Name: validVolumeName,
+		Parameters: map[string]string{
+			utils.VolumeParameters.GetSCKey("encryption"): "on",
+			PVCNameParameterKey:                           "data",
+			PVCNamespaceParameterKey:                      "default",
+		},
+		Secrets:            defaultSecrets,
+		VolumeCapabilities: []*csi.VolumeCapability{{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}}},
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
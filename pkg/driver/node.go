@@ -16,13 +16,16 @@ package driver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 
 	csi "github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
 )
 
 const (
@@ -43,7 +46,9 @@ var (
 )
 
 // NodeStageVolume handles the CSI NodeStageVolume request.
-// Logs the request and returns an unimplemented error.
+// With staged mounts the volume is mounted once per node at the staging path, and
+// NodePublishVolume bind mounts it into the target paths of the pods. Without staged
+// mounts the request is not supported.
 //
 // Parameters:
 //
@@ -52,10 +57,11 @@ var (
 //
 // Returns:
 //
-//	*csi.NodeStageVolumeResponse - Always nil.
-//	error - Always returns codes.Unimplemented.
+//	*csi.NodeStageVolumeResponse - The response on success.
+//	error - Returns codes.Unimplemented without staged mounts, or an error for invalid input,
+//	        unsupported capabilities, unreachable realm data paths or mount failures.
 func (d *Driver) NodeStageVolume(ctx context.Context, in *csi.NodeStageVolumeRequest) (*csi.NodeStageVolumeResponse, error) {
-	llog := d.log.WithValues("method", "NodeStageVolume")
+	llog := d.requestLogger(ctx).WithValues("method", "NodeStageVolume")
 	llog.V(2).Info("NodeStageVolume called",
 		"volume_id", in.VolumeId,
 		"publish_context", in.PublishContext,
@@ -63,11 +69,101 @@ func (d *Driver) NodeStageVolume(ctx context.Context, in *csi.NodeStageVolumeReq
 		"volume_capability", in.VolumeCapability,
 		"volume_context", in.VolumeContext)
 
-	return nil, status.Error(codes.Unimplemented, "")
+	if !d.stagedMounts {
+		return nil, status.Error(codes.Unimplemented, "")
+	}
+
+	volumeID := in.GetVolumeId()
+	if volumeID == "" {
+		llog.Error(fmt.Errorf("volume id must not be empty"), InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, "Volume id must be provided")
+	}
+
+	stagingPath, err := validTargetPath("Staging Target Path", in.GetStagingTargetPath())
+	if err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	volumeCapability := in.GetVolumeCapability()
+	if volumeCapability == nil {
+		llog.Error(fmt.Errorf("volume capability must not be empty"), InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, "Volume Capability must be provided")
+	}
+
+	secrets, err := d.volumeSecrets(in.GetSecrets(), in.GetVolumeContext())
+	if err != nil {
+		llog.Error(err, "failed to resolve the realm secrets")
+		return nil, err
+	}
+	if _, err := d.parseRealmSecrets(secrets); err != nil {
+		llog.Error(err, InvalidRequestSecretsErrorStr)
+		return nil, status.Error(codes.InvalidArgument, InvalidRequestSecretsErrorStr)
+	}
+
+	if err := validateSecretsPrivateKey(secrets); err != nil {
+		llog.Error(err, InvalidRequestSecretsErrorStr)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	// stage and unstage of the same staging path must not interleave
+	defer d.targetLocks.lock(stagingPath, "stage")()
+
+	if !d.isSupportedCapability(volumeCapability) {
+		llog.Error(fmt.Errorf("unsupported volume capability"), "unsupported volume capability provided",
+			"volume_capability", volumeCapability)
+		return nil, status.Error(codes.FailedPrecondition, "unsupported volume capability provided")
+	}
+
+	// the volume is staged read-write, read-only publishes are read-only bind mounts
+	mountOptions, err := d.volumeMountOptions(llog, in.GetVolumeContext(), volumeCapability, false)
+	if err != nil {
+		return nil, err
+	}
+
+	kmipOptions, cleanup, err := d.kmipMountOptions(llog, in.GetVolumeContext(), secrets)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	mountOptions = append(mountOptions, kmipOptions...)
+
+	if err := d.checkDataPath(secrets[utils.RealmConnectionContext.RealmAddress]); err != nil {
+		llog.Error(err, "realm data path check failed", "volume_id", volumeID)
+		return nil, err
+	}
+
+	if err := d.mountRealmVolume(ctx, secrets[utils.RealmConnectionContext.RealmAddress], volumeID, stagingPath, mountOptions); err != nil {
+		d.mounts.failed("stage")
+		llog.Error(err, "failed to stage volume",
+			"volume_id", volumeID,
+			"staging_target_path", stagingPath,
+			"mount_options", mountOptions)
+		return nil, status.Error(codes.Internal, "Failed to stage volume: "+err.Error())
+	}
+
+	if d.shouldVerifyMount(in.GetVolumeContext()) {
+		if err := verifyMountIO(stagingPath); err != nil {
+			llog.Error(err, "staged volume failed IO verification",
+				"volume_id", volumeID,
+				"staging_target_path", stagingPath,
+				"mount_options", mountOptions)
+			if unmountErr := d.mounterV2.Unmount(ctx, stagingPath); unmountErr != nil {
+				llog.Error(unmountErr, "failed to unmount volume after failed IO verification", "volume_id", volumeID)
+			}
+			return nil, status.Errorf(codes.Internal, "Staged volume %s failed IO verification: %v", volumeID, err)
+		}
+	}
+
+	llog.Info("successfully staged volume",
+		"volume_id", volumeID,
+		"staging_target_path", stagingPath)
+	return &csi.NodeStageVolumeResponse{}, nil
 }
 
 // NodeUnstageVolume handles the CSI NodeUnstageVolume request.
-// Logs the request and returns an unimplemented error.
+// With staged mounts the volume is unmounted from the staging path, after the CO unpublished
+// it from all pods of the node. Without staged mounts the request is not supported.
 //
 // Parameters:
 //
@@ -76,17 +172,54 @@ func (d *Driver) NodeStageVolume(ctx context.Context, in *csi.NodeStageVolumeReq
 //
 // Returns:
 //
-//	*csi.NodeUnstageVolumeResponse - Always nil.
-//	error - Always returns codes.Unimplemented.
+//	*csi.NodeUnstageVolumeResponse - The response on success.
+//	error - Returns codes.Unimplemented without staged mounts, or an error for invalid input
+//	        or unmount failures.
 func (d *Driver) NodeUnstageVolume(ctx context.Context, in *csi.NodeUnstageVolumeRequest) (*csi.NodeUnstageVolumeResponse, error) {
-	llog := d.log.WithValues("method", "NodeUnstageVolume")
+	llog := d.requestLogger(ctx).WithValues("method", "NodeUnstageVolume")
 	llog.V(2).Info("NodeUnstageVolume called", "volume_id", in.VolumeId, "staging_path", in.StagingTargetPath)
-	return nil, status.Error(codes.Unimplemented, "")
+
+	if !d.stagedMounts {
+		return nil, status.Error(codes.Unimplemented, "")
+	}
+
+	volumeID := in.GetVolumeId()
+	if volumeID == "" {
+		llog.Error(fmt.Errorf("volume id must not be empty"), InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, "Volume id must be provided")
+	}
+
+	stagingPath, err := validTargetPath("Staging Target Path", in.GetStagingTargetPath())
+	if err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	defer d.targetLocks.lock(stagingPath, "unstage")()
+
+	release, err := d.unmounts.acquire(ctx)
+	if err != nil {
+		llog.Error(err, "no unmount worker became free", "volume_id", volumeID)
+		return nil, err
+	}
+	err = d.mounterV2.Unmount(ctx, stagingPath)
+	release()
+	if err != nil {
+		d.mounts.failed("unstage")
+		llog.Error(err, "failed to unstage volume", "volume_id", volumeID)
+		return nil, status.Error(codes.Internal, "Failed to unstage volume: "+err.Error())
+	}
+
+	llog.V(2).Info("Successfully unstaged volume",
+		"volume_id", volumeID,
+		"staging_path", stagingPath)
+	return &csi.NodeUnstageVolumeResponse{}, nil
 }
 
 // NodePublishVolume handles the CSI NodePublishVolume request.
 // Publishes the volume to the target path, validates input, and performs mount operations.
-// Returns error for invalid input, unsupported capabilities, or mount failures.
+// Returns error for invalid input, unsupported capabilities, unreachable realm data paths, or mount failures.
+// Inline ephemeral volumes are published as directories of the parent volume if enabled.
 //
 // Parameters:
 //
@@ -98,7 +231,7 @@ func (d *Driver) NodeUnstageVolume(ctx context.Context, in *csi.NodeUnstageVolum
 //	*csi.NodePublishVolumeResponse - The response on success.
 //	error - Returns error for invalid input, unsupported capability, or mount failure.
 func (d *Driver) NodePublishVolume(ctx context.Context, in *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
-	llog := d.log.WithValues("method", "NodePublishVolume")
+	llog := d.requestLogger(ctx).WithValues("method", "NodePublishVolume")
 	llog.V(2).Info("NodePublishVolume called",
 		"volume_id", in.VolumeId,
 		"publish_context", in.PublishContext,
@@ -114,18 +247,44 @@ func (d *Driver) NodePublishVolume(ctx context.Context, in *csi.NodePublishVolum
 		return nil, status.Error(codes.InvalidArgument, "Volume id must be provided")
 	}
 
-	secrets := in.GetSecrets()
-	if err := validateReqSecrets(secrets); err != nil {
+	if isEphemeralVolume(in.GetVolumeContext()) {
+		if d.ephemeral == nil {
+			llog.Error(fmt.Errorf("ephemeral volumes are not enabled"), "Unsupported ephemeral volume requested")
+			return nil, status.Error(codes.FailedPrecondition, "Ephemeral volumes are not supported by this driver")
+		}
+		// inline ephemeral volumes are never staged
+		return d.publishEphemeralVolume(ctx, llog, in)
+	}
+
+	if d.stagedMounts {
+		// the volume is mounted at the staging path, its secrets are passed to NodeStageVolume
+		return d.publishStagedVolume(ctx, llog, in)
+	}
+
+	secrets, err := d.volumeSecrets(in.GetSecrets(), in.GetVolumeContext())
+	if err != nil {
+		llog.Error(err, "failed to resolve the realm secrets")
+		return nil, err
+	}
+	if _, err := d.parseRealmSecrets(secrets); err != nil {
 		llog.Error(err, InvalidRequestSecretsErrorStr)
 		return nil, status.Error(codes.InvalidArgument, InvalidRequestSecretsErrorStr)
 	}
 
-	publishTargetPath := in.GetTargetPath()
-	if publishTargetPath == "" {
-		llog.Error(fmt.Errorf("target path must not be empty"), InvalidRequestErrorStr)
-		return nil, status.Error(codes.InvalidArgument, "Target Path must be provided")
+	if err := validateSecretsPrivateKey(secrets); err != nil {
+		llog.Error(err, InvalidRequestSecretsErrorStr)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	publishTargetPath, err := validTargetPath("Target Path", in.GetTargetPath())
+	if err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	// publish and unpublish of the same target must not interleave
+	defer d.targetLocks.lock(publishTargetPath, "publish")()
+
 	volumeCapability := in.GetVolumeCapability()
 	if volumeCapability == nil {
 		llog.Error(fmt.Errorf("volume capability must not be empty"), InvalidRequestErrorStr)
@@ -138,64 +297,36 @@ func (d *Driver) NodePublishVolume(ctx context.Context, in *csi.NodePublishVolum
 		return nil, status.Error(codes.FailedPrecondition, "unsupported volume capability provided")
 	}
 
-	if isEphemeral, ok := in.VolumeContext[EphemeralK8SVolumeContext]; ok && isEphemeral == "true" {
-		llog.Error(fmt.Errorf("ephemeral volumes are not supported by this driver"), "Unsupported ephemeral volume requested")
-		return nil, status.Error(codes.FailedPrecondition, "Ephemeral volumes are not supported by this driver")
+	mountOptions, err := d.volumeMountOptions(llog, in.GetVolumeContext(), volumeCapability, in.GetReadonly())
+	if err != nil {
+		return nil, err
 	}
 
-	mountOptions := volumeCapability.GetMount().GetMountFlags()
-	if in.GetReadonly() {
-		mountOptions = append(mountOptions, "ro")
+	targetDirPerms, err := d.publishTargetDirPermissions(in.GetVolumeContext())
+	if err != nil {
+		llog.Error(err, "invalid target directory permissions requested")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	if encryptionVal, ok := in.VolumeContext[utils.VolumeParameters.GetSCKey("encryption")]; ok && encryptionVal != "none" && encryptionVal != "" {
-		// Create a temporary KMIP Config file
-		if err := osMkdirAll("/var/tmp/kmip/", 0o700); err != nil {
-			llog.Error(err, "failed to create temp directory for KMIP config file")
-			return nil, status.Error(codes.Internal, "Failed to create temp directory for KMIP config file: "+err.Error())
-		}
-
-		kmipConfigFile, err := d.tempFileFactory.CreateTemp("/var/tmp/kmip/", "config_*.conf")
-		if err != nil {
-			llog.Error(err, "failed to create temporary KMIP config file for mounting")
-			return nil, status.Error(codes.Internal, "Failed to create KMIP config file: "+err.Error())
-		}
-
-		// Cleanup the temp file after mount operation, checking errors
-		defer func() {
-			if err := kmipConfigFile.Close(); err != nil {
-				llog.Error(err, "failed to close KMIP config file")
-			}
-		}()
-
-		defer func() {
-			if err := osRemove(kmipConfigFile.Name()); err != nil {
-				llog.Error(err, "failed to remove KMIP config file")
-			}
-		}()
-
-		// Set file permissions to 0700
-		err = osChmod(kmipConfigFile.Name(), 0o600)
-		if err != nil {
-			llog.Error(err, "failed to set '0700' permissions on KMIP config file")
-			return nil, status.Error(codes.Internal, "Failed to set '0700' permissions on KMIP config file: "+err.Error())
-		}
-
-		if in.Secrets[utils.RealmConnectionContext.KMIPConfigData] == "" {
-			llog.Error(fmt.Errorf("%s key is empty", utils.RealmConnectionContext.KMIPConfigData), "KMIP secret must be provided for encrypted volumes")
-			return nil, status.Error(codes.InvalidArgument, "KMIP secret must be provided for encrypted volumes")
-		}
+	kmipOptions, cleanup, err := d.kmipMountOptions(llog, in.GetVolumeContext(), secrets)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	mountOptions = append(mountOptions, kmipOptions...)
 
-		data := []byte(in.Secrets[utils.RealmConnectionContext.KMIPConfigData])
-		if _, err := kmipConfigFile.Write(data); err != nil {
-			llog.Error(err, "failed to write KMIP config data to temporary file")
-			return nil, status.Error(codes.Internal, "Failed to write KMIP config data to temporary file: "+err.Error())
-		}
+	if err := d.checkDataPath(secrets[utils.RealmConnectionContext.RealmAddress]); err != nil {
+		llog.Error(err, "realm data path check failed", "volume_id", volumeID)
+		return nil, err
+	}
 
-		mountOptions = append(mountOptions, fmt.Sprintf("kmip-config-file=%s", kmipConfigFile.Name()))
+	if err := prepareTargetDir(publishTargetPath, targetDirPerms); err != nil {
+		llog.Error(err, "failed to create target directory", "publish_target_path", publishTargetPath)
+		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	if err := d.mounterV2.Mount(fmt.Sprintf("panfs://%s/%s", in.GetSecrets()[utils.RealmConnectionContext.RealmAddress], volumeID), publishTargetPath, mountOptions); err != nil {
+	if err := d.mountRealmVolume(ctx, secrets[utils.RealmConnectionContext.RealmAddress], volumeID, publishTargetPath, mountOptions); err != nil {
+		d.mounts.failed("publish")
 		llog.Error(fmt.Errorf("failed to publish volume"), UnexpectedErrorInternalStr,
 			"volume_id", volumeID,
 			"publish_target_path", publishTargetPath,
@@ -203,14 +334,198 @@ func (d *Driver) NodePublishVolume(ctx context.Context, in *csi.NodePublishVolum
 		return nil, status.Error(codes.Internal, "Failed to publish volume: "+err.Error())
 	}
 
+	if d.shouldVerifyMount(in.GetVolumeContext()) {
+		if err := verifyMountIO(publishTargetPath); err != nil {
+			llog.Error(err, "published volume failed IO verification",
+				"volume_id", volumeID,
+				"publish_target_path", publishTargetPath,
+				"mount_options", mountOptions)
+			if unmountErr := d.mounterV2.Unmount(ctx, publishTargetPath); unmountErr != nil {
+				llog.Error(unmountErr, "failed to unmount volume after failed IO verification", "volume_id", volumeID)
+			}
+			return nil, status.Errorf(codes.Internal, "Published volume %s failed IO verification: %v", volumeID, err)
+		}
+	}
+
+	d.mounts.published(volumeID, publishTargetPath)
+
 	llog.Info("successfully published volume",
 		"volume_id", volumeID,
+		"volume_name", d.volumeNames.OriginalName(volumeID, in.GetVolumeContext()),
 		"publish_path", publishTargetPath)
 	return &csi.NodePublishVolumeResponse{}, nil
 }
 
+// volumeMountOptions returns the PanFS mount options of a volume: the options of its mount
+// profile and cache mode, followed by the mount flags of the volume capability.
+//
+// Parameters:
+//
+//	llog          - The logger of the request.
+//	volumeContext - The volume context of the volume.
+//	capability    - The volume capability of the request.
+//	readonly      - Whether the volume is mounted read-only.
+//
+// Returns:
+//
+//	[]string - The mount options.
+//	error    - A gRPC status error if the mount profile, cache mode or options are invalid.
+func (d *Driver) volumeMountOptions(llog klog.Logger, volumeContext map[string]string, capability *csi.VolumeCapability, readonly bool) ([]string, error) {
+	profileOptions, err := d.mountProfileOptions(volumeContext)
+	if err != nil {
+		llog.Error(err, "invalid mount profile requested")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	cacheOptions, err := cacheModeOptions(volumeContext)
+	if err != nil {
+		llog.Error(err, "unsupported cache mode requested")
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	mountOptions := capability.GetMount().GetMountFlags()
+	if len(profileOptions) > 0 || len(cacheOptions) > 0 {
+		// explicit mount flags are applied after the profile and cache options
+		mountOptions = append(slices.Concat(profileOptions, cacheOptions), mountOptions...)
+	}
+	if readonly {
+		mountOptions = append(mountOptions, "ro")
+	}
+	if err := validateMountOptions(mountOptions); err != nil {
+		llog.Error(err, "invalid mount options", "mount_options", mountOptions)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return mountOptions, nil
+}
+
+// mountRealmVolume mounts a volume of the realm at the target path. The realm addresses are
+// tried in the configured order, so a single unreachable director does not fail the mount.
+//
+// Parameters:
+//
+//	ctx          - The context of the request.
+//	realmAddress - The realm address or comma-separated list of addresses.
+//	volume       - The volume name.
+//	target       - The path to mount the volume at.
+//	options      - The mount options.
+//
+// Returns:
+//
+//	error - The joined mount errors of all addresses, or an error if an address is invalid.
+func (d *Driver) mountRealmVolume(ctx context.Context, realmAddress, volume, target string, options []string) error {
+	sources, err := utils.RealmMountSources(realmAddress, volume)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, source := range sources {
+		err := d.mounterV2.Mount(ctx, source, target, options)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+		if i < len(sources)-1 {
+			klog.FromContext(ctx).Error(err, "failed to mount volume, trying the next realm address", "source", source)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// kmipMountOptions writes the KMIP configuration of an encrypted volume and the optional
+// KMIP certificates of the secrets to temporary files and returns the mount options
+// referencing them. Volumes without encryption need no options.
+//
+// Parameters:
+//
+//	llog          - The logger of the request.
+//	volumeContext - The volume context of the volume.
+//	secrets       - The secrets of the request, with the KMIP configuration of encrypted volumes.
+//
+// Returns:
+//
+//	[]string - The kmip-config-file and certificate file mount options, empty for volumes without encryption.
+//	func()   - Removes the temporary files, to be called once the volume is mounted.
+//	error    - A gRPC status error if the configuration is missing, invalid or cannot be written.
+func (d *Driver) kmipMountOptions(llog klog.Logger, volumeContext, secrets map[string]string) ([]string, func(), error) {
+	encryptionVal, ok := volumeContext[utils.VolumeParameters.GetSCKey("encryption")]
+	if !ok || encryptionVal == "none" || encryptionVal == "" {
+		return nil, func() {}, nil
+	}
+
+	// Create a temporary KMIP Config file
+	if err := osMkdirAll(kmipDir, 0o700); err != nil {
+		llog.Error(err, "failed to create temp directory for KMIP config file")
+		return nil, nil, status.Error(codes.Internal, "Failed to create temp directory for KMIP config file: "+err.Error())
+	}
+
+	kmipConfigFile, err := d.tempFileFactory.CreateTemp(kmipDir, "config_*.conf")
+	if err != nil {
+		llog.Error(err, "failed to create temporary KMIP config file for mounting")
+		return nil, nil, status.Error(codes.Internal, "Failed to create KMIP config file: "+err.Error())
+	}
+
+	// Cleanup the temp file after mount operation, checking errors
+	cleanup := func() {
+		if err := osRemove(kmipConfigFile.Name()); err != nil {
+			llog.Error(err, "failed to remove KMIP config file")
+		}
+		if err := kmipConfigFile.Close(); err != nil {
+			llog.Error(err, "failed to close KMIP config file")
+		}
+	}
+	fail := func(err error) ([]string, func(), error) {
+		cleanup()
+		return nil, nil, err
+	}
+
+	// Set file permissions to 0700
+	err = osChmod(kmipConfigFile.Name(), 0o600)
+	if err != nil {
+		llog.Error(err, "failed to set '0700' permissions on KMIP config file")
+		return fail(status.Error(codes.Internal, "Failed to set '0700' permissions on KMIP config file: "+err.Error()))
+	}
+
+	if secrets[utils.RealmConnectionContext.KMIPConfigData] == "" {
+		llog.Error(fmt.Errorf("%s key is empty", utils.RealmConnectionContext.KMIPConfigData), "KMIP secret must be provided for encrypted volumes")
+		return fail(status.Error(codes.InvalidArgument, "KMIP secret must be provided for encrypted volumes"))
+	}
+
+	if err := validateKMIPConfig(secrets[utils.RealmConnectionContext.KMIPConfigData]); err != nil {
+		llog.Error(err, "invalid KMIP configuration")
+		return fail(status.Errorf(codes.InvalidArgument, "Invalid %s in the node-publish secret: %v",
+			utils.RealmConnectionContext.KMIPConfigData, err))
+	}
+
+	data := []byte(secrets[utils.RealmConnectionContext.KMIPConfigData])
+	if _, err := kmipConfigFile.Write(data); err != nil {
+		llog.Error(err, "failed to write KMIP config data to temporary file")
+		return fail(status.Error(codes.Internal, "Failed to write KMIP config data to temporary file: "+err.Error()))
+	}
+
+	certOptions, certCleanup, err := d.kmipCertificateOptions(llog, secrets)
+	if err != nil {
+		return fail(err)
+	}
+
+	options := append([]string{fmt.Sprintf("kmip-config-file=%s", kmipConfigFile.Name())}, certOptions...)
+	if err := validateMountOptions(options); err != nil {
+		llog.Error(err, "invalid KMIP mount options", "mount_options", options)
+		certCleanup()
+		return fail(status.Error(codes.Internal, err.Error()))
+	}
+	return options, func() {
+		certCleanup()
+		cleanup()
+	}, nil
+}
+
 // NodeUnpublishVolume handles the CSI NodeUnpublishVolume request.
 // Unpublishes the volume from the target path, validates input, and performs unmount operations.
+// The directory of an inline ephemeral volume is removed with its content.
 // Returns error for invalid input or unmount failures.
 //
 // Parameters:
@@ -223,7 +538,7 @@ func (d *Driver) NodePublishVolume(ctx context.Context, in *csi.NodePublishVolum
 //	*csi.NodeUnpublishVolumeResponse - The response on success.
 //	error - Returns error for invalid input or unmount failure.
 func (d *Driver) NodeUnpublishVolume(ctx context.Context, in *csi.NodeUnpublishVolumeRequest) (*csi.NodeUnpublishVolumeResponse, error) {
-	llog := d.log.WithValues("method", "NodeUnpublishVolume")
+	llog := d.requestLogger(ctx).WithValues("method", "NodeUnpublishVolume")
 	llog.V(2).Info("NodeUnpublishVolume called",
 		"volume_id", in.VolumeId,
 		"target_path", in.TargetPath)
@@ -233,17 +548,36 @@ func (d *Driver) NodeUnpublishVolume(ctx context.Context, in *csi.NodeUnpublishV
 		return nil, status.Error(codes.InvalidArgument, "Volume id must be provided")
 	}
 
-	publishTargetPath := in.GetTargetPath()
-	if publishTargetPath == "" {
-		llog.Error(fmt.Errorf("target path must not be empty"), "Target Path must be provided")
-		return nil, status.Error(codes.InvalidArgument, "Target Path must be provided")
+	publishTargetPath, err := validTargetPath("Target Path", in.GetTargetPath())
+	if err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	if err := d.mounterV2.Unmount(publishTargetPath); err != nil {
+	defer d.targetLocks.lock(publishTargetPath, "unpublish")()
+
+	release, err := d.unmounts.acquire(ctx)
+	if err != nil {
+		llog.Error(err, "no unmount worker became free", "volume_id", volumeID)
+		return nil, err
+	}
+	err = d.mounterV2.Unmount(ctx, publishTargetPath)
+	release()
+	if err != nil {
+		d.mounts.failed("unpublish")
 		llog.Error(err, "failed to unpublish volume", "volume_id", volumeID)
 		return nil, status.Error(codes.Internal, "Failed to unpublish volume: "+err.Error())
 	}
 
+	if d.ephemeral != nil {
+		if err := d.removeEphemeralVolume(ctx, llog, volumeID); err != nil {
+			llog.Error(err, "failed to remove ephemeral volume", "volume_id", volumeID)
+			return nil, status.Error(codes.Internal, "Failed to remove ephemeral volume: "+err.Error())
+		}
+	}
+
+	d.mounts.unpublished(volumeID, publishTargetPath)
+
 	llog.V(2).Info("Successfully unpublished volume",
 		"volume_id", volumeID,
 		"publish_path", publishTargetPath)
@@ -251,7 +585,8 @@ func (d *Driver) NodeUnpublishVolume(ctx context.Context, in *csi.NodeUnpublishV
 }
 
 // NodeGetCapabilities handles the CSI NodeGetCapabilities request.
-// Returns the supported node service capabilities for the CSI driver.
+// Returns the supported node service capabilities for the CSI driver, including
+// STAGE_UNSTAGE_VOLUME with staged mounts.
 //
 // Parameters:
 //
@@ -265,43 +600,40 @@ func (d *Driver) NodeUnpublishVolume(ctx context.Context, in *csi.NodeUnpublishV
 func (d *Driver) NodeGetCapabilities(ctx context.Context, in *csi.NodeGetCapabilitiesRequest) (*csi.NodeGetCapabilitiesResponse, error) {
 	d.log.V(2).Info("NodeGetCapabilities called")
 
-	return &csi.NodeGetCapabilitiesResponse{
-		Capabilities: []*csi.NodeServiceCapability{
-			{
-				Type: &csi.NodeServiceCapability_Rpc{
-					Rpc: &csi.NodeServiceCapability_RPC{
-						Type: csi.NodeServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
-					},
+	capabilities := []*csi.NodeServiceCapability{
+		{
+			Type: &csi.NodeServiceCapability_Rpc{
+				Rpc: &csi.NodeServiceCapability_RPC{
+					Type: csi.NodeServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
 				},
 			},
 		},
-	}, nil
-}
+	}
+	if d.stagedMounts {
+		capabilities = append(capabilities, &csi.NodeServiceCapability{
+			Type: &csi.NodeServiceCapability_Rpc{
+				Rpc: &csi.NodeServiceCapability_RPC{
+					Type: csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
+				},
+			},
+		})
+	}
+	if d.quotaRefresh.node {
+		capabilities = append(capabilities, &csi.NodeServiceCapability{
+			Type: &csi.NodeServiceCapability_Rpc{
+				Rpc: &csi.NodeServiceCapability_RPC{
+					Type: csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
+				},
+			},
+		})
+	}
 
-// NodeExpandVolume handles the CSI NodeExpandVolume request.
-// Logs the request and returns an unimplemented error.
-//
-// Parameters:
-//
-//	ctx - The context for the request.
-//	in  - The NodeExpandVolumeRequest containing volume and capacity details.
-//
-// Returns:
-//
-//	*csi.NodeExpandVolumeResponse - Always nil.
-//	error - Always returns codes.Unimplemented.
-func (d *Driver) NodeExpandVolume(ctx context.Context, in *csi.NodeExpandVolumeRequest) (*csi.NodeExpandVolumeResponse, error) {
-	d.log.V(2).Info("NodeExpandVolume called",
-		"volume_id", in.VolumeId,
-		"volume_path", in.VolumePath,
-		"capacity_range", in.CapacityRange,
-		"staging_target_path", in.StagingTargetPath,
-		"volume_capability", in.VolumeCapability)
-	return nil, status.Error(codes.Unimplemented, "")
+	return &csi.NodeGetCapabilitiesResponse{Capabilities: capabilities}, nil
 }
 
 // NodeGetInfo handles the CSI NodeGetInfo request.
-// Returns the node ID and maximum volumes per node.
+// Returns the node ID, maximum volumes per node and the topology of the node, including
+// the realms reachable by the node.
 //
 // Parameters:
 //
@@ -315,25 +647,28 @@ func (d *Driver) NodeExpandVolume(ctx context.Context, in *csi.NodeExpandVolumeR
 func (d *Driver) NodeGetInfo(ctx context.Context, in *csi.NodeGetInfoRequest) (*csi.NodeGetInfoResponse, error) {
 	d.log.V(2).Info("NodeGetInfo called")
 
+	segments := d.nodeTopologySegments(ctx)
+
 	// Set the label when starting up
-	nodeLabelValue := "true"
 	if err := d.updateNodeLabel(NodeLabelKey, nodeLabelValue); err != nil {
 		d.log.Error(err, "failed to set node label")
 		return &csi.NodeGetInfoResponse{
 			NodeId: d.host,
 			AccessibleTopology: &csi.Topology{
-				Segments: map[string]string{},
+				Segments: segments,
 			},
 			MaxVolumesPerNode: 0,
 		}, nil
 	}
 
+	// keep the label in place until the driver stops
+	d.startNodeLabelReconciler()
+
+	segments[NodeLabelKey] = nodeLabelValue
 	return &csi.NodeGetInfoResponse{
 		NodeId: d.host,
 		AccessibleTopology: &csi.Topology{
-			Segments: map[string]string{
-				NodeLabelKey: nodeLabelValue,
-			},
+			Segments: segments,
 		},
 		MaxVolumesPerNode: 0,
 	}, nil
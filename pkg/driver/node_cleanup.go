@@ -0,0 +1,140 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// staleNodeCleaner watches Node deletions and removes the driver-owned records of deleted
+// nodes. The zero value is ready to use.
+type staleNodeCleaner struct {
+	namespace string
+	cancel    context.CancelFunc
+	sync.Mutex
+}
+
+// WithStaleNodeCleanup enables the removal of driver-owned records, e.g. attachment records,
+// of nodes deleted from the cluster. Records are ConfigMaps in the namespace labeled with
+// DriverOwnerLabelKey and DriverNodeLabelKey. Records of nodes deleted while no controller
+// was running are removed when the watch starts.
+//
+// Parameters:
+//
+//	namespace - The namespace the driver is installed in. Empty disables the cleanup.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithStaleNodeCleanup(namespace string) Option {
+	return func(d *Driver) {
+		d.nodeCleaner.namespace = namespace
+	}
+}
+
+// startStaleNodeCleaner starts watching Node deletions, if enabled and not started yet.
+func (d *Driver) startStaleNodeCleaner() {
+	if d.kubeClient == nil || d.nodeCleaner.namespace == "" {
+		return
+	}
+
+	d.nodeCleaner.Lock()
+	defer d.nodeCleaner.Unlock()
+	if d.nodeCleaner.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.nodeCleaner.cancel = cancel
+
+	factory := informers.NewSharedInformerFactory(d.kubeClient, 0)
+	informer := factory.Core().V1().Nodes().Informer()
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if node, ok := obj.(*corev1.Node); ok {
+				d.cleanupNodeRecords(ctx, node.Name)
+			}
+		},
+	})
+	if err != nil {
+		d.log.Error(err, "failed to watch node deletions")
+		cancel()
+		d.nodeCleaner.cancel = nil
+		return
+	}
+
+	factory.Start(ctx.Done())
+	go func() {
+		if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+			return
+		}
+		d.cleanupOrphanedNodeRecords(ctx, informer.GetStore())
+	}()
+	d.log.V(4).Info("watching node deletions", "namespace", d.nodeCleaner.namespace)
+}
+
+// stopStaleNodeCleaner stops watching Node deletions.
+func (d *Driver) stopStaleNodeCleaner() {
+	d.nodeCleaner.Lock()
+	defer d.nodeCleaner.Unlock()
+	if d.nodeCleaner.cancel != nil {
+		d.nodeCleaner.cancel()
+		d.nodeCleaner.cancel = nil
+	}
+}
+
+// cleanupNodeRecords deletes the driver-owned records of the node. Several controller replicas
+// may clean up the same node, records already deleted are ignored.
+func (d *Driver) cleanupNodeRecords(ctx context.Context, node string) {
+	selector := fmt.Sprintf("%s,%s=%s", ownerSelector(d.Name), DriverNodeLabelKey, node)
+	llog := d.log.WithValues("node", node)
+	llog.Info("node was deleted, removing its driver-owned records")
+	if err := deleteOwnedConfigMaps(ctx, d.kubeClient, d.nodeCleaner.namespace, selector, llog); err != nil {
+		llog.Error(err, "failed to remove records of deleted node")
+	}
+}
+
+// cleanupOrphanedNodeRecords deletes the driver-owned records of nodes missing from the store,
+// i.e. nodes deleted while the watch was not running.
+func (d *Driver) cleanupOrphanedNodeRecords(ctx context.Context, nodes cache.Store) {
+	cms, err := d.kubeClient.CoreV1().ConfigMaps(d.nodeCleaner.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s,%s", ownerSelector(d.Name), DriverNodeLabelKey),
+	})
+	if err != nil {
+		d.log.Error(err, "failed to list driver-owned node records")
+		return
+	}
+
+	orphaned := make(map[string]bool)
+	for _, cm := range cms.Items {
+		node := cm.Labels[DriverNodeLabelKey]
+		if _, exists, _ := nodes.GetByKey(node); !exists {
+			orphaned[node] = true
+		}
+	}
+	for node := range orphaned {
+		d.cleanupNodeRecords(ctx, node)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+)
+
+// TestStaleNodeCleaner verifies that the records of deleted nodes are removed, including
+// records of nodes deleted before the watch started.
+func TestStaleNodeCleaner(t *testing.T) {
+	record := func(name, node string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "csi-panfs",
+			Labels:    map[string]string{DriverOwnerLabelKey: DefaultDriverName, DriverNodeLabelKey: node},
+		}}
+	}
+	kubeClient := fake.NewClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2"}},
+		record("node1-pv1", "node1"),
+		record("node2-pv1", "node2"),
+		record("gone-pv1", "gone"),
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      "foreign",
+			Namespace: "csi-panfs",
+			Labels:    map[string]string{DriverNodeLabelKey: "node1"},
+		}},
+	)
+	d := &Driver{
+		Name:       DefaultDriverName,
+		log:        klog.Background(),
+		kubeClient: kubeClient,
+	}
+	WithStaleNodeCleanup("csi-panfs")(d)
+	d.startStaleNodeCleaner()
+	defer d.stopStaleNodeCleaner()
+
+	recordNames := func() []string {
+		cms, err := kubeClient.CoreV1().ConfigMaps("csi-panfs").List(t.Context(), metav1.ListOptions{})
+		require.NoError(t, err)
+		var names []string
+		for _, cm := range cms.Items {
+			names = append(names, cm.Name)
+		}
+		return names
+	}
+
+	assert.Eventually(t, func() bool { return len(recordNames()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{"node1-pv1", "node2-pv1", "foreign"}, recordNames())
+
+	require.NoError(t, kubeClient.CoreV1().Nodes().Delete(t.Context(), "node1", metav1.DeleteOptions{}))
+	assert.Eventually(t, func() bool { return len(recordNames()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{"node2-pv1", "foreign"}, recordNames())
+}
+
+// TestStaleNodeCleanerDisabled verifies that no watch is started without a namespace.
+func TestStaleNodeCleanerDisabled(t *testing.T) {
+	d := &Driver{log: klog.Background(), kubeClient: fake.NewClientset()}
+	d.startStaleNodeCleaner()
+	assert.Nil(t, d.nodeCleaner.cancel)
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// nodeLabelValue is the value of the readiness label set on ready nodes.
+const nodeLabelValue = "true"
+
+// nodeLabelReconciler watches the Node object of the driver and re-applies the readiness
+// label when it is removed or changed. The zero value is ready to use.
+type nodeLabelReconciler struct {
+	cancel  context.CancelFunc
+	factory informers.SharedInformerFactory
+	sync.Mutex
+}
+
+// startNodeLabelReconciler starts watching the Node object of the driver, if not started yet.
+// Only the Node object of the driver is watched, so the label is patched only when it changes.
+func (d *Driver) startNodeLabelReconciler() {
+	if d.kubeClient == nil {
+		return
+	}
+
+	d.labelReconciler.Lock()
+	defer d.labelReconciler.Unlock()
+	if d.labelReconciler.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.labelReconciler.cancel = cancel
+
+	factory := informers.NewSharedInformerFactoryWithOptions(d.kubeClient, 0,
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", d.host).String()
+		}),
+	)
+	informer := factory.Core().V1().Nodes().Informer()
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    d.reconcileNodeLabel,
+		UpdateFunc: func(_, obj any) { d.reconcileNodeLabel(obj) },
+	})
+	if err != nil {
+		d.log.Error(err, "failed to watch node for label changes", "node", d.host)
+		cancel()
+		d.labelReconciler.cancel = nil
+		return
+	}
+
+	factory.Start(ctx.Done())
+	d.labelReconciler.factory = factory
+	d.log.V(4).Info("watching node label", "label", NodeLabelKey, "node", d.host)
+}
+
+// stopNodeLabelReconciler stops watching the Node object of the driver. It returns once the
+// informer has stopped, so the label is no longer re-applied afterwards.
+func (d *Driver) stopNodeLabelReconciler() {
+	d.labelReconciler.Lock()
+	defer d.labelReconciler.Unlock()
+	if d.labelReconciler.cancel != nil {
+		d.labelReconciler.cancel()
+		d.labelReconciler.cancel = nil
+	}
+	if d.labelReconciler.factory != nil {
+		// waits for the event handlers in flight
+		d.labelReconciler.factory.Shutdown()
+		d.labelReconciler.factory = nil
+	}
+}
+
+// reconcileNodeLabel re-applies the readiness label if it is missing from or differs on the node.
+func (d *Driver) reconcileNodeLabel(obj any) {
+	node, ok := obj.(*corev1.Node)
+	if !ok || node.Name != d.host || node.Labels[NodeLabelKey] == nodeLabelValue {
+		return
+	}
+
+	d.log.Info("node label was removed or changed, re-applying it", "label", NodeLabelKey, "node", d.host)
+	if err := d.updateNodeLabel(NodeLabelKey, nodeLabelValue); err != nil {
+		d.log.Error(err, "failed to re-apply node label", "node", d.host)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/klog/v2"
+)
+
+// TestNodeLabelReconciler verifies that a removed readiness label is re-applied while the driver runs.
+func TestNodeLabelReconciler(t *testing.T) {
+	origLabelSet := IsNodeLabelSet
+	defer func() { IsNodeLabelSet = origLabelSet }()
+
+	kubeClient := fake.NewClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2"}},
+	)
+	d := &Driver{
+		host:       "node1",
+		log:        klog.Background(),
+		kubeClient: kubeClient,
+	}
+	defer d.stopNodeLabelReconciler()
+
+	nodeLabel := func(name string) string {
+		node, err := kubeClient.CoreV1().Nodes().Get(t.Context(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+		return node.Labels[NodeLabelKey]
+	}
+	setNodeLabel := func(value string) {
+		node, err := kubeClient.CoreV1().Nodes().Get(t.Context(), "node1", metav1.GetOptions{})
+		require.NoError(t, err)
+		node.Labels = map[string]string{NodeLabelKey: value}
+		_, err = kubeClient.CoreV1().Nodes().Update(t.Context(), node, metav1.UpdateOptions{})
+		require.NoError(t, err)
+	}
+
+	_, err := d.NodeGetInfo(t.Context(), &csi.NodeGetInfoRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, nodeLabelValue, nodeLabel("node1"))
+
+	setNodeLabel("false")
+	assert.Eventually(t, func() bool { return nodeLabel("node1") == nodeLabelValue }, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, nodeLabel("node2"))
+
+	// the label is not re-applied once the reconciler has stopped
+	d.stopNodeLabelReconciler()
+	setNodeLabel("")
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, nodeLabel("node1"))
+}
+
+// TestStopNodeLabelReconcilerWaits verifies that stopping the reconciler waits for a label
+// update in flight, so it cannot re-apply the label after the driver removed it.
+func TestStopNodeLabelReconcilerWaits(t *testing.T) {
+	kubeClient := fake.NewClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}})
+	patching := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	kubeClient.PrependReactor("patch", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		once.Do(func() { close(patching) })
+		<-release
+		return false, nil, nil
+	})
+	d := &Driver{
+		host:       "node1",
+		log:        klog.Background(),
+		kubeClient: kubeClient,
+	}
+
+	d.startNodeLabelReconciler()
+	<-patching
+
+	stopped := make(chan struct{})
+	go func() {
+		d.stopNodeLabelReconciler()
+		close(stopped)
+	}()
+	assert.Never(t, func() bool {
+		select {
+		case <-stopped:
+			return true
+		default:
+			return false
+		}
+	}, 100*time.Millisecond, 10*time.Millisecond)
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		select {
+		case <-stopped:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+}
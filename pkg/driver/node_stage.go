@@ -0,0 +1,144 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	csi "github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// WithStagedMounts enables staged mounts: NodeStageVolume mounts a volume once per node at
+// its staging path, and NodePublishVolume bind mounts the staged volume into the target path
+// of each pod, instead of a separate PanFS mount per pod. This reduces the client sessions
+// of volumes shared by many pods of a node. The realm credentials and KMIP configuration
+// are then read from the node-stage secret of the storage class.
+//
+// Parameters:
+//
+//	enabled - Whether volumes are staged.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithStagedMounts(enabled bool) Option {
+	return func(d *Driver) {
+		d.stagedMounts = enabled
+	}
+}
+
+// publishStagedVolume publishes a volume staged by NodeStageVolume by bind mounting the
+// staging path at the target path. Read-only publishes are read-only bind mounts.
+//
+// Parameters:
+//
+//	ctx  - The context of the request.
+//	llog - The logger of the request.
+//	in   - The NodePublishVolumeRequest with a valid volume id.
+//
+// Returns:
+//
+//	*csi.NodePublishVolumeResponse - The response on success.
+//	error - Returns codes.FailedPrecondition if the volume is not staged, or an error for
+//	        invalid input, unsupported capabilities or mount failures.
+func (d *Driver) publishStagedVolume(ctx context.Context, llog klog.Logger, in *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
+	volumeID := in.GetVolumeId()
+
+	stagingPath, err := validTargetPath("Staging Target Path", in.GetStagingTargetPath())
+	if err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	publishTargetPath, err := validTargetPath("Target Path", in.GetTargetPath())
+	if err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// the bind mount of the staging path onto a path below it would hide the staged volume
+	if utils.PathWithin(stagingPath, publishTargetPath) {
+		err := fmt.Errorf("target path %s must not be within the staging target path %s", publishTargetPath, stagingPath)
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// publish and unpublish of the same target must not interleave
+	defer d.targetLocks.lock(publishTargetPath, "publish")()
+
+	volumeCapability := in.GetVolumeCapability()
+	if volumeCapability == nil {
+		llog.Error(fmt.Errorf("volume capability must not be empty"), InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, "Volume Capability must be provided")
+	}
+
+	if !d.isSupportedCapability(volumeCapability) {
+		llog.Error(fmt.Errorf("unsupported volume capability"), "unsupported volume capability provided",
+			"volume_capability", volumeCapability)
+		return nil, status.Error(codes.FailedPrecondition, "unsupported volume capability provided")
+	}
+
+	targetDirPerms, err := d.publishTargetDirPermissions(in.GetVolumeContext())
+	if err != nil {
+		llog.Error(err, "invalid target directory permissions requested")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// a bind mount of an unstaged path would publish the empty staging directory
+	staged, err := d.mounterV2.IsMountPoint(ctx, stagingPath)
+	if err != nil {
+		llog.Error(err, "failed to check staging target path", "staging_target_path", stagingPath)
+		return nil, status.Error(codes.Internal, "Failed to check staging target path: "+err.Error())
+	}
+	if !staged {
+		llog.Error(fmt.Errorf("volume is not staged"), "volume must be staged before it is published",
+			"volume_id", volumeID,
+			"staging_target_path", stagingPath)
+		return nil, status.Errorf(codes.FailedPrecondition, "Volume %s is not staged at %s", volumeID, stagingPath)
+	}
+
+	var mountOptions []string
+	if in.GetReadonly() {
+		mountOptions = append(mountOptions, "ro")
+	}
+
+	if err := prepareTargetDir(publishTargetPath, targetDirPerms); err != nil {
+		llog.Error(err, "failed to create target directory", "publish_target_path", publishTargetPath)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := d.mounterV2.BindMount(ctx, stagingPath, publishTargetPath, mountOptions); err != nil {
+		d.mounts.failed("publish")
+		llog.Error(err, "failed to publish staged volume",
+			"volume_id", volumeID,
+			"staging_target_path", stagingPath,
+			"publish_target_path", publishTargetPath,
+			"mount_options", mountOptions)
+		return nil, status.Error(codes.Internal, "Failed to publish volume: "+err.Error())
+	}
+
+	d.mounts.published(volumeID, publishTargetPath)
+
+	llog.Info("successfully published staged volume",
+		"volume_id", volumeID,
+		"staging_target_path", stagingPath,
+		"publish_path", publishTargetPath)
+	return &csi.NodePublishVolumeResponse{}, nil
+}
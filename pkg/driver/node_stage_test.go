@@ -0,0 +1,223 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// newStagingTestDriver returns a driver with staged mounts and a mock mounter.
+func newStagingTestDriver(t *testing.T) (*Driver, *mock.MockPanMounter) {
+	mockMounter := mock.NewMockPanMounter(gomock.NewController(t))
+	d := &Driver{Name: DefaultDriverName, log: klog.Background(), mounterV2: mockMounter}
+	WithStagedMounts(true)(d)
+	return d, mockMounter
+}
+
+// mountCapability returns a mount volume capability with the mount flags.
+func mountCapability(flags ...string) *csi.VolumeCapability {
+	return &csi.VolumeCapability{
+		AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{MountFlags: flags}},
+	}
+}
+
+func TestNodeStageVolume(t *testing.T) {
+	source := fmt.Sprintf("panfs://%s/%s", defaultSecrets[utils.RealmConnectionContext.RealmAddress], validVolumeName)
+
+	t.Run("Disabled", func(t *testing.T) {
+		d := &Driver{log: klog.Background()}
+		_, err := d.NodeStageVolume(t.Context(), &csi.NodeStageVolumeRequest{VolumeId: validVolumeName})
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+		_, err = d.NodeUnstageVolume(t.Context(), &csi.NodeUnstageVolumeRequest{VolumeId: validVolumeName})
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		d, mockMounter := newStagingTestDriver(t)
+		d.mountProfiles = MountProfiles{"throughput": {"noatime"}}
+		mockMounter.EXPECT().Mount(gomock.Any(), source, validStagingPath, []string{"noatime", "nodev"})
+
+		resp, err := d.NodeStageVolume(t.Context(), &csi.NodeStageVolumeRequest{
+			VolumeId:          validVolumeName,
+			StagingTargetPath: validStagingPath,
+			VolumeCapability:  mountCapability("nodev"),
+			VolumeContext:     map[string]string{utils.VolumeParameters.GetSCKey("profile"): "throughput"},
+			Secrets:           defaultSecrets,
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+
+	t.Run("InvalidRequests", func(t *testing.T) {
+		d, _ := newStagingTestDriver(t)
+		tests := map[string]*csi.NodeStageVolumeRequest{
+			"MissingVolumeID":    {StagingTargetPath: validStagingPath, VolumeCapability: mountCapability(), Secrets: defaultSecrets},
+			"MissingStagingPath": {VolumeId: validVolumeName, VolumeCapability: mountCapability(), Secrets: defaultSecrets},
+			"MissingCapability":  {VolumeId: validVolumeName, StagingTargetPath: validStagingPath, Secrets: defaultSecrets},
+			"MissingSecrets":     {VolumeId: validVolumeName, StagingTargetPath: validStagingPath, VolumeCapability: mountCapability()},
+			"InvalidMountOption": {VolumeId: validVolumeName, StagingTargetPath: validStagingPath, VolumeCapability: mountCapability("noatime=1"), Secrets: defaultSecrets},
+		}
+		for name, req := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := d.NodeStageVolume(t.Context(), req)
+				assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			})
+		}
+	})
+
+	t.Run("MountFailed", func(t *testing.T) {
+		d, mockMounter := newStagingTestDriver(t)
+		mockMounter.EXPECT().Mount(gomock.Any(), source, validStagingPath, gomock.Any()).Return(errors.New("mount failed"))
+
+		_, err := d.NodeStageVolume(t.Context(), &csi.NodeStageVolumeRequest{
+			VolumeId:          validVolumeName,
+			StagingTargetPath: validStagingPath,
+			VolumeCapability:  mountCapability(),
+			Secrets:           defaultSecrets,
+		})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestNodePublishStagedVolume(t *testing.T) {
+	newRequest := func(t *testing.T, readonly bool) *csi.NodePublishVolumeRequest {
+		return &csi.NodePublishVolumeRequest{
+			VolumeId:          validVolumeName,
+			StagingTargetPath: validStagingPath,
+			TargetPath:        filepath.Join(t.TempDir(), "mount"),
+			VolumeCapability:  mountCapability(),
+			Readonly:          readonly,
+		}
+	}
+
+	t.Run("BindMount", func(t *testing.T) {
+		d, mockMounter := newStagingTestDriver(t)
+		req := newRequest(t, false)
+		mockMounter.EXPECT().IsMountPoint(gomock.Any(), validStagingPath).Return(true, nil)
+		mockMounter.EXPECT().BindMount(gomock.Any(), validStagingPath, req.TargetPath, nil)
+
+		// the realm secrets are passed to NodeStageVolume only
+		_, err := d.NodePublishVolume(t.Context(), req)
+		require.NoError(t, err)
+		assert.DirExists(t, req.TargetPath)
+	})
+
+	t.Run("ReadOnly", func(t *testing.T) {
+		d, mockMounter := newStagingTestDriver(t)
+		req := newRequest(t, true)
+		mockMounter.EXPECT().IsMountPoint(gomock.Any(), validStagingPath).Return(true, nil)
+		mockMounter.EXPECT().BindMount(gomock.Any(), validStagingPath, req.TargetPath, []string{"ro"})
+
+		_, err := d.NodePublishVolume(t.Context(), req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("NotStaged", func(t *testing.T) {
+		d, mockMounter := newStagingTestDriver(t)
+		mockMounter.EXPECT().IsMountPoint(gomock.Any(), validStagingPath).Return(false, nil)
+
+		_, err := d.NodePublishVolume(t.Context(), newRequest(t, false))
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("MissingStagingPath", func(t *testing.T) {
+		d, _ := newStagingTestDriver(t)
+		req := newRequest(t, false)
+		req.StagingTargetPath = ""
+
+		_, err := d.NodePublishVolume(t.Context(), req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("NormalizedPaths", func(t *testing.T) {
+		d, mockMounter := newStagingTestDriver(t)
+		req := newRequest(t, false)
+		target := req.TargetPath
+		req.StagingTargetPath = validStagingPath + "/"
+		req.TargetPath = target + "/./"
+		mockMounter.EXPECT().IsMountPoint(gomock.Any(), validStagingPath).Return(true, nil)
+		mockMounter.EXPECT().BindMount(gomock.Any(), validStagingPath, target, nil)
+
+		_, err := d.NodePublishVolume(t.Context(), req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("InvalidPaths", func(t *testing.T) {
+		d, _ := newStagingTestDriver(t)
+		for name, mutate := range map[string]func(*csi.NodePublishVolumeRequest){
+			"RelativeTarget":  func(req *csi.NodePublishVolumeRequest) { req.TargetPath = "pods/uid/mount" },
+			"RelativeStaging": func(req *csi.NodePublishVolumeRequest) { req.StagingTargetPath = "staging/vol-123" },
+			"TargetIsStaging": func(req *csi.NodePublishVolumeRequest) { req.TargetPath = validStagingPath + "/" },
+			"TargetInStaging": func(req *csi.NodePublishVolumeRequest) { req.TargetPath = validStagingPath + "/mount" },
+		} {
+			t.Run(name, func(t *testing.T) {
+				req := newRequest(t, false)
+				mutate(req)
+
+				_, err := d.NodePublishVolume(t.Context(), req)
+				assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			})
+		}
+	})
+}
+
+func TestNodeUnstageVolume(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		d, mockMounter := newStagingTestDriver(t)
+		mockMounter.EXPECT().Unmount(gomock.Any(), validStagingPath)
+
+		_, err := d.NodeUnstageVolume(t.Context(), &csi.NodeUnstageVolumeRequest{VolumeId: validVolumeName, StagingTargetPath: validStagingPath})
+		assert.NoError(t, err)
+	})
+
+	t.Run("UnmountFailed", func(t *testing.T) {
+		d, mockMounter := newStagingTestDriver(t)
+		mockMounter.EXPECT().Unmount(gomock.Any(), validStagingPath).Return(errors.New("device busy"))
+
+		_, err := d.NodeUnstageVolume(t.Context(), &csi.NodeUnstageVolumeRequest{VolumeId: validVolumeName, StagingTargetPath: validStagingPath})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("MissingStagingPath", func(t *testing.T) {
+		d, _ := newStagingTestDriver(t)
+		_, err := d.NodeUnstageVolume(t.Context(), &csi.NodeUnstageVolumeRequest{VolumeId: validVolumeName})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestNodeGetCapabilitiesStagedMounts(t *testing.T) {
+	d, _ := newStagingTestDriver(t)
+	resp, err := d.NodeGetCapabilities(t.Context(), &csi.NodeGetCapabilitiesRequest{})
+	require.NoError(t, err)
+
+	var types []csi.NodeServiceCapability_RPC_Type
+	for _, capability := range resp.Capabilities {
+		types = append(types, capability.GetRpc().GetType())
+	}
+	assert.Contains(t, types, csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME)
+}
@@ -39,46 +39,6 @@ const (
 	invalidPublishPath     = ""
 )
 
-// TODO: move this test to the mounter_test.go
-// func TestNodeStageVolumeMountPointAlreadyExists(t *testing.T) {
-// 	ctrl := gomock.NewController(t)
-// 	mockMounter := mock.NewMockPanMounter(ctrl)
-// 	// mounter := mount.NewFakeMounter(nil)
-// 	driver := &Driver{
-// 		version:  "testing",
-// 		name:     DefaultDriverName,
-// 		endpoint: "unix:///tmp/csi.sock",
-// 		host:     "localhost",
-// 		mounter:  mounter,
-// 		mounterV2: mockMounter,
-// 		panfs:    nil, // node service is not using PanFS so it's OK to pass nil
-// 	}
-
-// 	mounter.MountPoints = []mount.MountPoint{
-// 		{
-// 			Path: validStagingPath,
-// 		},
-// 	}
-
-// 	req := &csi.NodeStageVolumeRequest{
-// 		VolumeId:          validVolumeName,
-// 		StagingTargetPath: validStagingPath,
-// 		VolumeCapability: &csi.VolumeCapability{
-// 			AccessType: &csi.VolumeCapability_Mount{
-// 				Mount: &csi.VolumeCapability_MountVolume{},
-// 			},
-// 		},
-// 		Secrets: defaultSecrets,
-// 	}
-// 	mockMounter.EXPECT()
-// 	resp, err := driver.NodeStageVolume(t.Context(), req)
-// 	assert.NoError(t, err)
-// 	assert.NotNil(t, resp)
-
-// 	assert.Len(t, mounter.MountPoints, 1)
-// 	assert.Equal(t, validStagingPath, mounter.MountPoints[0].Path)
-// }
-
 // TestNodePublishVolume tests the NodePublishVolume method of the Driver.
 // It covers scenarios for successful publish, error cases, unsupported capabilities, ephemeral volumes, and mount options.
 func TestNodePublishVolume(t *testing.T) {
@@ -94,7 +54,7 @@ func TestNodePublishVolume(t *testing.T) {
 	}
 
 	bindMountCalledZeroTimes := func() {
-		mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 	}
 
 	testCases := []struct {
@@ -122,12 +82,108 @@ func TestNodePublishVolume(t *testing.T) {
 			&csi.NodePublishVolumeResponse{},
 			nil,
 			func() {
-				mockMounter.EXPECT().Mount(
+				mockMounter.EXPECT().Mount(gomock.Any(),
 					fmt.Sprintf("panfs://%s/%s", defaultSecrets[utils.RealmConnectionContext.RealmAddress], validVolumeName),
 					validPublishTargetPath,
 					[]string{}).Times(1)
 			},
 		},
+		{
+			"Successfully published from IPv6 realm",
+			&csi.NodePublishVolumeRequest{
+				VolumeId:          validVolumeName,
+				StagingTargetPath: "",
+				TargetPath:        validPublishTargetPath,
+				VolumeCapability: &csi.VolumeCapability{
+					AccessType: &csi.VolumeCapability_Mount{
+						Mount: &csi.VolumeCapability_MountVolume{
+							MountFlags: []string{},
+						},
+					},
+				},
+				Secrets: map[string]string{
+					utils.RealmConnectionContext.RealmAddress: "fd00::1",
+					utils.RealmConnectionContext.Username:     "user",
+					utils.RealmConnectionContext.Password:     "pass",
+				},
+			},
+			&csi.NodePublishVolumeResponse{},
+			nil,
+			func() {
+				mockMounter.EXPECT().Mount(gomock.Any(),
+					fmt.Sprintf("panfs://[fd00::1]/%s", validVolumeName),
+					validPublishTargetPath,
+					[]string{}).Times(1)
+			},
+		},
+		{
+			"Published from the next realm address",
+			&csi.NodePublishVolumeRequest{
+				VolumeId:          validVolumeName,
+				StagingTargetPath: "",
+				TargetPath:        validPublishTargetPath,
+				VolumeCapability: &csi.VolumeCapability{
+					AccessType: &csi.VolumeCapability_Mount{
+						Mount: &csi.VolumeCapability_MountVolume{
+							MountFlags: []string{},
+						},
+					},
+				},
+				Secrets: map[string]string{
+					utils.RealmConnectionContext.RealmAddress: "10.0.0.1,10.0.0.2",
+					utils.RealmConnectionContext.Username:     "user",
+					utils.RealmConnectionContext.Password:     "pass",
+				},
+			},
+			&csi.NodePublishVolumeResponse{},
+			nil,
+			func() {
+				gomock.InOrder(
+					mockMounter.EXPECT().Mount(gomock.Any(),
+						fmt.Sprintf("panfs://10.0.0.1/%s", validVolumeName),
+						validPublishTargetPath,
+						[]string{}).Return(fmt.Errorf("director unreachable")),
+					mockMounter.EXPECT().Mount(gomock.Any(),
+						fmt.Sprintf("panfs://10.0.0.2/%s", validVolumeName),
+						validPublishTargetPath,
+						[]string{}),
+				)
+			},
+		},
+		{
+			"Publish failure on all realm addresses",
+			&csi.NodePublishVolumeRequest{
+				VolumeId:          validVolumeName,
+				StagingTargetPath: "",
+				TargetPath:        validPublishTargetPath,
+				VolumeCapability: &csi.VolumeCapability{
+					AccessType: &csi.VolumeCapability_Mount{
+						Mount: &csi.VolumeCapability_MountVolume{
+							MountFlags: []string{},
+						},
+					},
+				},
+				Secrets: map[string]string{
+					utils.RealmConnectionContext.RealmAddress: "10.0.0.1,10.0.0.2",
+					utils.RealmConnectionContext.Username:     "user",
+					utils.RealmConnectionContext.Password:     "pass",
+				},
+			},
+			nil,
+			status.Error(codes.Internal, "Failed to publish volume: director 10.0.0.1 unreachable\ndirector 10.0.0.2 unreachable"),
+			func() {
+				gomock.InOrder(
+					mockMounter.EXPECT().Mount(gomock.Any(),
+						fmt.Sprintf("panfs://10.0.0.1/%s", validVolumeName),
+						validPublishTargetPath,
+						[]string{}).Return(fmt.Errorf("director 10.0.0.1 unreachable")),
+					mockMounter.EXPECT().Mount(gomock.Any(),
+						fmt.Sprintf("panfs://10.0.0.2/%s", validVolumeName),
+						validPublishTargetPath,
+						[]string{}).Return(fmt.Errorf("director 10.0.0.2 unreachable")),
+				)
+			},
+		},
 		{
 			"Empty volume id",
 			&csi.NodePublishVolumeRequest{
@@ -165,7 +221,7 @@ func TestNodePublishVolume(t *testing.T) {
 			nil,
 			status.Error(codes.Internal, "Failed to publish volume: mounter error"),
 			func() {
-				mockMounter.EXPECT().Mount(
+				mockMounter.EXPECT().Mount(gomock.Any(),
 					fmt.Sprintf("panfs://%s/%s", defaultSecrets[utils.RealmConnectionContext.RealmAddress], validVolumeName),
 					validPublishTargetPath,
 					[]string{"noatime"}).Return(fmt.Errorf("mounter error")).Times(1)
@@ -189,7 +245,7 @@ func TestNodePublishVolume(t *testing.T) {
 			&csi.NodePublishVolumeResponse{},
 			nil,
 			func() {
-				mockMounter.EXPECT().Mount(
+				mockMounter.EXPECT().Mount(gomock.Any(),
 					fmt.Sprintf("panfs://%s/%s", defaultSecrets[utils.RealmConnectionContext.RealmAddress], validVolumeName),
 					validPublishTargetPath,
 					[]string{}).Times(1)
@@ -285,7 +341,7 @@ func TestNodePublishVolume(t *testing.T) {
 			&csi.NodePublishVolumeResponse{},
 			nil,
 			func() {
-				mockMounter.EXPECT().Mount(
+				mockMounter.EXPECT().Mount(gomock.Any(),
 					fmt.Sprintf("panfs://%s/%s", defaultSecrets[utils.RealmConnectionContext.RealmAddress], validVolumeName),
 					validPublishTargetPath,
 					[]string{"noatime", "ro"}).Times(1)
@@ -395,7 +451,7 @@ func TestNodePublishVolume_EncryptedVolume(t *testing.T) {
 			tempFileFactory: &errorTempFileFactory{},
 		}
 
-		mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
 		// Save original functions to restore after test
 		origMkdirAll := osMkdirAll
@@ -458,7 +514,7 @@ func TestNodePublishVolume_EncryptedVolume(t *testing.T) {
 		}
 
 		// Mount should NOT be called if chmod fails
-		mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
 		req := &csi.NodePublishVolumeRequest{
 			VolumeId:   validVolumeName,
@@ -511,7 +567,7 @@ func TestNodePublishVolume_EncryptedVolume(t *testing.T) {
 		}
 
 		// Mount should NOT be called if KMIP secret is missing
-		mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
 		req := &csi.NodePublishVolumeRequest{
 			VolumeId:   validVolumeName,
@@ -563,7 +619,7 @@ func TestNodePublishVolume_EncryptedVolume(t *testing.T) {
 		}
 
 		// Mount should NOT be called if write fails
-		mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
 		req := &csi.NodePublishVolumeRequest{
 			VolumeId:   validVolumeName,
@@ -577,7 +633,7 @@ func TestNodePublishVolume_EncryptedVolume(t *testing.T) {
 				utils.RealmConnectionContext.RealmAddress:   "realm",
 				utils.RealmConnectionContext.Username:       "user",
 				utils.RealmConnectionContext.Password:       "password",
-				utils.RealmConnectionContext.KMIPConfigData: "some data",
+				utils.RealmConnectionContext.KMIPConfigData: validKMIPConfigData,
 			},
 			VolumeContext: map[string]string{
 				utils.VolumeParameters.GetSCKey("encryption"): "on",
@@ -614,7 +670,7 @@ func TestNodePublishVolume_EncryptedVolume(t *testing.T) {
 		}
 
 		// Expect Mount to be called with the KMIP config file option
-		mockMounter.EXPECT().Mount(
+		mockMounter.EXPECT().Mount(gomock.Any(),
 			"panfs://realm/validVolumeName",
 			validPublishTargetPath,
 			mountOptsRegexpMatcher{pattern: regexp.MustCompile(`kmip-config-file=/var/tmp/kmip/config_test.conf`)},
@@ -632,7 +688,7 @@ func TestNodePublishVolume_EncryptedVolume(t *testing.T) {
 				utils.RealmConnectionContext.RealmAddress:   "realm",
 				utils.RealmConnectionContext.Username:       "user",
 				utils.RealmConnectionContext.Password:       "password",
-				utils.RealmConnectionContext.KMIPConfigData: "some data",
+				utils.RealmConnectionContext.KMIPConfigData: validKMIPConfigData,
 			},
 			VolumeContext: map[string]string{
 				utils.VolumeParameters.GetSCKey("encryption"): "on",
@@ -650,45 +706,6 @@ func TestNodePublishVolume_EncryptedVolume(t *testing.T) {
 	})
 }
 
-// TODO: move to the mounter
-// func TestPublishVolumeAlreadyPublished(t *testing.T) {
-// 	mounter := mount.NewFakeMounter(nil)
-// 	driver := &Driver{
-// 		version:  "testing",
-// 		name:     DefaultDriverName,
-// 		endpoint: "unix:///tmp/csi.sock",
-// 		host:     "localhost",
-// 		mounter:  mounter,
-// 		panfs:    nil, // node service is not using PanFS so it's OK to pass nil
-// 	}
-
-// 	// precreate mount point
-// 	mounter.MountPoints = append(mounter.MountPoints,
-// 		mount.MountPoint{
-// 			Device: "panfs://realm/volume",
-// 			Path:   validPublishTargetPath,
-// 			Type:   "panfs",
-// 			Opts:   []string{"noatime"},
-// 		})
-
-// 	resp, err := driver.NodePublishVolume(t.Context(),
-// 		&csi.NodePublishVolumeRequest{
-// 			VolumeId:          validVolumeName,
-// 			StagingTargetPath: validStagingPath,
-// 			TargetPath:        validPublishTargetPath,
-// 			VolumeCapability: &csi.VolumeCapability{
-// 				AccessType: &csi.VolumeCapability_Mount{
-// 					Mount: &csi.VolumeCapability_MountVolume{
-// 						MountFlags: []string{},
-// 					},
-// 				},
-// 			},
-// 		})
-// 	assert.NoError(t, err)
-// 	expectedResp := &csi.NodePublishVolumeResponse{}
-// 	assert.Equal(t, expectedResp, resp, "Expected response: %v, got: %v\n", expectedResp, resp)
-// }
-
 // TestUnpublishVolume tests the NodeUnpublishVolume method of the Driver.
 // It covers scenarios for successful unpublish, error cases, and unpublish failures.
 func TestUnpublishVolume(t *testing.T) {
@@ -719,7 +736,7 @@ func TestUnpublishVolume(t *testing.T) {
 			&csi.NodeUnpublishVolumeResponse{},
 			nil,
 			func() {
-				mockMounter.EXPECT().Unmount(validPublishTargetPath).Times(1)
+				mockMounter.EXPECT().Unmount(gomock.Any(), validPublishTargetPath).Times(1)
 			},
 		},
 		{
@@ -731,7 +748,7 @@ func TestUnpublishVolume(t *testing.T) {
 			nil,
 			status.Error(codes.InvalidArgument, "Volume id must be provided"),
 			func() {
-				mockMounter.EXPECT().Unmount(gomock.Any()).Times(0)
+				mockMounter.EXPECT().Unmount(gomock.Any(), gomock.Any()).Times(0)
 			},
 		},
 		{
@@ -743,7 +760,7 @@ func TestUnpublishVolume(t *testing.T) {
 			nil,
 			status.Error(codes.InvalidArgument, "Target Path must be provided"),
 			func() {
-				mockMounter.EXPECT().Unmount(gomock.Any()).Times(0)
+				mockMounter.EXPECT().Unmount(gomock.Any(), gomock.Any()).Times(0)
 			},
 		},
 		{
@@ -755,7 +772,7 @@ func TestUnpublishVolume(t *testing.T) {
 			nil,
 			status.Error(codes.Internal, "Failed to unpublish volume: mounter error"),
 			func() {
-				mockMounter.EXPECT().Unmount(
+				mockMounter.EXPECT().Unmount(gomock.Any(),
 					validPublishTargetPath).Return(fmt.Errorf("mounter error")).Times(1)
 			},
 		},
@@ -0,0 +1,81 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"fmt"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// PluginMode is the component a plugin instance runs as. The controller and node plugins
+// both serve the Identity service, the mode tells them apart in the GetPluginInfo
+// manifest, the plugin info metric and the startup log.
+type PluginMode string
+
+// Supported plugin modes.
+const (
+	// PluginModeController is the controller plugin of the controller deployment.
+	PluginModeController PluginMode = "controller"
+	// PluginModeNode is the node plugin of the node daemonset.
+	PluginModeNode PluginMode = "node"
+	// PluginModeAll is a plugin running as both controller and node plugin, e.g. in tests.
+	PluginModeAll PluginMode = "all"
+)
+
+// PluginModeManifestKey is the GetPluginInfo manifest key holding the plugin mode.
+const PluginModeManifestKey = utils.VendorPrefix + "mode"
+
+// ParsePluginMode validates a plugin mode, e.g. as configured by command line flags.
+//
+// Parameters:
+//
+//	value - The mode: controller, node or all.
+//
+// Returns:
+//
+//	PluginMode - The plugin mode.
+//	error      - Error if the mode is not supported.
+func ParsePluginMode(value string) (PluginMode, error) {
+	switch mode := PluginMode(value); mode {
+	case PluginModeController, PluginModeNode, PluginModeAll:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid plugin mode %q: must be %s, %s or %s", value, PluginModeController, PluginModeNode, PluginModeAll)
+	}
+}
+
+// WithPluginMode sets the component the plugin runs as, PluginModeAll by default.
+//
+// Parameters:
+//
+//	mode - The plugin mode.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithPluginMode(mode PluginMode) Option {
+	return func(d *Driver) {
+		d.mode = mode
+	}
+}
+
+// pluginMode returns the mode of the plugin, PluginModeAll unless configured.
+func (d *Driver) pluginMode() PluginMode {
+	if d.mode == "" {
+		return PluginModeAll
+	}
+	return d.mode
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// validateProtectionTierParameter validates the protectionTier storage class parameter, which
+// must be a valid label value so replication tooling can select volumes by tier.
+func validateProtectionTierParameter(parameters map[string]string) error {
+	key := utils.VolumeParameters.GetSCKey("protectionTier")
+	val, exist := parameters[key]
+	if !exist {
+		return nil
+	}
+	if val == "" {
+		return fmt.Errorf("%s must be provided", key)
+	}
+	if errs := validation.IsValidLabelValue(val); len(errs) > 0 {
+		return fmt.Errorf("%s is not valid: %s", key, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// withProtectionTier returns the parameters with the protection tier tagged in the
+// description of the volume, see utils.ProtectionTierTagPrefix.
+//
+// Parameters:
+//
+//	parameters - The parameters of the request.
+//
+// Returns:
+//
+//	map[string]string - A copy of the parameters with the tagged description, or the
+//	                    parameters if they have no protection tier.
+func withProtectionTier(parameters map[string]string) map[string]string {
+	tier, ok := parameters[utils.VolumeParameters.GetSCKey("protectionTier")]
+	if !ok {
+		return parameters
+	}
+	tagged := maps.Clone(parameters)
+	key := utils.VolumeParameters.GetSCKey("description")
+	tagged[key] = utils.TagProtectionTier(tagged[key], tier)
+	return tagged
+}
+
+// listVolumeContext returns the volume context of a volume listed by ListVolumes, with the
+// protection tier of the volume.
+//
+// Parameters:
+//
+//	vol - The PanFS volume.
+//
+// Returns:
+//
+//	map[string]string - The volume context, nil if the volume has no protection tier.
+func listVolumeContext(vol *utils.Volume) map[string]string {
+	tier := utils.ProtectionTier(vol.Description)
+	if tier == "" {
+		return nil
+	}
+	return map[string]string{utils.VolumeParameters.GetSCKey("protectionTier"): tier}
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// TestValidateProtectionTierParameter verifies that protection tiers must be label values.
+func TestValidateProtectionTierParameter(t *testing.T) {
+	tierKey := utils.VolumeParameters.GetSCKey("protectionTier")
+
+	assert.NoError(t, validateVolumeParameters(map[string]string{tierKey: "gold"}))
+	assert.NoError(t, validateVolumeParameters(map[string]string{tierKey: "rpo-15m.tier_1"}))
+	assert.Error(t, validateVolumeParameters(map[string]string{tierKey: ""}))
+	assert.Error(t, validateVolumeParameters(map[string]string{tierKey: "gold tier"}))
+	assert.Error(t, validateVolumeParameters(map[string]string{tierKey: "-gold"}))
+}
+
+// TestCreateVolumeProtectionTier verifies that the protection tier is tagged in the
+// description of created volumes and returned in their volume context.
+func TestCreateVolumeProtectionTier(t *testing.T) {
+	tierKey := utils.VolumeParameters.GetSCKey("protectionTier")
+	descriptionKey := utils.VolumeParameters.GetSCKey("description")
+
+	d, pancliMock := newSnapshotTestDriver(t)
+	pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), defaultSecrets).
+		DoAndReturn(func(_ context.Context, _ string, params pancli.VolumeCreateParams, _ map[string]string) (*utils.Volume, error) {
+			assert.Equal(t, "team data csi-tier:gold", params[descriptionKey])
+			return &utils.Volume{Name: utils.VolumeName(validVolumeName), Soft: 1, Description: params[descriptionKey]}, nil
+		})
+
+	resp, err := d.CreateVolume(t.Context(), &csi.CreateVolumeRequest{
+		Name:          validVolumeName,
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 1 << 30},
+		Parameters:    map[string]string{tierKey: "gold", descriptionKey: "team data"},
+		Secrets:       defaultSecrets,
+		VolumeCapabilities: []*csi.VolumeCapability{
+			{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "gold", resp.GetVolume().GetVolumeContext()[tierKey])
+}
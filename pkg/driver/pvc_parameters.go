@@ -0,0 +1,115 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Parameters added to CreateVolume requests by the external provisioner started with --extra-create-metadata.
+const (
+	PVCNameParameterKey      = "csi.storage.k8s.io/pvc/name"
+	PVCNamespaceParameterKey = "csi.storage.k8s.io/pvc/namespace"
+)
+
+// ParsePVCAnnotationParameters parses a comma separated list of volume parameters which
+// may be set by PVC annotations. Quotas are derived from the requested capacity and
+// cannot be set by annotations.
+//
+// Parameters:
+//
+//	list - The comma separated parameter names, e.g. "user,group".
+//
+// Returns:
+//
+//	[]string - The storage class keys of the parameters.
+//	error    - Error if a parameter is not supported.
+func ParsePVCAnnotationParameters(list string) ([]string, error) {
+	var keys []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		key := utils.VolumeParameters.GetSCKey(name)
+		if key == "" || key == utils.VolumeParameters.GetSCKey("soft") || key == utils.VolumeParameters.GetSCKey("hard") {
+			return nil, fmt.Errorf("parameter %q cannot be set by PVC annotations", name)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// WithPVCAnnotationParameters allows the given volume parameters to be set by annotations
+// of the PVC, overriding the storage class parameters. It requires the external provisioner
+// to run with --extra-create-metadata.
+//
+// Parameters:
+//
+//	keys - The storage class keys of the parameters, see ParsePVCAnnotationParameters.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithPVCAnnotationParameters(keys []string) Option {
+	return func(d *Driver) {
+		d.pvcAnnotationParameters = keys
+	}
+}
+
+// pvcParameters merges the allowed annotations of the PVC a volume is created for into the
+// storage class parameters. The parameters are returned unchanged if no annotations are
+// allowed or the request carries no PVC metadata.
+//
+// Parameters:
+//
+//	ctx        - The context for the Kubernetes API call.
+//	parameters - The parameters of the CreateVolume request.
+//
+// Returns:
+//
+//	map[string]string - The merged parameters.
+//	error             - Error if the PVC cannot be read.
+func (d *Driver) pvcParameters(ctx context.Context, parameters map[string]string) (map[string]string, error) {
+	name, namespace := parameters[PVCNameParameterKey], parameters[PVCNamespaceParameterKey]
+	if len(d.pvcAnnotationParameters) == 0 || name == "" || namespace == "" {
+		return parameters, nil
+	}
+	if d.kubeClient == nil {
+		return nil, fmt.Errorf("kubernetes client is not available to read PVC %s/%s", namespace, name)
+	}
+
+	pvc, err := d.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get PVC %s/%s: %w", namespace, name, err)
+	}
+
+	merged := make(map[string]string, len(parameters))
+	for k, v := range parameters {
+		merged[k] = v
+	}
+	for _, key := range d.pvcAnnotationParameters {
+		if value, ok := pvc.Annotations[key]; ok {
+			d.log.V(4).Info("parameter set by PVC annotation", "pvc", namespace+"/"+name, "parameter", key, "value", value)
+			merged[key] = value
+		}
+	}
+	return merged, nil
+}
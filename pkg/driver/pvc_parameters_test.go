@@ -0,0 +1,120 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+)
+
+func TestParsePVCAnnotationParameters(t *testing.T) {
+	keys, err := ParsePVCAnnotationParameters("user, group,,panfs.csi.vdura.com/uperm")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		utils.VolumeParameters.GetSCKey("user"),
+		utils.VolumeParameters.GetSCKey("group"),
+		utils.VolumeParameters.GetSCKey("uperm"),
+	}, keys)
+
+	keys, err = ParsePVCAnnotationParameters("")
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+
+	_, err = ParsePVCAnnotationParameters("user,unknown")
+	assert.ErrorContains(t, err, `parameter "unknown" cannot be set by PVC annotations`)
+
+	_, err = ParsePVCAnnotationParameters("soft")
+	assert.Error(t, err)
+}
+
+func TestCreateVolumePVCAnnotationParameters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	pancliMock := mock.NewMockStorageProviderClient(ctrl)
+
+	userKey := utils.VolumeParameters.GetSCKey("user")
+	groupKey := utils.VolumeParameters.GetSCKey("group")
+	upermKey := utils.VolumeParameters.GetSCKey("uperm")
+
+	kubeClient := fake.NewClientset(
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+			Name:      "data",
+			Namespace: "team-a",
+			Annotations: map[string]string{
+				userKey:  "alice",
+				groupKey: "not-allowed",
+			},
+		}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+			Name:        "invalid",
+			Namespace:   "team-a",
+			Annotations: map[string]string{upermKey: "everything"},
+		}},
+	)
+
+	driver := &Driver{
+		Name:                    DefaultDriverName,
+		log:                     klog.Background(),
+		panfs:                   pancliMock,
+		kubeClient:              kubeClient,
+		pvcAnnotationParameters: []string{userKey, upermKey},
+	}
+
+	request := func(pvc string) *csi.CreateVolumeRequest {
+		return &csi.CreateVolumeRequest{
+			Name: validVolumeName,
+			VolumeCapabilities: []*csi.VolumeCapability{{
+				AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER},
+			}},
+			Parameters: map[string]string{
+				userKey:                  "root",
+				groupKey:                 "root",
+				PVCNameParameterKey:      pvc,
+				PVCNamespaceParameterKey: "team-a",
+			},
+			Secrets: defaultSecrets,
+		}
+	}
+
+	t.Run("AllowedAnnotationsMerged", func(t *testing.T) {
+		params := pancli.NewVolumeCreateParamsBuilder().SetParameters(map[string]string{userKey: "alice", groupKey: "root"})
+		pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, createParams(t, params), defaultSecrets).Return(&utils.Volume{Name: utils.VolumeName(validVolumeName)}, nil)
+
+		_, err := driver.CreateVolume(t.Context(), request("data"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("InvalidAnnotation", func(t *testing.T) {
+		_, err := driver.CreateVolume(t.Context(), request("invalid"))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("MissingPVC", func(t *testing.T) {
+		_, err := driver.CreateVolume(t.Context(), request("missing"))
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// quotaRefreshConfig configures the refresh of the quota cached by the PanFS clients of the
+// nodes after volume expansions.
+type quotaRefreshConfig struct {
+	// request is set on controllers requesting NodeExpandVolume after expansions.
+	request bool
+	// node is set on nodes serving NodeExpandVolume.
+	node bool
+	// fixedVersion is the oldest PanFS client version not caching the quota, empty if all
+	// versions do.
+	fixedVersion string
+}
+
+// WithNodeQuotaRefresh makes ControllerExpandVolume request NodeExpandVolume on the nodes the
+// volume is published to, so their PanFS clients refresh the cached quota, see WithQuotaRefresh.
+//
+// Parameters:
+//
+//	enabled - Whether node expansion is requested after volume expansions.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithNodeQuotaRefresh(enabled bool) Option {
+	return func(d *Driver) {
+		d.quotaRefresh.request = enabled
+	}
+}
+
+// WithQuotaRefresh serves NodeExpandVolume, remounting expanded volumes if the PanFS client of
+// the node caches their quota, so df in the pods reports the new size without waiting for a
+// remount. Clients at or after the fixed version are left untouched.
+//
+// Parameters:
+//
+//	enabled      - Whether NodeExpandVolume is served.
+//	fixedVersion - The oldest PanFS client version not caching the quota, empty remounts
+//	               volumes with all client versions.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithQuotaRefresh(enabled bool, fixedVersion string) Option {
+	return func(d *Driver) {
+		d.quotaRefresh.node = enabled
+		d.quotaRefresh.fixedVersion = fixedVersion
+	}
+}
+
+// clientCachesQuota reports whether the PanFS client of the node caches the quota of mounted
+// volumes. Clients whose version cannot be detected are assumed to cache it.
+//
+// Returns:
+//
+//	bool   - True if mounted volumes must be remounted to refresh the quota.
+//	string - The detected client version, empty if unknown.
+func (d *Driver) clientCachesQuota() (bool, string) {
+	if d.quotaRefresh.fixedVersion == "" {
+		return true, ""
+	}
+	version, err := readClientVersion()
+	if err != nil {
+		d.log.V(4).Info("failed to detect the PanFS client version, assuming it caches the quota", "err", err)
+		return true, ""
+	}
+	return compareVersions(version, d.quotaRefresh.fixedVersion) < 0, version
+}
+
+// NodeExpandVolume handles the CSI NodeExpandVolume request. The volume is already expanded on
+// the realm; if the PanFS client of the node caches the quota, the PanFS mount of the volume is
+// remounted so the new size is reported. With staged mounts the staging path is remounted,
+// which the bind mounts of the pods share.
+//
+// Parameters:
+//
+//	ctx - The context for the request.
+//	in  - The NodeExpandVolumeRequest containing volume and capacity details.
+//
+// Returns:
+//
+//	*csi.NodeExpandVolumeResponse - The response with the required capacity.
+//	error - Returns an error if validation fails or the volume cannot be remounted.
+//
+// Error Cases:
+//   - codes.Unimplemented: If quota refresh is disabled (see WithQuotaRefresh).
+//   - codes.InvalidArgument: If the volume id or volume path is missing or invalid.
+//   - codes.NotFound: If the volume is not mounted at the path.
+//   - codes.Internal: If the volume cannot be remounted.
+func (d *Driver) NodeExpandVolume(ctx context.Context, in *csi.NodeExpandVolumeRequest) (*csi.NodeExpandVolumeResponse, error) {
+	llog := d.requestLogger(ctx).WithValues("method", "NodeExpandVolume")
+	llog.V(2).Info("NodeExpandVolume called",
+		"volume_id", in.VolumeId,
+		"volume_path", in.VolumePath,
+		"capacity_range", in.CapacityRange,
+		"staging_target_path", in.StagingTargetPath,
+		"volume_capability", in.VolumeCapability)
+
+	if !d.quotaRefresh.node {
+		return nil, status.Error(codes.Unimplemented, "")
+	}
+
+	if in.GetVolumeId() == "" {
+		llog.Error(fmt.Errorf("volume id must be provided"), InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, "volume id must be provided")
+	}
+	mountPath, err := validTargetPath("Volume Path", in.GetVolumePath())
+	if d.stagedMounts && in.GetStagingTargetPath() != "" {
+		mountPath, err = validTargetPath("Staging Target Path", in.GetStagingTargetPath())
+	}
+	if err != nil {
+		llog.Error(err, InvalidRequestErrorStr)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	resp := &csi.NodeExpandVolumeResponse{CapacityBytes: in.GetCapacityRange().GetRequiredBytes()}
+
+	defer d.targetLocks.lock(mountPath, "expand")()
+
+	mounted, err := d.mounterV2.IsMountPoint(ctx, mountPath)
+	if err != nil {
+		llog.Error(err, "failed to check the mount point", "path", mountPath)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !mounted {
+		return nil, status.Errorf(codes.NotFound, "volume %s is not mounted at %s", in.GetVolumeId(), mountPath)
+	}
+
+	caches, version := d.clientCachesQuota()
+	if !caches {
+		llog.V(4).Info("PanFS client does not cache the quota, skipping the remount",
+			"volume_id", in.GetVolumeId(), "client_version", version)
+		return resp, nil
+	}
+
+	if err := d.mounterV2.Remount(ctx, mountPath); err != nil {
+		llog.Error(err, "failed to remount the volume to refresh its quota", "volume_id", in.GetVolumeId(), "path", mountPath)
+		return nil, status.Errorf(codes.Internal, "failed to remount volume %s: %v", in.GetVolumeId(), err)
+	}
+	llog.Info("remounted the volume to refresh its quota", "volume_id", in.GetVolumeId(), "path", mountPath, "client_version", version)
+	return resp, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// TestNodeExpandVolumeRemount verifies that expanded volumes are remounted depending on the
+// version of the PanFS client.
+func TestNodeExpandVolumeRemount(t *testing.T) {
+	origReadClientVersion := readClientVersion
+	t.Cleanup(func() { readClientVersion = origReadClientVersion })
+
+	tests := []struct {
+		name         string
+		fixedVersion string
+		version      string
+		versionErr   error
+		remount      bool
+	}{
+		{name: "AllVersions", remount: true},
+		{name: "OldClient", fixedVersion: "11.1", version: "11.0.2.a-1234567.1", remount: true},
+		{name: "FixedClient", fixedVersion: "11.1", version: "11.1.0", remount: false},
+		{name: "UnknownClient", fixedVersion: "11.1", versionErr: errors.New("no such file"), remount: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			readClientVersion = func() (string, error) { return tc.version, tc.versionErr }
+			mockMounter := mock.NewMockPanMounter(gomock.NewController(t))
+			d := &Driver{log: klog.Background(), mounterV2: mockMounter}
+			WithQuotaRefresh(true, tc.fixedVersion)(d)
+
+			mockMounter.EXPECT().IsMountPoint(gomock.Any(), "/var/lib/kubelet/pods/pod/mount").Return(true, nil)
+			if tc.remount {
+				mockMounter.EXPECT().Remount(gomock.Any(), "/var/lib/kubelet/pods/pod/mount").Return(nil)
+			}
+
+			resp, err := d.NodeExpandVolume(t.Context(), &csi.NodeExpandVolumeRequest{
+				VolumeId:      validVolumeName,
+				VolumePath:    "/var/lib/kubelet/pods/pod/mount/",
+				CapacityRange: &csi.CapacityRange{RequiredBytes: GB10Bytes},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, GB10Bytes, resp.GetCapacityBytes())
+		})
+	}
+}
+
+// TestNodeExpandVolumeStaged verifies that the staging path is remounted with staged mounts.
+func TestNodeExpandVolumeStaged(t *testing.T) {
+	d, mockMounter := newStagingTestDriver(t)
+	WithQuotaRefresh(true, "")(d)
+
+	mockMounter.EXPECT().IsMountPoint(gomock.Any(), "/staging").Return(true, nil)
+	mockMounter.EXPECT().Remount(gomock.Any(), "/staging").Return(errors.New("mount failed"))
+
+	_, err := d.NodeExpandVolume(t.Context(), &csi.NodeExpandVolumeRequest{
+		VolumeId:          validVolumeName,
+		VolumePath:        "/target",
+		StagingTargetPath: "/staging",
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+// TestNodeExpandVolumeInvalid verifies the validation of NodeExpandVolume requests.
+func TestNodeExpandVolumeInvalid(t *testing.T) {
+	mockMounter := mock.NewMockPanMounter(gomock.NewController(t))
+	d := &Driver{log: klog.Background(), mounterV2: mockMounter}
+
+	_, err := d.NodeExpandVolume(t.Context(), &csi.NodeExpandVolumeRequest{VolumeId: validVolumeName, VolumePath: "/target"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	WithQuotaRefresh(true, "")(d)
+	_, err = d.NodeExpandVolume(t.Context(), &csi.NodeExpandVolumeRequest{VolumePath: "/target"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = d.NodeExpandVolume(t.Context(), &csi.NodeExpandVolumeRequest{VolumeId: validVolumeName, VolumePath: "target"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	mockMounter.EXPECT().IsMountPoint(gomock.Any(), "/target").Return(false, nil)
+	_, err = d.NodeExpandVolume(t.Context(), &csi.NodeExpandVolumeRequest{VolumeId: validVolumeName, VolumePath: "/target"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// TestControllerExpandRequestsNodeExpansion verifies that node expansion is requested after
+// volume expansions if enabled.
+func TestControllerExpandRequestsNodeExpansion(t *testing.T) {
+	const gib = int64(1) << 30
+	d, panfs := newSnapshotTestDriver(t)
+	WithNodeQuotaRefresh(true)(d)
+	gomock.InOrder(
+		panfs.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).
+			Return(&utils.Volume{Name: utils.VolumeName(validVolumeName), Soft: 5, QuotaUnit: utils.QuotaUnitGiB}, nil),
+		panfs.EXPECT().ExpandVolume(gomock.Any(), validVolumeName, 10*gib, defaultSecrets).Return(nil),
+		panfs.EXPECT().GetVolume(gomock.Any(), validVolumeName, defaultSecrets).
+			Return(&utils.Volume{Name: utils.VolumeName(validVolumeName), Soft: 10, QuotaUnit: utils.QuotaUnitGiB}, nil),
+	)
+
+	resp, err := d.ControllerExpandVolume(t.Context(), &csi.ControllerExpandVolumeRequest{
+		VolumeId:      validVolumeName,
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 10 * gib},
+		Secrets:       defaultSecrets,
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.GetNodeExpansionRequired())
+}
+
+// TestQuotaRefreshCapabilities verifies that node expansion is requested by the controller and
+// advertised by the node only if enabled.
+func TestQuotaRefreshCapabilities(t *testing.T) {
+	d := &Driver{}
+	resp, err := d.NodeGetCapabilities(t.Context(), &csi.NodeGetCapabilitiesRequest{})
+	require.NoError(t, err)
+	assert.Len(t, resp.GetCapabilities(), 1)
+
+	WithQuotaRefresh(true, "")(d)
+	resp, err = d.NodeGetCapabilities(t.Context(), &csi.NodeGetCapabilitiesRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.GetCapabilities(), 2)
+	assert.Equal(t, csi.NodeServiceCapability_RPC_EXPAND_VOLUME, resp.GetCapabilities()[1].GetRpc().GetType())
+}
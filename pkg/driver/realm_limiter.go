@@ -0,0 +1,217 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// DefaultRealmQueueWait is the default time a controller request waits for a free realm session slot.
+const DefaultRealmQueueWait = 5 * time.Second
+
+// backgroundStarvationLimit is the number of free slots handed to foreground requests in a
+// row while background requests are waiting, before the oldest background request gets one.
+const backgroundStarvationLimit = 4
+
+// realmPriority is the priority of a request for a realm session slot.
+type realmPriority int
+
+const (
+	// priorityForeground is the priority of user-facing requests, e.g. provisioning.
+	priorityForeground realmPriority = iota
+	// priorityBackground is the priority of requests polled periodically by the sidecars,
+	// e.g. capacity tracking, which are deferred in favor of foreground requests.
+	priorityBackground
+)
+
+// WithRealmConcurrencyLimit limits the number of controller requests talking to a realm at
+// the same time. Requests wait for a free slot at most maxWait and at most half of the time
+// left until their deadline, leaving the other half for the realm commands; afterwards they
+// fail with codes.Unavailable and a RetryInfo detail. A zero or negative limit disables it.
+// Free slots go to waiting foreground requests, e.g. CreateVolume, before background
+// requests polled by the sidecars, e.g. GetCapacity. To avoid starving background requests,
+// every few slots one goes to the oldest background request.
+//
+// Parameters:
+//
+//	limit   - The maximum number of concurrent requests per realm.
+//	maxWait - The maximum time a request waits for a free slot.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithRealmConcurrencyLimit(limit int, maxWait time.Duration) Option {
+	return func(d *Driver) {
+		d.realmLimiter.limit = limit
+		d.realmLimiter.maxWait = maxWait
+	}
+}
+
+// slotWaiter is a request waiting for a realm slot.
+type slotWaiter struct {
+	// ready is closed when the slot is handed to the waiter
+	ready   chan struct{}
+	granted bool
+}
+
+// realmSlots is the slot state of a realm.
+type realmSlots struct {
+	busy int
+	// waiting requests in arrival order, by priority
+	queues [2][]*slotWaiter
+	// number of slots handed to foreground requests in a row while background requests waited
+	deferrals int
+}
+
+// next removes and returns the waiter the next free slot is handed to, nil if none is waiting.
+func (s *realmSlots) next(realm string) *slotWaiter {
+	foreground, background := s.queues[priorityForeground], s.queues[priorityBackground]
+	switch {
+	case len(background) > 0 && (len(foreground) == 0 || s.deferrals >= backgroundStarvationLimit):
+		s.queues[priorityBackground] = background[1:]
+		s.deferrals = 0
+		return background[0]
+	case len(foreground) > 0:
+		if len(background) > 0 {
+			s.deferrals++
+			metrics.RealmBackgroundDeferrals.WithLabelValues(realm).Inc()
+		}
+		s.queues[priorityForeground] = foreground[1:]
+		return foreground[0]
+	}
+	return nil
+}
+
+// remove removes a waiter which gave up from its queue.
+func (s *realmSlots) remove(w *slotWaiter, priority realmPriority) {
+	queue := s.queues[priority]
+	for i, queued := range queue {
+		if queued == w {
+			s.queues[priority] = append(queue[:i:i], queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// realmLimiter limits concurrent controller requests per realm. The zero value does not limit requests.
+type realmLimiter struct {
+	limit   int
+	maxWait time.Duration
+	// key is the realm address
+	realms map[string]*realmSlots
+	sync.Mutex
+}
+
+// acquire waits for a free slot of the realm.
+//
+// Parameters:
+//
+//	ctx      - The context of the request, its deadline bounds the wait.
+//	realm    - The realm address.
+//	priority - The priority of the request.
+//
+// Returns:
+//
+//	func() - Function releasing the slot.
+//	error  - codes.Unavailable with RetryInfo if no slot became free in time.
+func (l *realmLimiter) acquire(ctx context.Context, realm string, priority realmPriority) (func(), error) {
+	if l.limit <= 0 {
+		return func() {}, nil
+	}
+
+	l.Lock()
+	if l.realms == nil {
+		l.realms = make(map[string]*realmSlots)
+	}
+	slots, ok := l.realms[realm]
+	if !ok {
+		slots = &realmSlots{}
+		l.realms[realm] = slots
+	}
+
+	release := func() { l.release(realm, slots) }
+	start := time.Now()
+
+	// slots are handed over on release, so there are no waiters while a slot is free
+	if slots.busy < l.limit {
+		slots.busy++
+		l.Unlock()
+		metrics.RealmQueueWait.WithLabelValues(realm).Observe(0)
+		return release, nil
+	}
+
+	w := &slotWaiter{ready: make(chan struct{})}
+	slots.queues[priority] = append(slots.queues[priority], w)
+	l.Unlock()
+
+	wait := l.maxWait
+	if deadline, ok := ctx.Deadline(); ok {
+		wait = min(wait, time.Until(deadline)/2)
+	}
+	timer := time.NewTimer(max(wait, 0))
+	defer timer.Stop()
+
+	select {
+	case <-w.ready:
+		metrics.RealmQueueWait.WithLabelValues(realm).Observe(time.Since(start).Seconds())
+		return release, nil
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+
+	l.Lock()
+	if w.granted {
+		// the slot was handed over while giving up
+		l.Unlock()
+		metrics.RealmQueueWait.WithLabelValues(realm).Observe(time.Since(start).Seconds())
+		return release, nil
+	}
+	slots.remove(w, priority)
+	l.Unlock()
+
+	metrics.RealmQueueWait.WithLabelValues(realm).Observe(time.Since(start).Seconds())
+	metrics.RealmQueueRejections.WithLabelValues(realm).Inc()
+	return nil, realmBusyError(realm, l.limit, l.maxWait)
+}
+
+// release hands the slot to the next waiting request, or frees it if none is waiting.
+func (l *realmLimiter) release(realm string, slots *realmSlots) {
+	l.Lock()
+	defer l.Unlock()
+
+	if w := slots.next(realm); w != nil {
+		w.granted = true
+		close(w.ready)
+		return
+	}
+	slots.busy--
+}
+
+// realmBusyError returns the error of a request rejected because all realm slots are busy.
+func realmBusyError(realm string, limit int, retryDelay time.Duration) error {
+	st := status.Newf(codes.Unavailable, "realm %s is busy: all %d session slots are in use", realm, limit)
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryDelay)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
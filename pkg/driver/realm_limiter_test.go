@@ -0,0 +1,160 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestRealmLimiter verifies that requests wait for free realm slots and fail fast with
+// Unavailable and RetryInfo when the realm stays busy.
+func TestRealmLimiter(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		var l realmLimiter
+		for range 3 {
+			_, err := l.acquire(t.Context(), "realm-disabled", priorityForeground)
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("WaitsForRelease", func(t *testing.T) {
+		l := realmLimiter{limit: 1, maxWait: 5 * time.Second}
+		release, err := l.acquire(t.Context(), "realm-wait", priorityForeground)
+		require.NoError(t, err)
+
+		time.AfterFunc(50*time.Millisecond, release)
+		release, err = l.acquire(t.Context(), "realm-wait", priorityForeground)
+		require.NoError(t, err)
+		release()
+
+		// other realms are limited separately
+		_, err = l.acquire(t.Context(), "realm-other", priorityForeground)
+		assert.NoError(t, err)
+	})
+
+	t.Run("FailsBeforeDeadline", func(t *testing.T) {
+		l := realmLimiter{limit: 1, maxWait: time.Minute}
+		_, err := l.acquire(t.Context(), "realm-busy", priorityForeground)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 200*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err = l.acquire(ctx, "realm-busy", priorityForeground)
+		assert.Less(t, time.Since(start), 200*time.Millisecond, "should give up before the deadline")
+
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.Unavailable, st.Code())
+		assert.Contains(t, st.Message(), "realm realm-busy is busy")
+		require.Len(t, st.Details(), 1)
+		retry, ok := st.Details()[0].(*errdetails.RetryInfo)
+		require.True(t, ok)
+		assert.Equal(t, time.Minute, retry.GetRetryDelay().AsDuration())
+
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.RealmQueueRejections.WithLabelValues("realm-busy")))
+	})
+	t.Run("ForegroundFirst", func(t *testing.T) {
+		l := realmLimiter{limit: 1, maxWait: 5 * time.Second}
+		release, err := l.acquire(t.Context(), "realm-priority", priorityForeground)
+		require.NoError(t, err)
+
+		order := make(chan realmPriority, 2)
+		waitFor := func(priority realmPriority) {
+			go func() {
+				release, err := l.acquire(t.Context(), "realm-priority", priority)
+				if assert.NoError(t, err) {
+					order <- priority
+					release()
+				}
+			}()
+		}
+		waitFor(priorityBackground)
+		waitForQueued(t, &l, "realm-priority", 0, 1)
+		waitFor(priorityForeground)
+		waitForQueued(t, &l, "realm-priority", 1, 1)
+
+		deferrals := testutil.ToFloat64(metrics.RealmBackgroundDeferrals.WithLabelValues("realm-priority"))
+		release()
+		assert.Equal(t, priorityForeground, <-order)
+		assert.Equal(t, priorityBackground, <-order)
+		assert.Equal(t, deferrals+1, testutil.ToFloat64(metrics.RealmBackgroundDeferrals.WithLabelValues("realm-priority")))
+	})
+
+	t.Run("BackgroundNotStarved", func(t *testing.T) {
+		l := realmLimiter{limit: 1, maxWait: 5 * time.Second}
+		release, err := l.acquire(t.Context(), "realm-starved", priorityForeground)
+		require.NoError(t, err)
+
+		background := make(chan func(), 1)
+		go func() {
+			release, err := l.acquire(t.Context(), "realm-starved", priorityBackground)
+			if assert.NoError(t, err) {
+				background <- release
+			}
+		}()
+		waitForQueued(t, &l, "realm-starved", 0, 1)
+
+		// foreground requests keep queuing, the background request still gets a slot
+		for range backgroundStarvationLimit {
+			granted := make(chan func(), 1)
+			go func() {
+				release, err := l.acquire(t.Context(), "realm-starved", priorityForeground)
+				if assert.NoError(t, err) {
+					granted <- release
+				}
+			}()
+			waitForQueued(t, &l, "realm-starved", 1, 1)
+			release()
+			release = <-granted
+		}
+
+		go func() { _, _ = l.acquire(t.Context(), "realm-starved", priorityForeground) }()
+		waitForQueued(t, &l, "realm-starved", 1, 1)
+		release()
+
+		// the slot went to the background request, the foreground request is still waiting
+		waitForQueued(t, &l, "realm-starved", 1, 0)
+		select {
+		case release := <-background:
+			release()
+		case <-time.After(5 * time.Second):
+			t.Fatal("background request did not get a slot")
+		}
+	})
+}
+
+// waitForQueued waits until the given numbers of foreground and background requests are
+// waiting for a slot of the realm.
+func waitForQueued(t *testing.T, l *realmLimiter, realm string, foreground, background int) {
+	t.Helper()
+	assert.Eventually(t, func() bool {
+		l.Lock()
+		defer l.Unlock()
+		slots := l.realms[realm]
+		return slots != nil && len(slots.queues[priorityForeground]) == foreground && len(slots.queues[priorityBackground]) == background
+	}, 5*time.Second, time.Millisecond)
+}
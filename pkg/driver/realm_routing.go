@@ -0,0 +1,118 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"maps"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/realm"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithRealmRegistry serves the realms of a registry, selected by name with the realm storage
+// class parameter, instead of connecting to the realm of the request secrets. Requests without
+// a storage class, e.g. DeleteVolume, select the realm with the realm_name key of their secrets.
+//
+// Parameters:
+//
+//	registry - The realm registry, nil disables realm selection.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithRealmRegistry(registry *realm.Registry) Option {
+	return func(d *Driver) {
+		d.realms = registry
+	}
+}
+
+// realmName returns the name of the realm selected by a request, set by the realm storage
+// class parameter or volume context key, or else by the realm_name key of the secrets.
+//
+// Parameters:
+//
+//	secrets    - The secrets of the request.
+//	parameters - The storage class parameters or volume context of the request.
+//
+// Returns:
+//
+//	string - The realm name, empty if the request selects no realm.
+func realmName(secrets, parameters map[string]string) string {
+	if name := parameters[utils.VolumeParameters.GetSCKey("realm")]; name != "" {
+		return name
+	}
+	return secrets[utils.RealmConnectionContext.RealmName]
+}
+
+// routeRealm returns the secrets of a request with the connection settings of the selected
+// realm of the registry, which take precedence. A credential handle of the request is dropped,
+// so only the settings of the selected realm are used. Requests selecting no realm are
+// returned unchanged.
+//
+// Parameters:
+//
+//	secrets    - The secrets of the request.
+//	parameters - The storage class parameters or volume context of the request.
+//
+// Returns:
+//
+//	map[string]string - The secrets with the settings of the selected realm.
+//	error             - The gRPC status error, codes.InvalidArgument if the realm is not
+//	                    registered or no realm registry is configured.
+func (d *Driver) routeRealm(secrets, parameters map[string]string) (map[string]string, error) {
+	name := realmName(secrets, parameters)
+	if name == "" {
+		return secrets, nil
+	}
+	if d.realms == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "realm %q is selected but no realm registry is configured", name)
+	}
+
+	selected, err := d.realms.Get(name)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v, registered realms: %v", err, d.realms.Names())
+	}
+
+	routed := maps.Clone(secrets)
+	if routed == nil {
+		routed = make(map[string]string, len(selected.Secrets))
+	}
+	delete(routed, utils.RealmConnectionContext.CredentialsHandle)
+	delete(routed, utils.RealmConnectionContext.RealmName)
+	maps.Copy(routed, selected.Secrets)
+	return routed, nil
+}
+
+// volumeSecrets returns the secrets of a node request: the settings of the realm selected by
+// the volume context, the request secrets or else the default secret.
+//
+// Parameters:
+//
+//	secrets       - The secrets of the request.
+//	volumeContext - The volume context of the request.
+//
+// Returns:
+//
+//	map[string]string - The secrets to use.
+//	error             - The gRPC status error, see routeRealm and requestSecrets.
+func (d *Driver) volumeSecrets(secrets, volumeContext map[string]string) (map[string]string, error) {
+	routed, err := d.routeRealm(secrets, volumeContext)
+	if err != nil {
+		return nil, err
+	}
+	return d.requestSecrets(routed)
+}
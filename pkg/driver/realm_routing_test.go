@@ -0,0 +1,134 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/realm"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// realmASecrets are the connection settings of realm-a of newTestRealmRegistry.
+var realmASecrets = map[string]string{
+	utils.RealmConnectionContext.RealmAddress: "realm-a.example.com",
+	utils.RealmConnectionContext.Username:     "admin",
+	utils.RealmConnectionContext.Password:     "secret",
+}
+
+// newTestRealmRegistry returns a registry of realm-a with connection settings and realm-b
+// referencing the credential handle "b".
+func newTestRealmRegistry(t *testing.T) *realm.Registry {
+	registry, err := realm.Parse([]byte(`
+realms:
+- name: realm-a
+  secrets:
+    realm_ip: realm-a.example.com
+    user: admin
+    password: secret
+- name: realm-b
+  secrets:
+    credentials_handle: b
+`))
+	require.NoError(t, err)
+	return registry
+}
+
+// TestRouteRealm verifies that the realm selected by the storage class, or else by the
+// secrets, replaces the connection settings of the request.
+func TestRouteRealm(t *testing.T) {
+	realmKey := utils.VolumeParameters.GetSCKey("realm")
+	handleSecrets := map[string]string{utils.RealmConnectionContext.RealmAddress: "realm-b.example.com"}
+
+	d := &Driver{}
+	WithRealmRegistry(newTestRealmRegistry(t))(d)
+	WithCredentialProvider(&staticCredentials{values: map[string]map[string]string{"b": handleSecrets}}, 0)(d)
+
+	secrets, err := d.resolveCredentials(t.Context(), nil, map[string]string{realmKey: "realm-a"})
+	require.NoError(t, err)
+	assert.Equal(t, realmASecrets, secrets)
+
+	// the settings of the selected realm take precedence, other secrets are kept
+	secrets, err = d.resolveCredentials(t.Context(), defaultSecrets, map[string]string{realmKey: "realm-a"})
+	require.NoError(t, err)
+	assert.Equal(t, "realm-a.example.com", secrets[utils.RealmConnectionContext.RealmAddress])
+	assert.Equal(t, defaultSecrets[utils.RealmConnectionContext.KMIPConfigData], secrets[utils.RealmConnectionContext.KMIPConfigData])
+
+	secrets, err = d.resolveCredentials(t.Context(), map[string]string{utils.RealmConnectionContext.RealmName: "realm-b"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, handleSecrets, secrets)
+
+	secrets, err = d.defaultSecrets(t.Context(), map[string]string{realmKey: "realm-a"})
+	require.NoError(t, err)
+	assert.Equal(t, realmASecrets, secrets)
+
+	// requests selecting no realm are unchanged
+	secrets, err = d.resolveCredentials(t.Context(), defaultSecrets, nil)
+	require.NoError(t, err)
+	assert.Equal(t, defaultSecrets, secrets)
+
+	_, err = d.resolveCredentials(t.Context(), nil, map[string]string{realmKey: "realm-c"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.ErrorContains(t, err, "[realm-a realm-b]")
+
+	_, err = (&Driver{}).resolveCredentials(t.Context(), defaultSecrets, map[string]string{realmKey: "realm-a"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// TestCreateVolumeRoutesRealm verifies that a volume is created on the realm selected by its
+// storage class, which is passed to the node plugin in the volume context.
+func TestCreateVolumeRoutesRealm(t *testing.T) {
+	realmKey := utils.VolumeParameters.GetSCKey("realm")
+	d, pancliMock := newSnapshotTestDriver(t)
+	WithRealmRegistry(newTestRealmRegistry(t))(d)
+
+	pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), realmASecrets).
+		Return(&utils.Volume{Name: utils.VolumeName(validVolumeName), Soft: 10.00}, nil)
+
+	resp, err := d.CreateVolume(t.Context(), &csi.CreateVolumeRequest{
+		Name:               validVolumeName,
+		CapacityRange:      &csi.CapacityRange{RequiredBytes: GB10Bytes},
+		VolumeCapabilities: []*csi.VolumeCapability{{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}, AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER}}},
+		Parameters:         map[string]string{realmKey: "realm-a"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "realm-a", resp.GetVolume().GetVolumeContext()[realmKey])
+}
+
+// TestVolumeSecretsRoutesRealm verifies that node requests use the realm of the volume context
+// instead of the request or default secret.
+func TestVolumeSecretsRoutesRealm(t *testing.T) {
+	realmKey := utils.VolumeParameters.GetSCKey("realm")
+	d := &Driver{}
+	WithRealmRegistry(newTestRealmRegistry(t))(d)
+	WithDefaultSecret(writeDefaultSecretDir(t, defaultSecrets))(d)
+
+	secrets, err := d.volumeSecrets(nil, map[string]string{realmKey: "realm-a"})
+	require.NoError(t, err)
+	assert.Equal(t, realmASecrets, secrets)
+
+	secrets, err = d.volumeSecrets(nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, defaultSecrets, secrets)
+
+	_, err = d.volumeSecrets(nil, map[string]string{realmKey: "realm-c"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
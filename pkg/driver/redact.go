@@ -0,0 +1,102 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// redactedValue replaces the content of secret fields in redacted messages.
+const redactedValue = utils.RedactedValue
+
+// redactedString returns a compact string representation of a CSI message with all
+// fields marked as csi_secret in the CSI spec replaced by a placeholder.
+//
+// Parameters:
+//
+//	msg - The protobuf message to render.
+//
+// Returns:
+//
+//	string - The redacted message, or an empty string for nil messages.
+func redactedString(msg proto.Message) string {
+	if msg == nil {
+		return ""
+	}
+
+	clone := proto.Clone(msg)
+	redactSecrets(clone.ProtoReflect())
+
+	out, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(clone)
+	if err != nil {
+		return "<unprintable " + string(msg.ProtoReflect().Descriptor().FullName()) + ">"
+	}
+	return string(out)
+}
+
+// redactSecrets walks through the message and replaces all secret fields in place.
+//
+// Parameters:
+//
+//	msg - The message to redact.
+func redactSecrets(msg protoreflect.Message) {
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if isSecretField(fd) {
+			switch {
+			case fd.IsMap():
+				m := msg.Mutable(fd).Map()
+				m.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+					m.Set(k, protoreflect.ValueOfString(redactedValue))
+					return true
+				})
+			case fd.Kind() == protoreflect.StringKind && !fd.IsList():
+				msg.Set(fd, protoreflect.ValueOfString(redactedValue))
+			default:
+				msg.Clear(fd)
+			}
+			return true
+		}
+
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				redactSecrets(list.Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+				redactSecrets(mv.Message())
+				return true
+			})
+		case fd.Message() != nil && !fd.IsMap():
+			redactSecrets(v.Message())
+		}
+		return true
+	})
+}
+
+// isSecretField reports whether the field is marked with the csi_secret option.
+func isSecretField(fd protoreflect.FieldDescriptor) bool {
+	opts := fd.Options()
+	if opts == nil {
+		return false
+	}
+	secret, ok := proto.GetExtension(opts, csi.E_CsiSecret).(bool)
+	return ok && secret
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Trailing metadata keys returned on controller RPC responses. The keys are a stable
+// interface for correlating the logs of CSI sidecars with the driver logs.
+const (
+	// MetadataDriverVersion holds the version of the driver serving the request.
+	MetadataDriverVersion = "x-panfs-csi-driver-version"
+	// MetadataRequestID holds the ID of the request, logged by the driver as request_id.
+	MetadataRequestID = "x-panfs-csi-request-id"
+	// MetadataRealmLatency holds the time in milliseconds the request spent in realm commands.
+	MetadataRealmLatency = "x-panfs-csi-realm-latency-ms"
+)
+
+// controllerMethodPrefix is the prefix of the full method names of the controller service.
+const controllerMethodPrefix = "/csi.v1.Controller/"
+
+type realmTimerKey struct{}
+
+// realmTimer accumulates the time a request spends in realm commands.
+type realmTimer struct {
+	elapsed atomic.Int64
+}
+
+// newRequestID returns a random ID identifying a request in the driver logs.
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// responseMetadataInterceptor returns the driver version, request ID and realm latency of
+// controller RPCs as trailing metadata. The request ID and realm timer are taken from
+// loggingInterceptor, or created if the interceptor did not run.
+func (d *Driver) responseMetadataInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !strings.HasPrefix(info.FullMethod, controllerMethodPrefix) {
+		return handler(ctx, req)
+	}
+
+	requestID, ok := utils.RequestIDFromContext(ctx)
+	if !ok {
+		requestID = newRequestID()
+		ctx = utils.ContextWithRequestID(ctx, requestID)
+	}
+	timer, ok := ctx.Value(realmTimerKey{}).(*realmTimer)
+	if !ok {
+		timer = &realmTimer{}
+		ctx = context.WithValue(ctx, realmTimerKey{}, timer)
+	}
+
+	resp, err := handler(ctx, req)
+
+	// fails only outside of a gRPC server, e.g. in tests calling the interceptor directly
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(
+		MetadataDriverVersion, d.Version,
+		MetadataRequestID, requestID,
+		MetadataRealmLatency, strconv.FormatInt(time.Duration(timer.elapsed.Load()).Milliseconds(), 10),
+	))
+	return resp, err
+}
+
+// realm returns the storage provider client of the driver, accounting the time spent in
+// realm commands to the request of the context.
+//
+// Parameters:
+//
+//	ctx - The context of the request.
+//
+// Returns:
+//
+//	StorageProviderClient - The storage provider client.
+func (d *Driver) realm(ctx context.Context) StorageProviderClient {
+	timer, ok := ctx.Value(realmTimerKey{}).(*realmTimer)
+	if !ok {
+		return d.panfs
+	}
+	return &timedStorageProvider{client: d.panfs, timer: timer}
+}
+
+// timedStorageProvider measures the time spent in the calls of a StorageProviderClient.
+type timedStorageProvider struct {
+	client StorageProviderClient
+	timer  *realmTimer
+}
+
+// track adds the time since start to the realm timer.
+func (t *timedStorageProvider) track(start time.Time) {
+	t.timer.elapsed.Add(int64(time.Since(start)))
+}
+
+// CreateVolume implements StorageProviderClient.
+func (t *timedStorageProvider) CreateVolume(ctx context.Context, volumeName string, params pancli.VolumeCreateParams, secret map[string]string) (*utils.Volume, error) {
+	defer t.track(time.Now())
+	return t.client.CreateVolume(ctx, volumeName, params, secret)
+}
+
+// DeleteVolume implements StorageProviderClient.
+func (t *timedStorageProvider) DeleteVolume(ctx context.Context, volID string, secret map[string]string) error {
+	defer t.track(time.Now())
+	return t.client.DeleteVolume(ctx, volID, secret)
+}
+
+// ExpandVolume implements StorageProviderClient.
+func (t *timedStorageProvider) ExpandVolume(ctx context.Context, volumeName string, targetSize int64, secret map[string]string) error {
+	defer t.track(time.Now())
+	return t.client.ExpandVolume(ctx, volumeName, targetSize, secret)
+}
+
+// ModifyVolume implements StorageProviderClient.
+func (t *timedStorageProvider) ModifyVolume(ctx context.Context, volumeName string, params pancli.VolumeModifyParams, secret map[string]string) error {
+	defer t.track(time.Now())
+	return t.client.ModifyVolume(ctx, volumeName, params, secret)
+}
+
+// ListVolumes implements StorageProviderClient.
+func (t *timedStorageProvider) ListVolumes(ctx context.Context, secret map[string]string) (*utils.VolumeList, error) {
+	defer t.track(time.Now())
+	return t.client.ListVolumes(ctx, secret)
+}
+
+// GetVolume implements StorageProviderClient.
+func (t *timedStorageProvider) GetVolume(ctx context.Context, volumeName string, secret map[string]string) (*utils.Volume, error) {
+	defer t.track(time.Now())
+	return t.client.GetVolume(ctx, volumeName, secret)
+}
+
+// CreateSnapshot implements StorageProviderClient.
+func (t *timedStorageProvider) CreateSnapshot(ctx context.Context, volumeName, snapshotName string, secret map[string]string) (*utils.Snapshot, error) {
+	defer t.track(time.Now())
+	return t.client.CreateSnapshot(ctx, volumeName, snapshotName, secret)
+}
+
+// DeleteSnapshot implements StorageProviderClient.
+func (t *timedStorageProvider) DeleteSnapshot(ctx context.Context, volumeName, snapshotName string, secret map[string]string) error {
+	defer t.track(time.Now())
+	return t.client.DeleteSnapshot(ctx, volumeName, snapshotName, secret)
+}
+
+// ListSnapshots implements StorageProviderClient.
+func (t *timedStorageProvider) ListSnapshots(ctx context.Context, volumeName string, secret map[string]string) (*utils.SnapshotList, error) {
+	defer t.track(time.Now())
+	return t.client.ListSnapshots(ctx, volumeName, secret)
+}
+
+// CreateVolumeFromSnapshot implements StorageProviderClient.
+func (t *timedStorageProvider) CreateVolumeFromSnapshot(ctx context.Context, volumeName, sourceVolume, snapshotName string, params pancli.VolumeCreateParams, secret map[string]string) (*utils.Volume, error) {
+	defer t.track(time.Now())
+	return t.client.CreateVolumeFromSnapshot(ctx, volumeName, sourceVolume, snapshotName, params, secret)
+}
+
+// CreateDirectory implements StorageProviderClient.
+func (t *timedStorageProvider) CreateDirectory(ctx context.Context, volumeName, directory string, hardBytes int64, secret map[string]string) (*utils.Directory, error) {
+	defer t.track(time.Now())
+	return t.client.CreateDirectory(ctx, volumeName, directory, hardBytes, secret)
+}
+
+// DeleteDirectory implements StorageProviderClient.
+func (t *timedStorageProvider) DeleteDirectory(ctx context.Context, volumeName, directory string, secret map[string]string) error {
+	defer t.track(time.Now())
+	return t.client.DeleteDirectory(ctx, volumeName, directory, secret)
+}
+
+// SetDirectoryQuota implements StorageProviderClient.
+func (t *timedStorageProvider) SetDirectoryQuota(ctx context.Context, volumeName, directory string, hardBytes int64, secret map[string]string) error {
+	defer t.track(time.Now())
+	return t.client.SetDirectoryQuota(ctx, volumeName, directory, hardBytes, secret)
+}
+
+// GetDirectory implements StorageProviderClient.
+func (t *timedStorageProvider) GetDirectory(ctx context.Context, volumeName, directory string, secret map[string]string) (*utils.Directory, error) {
+	defer t.track(time.Now())
+	return t.client.GetDirectory(ctx, volumeName, directory, secret)
+}
+
+// GetCapacity implements StorageProviderClient.
+func (t *timedStorageProvider) GetCapacity(ctx context.Context, bladeset string, secret map[string]string) (int64, error) {
+	defer t.track(time.Now())
+	return t.client.GetCapacity(ctx, bladeset, secret)
+}
+
+// GetRealmFeatures implements StorageProviderClient.
+func (t *timedStorageProvider) GetRealmFeatures(ctx context.Context, secret map[string]string) (*utils.RealmFeatures, error) {
+	defer t.track(time.Now())
+	return t.client.GetRealmFeatures(ctx, secret)
+}
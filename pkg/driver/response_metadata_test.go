@@ -0,0 +1,104 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package driver
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"k8s.io/klog/v2"
+)
+
+// TestResponseMetadata verifies the trailing metadata of controller and identity RPCs.
+func TestResponseMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	panfs := mock.NewMockStorageProviderClient(ctrl)
+	panfs.EXPECT().DeleteVolume(gomock.Any(), validVolumeName, gomock.Any()).DoAndReturn(func(context.Context, string, map[string]string) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+
+	d := &Driver{Name: DefaultDriverName, Version: "1.2.3", log: klog.Background(), panfs: panfs}
+	server := d.newServer()
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	t.Run("Controller", func(t *testing.T) {
+		var trailer metadata.MD
+		_, err := csi.NewControllerClient(conn).DeleteVolume(t.Context(),
+			&csi.DeleteVolumeRequest{VolumeId: validVolumeName, Secrets: defaultSecrets}, grpc.Trailer(&trailer))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"1.2.3"}, trailer.Get(MetadataDriverVersion))
+		require.Len(t, trailer.Get(MetadataRequestID), 1)
+		assert.Len(t, trailer.Get(MetadataRequestID)[0], 16)
+		require.Len(t, trailer.Get(MetadataRealmLatency), 1)
+		latency, err := strconv.Atoi(trailer.Get(MetadataRealmLatency)[0])
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, latency, 20)
+	})
+
+	t.Run("Identity", func(t *testing.T) {
+		var trailer metadata.MD
+		_, err := csi.NewIdentityClient(conn).Probe(t.Context(), &csi.ProbeRequest{}, grpc.Trailer(&trailer))
+		require.NoError(t, err)
+		assert.Empty(t, trailer.Get(MetadataRequestID))
+	})
+}
+
+// TestRealmContext verifies that the context of the request, including its deadline, is
+// passed to the realm calls, with and without a realm timer.
+func TestRealmContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	panfs := mock.NewMockStorageProviderClient(ctrl)
+	d := &Driver{panfs: panfs, log: klog.Background()}
+	secrets := map[string]string{"realm_ip": "realm"}
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Minute)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+	panfs.EXPECT().DeleteVolume(gomock.Any(), validVolumeName, secrets).DoAndReturn(func(got context.Context, _ string, _ map[string]string) error {
+		gotDeadline, ok := got.Deadline()
+		assert.True(t, ok)
+		assert.Equal(t, deadline, gotDeadline)
+		return nil
+	}).Times(2)
+
+	assert.NoError(t, d.realm(ctx).DeleteVolume(ctx, validVolumeName, secrets))
+	timed := context.WithValue(ctx, realmTimerKey{}, &realmTimer{})
+	assert.NoError(t, d.realm(timed).DeleteVolume(timed, validVolumeName, secrets))
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package driver
+
+import (
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// validateSecretsPrivateKey checks that the SSH private key in the secrets, if any, can be parsed
+// with the provided passphrase.
+//
+// Parameters:
+//
+//	secrets - Map of secret keys and values.
+//
+// Returns:
+//
+//	error - Returns pancli.ErrorUnauthenticated describing why the key cannot be used.
+func validateSecretsPrivateKey(secrets map[string]string) error {
+	privateKey := secrets[utils.RealmConnectionContext.PrivateKey]
+	if privateKey == "" {
+		return nil
+	}
+
+	_, err := pancli.ParsePrivateKey(privateKey, secrets[utils.RealmConnectionContext.PrivateKeyPassphrase])
+	return err
+}
@@ -0,0 +1,31 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build nodeonly
+
+package driver
+
+// validateSecretsPrivateKey accepts any SSH private key in node-only builds. The node plugin
+// never connects to the realm over SSH, so the key is validated by the controller only.
+//
+// Parameters:
+//
+//	secrets - Map of secret keys and values.
+//
+// Returns:
+//
+//	error - Always nil.
+func validateSecretsPrivateKey(secrets map[string]string) error {
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVerifySecret_InvalidPrivateKey verifies that a private key that cannot be parsed is
+// reported without connecting to the realm.
+func TestVerifySecret_InvalidPrivateKey(t *testing.T) {
+	secrets := map[string]string{
+		utils.RealmConnectionContext.RealmAddress: "10.0.0.1",
+		utils.RealmConnectionContext.Username:     "admin",
+		utils.RealmConnectionContext.PrivateKey:   "not a key",
+	}
+
+	connected := false
+	got := VerifySecret(secrets, credentialVerifierFunc(func(map[string]string) error {
+		connected = true
+		return nil
+	}))
+
+	require.Len(t, got.Reasons, 1)
+	assert.Equal(t, SecretReasonInvalidPrivateKey, got.Reasons[0].Code)
+	assert.NotEmpty(t, got.Reasons[0].Message)
+	assert.False(t, got.Valid)
+	assert.False(t, connected)
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// WithForbidPasswordAuth rejects realm secrets which authenticate with a password only, e.g.
+// once key-based authentication is rolled out cluster-wide. Secrets must hold a private key,
+// or an API token for the REST realm provider.
+//
+// Parameters:
+//
+//	forbid - Whether password authentication is forbidden.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithForbidPasswordAuth(forbid bool) Option {
+	return func(d *Driver) {
+		d.forbidPasswordAuth = forbid
+	}
+}
+
+// parseRealmSecrets parses and validates the realm secrets of a request, applying the
+// authentication policy of the driver.
+//
+// Parameters:
+//
+//	secrets - The secrets of the request.
+//
+// Returns:
+//
+//	utils.RealmSecrets - The parsed secrets.
+//	error              - Error if required secrets are missing or invalid, or
+//	                     utils.ErrPasswordAuthForbidden if the secrets hold a password only
+//	                     while password authentication is forbidden.
+func (d *Driver) parseRealmSecrets(secrets map[string]string) (utils.RealmSecrets, error) {
+	realmSecrets, err := utils.ParseRealmSecrets(secrets)
+	if err != nil {
+		return utils.RealmSecrets{}, err
+	}
+	if d.forbidPasswordAuth {
+		if err := realmSecrets.ValidateKeyAuth(); err != nil {
+			return utils.RealmSecrets{}, err
+		}
+	}
+	return realmSecrets, nil
+}
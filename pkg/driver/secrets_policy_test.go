@@ -0,0 +1,52 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestForbidPasswordAuth verifies that secrets with a password only are rejected once
+// password authentication is forbidden, while secrets with a private key are accepted.
+func TestForbidPasswordAuth(t *testing.T) {
+	d, pancliMock := newSnapshotTestDriver(t)
+
+	_, err := d.parseRealmSecrets(defaultSecrets)
+	require.NoError(t, err)
+
+	WithForbidPasswordAuth(true)(d)
+	_, err = d.parseRealmSecrets(defaultSecrets)
+	assert.ErrorIs(t, err, utils.ErrPasswordAuthForbidden)
+
+	_, err = d.DeleteVolume(t.Context(), &csi.DeleteVolumeRequest{VolumeId: validVolumeName, Secrets: defaultSecrets})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.ErrorContains(t, err, "password authentication is forbidden")
+
+	keySecrets := map[string]string{utils.RealmConnectionContext.PrivateKey: "key"}
+	for k, v := range defaultSecrets {
+		keySecrets[k] = v
+	}
+	pancliMock.EXPECT().DeleteVolume(gomock.Any(), validVolumeName, keySecrets).Return(nil)
+	_, err = d.DeleteVolume(t.Context(), &csi.DeleteVolumeRequest{VolumeId: validVolumeName, Secrets: keySecrets})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	csi "github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// ListenFunc creates the listener the gRPC server accepts connections on, see net.Listen.
+type ListenFunc func(network, address string) (net.Listener, error)
+
+// WithListenFunc replaces net.Listen for creating the endpoint listener, e.g. to serve
+// requests from an in-memory listener in tests.
+//
+// Parameters:
+//
+//	listen - The listener factory.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithListenFunc(listen ListenFunc) Option {
+	return func(d *Driver) {
+		d.listen = listen
+	}
+}
+
+// WithServerOptions adds options to the gRPC server, after the options set by the driver.
+//
+// Parameters:
+//
+//	opts - The gRPC server options.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithServerOptions(opts ...grpc.ServerOption) Option {
+	return func(d *Driver) {
+		d.serverOptions = append(d.serverOptions, opts...)
+	}
+}
+
+// parseEndpoint splits a CSI endpoint into network and address. Endpoints without a
+// scheme are unix socket paths.
+//
+// Parameters:
+//
+//	endpoint - The endpoint, e.g. "/csi/csi.sock", "unix:///csi/csi.sock" or "tcp://127.0.0.1:10000".
+//
+// Returns:
+//
+//	string - The network, "unix" or "tcp".
+//	string - The address to listen on.
+//	error  - Error if the scheme is not supported or the address is empty.
+func parseEndpoint(endpoint string) (string, string, error) {
+	network, address := "unix", endpoint
+	if scheme, rest, ok := strings.Cut(endpoint, "://"); ok {
+		network, address = strings.ToLower(scheme), rest
+	} else if rest, ok := strings.CutPrefix(endpoint, "unix:"); ok {
+		address = rest
+	}
+
+	if network != "unix" && network != "tcp" {
+		return "", "", fmt.Errorf("unsupported endpoint scheme %q in %s", network, endpoint)
+	}
+	if address == "" {
+		return "", "", fmt.Errorf("endpoint %q has no address", endpoint)
+	}
+	return network, address, nil
+}
+
+// listenEndpoint creates the listener for the driver endpoint. Stale unix sockets left
+// behind by a previous run are removed first.
+//
+// Returns:
+//
+//	net.Listener - The endpoint listener.
+//	error        - Error if the endpoint is invalid or cannot be listened on.
+func (d *Driver) listenEndpoint() (net.Listener, error) {
+	network, address, err := parseEndpoint(d.endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if network == "unix" {
+		if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove address %s: %w", address, err)
+		}
+	}
+
+	listen := d.listen
+	if listen == nil {
+		listen = net.Listen
+	}
+	lis, err := listen(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	return lis, nil
+}
+
+// newServer creates the gRPC server with the CSI services of the driver registered.
+//
+// Returns:
+//
+//	*grpc.Server - The gRPC server.
+func (d *Driver) newServer() *grpc.Server {
+	serverOpts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(d.unaryInterceptors()...)}
+	if d.slowRPCThreshold > 0 {
+		serverOpts = append(serverOpts, grpc.StatsHandler(newSlowRPCHandler(d.slowRPCThreshold, d.log)))
+	}
+	serverOpts = append(serverOpts, d.serverOptions...)
+
+	grpcServer := grpc.NewServer(serverOpts...)
+	csi.RegisterIdentityServer(grpcServer, d)
+	registerControllerServer(grpcServer, d)
+	csi.RegisterNodeServer(grpcServer, d)
+
+	reflection.Register(grpcServer)
+
+	return grpcServer
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/klog/v2"
+)
+
+func TestParseEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint    string
+		wantNetwork string
+		wantAddress string
+		wantErr     string
+	}{
+		{endpoint: "/csi/csi.sock", wantNetwork: "unix", wantAddress: "/csi/csi.sock"},
+		{endpoint: "unix:///csi/csi.sock", wantNetwork: "unix", wantAddress: "/csi/csi.sock"},
+		{endpoint: "unix:/csi/csi.sock", wantNetwork: "unix", wantAddress: "/csi/csi.sock"},
+		{endpoint: "tcp://127.0.0.1:10000", wantNetwork: "tcp", wantAddress: "127.0.0.1:10000"},
+		{endpoint: "http://127.0.0.1:10000", wantErr: `unsupported endpoint scheme "http"`},
+		{endpoint: "unix://", wantErr: "has no address"},
+		{endpoint: "", wantErr: "has no address"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.endpoint, func(t *testing.T) {
+			network, address, err := parseEndpoint(tc.endpoint)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantNetwork, network)
+			assert.Equal(t, tc.wantAddress, address)
+		})
+	}
+}
+
+func TestRunListenError(t *testing.T) {
+	d := &Driver{
+		endpoint: "tcp://127.0.0.1:0",
+		log:      klog.Background(),
+	}
+	WithListenFunc(func(network, address string) (net.Listener, error) {
+		return nil, &net.OpError{Op: "listen", Net: network, Err: os.ErrPermission}
+	})(d)
+
+	err := d.Run(t.Context())
+	assert.ErrorIs(t, err, os.ErrPermission)
+
+	// a failed start does not leave the driver marked as running
+	assert.Nil(t, d.server)
+}
+
+// closeRecorder records when the listener is closed.
+type closeRecorder struct {
+	net.Listener
+	onClose func()
+}
+
+func (l *closeRecorder) Close() error {
+	l.onClose()
+	return l.Listener.Close()
+}
+
+// TestStopOrdering verifies that the node label is removed before the server stops accepting connections.
+func TestStopOrdering(t *testing.T) {
+	origLabelSet := IsNodeLabelSet
+	IsNodeLabelSet = true
+	defer func() { IsNodeLabelSet = origLabelSet }()
+
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+
+	kubeClient := fake.NewClientset()
+	kubeClient.PrependReactor("patch", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		record("label removed")
+		return true, nil, nil
+	})
+
+	lis := bufconn.Listen(1 << 20)
+	d := &Driver{
+		Name:       DefaultDriverName,
+		Version:    "testing",
+		endpoint:   "tcp://bufconn",
+		host:       "node1",
+		log:        klog.Background(),
+		kubeClient: kubeClient,
+	}
+	WithListenFunc(func(string, string) (net.Listener, error) {
+		return &closeRecorder{Listener: lis, onClose: func() { record("listener closed") }}, nil
+	})(d)
+
+	done := make(chan error, 1)
+	go func() { done <- d.Run(context.Background()) }()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	_, err = csi.NewIdentityClient(conn).Probe(t.Context(), &csi.ProbeRequest{})
+	require.NoError(t, err)
+
+	d.Stop()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("driver did not stop")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, events)
+	assert.Equal(t, "label removed", events[0], fmt.Sprint(events))
+	assert.Contains(t, events, "listener closed")
+}
@@ -0,0 +1,290 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"google.golang.org/grpc"
+	"k8s.io/klog/v2"
+)
+
+// Defaults of the provisioning SLO.
+const (
+	DefaultSLOThreshold = time.Minute
+	DefaultSLOWindow    = time.Hour
+)
+
+// sloBuckets is the number of buckets the rolling SLO window is divided into.
+const sloBuckets = 60
+
+// sloAllOperations is the operation label of the ratio of all volume operations.
+const sloAllOperations = "all"
+
+// sloOperations are the RPCs counted as volume operations by the provisioning SLO.
+var sloOperations = map[string]bool{
+	"CreateVolume":           true,
+	"DeleteVolume":           true,
+	"ControllerExpandVolume": true,
+	"CreateSnapshot":         true,
+	"DeleteSnapshot":         true,
+	"NodeStageVolume":        true,
+	"NodeUnstageVolume":      true,
+	"NodePublishVolume":      true,
+	"NodeUnpublishVolume":    true,
+	"NodeExpandVolume":       true,
+}
+
+// sloBucket counts the operations of one bucket of the rolling window.
+type sloBucket struct {
+	// epoch identifies the time slot of the bucket, buckets of older slots are stale
+	epoch  int64
+	total  int
+	within int
+}
+
+// sloTracker computes the fraction of volume operations completing successfully within a
+// threshold over a rolling window, and logs a summary once per window. The zero value is
+// disabled.
+type sloTracker struct {
+	threshold time.Duration
+	window    time.Duration
+	// buckets of the rolling window per operation
+	ops    map[string]*[sloBuckets]sloBucket
+	cancel context.CancelFunc
+	sync.Mutex
+}
+
+// sloSummary is the outcome of an operation over the rolling window.
+type sloSummary struct {
+	operation string
+	total     int
+	within    int
+}
+
+// ratio returns the fraction of operations within the threshold.
+func (s sloSummary) ratio() float64 {
+	if s.total == 0 {
+		return 1
+	}
+	return float64(s.within) / float64(s.total)
+}
+
+// WithProvisioningSLO tracks the fraction of volume operations, e.g. CreateVolume or
+// NodePublishVolume, completing successfully within the threshold. The ratio over the rolling
+// window is exported per operation and for all operations as the panfs_csi_slo_success_ratio
+// metric, and logged once per window.
+//
+// Parameters:
+//
+//	threshold - The duration within which an operation must succeed, 0 disables the tracking.
+//	window    - The rolling window of the ratio and the interval of the summary log.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithProvisioningSLO(threshold, window time.Duration) Option {
+	return func(d *Driver) {
+		d.slo.threshold = threshold
+		d.slo.window = window
+	}
+}
+
+// enabled reports whether the SLO is tracked.
+func (t *sloTracker) enabled() bool {
+	return t.threshold > 0 && t.window > 0
+}
+
+// unaryInterceptor records the outcome and duration of volume operations.
+func (t *sloTracker) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !t.enabled() {
+		return handler(ctx, req)
+	}
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	t.record(path.Base(info.FullMethod), time.Since(start), err, time.Now())
+	return resp, err
+}
+
+// record counts an operation which ended at the given time.
+//
+// Parameters:
+//
+//	operation - The RPC name, e.g. "CreateVolume". Other RPCs are ignored.
+//	duration  - The duration of the operation.
+//	err       - The error of the operation.
+//	now       - The time the operation ended.
+func (t *sloTracker) record(operation string, duration time.Duration, err error, now time.Time) {
+	if !sloOperations[operation] {
+		return
+	}
+
+	result := "within"
+	switch {
+	case err != nil:
+		result = "failed"
+	case duration > t.threshold:
+		result = "exceeded"
+	}
+	metrics.SLOOperations.WithLabelValues(operation, result).Inc()
+
+	t.Lock()
+	defer t.Unlock()
+	if t.ops == nil {
+		t.ops = make(map[string]*[sloBuckets]sloBucket)
+	}
+	buckets, ok := t.ops[operation]
+	if !ok {
+		buckets = &[sloBuckets]sloBucket{}
+		t.ops[operation] = buckets
+	}
+
+	epoch := t.epoch(now)
+	bucket := &buckets[epoch%sloBuckets]
+	if bucket.epoch != epoch {
+		*bucket = sloBucket{epoch: epoch}
+	}
+	bucket.total++
+	if result == "within" {
+		bucket.within++
+	}
+}
+
+// epoch returns the time slot of a bucket of the rolling window.
+func (t *sloTracker) epoch(now time.Time) int64 {
+	width := max(int64(t.window/sloBuckets), 1)
+	return now.UnixNano() / width
+}
+
+// summaries returns the outcome of every operation over the rolling window ending now,
+// sorted by operation, followed by the outcome of all operations.
+//
+// Parameters:
+//
+//	now - The end of the window.
+//
+// Returns:
+//
+//	[]sloSummary - The summaries of the operations seen in the window and of all operations.
+func (t *sloTracker) summaries(now time.Time) []sloSummary {
+	t.Lock()
+	defer t.Unlock()
+
+	oldest := t.epoch(now) - sloBuckets + 1
+	all := sloSummary{operation: sloAllOperations}
+	var summaries []sloSummary
+	for operation, buckets := range t.ops {
+		summary := sloSummary{operation: operation}
+		for _, bucket := range buckets {
+			if bucket.epoch >= oldest {
+				summary.total += bucket.total
+				summary.within += bucket.within
+			}
+		}
+		if summary.total == 0 {
+			continue
+		}
+		summaries = append(summaries, summary)
+		all.total += summary.total
+		all.within += summary.within
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].operation < summaries[j].operation })
+	return append(summaries, all)
+}
+
+// updateMetrics sets the ratio metrics to the rolling window ending now. Operations which
+// were not called in the window have no ratio.
+func (t *sloTracker) updateMetrics(now time.Time) []sloSummary {
+	summaries := t.summaries(now)
+
+	metrics.SLORatio.Reset()
+	for _, summary := range summaries {
+		if summary.total > 0 {
+			metrics.SLORatio.WithLabelValues(summary.operation).Set(summary.ratio())
+		}
+	}
+	return summaries
+}
+
+// logSummary logs the ratios of the rolling window ending now.
+func (t *sloTracker) logSummary(now time.Time, log klog.Logger) {
+	summaries := t.updateMetrics(now)
+	all := summaries[len(summaries)-1]
+
+	operations := make(map[string]float64, len(summaries)-1)
+	for _, summary := range summaries[:len(summaries)-1] {
+		operations[summary.operation] = summary.ratio()
+	}
+
+	log.Info("provisioning SLO summary",
+		"window", t.window.String(),
+		"threshold", t.threshold.String(),
+		"operations_total", all.total,
+		"operations_within_threshold", all.within,
+		"success_ratio", all.ratio(),
+		"success_ratio_by_operation", operations,
+	)
+}
+
+// startSLOReporter refreshes the ratio metrics as the window rolls and logs a summary once
+// per window, if enabled and not started yet.
+func (d *Driver) startSLOReporter() {
+	if !d.slo.enabled() {
+		return
+	}
+
+	d.slo.Lock()
+	defer d.slo.Unlock()
+	if d.slo.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d.slo.cancel = cancel
+
+	log := d.log.WithValues("component", "slo")
+	go func() {
+		refresh := time.NewTicker(max(d.slo.window/sloBuckets, time.Millisecond))
+		defer refresh.Stop()
+		summary := time.NewTicker(d.slo.window)
+		defer summary.Stop()
+
+		for {
+			select {
+			case now := <-refresh.C:
+				d.slo.updateMetrics(now)
+			case now := <-summary.C:
+				d.slo.logSummary(now, log)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// stopSLOReporter stops refreshing and logging the SLO.
+func (d *Driver) stopSLOReporter() {
+	d.slo.Lock()
+	defer d.slo.Unlock()
+	if d.slo.cancel != nil {
+		d.slo.cancel()
+		d.slo.cancel = nil
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// TestSLOTracker verifies the success-within-threshold ratios of volume operations over the
+// rolling window.
+func TestSLOTracker(t *testing.T) {
+	tracker := &sloTracker{threshold: time.Second, window: time.Hour}
+	now := time.Unix(1700000000, 0)
+	errRealm := errors.New("realm unavailable")
+
+	tracker.record("CreateVolume", 100*time.Millisecond, nil, now)
+	tracker.record("CreateVolume", 2*time.Second, nil, now)
+	tracker.record("CreateVolume", 100*time.Millisecond, errRealm, now.Add(30*time.Minute))
+	tracker.record("DeleteVolume", 100*time.Millisecond, nil, now.Add(30*time.Minute))
+	tracker.record("GetCapacity", 100*time.Millisecond, nil, now)
+
+	assert.Equal(t, []sloSummary{
+		{operation: "CreateVolume", total: 3, within: 1},
+		{operation: "DeleteVolume", total: 1, within: 1},
+		{operation: sloAllOperations, total: 4, within: 2},
+	}, tracker.summaries(now.Add(30*time.Minute)))
+
+	tracker.updateMetrics(now.Add(30 * time.Minute))
+	assert.InDelta(t, 1.0/3, testutil.ToFloat64(metrics.SLORatio.WithLabelValues("CreateVolume")), 1e-9)
+	assert.Equal(t, 0.5, testutil.ToFloat64(metrics.SLORatio.WithLabelValues(sloAllOperations)))
+
+	// the operations of the first bucket leave the window
+	assert.Equal(t, []sloSummary{
+		{operation: "CreateVolume", total: 1, within: 0},
+		{operation: "DeleteVolume", total: 1, within: 1},
+		{operation: sloAllOperations, total: 2, within: 1},
+	}, tracker.summaries(now.Add(time.Hour)))
+
+	// a bucket reused for a later slot starts empty
+	tracker.record("DeleteVolume", 100*time.Millisecond, nil, now.Add(90*time.Minute))
+	assert.Equal(t, []sloSummary{
+		{operation: "DeleteVolume", total: 1, within: 1},
+		{operation: sloAllOperations, total: 1, within: 1},
+	}, tracker.summaries(now.Add(90*time.Minute)))
+
+	assert.Equal(t, []sloSummary{{operation: sloAllOperations}}, tracker.summaries(now.Add(3*time.Hour)))
+	tracker.updateMetrics(now.Add(3 * time.Hour))
+	assert.Equal(t, 0, testutil.CollectAndCount(metrics.SLORatio))
+}
+
+// TestSLOTrackerInterceptor verifies that the interceptor records volume operations only
+// while the SLO is enabled, and that the summary is logged.
+func TestSLOTrackerInterceptor(t *testing.T) {
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+	info := &grpc.UnaryServerInfo{FullMethod: "/csi.v1.Controller/ControllerExpandVolume"}
+	within := metrics.SLOOperations.WithLabelValues("ControllerExpandVolume", "within")
+	before := testutil.ToFloat64(within)
+
+	disabled := &sloTracker{}
+	_, err := disabled.unaryInterceptor(t.Context(), nil, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, before, testutil.ToFloat64(within))
+
+	tracker := &sloTracker{threshold: time.Minute, window: time.Hour}
+	resp, err := tracker.unaryInterceptor(t.Context(), nil, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+	assert.Equal(t, before+1, testutil.ToFloat64(within))
+
+	var lines []string
+	log := funcr.New(func(_, args string) { lines = append(lines, args) }, funcr.Options{})
+	tracker.logSummary(time.Now(), log)
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"msg"="provisioning SLO summary"`)
+	assert.Contains(t, lines[0], `"operations_total"=1`)
+	assert.Contains(t, lines[0], `"success_ratio_by_operation"={"ControllerExpandVolume"=1}`)
+}
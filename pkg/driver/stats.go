@@ -0,0 +1,115 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/klog/v2"
+)
+
+// DefaultSlowRPCThreshold is the default duration after which an RPC is reported as slow.
+const DefaultSlowRPCThreshold = 30 * time.Second
+
+// rpcMetricsInterceptor counts every unary RPC by method and status code and observes how
+// long its handler took.
+func rpcMetricsInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	metrics.RPCDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+	metrics.RPCs.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+	return resp, err
+}
+
+// rpcStatsKey is the context key under which per-RPC state is stored by slowRPCHandler.
+type rpcStatsKey struct{}
+
+// rpcStats holds the state collected for a single RPC.
+type rpcStats struct {
+	method  string
+	request proto.Message
+}
+
+// slowRPCHandler is a gRPC stats handler reporting RPCs which take longer than a threshold.
+// Slow RPCs are logged with a redacted summary of the request and counted per method.
+type slowRPCHandler struct {
+	threshold time.Duration
+	log       klog.Logger
+}
+
+// newSlowRPCHandler creates a stats handler reporting RPCs slower than threshold.
+//
+// Parameters:
+//
+//	threshold - The duration after which an RPC is reported as slow.
+//	log       - The logger used for warnings.
+//
+// Returns:
+//
+//	*slowRPCHandler - The initialized handler.
+func newSlowRPCHandler(threshold time.Duration, log klog.Logger) *slowRPCHandler {
+	return &slowRPCHandler{
+		threshold: threshold,
+		log:       log.WithValues("component", "rpc-stats"),
+	}
+}
+
+// TagRPC attaches per-RPC state to the context.
+func (h *slowRPCHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, rpcStatsKey{}, &rpcStats{method: info.FullMethodName})
+}
+
+// HandleRPC records the request payload and reports the RPC once it ends if it was slow.
+func (h *slowRPCHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	st, ok := ctx.Value(rpcStatsKey{}).(*rpcStats)
+	if !ok {
+		return
+	}
+
+	switch s := s.(type) {
+	case *stats.InPayload:
+		if msg, ok := s.Payload.(proto.Message); ok {
+			st.request = msg
+		}
+	case *stats.End:
+		duration := s.EndTime.Sub(s.BeginTime)
+		if duration < h.threshold {
+			return
+		}
+
+		metrics.SlowRPCs.WithLabelValues(st.method).Inc()
+		h.log.Info("WARNING: slow RPC detected",
+			"method", st.method,
+			"duration", duration.String(),
+			"threshold", h.threshold.String(),
+			"request", redactedString(st.request),
+			"error", s.Error,
+		)
+	}
+}
+
+// TagConn is a no-op; connection level stats are not tracked.
+func (h *slowRPCHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn is a no-op; connection level stats are not tracked.
+func (h *slowRPCHandler) HandleConn(context.Context, stats.ConnStats) {}
@@ -0,0 +1,95 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// TestRedactedString verifies that secret fields are stripped from request summaries.
+func TestRedactedString(t *testing.T) {
+	req := &csi.CreateVolumeRequest{
+		Name:       validVolumeName,
+		Parameters: map[string]string{"key": "value"},
+		Secrets:    defaultSecrets,
+	}
+
+	out := redactedString(req)
+
+	assert.Contains(t, out, validVolumeName)
+	assert.Contains(t, out, "value")
+	assert.Contains(t, out, redactedValue)
+	assert.NotContains(t, out, `"`+defaultSecrets["password"]+`"`)
+	assert.NotContains(t, out, defaultSecrets["kmip_config_data"])
+
+	// the original request must be left untouched
+	assert.Equal(t, "pass", req.Secrets["password"])
+	assert.Equal(t, "", redactedString(nil))
+}
+
+// TestSlowRPCHandler verifies that only RPCs exceeding the threshold are counted.
+func TestSlowRPCHandler(t *testing.T) {
+	h := newSlowRPCHandler(time.Second, klog.Background())
+	method := "/csi.v1.Controller/TestSlowRPCHandler"
+
+	run := func(duration time.Duration) {
+		ctx := h.TagRPC(t.Context(), &stats.RPCTagInfo{FullMethodName: method})
+		h.HandleRPC(ctx, &stats.InPayload{Payload: &csi.DeleteVolumeRequest{VolumeId: validVolumeName, Secrets: defaultSecrets}})
+		begin := time.Now()
+		h.HandleRPC(ctx, &stats.End{BeginTime: begin, EndTime: begin.Add(duration)})
+	}
+
+	run(10 * time.Millisecond)
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.SlowRPCs.WithLabelValues(method)))
+
+	run(2 * time.Second)
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.SlowRPCs.WithLabelValues(method)))
+
+	// RPCs without tagged state are ignored
+	h.HandleRPC(t.Context(), &stats.End{})
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.SlowRPCs.WithLabelValues(method)))
+}
+
+// TestRPCMetricsInterceptor verifies that RPCs are counted by status code and their
+// durations observed.
+func TestRPCMetricsInterceptor(t *testing.T) {
+	method := "/csi.v1.Controller/TestRPCMetricsInterceptor"
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	_, err := rpcMetricsInterceptor(t.Context(), nil, info, func(context.Context, any) (any, error) {
+		return &csi.DeleteVolumeResponse{}, nil
+	})
+	assert.NoError(t, err)
+	_, err = rpcMetricsInterceptor(t.Context(), nil, info, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.NotFound, VolumeNotFoundErrorStr)
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.RPCs.WithLabelValues(method, "OK")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.RPCs.WithLabelValues(method, "NotFound")))
+	assert.GreaterOrEqual(t, testutil.CollectAndCount(metrics.RPCDuration), 1)
+}
@@ -0,0 +1,246 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// StringParameterLimits are the maximum lengths of the free-text volume parameters accepted
+// by a realm. Lengths are counted in bytes, so multibyte UTF-8 characters take several bytes
+// of the limit.
+type StringParameterLimits struct {
+	// Description is the maximum length of the volume description, including the tags
+	// appended by the driver.
+	Description int
+	// User is the maximum length of the name of the owner of the volume.
+	User int
+	// Group is the maximum length of the name of the group of the volume.
+	Group int
+}
+
+// realmStringParameterLimits are the limits of the realm versions, newest first. A realm
+// has the limits of the first entry it is not older than.
+var realmStringParameterLimits = []struct {
+	minVersion string
+	limits     StringParameterLimits
+}{
+	{minVersion: "10.0", limits: StringParameterLimits{Description: 255, User: 64, Group: 64}},
+	{minVersion: "0", limits: StringParameterLimits{Description: 127, User: 32, Group: 32}},
+}
+
+// descriptionTokenLength is the length of the idempotency token the SSH realm client may
+// append to descriptions, a space, "csi-token:" and 16 hex digits.
+const descriptionTokenLength = 1 + len("csi-token:") + 16
+
+// realmVersionPattern matches the realm versions of the realmVersion secret, e.g. "10.1" or
+// "11.0.0.a-1234567.1".
+var realmVersionPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)*([.-][0-9A-Za-z.-]*)?$`)
+
+// stringParameterLimits returns the limits of a realm version. Realms of unknown version
+// have the limits of the newest realms, so only values no realm accepts are refused.
+//
+// Parameters:
+//
+//	realmVersion - The version of the realm from the realmVersion secret, may be empty.
+//
+// Returns:
+//
+//	StringParameterLimits - The limits of the realm.
+//	error                 - Error if the version is invalid.
+func stringParameterLimits(realmVersion string) (StringParameterLimits, error) {
+	if realmVersion == "" {
+		return realmStringParameterLimits[0].limits, nil
+	}
+	if !realmVersionPattern.MatchString(realmVersion) {
+		return StringParameterLimits{}, fmt.Errorf("invalid realm version %q in secret %s: expected a version like 10.1",
+			realmVersion, utils.RealmConnectionContext.RealmVersion)
+	}
+	for _, entry := range realmStringParameterLimits {
+		if compareVersions(realmVersion, entry.minVersion) >= 0 {
+			return entry.limits, nil
+		}
+	}
+	return realmStringParameterLimits[len(realmStringParameterLimits)-1].limits, nil
+}
+
+// validateStringParameters validates the description, user and group parameters. Values must
+// be valid UTF-8 without characters breaking the quoting of realm commands, and must not
+// exceed the limits of the newest realms. Descriptions exceeding the limits are accepted if
+// the truncateDescription parameter is set.
+//
+// Parameters:
+//
+//	parameters - Map of volume parameters to validate.
+//
+// Returns:
+//
+//	error - Error if a value is invalid or too long for any realm.
+func validateStringParameters(parameters map[string]string) error {
+	truncate, err := truncateDescription(parameters)
+	if err != nil {
+		return err
+	}
+
+	maxLimits := realmStringParameterLimits[0].limits
+	for name, limit := range map[string]int{
+		"description": maxLimits.Description - descriptionTokenLength,
+		"user":        maxLimits.User,
+		"group":       maxLimits.Group,
+	} {
+		key := utils.VolumeParameters.GetSCKey(name)
+		value, exist := parameters[key]
+		if !exist {
+			continue
+		}
+		if err := validateParameterText(key, value); err != nil {
+			return err
+		}
+		if len(value) > limit && !(name == "description" && truncate) {
+			return fmt.Errorf("%s must be at most %d bytes, got %d", key, limit, len(value))
+		}
+	}
+	return nil
+}
+
+// validateParameterText checks that a free-text parameter is valid UTF-8 without quotes,
+// backslashes, shell expansions or control characters, which the realm command would
+// interpret instead of storing.
+//
+// Parameters:
+//
+//	key   - The storage class key of the parameter.
+//	value - The value of the parameter.
+//
+// Returns:
+//
+//	error - Error if the value contains invalid characters.
+func validateParameterText(key, value string) error {
+	if !utf8.ValidString(value) {
+		return fmt.Errorf("%s must be valid UTF-8", key)
+	}
+	for _, r := range value {
+		if strings.ContainsRune("\"\\$`", r) || unicode.IsControl(r) {
+			return fmt.Errorf("%s must not contain quotes, backslashes, '$', '`' or control characters, got %q", key, value)
+		}
+	}
+	return nil
+}
+
+// truncateDescription returns the truncateDescription parameter.
+func truncateDescription(parameters map[string]string) (bool, error) {
+	key := utils.VolumeParameters.GetSCKey("truncateDescription")
+	value, exist := parameters[key]
+	if !exist {
+		return false, nil
+	}
+	truncate, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be 'true' or 'false'", key)
+	}
+	return truncate, nil
+}
+
+// limitStringParameters applies the limits of the realm to the description, user and group
+// parameters. Descriptions exceeding the limit are truncated at a character boundary if the
+// truncateDescription parameter is set. User and group names are never truncated, as the
+// shortened name would refer to another user or group.
+//
+// Parameters:
+//
+//	parameters   - The validated volume parameters.
+//	realmVersion - The version of the realm from the realmVersion secret, may be empty.
+//	reserved     - The bytes of the description reserved for the tags appended by the driver.
+//
+// Returns:
+//
+//	map[string]string - The parameters, with the description truncated if needed.
+//	error             - Error if the realm version is invalid or a value exceeds the limits.
+func limitStringParameters(parameters map[string]string, realmVersion string, reserved int) (map[string]string, error) {
+	limits, err := stringParameterLimits(realmVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, limit := range map[string]int{"user": limits.User, "group": limits.Group} {
+		key := utils.VolumeParameters.GetSCKey(name)
+		if value := parameters[key]; len(value) > limit {
+			return nil, fmt.Errorf("%s must be at most %d bytes for realm version %q, got %d", key, limit, realmVersion, len(value))
+		}
+	}
+
+	key := utils.VolumeParameters.GetSCKey("description")
+	description := parameters[key]
+	limit := max(limits.Description-reserved, 0)
+	if len(description) <= limit {
+		return parameters, nil
+	}
+	// validated before
+	truncate, _ := truncateDescription(parameters)
+	if !truncate {
+		return nil, fmt.Errorf("%s must be at most %d bytes for realm version %q, got %d; set %s to truncate it",
+			key, limit, realmVersion, len(description), utils.VolumeParameters.GetSCKey("truncateDescription"))
+	}
+
+	limited := maps.Clone(parameters)
+	limited[key] = truncateUTF8(description, limit)
+	return limited, nil
+}
+
+// descriptionTagsLength returns the length of the protection tier tag appended to the
+// description of a volume.
+//
+// Parameters:
+//
+//	tier - The protection tier, may be empty.
+//
+// Returns:
+//
+//	int - The length of the tag including its separator, 0 without tier.
+func descriptionTagsLength(tier string) int {
+	if tier == "" {
+		return 0
+	}
+	return len(" " + utils.ProtectionTierTagPrefix + tier)
+}
+
+// truncateUTF8 truncates a string to at most n bytes without splitting a multibyte
+// character, and removes trailing whitespace.
+//
+// Parameters:
+//
+//	s - The string.
+//	n - The maximum length in bytes.
+//
+// Returns:
+//
+//	string - The truncated string.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return strings.TrimRightFunc(s[:n], unicode.IsSpace)
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestValidateStringParameters verifies the charset and length checks of the description,
+// user and group parameters.
+func TestValidateStringParameters(t *testing.T) {
+	descriptionKey := utils.VolumeParameters.GetSCKey("description")
+	userKey := utils.VolumeParameters.GetSCKey("user")
+	groupKey := utils.VolumeParameters.GetSCKey("group")
+	truncateKey := utils.VolumeParameters.GetSCKey("truncateDescription")
+
+	tests := []struct {
+		name    string
+		params  map[string]string
+		wantErr string
+	}{
+		{"ASCII", map[string]string{descriptionKey: "team data", userKey: "alice", groupKey: "1001"}, ""},
+		{"Multibyte", map[string]string{descriptionKey: "Daten für Team Ü – 数据", userKey: "jürgen"}, ""},
+		{"MaxDescription", map[string]string{descriptionKey: strings.Repeat("€", 76)}, ""},
+		{"LongDescription", map[string]string{descriptionKey: strings.Repeat("€", 77)}, "description must be at most 228 bytes, got 231"},
+		{"LongDescriptionTruncated", map[string]string{descriptionKey: strings.Repeat("€", 500), truncateKey: "true"}, ""},
+		{"LongUser", map[string]string{userKey: strings.Repeat("ü", 33)}, "user must be at most 64 bytes, got 66"},
+		{"LongGroupNotTruncated", map[string]string{groupKey: strings.Repeat("g", 65), truncateKey: "true"}, "group must be at most 64 bytes"},
+		{"InvalidUTF8", map[string]string{descriptionKey: "team \xff data"}, "description must be valid UTF-8"},
+		{"Quote", map[string]string{descriptionKey: `team "data"`}, "must not contain quotes"},
+		{"Backslash", map[string]string{userKey: `domain\alice`}, "must not contain quotes"},
+		{"CommandSubstitution", map[string]string{descriptionKey: "$(reboot)"}, "must not contain quotes"},
+		{"Backtick", map[string]string{groupKey: "`id`"}, "must not contain quotes"},
+		{"Newline", map[string]string{descriptionKey: "team\ndata"}, "must not contain quotes"},
+		{"InvalidTruncate", map[string]string{truncateKey: "yes"}, "truncateDescription must be 'true' or 'false'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateVolumeParameters(tt.params)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+// TestStringParameterLimits verifies that the limits depend on the realm version.
+func TestStringParameterLimits(t *testing.T) {
+	newest := StringParameterLimits{Description: 255, User: 64, Group: 64}
+	oldest := StringParameterLimits{Description: 127, User: 32, Group: 32}
+
+	for version, want := range map[string]StringParameterLimits{
+		"":                   newest,
+		"10.0":               newest,
+		"11.0.0.a-1234567.1": newest,
+		"9.3.2":              oldest,
+		"8":                  oldest,
+	} {
+		limits, err := stringParameterLimits(version)
+		require.NoError(t, err, version)
+		assert.Equal(t, want, limits, version)
+	}
+
+	_, err := stringParameterLimits("latest")
+	assert.ErrorContains(t, err, `invalid realm version "latest"`)
+}
+
+// TestLimitStringParameters verifies that descriptions are truncated at character boundaries
+// if requested, and that user and group names are never truncated.
+func TestLimitStringParameters(t *testing.T) {
+	descriptionKey := utils.VolumeParameters.GetSCKey("description")
+	userKey := utils.VolumeParameters.GetSCKey("user")
+	truncateKey := utils.VolumeParameters.GetSCKey("truncateDescription")
+
+	t.Run("WithinLimits", func(t *testing.T) {
+		params := map[string]string{descriptionKey: strings.Repeat("€", 33), userKey: "alice"}
+		limited, err := limitStringParameters(params, "9.3", descriptionTokenLength)
+		require.NoError(t, err)
+		assert.Equal(t, params, limited)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		params := map[string]string{descriptionKey: strings.Repeat("€", 34)}
+		_, err := limitStringParameters(params, "9.3", descriptionTokenLength)
+		assert.ErrorContains(t, err, `description must be at most 100 bytes for realm version "9.3", got 102; set panfs.csi.vdura.com/truncateDescription to truncate it`)
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		params := map[string]string{descriptionKey: strings.Repeat("€", 34), truncateKey: "true"}
+		limited, err := limitStringParameters(params, "9.3", descriptionTokenLength)
+		require.NoError(t, err)
+		// 100 bytes split the 34th character, which is dropped
+		assert.Equal(t, strings.Repeat("€", 33), limited[descriptionKey])
+		// the parameters of the request are left untouched
+		assert.Equal(t, strings.Repeat("€", 34), params[descriptionKey])
+	})
+
+	t.Run("TruncatedTrailingSpace", func(t *testing.T) {
+		params := map[string]string{descriptionKey: strings.Repeat("a", 95) + " über", truncateKey: "true"}
+		limited, err := limitStringParameters(params, "9.3", descriptionTokenLength)
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("a", 95)+" übe", limited[descriptionKey])
+
+		limited, err = limitStringParameters(params, "9.3", descriptionTokenLength+4)
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("a", 95), limited[descriptionKey])
+	})
+
+	t.Run("UserNotTruncated", func(t *testing.T) {
+		params := map[string]string{userKey: strings.Repeat("ü", 17), truncateKey: "true"}
+		_, err := limitStringParameters(params, "9.3", descriptionTokenLength)
+		assert.ErrorContains(t, err, `user must be at most 32 bytes for realm version "9.3", got 34`)
+	})
+
+	t.Run("InvalidVersion", func(t *testing.T) {
+		_, err := limitStringParameters(map[string]string{}, "v10", descriptionTokenLength)
+		assert.ErrorContains(t, err, "invalid realm version")
+	})
+}
+
+// TestCreateVolumeStringParameterLimits verifies that CreateVolume applies the limits of
+// the realm version of the secrets, keeping room for the protection tier tag.
+func TestCreateVolumeStringParameterLimits(t *testing.T) {
+	descriptionKey := utils.VolumeParameters.GetSCKey("description")
+	tierKey := utils.VolumeParameters.GetSCKey("protectionTier")
+	truncateKey := utils.VolumeParameters.GetSCKey("truncateDescription")
+
+	secrets := maps.Clone(defaultSecrets)
+	secrets[utils.RealmConnectionContext.RealmVersion] = "9.3"
+	request := func(params map[string]string) *csi.CreateVolumeRequest {
+		return &csi.CreateVolumeRequest{
+			Name:          validVolumeName,
+			CapacityRange: &csi.CapacityRange{RequiredBytes: 1 << 30},
+			Parameters:    params,
+			Secrets:       secrets,
+			VolumeCapabilities: []*csi.VolumeCapability{
+				{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}},
+			},
+		}
+	}
+
+	t.Run("Truncated", func(t *testing.T) {
+		d, pancliMock := newSnapshotTestDriver(t)
+		pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), secrets).
+			DoAndReturn(func(_ context.Context, _ string, params pancli.VolumeCreateParams, _ map[string]string) (*utils.Volume, error) {
+				// 127 bytes less the token and " csi-tier:gold" leave 86 bytes, 28 characters
+				assert.Equal(t, strings.Repeat("€", 28)+" csi-tier:gold", params[descriptionKey])
+				assert.LessOrEqual(t, len(params[descriptionKey])+descriptionTokenLength, 127)
+				return &utils.Volume{Name: utils.VolumeName(validVolumeName), Soft: 1, Description: params[descriptionKey]}, nil
+			})
+
+		_, err := d.CreateVolume(t.Context(), request(map[string]string{
+			descriptionKey: strings.Repeat("€", 40),
+			tierKey:        "gold",
+			truncateKey:    "true",
+		}))
+		require.NoError(t, err)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		d, _ := newSnapshotTestDriver(t)
+		_, err := d.CreateVolume(t.Context(), request(map[string]string{descriptionKey: strings.Repeat("€", 40)}))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.ErrorContains(t, err, `realm version "9.3"`)
+	})
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package driver
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// surfaceSnapshotFile holds the checked in snapshot of the CSI surface advertised by the driver.
+const surfaceSnapshotFile = "testdata/csi_surface.json"
+
+var updateSurface = flag.Bool("update-surface", false, "rewrite "+surfaceSnapshotFile+" from the current driver")
+
+// csiSurface is the CSI surface downstream automation depends on.
+type csiSurface struct {
+	// Capabilities lists the advertised plugin, controller and node capabilities.
+	Capabilities []string `json:"capabilities"`
+	// Parameters lists the supported storage class parameters.
+	Parameters []string `json:"parameters"`
+	// ErrorCodes maps RPC names to the gRPC codes returned for each realm error.
+	ErrorCodes map[string]map[string]string `json:"errorCodes"`
+}
+
+// surfaceRealmErrors names the realm errors the error code mapping is recorded for.
+var surfaceRealmErrors = map[string]error{
+	"already_exists":   pancli.ErrorAlreadyExist,
+	"not_found":        pancli.ErrorNotFound,
+	"invalid_argument": pancli.ErrorInvalidArgument,
+	"unauthenticated":  pancli.ErrorUnauthenticated,
+	"unavailable":      pancli.ErrorUnavailable,
+	"internal":         pancli.ErrorInternal,
+}
+
+// surfaceRPCs calls each controller RPC reaching the realm with a valid request.
+var surfaceRPCs = map[string]func(t *testing.T, d *Driver) error{
+	"CreateVolume": func(t *testing.T, d *Driver) error {
+		_, err := d.CreateVolume(t.Context(), &csi.CreateVolumeRequest{
+			Name:               validVolumeName,
+			CapacityRange:      &csi.CapacityRange{RequiredBytes: GB10Bytes},
+			Secrets:            defaultSecrets,
+			VolumeCapabilities: []*csi.VolumeCapability{{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}}},
+		})
+		return err
+	},
+	"DeleteVolume": func(t *testing.T, d *Driver) error {
+		_, err := d.DeleteVolume(t.Context(), &csi.DeleteVolumeRequest{VolumeId: validVolumeName, Secrets: defaultSecrets})
+		return err
+	},
+	"ValidateVolumeCapabilities": func(t *testing.T, d *Driver) error {
+		_, err := d.ValidateVolumeCapabilities(t.Context(), &csi.ValidateVolumeCapabilitiesRequest{
+			VolumeId:           validVolumeName,
+			Secrets:            defaultSecrets,
+			VolumeCapabilities: []*csi.VolumeCapability{{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}}},
+		})
+		return err
+	},
+	"ControllerExpandVolume": func(t *testing.T, d *Driver) error {
+		_, err := d.ControllerExpandVolume(t.Context(), &csi.ControllerExpandVolumeRequest{
+			VolumeId:      validVolumeName,
+			CapacityRange: &csi.CapacityRange{RequiredBytes: GB10Bytes},
+			Secrets:       defaultSecrets,
+		})
+		return err
+	},
+	"ControllerModifyVolume": func(t *testing.T, d *Driver) error {
+		_, err := d.ControllerModifyVolume(t.Context(), &csi.ControllerModifyVolumeRequest{
+			VolumeId:          validVolumeName,
+			MutableParameters: map[string]string{utils.VolumeParameters.GetSCKey("efsa"): string(utils.EFSAModeRetry)},
+			Secrets:           defaultSecrets,
+		})
+		return err
+	},
+}
+
+// currentSurface collects the CSI surface of the driver.
+func currentSurface(t *testing.T) csiSurface {
+	surface := csiSurface{
+		Capabilities: advertisedCapabilities(t, &Driver{log: klog.Background()}),
+		ErrorCodes:   map[string]map[string]string{},
+	}
+	sort.Strings(surface.Capabilities)
+
+	for key := range utils.VolumeParameters {
+		surface.Parameters = append(surface.Parameters, utils.VolumeParameters.GetSCKey(key))
+	}
+	sort.Strings(surface.Parameters)
+
+	for rpc, call := range surfaceRPCs {
+		surface.ErrorCodes[rpc] = map[string]string{}
+		for name, realmErr := range surfaceRealmErrors {
+			ctrl := gomock.NewController(t)
+			panfs := mock.NewMockStorageProviderClient(ctrl)
+			panfs.EXPECT().CreateVolume(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, realmErr).AnyTimes()
+			panfs.EXPECT().GetVolume(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, realmErr).AnyTimes()
+			panfs.EXPECT().DeleteVolume(gomock.Any(), gomock.Any(), gomock.Any()).Return(realmErr).AnyTimes()
+			panfs.EXPECT().ExpandVolume(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(realmErr).AnyTimes()
+			panfs.EXPECT().ModifyVolume(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(realmErr).AnyTimes()
+
+			d := &Driver{Name: DefaultDriverName, panfs: panfs, log: klog.Background()}
+			surface.ErrorCodes[rpc][name] = status.Code(call(t, d)).String()
+		}
+	}
+
+	return surface
+}
+
+// TestCSISurfaceSnapshot verifies that the advertised capabilities, supported parameters and
+// the mapping of realm errors to gRPC codes match the checked in snapshot. After an intended
+// change, regenerate the snapshot with:
+//
+//	go test ./pkg/driver -run TestCSISurfaceSnapshot -update-surface
+func TestCSISurfaceSnapshot(t *testing.T) {
+	current, err := json.MarshalIndent(currentSurface(t), "", "  ")
+	require.NoError(t, err)
+	current = append(current, '\n')
+
+	if *updateSurface {
+		require.NoError(t, os.WriteFile(surfaceSnapshotFile, current, 0o644))
+	}
+
+	snapshot, err := os.ReadFile(surfaceSnapshotFile)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(snapshot), string(current),
+		"the CSI surface changed, update %s with -update-surface if the change is intended", surfaceSnapshotFile)
+}
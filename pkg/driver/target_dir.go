@@ -0,0 +1,247 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// TargetDirPermissions are the mode and ownership of publish target directories created
+// by the node plugin.
+type TargetDirPermissions struct {
+	// Mode is the permission mode of the directory, including setuid, setgid and sticky bits.
+	Mode os.FileMode
+	// UID is the owner of the directory, -1 keeps the owner of the node plugin.
+	UID int
+	// GID is the group of the directory, -1 keeps the group of the node plugin.
+	GID int
+}
+
+// DefaultTargetDirPermissions are the permissions of target directories unless configured.
+var DefaultTargetDirPermissions = TargetDirPermissions{Mode: 0o755, UID: -1, GID: -1}
+
+// osChown changes the ownership of a file, replaced in tests.
+var osChown = os.Chown
+
+// WithTargetDirPermissions sets the mode and ownership of publish target directories which do
+// not exist yet, e.g. for workloads running as non-root users. Existing target directories
+// are left untouched. The targetDirMode, targetDirUID and targetDirGID storage class
+// parameters override these settings per volume.
+//
+// Parameters:
+//
+//	perms - The default permissions of created target directories.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithTargetDirPermissions(perms TargetDirPermissions) Option {
+	return func(d *Driver) {
+		d.targetDirPermissions = &perms
+	}
+}
+
+// NewTargetDirPermissions validates and returns the permissions of target directories,
+// e.g. as configured by command line flags.
+//
+// Parameters:
+//
+//	mode - The octal mode, e.g. "0755".
+//	uid  - The owner of the directories, -1 keeps the owner of the node plugin.
+//	gid  - The group of the directories, -1 keeps the group of the node plugin.
+//
+// Returns:
+//
+//	TargetDirPermissions - The permissions.
+//	error                - Error if the mode or one of the ids is invalid.
+func NewTargetDirPermissions(mode string, uid, gid int) (TargetDirPermissions, error) {
+	fileMode, err := ParseTargetDirMode(mode)
+	if err != nil {
+		return TargetDirPermissions{}, err
+	}
+	for _, id := range []int{uid, gid} {
+		if _, err := ParseTargetDirID(strconv.Itoa(id)); err != nil {
+			return TargetDirPermissions{}, err
+		}
+	}
+	return TargetDirPermissions{Mode: fileMode, UID: uid, GID: gid}, nil
+}
+
+// ParseTargetDirMode parses an octal permission mode of target directories, e.g. "0775"
+// or "2770".
+//
+// Parameters:
+//
+//	value - The octal mode.
+//
+// Returns:
+//
+//	os.FileMode - The mode, including setuid, setgid and sticky bits.
+//	error       - Error if the value is not an octal mode up to 7777.
+func ParseTargetDirMode(value string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if err != nil || mode > 0o7777 {
+		return 0, fmt.Errorf("invalid target directory mode %q: must be an octal mode up to 7777", value)
+	}
+
+	fileMode := os.FileMode(mode & 0o777)
+	if mode&0o4000 != 0 {
+		fileMode |= os.ModeSetuid
+	}
+	if mode&0o2000 != 0 {
+		fileMode |= os.ModeSetgid
+	}
+	if mode&0o1000 != 0 {
+		fileMode |= os.ModeSticky
+	}
+	return fileMode, nil
+}
+
+// ParseTargetDirID parses the owner or group id of target directories.
+//
+// Parameters:
+//
+//	value - The numeric id, -1 keeps the id of the node plugin.
+//
+// Returns:
+//
+//	int   - The id.
+//	error - Error if the value is not an integer between -1 and 2147483647.
+func ParseTargetDirID(value string) (int, error) {
+	id, err := strconv.Atoi(value)
+	if err != nil || id < -1 || id > math.MaxInt32 {
+		return 0, fmt.Errorf("invalid target directory owner %q: must be a numeric id or -1", value)
+	}
+	return id, nil
+}
+
+// publishTargetDirPermissions returns the permissions of the target directory of a volume.
+func (d *Driver) publishTargetDirPermissions(volumeContext map[string]string) (TargetDirPermissions, error) {
+	perms := DefaultTargetDirPermissions
+	if d.targetDirPermissions != nil {
+		perms = *d.targetDirPermissions
+	}
+	return targetDirPermissions(perms, volumeContext)
+}
+
+// validateTargetDirParameters validates the target directory storage class parameters.
+func validateTargetDirParameters(parameters map[string]string) error {
+	_, err := targetDirPermissions(DefaultTargetDirPermissions, parameters)
+	return err
+}
+
+// targetDirPermissions returns the permissions with the overrides of the volume context applied.
+//
+// Parameters:
+//
+//	perms         - The default permissions.
+//	volumeContext - The volume context of the volume.
+//
+// Returns:
+//
+//	TargetDirPermissions - The permissions of the target directory.
+//	error                - Error if an override is invalid.
+func targetDirPermissions(perms TargetDirPermissions, volumeContext map[string]string) (TargetDirPermissions, error) {
+	var errs []error
+
+	if value, ok := volumeContext[utils.VolumeParameters.GetSCKey("targetDirMode")]; ok {
+		mode, err := ParseTargetDirMode(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", utils.VolumeParameters.GetSCKey("targetDirMode"), err))
+		}
+		perms.Mode = mode
+	}
+	if value, ok := volumeContext[utils.VolumeParameters.GetSCKey("targetDirUID")]; ok {
+		uid, err := ParseTargetDirID(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", utils.VolumeParameters.GetSCKey("targetDirUID"), err))
+		}
+		perms.UID = uid
+	}
+	if value, ok := volumeContext[utils.VolumeParameters.GetSCKey("targetDirGID")]; ok {
+		gid, err := ParseTargetDirID(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", utils.VolumeParameters.GetSCKey("targetDirGID"), err))
+		}
+		perms.GID = gid
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return TargetDirPermissions{}, err
+	}
+	return perms, nil
+}
+
+// validTargetPath validates a target or staging path of a node request and returns it
+// cleaned, so locks, mounts and metrics of the same target use the same path however kubelet
+// formats it.
+//
+// Parameters:
+//
+//	name - The name of the path in error messages, e.g. "Target Path".
+//	path - The path of the request.
+//
+// Returns:
+//
+//	string - The cleaned absolute path.
+//	error  - Error if the path is empty or not an absolute path.
+func validTargetPath(name, path string) (string, error) {
+	cleaned, err := utils.ValidateAbsolutePath(path)
+	if errors.Is(err, utils.ErrEmptyPath) {
+		return "", fmt.Errorf("%s must be provided", name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s is invalid: %w", name, err)
+	}
+	return cleaned, nil
+}
+
+// prepareTargetDir creates the target directory with the given permissions if it does not
+// exist. The mode is applied explicitly, so it is not restricted by the umask of the node
+// plugin. Existing directories are left untouched.
+//
+// Parameters:
+//
+//	path  - The target path.
+//	perms - The permissions of the created directory.
+//
+// Returns:
+//
+//	error - Error if the directory cannot be created or its permissions cannot be set.
+func prepareTargetDir(path string, perms TargetDirPermissions) error {
+	if _, err := os.Stat(path); err == nil || !os.IsNotExist(err) {
+		// existing targets, or targets which cannot be checked, are left to the mounter
+		return nil
+	}
+
+	if err := osMkdirAll(path, perms.Mode.Perm()); err != nil {
+		return fmt.Errorf("failed to create target directory: %w", err)
+	}
+	if err := osChmod(path, perms.Mode); err != nil {
+		return fmt.Errorf("failed to set mode %s of target directory: %w", perms.Mode, err)
+	}
+	if perms.UID != -1 || perms.GID != -1 {
+		if err := osChown(path, perms.UID, perms.GID); err != nil {
+			return fmt.Errorf("failed to set owner %d:%d of target directory: %w", perms.UID, perms.GID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// TestParseTargetDirMode verifies the parsing of octal target directory modes.
+func TestParseTargetDirMode(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    os.FileMode
+		wantErr bool
+	}{
+		{value: "0755", want: 0o755},
+		{value: "775", want: 0o775},
+		{value: "2770", want: os.ModeSetgid | 0o770},
+		{value: "1777", want: os.ModeSticky | 0o777},
+		{value: "4700", want: os.ModeSetuid | 0o700},
+		{value: "0", want: 0},
+		{value: "10000", wantErr: true},
+		{value: "0789", wantErr: true},
+		{value: "-755", wantErr: true},
+		{value: "rwxr-xr-x", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			mode, err := ParseTargetDirMode(tc.value)
+			if tc.wantErr {
+				assert.ErrorContains(t, err, "must be an octal mode up to 7777")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, mode)
+		})
+	}
+}
+
+// TestNewTargetDirPermissions verifies the validation of the target directory flags.
+func TestNewTargetDirPermissions(t *testing.T) {
+	perms, err := NewTargetDirPermissions("0775", 1000, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, TargetDirPermissions{Mode: 0o775, UID: 1000, GID: -1}, perms)
+
+	_, err = NewTargetDirPermissions("0999", -1, -1)
+	assert.ErrorContains(t, err, "invalid target directory mode")
+
+	_, err = NewTargetDirPermissions("0755", -2, -1)
+	assert.ErrorContains(t, err, "invalid target directory owner")
+
+	_, err = NewTargetDirPermissions("0755", -1, 1<<32)
+	assert.ErrorContains(t, err, "invalid target directory owner")
+}
+
+// TestPublishTargetDirPermissions verifies that volume context parameters override the
+// driver settings.
+func TestPublishTargetDirPermissions(t *testing.T) {
+	modeKey := utils.VolumeParameters.GetSCKey("targetDirMode")
+	uidKey := utils.VolumeParameters.GetSCKey("targetDirUID")
+	gidKey := utils.VolumeParameters.GetSCKey("targetDirGID")
+
+	perms, err := (&Driver{}).publishTargetDirPermissions(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultTargetDirPermissions, perms)
+
+	configured := &Driver{}
+	WithTargetDirPermissions(TargetDirPermissions{Mode: 0o770, UID: 1000, GID: 1000})(configured)
+
+	perms, err = configured.publishTargetDirPermissions(map[string]string{uidKey: "2000"})
+	assert.NoError(t, err)
+	assert.Equal(t, TargetDirPermissions{Mode: 0o770, UID: 2000, GID: 1000}, perms)
+
+	perms, err = configured.publishTargetDirPermissions(map[string]string{modeKey: "2775", gidKey: "-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, TargetDirPermissions{Mode: os.ModeSetgid | 0o775, UID: 1000, GID: -1}, perms)
+
+	_, err = configured.publishTargetDirPermissions(map[string]string{modeKey: "rwx", uidKey: "root"})
+	assert.ErrorContains(t, err, modeKey)
+	assert.ErrorContains(t, err, uidKey)
+}
+
+// TestPrepareTargetDir verifies that missing target directories are created with the
+// configured mode and ownership while existing ones are left untouched.
+func TestPrepareTargetDir(t *testing.T) {
+	origChown := osChown
+	t.Cleanup(func() { osChown = origChown })
+
+	var chowned []string
+	osChown = func(name string, uid, gid int) error {
+		chowned = append(chowned, name)
+		assert.Equal(t, 1000, uid)
+		assert.Equal(t, -1, gid)
+		return nil
+	}
+
+	t.Run("Missing", func(t *testing.T) {
+		chowned = nil
+		target := filepath.Join(t.TempDir(), "pods", "mount")
+
+		require.NoError(t, prepareTargetDir(target, TargetDirPermissions{Mode: 0o770, UID: 1000, GID: -1}))
+		info, err := os.Stat(target)
+		require.NoError(t, err)
+		assert.True(t, info.IsDir())
+		// the mode is not restricted by the umask
+		assert.Equal(t, os.FileMode(0o770), info.Mode().Perm())
+		assert.Equal(t, []string{target}, chowned)
+	})
+
+	t.Run("Existing", func(t *testing.T) {
+		chowned = nil
+		target := t.TempDir()
+		require.NoError(t, os.Chmod(target, 0o700))
+
+		require.NoError(t, prepareTargetDir(target, TargetDirPermissions{Mode: 0o777, UID: 1000, GID: -1}))
+		info, err := os.Stat(target)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+		assert.Empty(t, chowned)
+	})
+
+	t.Run("DefaultOwnership", func(t *testing.T) {
+		chowned = nil
+		target := filepath.Join(t.TempDir(), "mount")
+
+		require.NoError(t, prepareTargetDir(target, DefaultTargetDirPermissions))
+		assert.Empty(t, chowned)
+	})
+
+	t.Run("ChownFails", func(t *testing.T) {
+		osChown = func(string, int, int) error { return errors.New("operation not permitted") }
+		target := filepath.Join(t.TempDir(), "mount")
+
+		err := prepareTargetDir(target, TargetDirPermissions{Mode: 0o755, UID: 1000, GID: 1000})
+		assert.EqualError(t, err, "failed to set owner 1000:1000 of target directory: operation not permitted")
+	})
+}
+
+// TestNodePublishVolume_TargetDirPermissions verifies that the target directory is created
+// with the permissions of the volume context before mounting, and that invalid permissions
+// fail the publish.
+func TestNodePublishVolume_TargetDirPermissions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockMounter := mock.NewMockPanMounter(ctrl)
+	driver := &Driver{
+		Name:      DefaultDriverName,
+		log:       klog.Background(),
+		mounterV2: mockMounter,
+	}
+	request := func(target string, volumeContext map[string]string) *csi.NodePublishVolumeRequest {
+		return &csi.NodePublishVolumeRequest{
+			VolumeId:   validVolumeName,
+			TargetPath: target,
+			VolumeCapability: &csi.VolumeCapability{
+				AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+			},
+			VolumeContext: volumeContext,
+			Secrets:       defaultSecrets,
+		}
+	}
+
+	t.Run("Created", func(t *testing.T) {
+		target := filepath.Join(t.TempDir(), "mount")
+		mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), target, gomock.Any()).DoAndReturn(func(_ context.Context, _, target string, _ []string) error {
+			info, err := os.Stat(target)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0o750), info.Mode().Perm())
+			return nil
+		})
+
+		_, err := driver.NodePublishVolume(t.Context(), request(target, map[string]string{
+			utils.VolumeParameters.GetSCKey("targetDirMode"): "0750",
+		}))
+		assert.NoError(t, err)
+	})
+
+	t.Run("InvalidMode", func(t *testing.T) {
+		target := filepath.Join(t.TempDir(), "mount")
+
+		resp, err := driver.NodePublishVolume(t.Context(), request(target, map[string]string{
+			utils.VolumeParameters.GetSCKey("targetDirMode"): "0999",
+		}))
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.NoDirExists(t, target)
+	})
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+)
+
+// targetLocks serializes node operations on the same target path, e.g. a publish racing
+// with an unpublish during pod churn. The zero value is ready to use.
+type targetLocks struct {
+	// key is the target path, entries are removed once no operation holds or waits for them.
+	locks map[string]*targetLock
+	sync.Mutex
+}
+
+// targetLock is the lock of a single target path.
+type targetLock struct {
+	// refs is the number of operations holding or waiting for the lock, guarded by targetLocks.
+	refs int
+	sync.Mutex
+}
+
+// lock acquires the lock of the target path, recording contention metrics if another
+// operation holds it.
+//
+// Parameters:
+//
+//	target    - The target path.
+//	operation - The node operation, used as metric label.
+//
+// Returns:
+//
+//	func() - Function releasing the lock.
+func (t *targetLocks) lock(target, operation string) func() {
+	t.Lock()
+	if t.locks == nil {
+		t.locks = make(map[string]*targetLock)
+	}
+	l, ok := t.locks[target]
+	if !ok {
+		l = &targetLock{}
+		t.locks[target] = l
+	}
+	l.refs++
+	t.Unlock()
+
+	if !l.TryLock() {
+		metrics.NodeTargetLockContentions.WithLabelValues(operation).Inc()
+		start := time.Now()
+		l.Lock()
+		metrics.NodeTargetLockWait.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	}
+
+	return func() {
+		l.Unlock()
+
+		t.Lock()
+		defer t.Unlock()
+		l.refs--
+		if l.refs == 0 {
+			delete(t.locks, target)
+		}
+	}
+}
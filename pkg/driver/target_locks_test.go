@@ -0,0 +1,58 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTargetLocks verifies that operations on the same target path are serialized and
+// that locks of released targets are removed.
+func TestTargetLocks(t *testing.T) {
+	var locks targetLocks
+	contentions := testutil.ToFloat64(metrics.NodeTargetLockContentions.WithLabelValues("unpublish"))
+
+	release := locks.lock("/target/1", "publish")
+
+	// other targets are not blocked
+	locks.lock("/target/2", "publish")()
+
+	acquired := make(chan struct{})
+	go func() {
+		defer locks.lock("/target/1", "unpublish")()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("unpublish acquired the lock held by publish")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	<-acquired
+
+	assert.Equal(t, contentions+1, testutil.ToFloat64(metrics.NodeTargetLockContentions.WithLabelValues("unpublish")))
+	assert.Eventually(t, func() bool {
+		locks.Lock()
+		defer locks.Unlock()
+		return len(locks.locks) == 0
+	}, time.Second, 10*time.Millisecond)
+}
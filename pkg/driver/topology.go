@@ -0,0 +1,276 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// TopologyRealmKeyPrefix is the prefix of the topology keys of the realms a node can reach,
+// followed by the realm host, e.g. "topology.panfs.csi.vdura.com/realm.example.com".
+const TopologyRealmKeyPrefix = "topology.panfs.csi.vdura.com/"
+
+// topologyRealmValue is the value of the topology keys of reachable realms.
+const topologyRealmValue = "true"
+
+// invalidTopologyKeyChars matches the characters of realm hosts not allowed in label names,
+// e.g. the colons of IPv6 addresses.
+var invalidTopologyKeyChars = regexp.MustCompile(`[^a-z0-9._-]`)
+
+// topologyConfig is the realm topology of the driver. The zero value reports no realms and
+// does not constrain created volumes.
+type topologyConfig struct {
+	// enabled makes the controller return the realm of created volumes as accessible topology
+	enabled bool
+	// realmKeys are the topology keys of the realms configured as reachable by the node
+	realmKeys []string
+	// nodeLabels reports the realm topology labels of the Node object of the driver
+	nodeLabels bool
+}
+
+// WithTopology makes the controller advertise volume accessibility constraints, honor the
+// accessibility requirements of CreateVolume and return the realm of created volumes as
+// their accessible topology, so pods are only scheduled onto nodes reporting the realm as
+// reachable. Requires the node plugins to report their realms, see WithTopologyRealms and
+// WithTopologyNodeLabels, and the Topology feature of the provisioner.
+//
+// Parameters:
+//
+//	enabled - Whether created volumes are constrained to the nodes reaching their realm.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithTopology(enabled bool) Option {
+	return func(d *Driver) {
+		d.topology.enabled = enabled
+	}
+}
+
+// WithTopologyRealms sets the realms the node can reach, reported as topology segments by
+// NodeGetInfo. The keys are validated with NewTopologyRealmKeys.
+//
+// Parameters:
+//
+//	realmKeys - The topology keys of the realms reachable by the node.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithTopologyRealms(realmKeys []string) Option {
+	return func(d *Driver) {
+		d.topology.realmKeys = realmKeys
+	}
+}
+
+// WithTopologyNodeLabels makes NodeGetInfo report the labels of the Node object of the driver
+// with the TopologyRealmKeyPrefix and the value "true" as reachable realms, e.g. set by
+// administrators per node pool. Label changes apply once the node plugin registers again.
+//
+// Parameters:
+//
+//	enabled - Whether the realm labels of the node are reported.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithTopologyNodeLabels(enabled bool) Option {
+	return func(d *Driver) {
+		d.topology.nodeLabels = enabled
+	}
+}
+
+// TopologyRealmKey returns the topology key of a realm. Realms with several addresses are
+// identified by their first address, so the nodes and the realm secrets must list the same
+// address first.
+//
+// Parameters:
+//
+//	realm - The realm address or comma-separated list of addresses, e.g. "realm.example.com".
+//
+// Returns:
+//
+//	string - The topology key, e.g. "topology.panfs.csi.vdura.com/realm.example.com".
+//	error  - Error if the realm address is invalid or too long for a label name.
+func TopologyRealmKey(realm string) (string, error) {
+	addresses, err := utils.ParseRealmAddresses(realm)
+	if err != nil {
+		return "", err
+	}
+	host, err := utils.ParseRealmAddress(addresses[0])
+	if err != nil {
+		return "", err
+	}
+
+	name := invalidTopologyKeyChars.ReplaceAllString(strings.ToLower(host), "-")
+	key := TopologyRealmKeyPrefix + name
+	if errs := validation.IsQualifiedName(key); len(errs) != 0 {
+		return "", fmt.Errorf("realm %q cannot be used as topology key: %s", realm, strings.Join(errs, ", "))
+	}
+	return key, nil
+}
+
+// NewTopologyRealmKeys validates the realms reachable by the node, e.g. as configured by
+// command line flags, and returns their topology keys.
+//
+// Parameters:
+//
+//	realms - The realm addresses, empty entries are ignored.
+//
+// Returns:
+//
+//	[]string - The sorted topology keys without duplicates.
+//	error    - Error if any of the realms is invalid.
+func NewTopologyRealmKeys(realms []string) ([]string, error) {
+	var keys []string
+	for _, realm := range realms {
+		if strings.TrimSpace(realm) == "" {
+			continue
+		}
+		key, err := TopologyRealmKey(realm)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
+
+// LoadTopologyRealms reads the realms reachable by the node from a file with one realm address
+// per line. Empty lines and lines starting with "#" are ignored.
+//
+// Parameters:
+//
+//	path - The path of the file.
+//
+// Returns:
+//
+//	[]string - The realm addresses in file order.
+//	error    - Error if the file cannot be read.
+func LoadTopologyRealms(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var realms []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		realms = append(realms, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read topology realms %s: %w", path, err)
+	}
+	return realms, nil
+}
+
+// nodeTopologySegments returns the realm topology segments of the node, from the configured
+// realms and, if enabled, the realm labels of the Node object.
+//
+// Parameters:
+//
+//	ctx - The context for the Kubernetes API call.
+//
+// Returns:
+//
+//	map[string]string - The topology segments of the reachable realms.
+func (d *Driver) nodeTopologySegments(ctx context.Context) map[string]string {
+	segments := make(map[string]string, len(d.topology.realmKeys))
+	for _, key := range d.topology.realmKeys {
+		segments[key] = topologyRealmValue
+	}
+
+	if !d.topology.nodeLabels || d.kubeClient == nil {
+		return segments
+	}
+	node, err := d.kubeClient.CoreV1().Nodes().Get(ctx, d.host, metav1.GetOptions{})
+	if err != nil {
+		d.log.Error(err, "failed to read the realm topology labels of the node", "node", d.host)
+		return segments
+	}
+	for key, value := range node.Labels {
+		if strings.HasPrefix(key, TopologyRealmKeyPrefix) && value == topologyRealmValue {
+			segments[key] = topologyRealmValue
+		}
+	}
+	return segments
+}
+
+// accessibleTopology returns the accessible topology of a volume created on the realm of
+// the secrets, checking that it satisfies the accessibility requirements of the request.
+// Requisite topologies take precedence, preferred topologies are checked if there are none.
+//
+// Parameters:
+//
+//	requirements - The accessibility requirements of the CreateVolume request.
+//	secrets      - The resolved secrets of the request.
+//
+// Returns:
+//
+//	[]*csi.Topology - The realm segment of the volume, nil if topology is disabled.
+//	error           - codes.InvalidArgument if the realm cannot be used as topology key,
+//	                  codes.ResourceExhausted if none of the required topologies reaches the realm.
+func (d *Driver) accessibleTopology(requirements *csi.TopologyRequirement, secrets map[string]string) ([]*csi.Topology, error) {
+	if !d.topology.enabled {
+		return nil, nil
+	}
+
+	realm := secrets[utils.RealmConnectionContext.RealmAddress]
+	key, err := TopologyRealmKey(realm)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	candidates := requirements.GetRequisite()
+	if len(candidates) == 0 {
+		candidates = requirements.GetPreferred()
+	}
+	if len(candidates) != 0 && !reachesRealm(candidates, key) {
+		return nil, status.Errorf(codes.ResourceExhausted, "realm %s is not reachable from the requested topology, %s is not reported by any of its nodes", realm, key)
+	}
+
+	return []*csi.Topology{{Segments: map[string]string{key: topologyRealmValue}}}, nil
+}
+
+// reachesRealm reports whether any of the topologies reaches the realm of the key.
+func reachesRealm(topologies []*csi.Topology, key string) bool {
+	for _, topology := range topologies {
+		if topology.GetSegments()[key] == topologyRealmValue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,217 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+)
+
+// TestTopologyRealmKey verifies the topology keys of realm addresses.
+func TestTopologyRealmKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		realm   string
+		wantKey string
+		wantErr string
+	}{
+		{name: "Hostname", realm: "Realm.Example.com", wantKey: TopologyRealmKeyPrefix + "realm.example.com"},
+		{name: "IPv4", realm: "10.0.0.1", wantKey: TopologyRealmKeyPrefix + "10.0.0.1"},
+		{name: "IPv6", realm: "[fd00::1]", wantKey: TopologyRealmKeyPrefix + "fd00--1"},
+		{name: "SeveralAddresses", realm: "10.0.0.1, 10.0.0.2", wantKey: TopologyRealmKeyPrefix + "10.0.0.1"},
+		{name: "Empty", realm: "", wantErr: "must not be empty"},
+		{name: "InvalidAddress", realm: "realm_a", wantErr: "invalid realm address"},
+		{name: "NameTooLong", realm: "a123456789.b123456789.c123456789.d123456789.e123456789.f123456789", wantErr: "cannot be used as topology key"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := TopologyRealmKey(tc.realm)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantKey, key)
+		})
+	}
+}
+
+// TestNewTopologyRealmKeys verifies that the configured realms are validated, sorted and
+// deduplicated.
+func TestNewTopologyRealmKeys(t *testing.T) {
+	keys, err := NewTopologyRealmKeys([]string{"realm-b", "", "realm-a", " ", "REALM-B"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{TopologyRealmKeyPrefix + "realm-a", TopologyRealmKeyPrefix + "realm-b"}, keys)
+
+	_, err = NewTopologyRealmKeys([]string{"realm-a", "realm_b"})
+	assert.ErrorContains(t, err, "invalid realm address")
+}
+
+// TestLoadTopologyRealms verifies that realms are read from a file, skipping comments and
+// empty lines.
+func TestLoadTopologyRealms(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "realms")
+	require.NoError(t, os.WriteFile(path, []byte("# realms of the rack\nrealm-a\n\n  10.0.0.1  \n"), 0o600))
+
+	realms, err := LoadTopologyRealms(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"realm-a", "10.0.0.1"}, realms)
+
+	_, err = LoadTopologyRealms(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// TestNodeGetInfoTopology verifies that NodeGetInfo reports the configured realms and the
+// realm labels of the node.
+func TestNodeGetInfoTopology(t *testing.T) {
+	origLabelSet := IsNodeLabelSet
+	defer func() { IsNodeLabelSet = origLabelSet }()
+
+	kubeClient := fake.NewClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: "node1",
+		Labels: map[string]string{
+			TopologyRealmKeyPrefix + "realm-b": "true",
+			TopologyRealmKeyPrefix + "realm-c": "false",
+			"kubernetes.io/hostname":           "node1",
+		},
+	}})
+	realmKeys, err := NewTopologyRealmKeys([]string{"realm-a"})
+	require.NoError(t, err)
+
+	t.Run("ConfiguredRealms", func(t *testing.T) {
+		d := &Driver{host: "node1", log: klog.Background()}
+		WithTopologyRealms(realmKeys)(d)
+
+		resp, err := d.NodeGetInfo(t.Context(), &csi.NodeGetInfoRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			TopologyRealmKeyPrefix + "realm-a": "true",
+			NodeLabelKey:                       nodeLabelValue,
+		}, resp.GetAccessibleTopology().GetSegments())
+	})
+
+	t.Run("NodeLabels", func(t *testing.T) {
+		d := &Driver{host: "node1", log: klog.Background(), kubeClient: kubeClient}
+		defer d.stopNodeLabelReconciler()
+		WithTopologyRealms(realmKeys)(d)
+		WithTopologyNodeLabels(true)(d)
+
+		resp, err := d.NodeGetInfo(t.Context(), &csi.NodeGetInfoRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			TopologyRealmKeyPrefix + "realm-a": "true",
+			TopologyRealmKeyPrefix + "realm-b": "true",
+			NodeLabelKey:                       nodeLabelValue,
+		}, resp.GetAccessibleTopology().GetSegments())
+	})
+
+	t.Run("MissingNode", func(t *testing.T) {
+		d := &Driver{host: "node1", log: klog.Background(), kubeClient: fake.NewClientset()}
+		WithTopologyRealms(realmKeys)(d)
+		WithTopologyNodeLabels(true)(d)
+
+		// the readiness label cannot be set on a missing node either
+		resp, err := d.NodeGetInfo(t.Context(), &csi.NodeGetInfoRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{TopologyRealmKeyPrefix + "realm-a": "true"}, resp.GetAccessibleTopology().GetSegments())
+	})
+}
+
+// TestCreateVolumeTopology verifies that created volumes are constrained to their realm and
+// that accessibility requirements without the realm fail before the volume is created.
+func TestCreateVolumeTopology(t *testing.T) {
+	realmKey := TopologyRealmKeyPrefix + "realm"
+	reachable := &csi.Topology{Segments: map[string]string{realmKey: "true", NodeLabelKey: nodeLabelValue}}
+	unreachable := &csi.Topology{Segments: map[string]string{NodeLabelKey: nodeLabelValue}}
+
+	tests := []struct {
+		name         string
+		disabled     bool
+		requirements *csi.TopologyRequirement
+		wantCode     codes.Code
+		wantTopology []*csi.Topology
+	}{
+		{
+			name:         "NoRequirements",
+			wantTopology: []*csi.Topology{{Segments: map[string]string{realmKey: "true"}}},
+		},
+		{
+			name:         "RequisiteReachesRealm",
+			requirements: &csi.TopologyRequirement{Requisite: []*csi.Topology{unreachable, reachable}, Preferred: []*csi.Topology{unreachable}},
+			wantTopology: []*csi.Topology{{Segments: map[string]string{realmKey: "true"}}},
+		},
+		{
+			name:         "PreferredReachesRealm",
+			requirements: &csi.TopologyRequirement{Preferred: []*csi.Topology{reachable}},
+			wantTopology: []*csi.Topology{{Segments: map[string]string{realmKey: "true"}}},
+		},
+		{
+			name:         "RequisiteMissesRealm",
+			requirements: &csi.TopologyRequirement{Requisite: []*csi.Topology{unreachable}, Preferred: []*csi.Topology{reachable}},
+			wantCode:     codes.ResourceExhausted,
+		},
+		{
+			name:         "Disabled",
+			disabled:     true,
+			requirements: &csi.TopologyRequirement{Requisite: []*csi.Topology{unreachable}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			pancliMock := mock.NewMockStorageProviderClient(ctrl)
+			d := &Driver{panfs: pancliMock}
+			WithTopology(!tc.disabled)(d)
+
+			if tc.wantCode == codes.OK {
+				pancliMock.EXPECT().CreateVolume(gomock.Any(), validVolumeName, gomock.Any(), defaultSecrets).Return(&utils.Volume{
+					Name:  utils.VolumeName(validVolumeName),
+					State: utils.VolumeStateOnline,
+					Soft:  10,
+				}, nil)
+			}
+
+			resp, err := d.CreateVolume(t.Context(), &csi.CreateVolumeRequest{
+				Name:                      validVolumeName,
+				Secrets:                   defaultSecrets,
+				CapacityRange:             &csi.CapacityRange{RequiredBytes: 10 << 30},
+				VolumeCapabilities:        []*csi.VolumeCapability{mountCapability()},
+				AccessibilityRequirements: tc.requirements,
+			})
+			require.Equal(t, tc.wantCode, status.Code(err), err)
+			if tc.wantCode != codes.OK {
+				assert.ErrorContains(t, err, "realm realm is not reachable from the requested topology")
+				return
+			}
+			assert.Equal(t, tc.wantTopology, resp.GetVolume().GetAccessibleTopology())
+		})
+	}
+}
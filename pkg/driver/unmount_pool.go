@@ -0,0 +1,161 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"math"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"google.golang.org/grpc/status"
+)
+
+// cgroupCPUMaxFile holds the CPU quota of the container in cgroup v2, e.g. "200000 100000".
+const cgroupCPUMaxFile = "/sys/fs/cgroup/cpu.max"
+
+// readCgroupCPUMax reads the CPU quota of the container, replaced in tests.
+var readCgroupCPUMax = func() ([]byte, error) {
+	return os.ReadFile(cgroupCPUMaxFile)
+}
+
+// DefaultUnmountConcurrency is the default maximum number of concurrent unmounts.
+const DefaultUnmountConcurrency = 16
+
+// WithUnmountConcurrency bounds the number of unmounts the node plugin runs at the same time.
+// During mass pod evictions the kubelet sends hundreds of unpublish requests at once; further
+// unmounts wait for a free worker instead of slowing down the whole node, so that publish
+// requests keep a stable latency. A zero limit uses DefaultUnmountConcurrency, a negative
+// limit disables it. Ignored if WithUnmountConcurrencyFromCPU is enabled.
+//
+// Parameters:
+//
+//	limit - The maximum number of concurrent unmounts.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithUnmountConcurrency(limit int) Option {
+	return func(d *Driver) {
+		if d.unmounts.fromCPU {
+			return
+		}
+		if limit == 0 {
+			limit = DefaultUnmountConcurrency
+		}
+		d.unmounts.limit = limit
+	}
+}
+
+// WithUnmountConcurrencyFromCPU sizes the unmount workers of WithUnmountConcurrency by the
+// CPU quota of the container cgroup, or the number of CPUs if there is no quota, instead of
+// a fixed limit. Small CPU limits leave a single worker, so it only suits node plugins with
+// several CPUs.
+//
+// Parameters:
+//
+//	enabled - Whether the number of concurrent unmounts follows the CPU quota.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithUnmountConcurrencyFromCPU(enabled bool) Option {
+	return func(d *Driver) {
+		if !enabled {
+			return
+		}
+		d.unmounts.fromCPU = true
+		d.unmounts.limit = cgroupCPULimit()
+	}
+}
+
+// cgroupCPULimit returns the number of CPUs the container may use, rounded up, falling back
+// to the number of CPUs of the node if the container has no cgroup v2 CPU quota.
+func cgroupCPULimit() int {
+	data, err := readCgroupCPUMax()
+	if err != nil {
+		return runtime.NumCPU()
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 || fields[0] == "max" {
+		return runtime.NumCPU()
+	}
+	quota, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return runtime.NumCPU()
+	}
+	period, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || period <= 0 {
+		return runtime.NumCPU()
+	}
+	return max(1, int(math.Ceil(quota/period)))
+}
+
+// unmountPool bounds the number of concurrent unmounts. The zero value does not limit unmounts.
+type unmountPool struct {
+	limit int
+	// fromCPU is set if limit is derived from the CPU quota, see WithUnmountConcurrencyFromCPU
+	fromCPU bool
+	// workers is a semaphore with one element per busy worker
+	workers chan struct{}
+	sync.Mutex
+}
+
+// acquire waits for a free unmount worker.
+//
+// Parameters:
+//
+//	ctx - The context of the request, the wait ends when it is done.
+//
+// Returns:
+//
+//	func() - Function releasing the worker.
+//	error  - The status error of the context if no worker became free in time.
+func (p *unmountPool) acquire(ctx context.Context) (func(), error) {
+	if p.limit <= 0 {
+		metrics.NodeUnmountsInProgress.Inc()
+		return metrics.NodeUnmountsInProgress.Dec, nil
+	}
+
+	p.Lock()
+	if p.workers == nil {
+		p.workers = make(chan struct{}, p.limit)
+	}
+	workers := p.workers
+	p.Unlock()
+
+	metrics.NodeUnmountsWaiting.Inc()
+	defer metrics.NodeUnmountsWaiting.Dec()
+	start := time.Now()
+
+	select {
+	case workers <- struct{}{}:
+		metrics.NodeUnmountQueueWait.Observe(time.Since(start).Seconds())
+		metrics.NodeUnmountsInProgress.Inc()
+		return func() {
+			metrics.NodeUnmountsInProgress.Dec()
+			<-workers
+		}, nil
+	case <-ctx.Done():
+		metrics.NodeUnmountQueueWait.Observe(time.Since(start).Seconds())
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
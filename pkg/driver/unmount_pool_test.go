@@ -0,0 +1,135 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestUnmountPool verifies that unmounts beyond the limit wait for a free worker.
+func TestUnmountPool(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		var p unmountPool
+		var releases []func()
+		for range 3 {
+			release, err := p.acquire(t.Context())
+			require.NoError(t, err)
+			releases = append(releases, release)
+		}
+		assert.Equal(t, float64(3), testutil.ToFloat64(metrics.NodeUnmountsInProgress))
+		for _, release := range releases {
+			release()
+		}
+	})
+
+	t.Run("WaitsForRelease", func(t *testing.T) {
+		p := unmountPool{limit: 1}
+		release, err := p.acquire(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.NodeUnmountsInProgress))
+
+		time.AfterFunc(50*time.Millisecond, release)
+		release, err = p.acquire(t.Context())
+		require.NoError(t, err)
+		release()
+		assert.Equal(t, float64(0), testutil.ToFloat64(metrics.NodeUnmountsInProgress))
+		assert.Equal(t, float64(0), testutil.ToFloat64(metrics.NodeUnmountsWaiting))
+	})
+
+	t.Run("ContextDone", func(t *testing.T) {
+		p := unmountPool{limit: 1}
+		release, err := p.acquire(t.Context())
+		require.NoError(t, err)
+		defer release()
+
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+		_, err = p.acquire(ctx)
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	})
+}
+
+// TestCgroupCPULimit verifies the default unmount concurrency derived from the CPU quota.
+func TestCgroupCPULimit(t *testing.T) {
+	origRead := readCgroupCPUMax
+	defer func() { readCgroupCPUMax = origRead }()
+
+	tests := []struct {
+		name string
+		data string
+		err  error
+		want int
+	}{
+		{name: "Quota", data: "250000 100000\n", want: 3},
+		{name: "SmallQuota", data: "10000 100000\n", want: 1},
+		{name: "Unlimited", data: "max 100000\n", want: runtime.NumCPU()},
+		{name: "Malformed", data: "invalid", want: runtime.NumCPU()},
+		{name: "NoCgroup", err: errors.New("no such file"), want: runtime.NumCPU()},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			readCgroupCPUMax = func() ([]byte, error) { return []byte(tc.data), tc.err }
+			assert.Equal(t, tc.want, cgroupCPULimit())
+
+			// the CPU quota takes precedence over the fixed limit in any order
+			d := &Driver{}
+			WithUnmountConcurrency(4)(d)
+			WithUnmountConcurrencyFromCPU(true)(d)
+			assert.Equal(t, tc.want, d.unmounts.limit)
+			WithUnmountConcurrency(4)(d)
+			assert.Equal(t, tc.want, d.unmounts.limit)
+		})
+	}
+}
+
+// TestWithUnmountConcurrency verifies the fixed unmount concurrency used by default.
+func TestWithUnmountConcurrency(t *testing.T) {
+	origRead := readCgroupCPUMax
+	defer func() { readCgroupCPUMax = origRead }()
+	readCgroupCPUMax = func() ([]byte, error) { return []byte("30000 100000\n"), nil }
+
+	tests := []struct {
+		name    string
+		limit   int
+		fromCPU bool
+		want    int
+	}{
+		{name: "Default", limit: 0, want: DefaultUnmountConcurrency},
+		{name: "Fixed", limit: 4, want: 4},
+		{name: "Disabled", limit: -1, want: -1},
+		{name: "FromCPU", limit: 0, fromCPU: true, want: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &Driver{}
+			WithUnmountConcurrencyFromCPU(tc.fromCPU)(d)
+			WithUnmountConcurrency(tc.limit)(d)
+			assert.Equal(t, tc.want, d.unmounts.limit)
+		})
+	}
+}
@@ -41,14 +41,14 @@ var (
 //	error - Returns an error if requiredBytes exceeds soft quota or limitBytes does not match hard quota.
 func validateVolumeCapacity(capacity *csi.CapacityRange, vol *utils.Volume) error {
 	requiredBytes := capacity.GetRequiredBytes()
-	softBytes := utils.GBToBytes(vol.Soft)
+	softBytes := vol.GetSoftQuotaBytes()
 
 	if requiredBytes != 0 && requiredBytes > softBytes {
 		return fmt.Errorf("requiredBytes bytes (%d) exceeds soft quota bytes (%d)", requiredBytes, softBytes)
 	}
 
 	limit := capacity.GetLimitBytes()
-	hardBytes := utils.GBToBytes(vol.Hard)
+	hardBytes := vol.GetHardQuotaBytes()
 
 	if limit != 0 && limit != hardBytes {
 		return fmt.Errorf("limit bytes (%d) not equal to hard quota bytes (%d)", limit, hardBytes)
@@ -58,7 +58,7 @@ func validateVolumeCapacity(capacity *csi.CapacityRange, vol *utils.Volume) erro
 }
 
 // validateCreateVolumeRequest validates the CreateVolumeRequest for correctness.
-// Checks for required fields, unsupported content source, and valid capacity range.
+// Checks for required fields, supported content source, and valid capacity range.
 //
 // Parameters:
 //
@@ -76,9 +76,19 @@ func validateCreateVolumeRequest(req *csi.CreateVolumeRequest) error {
 		return fmt.Errorf("volume_capabilities must be provided")
 	}
 
-	// Content source is not supported in this driver
-	if req.GetVolumeContentSource() != nil {
-		return fmt.Errorf("create volume request with content source is not supported")
+	if source := req.GetVolumeContentSource(); source != nil {
+		switch {
+		case source.GetSnapshot() != nil:
+			if source.GetSnapshot().GetSnapshotId() == "" {
+				return fmt.Errorf("snapshot id of the volume content source must be provided")
+			}
+		case source.GetVolume() != nil:
+			if source.GetVolume().GetVolumeId() == "" {
+				return fmt.Errorf("volume id of the volume content source must be provided")
+			}
+		default:
+			return fmt.Errorf("create volume request with volume content source is not supported")
+		}
 	}
 
 	requiredBytes := req.CapacityRange.GetRequiredBytes()
@@ -127,6 +137,10 @@ func validateVolumeParameters(parameters map[string]string) error {
 		return fmt.Errorf("%s must be one of: %v", utils.VolumeParameters.GetSCKey("layout"), layoutList)
 	}
 
+	if val, exist := parameters[utils.VolumeParameters.GetSCKey("efsa")]; exist && !utils.EFSAMode(val).IsValid() {
+		return fmt.Errorf("%s must be one of: %v, got %q", utils.VolumeParameters.GetSCKey("efsa"), utils.EFSAModes, val)
+	}
+
 	if val, exist := parameters[utils.VolumeParameters.GetSCKey("maxwidth")]; exist {
 		intValue, err := strconv.Atoi(val)
 		if err != nil {
@@ -172,6 +186,10 @@ func validateVolumeParameters(parameters map[string]string) error {
 		// todo: This option is only available for volumes with RAID 6+ or RAID 5+ layout.
 	}
 
+	if err := validateStringParameters(parameters); err != nil {
+		return err
+	}
+
 	if val, exist := parameters[utils.VolumeParameters.GetSCKey("user")]; exist && val == "" {
 		return fmt.Errorf("%s must be provided", utils.VolumeParameters.GetSCKey("user"))
 	}
@@ -192,53 +210,45 @@ func validateVolumeParameters(parameters map[string]string) error {
 		return fmt.Errorf("%s must be one of: %v", utils.VolumeParameters.GetSCKey("operm"), permList)
 	}
 
-	if val, exist := parameters[utils.VolumeParameters.GetSCKey("encryption")]; exist {
-		if valid := validateEncryptionParameter(val); !valid {
-			return fmt.Errorf("%s must be 'on' or 'off'", utils.VolumeParameters.GetSCKey("encryption"))
-		}
+	if val, exist := parameters[utils.VolumeParameters.GetSCKey("cacheMode")]; exist && !utils.In(val, cacheModeList...) {
+		return fmt.Errorf("%s must be one of: %v", utils.VolumeParameters.GetSCKey("cacheMode"), cacheModeList)
 	}
 
-	// Additional validation rules can be added here as needed.
-	return nil
-}
+	if val, exist := parameters[utils.VolumeParameters.GetSCKey("verifyMount")]; exist {
+		if _, err := strconv.ParseBool(val); err != nil {
+			return fmt.Errorf("%s must be 'true' or 'false'", utils.VolumeParameters.GetSCKey("verifyMount"))
+		}
+	}
 
-// validateReqSecrets validates the secrets map for required authentication keys.
-// Ensures realm, SSH user, and either password or private key are present.
-//
-// Parameters:
-//
-//	secrets - Map of secret keys and values.
-//
-// Returns:
-//
-//	error - Returns an error if required secrets are missing or invalid.
-func validateReqSecrets(secrets map[string]string) error {
-	if secrets == nil {
-		return fmt.Errorf("secrets must be provided")
+	if err := validateTargetDirParameters(parameters); err != nil {
+		return err
 	}
-	if _, ok := secrets[utils.RealmConnectionContext.RealmAddress]; !ok {
-		return fmt.Errorf("missing %s in secrets", utils.RealmConnectionContext.RealmAddress)
+
+	if err := validateMinCapacityParameters(parameters); err != nil {
+		return err
 	}
 
-	if _, ok := secrets[utils.RealmConnectionContext.Username]; !ok {
-		return fmt.Errorf("missing %s in secrets", utils.RealmConnectionContext.Username)
+	if err := validateProtectionTierParameter(parameters); err != nil {
+		return err
 	}
 
-	password, ok := secrets[utils.RealmConnectionContext.Password]
-	if !ok {
-		password = "" // Default to empty if not provided
+	if val, exist := parameters[utils.VolumeParameters.GetSCKey("reconcileCapacity")]; exist && val != ReconcileCapacityExpand {
+		return fmt.Errorf("%s must be '%s'", utils.VolumeParameters.GetSCKey("reconcileCapacity"), ReconcileCapacityExpand)
 	}
 
-	privateKey, ok := secrets[utils.RealmConnectionContext.PrivateKey]
-	if !ok {
-		privateKey = "" // Default to empty if not provided
+	if val, exist := parameters[utils.VolumeParameters.GetSCKey("parentVolume")]; exist {
+		if err := utils.ValidateDirectoryName(val); err != nil {
+			return fmt.Errorf("%s must be the name of a volume: %w", utils.VolumeParameters.GetSCKey("parentVolume"), err)
+		}
 	}
 
-	if password == "" && privateKey == "" {
-		// If neither password nor private key is provided, return an error.
-		return fmt.Errorf("no valid authentication credentials provided in secrets, either password or public key is required")
+	if val, exist := parameters[utils.VolumeParameters.GetSCKey("encryption")]; exist {
+		if valid := validateEncryptionParameter(val); !valid {
+			return fmt.Errorf("%s must be 'on' or 'off'", utils.VolumeParameters.GetSCKey("encryption"))
+		}
 	}
 
+	// Additional validation rules can be added here as needed.
 	return nil
 }
 
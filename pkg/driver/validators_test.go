@@ -204,6 +204,20 @@ func TestValidateCreateVolumeRequest(t *testing.T) {
 			},
 			err: fmt.Errorf("%s must be one of: %v", utils.VolumeParameters.GetSCKey("layout"), layoutList),
 		},
+		{
+			name: "invalid efsa parameter",
+			request: &csi.CreateVolumeRequest{
+				Name: "test",
+				CapacityRange: &csi.CapacityRange{
+					RequiredBytes: 10,
+				},
+				VolumeCapabilities: []*csi.VolumeCapability{{}},
+				Parameters: map[string]string{
+					utils.VolumeParameters.GetSCKey("efsa"): "retry-forever",
+				},
+			},
+			err: fmt.Errorf(`%s must be one of: [retry file-unavailable], got "retry-forever"`, utils.VolumeParameters.GetSCKey("efsa")),
+		},
 		{
 			name: "invalid maxwidth parameter (alphanumeric)",
 			request: &csi.CreateVolumeRequest{
@@ -317,6 +331,34 @@ func TestValidateCreateVolumeRequest(t *testing.T) {
 			},
 			err: fmt.Errorf("%s must be greater then 0", utils.VolumeParameters.GetSCKey("rgdepth")),
 		},
+		{
+			name: "invalid reconcileCapacity parameter",
+			request: &csi.CreateVolumeRequest{
+				Name: "test",
+				CapacityRange: &csi.CapacityRange{
+					RequiredBytes: 10,
+				},
+				VolumeCapabilities: []*csi.VolumeCapability{{}},
+				Parameters: map[string]string{
+					utils.VolumeParameters.GetSCKey("reconcileCapacity"): "shrink",
+				},
+			},
+			err: fmt.Errorf("%s must be 'expand'", utils.VolumeParameters.GetSCKey("reconcileCapacity")),
+		},
+		{
+			name: "invalid targetDirUID parameter",
+			request: &csi.CreateVolumeRequest{
+				Name: "test",
+				CapacityRange: &csi.CapacityRange{
+					RequiredBytes: 10,
+				},
+				VolumeCapabilities: []*csi.VolumeCapability{{}},
+				Parameters: map[string]string{
+					utils.VolumeParameters.GetSCKey("targetDirUID"): "nobody",
+				},
+			},
+			err: fmt.Errorf("%s: invalid target directory owner \"nobody\": must be a numeric id or -1", utils.VolumeParameters.GetSCKey("targetDirUID")),
+		},
 		{
 			name: "empty user parameter",
 			request: &csi.CreateVolumeRequest{
@@ -402,7 +444,7 @@ func TestValidateCreateVolumeRequest(t *testing.T) {
 			err: fmt.Errorf("%s must be 'on' or 'off'", utils.VolumeParameters.GetSCKey("encryption")),
 		},
 		{
-			name: "volume content source not supported",
+			name: "snapshot content source without snapshot id",
 			request: &csi.CreateVolumeRequest{
 				Name: "test",
 				CapacityRange: &csi.CapacityRange{
@@ -411,14 +453,14 @@ func TestValidateCreateVolumeRequest(t *testing.T) {
 				VolumeCapabilities: []*csi.VolumeCapability{{}},
 				VolumeContentSource: &csi.VolumeContentSource{
 					Type: &csi.VolumeContentSource_Snapshot{
-						Snapshot: &csi.VolumeContentSource_SnapshotSource{SnapshotId: "snap-123"},
+						Snapshot: &csi.VolumeContentSource_SnapshotSource{},
 					},
 				},
 			},
-			err: fmt.Errorf("create volume request with content source is not supported"),
+			err: fmt.Errorf("snapshot id of the volume content source must be provided"),
 		},
 		{
-			name: "volume content source not supported with volume source",
+			name: "volume content source without volume id",
 			request: &csi.CreateVolumeRequest{
 				Name: "test",
 				CapacityRange: &csi.CapacityRange{
@@ -427,11 +469,23 @@ func TestValidateCreateVolumeRequest(t *testing.T) {
 				VolumeCapabilities: []*csi.VolumeCapability{{}},
 				VolumeContentSource: &csi.VolumeContentSource{
 					Type: &csi.VolumeContentSource_Volume{
-						Volume: &csi.VolumeContentSource_VolumeSource{VolumeId: "vol-123"},
+						Volume: &csi.VolumeContentSource_VolumeSource{},
 					},
 				},
 			},
-			err: fmt.Errorf("create volume request with content source is not supported"),
+			err: fmt.Errorf("volume id of the volume content source must be provided"),
+		},
+		{
+			name: "volume content source without type",
+			request: &csi.CreateVolumeRequest{
+				Name: "test",
+				CapacityRange: &csi.CapacityRange{
+					RequiredBytes: 10,
+				},
+				VolumeCapabilities:  []*csi.VolumeCapability{{}},
+				VolumeContentSource: &csi.VolumeContentSource{},
+			},
+			err: fmt.Errorf("create volume request with volume content source is not supported"),
 		},
 	}
 
@@ -456,6 +510,7 @@ func TestValidateCreateVolumeRequest(t *testing.T) {
 				utils.VolumeParameters.GetSCKey("bladeset"):   "Set 1",
 				utils.VolumeParameters.GetSCKey("volservice"): "vol_service_id",
 				utils.VolumeParameters.GetSCKey("layout"):     "raid10+",
+				utils.VolumeParameters.GetSCKey("efsa"):       "file-unavailable",
 				utils.VolumeParameters.GetSCKey("maxwidth"):   "3",
 				utils.VolumeParameters.GetSCKey("stripeunit"): "16K",
 				utils.VolumeParameters.GetSCKey("rgwidth"):    "9",
@@ -478,6 +533,40 @@ func TestValidateCreateVolumeRequest(t *testing.T) {
 			t.Errorf("unexpected error: %v", err)
 		}
 	})
+
+	t.Run("valid request with snapshot content source", func(t *testing.T) {
+		req := &csi.CreateVolumeRequest{
+			Name:               "test",
+			VolumeCapabilities: []*csi.VolumeCapability{{}},
+			VolumeContentSource: &csi.VolumeContentSource{
+				Type: &csi.VolumeContentSource_Snapshot{
+					Snapshot: &csi.VolumeContentSource_SnapshotSource{SnapshotId: "vol-123@snap-123"},
+				},
+			},
+		}
+
+		err := validateCreateVolumeRequest(req)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("valid request with volume content source", func(t *testing.T) {
+		req := &csi.CreateVolumeRequest{
+			Name:               "test",
+			VolumeCapabilities: []*csi.VolumeCapability{{}},
+			VolumeContentSource: &csi.VolumeContentSource{
+				Type: &csi.VolumeContentSource_Volume{
+					Volume: &csi.VolumeContentSource_VolumeSource{VolumeId: "vol-123"},
+				},
+			},
+		}
+
+		err := validateCreateVolumeRequest(req)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
 }
 
 // TestValidateStripeUnit tests the validateStripeUnit function.
@@ -0,0 +1,129 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Reason codes reported by VerifySecret.
+const (
+	// SecretReasonInvalid means required keys are missing or malformed.
+	SecretReasonInvalid = "InvalidSecret"
+	// SecretReasonInvalidPrivateKey means the private key cannot be parsed or decrypted.
+	SecretReasonInvalidPrivateKey = "InvalidPrivateKey"
+	// SecretReasonUnauthenticated means the realm rejected the credentials.
+	SecretReasonUnauthenticated = "Unauthenticated"
+	// SecretReasonUnavailable means the realm could not be reached.
+	SecretReasonUnavailable = "Unavailable"
+	// SecretReasonInternal means the check failed for any other reason.
+	SecretReasonInternal = "Internal"
+)
+
+// CredentialVerifier checks realm credentials with a live connection.
+type CredentialVerifier interface {
+	VerifyCredentials(secrets map[string]string) error
+}
+
+// SecretVerificationReason describes a single failed secret check.
+type SecretVerificationReason struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// SecretVerification is the machine-readable result of VerifySecret.
+type SecretVerification struct {
+	Valid   bool                       `json:"valid"`
+	Reasons []SecretVerificationReason `json:"reasons,omitempty"`
+}
+
+// VerifySecret runs the checks the controller applies to request secrets and, if they
+// pass, authenticates against the realm. It is meant for secret rotation pipelines which
+// need to validate a new secret before it is rolled out.
+//
+// Parameters:
+//
+//	secrets  - Map of secret keys and values.
+//	verifier - The verifier for the live authentication check.
+//
+// Returns:
+//
+//	SecretVerification - The result with the reasons of all failed checks.
+func VerifySecret(secrets map[string]string, verifier CredentialVerifier) SecretVerification {
+	var reasons []SecretVerificationReason
+	fail := func(code string, err error) {
+		reasons = append(reasons, SecretVerificationReason{Code: code, Message: err.Error()})
+	}
+
+	if _, err := utils.ParseRealmSecrets(secrets); err != nil {
+		fail(SecretReasonInvalid, err)
+	}
+	if err := validateSecretsPrivateKey(secrets); err != nil {
+		fail(SecretReasonInvalidPrivateKey, err)
+	}
+
+	// only connect with a secret the controller would accept
+	if len(reasons) == 0 {
+		if err := verifier.VerifyCredentials(secrets); err != nil {
+			switch {
+			case errors.Is(err, pancli.ErrorUnauthenticated):
+				fail(SecretReasonUnauthenticated, err)
+			case errors.Is(err, pancli.ErrorUnavailable):
+				fail(SecretReasonUnavailable, err)
+			default:
+				fail(SecretReasonInternal, err)
+			}
+		}
+	}
+
+	return SecretVerification{Valid: len(reasons) == 0, Reasons: reasons}
+}
+
+// ParseSecretManifest reads a Kubernetes Secret manifest in YAML or JSON format.
+// Values of stringData take precedence over data, as they do on the API server.
+//
+// Parameters:
+//
+//	r - The reader providing the manifest.
+//
+// Returns:
+//
+//	map[string]string - The secret keys and values.
+//	error             - Error if the manifest cannot be parsed or is not a Secret.
+func ParseSecretManifest(r io.Reader) (map[string]string, error) {
+	secret := corev1.Secret{}
+	if err := utilyaml.NewYAMLOrJSONDecoder(r, 4096).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("failed to parse secret manifest: %w", err)
+	}
+	if secret.Kind != "Secret" {
+		return nil, fmt.Errorf("manifest kind is %q, expected Secret", secret.Kind)
+	}
+
+	secrets := make(map[string]string, len(secret.Data)+len(secret.StringData))
+	for k, v := range secret.Data {
+		secrets[k] = string(v)
+	}
+	for k, v := range secret.StringData {
+		secrets[k] = v
+	}
+	return secrets, nil
+}
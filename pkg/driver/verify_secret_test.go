@@ -0,0 +1,119 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// credentialVerifierFunc adapts a function to the CredentialVerifier interface.
+type credentialVerifierFunc func(secrets map[string]string) error
+
+func (f credentialVerifierFunc) VerifyCredentials(secrets map[string]string) error {
+	return f(secrets)
+}
+
+// TestVerifySecret verifies the reason codes reported for invalid and rejected secrets.
+func TestVerifySecret(t *testing.T) {
+	validSecrets := map[string]string{
+		utils.RealmConnectionContext.RealmAddress: "10.0.0.1",
+		utils.RealmConnectionContext.Username:     "admin",
+		utils.RealmConnectionContext.Password:     "secret",
+	}
+
+	tests := []struct {
+		name      string
+		secrets   map[string]string
+		verifyErr error
+		wantCodes []string
+		connected bool
+	}{
+		{
+			name:      "Valid",
+			secrets:   validSecrets,
+			connected: true,
+		},
+		{
+			name: "MissingUser",
+			secrets: map[string]string{
+				utils.RealmConnectionContext.RealmAddress: "10.0.0.1",
+				utils.RealmConnectionContext.Password:     "secret",
+			},
+			wantCodes: []string{SecretReasonInvalid},
+		},
+		{
+			name:      "Rejected",
+			secrets:   validSecrets,
+			verifyErr: fmt.Errorf("%w: unable to authenticate", pancli.ErrorUnauthenticated),
+			wantCodes: []string{SecretReasonUnauthenticated},
+			connected: true,
+		},
+		{
+			name:      "Unreachable",
+			secrets:   validSecrets,
+			verifyErr: fmt.Errorf("%w: connection refused", pancli.ErrorUnavailable),
+			wantCodes: []string{SecretReasonUnavailable},
+			connected: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			connected := false
+			got := VerifySecret(tc.secrets, credentialVerifierFunc(func(map[string]string) error {
+				connected = true
+				return tc.verifyErr
+			}))
+
+			var codes []string
+			for _, r := range got.Reasons {
+				codes = append(codes, r.Code)
+				assert.NotEmpty(t, r.Message)
+			}
+			assert.Equal(t, tc.wantCodes, codes)
+			assert.Equal(t, len(tc.wantCodes) == 0, got.Valid)
+			assert.Equal(t, tc.connected, connected)
+		})
+	}
+}
+
+// TestParseSecretManifest verifies that data and stringData of a Secret manifest are merged.
+func TestParseSecretManifest(t *testing.T) {
+	manifest := `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: csi-panfs-realm
+data:
+  user: YWRtaW4=
+  password: b2xk
+stringData:
+  password: new
+  realm_ip: 10.0.0.1
+`
+	secrets, err := ParseSecretManifest(strings.NewReader(manifest))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"user": "admin", "password": "new", "realm_ip": "10.0.0.1"}, secrets)
+
+	_, err = ParseSecretManifest(strings.NewReader("apiVersion: v1\nkind: ConfigMap\n"))
+	assert.ErrorContains(t, err, `manifest kind is "ConfigMap"`)
+}
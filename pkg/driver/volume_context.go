@@ -0,0 +1,113 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"slices"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// DefaultMaxVolumeContextSize is the default limit in bytes of the volume context returned
+// by CreateVolume, counted as the total length of all keys and values.
+const DefaultMaxVolumeContextSize = 4096
+
+// volumeContextKeys lists the keys which may be returned in the volume context, in order of
+// priority: keys are dropped from the end of the list if the size limit is exceeded.
+var volumeContextKeys = []string{
+	utils.VolumeParameters.GetSCKey("realm"),
+	utils.VolumeNameContextKey,
+	utils.VolumeParameters.GetSCKey("encryption"),
+	utils.VolumeParameters.GetSCKey("profile"),
+	utils.VolumeParameters.GetSCKey("cacheMode"),
+	utils.VolumeParameters.GetSCKey("verifyMount"),
+	utils.VolumeParameters.GetSCKey("targetDirMode"),
+	utils.VolumeParameters.GetSCKey("targetDirUID"),
+	utils.VolumeParameters.GetSCKey("targetDirGID"),
+	utils.HardQuotaDegradedContextKey,
+	utils.VolumeParameters.GetSCKey("protectionTier"),
+}
+
+// nodeParameters lists the storage class parameters applied by the node plugin, and the
+// original name of volumes with a mapped name, which are passed to it in the volume context.
+var nodeParameters = []string{
+	utils.VolumeParameters.GetSCKey("realm"),
+	utils.VolumeNameContextKey,
+	utils.VolumeParameters.GetSCKey("profile"),
+	utils.VolumeParameters.GetSCKey("cacheMode"),
+	utils.VolumeParameters.GetSCKey("verifyMount"),
+	utils.VolumeParameters.GetSCKey("targetDirMode"),
+	utils.VolumeParameters.GetSCKey("targetDirUID"),
+	utils.VolumeParameters.GetSCKey("targetDirGID"),
+}
+
+// WithMaxVolumeContextSize limits the size of the volume context stored in PersistentVolume
+// objects. A zero or negative size disables the limit.
+//
+// Parameters:
+//
+//	size - The maximum total length of all keys and values in bytes.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithMaxVolumeContextSize(size int) Option {
+	return func(d *Driver) {
+		d.maxVolumeContextSize = size
+	}
+}
+
+// volumeContext builds the volume context of a volume, including the storage class
+// parameters needed by the node plugin. Only allow-listed keys are returned, within the
+// configured size limit; dropped keys are logged.
+//
+// Parameters:
+//
+//	vol        - The PanFS volume.
+//	parameters - The storage class parameters of the request.
+//
+// Returns:
+//
+//	map[string]string - The volume context.
+func (d *Driver) volumeContext(vol *utils.Volume, parameters map[string]string) map[string]string {
+	full := vol.VolumeContext()
+	for _, key := range nodeParameters {
+		if value := parameters[key]; value != "" {
+			full[key] = value
+		}
+	}
+
+	for key := range full {
+		if !slices.Contains(volumeContextKeys, key) {
+			d.log.Info("WARNING: dropping volume context key which is not allowed", "volume_id", vol.Name, "key", key)
+		}
+	}
+
+	ctx := make(map[string]string)
+	size := 0
+	for _, key := range volumeContextKeys {
+		value, ok := full[key]
+		if !ok {
+			continue
+		}
+		if d.maxVolumeContextSize > 0 && size+len(key)+len(value) > d.maxVolumeContextSize {
+			d.log.Info("WARNING: dropping volume context key exceeding the size limit", "volume_id", vol.Name, "key", key, "size", len(key)+len(value), "limit", d.maxVolumeContextSize)
+			continue
+		}
+		ctx[key] = value
+		size += len(key) + len(value)
+	}
+	return ctx
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/klog/v2"
+)
+
+// TestVolumeContextLimit verifies that keys are dropped by priority when the volume context exceeds the size limit.
+func TestVolumeContextLimit(t *testing.T) {
+	encryptionKey := utils.VolumeParameters.GetSCKey("encryption")
+	profileKey := utils.VolumeParameters.GetSCKey("profile")
+
+	vol := &utils.Volume{Name: "vol", Encryption: "on", HardQuotaDegraded: true}
+	params := map[string]string{profileKey: "throughput", "other": "value"}
+
+	full := map[string]string{
+		encryptionKey:                     "on",
+		profileKey:                        "throughput",
+		utils.HardQuotaDegradedContextKey: "true",
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		want  map[string]string
+	}{
+		{
+			name:  "Unlimited",
+			limit: 0,
+			want:  full,
+		},
+		{
+			name:  "Default",
+			limit: DefaultMaxVolumeContextSize,
+			want:  full,
+		},
+		{
+			name:  "LowPriorityKeyDropped",
+			limit: len(encryptionKey) + len("on") + len(profileKey) + len("throughput"),
+			want:  map[string]string{encryptionKey: "on", profileKey: "throughput"},
+		},
+		{
+			name:  "AllDropped",
+			limit: 1,
+			want:  map[string]string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &Driver{log: klog.Background(), maxVolumeContextSize: tc.limit}
+			assert.Equal(t, tc.want, d.volumeContext(vol, params))
+		})
+	}
+}
+
+// TestVolumeContextNodeParameters verifies that storage class parameters applied by the node
+// plugin are passed in the volume context.
+func TestVolumeContextNodeParameters(t *testing.T) {
+	params := map[string]string{
+		utils.VolumeParameters.GetSCKey("cacheMode"):   CacheModeReadOnly,
+		utils.VolumeParameters.GetSCKey("verifyMount"): "true",
+		utils.VolumeParameters.GetSCKey("layout"):      "raid6+",
+	}
+
+	d := &Driver{log: klog.Background()}
+	assert.Equal(t, map[string]string{
+		utils.VolumeParameters.GetSCKey("cacheMode"):   CacheModeReadOnly,
+		utils.VolumeParameters.GetSCKey("verifyMount"): "true",
+	}, d.volumeContext(&utils.Volume{Name: "vol"}, params))
+}
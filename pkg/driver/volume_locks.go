@@ -0,0 +1,64 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"sync"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// volumeLocks tracks the controller operations in progress per volume, so that a retry of the
+// CO racing the original request, or a deletion racing a creation, does not run duplicate
+// realm commands on the same volume. Unlike targetLocks, conflicting requests are rejected
+// instead of waiting, and the CO retries them. The zero value is ready to use.
+type volumeLocks struct {
+	// key is the volume name, the value is the operation in progress.
+	volumes map[string]string
+	sync.Mutex
+}
+
+// tryLock marks an operation on the volume as in progress.
+//
+// Parameters:
+//
+//	volume    - The volume name.
+//	operation - The controller operation, used in the error and as metric label.
+//
+// Returns:
+//
+//	func() - Function ending the operation, nil on error.
+//	error  - codes.Aborted if another operation on the volume is in progress.
+func (v *volumeLocks) tryLock(volume, operation string) (func(), error) {
+	v.Lock()
+	defer v.Unlock()
+
+	if running, ok := v.volumes[volume]; ok {
+		metrics.ControllerVolumeConflicts.WithLabelValues(operation).Inc()
+		return nil, status.Errorf(codes.Aborted, "%s of volume %s is already in progress", running, volume)
+	}
+	if v.volumes == nil {
+		v.volumes = make(map[string]string)
+	}
+	v.volumes[volume] = operation
+
+	return func() {
+		v.Lock()
+		defer v.Unlock()
+		delete(v.volumes, volume)
+	}, nil
+}
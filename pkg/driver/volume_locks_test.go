@@ -0,0 +1,88 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestVolumeLocks verifies that concurrent operations on the same volume are rejected while
+// operations on other volumes proceed.
+func TestVolumeLocks(t *testing.T) {
+	var locks volumeLocks
+	conflicts := testutil.ToFloat64(metrics.ControllerVolumeConflicts.WithLabelValues("delete"))
+
+	unlock, err := locks.tryLock("pvc-1", "create")
+	require.NoError(t, err)
+
+	_, err = locks.tryLock("pvc-1", "delete")
+	assert.Equal(t, codes.Aborted, status.Code(err))
+	assert.ErrorContains(t, err, "create of volume pvc-1 is already in progress")
+	assert.Equal(t, conflicts+1, testutil.ToFloat64(metrics.ControllerVolumeConflicts.WithLabelValues("delete")))
+
+	unlockOther, err := locks.tryLock("pvc-2", "delete")
+	require.NoError(t, err)
+	unlockOther()
+
+	unlock()
+	unlock, err = locks.tryLock("pvc-1", "delete")
+	require.NoError(t, err)
+	unlock()
+	assert.Empty(t, locks.volumes)
+}
+
+// TestControllerVolumeConflict verifies that a DeleteVolume retry racing the original request
+// is rejected with Aborted instead of running a second realm command.
+func TestControllerVolumeConflict(t *testing.T) {
+	d, pancliMock := newSnapshotTestDriver(t)
+	req := &csi.DeleteVolumeRequest{VolumeId: validVolumeName, Secrets: defaultSecrets}
+
+	started, finish := make(chan struct{}), make(chan struct{})
+	pancliMock.EXPECT().DeleteVolume(gomock.Any(), validVolumeName, defaultSecrets).DoAndReturn(
+		func(context.Context, string, map[string]string) error {
+			close(started)
+			<-finish
+			return nil
+		})
+
+	done := make(chan error)
+	go func() {
+		_, err := d.DeleteVolume(t.Context(), req)
+		done <- err
+	}()
+	<-started
+
+	_, err := d.DeleteVolume(t.Context(), req)
+	assert.Equal(t, codes.Aborted, status.Code(err))
+	_, err = d.ControllerExpandVolume(t.Context(), &csi.ControllerExpandVolumeRequest{
+		VolumeId:      validVolumeName,
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 1 << 30},
+		Secrets:       defaultSecrets,
+	})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	close(finish)
+	assert.NoError(t, <-done)
+}
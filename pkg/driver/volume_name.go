@@ -0,0 +1,165 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// DefaultMaxVolumeNameLength is the default maximum length of the names of realm volumes
+// created by the driver.
+const DefaultMaxVolumeNameLength = 64
+
+// volumeNameHashLength is the number of hex digits of the hash appended to mapped names.
+const volumeNameHashLength = 10
+
+// minVolumeNameLength is the smallest maximum length leaving room for a character of the
+// name, the separator and the hash.
+const minVolumeNameLength = volumeNameHashLength + 2
+
+var (
+	// validRealmVolumeName matches the names used for realm volumes without mapping.
+	validRealmVolumeName = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`)
+	// invalidVolumeNameChars matches the characters not allowed in realm volume names.
+	invalidVolumeNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+)
+
+// VolumeNameMapper maps the names of CreateVolume requests to the names of realm volumes.
+// Names which are valid realm volume names within the maximum length, e.g. the pvc-<uuid>
+// names of the provisioner, are used as they are. Other names have their invalid characters
+// replaced by "-" and are shortened, and a hash of the original name is appended, so
+// retried requests map to the same volume and different names do not collide. The realm
+// volume name is the volume id, the original name is kept in the volume context. The zero
+// value uses DefaultMaxVolumeNameLength.
+type VolumeNameMapper struct {
+	maxLength int
+}
+
+// NewVolumeNameMapper validates and returns a mapper of volume names, e.g. as configured by
+// command line flags.
+//
+// Parameters:
+//
+//	maxLength - The maximum length of realm volume names.
+//
+// Returns:
+//
+//	VolumeNameMapper - The mapper.
+//	error            - Error if the maximum length leaves no room for the hash of mapped names.
+func NewVolumeNameMapper(maxLength int) (VolumeNameMapper, error) {
+	if maxLength < minVolumeNameLength {
+		return VolumeNameMapper{}, fmt.Errorf("invalid maximum volume name length %d: must be at least %d", maxLength, minVolumeNameLength)
+	}
+	return VolumeNameMapper{maxLength: maxLength}, nil
+}
+
+// WithVolumeNameMapper sets the mapping of the names of CreateVolume requests to the names
+// of realm volumes, see VolumeNameMapper.
+//
+// Parameters:
+//
+//	mapper - The mapper, e.g. created with NewVolumeNameMapper.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithVolumeNameMapper(mapper VolumeNameMapper) Option {
+	return func(d *Driver) {
+		d.volumeNames = mapper
+	}
+}
+
+// RealmName returns the name of the realm volume of a CreateVolume request.
+//
+// Parameters:
+//
+//	name - The name of the request.
+//
+// Returns:
+//
+//	string - The name itself if it is valid, or the sanitized and shortened name followed
+//	         by "-" and a hash of the name.
+func (m VolumeNameMapper) RealmName(name string) string {
+	maxLength := m.maxLength
+	if maxLength == 0 {
+		maxLength = DefaultMaxVolumeNameLength
+	}
+	if len(name) <= maxLength && validRealmVolumeName.MatchString(name) {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:volumeNameHashLength]
+
+	// the replaced name is ASCII, so it is shortened at character boundaries
+	sanitized := strings.TrimLeft(invalidVolumeNameChars.ReplaceAllString(name, "-"), ".-")
+	if keep := maxLength - volumeNameHashLength - 1; len(sanitized) > keep {
+		sanitized = sanitized[:keep]
+	}
+	if sanitized == "" {
+		return hash
+	}
+	return sanitized + "-" + hash
+}
+
+// OriginalName returns the name of the CreateVolume request of a volume, reversing the
+// mapping of RealmName.
+//
+// Parameters:
+//
+//	volumeID      - The volume id, the name of the realm volume.
+//	volumeContext - The volume context returned by CreateVolume.
+//
+// Returns:
+//
+//	string - The original name kept in the volume context, or the volume id if the name
+//	         was not mapped.
+func (m VolumeNameMapper) OriginalName(volumeID string, volumeContext map[string]string) string {
+	if name := volumeContext[utils.VolumeNameContextKey]; name != "" {
+		return name
+	}
+	return volumeID
+}
+
+// volumeNameParameters returns the parameters of the volume context of a created volume
+// with the original name of the request added if it was mapped to another volume id.
+//
+// Parameters:
+//
+//	parameters - The storage class parameters of the request.
+//	name       - The name of the request.
+//	volumeID   - The volume id of the created volume.
+//
+// Returns:
+//
+//	map[string]string - A copy of the parameters.
+func volumeNameParameters(parameters map[string]string, name, volumeID string) map[string]string {
+	params := maps.Clone(parameters)
+	if params == nil {
+		params = make(map[string]string)
+	}
+	delete(params, utils.VolumeNameContextKey)
+	if name != volumeID {
+		params[utils.VolumeNameContextKey] = name
+	}
+	return params
+}
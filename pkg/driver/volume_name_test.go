@@ -0,0 +1,120 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"k8s.io/klog/v2"
+)
+
+// TestVolumeNameMapperRealmName verifies the mapping of request names to realm volume names.
+func TestVolumeNameMapperRealmName(t *testing.T) {
+	mapper, err := NewVolumeNameMapper(20)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "pvc-1", want: "pvc-1"},
+		{name: "data_v1.2", want: "data_v1.2"},
+		{name: "exactly-twenty-chars", want: "exactly-twenty-chars"},
+		{name: "team/data", want: "team-data-be750b9b90"},
+		{name: "longer-than-twenty-chars", want: "longer-th-f513479095"},
+		{name: ".hidden", want: "hidden-1692419006"},
+		{name: "ünïcode", want: "n-code-b8be8967e4"},
+		{name: "///", want: "732c4e9711"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mapper.RealmName(tt.name)
+			assert.Equal(t, tt.want, got)
+			assert.LessOrEqual(t, len(got), 20)
+			assert.Regexp(t, validRealmVolumeName, got)
+		})
+	}
+
+	t.Run("DefaultLength", func(t *testing.T) {
+		name := "pvc-" + strings.Repeat("a", DefaultMaxVolumeNameLength)
+		got := VolumeNameMapper{}.RealmName(name)
+		assert.Len(t, got, DefaultMaxVolumeNameLength)
+		assert.True(t, strings.HasPrefix(got, "pvc-aaa"))
+	})
+
+	t.Run("SharedPrefixDoesNotCollide", func(t *testing.T) {
+		assert.NotEqual(t, mapper.RealmName("longer-than-twenty-chars-1"), mapper.RealmName("longer-than-twenty-chars-2"))
+	})
+}
+
+// TestNewVolumeNameMapper verifies the validation of the maximum volume name length.
+func TestNewVolumeNameMapper(t *testing.T) {
+	_, err := NewVolumeNameMapper(minVolumeNameLength)
+	assert.NoError(t, err)
+
+	_, err = NewVolumeNameMapper(minVolumeNameLength - 1)
+	assert.ErrorContains(t, err, "must be at least 12")
+}
+
+// TestVolumeNameMapperOriginalName verifies that the original name is read back from the volume context.
+func TestVolumeNameMapperOriginalName(t *testing.T) {
+	mapper := VolumeNameMapper{}
+	assert.Equal(t, "team/data", mapper.OriginalName("team-data-be750b9b90", map[string]string{utils.VolumeNameContextKey: "team/data"}))
+	assert.Equal(t, validVolumeName, mapper.OriginalName(validVolumeName, nil))
+}
+
+// TestCreateVolumeMappedName verifies that volumes with invalid names are created under the
+// mapped name, which is returned as volume id with the original name in the volume context.
+func TestCreateVolumeMappedName(t *testing.T) {
+	pancliMock := mock.NewMockStorageProviderClient(gomock.NewController(t))
+	mapper, err := NewVolumeNameMapper(20)
+	require.NoError(t, err)
+	d := &Driver{Name: DefaultDriverName, panfs: pancliMock, volumeNames: mapper, log: klog.Background()}
+
+	request := func(name string) *csi.CreateVolumeRequest {
+		return &csi.CreateVolumeRequest{
+			Name:               name,
+			Secrets:            defaultSecrets,
+			VolumeCapabilities: []*csi.VolumeCapability{{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}}},
+		}
+	}
+
+	t.Run("Mapped", func(t *testing.T) {
+		pancliMock.EXPECT().CreateVolume(gomock.Any(), "team-data-be750b9b90", gomock.Any(), defaultSecrets).
+			Return(&utils.Volume{Name: "team-data-be750b9b90"}, nil)
+
+		resp, err := d.CreateVolume(t.Context(), request("team/data"))
+		require.NoError(t, err)
+		assert.Equal(t, "team-data-be750b9b90", resp.Volume.VolumeId)
+		assert.Equal(t, "team/data", resp.Volume.VolumeContext[utils.VolumeNameContextKey])
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		pancliMock.EXPECT().CreateVolume(gomock.Any(), "pvc-1", gomock.Any(), defaultSecrets).
+			Return(&utils.Volume{Name: "pvc-1"}, nil)
+
+		resp, err := d.CreateVolume(t.Context(), request("pvc-1"))
+		require.NoError(t, err)
+		assert.Equal(t, "pvc-1", resp.Volume.VolumeId)
+		assert.NotContains(t, resp.Volume.VolumeContext, utils.VolumeNameContextKey)
+	})
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// VolumeStatsProvider is implemented by storage provider clients reading the performance
+// counters of the volumes of a realm, e.g. the pancli client.
+type VolumeStatsProvider interface {
+	GetVolumeStats(ctx context.Context, secret map[string]string) (*utils.VolumeStatsList, error)
+}
+
+var (
+	volumeReadBytesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "volume", "read_bytes_total"),
+		"Bytes read from the realm volume, as counted by the realm.",
+		[]string{"realm", "volume_id"}, nil,
+	)
+	volumeWriteBytesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "volume", "write_bytes_total"),
+		"Bytes written to the realm volume, as counted by the realm.",
+		[]string{"realm", "volume_id"}, nil,
+	)
+	volumeReadOpsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "volume", "read_operations_total"),
+		"Read operations on the realm volume, as counted by the realm.",
+		[]string{"realm", "volume_id"}, nil,
+	)
+	volumeWriteOpsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "volume", "write_operations_total"),
+		"Write operations on the realm volume, as counted by the realm.",
+		[]string{"realm", "volume_id"}, nil,
+	)
+	volumeReadLatencyDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "volume", "read_latency_seconds"),
+		"Average latency of read operations on the realm volume over the sampling interval of the realm.",
+		[]string{"realm", "volume_id"}, nil,
+	)
+	volumeWriteLatencyDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "volume", "write_latency_seconds"),
+		"Average latency of write operations on the realm volume over the sampling interval of the realm.",
+		[]string{"realm", "volume_id"}, nil,
+	)
+)
+
+// volumeStatsCollector periodically reads the performance counters of the realm volumes and
+// exposes the last values read as Prometheus metrics. The zero value is disabled.
+type volumeStatsCollector struct {
+	interval time.Duration
+	// stats are the counters of the last poll, key is the realm address
+	stats  map[string][]utils.VolumeStats
+	cancel context.CancelFunc
+	sync.Mutex
+}
+
+// WithVolumeStats enables the export of per-volume read and write throughput and latency
+// metrics, read from the performance counters of the realms with the pasxml volumestats
+// command. The realms are the realms of the realm registry (see WithRealmRegistry), or the
+// realm of the credentials of requests without secrets (see WithDefaultCredentials and
+// WithDefaultSecret). Realms without performance counters are skipped.
+//
+// Parameters:
+//
+//	interval - The interval between reads of the counters. Zero or negative disables the metrics.
+//
+// Returns:
+//
+//	Option - The driver option.
+func WithVolumeStats(interval time.Duration) Option {
+	return func(d *Driver) {
+		d.volumeStats.interval = interval
+	}
+}
+
+// startVolumeStatsCollector registers the per-volume metrics and starts polling the realms,
+// if enabled and not started yet.
+func (d *Driver) startVolumeStatsCollector() {
+	if d.volumeStats.interval <= 0 {
+		return
+	}
+	provider, ok := d.panfs.(VolumeStatsProvider)
+	if !ok {
+		d.log.Info("WARNING: the realm provider does not read volume performance counters, volume metrics are disabled")
+		return
+	}
+
+	d.volumeStats.Lock()
+	defer d.volumeStats.Unlock()
+	if d.volumeStats.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d.volumeStats.cancel = cancel
+
+	err := metrics.Registry.Register(&d.volumeStats)
+	var already prometheus.AlreadyRegisteredError
+	if err != nil && !errors.As(err, &already) {
+		d.log.Error(err, "failed to register volume metrics")
+	}
+
+	go func() {
+		ticker := time.NewTicker(d.volumeStats.interval)
+		defer ticker.Stop()
+
+		for {
+			d.pollVolumeStats(ctx, provider)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// stopVolumeStatsCollector stops polling the realms.
+func (d *Driver) stopVolumeStatsCollector() {
+	d.volumeStats.Lock()
+	defer d.volumeStats.Unlock()
+	if d.volumeStats.cancel != nil {
+		d.volumeStats.cancel()
+		d.volumeStats.cancel = nil
+	}
+}
+
+// pollVolumeStats reads the performance counters of the volumes of all realms. The counters
+// of realms which cannot be read are dropped, so stale values are not exported.
+//
+// Parameters:
+//
+//	ctx      - The context of the collector, cancelled when it stops.
+//	provider - The client reading the counters.
+func (d *Driver) pollVolumeStats(ctx context.Context, provider VolumeStatsProvider) {
+	log := d.log.WithValues("component", "volume-stats")
+	ctx, cancel := context.WithTimeout(ctx, d.volumeStats.interval)
+	defer cancel()
+
+	stats := make(map[string][]utils.VolumeStats)
+	for _, parameters := range d.volumeStatsRealms() {
+		secrets, err := d.defaultSecrets(ctx, parameters)
+		if err != nil {
+			log.Error(err, "failed to resolve realm credentials", "realm", parameters[utils.VolumeParameters.GetSCKey("realm")])
+			continue
+		}
+		address := secrets[utils.RealmConnectionContext.RealmAddress]
+
+		release, err := d.realmLimiter.acquire(ctx, address, priorityBackground)
+		if err != nil {
+			log.V(4).Info("realm session slots are busy, skipping volume performance counters", "realm", address)
+			continue
+		}
+		list, err := provider.GetVolumeStats(ctx, secrets)
+		release()
+		switch {
+		case pancli.IsUnsupportedCommand(err):
+			log.V(4).Info("realm does not expose volume performance counters", "realm", address)
+		case err != nil:
+			log.Error(err, "failed to read volume performance counters", "realm", address)
+		default:
+			stats[address] = list.Volumes
+		}
+	}
+
+	d.volumeStats.Lock()
+	d.volumeStats.stats = stats
+	d.volumeStats.Unlock()
+}
+
+// volumeStatsRealms returns the storage class parameters selecting each realm polled for
+// performance counters: one per realm of the realm registry, or the default realm.
+func (d *Driver) volumeStatsRealms() []map[string]string {
+	if d.realms == nil {
+		return []map[string]string{nil}
+	}
+	names := d.realms.Names()
+	realms := make([]map[string]string, 0, len(names))
+	for _, name := range names {
+		realms = append(realms, map[string]string{utils.VolumeParameters.GetSCKey("realm"): name})
+	}
+	return realms
+}
+
+// Describe implements prometheus.Collector.
+func (c *volumeStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- volumeReadBytesDesc
+	ch <- volumeWriteBytesDesc
+	ch <- volumeReadOpsDesc
+	ch <- volumeWriteOpsDesc
+	ch <- volumeReadLatencyDesc
+	ch <- volumeWriteLatencyDesc
+}
+
+// Collect implements prometheus.Collector. The counters of the last poll are exported.
+func (c *volumeStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.Lock()
+	defer c.Unlock()
+
+	for realm, volumes := range c.stats {
+		for _, v := range volumes {
+			name := string(v.Name)
+			ch <- prometheus.MustNewConstMetric(volumeReadBytesDesc, prometheus.CounterValue, v.ReadBytes, realm, name)
+			ch <- prometheus.MustNewConstMetric(volumeWriteBytesDesc, prometheus.CounterValue, v.WriteBytes, realm, name)
+			ch <- prometheus.MustNewConstMetric(volumeReadOpsDesc, prometheus.CounterValue, v.ReadOps, realm, name)
+			ch <- prometheus.MustNewConstMetric(volumeWriteOpsDesc, prometheus.CounterValue, v.WriteOps, realm, name)
+			ch <- prometheus.MustNewConstMetric(volumeReadLatencyDesc, prometheus.GaugeValue, v.ReadLatencyUs/1e6, realm, name)
+			ch <- prometheus.MustNewConstMetric(volumeWriteLatencyDesc, prometheus.GaugeValue, v.WriteLatencyUs/1e6, realm, name)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/driver/mock"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"k8s.io/klog/v2"
+)
+
+// fakeVolumeStatsProvider is a storage provider client returning the volume performance
+// counters of each realm address.
+type fakeVolumeStatsProvider struct {
+	*mock.MockStorageProviderClient
+	stats map[string]*utils.VolumeStatsList
+}
+
+// GetVolumeStats implements VolumeStatsProvider.
+func (p *fakeVolumeStatsProvider) GetVolumeStats(_ context.Context, secrets map[string]string) (*utils.VolumeStatsList, error) {
+	address := secrets[utils.RealmConnectionContext.RealmAddress]
+	if stats, ok := p.stats[address]; ok {
+		return stats, nil
+	}
+	return nil, fmt.Errorf("%w: volumestats", pancli.ErrorNotImplemented)
+}
+
+// TestPollVolumeStats verifies that the counters of each realm of the registry are exported,
+// skipping realms without performance counters.
+func TestPollVolumeStats(t *testing.T) {
+	provider := &fakeVolumeStatsProvider{
+		MockStorageProviderClient: mock.NewMockStorageProviderClient(gomock.NewController(t)),
+		stats: map[string]*utils.VolumeStatsList{
+			"realm-a.example.com": {Volumes: []utils.VolumeStats{{
+				Name:           "pvc-1",
+				ReadBytes:      4096,
+				WriteBytes:     8192,
+				ReadOps:        1,
+				WriteOps:       2,
+				ReadLatencyUs:  500,
+				WriteLatencyUs: 1500,
+			}}},
+		},
+	}
+	d := &Driver{log: klog.Background(), panfs: provider}
+	WithRealmRegistry(newTestRealmRegistry(t))(d)
+	WithCredentialProvider(&staticCredentials{values: map[string]map[string]string{
+		"b": {utils.RealmConnectionContext.RealmAddress: "realm-b.example.com"},
+	}}, 0)(d)
+	WithVolumeStats(time.Minute)(d)
+
+	d.pollVolumeStats(t.Context(), provider)
+
+	expected := `
+# HELP panfs_csi_volume_read_bytes_total Bytes read from the realm volume, as counted by the realm.
+# TYPE panfs_csi_volume_read_bytes_total counter
+panfs_csi_volume_read_bytes_total{realm="realm-a.example.com",volume_id="pvc-1"} 4096
+# HELP panfs_csi_volume_write_latency_seconds Average latency of write operations on the realm volume over the sampling interval of the realm.
+# TYPE panfs_csi_volume_write_latency_seconds gauge
+panfs_csi_volume_write_latency_seconds{realm="realm-a.example.com",volume_id="pvc-1"} 0.0015
+`
+	assert.Equal(t, 6, testutil.CollectAndCount(&d.volumeStats))
+	assert.NoError(t, testutil.CollectAndCompare(&d.volumeStats, strings.NewReader(expected),
+		"panfs_csi_volume_read_bytes_total", "panfs_csi_volume_write_latency_seconds"))
+
+	// counters of realms which can no longer be read are dropped
+	delete(provider.stats, "realm-a.example.com")
+	d.pollVolumeStats(t.Context(), provider)
+	assert.Equal(t, 0, testutil.CollectAndCount(&d.volumeStats))
+}
+
+// TestStartVolumeStatsCollector verifies that the collector only starts if enabled and
+// supported by the realm provider.
+func TestStartVolumeStatsCollector(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		d := &Driver{log: klog.Background(), panfs: &fakeVolumeStatsProvider{}}
+		d.startVolumeStatsCollector()
+		assert.Nil(t, d.volumeStats.cancel)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		d := &Driver{log: klog.Background(), panfs: mock.NewMockStorageProviderClient(gomock.NewController(t))}
+		WithVolumeStats(time.Minute)(d)
+		d.startVolumeStatsCollector()
+		assert.Nil(t, d.volumeStats.cancel)
+	})
+
+	t.Run("Started", func(t *testing.T) {
+		d := &Driver{log: klog.Background(), panfs: &fakeVolumeStatsProvider{}}
+		WithVolumeStats(time.Minute)(d)
+		d.startVolumeStatsCollector()
+		assert.NotNil(t, d.volumeStats.cancel)
+		d.stopVolumeStatsCollector()
+		assert.Nil(t, d.volumeStats.cancel)
+	})
+}
@@ -0,0 +1,21 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interfaces
+
+// The mocks of the interfaces, regenerated with "make generate" and verified to be current by
+// TestGeneratedMocks ("make generate-verify").
+//go:generate go run go.uber.org/mock/mockgen -write_command_comment=false -destination=../driver/mock/mock_driver.go -package=mock . StorageProviderClient,PanMounter
+//go:generate go run go.uber.org/mock/mockgen -write_command_comment=false -destination=../driver/mock/mock_kmounter.go -package=mock . KMounter
+//go:generate go run go.uber.org/mock/mockgen -write_command_comment=false -destination=../pancli/mock/mock_runner.go -package=mock . SSHRunner
@@ -0,0 +1,86 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interfaces
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generateDirective is the prefix of the go:generate directives of generate.go.
+const generateDirective = "//go:generate "
+
+// generateCommands returns the commands of the go:generate directives of generate.go.
+func generateCommands(t *testing.T) [][]string {
+	t.Helper()
+
+	file, err := os.Open("generate.go")
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	var commands [][]string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line, ok := strings.CutPrefix(scanner.Text(), generateDirective); ok {
+			commands = append(commands, strings.Fields(line))
+		}
+	}
+	require.NoError(t, scanner.Err())
+	require.NotEmpty(t, commands, "no go:generate directives in generate.go")
+	return commands
+}
+
+// TestGeneratedMocks regenerates the mocks into a temporary directory and fails if the
+// checked in mocks differ, e.g. after an interface changed without running "make generate".
+func TestGeneratedMocks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping mock generation in short mode")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not available")
+	}
+
+	for _, command := range generateCommands(t) {
+		var destination, generated string
+		args := make([]string, 0, len(command))
+		for _, arg := range command {
+			if value, ok := strings.CutPrefix(arg, "-destination="); ok {
+				destination = value
+				generated = filepath.Join(t.TempDir(), filepath.Base(value))
+				arg = "-destination=" + generated
+			}
+			args = append(args, arg)
+		}
+		require.NotEmpty(t, destination, "go:generate directive without -destination: %v", command)
+
+		t.Run(filepath.Base(destination), func(t *testing.T) {
+			output, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+			require.NoError(t, err, "%s", output)
+
+			want, err := os.ReadFile(destination)
+			require.NoError(t, err)
+			got, err := os.ReadFile(generated)
+			require.NoError(t, err)
+			assert.Equal(t, string(got), string(want), "%s is out of date, run \"make generate\"", destination)
+		})
+	}
+}
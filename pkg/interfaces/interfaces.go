@@ -0,0 +1,62 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package interfaces defines the interfaces of the driver replaced by mocks in tests. The
+// mocks of all interfaces are generated from this package by the go:generate directives of
+// generate.go, so the interfaces and their mocks cannot drift apart.
+package interfaces
+
+import (
+	"context"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"k8s.io/mount-utils"
+)
+
+// StorageProviderClient defines an interface for managing volumes with a storage provider.
+// Calls still running on the realm when the context is done are aborted.
+type StorageProviderClient interface {
+	CreateVolume(ctx context.Context, volumeName string, params pancli.VolumeCreateParams, secret map[string]string) (*utils.Volume, error)
+	DeleteVolume(ctx context.Context, volID string, secret map[string]string) error
+	ExpandVolume(ctx context.Context, volumeName string, targetSize int64, secret map[string]string) error
+	ModifyVolume(ctx context.Context, volumeName string, params pancli.VolumeModifyParams, secret map[string]string) error
+	ListVolumes(ctx context.Context, secret map[string]string) (*utils.VolumeList, error)
+	GetVolume(ctx context.Context, volumeName string, secret map[string]string) (*utils.Volume, error)
+	CreateSnapshot(ctx context.Context, volumeName, snapshotName string, secret map[string]string) (*utils.Snapshot, error)
+	DeleteSnapshot(ctx context.Context, volumeName, snapshotName string, secret map[string]string) error
+	ListSnapshots(ctx context.Context, volumeName string, secret map[string]string) (*utils.SnapshotList, error)
+	CreateVolumeFromSnapshot(ctx context.Context, volumeName, sourceVolume, snapshotName string, params pancli.VolumeCreateParams, secret map[string]string) (*utils.Volume, error)
+	CreateDirectory(ctx context.Context, volumeName, directory string, hardBytes int64, secret map[string]string) (*utils.Directory, error)
+	DeleteDirectory(ctx context.Context, volumeName, directory string, secret map[string]string) error
+	SetDirectoryQuota(ctx context.Context, volumeName, directory string, hardBytes int64, secret map[string]string) error
+	GetDirectory(ctx context.Context, volumeName, directory string, secret map[string]string) (*utils.Directory, error)
+	GetCapacity(ctx context.Context, bladeset string, secret map[string]string) (int64, error)
+	GetRealmFeatures(ctx context.Context, secret map[string]string) (*utils.RealmFeatures, error)
+}
+
+// PanMounter defines the interface for mounting and unmounting PanFS volumes. The context
+// carries the logger of the request the mount operation is run for.
+type PanMounter interface {
+	Mount(ctx context.Context, source string, target string, options []string) error
+	BindMount(ctx context.Context, source string, target string, options []string) error
+	Unmount(ctx context.Context, target string) error
+	IsMountPoint(ctx context.Context, target string) (bool, error)
+	Remount(ctx context.Context, target string) error
+}
+
+// KMounter is the Kubernetes mounter used by PanFSMounter to run mount commands.
+type KMounter interface {
+	mount.Interface
+}
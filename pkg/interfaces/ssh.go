@@ -0,0 +1,24 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package interfaces
+
+import "github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli"
+
+// SSHRunner defines an interface for running commands over SSH. It is declared by the pancli
+// package, which uses it and cannot import this package, and is aliased here for generating
+// its mock with the mocks of the other interfaces.
+type SSHRunner = pancli.SSHRunner
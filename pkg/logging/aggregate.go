@@ -0,0 +1,241 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logging provides logging helpers shared by the controller and node plugins.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/klog/v2"
+)
+
+// DefaultAggregationWindow is the default window in which repeated errors are collapsed.
+// Aggregation is disabled by default.
+const DefaultAggregationWindow time.Duration = 0
+
+// requestIDKey is the log key of the request ID. It differs for every request, so it is
+// left out when comparing errors.
+const requestIDKey = "request_id"
+
+// Aggregator collapses repeated error log lines. The first occurrence of an error is logged
+// immediately, repeats within the window are counted and logged as a single summary
+// "<message> (repeated N times)" once the window has passed. Errors are repeats if their
+// message, error and key/value pairs, except the request ID, are equal.
+type Aggregator struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	repeats map[string]*repeatedError
+}
+
+// repeatedError tracks the repeats of an error within the current window.
+type repeatedError struct {
+	start         time.Time
+	count         int
+	sink          logr.LogSink
+	err           error
+	msg           string
+	keysAndValues []any
+}
+
+// NewAggregator creates an Aggregator. A zero or negative window disables aggregation.
+//
+// Parameters:
+//
+//	window - The window in which repeated errors are collapsed.
+//
+// Returns:
+//
+//	*Aggregator - The initialized aggregator.
+func NewAggregator(window time.Duration) *Aggregator {
+	return &Aggregator{
+		window:  window,
+		now:     time.Now,
+		repeats: make(map[string]*repeatedError),
+	}
+}
+
+// Wrap returns a logger whose errors are aggregated. Loggers derived from it with
+// WithValues or WithName share the aggregation state.
+//
+// Parameters:
+//
+//	base - The logger to write to.
+//
+// Returns:
+//
+//	klog.Logger - The aggregating logger, or base if aggregation is disabled.
+func (a *Aggregator) Wrap(base klog.Logger) klog.Logger {
+	if a.window <= 0 || base.GetSink() == nil {
+		return base
+	}
+	// the wrapping sink adds a frame between the caller and the base sink
+	return logr.New(&aggregatingSink{sink: base.WithCallDepth(1).GetSink(), aggregator: a})
+}
+
+// Run periodically logs the summaries of errors whose window has passed, until the context is done.
+//
+// Parameters:
+//
+//	ctx - The context controlling the lifetime of the flush loop.
+func (a *Aggregator) Run(ctx context.Context) {
+	if a.window <= 0 {
+		return
+	}
+	ticker := time.NewTicker(a.window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			a.Flush()
+			return
+		case <-ticker.C:
+			a.flushExpired()
+		}
+	}
+}
+
+// Flush logs the summaries of all pending repeated errors.
+func (a *Aggregator) Flush() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for key, r := range a.repeats {
+		r.summarize()
+		delete(a.repeats, key)
+	}
+}
+
+// flushExpired logs the summaries of repeated errors whose window has passed.
+func (a *Aggregator) flushExpired() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	for key, r := range a.repeats {
+		if now.Sub(r.start) >= a.window {
+			r.summarize()
+			delete(a.repeats, key)
+		}
+	}
+}
+
+// aggregationKey returns the key identifying repeats of an error.
+//
+// Parameters:
+//
+//	err           - The logged error.
+//	msg           - The logged message.
+//	keysAndValues - The key/value pairs of the logger and of the call.
+//
+// Returns:
+//
+//	string - The key of the error.
+func aggregationKey(err error, msg string, keysAndValues ...[]any) string {
+	var b strings.Builder
+	b.WriteString(msg)
+	if err != nil {
+		b.WriteString("\x00" + err.Error())
+	}
+	for _, kv := range keysAndValues {
+		for i := 0; i+1 < len(kv); i += 2 {
+			if kv[i] == requestIDKey {
+				continue
+			}
+			fmt.Fprintf(&b, "\x00%v=%v", kv[i], kv[i+1])
+		}
+	}
+	return b.String()
+}
+
+// record counts an error and reports whether it should be logged.
+func (a *Aggregator) record(sink logr.LogSink, values []any, err error, msg string, keysAndValues []any) bool {
+	key := aggregationKey(err, msg, values, keysAndValues)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	if r, ok := a.repeats[key]; ok {
+		if now.Sub(r.start) < a.window {
+			r.count++
+			return false
+		}
+		r.summarize()
+	}
+	a.repeats[key] = &repeatedError{start: now, sink: sink, err: err, msg: msg, keysAndValues: keysAndValues}
+	return true
+}
+
+// summarize logs the number of repeats, if any.
+func (r *repeatedError) summarize() {
+	if r.count == 0 {
+		return
+	}
+	r.sink.Error(r.err, fmt.Sprintf("%s (repeated %d times)", r.msg, r.count), r.keysAndValues...)
+}
+
+// aggregatingSink is a logr.LogSink passing errors through an Aggregator.
+type aggregatingSink struct {
+	sink       logr.LogSink
+	aggregator *Aggregator
+	// values are the key/value pairs added with WithValues, part of the aggregation key
+	values []any
+}
+
+// Init implements logr.LogSink. The wrapped sink is already initialized.
+func (s *aggregatingSink) Init(logr.RuntimeInfo) {}
+
+// Enabled implements logr.LogSink.
+func (s *aggregatingSink) Enabled(level int) bool {
+	return s.sink.Enabled(level)
+}
+
+// Info implements logr.LogSink.
+func (s *aggregatingSink) Info(level int, msg string, keysAndValues ...any) {
+	s.sink.Info(level, msg, keysAndValues...)
+}
+
+// Error implements logr.LogSink, dropping repeats of the same error within the window.
+func (s *aggregatingSink) Error(err error, msg string, keysAndValues ...any) {
+	if s.aggregator.record(s.sink, s.values, err, msg, keysAndValues) {
+		s.sink.Error(err, msg, keysAndValues...)
+	}
+}
+
+// WithValues implements logr.LogSink.
+func (s *aggregatingSink) WithValues(keysAndValues ...any) logr.LogSink {
+	values := append(slices.Clip(s.values), keysAndValues...)
+	return &aggregatingSink{sink: s.sink.WithValues(keysAndValues...), aggregator: s.aggregator, values: values}
+}
+
+// WithName implements logr.LogSink.
+func (s *aggregatingSink) WithName(name string) logr.LogSink {
+	return &aggregatingSink{sink: s.sink.WithName(name), aggregator: s.aggregator, values: s.values}
+}
+
+// WithCallDepth implements logr.CallDepthLogSink.
+func (s *aggregatingSink) WithCallDepth(depth int) logr.LogSink {
+	cd, ok := s.sink.(logr.CallDepthLogSink)
+	if !ok {
+		return s
+	}
+	return &aggregatingSink{sink: cd.WithCallDepth(depth), aggregator: s.aggregator, values: s.values}
+}
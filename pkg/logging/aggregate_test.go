@@ -0,0 +1,85 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregator(t *testing.T) {
+	var lines []string
+	base := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{})
+
+	now := time.Unix(0, 0)
+	a := NewAggregator(time.Minute)
+	a.now = func() time.Time { return now }
+	log := a.Wrap(base)
+
+	errRealm := errors.New("connection refused")
+	pvc1 := log.WithValues("volume_id", "pvc-1")
+	for i := range 5 {
+		pvc1.WithValues("request_id", i).Error(errRealm, "failed to create volume")
+	}
+	// errors of other volumes are not repeats
+	log.WithValues("volume_id", "pvc-2").Error(errRealm, "failed to create volume")
+	log.Error(errRealm, "failed to create volume", "volume_id", "pvc-3")
+	log.Error(errors.New("other"), "failed to create volume")
+	log.Info("not aggregated")
+	log.Info("not aggregated")
+
+	assert.Equal(t, []string{
+		`"msg"="failed to create volume" "error"="connection refused" "volume_id"="pvc-1" "request_id"=0`,
+		`"msg"="failed to create volume" "error"="connection refused" "volume_id"="pvc-2"`,
+		`"msg"="failed to create volume" "error"="connection refused" "volume_id"="pvc-3"`,
+		`"msg"="failed to create volume" "error"="other"`,
+		`"level"=0 "msg"="not aggregated"`,
+		`"level"=0 "msg"="not aggregated"`,
+	}, lines)
+
+	// the window has passed, the next occurrence is logged after the summary
+	lines = nil
+	now = now.Add(time.Minute)
+	pvc1.Error(errRealm, "failed to create volume")
+	assert.Equal(t, []string{
+		`"msg"="failed to create volume (repeated 4 times)" "error"="connection refused" "volume_id"="pvc-1" "request_id"=0`,
+		`"msg"="failed to create volume" "error"="connection refused" "volume_id"="pvc-1"`,
+	}, lines)
+
+	// pending repeats are summarized by the flush loop
+	lines = nil
+	pvc1.Error(errRealm, "failed to create volume")
+	now = now.Add(time.Minute)
+	a.flushExpired()
+	assert.Equal(t, []string{
+		`"msg"="failed to create volume (repeated 1 times)" "error"="connection refused" "volume_id"="pvc-1"`,
+	}, lines)
+
+	lines = nil
+	a.Flush()
+	assert.Empty(t, lines)
+}
+
+func TestAggregatorDisabled(t *testing.T) {
+	base := funcr.New(func(string, string) {}, funcr.Options{})
+	assert.Equal(t, base, NewAggregator(0).Wrap(base))
+	assert.Equal(t, base, NewAggregator(DefaultAggregationWindow).Wrap(base))
+}
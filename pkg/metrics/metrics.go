@@ -0,0 +1,379 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics defines the Prometheus metrics exported by the PanFS CSI driver
+// and the HTTP endpoint used to expose them.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace is the common prefix for all metrics exported by the driver.
+const Namespace = "panfs_csi"
+
+// Registry is the Prometheus registry holding all driver metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// PluginInfo is always 1, labeled with the driver name, version and mode of the plugin,
+	// so the metrics of the controller and node plugins can be told apart by joining it.
+	PluginInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "plugin_info",
+			Help:      "Information about the running plugin, by driver name, version and mode (controller, node or all).",
+		},
+		[]string{"driver_name", "version", "mode"},
+	)
+
+	// RPCs counts handled CSI RPCs by method and gRPC status code.
+	RPCs = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "rpcs_total",
+			Help:      "Number of handled CSI RPCs, by method and gRPC status code.",
+		},
+		[]string{"method", "code"},
+	)
+
+	// RPCDuration observes how long CSI RPCs took to handle.
+	RPCDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "rpc_duration_seconds",
+			Help:      "Time taken to handle CSI RPCs, by method.",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+		},
+		[]string{"method"},
+	)
+
+	// RecoveredPanics counts RPC handlers which panicked and failed with codes.Internal.
+	RecoveredPanics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "recovered_panics_total",
+			Help:      "Number of CSI RPC handlers which panicked, by method.",
+		},
+		[]string{"method"},
+	)
+
+	// SlowRPCs counts gRPC calls which took longer than the configured slow RPC threshold.
+	SlowRPCs = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "slow_rpcs_total",
+			Help:      "Number of CSI RPCs which exceeded the slow RPC threshold.",
+		},
+		[]string{"method"},
+	)
+
+	// SLOOperations counts volume operations by whether they completed successfully within the
+	// SLO threshold.
+	SLOOperations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "slo",
+			Name:      "operations_total",
+			Help:      "Number of volume operations by result: within (successful within the SLO threshold), exceeded (successful but slower) or failed.",
+		},
+		[]string{"operation", "result"},
+	)
+
+	// SLORatio is the fraction of volume operations of the rolling SLO window which completed
+	// successfully within the SLO threshold.
+	SLORatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: "slo",
+			Name:      "success_ratio",
+			Help:      "Fraction of volume operations in the rolling SLO window which completed successfully within the SLO threshold, \"all\" for all operations.",
+		},
+		[]string{"operation"},
+	)
+
+	// CreateVolumeVerifyRetries counts volume creations whose details were not readable
+	// right after creation and had to be polled, by the final outcome of the poll.
+	CreateVolumeVerifyRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "create_volume_verify_retries_total",
+			Help:      "Number of volume creations which needed to poll for the created volume, by result.",
+		},
+		[]string{"result"},
+	)
+
+	// MutationOutcomeChecks counts mutating realm commands whose connection failed after the
+	// command was sent, by operation and by the outcome found when checking the realm state.
+	MutationOutcomeChecks = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "mutation_outcome_checks_total",
+			Help:      "Number of mutating realm commands with an unknown outcome, by operation and checked outcome.",
+		},
+		[]string{"operation", "result"},
+	)
+
+	// RealmCommandDuration observes how long pancli commands took on the realm, including
+	// establishing the SSH connection.
+	RealmCommandDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "realm",
+			Name:      "command_duration_seconds",
+			Help:      "Time taken by pancli commands on the realm, by command and result.",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		},
+		[]string{"command", "result"},
+	)
+
+	// RealmCommandRetries counts retries of pancli commands which failed transiently, by command.
+	RealmCommandRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "realm",
+			Name:      "command_retries_total",
+			Help:      "Number of retries of pancli commands which failed transiently, by command.",
+		},
+		[]string{"command"},
+	)
+
+	// RealmCommandsAborted counts pancli commands aborted because their request was cancelled
+	// or exceeded its deadline, by command.
+	RealmCommandsAborted = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "realm",
+			Name:      "commands_aborted_total",
+			Help:      "Number of pancli commands aborted because their request was cancelled or exceeded its deadline, by command.",
+		},
+		[]string{"command"},
+	)
+
+	// RealmSSHConnections is the number of cached SSH connections to realms.
+	RealmSSHConnections = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: "realm",
+			Name:      "ssh_connections",
+			Help:      "Number of cached SSH connections to realms.",
+		},
+	)
+
+	// RealmSSHDials counts the SSH connections opened to realm addresses, by result.
+	RealmSSHDials = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "realm",
+			Name:      "ssh_dials_total",
+			Help:      "Number of SSH connections opened to realm addresses, by result.",
+		},
+		[]string{"result"},
+	)
+
+	// RealmSSHSessionWaits counts realm commands which waited for a free SSH session, by result.
+	RealmSSHSessionWaits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "realm",
+			Name:      "ssh_session_waits_total",
+			Help:      "Number of realm commands which waited for a free SSH session, by result.",
+		},
+		[]string{"result"},
+	)
+
+	// RealmSSHReconnects counts the reconnects of SSH connections lost while idle, by result.
+	RealmSSHReconnects = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "realm",
+			Name:      "ssh_reconnects_total",
+			Help:      "Number of reconnects of SSH connections to realms lost while idle, by result.",
+		},
+		[]string{"result"},
+	)
+
+	// NodeVolumeOperations counts successful publish and unpublish operations of the node plugin per volume.
+	NodeVolumeOperations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "node",
+			Name:      "volume_operations_total",
+			Help:      "Number of successful volume publish and unpublish operations, by volume and operation.",
+		},
+		[]string{"volume_id", "operation"},
+	)
+
+	// NodeMountFailures counts failed mounts and unmounts of the node plugin.
+	NodeMountFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "node",
+			Name:      "mount_failures_total",
+			Help:      "Number of failed mounts and unmounts, by operation.",
+		},
+		[]string{"operation"},
+	)
+
+	// ControllerVolumeConflicts counts controller requests rejected because another request
+	// on the same volume was in progress.
+	ControllerVolumeConflicts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "controller",
+			Name:      "volume_conflicts_total",
+			Help:      "Number of controller requests rejected with Aborted because another request on the same volume was in progress, by operation.",
+		},
+		[]string{"operation"},
+	)
+
+	// NodeTargetLockContentions counts node operations which had to wait for another operation
+	// on the same target path.
+	NodeTargetLockContentions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "node",
+			Name:      "target_lock_contentions_total",
+			Help:      "Number of node operations which waited for another operation on the same target path, by operation.",
+		},
+		[]string{"operation"},
+	)
+
+	// NodeTargetLockWait observes how long contended node operations waited for the target path lock.
+	NodeTargetLockWait = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "node",
+			Name:      "target_lock_wait_seconds",
+			Help:      "Time contended node operations waited for the target path lock, by operation.",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 15, 60},
+		},
+		[]string{"operation"},
+	)
+
+	// NodeUnmountsWaiting is the number of unpublish requests waiting for a free unmount worker.
+	NodeUnmountsWaiting = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: "node",
+			Name:      "unmounts_waiting",
+			Help:      "Number of unpublish requests waiting for a free unmount worker.",
+		},
+	)
+
+	// NodeUnmountsInProgress is the number of unmounts currently running.
+	NodeUnmountsInProgress = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: "node",
+			Name:      "unmounts_in_progress",
+			Help:      "Number of unmounts currently running.",
+		},
+	)
+
+	// NodeUnmountQueueWait observes how long unpublish requests waited for a free unmount worker.
+	NodeUnmountQueueWait = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "node",
+			Name:      "unmount_queue_wait_seconds",
+			Help:      "Time unpublish requests waited for a free unmount worker.",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 15, 60},
+		},
+	)
+
+	// RealmQueueWait observes how long controller requests waited for a free realm session slot.
+	RealmQueueWait = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "realm",
+			Name:      "queue_wait_seconds",
+			Help:      "Time controller requests waited for a free realm session slot, by realm.",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"realm"},
+	)
+
+	// RealmBackgroundDeferrals counts free realm session slots handed to foreground requests
+	// while background requests were waiting.
+	RealmBackgroundDeferrals = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "realm",
+			Name:      "background_deferrals_total",
+			Help:      "Number of realm session slots given to foreground requests while background requests were waiting, by realm.",
+		},
+		[]string{"realm"},
+	)
+
+	// RealmQueueRejections counts controller requests failed because no realm session slot
+	// became free in time.
+	RealmQueueRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "realm",
+			Name:      "queue_rejections_total",
+			Help:      "Number of controller requests rejected because all realm session slots were busy, by realm.",
+		},
+		[]string{"realm"},
+	)
+)
+
+func init() {
+	Registry.MustRegister(PluginInfo, RPCs, RPCDuration, RecoveredPanics, SlowRPCs, SLOOperations, SLORatio, CreateVolumeVerifyRetries, MutationOutcomeChecks,
+		RealmCommandDuration, RealmCommandRetries, RealmCommandsAborted, RealmSSHConnections, RealmSSHDials, RealmSSHSessionWaits, RealmSSHReconnects,
+		ControllerVolumeConflicts, NodeVolumeOperations, NodeMountFailures,
+		NodeTargetLockContentions, NodeTargetLockWait, NodeUnmountsWaiting, NodeUnmountsInProgress,
+		NodeUnmountQueueWait, RealmQueueWait, RealmBackgroundDeferrals, RealmQueueRejections)
+}
+
+// Handler returns an HTTP handler serving the driver metrics in Prometheus format.
+//
+// Returns:
+//
+//	http.Handler - The metrics handler.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// Route is an additional HTTP handler served next to the metrics, e.g. a debug endpoint.
+type Route struct {
+	// Pattern is the path pattern of the handler, e.g. "/debug/validate-volumes".
+	Pattern string
+	// Handler serves the requests matching the pattern.
+	Handler http.Handler
+}
+
+// ListenAndServe starts an HTTP server exposing the driver metrics on /metrics and the
+// additional routes. The call blocks until the server fails.
+//
+// Parameters:
+//
+//	address - The TCP address to listen on, e.g. ":9090".
+//	routes  - Additional handlers served by the server.
+//
+// Returns:
+//
+//	error - The error returned by the HTTP server.
+func ListenAndServe(address string, routes ...Route) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	for _, route := range routes {
+		mux.Handle(route.Pattern, route.Handler)
+	}
+	return http.ListenAndServe(address, mux)
+}
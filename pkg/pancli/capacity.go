@@ -0,0 +1,73 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// GetCapacity returns the free space of the realm available for new volumes.
+// Runs the pasxml bladesets command and sums the free space of the matching bladesets.
+//
+// Parameters:
+//
+//	ctx      - The context of the request.
+//	bladeset - The name of the bladeset, empty for all bladesets of the realm.
+//	secrets  - Map of authentication secrets.
+//
+// Returns:
+//
+//	int64 - The free space in bytes.
+//	error - ErrorNotFound if the bladeset does not exist, or other errors if retrieval or parsing fails.
+func (p *PancliSSHClient) GetCapacity(ctx context.Context, bladeset string, secrets map[string]string) (int64, error) {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return 0, err
+	}
+
+	requestLogger(ctx).V(5).Info("GetCapacity executes:", "command", strings.Join([]string{"pasxml", "bladesets"}, " "))
+	out, err := p.pancli.RunCommand(ctx, secrets, "pasxml", "bladesets")
+	if err != nil {
+		return 0, err
+	}
+
+	list, err := utils.ParseListBladesets(out)
+	if err != nil {
+		return 0, fmt.Errorf("GetCapacity: Cannot parse pancli response: %v", err)
+	}
+
+	var available int64
+	found := false
+	for i := range list.Bladesets {
+		b := &list.Bladesets[i]
+		if bladeset != "" && b.Name != bladeset {
+			continue
+		}
+		b.QuotaUnit = unit
+		available += b.GetAvailableBytes()
+		found = true
+	}
+	if bladeset != "" && !found {
+		return 0, fmt.Errorf("%w: bladeset %s", ErrorNotFound, bladeset)
+	}
+
+	return available, nil
+}
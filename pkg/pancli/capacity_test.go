@@ -0,0 +1,70 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli/fake"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const bladesetsPasXML = `<pasxml version="6.0.0">
+    <bladesets>
+        <bladeset id="1">
+            <name>Set 1</name>
+            <totalGB>100.00</totalGB>
+            <availableGB>10.00</availableGB>
+        </bladeset>
+        <bladeset id="2">
+            <name>Set 2</name>
+            <totalGB>100.00</totalGB>
+            <availableGB>5.00</availableGB>
+        </bladeset>
+    </bladesets>
+</pasxml>`
+
+func TestGetCapacity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		bladeset string
+		secrets  map[string]string
+		expected int64
+		err      error
+	}{
+		{name: "AllBladesets", expected: 15 << 30},
+		{name: "Bladeset", bladeset: "Set 2", expected: 5 << 30},
+		{name: "QuotaUnitGB", bladeset: "Set 1", secrets: map[string]string{utils.RealmConnectionContext.QuotaUnit: string(utils.QuotaUnitGB)}, expected: 10e9},
+		{name: "UnknownBladeset", bladeset: "Set 3", err: ErrorNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			runner := fake.NewRunner(t)
+			runner.Expect("pasxml bladesets").Return(bladesetsPasXML, nil)
+
+			available, err := NewPancliSSHClient(runner).GetCapacity(t.Context(), tc.bladeset, tc.secrets)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, available)
+		})
+	}
+}
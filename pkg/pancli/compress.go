@@ -0,0 +1,79 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// gzipMagic is the header of gzip compressed data.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// compressionRequested reports whether the output of realm commands should be compressed.
+func compressionRequested(secrets map[string]string) bool {
+	compress, _ := strconv.ParseBool(secrets[utils.RealmConnectionContext.CompressOutput])
+	return compress
+}
+
+// compressedCommand wraps a realm command so that its combined output is gzip compressed.
+// pipefail keeps the exit status of the command instead of the one of gzip.
+//
+// Parameters:
+//
+//	cmd - The realm command.
+//
+// Returns:
+//
+//	string - The command piping its output through gzip.
+func compressedCommand(cmd string) string {
+	return fmt.Sprintf("set -o pipefail; %s 2>&1 | gzip -c", cmd)
+}
+
+// decompressOutput decompresses the output of a compressed realm command. Output which is not
+// gzip compressed, e.g. a shell error reported before the pipeline ran, is returned as is.
+//
+// Parameters:
+//
+//	output - The raw command output.
+//
+// Returns:
+//
+//	[]byte - The decompressed output.
+//	error  - Error if the compressed output is corrupt.
+func decompressOutput(output []byte) ([]byte, error) {
+	if !bytes.HasPrefix(output, gzipMagic) {
+		return output, nil
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(output))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress command output: %w", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress command output: %w", err)
+	}
+	return decompressed, nil
+}
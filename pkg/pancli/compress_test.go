@@ -0,0 +1,52 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputCompression(t *testing.T) {
+	assert.False(t, compressionRequested(defaultSecrets))
+	assert.True(t, compressionRequested(map[string]string{utils.RealmConnectionContext.CompressOutput: "true"}))
+
+	assert.Equal(t, `set -o pipefail; pancli -x volume list 2>&1 | gzip -c`, compressedCommand("pancli -x volume list"))
+
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	_, err := w.Write([]byte("<pasxml/>"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	output, err := decompressOutput(compressed.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, "<pasxml/>", string(output))
+
+	// plain output, e.g. the shell failing before the pipeline runs
+	output, err = decompressOutput([]byte("sh: gzip: not found"))
+	assert.NoError(t, err)
+	assert.Equal(t, "sh: gzip: not found", string(output))
+
+	_, err = decompressOutput(compressed.Bytes()[:compressed.Len()-4])
+	assert.ErrorContains(t, err, "failed to decompress command output")
+}
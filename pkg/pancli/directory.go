@@ -0,0 +1,177 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// CreateDirectory creates a directory with a directory quota in a volume and returns the
+// created directory object.
+//
+// Parameters:
+//
+//	ctx          - The context of the request.
+//	volumeName   - The name of the volume holding the directory.
+//	directory    - The name of the directory to create.
+//	hardBytes    - The directory quota in bytes.
+//	secrets      - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.Directory - The created directory object.
+//	error            - ErrorAlreadyExist if the volume already has a directory with the name,
+//	                   ErrorNotFound if the volume does not exist, or other errors if creation
+//	                   or retrieval fails.
+func (p *PancliSSHClient) CreateDirectory(ctx context.Context, volumeName, directory string, hardBytes int64, secrets map[string]string) (*utils.Directory, error) {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return nil, err
+	}
+	cmd := []string{"directory", "create", volumeName, directory, "hard", strconv.FormatFloat(unit.FromBytes(hardBytes), 'f', 2, 64)}
+
+	requestLogger(ctx).V(5).Info("CreateDirectory executes:", "command", strings.Join(cmd, " "))
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return nil, err
+	}
+	err = p.runMutation(ctx, secrets, mutation{
+		operation: "CreateDirectory",
+		cmd:       cmd,
+		applied:   p.directoryCreated(ctx, volumeName, directory, secrets),
+	})
+	unlock()
+	if err != nil {
+		return nil, quotaLimitError(err, unit)
+	}
+
+	return p.GetDirectory(ctx, volumeName, directory, secrets)
+}
+
+// DeleteDirectory deletes a directory of a volume with its content.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume holding the directory.
+//	directory  - The name of the directory to delete.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	error - ErrorNotFound if the directory does not exist, or other errors if deletion fails.
+func (p *PancliSSHClient) DeleteDirectory(ctx context.Context, volumeName, directory string, secrets map[string]string) error {
+	cmd := []string{"directory", "delete", "-f", volumeName, directory}
+
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	requestLogger(ctx).V(5).Info("DeleteDirectory executes:", "command", strings.Join(cmd, " "))
+	return p.runMutation(ctx, secrets, mutation{
+		operation: "DeleteDirectory",
+		cmd:       cmd,
+		applied:   p.directoryDeleted(ctx, volumeName, directory, secrets),
+	})
+}
+
+// SetDirectoryQuota changes the directory quota of a directory.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume holding the directory.
+//	directory  - The name of the directory.
+//	hardBytes  - The new directory quota in bytes.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	error - ErrorNotFound if the directory does not exist, a QuotaLimitError if the quota
+//	        exceeds the limits of the realm, or other errors if the change fails.
+func (p *PancliSSHClient) SetDirectoryQuota(ctx context.Context, volumeName, directory string, hardBytes int64, secrets map[string]string) error {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return err
+	}
+	sizeGBStr := strconv.FormatFloat(unit.FromBytes(hardBytes), 'f', 2, 64)
+	cmd := []string{"directory", "set", "hard-quota", volumeName, directory, sizeGBStr}
+
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	requestLogger(ctx).V(5).Info("SetDirectoryQuota executes:", "command", strings.Join(cmd, " "))
+	sizeGB, _ := strconv.ParseFloat(sizeGBStr, 64)
+	err = p.runMutation(ctx, secrets, mutation{
+		operation: "SetDirectoryQuota",
+		cmd:       cmd,
+		applied:   p.directoryQuotaSet(ctx, volumeName, directory, sizeGB, secrets),
+	})
+	return quotaLimitError(err, unit)
+}
+
+// GetDirectory reads the details of a directory of a volume. Runs the pasxml directories
+// command for the volume and picks the directory from the output.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume holding the directory.
+//	directory  - The name of the directory.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.Directory - The directory object.
+//	error            - ErrorNotFound if the volume or directory does not exist, or other
+//	                   errors if retrieval or parsing fails.
+func (p *PancliSSHClient) GetDirectory(ctx context.Context, volumeName, directory string, secrets map[string]string) (*utils.Directory, error) {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := []string{"pasxml", "directories", "volume", volumeName}
+	requestLogger(ctx).V(5).Info("GetDirectory executes:", "command", strings.Join(cmd, " "))
+	out, err := p.pancli.RunCommand(ctx, secrets, cmd...)
+	if err != nil {
+		return nil, err
+	}
+
+	directories, err := utils.ParseListDirectories(out)
+	if err != nil {
+		return nil, fmt.Errorf("GetDirectory: Cannot parse pancli response: %v", err)
+	}
+
+	for i := range directories.Directories {
+		if directories.Directories[i].Name == directory {
+			directories.Directories[i].QuotaUnit = unit
+			return &directories.Directories[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: directory %s of volume %s", ErrorNotFound, directory, volumeName)
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const directoriesPasXML = `<pasxml version="6.0.0">
+    <directories>
+        <directory>
+            <name>pvc-1</name>
+            <volumeName>/validVolumeName</volumeName>
+            <hardQuotaGB>1.00</hardQuotaGB>
+        </directory>
+        <directory>
+            <name>pvc-2</name>
+            <volumeName>/validVolumeName</volumeName>
+            <hardQuotaGB>2.50</hardQuotaGB>
+        </directory>
+    </directories>
+</pasxml>`
+
+func TestCreateDirectory(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("directory create " + validVolumeName + " pvc-2 hard 2.50")
+		runner.Expect("pasxml directories volume "+validVolumeName).Return(directoriesPasXML, nil)
+
+		dir, err := NewPancliSSHClient(runner).CreateDirectory(t.Context(), validVolumeName, "pvc-2", 5<<29, defaultSecrets)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5<<29), dir.GetHardQuotaBytes())
+		assert.Equal(t, validVolumeName+"/pvc-2", dir.VolumeID())
+	})
+
+	t.Run("AlreadyExists", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("directory create "+validVolumeName+" pvc-1 hard 1.00").Return("", fmt.Errorf("%w: directory already exists", ErrorAlreadyExist))
+
+		_, err := NewPancliSSHClient(runner).CreateDirectory(t.Context(), validVolumeName, "pvc-1", 1<<30, defaultSecrets)
+		assert.ErrorIs(t, err, ErrorAlreadyExist)
+	})
+
+	t.Run("QuotaLimit", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("directory create "+validVolumeName+" pvc-3 hard *").Return("", fmt.Errorf("%w: quota exceeds the volume size", ErrorOutOfRange))
+
+		_, err := NewPancliSSHClient(runner).CreateDirectory(t.Context(), validVolumeName, "pvc-3", 1<<40, defaultSecrets)
+		assert.ErrorIs(t, err, ErrorOutOfRange)
+	})
+}
+
+func TestDeleteDirectory(t *testing.T) {
+	runner := fake.NewRunner(t)
+	runner.Expect("directory delete -f " + validVolumeName + " pvc-1")
+	runner.Expect("directory delete -f "+validVolumeName+" pvc-1").Return("", fmt.Errorf("%w: pvc-1", ErrorNotFound))
+
+	panfs := NewPancliSSHClient(runner)
+	assert.NoError(t, panfs.DeleteDirectory(t.Context(), validVolumeName, "pvc-1", defaultSecrets))
+	assert.ErrorIs(t, panfs.DeleteDirectory(t.Context(), validVolumeName, "pvc-1", defaultSecrets), ErrorNotFound)
+}
+
+func TestSetDirectoryQuota(t *testing.T) {
+	runner := fake.NewRunner(t)
+	runner.Expect("directory set hard-quota " + validVolumeName + " pvc-1 3.00")
+
+	assert.NoError(t, NewPancliSSHClient(runner).SetDirectoryQuota(t.Context(), validVolumeName, "pvc-1", 3<<30, defaultSecrets))
+	assert.Equal(t, []string{"directory set hard-quota " + validVolumeName + " pvc-1 3.00"}, runner.Calls())
+}
+
+func TestGetDirectory(t *testing.T) {
+	runner := fake.NewRunner(t)
+	runner.Expect("pasxml directories volume "+validVolumeName).Return(directoriesPasXML, nil)
+	runner.Expect("pasxml directories volume "+validVolumeName).Return(directoriesPasXML, nil)
+	runner.Expect("pasxml directories volume missing").Return("", fmt.Errorf("%w: missing", ErrorNotFound))
+
+	panfs := NewPancliSSHClient(runner)
+	dir, err := panfs.GetDirectory(t.Context(), validVolumeName, "pvc-1", defaultSecrets)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1<<30), dir.GetHardQuotaBytes())
+
+	_, err = panfs.GetDirectory(t.Context(), validVolumeName, "pvc-3", defaultSecrets)
+	assert.ErrorIs(t, err, ErrorNotFound)
+
+	_, err = panfs.GetDirectory(t.Context(), "missing", "pvc-1", defaultSecrets)
+	assert.ErrorIs(t, err, ErrorNotFound)
+}
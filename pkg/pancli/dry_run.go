@@ -0,0 +1,80 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// DryRunRunner is an SSHRunner which runs read-only pasxml commands on the realm, but only
+// records the commands changing the realm state and reports them as successful. A
+// PancliSSHClient using it renders the exact commands of an operation without executing
+// them, e.g. to validate admin automation. DryRunRunner is safe for concurrent use.
+type DryRunRunner struct {
+	runner   SSHRunner
+	commands []string
+	sync.Mutex
+}
+
+// NewDryRunRunner creates a DryRunRunner running the read-only commands with the given runner.
+//
+// Parameters:
+//
+//	runner - The runner executing the read-only commands, e.g. an SSHClient.
+//
+// Returns:
+//
+//	*DryRunRunner - The runner without recorded commands.
+func NewDryRunRunner(runner SSHRunner) *DryRunRunner {
+	return &DryRunRunner{runner: runner}
+}
+
+// RunCommand runs a read-only command, or records any other command without running it.
+//
+// Parameters:
+//
+//	ctx     - The context of the request.
+//	secrets - Map of authentication secrets.
+//	args    - Command-line arguments.
+//
+// Returns:
+//
+//	[]byte - The output of a read-only command, empty for recorded commands.
+//	error  - Error if a read-only command fails.
+func (r *DryRunRunner) RunCommand(ctx context.Context, secrets map[string]string, args ...string) ([]byte, error) {
+	if len(args) > 0 && args[0] == "pasxml" {
+		return r.runner.RunCommand(ctx, secrets, args...)
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	r.commands = append(r.commands, strings.Join(args, " "))
+	return nil, nil
+}
+
+// Commands returns the recorded commands which were not run.
+//
+// Returns:
+//
+//	[]string - The commands as they would be sent to the realm, in order.
+func (r *DryRunRunner) Commands() []string {
+	r.Lock()
+	defer r.Unlock()
+	return append([]string(nil), r.commands...)
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli/fake"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDryRunRunner verifies that only read-only commands reach the realm while the
+// mutating commands of an operation are recorded.
+func TestDryRunRunner(t *testing.T) {
+	volume := &utils.Volume{ID: "372", Name: validVolumeName, State: utils.VolumeStateOnline, Soft: 1}
+	runner := fake.NewRunner(t)
+	runner.Expect("pasxml volumes volume "+validVolumeName).Return(volumePasXML(t, volume), nil)
+
+	dryRun := NewDryRunRunner(runner)
+	panfs := NewPancliSSHClient(dryRun)
+
+	vol, err := panfs.GetVolume(t.Context(), validVolumeName, defaultSecrets)
+	require.NoError(t, err)
+	assert.Equal(t, "372", vol.ID)
+
+	require.NoError(t, panfs.ExpandVolume(t.Context(), validVolumeName, 2<<30, defaultSecrets))
+	require.NoError(t, panfs.DeleteVolume(t.Context(), validVolumeName, defaultSecrets))
+
+	assert.Equal(t, []string{
+		"volume set soft-quota " + validVolumeName + " 2.00",
+		"volume delete -f " + validVolumeName,
+	}, dryRun.Commands())
+}
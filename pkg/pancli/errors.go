@@ -15,11 +15,16 @@
 package pancli
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"regexp"
+	"strconv"
 	"strings"
 	"unicode"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
 )
 
 var (
@@ -37,10 +42,223 @@ var (
 	ErrorUnavailable = errors.New("connection was refused or terminated")
 	// ErrorInternal is returned for internal server errors.
 	ErrorInternal = errors.New("internal server error")
+	// ErrorOutOfRange is returned when a requested size exceeds the limits of the realm or bladeset.
+	ErrorOutOfRange = errors.New("requested size exceeds the limits of the realm")
+	// ErrorOutcomeUnknown is returned when the connection failed after a command was sent, so
+	// the realm may or may not have run it. It is always returned together with ErrorUnavailable.
+	ErrorOutcomeUnknown = errors.New("command was sent but its outcome is unknown")
+)
+
+// QuotaLimitError is returned when a requested quota exceeds the limits of the realm or
+// bladeset, or is below the minimum volume size of the realm. It wraps ErrorOutOfRange and
+// the realm message.
+type QuotaLimitError struct {
+	// MaxBytes is the maximum allowed quota reported by the realm, 0 if it is not reported.
+	MaxBytes int64
+	// MinBytes is the minimum allowed quota reported by the realm, 0 if it is not reported.
+	MinBytes int64
+	// Err is the error parsed from the realm message.
+	Err error
+}
+
+// Error implements error.
+func (e *QuotaLimitError) Error() string {
+	switch {
+	case e.MinBytes != 0:
+		return fmt.Sprintf("%s (minimum %d bytes)", e.Err.Error(), e.MinBytes)
+	case e.MaxBytes != 0:
+		return fmt.Sprintf("%s (maximum %d bytes)", e.Err.Error(), e.MaxBytes)
+	default:
+		return e.Err.Error()
+	}
+}
+
+// Unwrap returns the error parsed from the realm message.
+func (e *QuotaLimitError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorPattern maps a realm message substring to the error it indicates.
+type ErrorPattern struct {
+	// Pattern is matched case-insensitively against the message with whitespace collapsed.
+	Pattern string
+	// Err is the error the message indicates, nil for success messages.
+	Err error
+}
+
+// ErrorPatterns is the ordered table of realm messages recognized by parseErrorString.
+// The first matching pattern wins. Patterns for other locales or realm versions can be
+// added with LoadErrorPatterns.
+var ErrorPatterns = []ErrorPattern{
+	{Pattern: "already exists", Err: ErrorAlreadyExist},
+	{Pattern: "no volume with name", Err: ErrorNotFound},
+	{Pattern: "successfully", Err: nil},
+	{Pattern: "<volumes>", Err: nil},
+	{Pattern: "<snapshots>", Err: nil},
+	{Pattern: "<bladesets>", Err: nil},
+	{Pattern: "<directories>", Err: nil},
+	{Pattern: "<volumeStats>", Err: nil},
+	{Pattern: "do not exist", Err: ErrorNotFound},
+	{Pattern: "exceeds the maximum", Err: ErrorOutOfRange},
+	{Pattern: "exceeds maximum", Err: ErrorOutOfRange},
+	{Pattern: "exceeds the available", Err: ErrorOutOfRange},
+	{Pattern: "larger than the maximum", Err: ErrorOutOfRange},
+	{Pattern: "less than the minimum", Err: ErrorOutOfRange},
+	{Pattern: "smaller than the minimum", Err: ErrorOutOfRange},
+	{Pattern: "below the minimum", Err: ErrorOutOfRange},
+	{Pattern: "must be one of", Err: ErrorInvalidArgument},
+	{Pattern: "invalid string", Err: ErrorInvalidArgument},
+	{Pattern: "should be", Err: ErrorInvalidArgument},
+	{Pattern: "status 255", Err: ErrorUnavailable},
+}
+
+// fallbackPatterns are generic phrases used when neither an error code nor a pattern
+// from ErrorPatterns matches the message.
+var fallbackPatterns = []ErrorPattern{
+	{Pattern: "does not exist", Err: ErrorNotFound},
+	{Pattern: "not found", Err: ErrorNotFound},
+	{Pattern: "permission denied", Err: ErrorUnauthenticated},
+	{Pattern: "authentication failed", Err: ErrorUnauthenticated},
+	{Pattern: "unable to authenticate", Err: ErrorUnauthenticated},
+	{Pattern: "connection refused", Err: ErrorUnavailable},
+	{Pattern: "connection reset", Err: ErrorUnavailable},
+	{Pattern: "timed out", Err: ErrorUnavailable},
+	{Pattern: "invalid", Err: ErrorInvalidArgument},
+}
+
+// errorNames maps the error names used in pattern files to errors.
+var errorNames = map[string]error{
+	"success":          nil,
+	"already_exists":   ErrorAlreadyExist,
+	"not_found":        ErrorNotFound,
+	"invalid_argument": ErrorInvalidArgument,
+	"unauthenticated":  ErrorUnauthenticated,
+	"unavailable":      ErrorUnavailable,
+	"internal":         ErrorInternal,
+	"out_of_range":     ErrorOutOfRange,
+}
+
+// errorCodes maps errno style codes found in structured realm messages to errors.
+var errorCodes = map[string]error{
+	"EEXIST":       ErrorAlreadyExist,
+	"ENOENT":       ErrorNotFound,
+	"EINVAL":       ErrorInvalidArgument,
+	"ERANGE":       ErrorOutOfRange,
+	"EACCES":       ErrorUnauthenticated,
+	"EPERM":        ErrorUnauthenticated,
+	"ECONNREFUSED": ErrorUnavailable,
+	"ETIMEDOUT":    ErrorUnavailable,
+	"EAGAIN":       ErrorUnavailable,
+	"EIO":          ErrorInternal,
+}
+
+var (
+	// errorCodeRegexp extracts an errno style code from a structured message prefix,
+	// e.g. "Error [ENOENT]: ..." or "ENOENT: ...".
+	errorCodeRegexp = regexp.MustCompile(`^\s*(?i:error)?\s*[\[(]?\s*(E[A-Z]{2,})\s*[\])]?\s*:`)
+	// helpLineRegexp matches the help hint at the end of pancli usage errors.
+	helpLineRegexp = regexp.MustCompile(`\s*Use the command "[^"]*" to get more help\.?\s*$`)
+	// trailingForceRegexp matches a trailing ", -f." of pancli usage errors.
+	trailingForceRegexp = regexp.MustCompile(`,\s*-f\.$`)
+	// quotaLimitRegexp extracts the maximum size from limit-exceeded messages, e.g.
+	// "soft quota exceeds the maximum of 1024.00 GB".
+	quotaLimitRegexp = regexp.MustCompile(`(?i)(?:maximum|limit)(?:\s+(?:allowed|size|quota|of|is))*\s*:?\s*([0-9]+(?:\.[0-9]+)?)\s*(T|G)?i?B?\b`)
+	// quotaMinimumRegexp extracts the minimum size from below-minimum messages, e.g.
+	// "soft quota is less than the minimum of 1.00 GB".
+	quotaMinimumRegexp = regexp.MustCompile(`(?i)minimum(?:\s+(?:allowed|size|quota|of|is))*\s*:?\s*([0-9]+(?:\.[0-9]+)?)\s*(T|G)?i?B?\b`)
 )
 
+// LoadErrorPatterns reads additional realm message patterns in JSON format and puts them in
+// front of ErrorPatterns, so they take precedence over the built-in patterns. It is not safe
+// to call concurrently with command execution and is meant to be called on startup.
+//
+// The input is a list of objects, e.g. [{"pattern": "existe déjà", "error": "already_exists"}],
+// where error is one of success, already_exists, not_found, invalid_argument, unauthenticated,
+// unavailable, internal or out_of_range.
+//
+// Parameters:
+//
+//	r - The reader providing the JSON pattern list.
+//
+// Returns:
+//
+//	error - Error if the input cannot be parsed or names an unknown error.
+func LoadErrorPatterns(r io.Reader) error {
+	var entries []struct {
+		Pattern string `json:"pattern"`
+		Error   string `json:"error"`
+	}
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return fmt.Errorf("failed to parse error patterns: %w", err)
+	}
+
+	patterns := make([]ErrorPattern, 0, len(entries)+len(ErrorPatterns))
+	for _, e := range entries {
+		err, ok := errorNames[e.Error]
+		if !ok {
+			return fmt.Errorf("unknown error %q for pattern %q", e.Error, e.Pattern)
+		}
+		if normalizeMessage(e.Pattern) == "" {
+			return fmt.Errorf("empty pattern for error %q", e.Error)
+		}
+		patterns = append(patterns, ErrorPattern{Pattern: e.Pattern, Err: err})
+	}
+
+	ErrorPatterns = append(patterns, ErrorPatterns...)
+	return nil
+}
+
+// normalizeMessage lowercases a message and collapses all whitespace, including
+// non-breaking spaces and newlines, into single spaces.
+func normalizeMessage(msg string) string {
+	return strings.ToLower(strings.Join(strings.Fields(strings.ReplaceAll(msg, "\u00A0", " ")), " "))
+}
+
+// matchPatterns returns the first pattern matching the normalized message.
+func matchPatterns(patterns []ErrorPattern, normalized string) (ErrorPattern, bool) {
+	for _, p := range patterns {
+		if strings.Contains(normalized, normalizeMessage(p.Pattern)) {
+			return p, true
+		}
+	}
+	return ErrorPattern{}, false
+}
+
+// wrapRealmError wraps the realm message into the given error. Invalid argument messages
+// are cleaned up so they can be returned to the user.
+func wrapRealmError(err error, errorStr string) error {
+	if err != ErrorInvalidArgument {
+		return fmt.Errorf("%w: %s", err, errorStr)
+	}
+
+	// Collapse whitespace, remove newlines and non-breaking spaces
+	clean := strings.Join(strings.Fields(strings.ReplaceAll(errorStr, "\u00A0", " ")), " ")
+
+	// Remove help line at the end
+	clean = helpLineRegexp.ReplaceAllString(clean, "")
+
+	// Remove trailing ", -f."
+	clean = trailingForceRegexp.ReplaceAllString(clean, "")
+
+	// Trim spaces
+	clean = strings.TrimSpace(clean)
+
+	if clean == "" {
+		return fmt.Errorf("%w: %s", ErrorInvalidArgument, errorStr)
+	}
+
+	// Capitalize first rune
+	runes := []rune(clean)
+	runes[0] = unicode.ToUpper(runes[0])
+	clean = string(runes)
+
+	return fmt.Errorf("%w: %s", ErrorInvalidArgument, clean)
+}
+
 // parseErrorString parses an error string and returns a corresponding error value.
-// Matches known error patterns and returns specific error types, or nil for success.
+// An errno style code in a structured prefix takes precedence, then the messages in
+// ErrorPatterns are matched, then generic fallback phrases. Matching is case-insensitive
+// and tolerates whitespace differences.
 //
 // Parameters:
 //
@@ -50,53 +268,163 @@ var (
 //
 //	error - The parsed error value, or nil if no error.
 func parseErrorString(errorStr string) error {
-	s := strings.ToLower(errorStr)
-	switch {
-	case strings.Contains(s, "already exists"):
-		return fmt.Errorf("%w: %s", ErrorAlreadyExist, errorStr)
-	case strings.Contains(s, "no volume with name"):
-		return fmt.Errorf("%w: %s", ErrorNotFound, errorStr)
-	case strings.Contains(s, "successfully"):
-		return nil
-	case strings.Contains(s, "<volumes>"):
-		return nil
-	case strings.Contains(s, "do not exist"):
-		return fmt.Errorf("%w: %s", ErrorNotFound, errorStr)
-	//	internal errors
-	case strings.Contains(s, "must be one of"), strings.Contains(s, "invalid string"):
-		// Normalize NBSP -> space, remove newlines
-		reNBSP := regexp.MustCompile("\u00A0")
-		clean := reNBSP.ReplaceAllString(errorStr, " ")
-
-		// Collapse whitespace, remove newlines
-		clean = strings.Join(strings.Fields(clean), " ")
-
-		// Remove help line at the end
-		reHelpLine := regexp.MustCompile(`\s*Use the command "[^"]*" to get more help\.?\s*$`)
-		clean = reHelpLine.ReplaceAllString(clean, "")
-
-		// Remove trailing ", -f."
-		reTrailingF := regexp.MustCompile(`,\s*-f\.$`)
-		clean = reTrailingF.ReplaceAllString(clean, "")
-
-		// Trim spaces
-		clean = strings.TrimSpace(clean)
-
-		if clean == "" {
-			return fmt.Errorf("%w: %s", ErrorInvalidArgument, errorStr)
+	if m := errorCodeRegexp.FindStringSubmatch(errorStr); m != nil {
+		if err, ok := errorCodes[m[1]]; ok {
+			return wrapRealmError(err, errorStr)
 		}
+	}
 
-		// Capitalize first rune
-		runes := []rune(clean)
-		runes[0] = unicode.ToUpper(runes[0])
-		clean = string(runes)
+	s := normalizeMessage(errorStr)
+	if p, ok := matchPatterns(ErrorPatterns, s); ok {
+		if p.Err == nil {
+			return nil
+		}
+		return wrapRealmError(p.Err, errorStr)
+	}
 
-		return fmt.Errorf("%w: %s", ErrorInvalidArgument, clean)
-	case strings.Contains(s, "should be"):
-		return fmt.Errorf("%w: %s", ErrorInvalidArgument, errorStr)
-	case strings.Contains(s, "status 255"):
-		return fmt.Errorf("%w: %s", ErrorUnavailable, errorStr)
-	default:
-		return fmt.Errorf("%w: %s", ErrorInternal, errorStr)
+	if p, ok := matchPatterns(fallbackPatterns, s); ok {
+		return wrapRealmError(p.Err, errorStr)
+	}
+
+	return fmt.Errorf("%w: %s", ErrorInternal, errorStr)
+}
+
+// parseExitError classifies a command which exited with a non-zero status.
+//
+// Parameters:
+//
+//	exitStatus - The exit status of the command.
+//	output     - The combined output of the command.
+//
+// Returns:
+//
+//	error - The parsed error value.
+func parseExitError(exitStatus int, output string) error {
+	// ssh exits with 255 if the connection to the realm fails
+	if exitStatus == 255 {
+		return fmt.Errorf("%w: command exited with status %d: %s", ErrorUnavailable, exitStatus, output)
+	}
+
+	if err := parseErrorString(output); err != nil {
+		return err
+	}
+
+	// the output looks like success, but the exit status does not
+	return fmt.Errorf("%w: command exited with status %d: %s", ErrorInternal, exitStatus, output)
+}
+
+// isHardQuotaUnsupported reports whether a volume creation error indicates that the
+// realm does not support setting a hard quota.
+//
+// Parameters:
+//
+//	err - The error returned by the volume creation command.
+//
+// Returns:
+//
+//	bool - True if the error is caused by the unsupported hard quota parameter.
+func isHardQuotaUnsupported(err error) bool {
+	if err == nil || !errors.Is(err, ErrorInvalidArgument) && !errors.Is(err, ErrorInternal) {
+		return false
+	}
+
+	s := strings.ToLower(err.Error())
+	if !strings.Contains(s, "hard") {
+		return false
+	}
+	for _, pattern := range []string{"not supported", "unsupported", "unknown", "unrecognized", "invalid"} {
+		if strings.Contains(s, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// unsupportedCommandPatterns are phrases of realm messages rejecting a command or object
+// the realm version does not know.
+var unsupportedCommandPatterns = []string{
+	"unknown command", "command not found", "unknown object", "invalid command",
+	"unrecognized", "not supported", "unsupported",
+}
+
+// IsUnsupportedCommand reports whether a command failed because the realm does not support
+// it, e.g. snapshot commands on realm versions without snapshots, or a REST request was
+// rejected as not implemented. Connection and authentication errors are never reported as
+// unsupported.
+//
+// Parameters:
+//
+//	err - The error returned by the command.
+//
+// Returns:
+//
+//	bool - True if the realm does not support the command.
+func IsUnsupportedCommand(err error) bool {
+	if err == nil || errors.Is(err, ErrorUnauthenticated) || errors.Is(err, ErrorUnavailable) {
+		return false
+	}
+	if errors.Is(err, ErrorNotImplemented) {
+		return true
+	}
+
+	s := normalizeMessage(err.Error())
+	for _, pattern := range unsupportedCommandPatterns {
+		if strings.Contains(s, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// quotaLimitError converts an ErrorOutOfRange error into a QuotaLimitError carrying the
+// minimum or maximum size found in the realm message. Sizes without unit or in GB are in the
+// quota unit of the realm, sizes in TB are a thousand or 1024 times the quota unit. Other
+// errors are returned unchanged.
+//
+// Parameters:
+//
+//	err  - The error of the realm command.
+//	unit - The quota unit of the realm.
+//
+// Returns:
+//
+//	error - The QuotaLimitError, or err if it is not an ErrorOutOfRange error.
+func quotaLimitError(err error, unit utils.QuotaUnit) error {
+	if !errors.Is(err, ErrorOutOfRange) {
+		return err
+	}
+
+	limitErr := &QuotaLimitError{Err: err}
+	if m := quotaMinimumRegexp.FindStringSubmatch(err.Error()); m != nil {
+		limitErr.MinBytes = quotaSizeBytes(m[1], m[2], unit)
+	} else if m := quotaLimitRegexp.FindStringSubmatch(err.Error()); m != nil {
+		limitErr.MaxBytes = quotaSizeBytes(m[1], m[2], unit)
+	}
+	return limitErr
+}
+
+// quotaSizeBytes converts a size of a realm message into bytes.
+//
+// Parameters:
+//
+//	value  - The size, e.g. "1024.00".
+//	prefix - The unit prefix of the size, "T" for TB, empty or "G" for the quota unit.
+//	unit   - The quota unit of the realm.
+//
+// Returns:
+//
+//	int64 - The size in bytes, 0 if the value cannot be parsed.
+func quotaSizeBytes(value, prefix string, unit utils.QuotaUnit) int64 {
+	size, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	if strings.EqualFold(prefix, "T") {
+		if unit == utils.QuotaUnitGB {
+			size *= 1000
+		} else {
+			size *= 1024
+		}
 	}
+	return unit.ToBytes(size)
 }
@@ -15,8 +15,13 @@
 package pancli
 
 import (
+	"encoding/json"
 	"errors"
+	"os"
+	"strings"
 	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
 )
 
 // TestParseOutput tests the parseErrorString function.
@@ -68,3 +73,165 @@ func TestParseOutput(t *testing.T) {
 		}
 	}
 }
+
+// TestIsHardQuotaUnsupported tests the isHardQuotaUnsupported function.
+func TestIsHardQuotaUnsupported(t *testing.T) {
+	testCases := []struct {
+		input    error
+		expected bool
+	}{
+		{input: nil, expected: false},
+		{input: parseErrorString("Invalid argument: hard quota is not supported"), expected: true},
+		{input: parseErrorString("Unknown option 'hard'"), expected: true},
+		{input: parseErrorString("Invalid argument: soft should be greater than 0"), expected: false},
+		{input: parseErrorString("Command failed with status 255 (hard)"), expected: false},
+	}
+
+	for _, testCase := range testCases {
+		if actual := isHardQuotaUnsupported(testCase.input); actual != testCase.expected {
+			t.Errorf("Expected %v for %v but got %v", testCase.expected, testCase.input, actual)
+		}
+	}
+}
+
+// TestIsUnsupportedCommand tests the IsUnsupportedCommand function.
+func TestIsUnsupportedCommand(t *testing.T) {
+	testCases := []struct {
+		input    error
+		expected bool
+	}{
+		{input: nil, expected: false},
+		{input: parseErrorString("Unknown command: snapshot"), expected: true},
+		{input: parseErrorString("bash: pasxml: command not found"), expected: true},
+		{input: parseErrorString("Invalid argument: snapshots are not supported"), expected: true},
+		{input: parseErrorString("No volume with name 'pvc-1'"), expected: false},
+		{input: parseExitError(255, "ssh: unsupported cipher"), expected: false},
+	}
+
+	for _, testCase := range testCases {
+		if actual := IsUnsupportedCommand(testCase.input); actual != testCase.expected {
+			t.Errorf("Expected %v for %v but got %v", testCase.expected, testCase.input, actual)
+		}
+	}
+}
+
+// TestParseErrorCorpus classifies the realm messages collected in testdata/realm_errors.json.
+func TestParseErrorCorpus(t *testing.T) {
+	data, err := os.ReadFile("testdata/realm_errors.json")
+	if err != nil {
+		t.Fatalf("failed to read corpus: %v", err)
+	}
+
+	var corpus []struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &corpus); err != nil {
+		t.Fatalf("failed to parse corpus: %v", err)
+	}
+
+	for _, entry := range corpus {
+		expected, ok := errorNames[entry.Error]
+		if !ok {
+			t.Fatalf("unknown error %q in corpus", entry.Error)
+		}
+
+		actual := parseErrorString(entry.Message)
+		if expected == nil && actual != nil || !errors.Is(actual, expected) {
+			t.Errorf("Message %q: expected error: %v but got: %v", entry.Message, expected, actual)
+		}
+	}
+}
+
+// TestInvalidArgumentMessageCleanup verifies that usage errors are cleaned up for users.
+func TestInvalidArgumentMessageCleanup(t *testing.T) {
+	err := parseErrorString("the layout must be one of: raid6+, raid10+,\n -f.\nUse the command \"help volume create\" to get more help.")
+	if err == nil || err.Error() != ErrorInvalidArgument.Error()+": The layout must be one of: raid6+, raid10+" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestLoadErrorPatterns verifies that loaded patterns take precedence over the built-in ones.
+func TestLoadErrorPatterns(t *testing.T) {
+	defaults := ErrorPatterns
+	defer func() { ErrorPatterns = defaults }()
+
+	err := LoadErrorPatterns(strings.NewReader(`[
+		{"pattern": "existe déjà", "error": "already_exists"},
+		{"pattern": "erfolgreich", "error": "success"}
+	]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := parseErrorString("Le volume existe  déjà"); !errors.Is(err, ErrorAlreadyExist) {
+		t.Errorf("Expected error: %v but got: %v", ErrorAlreadyExist, err)
+	}
+	if err := parseErrorString("Volume erfolgreich erstellt"); err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+
+	if err := LoadErrorPatterns(strings.NewReader(`[{"pattern": "x", "error": "unknown"}]`)); err == nil {
+		t.Errorf("Expected error for unknown error name")
+	}
+	if err := LoadErrorPatterns(strings.NewReader(`[{"pattern": " ", "error": "internal"}]`)); err == nil {
+		t.Errorf("Expected error for empty pattern")
+	}
+}
+
+// TestParseExitError tests classification of commands exiting with a non-zero status.
+func TestParseExitError(t *testing.T) {
+	testCases := []struct {
+		status   int
+		output   string
+		expected error
+	}{
+		{status: 255, output: "", expected: ErrorUnavailable},
+		{status: 1, output: "No volume with name 'test'", expected: ErrorNotFound},
+		{status: 1, output: "completed successfully", expected: ErrorInternal},
+	}
+
+	for _, testCase := range testCases {
+		if actual := parseExitError(testCase.status, testCase.output); !errors.Is(actual, testCase.expected) {
+			t.Errorf("Expected error: %v but got: %v", testCase.expected, actual)
+		}
+	}
+}
+
+// TestQuotaLimitError verifies that the maximum size is extracted from limit-exceeded messages.
+func TestQuotaLimitError(t *testing.T) {
+	testCases := []struct {
+		message  string
+		unit     utils.QuotaUnit
+		maxBytes int64
+		minBytes int64
+	}{
+		{message: "Soft quota 2048.00 exceeds the maximum of 1024.00 GB", unit: utils.QuotaUnitGiB, maxBytes: 1024 << 30},
+		{message: "Quota exceeds the maximum allowed size: 1.5 TB", unit: utils.QuotaUnitGiB, maxBytes: 1536 << 30},
+		{message: "Quota exceeds the maximum allowed size: 2 TB", unit: utils.QuotaUnitGB, maxBytes: 2000 * 1e9},
+		{message: "Error [ERANGE]: quota limit is 100", unit: utils.QuotaUnitGiB, maxBytes: 100 << 30},
+		{message: "Requested quota exceeds the available space of bladeset 'Set 1'", unit: utils.QuotaUnitGiB, maxBytes: 0},
+		{message: "Soft quota 0.50 is less than the minimum of 1.00 GB", unit: utils.QuotaUnitGiB, minBytes: 1 << 30},
+		{message: "Quota is below the minimum size: 2 GB", unit: utils.QuotaUnitGB, minBytes: 2 * 1e9},
+	}
+
+	for _, testCase := range testCases {
+		err := quotaLimitError(parseErrorString(testCase.message), testCase.unit)
+
+		var limitErr *QuotaLimitError
+		if !errors.As(err, &limitErr) || !errors.Is(err, ErrorOutOfRange) {
+			t.Errorf("Message %q: expected a quota limit error but got: %v", testCase.message, err)
+			continue
+		}
+		if limitErr.MaxBytes != testCase.maxBytes {
+			t.Errorf("Message %q: expected maximum %d but got: %d", testCase.message, testCase.maxBytes, limitErr.MaxBytes)
+		}
+		if limitErr.MinBytes != testCase.minBytes {
+			t.Errorf("Message %q: expected minimum %d but got: %d", testCase.message, testCase.minBytes, limitErr.MinBytes)
+		}
+	}
+
+	if err := quotaLimitError(ErrorNotFound, utils.QuotaUnitGiB); err != ErrorNotFound {
+		t.Errorf("Expected other errors to be returned unchanged but got: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fake provides a scripted SSHRunner for testing multi-command pancli flows.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Step is a command expected by a Runner and the response to it.
+type Step struct {
+	pattern *regexp.Regexp
+	command string
+	output  []byte
+	err     error
+	times   int
+}
+
+// Return sets the output and error returned for the command.
+//
+// Parameters:
+//
+//	output - The command output.
+//	err    - The command error.
+//
+// Returns:
+//
+//	*Step - The step, for chaining.
+func (s *Step) Return(output string, err error) *Step {
+	s.output = []byte(output)
+	s.err = err
+	return s
+}
+
+// Times sets how many consecutive times the command is expected, 1 by default.
+//
+// Parameters:
+//
+//	n - The number of times the command is expected.
+//
+// Returns:
+//
+//	*Step - The step, for chaining.
+func (s *Step) Times(n int) *Step {
+	s.times = n
+	return s
+}
+
+// Runner is an SSHRunner replaying a script of expected commands in order. Commands are
+// matched against glob patterns of the space-joined arguments, where "*" matches any text,
+// e.g. "volume create pvc-1 *". Unexpected commands and unconsumed steps fail the test.
+// All commands are recorded. Runner is safe for concurrent use.
+type Runner struct {
+	t     testing.TB
+	steps []*Step
+	calls []string
+	sync.Mutex
+}
+
+// NewRunner creates a Runner whose script is verified when the test finishes.
+//
+// Parameters:
+//
+//	t - The test using the runner.
+//
+// Returns:
+//
+//	*Runner - The runner with an empty script.
+func NewRunner(t testing.TB) *Runner {
+	r := &Runner{t: t}
+	t.Cleanup(r.verify)
+	return r
+}
+
+// Expect appends a command to the script. The command returns empty output without error
+// unless set with Return.
+//
+// Parameters:
+//
+//	command - The glob pattern of the space-joined command arguments.
+//
+// Returns:
+//
+//	*Step - The step, to set its response.
+func (r *Runner) Expect(command string) *Step {
+	quoted := regexp.QuoteMeta(command)
+	step := &Step{
+		pattern: regexp.MustCompile("^" + strings.ReplaceAll(quoted, `\*`, ".*") + "$"),
+		command: command,
+		times:   1,
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	r.steps = append(r.steps, step)
+	return step
+}
+
+// RunCommand runs the next step of the script if the command matches it.
+//
+// Parameters:
+//
+//	ctx     - The context of the request, ignored.
+//	secrets - Map of authentication secrets, ignored.
+//	args    - Command-line arguments.
+//
+// Returns:
+//
+//	[]byte - The scripted output.
+//	error  - The scripted error, or an error if the command is not expected.
+func (r *Runner) RunCommand(_ context.Context, secrets map[string]string, args ...string) ([]byte, error) {
+	command := strings.Join(args, " ")
+
+	r.Lock()
+	defer r.Unlock()
+	r.calls = append(r.calls, command)
+
+	if len(r.steps) == 0 {
+		r.t.Errorf("unexpected command %q: script is finished", command)
+		return nil, fmt.Errorf("unexpected command %q", command)
+	}
+
+	step := r.steps[0]
+	if !step.pattern.MatchString(command) {
+		r.t.Errorf("unexpected command %q: expected %q", command, step.command)
+		return nil, fmt.Errorf("unexpected command %q", command)
+	}
+
+	if step.times--; step.times <= 0 {
+		r.steps = r.steps[1:]
+	}
+	return step.output, step.err
+}
+
+// Calls returns the commands run so far, including unexpected ones.
+//
+// Returns:
+//
+//	[]string - The space-joined command arguments in order.
+func (r *Runner) Calls() []string {
+	r.Lock()
+	defer r.Unlock()
+	return append([]string(nil), r.calls...)
+}
+
+// verify fails the test if the script was not run to completion.
+func (r *Runner) verify() {
+	r.Lock()
+	defer r.Unlock()
+	for _, step := range r.steps {
+		r.t.Errorf("expected command %q was not run (%d times remaining)", step.command, step.times)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingTB records the failures and cleanups of a test using a Runner.
+type recordingTB struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (tb *recordingTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func (tb *recordingTB) Cleanup(f func()) {
+	tb.cleanups = append(tb.cleanups, f)
+}
+
+func (tb *recordingTB) finish() {
+	for _, f := range tb.cleanups {
+		f()
+	}
+}
+
+// TestRunnerScript verifies that commands are matched in order and repeated steps are consumed.
+func TestRunnerScript(t *testing.T) {
+	r := NewRunner(t)
+	notFound := errors.New("not found")
+	r.Expect("volume create pvc-1 *").Return("", nil)
+	r.Expect("pasxml volumes volume pvc-1").Return("", notFound).Times(2)
+	r.Expect("pasxml volumes volume pvc-1").Return("<volume/>", nil)
+
+	_, err := r.RunCommand(t.Context(), nil, "volume", "create", "pvc-1", "soft 1.00", "hard 2.00")
+	assert.NoError(t, err)
+	for range 2 {
+		_, err = r.RunCommand(t.Context(), nil, "pasxml", "volumes", "volume", "pvc-1")
+		assert.Equal(t, notFound, err)
+	}
+	output, err := r.RunCommand(t.Context(), nil, "pasxml", "volumes", "volume", "pvc-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "<volume/>", string(output))
+
+	assert.Equal(t, []string{
+		"volume create pvc-1 soft 1.00 hard 2.00",
+		"pasxml volumes volume pvc-1",
+		"pasxml volumes volume pvc-1",
+		"pasxml volumes volume pvc-1",
+	}, r.Calls())
+}
+
+// TestRunnerFailures verifies that unexpected and missing commands fail the test.
+func TestRunnerFailures(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	r := NewRunner(tb)
+	r.Expect("volume delete -f pvc-1")
+	r.Expect("volume delete -f pvc-2")
+
+	_, err := r.RunCommand(t.Context(), nil, "volume", "delete", "-f", "pvc-3")
+	assert.ErrorContains(t, err, `unexpected command "volume delete -f pvc-3"`)
+	_, err = r.RunCommand(t.Context(), nil, "volume", "delete", "-f", "pvc-1")
+	assert.NoError(t, err)
+	tb.finish()
+
+	assert.Equal(t, []string{
+		`unexpected command "volume delete -f pvc-3": expected "volume delete -f pvc-1"`,
+		`expected command "volume delete -f pvc-2" was not run (1 times remaining)`,
+	}, tb.errors)
+}
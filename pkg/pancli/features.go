@@ -0,0 +1,64 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// GetRealmFeatures detects the optional features supported by the realm. Each feature is
+// probed with a read-only command; a feature is unsupported if the realm rejects the command.
+//
+// Parameters:
+//
+//	ctx     - The context of the request.
+//	secrets - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.RealmFeatures - The features of the realm.
+//	error                - Error if a probe fails for another reason than an unsupported command.
+func (p *PancliSSHClient) GetRealmFeatures(ctx context.Context, secrets map[string]string) (*utils.RealmFeatures, error) {
+	features := &utils.RealmFeatures{}
+	probes := []struct {
+		name    string
+		cmd     []string
+		feature *bool
+	}{
+		{name: "snapshots", cmd: []string{"pasxml", "snapshots"}, feature: &features.Snapshots},
+		{name: "capacity", cmd: []string{"pasxml", "bladesets"}, feature: &features.Capacity},
+	}
+
+	for _, probe := range probes {
+		requestLogger(ctx).V(5).Info("GetRealmFeatures executes:", "command", strings.Join(probe.cmd, " "))
+		_, err := p.pancli.RunCommand(ctx, secrets, probe.cmd...)
+		switch {
+		case err == nil:
+			*probe.feature = true
+		case IsUnsupportedCommand(err):
+			requestLogger(ctx).V(4).Info("realm does not support feature", "feature", probe.name, "error", err.Error())
+		default:
+			return nil, fmt.Errorf("failed to detect %s support of the realm: %w", probe.name, err)
+		}
+	}
+
+	return features, nil
+}
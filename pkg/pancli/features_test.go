@@ -0,0 +1,56 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli/fake"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRealmFeatures(t *testing.T) {
+	t.Run("AllSupported", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("pasxml snapshots").Return("<pasxml><snapshots></snapshots></pasxml>", nil)
+		runner.Expect("pasxml bladesets").Return("<pasxml><bladesets></bladesets></pasxml>", nil)
+
+		features, err := NewPancliSSHClient(runner).GetRealmFeatures(t.Context(), defaultSecrets)
+		assert.NoError(t, err)
+		assert.Equal(t, &utils.AllRealmFeatures, features)
+	})
+
+	t.Run("SnapshotsUnsupported", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("pasxml snapshots").Return("", parseErrorString("Unknown command: snapshots"))
+		runner.Expect("pasxml bladesets").Return("<pasxml><bladesets></bladesets></pasxml>", nil)
+
+		features, err := NewPancliSSHClient(runner).GetRealmFeatures(t.Context(), defaultSecrets)
+		assert.NoError(t, err)
+		assert.Equal(t, &utils.RealmFeatures{Capacity: true}, features)
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("pasxml snapshots").Return("", parseExitError(255, "ssh: connect to host director port 22: Connection refused"))
+
+		_, err := NewPancliSSHClient(runner).GetRealmFeatures(t.Context(), defaultSecrets)
+		assert.ErrorIs(t, err, ErrorUnavailable)
+		assert.ErrorContains(t, err, "failed to detect snapshots support")
+	})
+}
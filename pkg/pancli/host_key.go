@@ -0,0 +1,158 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SSHClientOption configures an SSHClient.
+type SSHClientOption func(*SSHClient)
+
+// WithKnownHostsFile verifies the host keys of realms against a known_hosts file, e.g.
+// mounted from a ConfigMap. The file is read for every new connection, so updates apply
+// without a restart.
+//
+// Parameters:
+//
+//	path - The path of the known_hosts file, empty to disable.
+//
+// Returns:
+//
+//	SSHClientOption - The client option.
+func WithKnownHostsFile(path string) SSHClientOption {
+	return func(s *SSHClient) {
+		s.knownHostsFile = path
+	}
+}
+
+// WithStrictHostKeyChecking refuses connections to realms whose host key does not match the
+// configured host keys, or which have no host key configured. Without strict checking a
+// mismatch is logged and the connection proceeds, and realms without host keys are not
+// verified.
+//
+// Parameters:
+//
+//	strict - Whether to refuse unverified host keys.
+//
+// Returns:
+//
+//	SSHClientOption - The client option.
+func WithStrictHostKeyChecking(strict bool) SSHClientOption {
+	return func(s *SSHClient) {
+		s.strictHostKeys = strict
+	}
+}
+
+// ParseHostKeys parses the host_key secret value. Each non-empty line is a SHA256
+// fingerprint as printed by ssh-keygen -l, e.g. "SHA256:nThbg6kX...", a public key in
+// authorized_keys format, e.g. "ssh-ed25519 AAAA...", or a known_hosts line, whose host
+// patterns are ignored since the secret belongs to a single realm.
+//
+// Parameters:
+//
+//	value - The secret value.
+//
+// Returns:
+//
+//	[]string - The SHA256 fingerprints of the host keys.
+//	error    - Error if a line cannot be parsed.
+func ParseHostKeys(value string) ([]string, error) {
+	var fingerprints []string
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "SHA256:") {
+			fingerprints = append(fingerprints, line)
+			continue
+		}
+		if key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line)); err == nil {
+			fingerprints = append(fingerprints, ssh.FingerprintSHA256(key))
+			continue
+		}
+		_, _, key, _, _, err := ssh.ParseKnownHosts([]byte(line))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid %s line %q: must be a SHA256 fingerprint, public key or known_hosts line",
+				ErrorInvalidArgument, utils.RealmConnectionContext.HostKey, line)
+		}
+		fingerprints = append(fingerprints, ssh.FingerprintSHA256(key))
+	}
+	return fingerprints, nil
+}
+
+// hostKeyCallback returns the host key verification of a connection to the realm of the
+// secrets. Host keys are accepted if they match the host_key secret or the known_hosts file.
+//
+// Parameters:
+//
+//	secrets - The parsed realm secrets.
+//
+// Returns:
+//
+//	ssh.HostKeyCallback - The host key verification.
+//	error               - Error if the configured host keys cannot be read.
+func (s *SSHClient) hostKeyCallback(secrets utils.RealmSecrets) (ssh.HostKeyCallback, error) {
+	fingerprints, err := ParseHostKeys(secrets.HostKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var knownHosts ssh.HostKeyCallback
+	if s.knownHostsFile != "" {
+		if knownHosts, err = knownhosts.New(s.knownHostsFile); err != nil {
+			return nil, fmt.Errorf("failed to read known hosts file %s: %w", s.knownHostsFile, err)
+		}
+	}
+
+	if len(fingerprints) == 0 && knownHosts == nil {
+		if s.strictHostKeys {
+			return func(hostname string, _ net.Addr, _ ssh.PublicKey) error {
+				return fmt.Errorf("%w: no host key is configured for %s, set %s in the secret or add it to the known hosts file",
+					ErrorUnauthenticated, hostname, utils.RealmConnectionContext.HostKey)
+			}, nil
+		}
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		fingerprint := ssh.FingerprintSHA256(key)
+		for _, accepted := range fingerprints {
+			if accepted == fingerprint {
+				return nil
+			}
+		}
+		if knownHosts != nil && knownHosts(hostname, remote, key) == nil {
+			return nil
+		}
+
+		if !s.strictHostKeys {
+			llog.Info("WARNING: host key of the realm does not match the configured host keys, connecting without strict host key checking",
+				"host", hostname, "fingerprint", fingerprint)
+			return nil
+		}
+		return fmt.Errorf("%w: host key %s of %s does not match the configured host keys",
+			ErrorUnauthenticated, fingerprint, hostname)
+	}, nil
+}
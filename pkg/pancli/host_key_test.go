@@ -0,0 +1,93 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli/sshtest"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func TestParseHostKeys(t *testing.T) {
+	server := sshtest.NewServer(t)
+	key := server.HostKey()
+	fingerprint := ssh.FingerprintSHA256(key)
+	authorized := string(ssh.MarshalAuthorizedKey(key))
+
+	fingerprints, err := ParseHostKeys("# realm directors\n" + fingerprint + "\n" + authorized + knownhosts.Line([]string{"realm.example.com"}, key) + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, []string{fingerprint, fingerprint, fingerprint}, fingerprints)
+
+	fingerprints, err = ParseHostKeys("")
+	require.NoError(t, err)
+	assert.Empty(t, fingerprints)
+
+	_, err = ParseHostKeys("not a key")
+	assert.ErrorIs(t, err, ErrorInvalidArgument)
+}
+
+// TestSSHClientHostKeyVerification verifies the host key checks against the host_key
+// secret and the known_hosts file, in strict and non-strict mode.
+func TestSSHClientHostKeyVerification(t *testing.T) {
+	server := newRealmServer(t, sshtest.WithPassword("admin", "secret"))
+	other := sshtest.NewServer(t)
+
+	knownHostsFile := filepath.Join(t.TempDir(), "known_hosts")
+	require.NoError(t, os.WriteFile(knownHostsFile, []byte(knownhosts.Line([]string{server.Addr()}, server.HostKey())+"\n"), 0o600))
+
+	tests := []struct {
+		name    string
+		hostKey string
+		opts    []SSHClientOption
+		wantErr error
+	}{
+		{name: "NotConfigured"},
+		{name: "NotConfiguredStrict", opts: []SSHClientOption{WithStrictHostKeyChecking(true)}, wantErr: ErrorUnauthenticated},
+		{name: "Fingerprint", hostKey: ssh.FingerprintSHA256(server.HostKey()), opts: []SSHClientOption{WithStrictHostKeyChecking(true)}},
+		{name: "PublicKey", hostKey: string(ssh.MarshalAuthorizedKey(server.HostKey())), opts: []SSHClientOption{WithStrictHostKeyChecking(true)}},
+		{name: "Mismatch", hostKey: ssh.FingerprintSHA256(other.HostKey())},
+		{name: "MismatchStrict", hostKey: ssh.FingerprintSHA256(other.HostKey()), opts: []SSHClientOption{WithStrictHostKeyChecking(true)}, wantErr: ErrorUnauthenticated},
+		{name: "KnownHostsFile", opts: []SSHClientOption{WithKnownHostsFile(knownHostsFile), WithStrictHostKeyChecking(true)}},
+		{name: "Invalid", hostKey: "not a key", wantErr: ErrorInvalidArgument},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewSSHClient(tc.opts...)
+			client.dial = newServerSSHClient(server).dial
+
+			secrets := realmSecrets(map[string]string{utils.RealmConnectionContext.Password: "secret"})
+			if tc.hostKey != "" {
+				secrets[utils.RealmConnectionContext.HostKey] = tc.hostKey
+			}
+
+			_, err := NewPancliSSHClient(client).GetVolume(t.Context(), "pvc-1", secrets)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// IdempotencyTokenPrefix prefixes the idempotency token appended to the description of
+// volumes created by the driver, e.g. "csi-token:3f9c2a7d51e08b46".
+const IdempotencyTokenPrefix = "csi-token:"
+
+// WithIdempotencyTokens tags the description of created volumes with a random token of the
+// request. If the connection fails after the create command was sent, a volume carrying the
+// token was created by the request, so the creation succeeded; otherwise the command is only
+// retried if the volume does not exist. Without tokens an existing volume cannot be told
+// from one created by the request, so the command is not retried and the error is returned.
+//
+// Parameters:
+//
+//	enabled - Whether created volumes are tagged.
+//
+// Returns:
+//
+//	PancliSSHClientOption - The client option.
+func WithIdempotencyTokens(enabled bool) PancliSSHClientOption {
+	return func(p *PancliSSHClient) {
+		p.idempotencyTokens = enabled
+	}
+}
+
+// newIdempotencyToken returns a random token identifying a single mutating request,
+// replaced in tests.
+var newIdempotencyToken = func() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// HasIdempotencyToken reports whether a volume description carries the token.
+//
+// Parameters:
+//
+//	description - The description of the volume.
+//	token       - The idempotency token.
+//
+// Returns:
+//
+//	bool - True if the description contains the tagged token.
+func HasIdempotencyToken(description, token string) bool {
+	return token != "" && strings.Contains(description, IdempotencyTokenPrefix+token)
+}
+
+// mutation is a mutating realm command together with the check of its effect.
+//
+// A command whose connection fails after it was sent may or may not have been run by the
+// realm. Blindly running it again is only safe for commands whose second run is harmless.
+// Instead, the realm state is checked first and the command is only run again if it
+// provably did not take effect, so every mutation runs at most once on the realm.
+type mutation struct {
+	// operation names the mutation in logs and metrics, e.g. "DeleteVolume".
+	operation string
+	// cmd is the command to run.
+	cmd []string
+	// applied reads the realm state and reports whether the command took effect. It must
+	// only report true if the state stems from this command or is indistinguishable from
+	// it, e.g. by the idempotency token of a created volume.
+	applied func() (bool, error)
+}
+
+// runMutation runs a mutating command. If its outcome is unknown because the connection
+// failed after the command was sent, the realm state is checked before the command is
+// retried once.
+//
+// Parameters:
+//
+//	ctx     - The context of the request.
+//	secrets - Map of authentication secrets.
+//	m       - The mutation to run.
+//
+// Returns:
+//
+//	error - Error if the command fails, or if its outcome is still unknown after checking
+//	        the realm state or retrying.
+func (p *PancliSSHClient) runMutation(ctx context.Context, secrets map[string]string, m mutation) error {
+	_, err := p.pancli.RunCommand(ctx, secrets, m.cmd...)
+	if !errors.Is(err, ErrorOutcomeUnknown) || m.applied == nil || ctx.Err() != nil {
+		// the realm state is not checked once the request ended, the CO retries the request
+		return err
+	}
+
+	applied, checkErr := m.applied()
+	switch {
+	case checkErr != nil:
+		metrics.MutationOutcomeChecks.WithLabelValues(m.operation, "unknown").Inc()
+		requestLogger(ctx).Error(checkErr, "failed to check the outcome of the command, not retrying", "operation", m.operation, "command", strings.Join(m.cmd, " "))
+		return err
+	case applied:
+		metrics.MutationOutcomeChecks.WithLabelValues(m.operation, "applied").Inc()
+		requestLogger(ctx).Info("connection failed after the command was sent, but the realm applied it", "operation", m.operation, "command", strings.Join(m.cmd, " "), "error", err.Error())
+		return nil
+	}
+
+	metrics.MutationOutcomeChecks.WithLabelValues(m.operation, "retried").Inc()
+	requestLogger(ctx).Info("connection failed before the realm applied the command, retrying", "operation", m.operation, "command", strings.Join(m.cmd, " "), "error", err.Error())
+	_, err = p.pancli.RunCommand(ctx, secrets, m.cmd...)
+	return err
+}
+
+// volumeCreated returns the check of a volume creation tagged with the idempotency token.
+// A volume with the name but without the token was not created by this request. Without a
+// token an existing volume cannot be attributed, so the check fails.
+func (p *PancliSSHClient) volumeCreated(ctx context.Context, volumeName, token string, secrets map[string]string) func() (bool, error) {
+	return func() (bool, error) {
+		volume, err := p.GetVolume(ctx, volumeName, secrets)
+		if errors.Is(err, ErrorNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if token == "" {
+			return false, fmt.Errorf("volume %s exists, but created volumes are not tagged with idempotency tokens", volumeName)
+		}
+		return HasIdempotencyToken(volume.Description, token), nil
+	}
+}
+
+// volumeDeleted returns the check of a volume deletion.
+func (p *PancliSSHClient) volumeDeleted(ctx context.Context, volumeName string, secrets map[string]string) func() (bool, error) {
+	return func() (bool, error) {
+		_, err := p.GetVolume(ctx, volumeName, secrets)
+		if errors.Is(err, ErrorNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+}
+
+// volumeExpanded returns the check of a soft quota change to at least the given size in the
+// quota unit of the realm.
+func (p *PancliSSHClient) volumeExpanded(ctx context.Context, volumeName string, size float64, secrets map[string]string) func() (bool, error) {
+	return func() (bool, error) {
+		volume, err := p.GetVolume(ctx, volumeName, secrets)
+		if err != nil {
+			return false, err
+		}
+		return volume.Soft >= size, nil
+	}
+}
+
+// snapshotCreated returns the check of a snapshot creation. Snapshot names are derived
+// from the unique CSI request name, so an existing snapshot with the name is the result of
+// this request or of an earlier attempt of it.
+func (p *PancliSSHClient) snapshotCreated(ctx context.Context, volumeName, snapshotName string, secrets map[string]string) func() (bool, error) {
+	return func() (bool, error) {
+		_, err := p.getSnapshot(ctx, volumeName, snapshotName, secrets)
+		if errors.Is(err, ErrorNotFound) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+}
+
+// snapshotDeleted returns the check of a snapshot deletion.
+func (p *PancliSSHClient) snapshotDeleted(ctx context.Context, volumeName, snapshotName string, secrets map[string]string) func() (bool, error) {
+	return func() (bool, error) {
+		_, err := p.getSnapshot(ctx, volumeName, snapshotName, secrets)
+		if errors.Is(err, ErrorNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+}
+
+// directoryCreated returns the check of a directory creation. Directory names are derived
+// from the unique CSI request name, so an existing directory with the name is the result of
+// this request or of an earlier attempt of it.
+func (p *PancliSSHClient) directoryCreated(ctx context.Context, volumeName, directory string, secrets map[string]string) func() (bool, error) {
+	return func() (bool, error) {
+		_, err := p.GetDirectory(ctx, volumeName, directory, secrets)
+		if errors.Is(err, ErrorNotFound) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+}
+
+// directoryDeleted returns the check of a directory deletion.
+func (p *PancliSSHClient) directoryDeleted(ctx context.Context, volumeName, directory string, secrets map[string]string) func() (bool, error) {
+	return func() (bool, error) {
+		_, err := p.GetDirectory(ctx, volumeName, directory, secrets)
+		if errors.Is(err, ErrorNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+}
+
+// directoryQuotaSet returns the check of a directory quota change to at least the given size
+// in the quota unit of the realm.
+func (p *PancliSSHClient) directoryQuotaSet(ctx context.Context, volumeName, directory string, size float64, secrets map[string]string) func() (bool, error) {
+	return func() (bool, error) {
+		dir, err := p.GetDirectory(ctx, volumeName, directory, secrets)
+		if err != nil {
+			return false, err
+		}
+		return dir.Hard >= size, nil
+	}
+}
+
+// withIdempotencyToken returns a copy of the parameters with the token appended to the
+// description of the volume.
+func (p VolumeCreateParams) withIdempotencyToken(token string) VolumeCreateParams {
+	params := make(VolumeCreateParams, len(p)+1)
+	for k, v := range p {
+		params[k] = v
+	}
+	key := utils.VolumeParameters.GetSCKey("description")
+	params[key] = strings.TrimSpace(params[key] + " " + IdempotencyTokenPrefix + token)
+	return params
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli/fake"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errOutcomeUnknown is the error of a command whose connection failed after it was sent.
+var errOutcomeUnknown = fmt.Errorf("%w: %w: %v", ErrorOutcomeUnknown, ErrorUnavailable, io.EOF)
+
+// volumePasXML returns the pasxml output of a volume.
+func volumePasXML(t *testing.T, volume *utils.Volume) string {
+	out, err := volume.MarshalVolumeToPasXML()
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestHasIdempotencyToken(t *testing.T) {
+	assert.True(t, HasIdempotencyToken("csi-token:abc", "abc"))
+	assert.True(t, HasIdempotencyToken("team volume csi-token:abc", "abc"))
+	assert.False(t, HasIdempotencyToken("csi-token:abd", "abc"))
+	assert.False(t, HasIdempotencyToken("", "abc"))
+	assert.False(t, HasIdempotencyToken("csi-token:", ""))
+}
+
+func TestCreateVolumeIdempotency(t *testing.T) {
+	newToken := newIdempotencyToken
+	newIdempotencyToken = func() string { return "3f9c2a7d51e08b46" }
+	t.Cleanup(func() { newIdempotencyToken = newToken })
+	tagged := &utils.Volume{ID: "372", Name: validVolumeName, State: utils.VolumeStateOnline, Soft: 1, Description: "team volume csi-token:3f9c2a7d51e08b46"}
+	foreign := &utils.Volume{ID: "12", Name: validVolumeName, State: utils.VolumeStateOnline, Soft: 1, Description: "team volume"}
+	params := VolumeCreateParams{utils.VolumeParameters.GetSCKey("description"): "team volume"}
+	createCmd := `volume create ` + validVolumeName + ` description "team volume csi-token:3f9c2a7d51e08b46"`
+
+	t.Run("Tagged", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect(createCmd)
+		runner.Expect("pasxml volumes volume "+validVolumeName).Return(volumePasXML(t, tagged), nil)
+
+		_, err := NewPancliSSHClient(runner, WithIdempotencyTokens(true)).CreateVolume(t.Context(), validVolumeName, params, defaultSecrets)
+		assert.NoError(t, err)
+	})
+
+	t.Run("AppliedBeforeConnectionLoss", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect(createCmd).Return("", errOutcomeUnknown)
+		runner.Expect("pasxml volumes volume "+validVolumeName).Return(volumePasXML(t, tagged), nil).Times(2)
+
+		vol, err := NewPancliSSHClient(runner, WithIdempotencyTokens(true)).CreateVolume(t.Context(), validVolumeName, params, defaultSecrets)
+		require.NoError(t, err)
+		assert.Equal(t, "372", vol.ID)
+	})
+
+	t.Run("NotAppliedBeforeConnectionLoss", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect(createCmd).Return("", errOutcomeUnknown)
+		runner.Expect("pasxml volumes volume "+validVolumeName).Return("", fmt.Errorf("%w: No volume with name %s", ErrorNotFound, validVolumeName))
+		runner.Expect(createCmd)
+		runner.Expect("pasxml volumes volume "+validVolumeName).Return(volumePasXML(t, tagged), nil)
+
+		_, err := NewPancliSSHClient(runner, WithIdempotencyTokens(true)).CreateVolume(t.Context(), validVolumeName, params, defaultSecrets)
+		assert.NoError(t, err)
+	})
+
+	t.Run("ExistingVolumeOfOtherRequest", func(t *testing.T) {
+		// the retry is rejected by the realm, the existing volume is left to the caller
+		runner := fake.NewRunner(t)
+		runner.Expect(createCmd).Return("", errOutcomeUnknown)
+		runner.Expect("pasxml volumes volume "+validVolumeName).Return(volumePasXML(t, foreign), nil)
+		runner.Expect(createCmd).Return("", fmt.Errorf("%w: volume %s", ErrorAlreadyExist, validVolumeName))
+
+		_, err := NewPancliSSHClient(runner, WithIdempotencyTokens(true)).CreateVolume(t.Context(), validVolumeName, params, defaultSecrets)
+		assert.ErrorIs(t, err, ErrorAlreadyExist)
+	})
+
+	t.Run("ExistingVolumeWithoutTokens", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("volume create "+validVolumeName+` description "team volume"`).Return("", errOutcomeUnknown)
+		runner.Expect("pasxml volumes volume "+validVolumeName).Return(volumePasXML(t, tagged), nil)
+
+		_, err := NewPancliSSHClient(runner).CreateVolume(t.Context(), validVolumeName, params, defaultSecrets)
+		assert.ErrorIs(t, err, ErrorOutcomeUnknown)
+		assert.ErrorIs(t, err, ErrorUnavailable)
+	})
+}
+
+func TestMutationOutcomeChecks(t *testing.T) {
+	notFound := fmt.Errorf("%w: No volume with name %s", ErrorNotFound, validVolumeName)
+	online := &utils.Volume{ID: "372", Name: validVolumeName, State: utils.VolumeStateOnline, Soft: 2}
+
+	t.Run("DeleteVolumeApplied", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("volume delete -f "+validVolumeName).Return("", errOutcomeUnknown)
+		runner.Expect("pasxml volumes volume "+validVolumeName).Return("", notFound)
+
+		assert.NoError(t, NewPancliSSHClient(runner).DeleteVolume(t.Context(), validVolumeName, defaultSecrets))
+	})
+
+	t.Run("DeleteVolumeRetried", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("volume delete -f "+validVolumeName).Return("", errOutcomeUnknown)
+		runner.Expect("pasxml volumes volume "+validVolumeName).Return(volumePasXML(t, online), nil)
+		runner.Expect("volume delete -f " + validVolumeName)
+
+		assert.NoError(t, NewPancliSSHClient(runner).DeleteVolume(t.Context(), validVolumeName, defaultSecrets))
+	})
+
+	t.Run("RetriedOnlyOnce", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("volume delete -f "+validVolumeName).Return("", errOutcomeUnknown)
+		runner.Expect("pasxml volumes volume "+validVolumeName).Return(volumePasXML(t, online), nil)
+		runner.Expect("volume delete -f "+validVolumeName).Return("", errOutcomeUnknown)
+
+		assert.ErrorIs(t, NewPancliSSHClient(runner).DeleteVolume(t.Context(), validVolumeName, defaultSecrets), ErrorOutcomeUnknown)
+	})
+
+	t.Run("CheckFailed", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("volume delete -f "+validVolumeName).Return("", errOutcomeUnknown)
+		runner.Expect("pasxml volumes volume "+validVolumeName).Return("", fmt.Errorf("%w: connection refused", ErrorUnavailable))
+
+		assert.ErrorIs(t, NewPancliSSHClient(runner).DeleteVolume(t.Context(), validVolumeName, defaultSecrets), ErrorOutcomeUnknown)
+	})
+
+	t.Run("RealmErrorNotChecked", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("volume delete -f "+validVolumeName).Return("", notFound)
+
+		assert.ErrorIs(t, NewPancliSSHClient(runner).DeleteVolume(t.Context(), validVolumeName, defaultSecrets), ErrorNotFound)
+	})
+
+	t.Run("ExpandVolume", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("volume set soft-quota "+validVolumeName+" 2.00").Return("", errOutcomeUnknown)
+		runner.Expect("pasxml volumes volume "+validVolumeName).Return(volumePasXML(t, online), nil)
+		runner.Expect("volume set soft-quota "+validVolumeName+" 3.00").Return("", errOutcomeUnknown)
+		runner.Expect("pasxml volumes volume "+validVolumeName).Return(volumePasXML(t, online), nil)
+		runner.Expect("volume set soft-quota " + validVolumeName + " 3.00")
+
+		panfs := NewPancliSSHClient(runner)
+		assert.NoError(t, panfs.ExpandVolume(t.Context(), validVolumeName, 2<<30, defaultSecrets))
+		assert.NoError(t, panfs.ExpandVolume(t.Context(), validVolumeName, 3<<30, defaultSecrets))
+	})
+
+	t.Run("CreateSnapshot", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("snapshot create "+validVolumeName+" snapshot-1").Return("", errOutcomeUnknown)
+		runner.Expect("pasxml snapshots volume "+validVolumeName).Return(snapshotsPasXML, nil).Times(2)
+
+		snapshot, err := NewPancliSSHClient(runner).CreateSnapshot(t.Context(), validVolumeName, "snapshot-1", defaultSecrets)
+		require.NoError(t, err)
+		assert.Equal(t, "snapshot-1", snapshot.Name)
+	})
+
+	t.Run("DeleteSnapshot", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("snapshot delete -f "+validVolumeName+" snapshot-1").Return("", errOutcomeUnknown)
+		runner.Expect("pasxml snapshots volume "+validVolumeName).Return(snapshotsPasXML, nil)
+		runner.Expect("snapshot delete -f "+validVolumeName+" snapshot-1").Return("", errors.New("unexpected"))
+
+		assert.EqualError(t, NewPancliSSHClient(runner).DeleteSnapshot(t.Context(), validVolumeName, "snapshot-1", defaultSecrets), "unexpected")
+	})
+}
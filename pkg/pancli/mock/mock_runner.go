@@ -1,15 +1,11 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: pancli_ssh.go
-//
-// Generated by this command:
-//
-//	mockgen -source=pancli_ssh.go -destination=mock/mock_runner.go -package=mock PancliRunner
-//
+// Source: github.com/panasasinc/panfs-container-storage-interface-oss/pkg/interfaces (interfaces: SSHRunner)
 
 // Package mock is a generated GoMock package.
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -40,9 +36,9 @@ func (m *MockSSHRunner) EXPECT() *MockSSHRunnerMockRecorder {
 }
 
 // RunCommand mocks base method.
-func (m *MockSSHRunner) RunCommand(secrets map[string]string, args ...string) ([]byte, error) {
+func (m *MockSSHRunner) RunCommand(ctx context.Context, secrets map[string]string, args ...string) ([]byte, error) {
 	m.ctrl.T.Helper()
-	varargs := []any{secrets}
+	varargs := []any{ctx, secrets}
 	for _, a := range args {
 		varargs = append(varargs, a)
 	}
@@ -53,8 +49,8 @@ func (m *MockSSHRunner) RunCommand(secrets map[string]string, args ...string) ([
 }
 
 // RunCommand indicates an expected call of RunCommand.
-func (mr *MockSSHRunnerMockRecorder) RunCommand(secrets any, args ...any) *gomock.Call {
+func (mr *MockSSHRunnerMockRecorder) RunCommand(ctx, secrets any, args ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]any{secrets}, args...)
+	varargs := append([]any{ctx, secrets}, args...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunCommand", reflect.TypeOf((*MockSSHRunner)(nil).RunCommand), varargs...)
 }
@@ -0,0 +1,87 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// volumeSetAttributes are the attributes of the "volume set" command by mutable parameter.
+var volumeSetAttributes = map[string]string{
+	"description": "description",
+	"recovery":    "recoverypriority",
+	"efsa":        "efsa",
+	"hard":        "hard-quota",
+}
+
+// ModifyVolume changes the mutable parameters of a volume with one "volume set" command per
+// parameter, in the order of MutableVolumeParameters. The commands set absolute values, so a
+// partially applied modification is completed by running it again.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume to modify.
+//	params     - The changed parameters.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	error - Error if a command fails, a *QuotaLimitError if the hard quota exceeds the limits
+//	        of the realm.
+func (p *PancliSSHClient) ModifyVolume(ctx context.Context, volumeName string, params VolumeModifyParams, secrets map[string]string) error {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	for _, name := range MutableVolumeParameters {
+		value, ok := params[utils.VolumeParameters.GetSCKey(name)]
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "description":
+			value = fmt.Sprintf(`"%s"`, value)
+		case "hard":
+			hardGB, _ := params.HardGB()
+			value = strconv.FormatFloat(unit.FromBytes(utils.GBToBytes(hardGB)), 'f', 2, 64)
+		}
+		cmd := []string{"volume", "set", volumeSetAttributes[name], volumeName, value}
+
+		requestLogger(ctx).V(5).Info("ModifyVolume executes:", "command", strings.Join(cmd, " "))
+		if _, err := p.pancli.RunCommand(ctx, secrets, cmd...); err != nil {
+			if name == "hard" {
+				return quotaLimitError(err, unit)
+			}
+			return fmt.Errorf("failed to set %s of volume %s: %w", name, volumeName, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pancli provides SSH-based client implementations and utilities for interacting with PanFS storage systems.
+// It defines types and functions for volume management, SSH command execution, and parameter handling.
+//
+// The SSH client is excluded from builds with the nodeonly tag, which only need the volume
+// parameters and error types of the package.
+package pancli
+
+import (
+	"context"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+var llog klog.Logger = klog.NewKlogr()
+
+// SetLogger sets the logger used by the package.
+//
+// Parameters:
+//
+//	log - The logger instance.
+func SetLogger(log klog.Logger) {
+	llog = log
+}
+
+// requestLogger returns the logger of the package with the request ID of the context, so
+// realm commands can be correlated with the RPC running them.
+//
+// Parameters:
+//
+//	ctx - The context of the request.
+//
+// Returns:
+//
+//	klog.Logger - The logger, which logs the request ID if the context has one.
+func requestLogger(ctx context.Context) klog.Logger {
+	if requestID, ok := utils.RequestIDFromContext(ctx); ok {
+		return llog.WithValues("request_id", requestID)
+	}
+	return llog
+}
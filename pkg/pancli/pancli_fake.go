@@ -15,7 +15,9 @@
 package pancli
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
@@ -35,14 +37,18 @@ type Log struct {
 //	*FakePancliSSHClient - The initialized fake client.
 func NewFakePancliSSHClient() *FakePancliSSHClient {
 	return &FakePancliSSHClient{
-		Volumes: make([]*utils.Volume, 0),
+		Volumes:     make([]*utils.Volume, 0),
+		Snapshots:   make([]*utils.Snapshot, 0),
+		Directories: make([]*utils.Directory, 0),
 	}
 }
 
 // FakePancliSSHClient simulates a PanFS SSH client for testing purposes.
 type FakePancliSSHClient struct {
-	Volumes   []*utils.Volume
-	ActionLog []Log
+	Volumes     []*utils.Volume
+	Snapshots   []*utils.Snapshot
+	Directories []*utils.Directory
+	ActionLog   []Log
 }
 
 // CreateVolume creates a volume in the fake client.
@@ -50,6 +56,7 @@ type FakePancliSSHClient struct {
 //
 // Parameters:
 //
+//	_          - Unused context.
 //	volumeName - The name of the volume to create.
 //	params     - The volume creation parameters.
 //	_          - Unused secrets map.
@@ -58,42 +65,33 @@ type FakePancliSSHClient struct {
 //
 //	*utils.Volume - The created volume object.
 //	error         - Error if volume exists.
-func (c *FakePancliSSHClient) CreateVolume(volumeName string, params VolumeCreateParams, _ map[string]string) (*utils.Volume, error) {
+func (c *FakePancliSSHClient) CreateVolume(_ context.Context, volumeName string, params VolumeCreateParams, _ map[string]string) (*utils.Volume, error) {
 	if _, err := c.getVolume(volumeName); err == nil {
 		// no error means volume already exists
 		return nil, ErrorAlreadyExist
 	}
 
-	bsetName := params[utils.VolumeParameters.GetSCKey("bladeset")]
+	bsetName := params.Bladeset()
 	if bsetName == "" {
 		bsetName = "Set 1"
 	}
 
-	soft := params[utils.VolumeParameters.GetSCKey("soft")]
-	if soft == "" {
-		soft = "0"
-	}
-
-	hard := params[utils.VolumeParameters.GetSCKey("hard")]
-	if hard == "" {
-		hard = "0"
-	}
-
 	vol := &utils.Volume{
 		Name: utils.VolumeName(volumeName),
 		Bset: utils.Bladeset{
 			ID:   "1",
 			Name: bsetName,
 		},
-		State:      "Online",
-		Soft:       utils.BytesStringToGB(soft),
-		Hard:       utils.BytesStringToGB(hard),
-		ID:         uuid.New().String(),
-		Encryption: "none",
+		State:       utils.VolumeStateOnline,
+		Soft:        params.SoftGB(),
+		Hard:        params.HardGB(),
+		ID:          uuid.New().String(),
+		Encryption:  "none",
+		Description: params.Description(),
 	}
 
-	if val, ok := params[utils.VolumeParameters.GetSCKey("encryption")]; ok {
-		vol.Encryption = val
+	if params.Encrypted() {
+		vol.Encryption = "on"
 	}
 
 	c.Volumes = append(c.Volumes, vol)
@@ -125,13 +123,14 @@ func (c *FakePancliSSHClient) getVolume(volumeName string) (*utils.Volume, error
 //
 // Parameters:
 //
+//	_     - Unused context.
 //	volID - The ID of the volume to delete.
 //	_     - Unused secrets map.
 //
 // Returns:
 //
 //	error - Error if not found.
-func (c *FakePancliSSHClient) DeleteVolume(volID string, _ map[string]string) error {
+func (c *FakePancliSSHClient) DeleteVolume(_ context.Context, volID string, _ map[string]string) error {
 	for i, vol := range c.Volumes {
 		if vol.ID == volID {
 			c.Volumes = append(c.Volumes[:i], c.Volumes[i+1:]...)
@@ -146,6 +145,7 @@ func (c *FakePancliSSHClient) DeleteVolume(volID string, _ map[string]string) er
 //
 // Parameters:
 //
+//	_           - Unused context.
 //	volumeName  - The name of the volume to expand.
 //	targetSize  - The target size in bytes.
 //	_           - Unused secrets map.
@@ -153,7 +153,7 @@ func (c *FakePancliSSHClient) DeleteVolume(volID string, _ map[string]string) er
 // Returns:
 //
 //	error - Error if not found.
-func (c *FakePancliSSHClient) ExpandVolume(volumeName string, targetSize int64, _ map[string]string) error {
+func (c *FakePancliSSHClient) ExpandVolume(_ context.Context, volumeName string, targetSize int64, _ map[string]string) error {
 	vol, err := c.getVolume(volumeName)
 	if err != nil {
 		return err
@@ -162,24 +162,88 @@ func (c *FakePancliSSHClient) ExpandVolume(volumeName string, targetSize int64,
 	return nil
 }
 
+// ModifyVolume changes the description and hard quota of a volume in the fake client,
+// other parameters are not stored. Returns an error if not found.
+//
+// Parameters:
+//
+//	_          - Unused context.
+//	volumeName - The name of the volume to modify.
+//	params     - The changed parameters.
+//	_          - Unused secrets map.
+//
+// Returns:
+//
+//	error - Error if not found.
+func (c *FakePancliSSHClient) ModifyVolume(_ context.Context, volumeName string, params VolumeModifyParams, _ map[string]string) error {
+	vol, err := c.getVolume(volumeName)
+	if err != nil {
+		return err
+	}
+	if description, ok := params[utils.VolumeParameters.GetSCKey("description")]; ok {
+		vol.Description = description
+	}
+	if hardGB, ok := params.HardGB(); ok {
+		vol.Hard = hardGB
+	}
+	return nil
+}
+
 // ListVolumes returns an empty volume list in the fake client.
 //
 // Parameters:
 //
-//	_ - Unused secrets map.
+//	_   - Unused context.
+//	_   - Unused secrets map.
 //
 // Returns:
 //
 //	*utils.VolumeList - An empty volume list.
 //	error             - Always nil.
-func (c *FakePancliSSHClient) ListVolumes(_ map[string]string) (*utils.VolumeList, error) {
+func (c *FakePancliSSHClient) ListVolumes(_ context.Context, _ map[string]string) (*utils.VolumeList, error) {
 	return &utils.VolumeList{}, nil
 }
 
+// FakeAvailableCapacity is the free space reported by the fake client.
+const FakeAvailableCapacity int64 = 1 << 40
+
+// GetCapacity returns FakeAvailableCapacity in the fake client.
+//
+// Parameters:
+//
+//	_   - Unused context.
+//	_   - Unused bladeset name.
+//	_   - Unused secrets map.
+//
+// Returns:
+//
+//	int64 - FakeAvailableCapacity.
+//	error - Always nil.
+func (c *FakePancliSSHClient) GetCapacity(_ context.Context, _ string, _ map[string]string) (int64, error) {
+	return FakeAvailableCapacity, nil
+}
+
+// GetRealmFeatures reports all optional features as supported in the fake client.
+//
+// Parameters:
+//
+//	_   - Unused context.
+//	_   - Unused secrets map.
+//
+// Returns:
+//
+//	*utils.RealmFeatures - utils.AllRealmFeatures.
+//	error                - Always nil.
+func (c *FakePancliSSHClient) GetRealmFeatures(_ context.Context, _ map[string]string) (*utils.RealmFeatures, error) {
+	features := utils.AllRealmFeatures
+	return &features, nil
+}
+
 // GetVolume retrieves a volume by name from the fake client.
 //
 // Parameters:
 //
+//	_          - Unused context.
 //	volumeName - The name of the volume to retrieve.
 //	_          - Unused secrets map.
 //
@@ -187,6 +251,223 @@ func (c *FakePancliSSHClient) ListVolumes(_ map[string]string) (*utils.VolumeLis
 //
 //	*utils.Volume - The found volume object.
 //	error         - Error if not found.
-func (c *FakePancliSSHClient) GetVolume(volumeName string, _ map[string]string) (*utils.Volume, error) {
+func (c *FakePancliSSHClient) GetVolume(_ context.Context, volumeName string, _ map[string]string) (*utils.Volume, error) {
 	return c.getVolume(volumeName)
 }
+
+// CreateSnapshot creates a snapshot of a volume in the fake client.
+// Returns an error if the volume does not exist or already has a snapshot with the name.
+//
+// Parameters:
+//
+//	_            - Unused context.
+//	volumeName   - The name of the volume to snapshot.
+//	snapshotName - The name of the snapshot to create.
+//	_            - Unused secrets map.
+//
+// Returns:
+//
+//	*utils.Snapshot - The created snapshot object.
+//	error           - Error if the volume is not found or the snapshot exists.
+func (c *FakePancliSSHClient) CreateSnapshot(_ context.Context, volumeName, snapshotName string, _ map[string]string) (*utils.Snapshot, error) {
+	if _, err := c.getVolume(volumeName); err != nil {
+		return nil, err
+	}
+	for _, snap := range c.Snapshots {
+		if string(snap.VolumeName) == volumeName && snap.Name == snapshotName {
+			return nil, ErrorAlreadyExist
+		}
+	}
+
+	snap := &utils.Snapshot{
+		ID:           uuid.New().String(),
+		Name:         snapshotName,
+		VolumeName:   utils.VolumeName(volumeName),
+		CreationTime: time.Now().Unix(),
+	}
+	c.Snapshots = append(c.Snapshots, snap)
+	return snap, nil
+}
+
+// CreateVolumeFromSnapshot creates a volume from a snapshot in the fake client.
+// Returns an error if the snapshot does not exist or the volume already exists.
+//
+// Parameters:
+//
+//	ctx          - The context of the request.
+//	volumeName   - The name of the volume to create.
+//	sourceVolume - The name of the snapshotted volume.
+//	snapshotName - The name of the snapshot to restore.
+//	params       - The volume creation parameters.
+//	secrets      - Unused secrets map.
+//
+// Returns:
+//
+//	*utils.Volume - The created volume object.
+//	error         - Error if the snapshot is not found or the volume exists.
+func (c *FakePancliSSHClient) CreateVolumeFromSnapshot(ctx context.Context, volumeName, sourceVolume, snapshotName string, params VolumeCreateParams, secrets map[string]string) (*utils.Volume, error) {
+	for _, snap := range c.Snapshots {
+		if string(snap.VolumeName) == sourceVolume && snap.Name == snapshotName {
+			return c.CreateVolume(ctx, volumeName, params, secrets)
+		}
+	}
+	return nil, fmt.Errorf("%w: snapshot %s of volume %s", ErrorNotFound, snapshotName, sourceVolume)
+}
+
+// DeleteSnapshot deletes a snapshot of a volume from the fake client.
+// Returns an error if not found.
+//
+// Parameters:
+//
+//	_            - Unused context.
+//	volumeName   - The name of the snapshotted volume.
+//	snapshotName - The name of the snapshot to delete.
+//	_            - Unused secrets map.
+//
+// Returns:
+//
+//	error - Error if not found.
+func (c *FakePancliSSHClient) DeleteSnapshot(_ context.Context, volumeName, snapshotName string, _ map[string]string) error {
+	for i, snap := range c.Snapshots {
+		if string(snap.VolumeName) == volumeName && snap.Name == snapshotName {
+			c.Snapshots = append(c.Snapshots[:i], c.Snapshots[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrorNotFound, snapshotName)
+}
+
+// ListSnapshots returns the snapshots of a volume, or of all volumes, in the fake client.
+//
+// Parameters:
+//
+//	_          - Unused context.
+//	volumeName - The name of the volume whose snapshots are listed, empty for all volumes.
+//	_          - Unused secrets map.
+//
+// Returns:
+//
+//	*utils.SnapshotList - The snapshot list.
+//	error               - Error if the volume is not found.
+func (c *FakePancliSSHClient) ListSnapshots(_ context.Context, volumeName string, _ map[string]string) (*utils.SnapshotList, error) {
+	if volumeName != "" {
+		if _, err := c.getVolume(volumeName); err != nil {
+			return nil, err
+		}
+	}
+
+	list := &utils.SnapshotList{}
+	for _, snap := range c.Snapshots {
+		if volumeName == "" || string(snap.VolumeName) == volumeName {
+			list.Snapshots = append(list.Snapshots, *snap)
+		}
+	}
+	return list, nil
+}
+
+// CreateDirectory creates a directory of a volume in the fake client.
+// Returns an error if the volume does not exist or the directory exists.
+//
+// Parameters:
+//
+//	_          - Unused context.
+//	volumeName - The name of the volume holding the directory.
+//	directory  - The name of the directory to create.
+//	hardBytes  - The directory quota in bytes.
+//	_          - Unused secrets map.
+//
+// Returns:
+//
+//	*utils.Directory - The created directory object.
+//	error            - Error if the volume is not found or the directory exists.
+func (c *FakePancliSSHClient) CreateDirectory(_ context.Context, volumeName, directory string, hardBytes int64, _ map[string]string) (*utils.Directory, error) {
+	if _, err := c.getVolume(volumeName); err != nil {
+		return nil, err
+	}
+	if _, err := c.getDirectory(volumeName, directory); err == nil {
+		return nil, ErrorAlreadyExist
+	}
+
+	dir := &utils.Directory{
+		Name:       directory,
+		VolumeName: utils.VolumeName(volumeName),
+		Hard:       utils.BytesToGB(hardBytes),
+	}
+	c.Directories = append(c.Directories, dir)
+	return dir, nil
+}
+
+// getDirectory retrieves a directory of a volume from the fake client.
+// Returns an error if not found.
+func (c *FakePancliSSHClient) getDirectory(volumeName, directory string) (*utils.Directory, error) {
+	for _, dir := range c.Directories {
+		if string(dir.VolumeName) == volumeName && dir.Name == directory {
+			return dir, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: directory %s of volume %s", ErrorNotFound, directory, volumeName)
+}
+
+// DeleteDirectory deletes a directory of a volume from the fake client.
+// Returns an error if not found.
+//
+// Parameters:
+//
+//	_          - Unused context.
+//	volumeName - The name of the volume holding the directory.
+//	directory  - The name of the directory to delete.
+//	_          - Unused secrets map.
+//
+// Returns:
+//
+//	error - Error if not found.
+func (c *FakePancliSSHClient) DeleteDirectory(_ context.Context, volumeName, directory string, _ map[string]string) error {
+	for i, dir := range c.Directories {
+		if string(dir.VolumeName) == volumeName && dir.Name == directory {
+			c.Directories = append(c.Directories[:i], c.Directories[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: directory %s of volume %s", ErrorNotFound, directory, volumeName)
+}
+
+// SetDirectoryQuota changes the directory quota of a directory in the fake client.
+// Returns an error if not found.
+//
+// Parameters:
+//
+//	_          - Unused context.
+//	volumeName - The name of the volume holding the directory.
+//	directory  - The name of the directory.
+//	hardBytes  - The new directory quota in bytes.
+//	_          - Unused secrets map.
+//
+// Returns:
+//
+//	error - Error if not found.
+func (c *FakePancliSSHClient) SetDirectoryQuota(_ context.Context, volumeName, directory string, hardBytes int64, _ map[string]string) error {
+	dir, err := c.getDirectory(volumeName, directory)
+	if err != nil {
+		return err
+	}
+	dir.Hard = utils.BytesToGB(hardBytes)
+	return nil
+}
+
+// GetDirectory returns a directory of a volume in the fake client.
+// Returns an error if not found.
+//
+// Parameters:
+//
+//	_          - Unused context.
+//	volumeName - The name of the volume holding the directory.
+//	directory  - The name of the directory.
+//	_          - Unused secrets map.
+//
+// Returns:
+//
+//	*utils.Directory - The directory object.
+//	error            - Error if not found.
+func (c *FakePancliSSHClient) GetDirectory(_ context.Context, volumeName, directory string, _ map[string]string) (*utils.Directory, error) {
+	return c.getDirectory(volumeName, directory)
+}
@@ -0,0 +1,1206 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// Realm providers selectable with the --provider flag of the controller.
+const (
+	// RealmProviderSSH runs pancli commands on the realm over SSH.
+	RealmProviderSSH = "ssh"
+	// RealmProviderREST calls the REST API of the realm over HTTPS.
+	RealmProviderREST = "rest"
+)
+
+// Defaults of the REST realm provider.
+const (
+	// DefaultRESTPort is the port of the realm REST API.
+	DefaultRESTPort = "443"
+	// DefaultRESTTimeout bounds a single request to the realm REST API.
+	DefaultRESTTimeout = 30 * time.Second
+)
+
+const (
+	// restAPIPath is the base path of the realm REST API.
+	restAPIPath = "/api/v1"
+	// restMaxIdleConnsPerHost is the number of idle connections kept open per realm address.
+	restMaxIdleConnsPerHost = 16
+	// restMaxResponseSize bounds the size of realm responses read into memory.
+	restMaxResponseSize = 64 << 20
+)
+
+// restStatusErrors maps HTTP status codes to errors for responses without a known error code.
+var restStatusErrors = map[int]error{
+	http.StatusBadRequest:          ErrorInvalidArgument,
+	http.StatusUnauthorized:        ErrorUnauthenticated,
+	http.StatusForbidden:           ErrorUnauthenticated,
+	http.StatusNotFound:            ErrorNotFound,
+	http.StatusConflict:            ErrorAlreadyExist,
+	http.StatusTooManyRequests:     ErrorUnavailable,
+	http.StatusNotImplemented:      ErrorNotImplemented,
+	http.StatusBadGateway:          ErrorUnavailable,
+	http.StatusServiceUnavailable:  ErrorUnavailable,
+	http.StatusGatewayTimeout:      ErrorUnavailable,
+	http.StatusInternalServerError: ErrorInternal,
+}
+
+// PancliRESTClient implements the StorageProviderClient interface of the driver with the REST
+// API of the PanFS realm instead of pancli commands over SSH. Requests are sent to
+// https://<realm address>:<port>/api/v1 and the realm addresses of the secrets are tried in
+// turn, sticking to the last reachable one. Connections are pooled per realm address.
+//
+// The operations map to the following endpoints:
+//
+//	GET    /volumes                               - ListVolumes
+//	POST   /volumes                               - CreateVolume, CreateVolumeFromSnapshot
+//	GET    /volumes/{volume}                      - GetVolume
+//	PATCH  /volumes/{volume}                      - ExpandVolume, ModifyVolume
+//	DELETE /volumes/{volume}                      - DeleteVolume
+//	GET    /snapshots                             - ListSnapshots of all volumes
+//	GET    /volumes/{volume}/snapshots            - ListSnapshots of a volume
+//	POST   /volumes/{volume}/snapshots            - CreateSnapshot
+//	DELETE /volumes/{volume}/snapshots/{snapshot} - DeleteSnapshot
+//	POST   /volumes/{volume}/directories          - CreateDirectory
+//	GET    /volumes/{volume}/directories/{dir}    - GetDirectory
+//	PATCH  /volumes/{volume}/directories/{dir}    - SetDirectoryQuota
+//	DELETE /volumes/{volume}/directories/{dir}    - DeleteDirectory
+//	GET    /bladesets                             - GetCapacity
+//
+// Requests are authenticated with the api_token of the secrets as bearer token, or with the
+// user and password of the secrets. Failed requests are answered with a JSON body like
+// {"error": {"code": "not_found", "message": "..."}}, where code is one of the error names
+// of LoadErrorPatterns or "unsupported"; responses without a known code are mapped by their
+// HTTP status.
+type PancliRESTClient struct {
+	client    *http.Client
+	transport *http.Transport
+	port      string
+	// reachability of the addresses of realms with several directors
+	health realmHealth
+	// serializes mutating requests for realms requesting it via secrets
+	realmLocks realmLocks
+	// realms already reported as not supporting hard quotas
+	hardQuotaWarned sync.Map
+	// send an idempotency key with mutating requests and resend them once if the
+	// connection failed after the request was sent
+	idempotencyKeys bool
+	// authenticate with API tokens only
+	forbidPasswordAuth bool
+}
+
+// PancliRESTClientOption configures optional PancliRESTClient behavior in NewPancliRESTClient.
+type PancliRESTClientOption func(*PancliRESTClient)
+
+// WithRESTPort sets the port of the realm REST API.
+//
+// Parameters:
+//
+//	port - The port, DefaultRESTPort unless set.
+//
+// Returns:
+//
+//	PancliRESTClientOption - The client option.
+func WithRESTPort(port string) PancliRESTClientOption {
+	return func(p *PancliRESTClient) {
+		p.port = port
+	}
+}
+
+// WithRESTTimeout sets the time a single request to the realm may take, including
+// connecting and reading the response.
+//
+// Parameters:
+//
+//	timeout - The request timeout, DefaultRESTTimeout unless set.
+//
+// Returns:
+//
+//	PancliRESTClientOption - The client option.
+func WithRESTTimeout(timeout time.Duration) PancliRESTClientOption {
+	return func(p *PancliRESTClient) {
+		p.client.Timeout = timeout
+	}
+}
+
+// WithRESTTLSConfig sets the TLS configuration of connections to the realm, e.g. as
+// returned by NewRESTTLSConfig.
+//
+// Parameters:
+//
+//	config - The TLS configuration.
+//
+// Returns:
+//
+//	PancliRESTClientOption - The client option.
+func WithRESTTLSConfig(config *tls.Config) PancliRESTClientOption {
+	return func(p *PancliRESTClient) {
+		p.transport.TLSClientConfig = config
+	}
+}
+
+// WithRESTIdempotencyKeys sends an Idempotency-Key header with mutating requests, so a
+// request whose connection failed after it was sent can be resent once without applying
+// it twice.
+//
+// Parameters:
+//
+//	enabled - Whether to send idempotency keys.
+//
+// Returns:
+//
+//	PancliRESTClientOption - The client option.
+func WithRESTIdempotencyKeys(enabled bool) PancliRESTClientOption {
+	return func(p *PancliRESTClient) {
+		p.idempotencyKeys = enabled
+	}
+}
+
+// WithRESTForbidPasswordAuth authenticates with API tokens only. Secrets without an API
+// token are rejected with ErrorUnauthenticated instead of using basic authentication.
+//
+// Parameters:
+//
+//	forbid - Whether password authentication is forbidden.
+//
+// Returns:
+//
+//	PancliRESTClientOption - The client option.
+func WithRESTForbidPasswordAuth(forbid bool) PancliRESTClientOption {
+	return func(p *PancliRESTClient) {
+		p.forbidPasswordAuth = forbid
+	}
+}
+
+// NewRESTTLSConfig returns the TLS configuration of connections to the realm REST API.
+//
+// Parameters:
+//
+//	caFile             - PEM file with the certificate authorities of the realm, empty for the
+//	                     system certificate pool.
+//	insecureSkipVerify - Whether to skip the verification of the realm certificate.
+//
+// Returns:
+//
+//	*tls.Config - The TLS configuration.
+//	error       - Error if the CA file cannot be read or holds no certificate.
+func NewRESTTLSConfig(caFile string, insecureSkipVerify bool) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecureSkipVerify, //nolint:gosec // explicitly requested for test realms
+	}
+	if caFile == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read realm CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificate found in realm CA file %s", caFile)
+	}
+	config.RootCAs = pool
+	return config, nil
+}
+
+// NewPancliRESTClient creates a new instance of PancliRESTClient.
+//
+// Parameters:
+//
+//	opts - Optional client settings.
+//
+// Returns:
+//
+//	*PancliRESTClient - The initialized PancliRESTClient.
+func NewPancliRESTClient(opts ...PancliRESTClientOption) *PancliRESTClient {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = restMaxIdleConnsPerHost
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	p := &PancliRESTClient{
+		client:    &http.Client{Transport: transport, Timeout: DefaultRESTTimeout},
+		transport: transport,
+		port:      DefaultRESTPort,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// DebugState returns the internal state of the client for debugging.
+//
+// Returns:
+//
+//	map[string]any - The client state.
+func (p *PancliRESTClient) DebugState() map[string]any {
+	return map[string]any{
+		"serialized_realms":           p.realmLocks.realms(),
+		"unreachable_realm_addresses": p.health.unhealthy(),
+	}
+}
+
+// VerifyCredentials authenticates with the given secrets by listing the volumes of the realm.
+//
+// Parameters:
+//
+//	secrets - Map of authentication secrets.
+//
+// Returns:
+//
+//	error - ErrorUnauthenticated if the credentials are rejected, ErrorUnavailable if the
+//	        realm cannot be reached, ErrorInternal otherwise.
+func (p *PancliRESTClient) VerifyCredentials(secrets map[string]string) error {
+	err := p.do(context.Background(), secrets, restRequest{method: http.MethodGet, path: "/volumes"}, nil)
+	if err == nil || errors.Is(err, ErrorUnauthenticated) || errors.Is(err, ErrorUnavailable) {
+		return err
+	}
+	return fmt.Errorf("%w: %s", ErrorInternal, err)
+}
+
+// restVolume is a volume in realm REST API responses.
+type restVolume struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	State       string  `json:"state"`
+	SoftQuotaGB float64 `json:"soft_quota_gb"`
+	HardQuotaGB float64 `json:"hard_quota_gb"`
+	Bladeset    struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"bladeset"`
+	Encryption  string `json:"encryption"`
+	Description string `json:"description,omitempty"`
+}
+
+// toVolume converts the volume into a utils.Volume with quotas in the given unit.
+func (v *restVolume) toVolume(unit utils.QuotaUnit) utils.Volume {
+	return utils.Volume{
+		ID:          v.ID,
+		Name:        utils.VolumeName(v.Name),
+		State:       utils.VolumeState(v.State),
+		Soft:        v.SoftQuotaGB,
+		Hard:        v.HardQuotaGB,
+		Bset:        utils.Bladeset{ID: v.Bladeset.ID, Name: v.Bladeset.Name},
+		Encryption:  v.Encryption,
+		Description: v.Description,
+		QuotaUnit:   unit,
+	}
+}
+
+// restSnapshot is a snapshot in realm REST API responses.
+type restSnapshot struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Volume string `json:"volume"`
+	// CreationTime is the creation time of the snapshot in seconds since the epoch.
+	CreationTime int64 `json:"creation_time"`
+}
+
+// toSnapshot converts the snapshot into a utils.Snapshot.
+func (s *restSnapshot) toSnapshot() utils.Snapshot {
+	return utils.Snapshot{
+		ID:           s.ID,
+		Name:         s.Name,
+		VolumeName:   utils.VolumeName(s.Volume),
+		CreationTime: s.CreationTime,
+	}
+}
+
+// restDirectory is a directory of a volume in realm REST API responses.
+type restDirectory struct {
+	Name        string  `json:"name"`
+	Volume      string  `json:"volume"`
+	HardQuotaGB float64 `json:"hard_quota_gb"`
+}
+
+// toDirectory converts the directory into a utils.Directory with the quota in the given unit.
+func (d *restDirectory) toDirectory(unit utils.QuotaUnit) utils.Directory {
+	return utils.Directory{
+		Name:       d.Name,
+		VolumeName: utils.VolumeName(d.Volume),
+		Hard:       d.HardQuotaGB,
+		QuotaUnit:  unit,
+	}
+}
+
+// restCreateVolumeRequest is the body of volume creation requests.
+type restCreateVolumeRequest struct {
+	Name string `json:"name"`
+	// Parameters are the pancli volume parameters by their short storage class key,
+	// e.g. "bladeset" or "soft", with quotas in the quota unit of the realm.
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// Source is the snapshot the volume is created from, nil for an empty volume.
+	Source *restVolumeSource `json:"source,omitempty"`
+}
+
+// restVolumeSource is the snapshot a volume is created from.
+type restVolumeSource struct {
+	Volume   string `json:"volume"`
+	Snapshot string `json:"snapshot"`
+}
+
+// restParameters returns the volume parameters passed to the realm by their short storage
+// class key, skipping the driver-only parameters. It mirrors getOptionalParameters.
+//
+// Parameters:
+//
+//	params - The volume creation parameters.
+//
+// Returns:
+//
+//	map[string]string - The parameters of the creation request.
+func restParameters(params VolumeCreateParams) map[string]string {
+	out := map[string]string{}
+	for key, value := range params {
+		keyParam := utils.VolumeParameters.GetSCKey(key)
+		if value == "" || keyParam != key || utils.VolumeParameters.GetFmt(keyParam) == "" {
+			continue
+		}
+
+		// Backward compatibility: skip encryption parameter if it is not requested explicitly as "on"
+		if keyParam == utils.VolumeParameters.GetSCKey("encryption") && value != "on" {
+			continue
+		}
+
+		out[strings.TrimPrefix(key, utils.VendorPrefix)] = value
+	}
+	return out
+}
+
+// CreateVolume creates a volume using the provided arguments and returns the created volume object.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume to create.
+//	params     - The volume creation parameters.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.Volume - The created volume object.
+//	error         - Error if creation fails, a *QuotaLimitError if the quotas are outside the
+//	                limits of the realm.
+func (p *PancliRESTClient) CreateVolume(ctx context.Context, volumeName string, params VolumeCreateParams, secrets map[string]string) (*utils.Volume, error) {
+	return p.createVolume(ctx, volumeName, params, nil, secrets)
+}
+
+// CreateVolumeFromSnapshot creates a volume with the content of a snapshot and returns the
+// created volume object.
+//
+// Parameters:
+//
+//	ctx          - The context of the request.
+//	volumeName   - The name of the volume to create.
+//	sourceVolume - The name of the snapshotted volume.
+//	snapshotName - The name of the snapshot to restore.
+//	params       - The volume creation parameters.
+//	secrets      - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.Volume - The created volume object.
+//	error         - ErrorNotFound if the snapshot does not exist, or other errors if creation fails.
+func (p *PancliRESTClient) CreateVolumeFromSnapshot(ctx context.Context, volumeName, sourceVolume, snapshotName string, params VolumeCreateParams, secrets map[string]string) (*utils.Volume, error) {
+	return p.createVolume(ctx, volumeName, params, &restVolumeSource{Volume: sourceVolume, Snapshot: snapshotName}, secrets)
+}
+
+// createVolume creates a volume, optionally from a snapshot, and returns the created volume object.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume to create.
+//	params     - The volume creation parameters.
+//	source     - The snapshot the volume is created from, nil for an empty volume.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.Volume - The created volume object.
+//	error         - Error if creation fails.
+func (p *PancliRESTClient) createVolume(ctx context.Context, volumeName string, params VolumeCreateParams, source *restVolumeSource, secrets map[string]string) (*utils.Volume, error) {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	create := func(params VolumeCreateParams) (*restVolume, error) {
+		var created restVolume
+		err := p.do(ctx, secrets, restRequest{
+			method: http.MethodPost,
+			path:   "/volumes",
+			body: restCreateVolumeRequest{
+				Name:       volumeName,
+				Parameters: restParameters(params.inQuotaUnit(unit)),
+				Source:     source,
+			},
+		}, &created)
+		return &created, err
+	}
+
+	created, err := create(params)
+
+	// some realm versions do not support setting the hard quota, create a soft-quota-only volume if tolerated
+	degraded := false
+	if err != nil && params.TolerateMissingHardQuota() && isHardQuotaUnsupported(err) {
+		realm := secrets[utils.RealmConnectionContext.RealmAddress]
+		if _, warned := p.hardQuotaWarned.LoadOrStore(realm, struct{}{}); !warned {
+			requestLogger(ctx).Info("WARNING: realm does not support hard quota, creating volumes with soft quota only", "realm", realm, "error", err.Error())
+		}
+		degraded = params.HardGB() > 0
+		created, err = create(params.withoutHardQuota())
+	}
+	if err != nil {
+		return nil, quotaLimitError(err, unit)
+	}
+
+	volume := created.toVolume(unit)
+	volume.HardQuotaDegraded = degraded
+	return &volume, nil
+}
+
+// DeleteVolume deletes a volume by its name.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume to delete.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	error - ErrorNotFound if the volume does not exist, or other errors if deletion fails.
+func (p *PancliRESTClient) DeleteVolume(ctx context.Context, volumeName string, secrets map[string]string) error {
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return p.do(ctx, secrets, restRequest{method: http.MethodDelete, path: restPath("volumes", volumeName)}, nil)
+}
+
+// ExpandVolume sets the soft quota of a volume to the specified size in bytes.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume to expand.
+//	sizeBytes  - The target size in bytes.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	error - Error if expansion fails, a *QuotaLimitError if the size exceeds the limits of the realm.
+func (p *PancliRESTClient) ExpandVolume(ctx context.Context, volumeName string, sizeBytes int64, secrets map[string]string) error {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return err
+	}
+	// convert size from bytes to the quota unit of the realm, rounded like pancli arguments
+	sizeGB, _ := strconv.ParseFloat(strconv.FormatFloat(unit.FromBytes(sizeBytes), 'f', 2, 64), 64)
+
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = p.do(ctx, secrets, restRequest{
+		method: http.MethodPatch,
+		path:   restPath("volumes", volumeName),
+		body:   map[string]float64{"soft_quota_gb": sizeGB},
+	}, nil)
+	if err != nil {
+		return quotaLimitError(err, unit)
+	}
+	return nil
+}
+
+// ModifyVolume changes the mutable parameters of a volume, see MutableVolumeParameters.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume to modify.
+//	params     - The changed parameters.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	error - Error if the modification fails, a *QuotaLimitError if the hard quota exceeds
+//	        the limits of the realm.
+func (p *PancliRESTClient) ModifyVolume(ctx context.Context, volumeName string, params VolumeModifyParams, secrets map[string]string) error {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]any{}
+	for _, name := range []string{"description", "recovery", "efsa"} {
+		if value, ok := params[utils.VolumeParameters.GetSCKey(name)]; ok {
+			body[name] = value
+		}
+	}
+	if hardGB, ok := params.HardGB(); ok {
+		// rounded like pancli arguments
+		body["hard_quota_gb"], _ = strconv.ParseFloat(strconv.FormatFloat(unit.FromBytes(utils.GBToBytes(hardGB)), 'f', 2, 64), 64)
+	}
+
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = p.do(ctx, secrets, restRequest{
+		method: http.MethodPatch,
+		path:   restPath("volumes", volumeName),
+		body:   body,
+	}, nil)
+	if err != nil {
+		return quotaLimitError(err, unit)
+	}
+	return nil
+}
+
+// ListVolumes retrieves a list of all volumes and returns them as a VolumeList object.
+//
+// Parameters:
+//
+//	ctx     - The context of the request.
+//	secrets - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.VolumeList - The volume list.
+//	error             - Error if retrieval or parsing fails.
+func (p *PancliRESTClient) ListVolumes(ctx context.Context, secrets map[string]string) (*utils.VolumeList, error) {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Volumes []restVolume `json:"volumes"`
+	}
+	if err := p.do(ctx, secrets, restRequest{method: http.MethodGet, path: "/volumes"}, &list); err != nil {
+		return nil, err
+	}
+
+	vols := &utils.VolumeList{}
+	for i := range list.Volumes {
+		vols.Volumes = append(vols.Volumes, list.Volumes[i].toVolume(unit))
+	}
+	return vols, nil
+}
+
+// GetVolume retrieves a specific volume by its name and returns it as a Volume object.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume to retrieve.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.Volume - The volume object.
+//	error         - ErrorNotFound if the volume does not exist, or other errors if retrieval fails.
+func (p *PancliRESTClient) GetVolume(ctx context.Context, volumeName string, secrets map[string]string) (*utils.Volume, error) {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	var vol restVolume
+	if err := p.do(ctx, secrets, restRequest{method: http.MethodGet, path: restPath("volumes", volumeName)}, &vol); err != nil {
+		return nil, err
+	}
+
+	volume := vol.toVolume(unit)
+	return &volume, nil
+}
+
+// CreateSnapshot creates a snapshot of a volume and returns the created snapshot object.
+//
+// Parameters:
+//
+//	ctx          - The context of the request.
+//	volumeName   - The name of the volume to snapshot.
+//	snapshotName - The name of the snapshot to create.
+//	secrets      - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.Snapshot - The created snapshot object.
+//	error           - ErrorAlreadyExist if the volume already has a snapshot with the name,
+//	                  ErrorNotFound if the volume does not exist, or other errors if creation fails.
+func (p *PancliRESTClient) CreateSnapshot(ctx context.Context, volumeName, snapshotName string, secrets map[string]string) (*utils.Snapshot, error) {
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var created restSnapshot
+	err = p.do(ctx, secrets, restRequest{
+		method: http.MethodPost,
+		path:   restPath("volumes", volumeName, "snapshots"),
+		body:   map[string]string{"name": snapshotName},
+	}, &created)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := created.toSnapshot()
+	return &snapshot, nil
+}
+
+// DeleteSnapshot deletes a snapshot of a volume.
+//
+// Parameters:
+//
+//	ctx          - The context of the request.
+//	volumeName   - The name of the snapshotted volume.
+//	snapshotName - The name of the snapshot to delete.
+//	secrets      - Map of authentication secrets.
+//
+// Returns:
+//
+//	error - ErrorNotFound if the snapshot does not exist, or other errors if deletion fails.
+func (p *PancliRESTClient) DeleteSnapshot(ctx context.Context, volumeName, snapshotName string, secrets map[string]string) error {
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return p.do(ctx, secrets, restRequest{method: http.MethodDelete, path: restPath("volumes", volumeName, "snapshots", snapshotName)}, nil)
+}
+
+// ListSnapshots retrieves the snapshots of a volume, or of all volumes, and returns them as a
+// SnapshotList object.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume whose snapshots are listed, empty for all volumes.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.SnapshotList - The snapshot list.
+//	error               - ErrorNotFound if the volume does not exist, or other errors if
+//	                      retrieval or parsing fails.
+func (p *PancliRESTClient) ListSnapshots(ctx context.Context, volumeName string, secrets map[string]string) (*utils.SnapshotList, error) {
+	path := "/snapshots"
+	if volumeName != "" {
+		path = restPath("volumes", volumeName, "snapshots")
+	}
+
+	var list struct {
+		Snapshots []restSnapshot `json:"snapshots"`
+	}
+	if err := p.do(ctx, secrets, restRequest{method: http.MethodGet, path: path}, &list); err != nil {
+		return nil, err
+	}
+
+	snapshots := &utils.SnapshotList{}
+	for i := range list.Snapshots {
+		snapshots.Snapshots = append(snapshots.Snapshots, list.Snapshots[i].toSnapshot())
+	}
+	return snapshots, nil
+}
+
+// CreateDirectory creates a directory with a directory quota in a volume and returns the
+// created directory object.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume holding the directory.
+//	directory  - The name of the directory to create.
+//	hardBytes  - The directory quota in bytes.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.Directory - The created directory object.
+//	error            - ErrorAlreadyExist if the volume already has a directory with the name,
+//	                   ErrorNotFound if the volume does not exist, or other errors if creation fails.
+func (p *PancliRESTClient) CreateDirectory(ctx context.Context, volumeName, directory string, hardBytes int64, secrets map[string]string) (*utils.Directory, error) {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return nil, err
+	}
+	// rounded like pancli arguments
+	hardGB, _ := strconv.ParseFloat(strconv.FormatFloat(unit.FromBytes(hardBytes), 'f', 2, 64), 64)
+
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var created restDirectory
+	err = p.do(ctx, secrets, restRequest{
+		method: http.MethodPost,
+		path:   restPath("volumes", volumeName, "directories"),
+		body:   map[string]any{"name": directory, "hard_quota_gb": hardGB},
+	}, &created)
+	if err != nil {
+		return nil, quotaLimitError(err, unit)
+	}
+
+	dir := created.toDirectory(unit)
+	return &dir, nil
+}
+
+// DeleteDirectory deletes a directory of a volume with its content.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume holding the directory.
+//	directory  - The name of the directory to delete.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	error - ErrorNotFound if the directory does not exist, or other errors if deletion fails.
+func (p *PancliRESTClient) DeleteDirectory(ctx context.Context, volumeName, directory string, secrets map[string]string) error {
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return p.do(ctx, secrets, restRequest{method: http.MethodDelete, path: restPath("volumes", volumeName, "directories", directory)}, nil)
+}
+
+// SetDirectoryQuota changes the directory quota of a directory.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume holding the directory.
+//	directory  - The name of the directory.
+//	hardBytes  - The new directory quota in bytes.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	error - ErrorNotFound if the directory does not exist, a *QuotaLimitError if the quota
+//	        exceeds the limits of the realm, or other errors if the change fails.
+func (p *PancliRESTClient) SetDirectoryQuota(ctx context.Context, volumeName, directory string, hardBytes int64, secrets map[string]string) error {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return err
+	}
+	// rounded like pancli arguments
+	hardGB, _ := strconv.ParseFloat(strconv.FormatFloat(unit.FromBytes(hardBytes), 'f', 2, 64), 64)
+
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = p.do(ctx, secrets, restRequest{
+		method: http.MethodPatch,
+		path:   restPath("volumes", volumeName, "directories", directory),
+		body:   map[string]float64{"hard_quota_gb": hardGB},
+	}, nil)
+	if err != nil {
+		return quotaLimitError(err, unit)
+	}
+	return nil
+}
+
+// GetDirectory reads the details of a directory of a volume.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume holding the directory.
+//	directory  - The name of the directory.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.Directory - The directory object.
+//	error            - ErrorNotFound if the volume or directory does not exist, or other
+//	                   errors if retrieval fails.
+func (p *PancliRESTClient) GetDirectory(ctx context.Context, volumeName, directory string, secrets map[string]string) (*utils.Directory, error) {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	var got restDirectory
+	if err := p.do(ctx, secrets, restRequest{method: http.MethodGet, path: restPath("volumes", volumeName, "directories", directory)}, &got); err != nil {
+		return nil, err
+	}
+
+	dir := got.toDirectory(unit)
+	return &dir, nil
+}
+
+// GetCapacity returns the free space of the realm available for new volumes, summing the free
+// space of the matching bladesets.
+//
+// Parameters:
+//
+//	ctx      - The context of the request.
+//	bladeset - The name of the bladeset, empty for all bladesets of the realm.
+//	secrets  - Map of authentication secrets.
+//
+// Returns:
+//
+//	int64 - The free space in bytes.
+//	error - ErrorNotFound if the bladeset does not exist, or other errors if retrieval fails.
+func (p *PancliRESTClient) GetCapacity(ctx context.Context, bladeset string, secrets map[string]string) (int64, error) {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return 0, err
+	}
+
+	var list struct {
+		Bladesets []struct {
+			ID          string  `json:"id"`
+			Name        string  `json:"name"`
+			TotalGB     float64 `json:"total_gb"`
+			AvailableGB float64 `json:"available_gb"`
+		} `json:"bladesets"`
+	}
+	if err := p.do(ctx, secrets, restRequest{method: http.MethodGet, path: "/bladesets"}, &list); err != nil {
+		return 0, err
+	}
+
+	var available int64
+	found := false
+	for _, b := range list.Bladesets {
+		if bladeset != "" && b.Name != bladeset {
+			continue
+		}
+		capacity := utils.BladesetCapacity{ID: b.ID, Name: b.Name, TotalGB: b.TotalGB, AvailableGB: b.AvailableGB, QuotaUnit: unit}
+		available += capacity.GetAvailableBytes()
+		found = true
+	}
+	if bladeset != "" && !found {
+		return 0, fmt.Errorf("%w: bladeset %s", ErrorNotFound, bladeset)
+	}
+
+	return available, nil
+}
+
+// GetRealmFeatures detects the optional features supported by the realm. Each feature is
+// probed with a read-only request; a feature is unsupported if the realm rejects the request
+// as not implemented.
+//
+// Parameters:
+//
+//	ctx     - The context of the request.
+//	secrets - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.RealmFeatures - The features of the realm.
+//	error                - Error if a probe fails for another reason than an unsupported request.
+func (p *PancliRESTClient) GetRealmFeatures(ctx context.Context, secrets map[string]string) (*utils.RealmFeatures, error) {
+	features := &utils.RealmFeatures{}
+	probes := []struct {
+		name    string
+		path    string
+		feature *bool
+	}{
+		{name: "snapshots", path: "/snapshots", feature: &features.Snapshots},
+		{name: "capacity", path: "/bladesets", feature: &features.Capacity},
+	}
+
+	for _, probe := range probes {
+		err := p.do(ctx, secrets, restRequest{method: http.MethodGet, path: probe.path}, nil)
+		switch {
+		case err == nil:
+			*probe.feature = true
+		case IsUnsupportedCommand(err):
+			requestLogger(ctx).V(4).Info("realm does not support feature", "feature", probe.name, "error", err.Error())
+		default:
+			return nil, fmt.Errorf("failed to detect %s support of the realm: %w", probe.name, err)
+		}
+	}
+
+	return features, nil
+}
+
+// restRequest is a request to the realm REST API.
+type restRequest struct {
+	method string
+	// path is the escaped path below restAPIPath, e.g. built with restPath
+	path string
+	// body is encoded as JSON, nil for requests without body
+	body any
+}
+
+// restPath joins escaped path segments, e.g. volume names, into a request path.
+func restPath(segments ...string) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		b.WriteString("/")
+		b.WriteString(url.PathEscape(segment))
+	}
+	return b.String()
+}
+
+// do sends a request to the first reachable address of the realm and decodes the JSON
+// response into out. Mutating requests whose connection failed after they were sent are
+// resent once with the same idempotency key if idempotency keys are enabled.
+//
+// Parameters:
+//
+//	ctx     - The context of the request, cancelling it aborts the request.
+//	secrets - Map of authentication secrets.
+//	req     - The request.
+//	out     - The value the response is decoded into, nil to ignore the response body.
+//
+// Returns:
+//
+//	error - The error of the realm response, or ErrorUnavailable if the realm cannot be reached.
+func (p *PancliRESTClient) do(ctx context.Context, secrets map[string]string, req restRequest, out any) error {
+	realmSecrets := utils.NewRealmSecrets(secrets)
+	if !realmSecrets.Has(utils.RealmConnectionContext.RealmAddress) {
+		return fmt.Errorf("missing %s in secrets", utils.RealmConnectionContext.RealmAddress)
+	}
+	realm := realmSecrets.Realm
+	addresses, err := utils.ParseRealmAddresses(realm)
+	if err != nil {
+		return err
+	}
+	authorize, err := restAuthorization(realmSecrets, p.forbidPasswordAuth)
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	if req.body != nil {
+		if body, err = json.Marshal(req.body); err != nil {
+			return fmt.Errorf("failed to encode %s %s request: %w", req.method, req.path, err)
+		}
+	}
+
+	mutating := req.method != http.MethodGet
+	var key string
+	if mutating && p.idempotencyKeys {
+		key = newIdempotencyToken()
+	}
+
+	requestLogger(ctx).V(5).Info("REST request", "method", req.method, "path", req.path)
+	err = p.send(ctx, realm, addresses, req, body, key, authorize, out)
+	if key != "" && errors.Is(err, ErrorOutcomeUnknown) && ctx.Err() == nil {
+		requestLogger(ctx).Info("connection failed after the request was sent, resending it with the same idempotency key", "method", req.method, "path", req.path, "error", err.Error())
+		err = p.send(ctx, realm, addresses, req, body, key, authorize, out)
+	}
+	return err
+}
+
+// send sends a request to the realm addresses in turn until one is reachable.
+func (p *PancliRESTClient) send(ctx context.Context, realm string, addresses []string, req restRequest, body []byte, key string, authorize func(*http.Request), out any) error {
+	var errs []error
+	for _, address := range p.health.order(realm, addresses) {
+		host, err := utils.ParseRealmAddress(address)
+		if err != nil {
+			return err
+		}
+
+		httpReq, err := http.NewRequestWithContext(ctx, req.method, "https://"+net.JoinHostPort(host, p.port)+restAPIPath+req.path, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create %s %s request: %w", req.method, req.path, err)
+		}
+		httpReq.Header.Set("Accept", "application/json")
+		if body != nil {
+			httpReq.Header.Set("Content-Type", "application/json")
+		}
+		if key != "" {
+			httpReq.Header.Set("Idempotency-Key", key)
+		}
+		authorize(httpReq)
+
+		resp, err := p.client.Do(httpReq)
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("%w: %w: realm request aborted: %w", ErrorOutcomeUnknown, ErrorUnavailable, ctx.Err())
+			}
+			if !requestNotSent(err) {
+				if req.method == http.MethodGet {
+					return fmt.Errorf("%w: %v", ErrorUnavailable, err)
+				}
+				// the realm may have received the request
+				return fmt.Errorf("%w: %w: %v", ErrorOutcomeUnknown, ErrorUnavailable, err)
+			}
+			p.health.failed(realm, address)
+			requestLogger(ctx).V(4).Info("failed to connect to realm address", "realm", realm, "address", address, "error", err.Error())
+			errs = append(errs, err)
+			continue
+		}
+		p.health.succeeded(realm, address)
+		return decodeRESTResponse(resp, out)
+	}
+
+	if len(errs) == 1 {
+		return fmt.Errorf("%w: %v", ErrorUnavailable, errs[0])
+	}
+	return fmt.Errorf("%w: failed to connect to any of the realm addresses: %v", ErrorUnavailable, errors.Join(errs...))
+}
+
+// restAuthorization returns a function adding the credentials of the secrets to requests,
+// preferring the API token over the user and password.
+//
+// Parameters:
+//
+//	secrets        - The parsed realm secrets.
+//	forbidPassword - Whether basic authentication with a password is forbidden.
+//
+// Returns:
+//
+//	func(*http.Request) - Adds the Authorization header to a request.
+//	error               - ErrorUnauthenticated if the secrets hold neither an API token nor a
+//	                      password, or no API token while passwords are forbidden.
+func restAuthorization(secrets utils.RealmSecrets, forbidPassword bool) (func(*http.Request), error) {
+	if token := secrets.APIToken; token != "" {
+		return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }, nil
+	}
+	if forbidPassword {
+		return nil, fmt.Errorf("%w: %w: the REST realm provider requires %s in secrets", ErrorUnauthenticated,
+			utils.ErrPasswordAuthForbidden, utils.RealmConnectionContext.APIToken)
+	}
+
+	user, password := secrets.Username, secrets.Password
+	if user == "" || password == "" {
+		return nil, fmt.Errorf("%w: the REST realm provider requires %s, or %s and %s in secrets", ErrorUnauthenticated,
+			utils.RealmConnectionContext.APIToken, utils.RealmConnectionContext.Username, utils.RealmConnectionContext.Password)
+	}
+	return func(r *http.Request) { r.SetBasicAuth(user, password) }, nil
+}
+
+// requestNotSent reports whether a request failed before it reached the realm, so it can
+// be sent to another realm address.
+func requestNotSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var certErr *tls.CertificateVerificationError
+	return errors.As(err, &certErr)
+}
+
+// decodeRESTResponse decodes a successful JSON response into out, or the JSON error of a
+// failed response into the matching error.
+//
+// Parameters:
+//
+//	resp - The response of the realm.
+//	out  - The value the response is decoded into, nil to ignore the response body.
+//
+// Returns:
+//
+//	error - The error of a failed response, or ErrorInternal if the response cannot be parsed.
+func decodeRESTResponse(resp *http.Response, out any) error {
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, restMaxResponseSize))
+	if err != nil {
+		return fmt.Errorf("%w: failed to read realm response: %v", ErrorUnavailable, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return parseRESTError(resp.StatusCode, body)
+	}
+	if out == nil || len(body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("%w: cannot parse realm response: %v", ErrorInternal, err)
+	}
+	return nil
+}
+
+// parseRESTError returns the error of a failed realm response. The error code of the JSON
+// body takes precedence over the HTTP status.
+//
+// Parameters:
+//
+//	status - The HTTP status code.
+//	body   - The response body.
+//
+// Returns:
+//
+//	error - The error wrapping the realm message.
+func parseRESTError(status int, body []byte) error {
+	err, ok := restStatusErrors[status]
+	if !ok {
+		err = ErrorInternal
+	}
+	message := fmt.Sprintf("%d %s", status, http.StatusText(status))
+
+	var resp struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &resp) == nil {
+		if resp.Error.Message != "" {
+			message = resp.Error.Message
+		}
+		if resp.Error.Code == "unsupported" {
+			err = ErrorNotImplemented
+		} else if named, ok := errorNames[resp.Error.Code]; ok && named != nil {
+			err = named
+		}
+	}
+
+	return wrapRealmError(err, message)
+}
@@ -0,0 +1,397 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// restCall is a request received by the test realm REST API.
+type restCall struct {
+	method        string
+	path          string
+	authorization string
+	key           string
+	body          map[string]any
+}
+
+// restRealm is a test realm REST API recording the received requests.
+type restRealm struct {
+	*httptest.Server
+	mux   *http.ServeMux
+	calls []restCall
+	sync.Mutex
+}
+
+// newRESTRealm starts a TLS server answering the realm REST API with the handlers added to mux.
+func newRESTRealm(t *testing.T) *restRealm {
+	r := &restRealm{mux: http.NewServeMux()}
+	r.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		call := restCall{
+			method:        req.Method,
+			path:          req.URL.EscapedPath(),
+			authorization: req.Header.Get("Authorization"),
+			key:           req.Header.Get("Idempotency-Key"),
+		}
+		if data, _ := io.ReadAll(req.Body); len(data) > 0 {
+			_ = json.Unmarshal(data, &call.body)
+		}
+		r.Lock()
+		r.calls = append(r.calls, call)
+		r.Unlock()
+		r.mux.ServeHTTP(w, req)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+// Calls returns the requests received so far.
+func (r *restRealm) Calls() []restCall {
+	r.Lock()
+	defer r.Unlock()
+	return append([]restCall(nil), r.calls...)
+}
+
+// client returns a client trusting the certificate of the server, via a CA file.
+func (r *restRealm) client(t *testing.T, opts ...PancliRESTClientOption) *PancliRESTClient {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: r.Certificate().Raw}), 0o600))
+	tlsConfig, err := NewRESTTLSConfig(caFile, false)
+	require.NoError(t, err)
+
+	_, port, err := net.SplitHostPort(r.Listener.Addr().String())
+	require.NoError(t, err)
+	return NewPancliRESTClient(append([]PancliRESTClientOption{WithRESTPort(port), WithRESTTLSConfig(tlsConfig)}, opts...)...)
+}
+
+// restSecrets returns the secrets of the test realm with the given credentials.
+func restSecrets(credentials map[string]string) map[string]string {
+	secrets := map[string]string{utils.RealmConnectionContext.RealmAddress: "127.0.0.1"}
+	for k, v := range credentials {
+		secrets[k] = v
+	}
+	return secrets
+}
+
+// writeJSON writes a JSON response with the given status.
+func writeJSON(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, body)
+}
+
+// TestPancliRESTClient runs the volume operations against a test realm REST API.
+func TestPancliRESTClient(t *testing.T) {
+	realm := newRESTRealm(t)
+	realm.mux.HandleFunc("POST /api/v1/volumes", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusCreated, `{"id": "372", "name": "pvc-1", "state": "Online", "soft_quota_gb": 1, "hard_quota_gb": 2, "bladeset": {"id": "1", "name": "Set 1"}}`)
+	})
+	realm.mux.HandleFunc("GET /api/v1/volumes", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, `{"volumes": [{"id": "1", "name": "pvc-1", "state": "Online"}, {"id": "2", "name": "pvc-2", "state": "Online"}]}`)
+	})
+	realm.mux.HandleFunc("GET /api/v1/volumes/{volume}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("volume") != "pvc-1" {
+			writeJSON(w, http.StatusNotFound, `{"error": {"code": "not_found", "message": "volume does not exist"}}`)
+			return
+		}
+		writeJSON(w, http.StatusOK, `{"id": "372", "name": "pvc-1", "state": "Online", "soft_quota_gb": 1}`)
+	})
+	realm.mux.HandleFunc("PATCH /api/v1/volumes/{volume}", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusBadRequest, `{"error": {"code": "out_of_range", "message": "soft quota exceeds the maximum of 1024.00 GB"}}`)
+	})
+	realm.mux.HandleFunc("DELETE /api/v1/volumes/{volume}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	realm.mux.HandleFunc("POST /api/v1/volumes/{volume}/snapshots", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusConflict, `{"error": {"message": "snapshot already exists"}}`)
+	})
+	realm.mux.HandleFunc("GET /api/v1/snapshots", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusNotImplemented, `{"error": {"code": "unsupported", "message": "snapshots are not supported"}}`)
+	})
+	realm.mux.HandleFunc("POST /api/v1/volumes/{volume}/directories", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusCreated, `{"name": "pvc-3", "volume": "pvc-1", "hard_quota_gb": 1.5}`)
+	})
+	realm.mux.HandleFunc("GET /api/v1/volumes/{volume}/directories/{directory}", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, `{"name": "pvc-3", "volume": "pvc-1", "hard_quota_gb": 3}`)
+	})
+	realm.mux.HandleFunc("PATCH /api/v1/volumes/{volume}/directories/{directory}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	realm.mux.HandleFunc("DELETE /api/v1/volumes/{volume}/directories/{directory}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	realm.mux.HandleFunc("GET /api/v1/bladesets", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, `{"bladesets": [{"name": "Set 1", "total_gb": 100, "available_gb": 10}, {"name": "Set 2", "total_gb": 100, "available_gb": 5}]}`)
+	})
+
+	panfs := realm.client(t)
+	secrets := restSecrets(map[string]string{utils.RealmConnectionContext.APIToken: "token"})
+
+	t.Run("CreateVolume", func(t *testing.T) {
+		params, err := NewVolumeCreateParamsBuilder().SetSoftBytes(1 << 30).SetHardBytes(2 << 30).SetBladeset("Set 1").Build()
+		require.NoError(t, err)
+
+		vol, err := panfs.CreateVolume(t.Context(), "pvc-1", params, secrets)
+		require.NoError(t, err)
+		assert.Equal(t, "372", vol.ID)
+		assert.Equal(t, utils.VolumeStateOnline, vol.State)
+		assert.Equal(t, "Set 1", vol.Bset.Name)
+		assert.Equal(t, int64(1<<30), vol.GetSoftQuotaBytes())
+
+		calls := realm.Calls()
+		call := calls[len(calls)-1]
+		assert.Equal(t, "Bearer token", call.authorization)
+		assert.Equal(t, "pvc-1", call.body["name"])
+		assert.Equal(t, map[string]any{"soft": "1.00", "hard": "2.00", "bladeset": "Set 1"}, call.body["parameters"])
+		assert.NotContains(t, call.body, "source")
+	})
+
+	t.Run("CreateVolumeFromSnapshot", func(t *testing.T) {
+		_, err := panfs.CreateVolumeFromSnapshot(t.Context(), "pvc-1", "pvc-0", "snap-1", VolumeCreateParams{}, secrets)
+		require.NoError(t, err)
+
+		calls := realm.Calls()
+		assert.Equal(t, map[string]any{"volume": "pvc-0", "snapshot": "snap-1"}, calls[len(calls)-1].body["source"])
+	})
+
+	t.Run("ListAndGetVolumes", func(t *testing.T) {
+		vols, err := panfs.ListVolumes(t.Context(), secrets)
+		require.NoError(t, err)
+		require.Len(t, vols.Volumes, 2)
+		assert.Equal(t, utils.VolumeName("pvc-2"), vols.Volumes[1].Name)
+
+		vol, err := panfs.GetVolume(t.Context(), "pvc-1", secrets)
+		require.NoError(t, err)
+		assert.Equal(t, "372", vol.ID)
+	})
+
+	t.Run("DeleteVolume", func(t *testing.T) {
+		assert.NoError(t, panfs.DeleteVolume(t.Context(), "pvc/1", secrets))
+
+		calls := realm.Calls()
+		assert.Equal(t, "/api/v1/volumes/pvc%2F1", calls[len(calls)-1].path)
+	})
+
+	t.Run("Directories", func(t *testing.T) {
+		dir, err := panfs.CreateDirectory(t.Context(), "pvc-1", "pvc-3", 3<<29, secrets)
+		require.NoError(t, err)
+		assert.Equal(t, "pvc-1/pvc-3", dir.VolumeID())
+		assert.Equal(t, int64(3<<29), dir.GetHardQuotaBytes())
+		calls := realm.Calls()
+		assert.Equal(t, map[string]any{"name": "pvc-3", "hard_quota_gb": 1.5}, calls[len(calls)-1].body)
+
+		require.NoError(t, panfs.SetDirectoryQuota(t.Context(), "pvc-1", "pvc-3", 3<<30, secrets))
+		calls = realm.Calls()
+		assert.Equal(t, "/api/v1/volumes/pvc-1/directories/pvc-3", calls[len(calls)-1].path)
+		assert.Equal(t, map[string]any{"hard_quota_gb": 3.0}, calls[len(calls)-1].body)
+
+		dir, err = panfs.GetDirectory(t.Context(), "pvc-1", "pvc-3", secrets)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3<<30), dir.GetHardQuotaBytes())
+
+		assert.NoError(t, panfs.DeleteDirectory(t.Context(), "pvc-1", "pvc-3", secrets))
+	})
+
+	t.Run("GetCapacity", func(t *testing.T) {
+		available, err := panfs.GetCapacity(t.Context(), "", secrets)
+		require.NoError(t, err)
+		assert.Equal(t, int64(15<<30), available)
+
+		available, err = panfs.GetCapacity(t.Context(), "Set 2", secrets)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5<<30), available)
+
+		_, err = panfs.GetCapacity(t.Context(), "Set 3", secrets)
+		assert.ErrorIs(t, err, ErrorNotFound)
+	})
+
+	t.Run("GetRealmFeatures", func(t *testing.T) {
+		features, err := panfs.GetRealmFeatures(t.Context(), secrets)
+		require.NoError(t, err)
+		assert.Equal(t, utils.RealmFeatures{Snapshots: false, Capacity: true}, *features)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := panfs.GetVolume(t.Context(), "missing", secrets)
+		assert.ErrorIs(t, err, ErrorNotFound)
+		assert.ErrorContains(t, err, "volume does not exist")
+
+		// the HTTP status is used for errors without code
+		_, err = panfs.CreateSnapshot(t.Context(), "pvc-1", "snap-1", secrets)
+		assert.ErrorIs(t, err, ErrorAlreadyExist)
+
+		_, err = panfs.ListSnapshots(t.Context(), "", secrets)
+		assert.True(t, IsUnsupportedCommand(err))
+
+		err = panfs.ExpandVolume(t.Context(), "pvc-1", 2<<40, secrets)
+		var limitErr *QuotaLimitError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, int64(1024<<30), limitErr.MaxBytes)
+
+		err = panfs.ModifyVolume(t.Context(), "pvc-1", VolumeModifyParams{
+			utils.VolumeParameters.GetSCKey("efsa"): "retry",
+			utils.VolumeParameters.GetSCKey("hard"): "2048.00",
+		}, secrets)
+		require.ErrorAs(t, err, &limitErr)
+		calls := realm.Calls()
+		assert.Equal(t, map[string]any{"efsa": "retry", "hard_quota_gb": 2048.0}, calls[len(calls)-1].body)
+
+		// requests without a handler are answered with a plain text 404
+		err = panfs.DeleteSnapshot(t.Context(), "pvc-1", "snap-1", secrets)
+		assert.ErrorIs(t, err, ErrorNotFound)
+	})
+}
+
+// TestPancliRESTClientAuthentication verifies the credentials sent to the realm.
+func TestPancliRESTClientAuthentication(t *testing.T) {
+	realm := newRESTRealm(t)
+	realm.mux.HandleFunc("GET /api/v1/volumes", func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "admin" || password != "secret" {
+			writeJSON(w, http.StatusUnauthorized, `{"error": {"code": "unauthenticated", "message": "invalid credentials"}}`)
+			return
+		}
+		writeJSON(w, http.StatusOK, `{"volumes": []}`)
+	})
+	panfs := realm.client(t)
+
+	tests := []struct {
+		name        string
+		credentials map[string]string
+		wantErr     error
+	}{
+		{
+			name:        "Password",
+			credentials: map[string]string{utils.RealmConnectionContext.Username: "admin", utils.RealmConnectionContext.Password: "secret"},
+		},
+		{
+			name:        "WrongPassword",
+			credentials: map[string]string{utils.RealmConnectionContext.Username: "admin", utils.RealmConnectionContext.Password: "wrong"},
+			wantErr:     ErrorUnauthenticated,
+		},
+		{
+			name:        "PrivateKeyOnly",
+			credentials: map[string]string{utils.RealmConnectionContext.Username: "admin", utils.RealmConnectionContext.PrivateKey: "key"},
+			wantErr:     ErrorUnauthenticated,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := panfs.VerifyCredentials(restSecrets(tc.credentials))
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestPancliRESTClientForbidPasswordAuth verifies that only API tokens authenticate once
+// password authentication is forbidden.
+func TestPancliRESTClientForbidPasswordAuth(t *testing.T) {
+	realm := newRESTRealm(t)
+	realm.mux.HandleFunc("GET /api/v1/volumes", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, `{"volumes": []}`)
+	})
+	panfs := realm.client(t, WithRESTForbidPasswordAuth(true))
+
+	err := panfs.VerifyCredentials(restSecrets(map[string]string{
+		utils.RealmConnectionContext.Username: "admin",
+		utils.RealmConnectionContext.Password: "secret",
+	}))
+	assert.ErrorIs(t, err, ErrorUnauthenticated)
+	assert.ErrorIs(t, err, utils.ErrPasswordAuthForbidden)
+	assert.Empty(t, realm.Calls())
+
+	assert.NoError(t, panfs.VerifyCredentials(restSecrets(map[string]string{utils.RealmConnectionContext.APIToken: "token"})))
+}
+
+// TestPancliRESTClientConnections verifies the failover between realm addresses and the
+// handling of connections lost after a request was sent.
+func TestPancliRESTClientConnections(t *testing.T) {
+	secrets := restSecrets(map[string]string{utils.RealmConnectionContext.APIToken: "token"})
+
+	t.Run("Failover", func(t *testing.T) {
+		realm := newRESTRealm(t)
+		realm.mux.HandleFunc("GET /api/v1/volumes", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, http.StatusOK, `{"volumes": []}`)
+		})
+		panfs := realm.client(t)
+
+		// nothing listens on the port of the server at the first address
+		secrets := restSecrets(map[string]string{
+			utils.RealmConnectionContext.RealmAddress: "127.0.0.2,127.0.0.1",
+			utils.RealmConnectionContext.APIToken:     "token",
+		})
+		_, err := panfs.ListVolumes(t.Context(), secrets)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"127.0.0.2"}, panfs.health.unhealthy())
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		realm := newRESTRealm(t)
+		panfs := realm.client(t)
+		realm.Close()
+
+		_, err := panfs.ListVolumes(t.Context(), secrets)
+		assert.ErrorIs(t, err, ErrorUnavailable)
+	})
+
+	t.Run("ConnectionLostAfterRequest", func(t *testing.T) {
+		realm := newRESTRealm(t)
+		deletes := 0
+		realm.mux.HandleFunc("DELETE /api/v1/volumes/{volume}", func(w http.ResponseWriter, _ *http.Request) {
+			deletes++
+			if deletes == 1 {
+				conn, _, err := http.NewResponseController(w).Hijack()
+				require.NoError(t, err)
+				_ = conn.Close()
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		// the request is resent with the same idempotency key
+		panfs := realm.client(t, WithRESTIdempotencyKeys(true))
+		require.NoError(t, panfs.DeleteVolume(t.Context(), "pvc-1", secrets))
+		calls := realm.Calls()
+		require.Len(t, calls, 2)
+		assert.NotEmpty(t, calls[0].key)
+		assert.Equal(t, calls[0].key, calls[1].key)
+
+		// without idempotency keys the outcome is reported as unknown
+		deletes = 0
+		panfs = realm.client(t)
+		err := panfs.DeleteVolume(t.Context(), "pvc-1", secrets)
+		assert.ErrorIs(t, err, ErrorOutcomeUnknown)
+		assert.ErrorIs(t, err, ErrorUnavailable)
+	})
+}
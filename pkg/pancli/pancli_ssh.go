@@ -12,28 +12,29 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package pancli provides SSH-based client implementations and utilities for interacting with PanFS storage systems.
-// It defines types and functions for volume management, SSH command execution, and parameter handling.
+//go:build !nodeonly
+
 package pancli
 
 import (
+	"context"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"net"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
 	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
 	"golang.org/x/crypto/ssh"
-	"k8s.io/klog/v2"
 )
 
-//go:generate mockgen -source=pancli_ssh.go -destination=mock/mock_runner.go -package=mock PancliRunner
-
-// VolumeCreateParams represents the parameters for creating a volume.
-type VolumeCreateParams map[string]string
-
 // getOptionalParameters constructs a list of optional parameters for the volume creation command.
+// Values are expected to be validated and converted by VolumeCreateParamsBuilder.
 //
 // Parameters:
 //
@@ -45,8 +46,6 @@ type VolumeCreateParams map[string]string
 func getOptionalParameters(params VolumeCreateParams) []string {
 	opts := []string{}
 
-	soft := utils.VolumeParameters.GetSCKey("soft")
-	hard := utils.VolumeParameters.GetSCKey("hard")
 	for key, value := range params {
 		// Skip parameters with empty values
 		if value == "" {
@@ -68,15 +67,6 @@ func getOptionalParameters(params VolumeCreateParams) []string {
 			continue
 		}
 
-		// Convert size from bytes to gigabytes for soft and hard quota parameters
-		if keyParam == soft || keyParam == hard {
-			sizeBytes, err := strconv.ParseInt(value, 10, 64)
-			if err != nil {
-				continue
-			}
-			value = fmt.Sprintf("%.2f", utils.BytesToGB(sizeBytes))
-		}
-
 		if fmtStr := utils.VolumeParameters.GetFmt(keyParam); fmtStr != "" {
 			opts = append(opts, fmt.Sprintf(fmtStr, value))
 		}
@@ -85,28 +75,61 @@ func getOptionalParameters(params VolumeCreateParams) []string {
 	return opts
 }
 
-// SSHRunner defines an interface for running commands over SSH.
+// SSHRunner defines an interface for running commands over SSH. Commands still running
+// when the context is done are aborted.
 type SSHRunner interface {
-	RunCommand(secrets map[string]string, args ...string) ([]byte, error)
+	RunCommand(ctx context.Context, secrets map[string]string, args ...string) ([]byte, error)
 }
 
 // SSHClient manages SSH connections and command execution.
 type SSHClient struct {
-	// cache for SSH connections to avoid creating a new connection for each command.
-	// key is the realm address, value is the SSH client.
-	clients map[string]*ssh.Client
+	// pool of SSH connections to avoid creating a new connection for each command.
+	// key is the realm address, value is the pooled connection.
+	clients map[string]*realmConn
+	// limits, idle expiry and keepalives of the pooled connections
+	pool SSHPoolConfig
+	// keepaliveStarted is set once the keepalive goroutine runs, done stops it
+	keepaliveStarted bool
+	done             chan struct{}
+	// reachability of the addresses of realms with several directors
+	health realmHealth
+	// dial connects to the SSH server, dialSSH unless overridden in tests
+	dial func(network, addr string, config *ssh.ClientConfig) (*ssh.Client, error)
+	// timeout bounds establishing a connection, including the SSH handshake
+	timeout time.Duration
+	// knownHostsFile holds the host keys of realms in known_hosts format
+	knownHostsFile string
+	// strictHostKeys refuses realms whose host key is not configured or does not match
+	strictHostKeys bool
+	// forbidPasswordAuth authenticates with private keys only
+	forbidPasswordAuth bool
 	sync.Mutex
 }
 
+// sshConnectTimeout is the default timeout of establishing an SSH connection.
+const sshConnectTimeout = 30 * time.Second
+
 // NewSSHClient creates a new SSHClient instance for managing SSH connections.
 //
+// Parameters:
+//
+//	opts - Optional settings, e.g. WithKnownHostsFile or WithSSHPool.
+//
 // Returns:
 //
 //	*SSHClient - The initialized SSHClient.
-func NewSSHClient() *SSHClient {
-	return &SSHClient{
-		clients: make(map[string]*ssh.Client),
+func NewSSHClient(opts ...SSHClientOption) *SSHClient {
+	s := &SSHClient{
+		clients: make(map[string]*realmConn),
+		dial:    dialSSH,
+		timeout: sshConnectTimeout,
+		pool:    DefaultSSHPoolConfig,
+		done:    make(chan struct{}),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // RunCommand executes a command over SSH using the provided secrets and arguments.
@@ -114,6 +137,7 @@ func NewSSHClient() *SSHClient {
 //
 // Parameters:
 //
+//	ctx     - The context of the request, cancelling it aborts the command.
 //	secrets - Map of authentication secrets.
 //	args    - Command-line arguments to execute.
 //
@@ -121,12 +145,52 @@ func NewSSHClient() *SSHClient {
 //
 //	[]byte - Command output.
 //	error  - Error if command fails or output indicates an error.
-func (s *SSHClient) RunCommand(secrets map[string]string, args ...string) ([]byte, error) {
+func (s *SSHClient) RunCommand(ctx context.Context, secrets map[string]string, args ...string) ([]byte, error) {
+	start := time.Now()
+	output, err := s.runCommand(ctx, secrets, args...)
+
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	metrics.RealmCommandDuration.WithLabelValues(commandName(args), result).Observe(time.Since(start).Seconds())
+	return output, err
+}
+
+// commandName returns the name of a pancli command for metrics, e.g. "volume create". The
+// arguments naming volumes and snapshots are left out to bound the number of label values.
+//
+// Parameters:
+//
+//	args - Command-line arguments.
+//
+// Returns:
+//
+//	string - The first two arguments.
+func commandName(args []string) string {
+	if len(args) > 2 {
+		args = args[:2]
+	}
+	return strings.Join(args, " ")
+}
+
+// runCommand executes a command over SSH, see RunCommand.
+func (s *SSHClient) runCommand(ctx context.Context, secrets map[string]string, args ...string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("realm command not run: %w", err)
+	}
+
 	conn, err := s.getSSHConnection(secrets)
 	if err != nil {
 		return nil, err
 	}
 
+	release, err := s.acquireSession(ctx, secrets[utils.RealmConnectionContext.RealmAddress])
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	session, err := conn.NewSession()
 	if err != nil {
 		return nil, err
@@ -134,10 +198,32 @@ func (s *SSHClient) RunCommand(secrets map[string]string, args ...string) ([]byt
 	defer func() { _ = session.Close() }()
 
 	cmd := strings.Join(args, " ")
-	output, err := session.CombinedOutput(cmd)
-	if err != nil {
+	compress := compressionRequested(secrets)
+	if compress {
+		cmd = compressedCommand(cmd)
+	}
+	output, err := runSession(ctx, session, cmd)
+	if err != nil && errors.Is(err, ctx.Err()) {
 		return nil, err
 	}
+	if compress {
+		var decompressErr error
+		if output, decompressErr = decompressOutput(output); decompressErr != nil {
+			return nil, decompressErr
+		}
+	}
+	if len(args) > 0 && args[0] == "pasxml" {
+		// a login banner of the realm must not be classified as an error
+		output = utils.TrimPasxmlNoise(output)
+	}
+	if err != nil {
+		var exitErr *ssh.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, parseExitError(exitErr.ExitStatus(), string(output))
+		}
+		// the session failed after the command was sent, the realm may have run it
+		return nil, fmt.Errorf("%w: %w: %v", ErrorOutcomeUnknown, ErrorUnavailable, err)
+	}
 
 	err = parseErrorString(string(output))
 	if err != nil {
@@ -147,7 +233,73 @@ func (s *SSHClient) RunCommand(secrets map[string]string, args ...string) ([]byt
 	return output, nil
 }
 
-// getSSHConnection establishes or retrieves a cached SSH connection using secrets.
+// runSession runs the command in the session. If the context is done first, the command is
+// killed and the session closed without waiting for the realm, so the command does not
+// outlive the request.
+//
+// Parameters:
+//
+//	ctx     - The context of the request.
+//	session - The SSH session.
+//	cmd     - The command line.
+//
+// Returns:
+//
+//	[]byte - The combined output of the command.
+//	error  - The error of the command, or an ErrorOutcomeUnknown error wrapping the context
+//	         error if the command was aborted.
+func runSession(ctx context.Context, session *ssh.Session, cmd string) ([]byte, error) {
+	type result struct {
+		output []byte
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		output, err := session.CombinedOutput(cmd)
+		done <- result{output: output, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.output, r.err
+	case <-ctx.Done():
+		// not every realm honours the signal, closing the session ends the command anyway
+		_ = session.Signal(ssh.SIGKILL)
+		_ = session.Close()
+		metrics.RealmCommandsAborted.WithLabelValues(commandName(strings.Fields(cmd))).Inc()
+		return nil, fmt.Errorf("%w: %w: realm command aborted: %w", ErrorOutcomeUnknown, ErrorUnavailable, ctx.Err())
+	}
+}
+
+// DebugState returns the state of the SSH connection pool for debugging.
+//
+// Returns:
+//
+//	map[string]any - The open realm connections and their running sessions.
+func (s *SSHClient) DebugState() map[string]any {
+	s.Lock()
+	defer s.Unlock()
+
+	realms := []string{}
+	sessions := map[string]int{}
+	for realm, rc := range s.clients {
+		if rc.client != nil {
+			realms = append(realms, realm)
+		}
+		if rc.sessions > 0 {
+			sessions[realm] = rc.sessions
+		}
+	}
+	sort.Strings(realms)
+
+	return map[string]any{
+		"ssh_connections":             realms,
+		"ssh_sessions":                sessions,
+		"unreachable_realm_addresses": s.health.unhealthy(),
+	}
+}
+
+// getSSHConnection establishes or retrieves a pooled SSH connection using secrets.
 // Returns an SSH client or error if authentication fails.
 //
 // Parameters:
@@ -159,45 +311,85 @@ func (s *SSHClient) RunCommand(secrets map[string]string, args ...string) ([]byt
 //	*ssh.Client - The SSH client connection.
 //	error       - Error if connection fails.
 func (s *SSHClient) getSSHConnection(secrets map[string]string) (*ssh.Client, error) {
-	realm, ok := secrets[utils.RealmConnectionContext.RealmAddress]
-	if !ok {
+	realmSecrets := utils.NewRealmSecrets(secrets)
+	if !realmSecrets.Has(utils.RealmConnectionContext.RealmAddress) {
 		return nil, fmt.Errorf("missing %s in secrets", utils.RealmConnectionContext.RealmAddress)
 	}
+	realm := realmSecrets.Realm
 
 	// acquire a lock to ensure thread safety when accessing the clients map
 	s.Lock()
 	defer s.Unlock()
 
-	// check if there is a connection in the cache
-	if client, exists := s.clients[realm]; exists {
+	// check if there is a connection in the pool
+	rc := s.realmConn(realm)
+	if client := rc.client; client != nil {
 		// check if connection is alive by sending a simple command
 		if _, _, err := client.SendRequest("ping", false, nil); err == nil {
 			// connection is alive and can be reused
 			return client, nil
 		}
-		_ = client.Close()
-		s.clients[realm] = nil // Remove dead connection from cache
+		_ = rc.dropConnection().Close() // Remove dead connection from the pool
 	}
 
-	// If no cached connection or the cached connection is dead, create a new one
-	user, ok := secrets[utils.RealmConnectionContext.Username]
-	if !ok {
-		return nil, fmt.Errorf("missing user in secrets")
+	// If no pooled connection or the pooled connection is dead, create a new one
+	config, err := s.clientConfig(realmSecrets)
+	if err != nil {
+		return nil, err
 	}
 
-	password, ok := secrets[utils.RealmConnectionContext.Password]
-	if !ok {
-		password = "" // Default to empty if not provided
+	client, err := s.connect(realm, config)
+	if err != nil {
+		return nil, err
 	}
+	rc.client = client // Put new connection into the pool
+	rc.config = config
+	rc.lastUsed = time.Now()
+	metrics.RealmSSHConnections.Inc()
+	s.startKeepalive()
+	return client, nil
+}
 
-	privateKey, ok := secrets[utils.RealmConnectionContext.PrivateKey]
-	if !ok {
-		privateKey = "" // Default to empty if not provided
+// WithForbidPasswordAuth authenticates with private keys only. Secrets without a private key
+// are rejected with ErrorUnauthenticated and instructions to migrate to key-based
+// authentication, and passwords of secrets with a private key are never offered.
+//
+// Parameters:
+//
+//	forbid - Whether password authentication is forbidden.
+//
+// Returns:
+//
+//	SSHClientOption - The client option.
+func WithForbidPasswordAuth(forbid bool) SSHClientOption {
+	return func(s *SSHClient) {
+		s.forbidPasswordAuth = forbid
 	}
+}
 
-	privateKeyPassphrase, ok := secrets[utils.RealmConnectionContext.PrivateKeyPassphrase]
-	if !ok {
-		privateKeyPassphrase = "" // Default to empty if not provided
+// clientConfig returns the SSH client configuration for the credentials of the secrets.
+//
+// Parameters:
+//
+//	secrets - The parsed realm secrets.
+//
+// Returns:
+//
+//	*ssh.ClientConfig - The SSH client configuration.
+//	error             - Error if the credentials or host keys are missing or invalid.
+func (s *SSHClient) clientConfig(secrets utils.RealmSecrets) (*ssh.ClientConfig, error) {
+	if !secrets.Has(utils.RealmConnectionContext.Username) {
+		return nil, fmt.Errorf("missing user in secrets")
+	}
+	user, password, privateKey := secrets.Username, secrets.Password, secrets.PrivateKey
+	if s.forbidPasswordAuth {
+		// the API token does not authenticate SSH connections
+		keyOnly := secrets
+		keyOnly.APIToken = ""
+		if err := keyOnly.ValidateKeyAuth(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrorUnauthenticated, err)
+		}
+		password = ""
 	}
 
 	if password == "" && privateKey == "" {
@@ -205,26 +397,26 @@ func (s *SSHClient) getSSHConnection(secrets map[string]string) (*ssh.Client, er
 		return nil, fmt.Errorf("no valid authentication method provided in secrets, either password or public key is required")
 	}
 
+	hostKeyCallback, err := s.hostKeyCallback(secrets)
+	if err != nil {
+		return nil, err
+	}
+
 	config := &ssh.ClientConfig{
 		User:            user,
 		Auth:            []ssh.AuthMethod{},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Timeout:         30 * time.Second, // Connection establishment timeout
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         s.timeout, // Connection establishment timeout
+	}
+	if config.Timeout == 0 {
+		config.Timeout = sshConnectTimeout
 	}
 
 	// Add private key authentication if provided
 	if privateKey != "" {
-		var signer ssh.Signer
-		var err error
-
-		if privateKeyPassphrase == "" {
-			signer, err = ssh.ParsePrivateKey([]byte(privateKey))
-		} else {
-			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(privateKey), []byte(privateKeyPassphrase))
-		}
-
+		signer, err := ParsePrivateKey(privateKey, secrets.PrivateKeyPassphrase)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse SSH private key: %v, check passphrase for the key", err)
+			return nil, err
 		}
 
 		config.Auth = append(config.Auth, ssh.PublicKeys(signer))
@@ -246,33 +438,228 @@ func (s *SSHClient) getSSHConnection(secrets map[string]string) (*ssh.Client, er
 		))
 	}
 
-	client, err := ssh.Dial("tcp", realm+":22", config)
+	return config, nil
+}
+
+// connect opens an SSH connection to one of the addresses of the realm.
+//
+// Parameters:
+//
+//	realm  - The configured realm value.
+//	config - The SSH client configuration.
+//
+// Returns:
+//
+//	*ssh.Client - The SSH client connection.
+//	error       - Error if none of the addresses can be connected to.
+func (s *SSHClient) connect(realm string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	addresses, err := utils.ParseRealmAddresses(realm)
+	if err != nil {
+		return nil, err
+	}
+
+	// try the realm addresses in turn, sticking to the last reachable one
+	var errs []error
+	for _, address := range s.health.order(realm, addresses) {
+		client, err := s.dialRealmAddress(address, config)
+		if err != nil {
+			metrics.RealmSSHDials.WithLabelValues("error").Inc()
+			s.health.failed(realm, address)
+			llog.V(4).Info("failed to connect to realm address", "realm", realm, "address", address, "error", err.Error())
+			errs = append(errs, err)
+			continue
+		}
+		metrics.RealmSSHDials.WithLabelValues("success").Inc()
+		s.health.succeeded(realm, address)
+		return client, nil
+	}
+
+	if len(errs) == 1 {
+		return nil, errs[0]
+	}
+	return nil, fmt.Errorf("failed to connect to any of the realm addresses: %w", errors.Join(errs...))
+}
+
+// dialRealmAddress opens an SSH connection to a single realm address.
+//
+// Parameters:
+//
+//	address - The realm address.
+//	config  - The SSH client configuration.
+//
+// Returns:
+//
+//	*ssh.Client - The SSH client connection.
+//	error       - Error if the connection fails.
+func (s *SSHClient) dialRealmAddress(address string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	sshAddress, err := utils.RealmSSHAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	dial := s.dial
+	if dial == nil {
+		dial = dialSSH
+	}
+	client, err := dial("tcp", sshAddress, config)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", address, err)
+	}
+	return client, nil
+}
+
+// dialSSH opens an SSH connection like ssh.Dial, but bounds the SSH handshake by the
+// connection timeout as well, so an unresponsive server does not block the caller forever.
+//
+// Parameters:
+//
+//	network - The network, e.g. "tcp".
+//	addr    - The "host:port" address of the SSH server.
+//	config  - The SSH client configuration.
+//
+// Returns:
+//
+//	*ssh.Client - The SSH client connection.
+//	error       - Error if the connection or the handshake fails or times out.
+func dialSSH(network, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	conn, err := net.DialTimeout(network, addr, config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	if config.Timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(config.Timeout))
+	}
+
+	clientConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return ssh.NewClient(clientConn, chans, reqs), nil
+}
+
+// ParsePrivateKey parses an SSH private key, decrypting it with the passphrase if the key is encrypted.
+// PEM (PKCS#1, PKCS#8, SEC1) and OpenSSH formats are supported, both plain and passphrase protected.
+// The passphrase is ignored for keys which are not encrypted.
+//
+// Parameters:
+//
+//	privateKey - The PEM encoded private key.
+//	passphrase - The passphrase of the key, empty if the key is not encrypted.
+//
+// Returns:
+//
+//	ssh.Signer - The signer for public key authentication.
+//	error      - ErrorUnauthenticated describing whether the passphrase is missing or incorrect,
+//	             or the key is corrupt.
+func ParsePrivateKey(privateKey, passphrase string) (ssh.Signer, error) {
+	signer, err := ssh.ParsePrivateKey([]byte(privateKey))
 	if err == nil {
-		s.clients[realm] = client // Put new connection into the cache
+		return signer, nil
 	}
-	return client, err
+
+	var missing *ssh.PassphraseMissingError
+	if !errors.As(err, &missing) {
+		return nil, fmt.Errorf("%w: SSH private key is corrupt or in an unsupported format: %v", ErrorUnauthenticated, err)
+	}
+
+	if passphrase == "" {
+		return nil, fmt.Errorf("%w: SSH private key is encrypted but %s is not provided in secrets",
+			ErrorUnauthenticated, utils.RealmConnectionContext.PrivateKeyPassphrase)
+	}
+
+	signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(privateKey), []byte(passphrase))
+	if errors.Is(err, x509.IncorrectPasswordError) {
+		return nil, fmt.Errorf("%w: incorrect %s for SSH private key", ErrorUnauthenticated, utils.RealmConnectionContext.PrivateKeyPassphrase)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decrypt SSH private key: %v", ErrorUnauthenticated, err)
+	}
+
+	return signer, nil
 }
 
 // PancliSSHClient implements the PancliClient interface for SSH-based communication with the PanFS realm.
 type PancliSSHClient struct {
 	pancli SSHRunner
+	// serializes mutating commands for realms requesting it via secrets
+	realmLocks realmLocks
+	// realms already reported as not supporting hard quotas
+	hardQuotaWarned sync.Map
+
+	// polling of the created volume while it is not yet visible on the realm
+	verifyAttempts int
+	verifyInterval time.Duration
+	// tag created volumes with an idempotency token in their description
+	idempotencyTokens bool
 }
 
-var llog klog.Logger = klog.NewKlogr()
+// Default polling of a created volume which is not yet visible on the realm.
+const (
+	DefaultCreateVerifyAttempts = 5
+	DefaultCreateVerifyInterval = 500 * time.Millisecond
+)
+
+// PancliSSHClientOption configures optional PancliSSHClient behavior in NewPancliSSHClient.
+type PancliSSHClientOption func(*PancliSSHClient)
+
+// WithCreateVerifyRetry sets how often the details of a created volume are read before
+// giving up, for realms which report a new volume as not found for a short time.
+//
+// Parameters:
+//
+//	attempts - The maximum number of reads, 1 disables retries.
+//	interval - The delay between reads.
+//
+// Returns:
+//
+//	PancliSSHClientOption - The client option.
+func WithCreateVerifyRetry(attempts int, interval time.Duration) PancliSSHClientOption {
+	return func(p *PancliSSHClient) {
+		p.verifyAttempts = attempts
+		p.verifyInterval = interval
+	}
+}
 
 // NewPancliSSHClient creates a new instance of PancliSSHClient with the provided SSHRunner.
 //
 // Parameters:
 //
 //	runner - The SSHRunner implementation.
+//	opts   - Optional client settings.
 //
 // Returns:
 //
 //	*PancliSSHClient - The initialized PancliSSHClient.
-func NewPancliSSHClient(runner SSHRunner) *PancliSSHClient {
-	return &PancliSSHClient{
-		pancli: runner,
+func NewPancliSSHClient(runner SSHRunner, opts ...PancliSSHClientOption) *PancliSSHClient {
+	p := &PancliSSHClient{
+		pancli:         runner,
+		verifyAttempts: DefaultCreateVerifyAttempts,
+		verifyInterval: DefaultCreateVerifyInterval,
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// DebugState returns the internal state of the client for debugging, including
+// the state of the underlying SSHRunner if it exposes one.
+//
+// Returns:
+//
+//	map[string]any - The client state.
+func (p *PancliSSHClient) DebugState() map[string]any {
+	state := map[string]any{
+		"serialized_realms": p.realmLocks.realms(),
+	}
+	if r, ok := p.pancli.(interface{ DebugState() map[string]any }); ok {
+		for k, v := range r.DebugState() {
+			state[k] = v
+		}
+	}
+	return state
 }
 
 // CreateVolume creates a volume using the provided arguments and returns the created volume object.
@@ -280,6 +667,7 @@ func NewPancliSSHClient(runner SSHRunner) *PancliSSHClient {
 //
 // Parameters:
 //
+//	ctx        - The context of the request.
 //	volumeName - The name of the volume to create.
 //	params     - The volume creation parameters.
 //	secrets    - Map of authentication secrets.
@@ -287,42 +675,174 @@ func NewPancliSSHClient(runner SSHRunner) *PancliSSHClient {
 // Returns:
 //
 //	*utils.Volume - The created volume object.
-//	error         - Error if creation or retrieval fails.
-func (p *PancliSSHClient) CreateVolume(volumeName string, params VolumeCreateParams, secrets map[string]string) (*utils.Volume, error) {
-	cmd := []string{"volume", "create", volumeName}
+//	error         - Error if creation or retrieval fails, a *QuotaLimitError if the quotas are
+//	                outside the limits of the realm.
+func (p *PancliSSHClient) CreateVolume(ctx context.Context, volumeName string, params VolumeCreateParams, secrets map[string]string) (*utils.Volume, error) {
+	return p.createVolume(ctx, volumeName, params, nil, secrets)
+}
 
-	optionalParams := getOptionalParameters(params)
-	if len(optionalParams) != 0 {
-		cmd = append(cmd, optionalParams...)
+// createVolume creates a volume, optionally from a source, and returns the created volume object.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume to create.
+//	params     - The volume creation parameters.
+//	source     - The source arguments of the volume creation command, nil for an empty volume.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.Volume - The created volume object.
+//	error         - Error if creation or retrieval fails.
+func (p *PancliSSHClient) createVolume(ctx context.Context, volumeName string, params VolumeCreateParams, source []string, secrets map[string]string) (*utils.Volume, error) {
+	// the token tells a volume created by this request from an existing one if the outcome
+	// of the create command is unknown
+	var token string
+	if p.idempotencyTokens {
+		token = newIdempotencyToken()
+		params = params.withIdempotencyToken(token)
 	}
 
-	llog.V(5).Info("CreateVolume executes:", "command", strings.Join(cmd, " "))
-	if _, err := p.pancli.RunCommand(secrets, cmd...); err != nil {
+	err := p.runCreateVolume(ctx, volumeName, params, token, source, secrets)
+
+	// some realm versions do not support setting the hard quota, create a soft-quota-only volume if tolerated
+	degraded := false
+	if err != nil && params.TolerateMissingHardQuota() && isHardQuotaUnsupported(err) {
+		realm := secrets[utils.RealmConnectionContext.RealmAddress]
+		if _, warned := p.hardQuotaWarned.LoadOrStore(realm, struct{}{}); !warned {
+			requestLogger(ctx).Info("WARNING: realm does not support hard quota, creating volumes with soft quota only", "realm", realm, "error", err.Error())
+		}
+		degraded = params.HardGB() > 0
+		err = p.runCreateVolume(ctx, volumeName, params.withoutHardQuota(), token, source, secrets)
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	volume, err := p.GetVolume(volumeName, secrets)
+	volume, err := p.getCreatedVolume(ctx, volumeName, secrets)
 	if err != nil {
 		return nil, err
 	}
+	volume.HardQuotaDegraded = degraded
 
 	return volume, nil
 }
 
+// getCreatedVolume reads the details of a just created volume. Some realms report a new
+// volume as not found or still creating for a short time, so these reads are retried.
+// A volume which is still creating after all attempts is returned as is.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the created volume.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.Volume - The volume object.
+//	error         - Error if the volume cannot be read within the configured attempts.
+func (p *PancliSSHClient) getCreatedVolume(ctx context.Context, volumeName string, secrets map[string]string) (*utils.Volume, error) {
+	pending := func(volume *utils.Volume, err error) bool {
+		if err != nil {
+			return errors.Is(err, ErrorNotFound)
+		}
+		return volume.State == utils.VolumeStateCreating
+	}
+
+	volume, err := p.GetVolume(ctx, volumeName, secrets)
+	if !pending(volume, err) || p.verifyAttempts <= 1 {
+		return volume, err
+	}
+
+	for attempt := 1; attempt < p.verifyAttempts && pending(volume, err); attempt++ {
+		requestLogger(ctx).V(4).Info("created volume not available yet, retrying", "volume_name", volumeName, "attempt", attempt, "error", err)
+		if err := sleepContext(ctx, p.verifyInterval); err != nil {
+			return nil, err
+		}
+		volume, err = p.GetVolume(ctx, volumeName, secrets)
+	}
+
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	metrics.CreateVolumeVerifyRetries.WithLabelValues(result).Inc()
+
+	return volume, err
+}
+
+// runCreateVolume runs the volume creation command.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume to create.
+//	params     - The volume creation parameters, with the token in the description.
+//	token      - The idempotency token of the request, empty if volumes are not tagged.
+//	source     - The source arguments appended to the command, nil for an empty volume.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	error - Error if the command fails, a *QuotaLimitError if the quotas are outside the limits of the realm.
+func (p *PancliSSHClient) runCreateVolume(ctx context.Context, volumeName string, params VolumeCreateParams, token string, source []string, secrets map[string]string) error {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return err
+	}
+
+	cmd := []string{"volume", "create", volumeName}
+
+	optionalParams := getOptionalParameters(params.inQuotaUnit(unit))
+	if len(optionalParams) != 0 {
+		cmd = append(cmd, optionalParams...)
+	}
+	cmd = append(cmd, source...)
+
+	requestLogger(ctx).V(5).Info("CreateVolume executes:", "command", strings.Join(cmd, " "))
+	// only the create command is serialized, reading volume details stays concurrent
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = p.runMutation(ctx, secrets, mutation{
+		operation: "CreateVolume",
+		cmd:       cmd,
+		applied:   p.volumeCreated(ctx, volumeName, token, secrets),
+	})
+	return quotaLimitError(err, unit)
+}
+
 // DeleteVolume deletes a volume by its ID and returns an error if the operation fails.
 //
 // Parameters:
 //
+//	ctx        - The context of the request.
 //	volumeName - The name of the volume to delete.
 //	secrets    - Map of authentication secrets.
 //
 // Returns:
 //
 //	error - Error if deletion fails.
-func (p *PancliSSHClient) DeleteVolume(volumeName string, secrets map[string]string) error {
-	llog.V(5).Info("DeleteVolume executes:", "command", strings.Join([]string{"volume", "delete", "-f", volumeName}, " "))
-	_, err := p.pancli.RunCommand(secrets, "volume", "delete", "-f", volumeName)
-	return err
+func (p *PancliSSHClient) DeleteVolume(ctx context.Context, volumeName string, secrets map[string]string) error {
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cmd := []string{"volume", "delete", "-f", volumeName}
+
+	requestLogger(ctx).V(5).Info("DeleteVolume executes:", "command", strings.Join(cmd, " "))
+	return p.runMutation(ctx, secrets, mutation{
+		operation: "DeleteVolume",
+		cmd:       cmd,
+		applied:   p.volumeDeleted(ctx, volumeName, secrets),
+	})
 }
 
 // ExpandVolume expands the size of a volume to the specified size in bytes.
@@ -330,22 +850,40 @@ func (p *PancliSSHClient) DeleteVolume(volumeName string, secrets map[string]str
 //
 // Parameters:
 //
+//	ctx        - The context of the request.
 //	volumeName - The name of the volume to expand.
 //	sizeBytes  - The target size in bytes.
 //	secrets    - Map of authentication secrets.
 //
 // Returns:
 //
-//	error - Error if expansion fails.
-func (p *PancliSSHClient) ExpandVolume(volumeName string, sizeBytes int64, secrets map[string]string) error {
-	// convert size from bytes to gigabytes
-	sizeGBStr := strconv.FormatFloat(utils.BytesToGB(sizeBytes), 'f', 2, 64)
+//	error - Error if expansion fails, a *QuotaLimitError if the size exceeds the limits of the realm.
+func (p *PancliSSHClient) ExpandVolume(ctx context.Context, volumeName string, sizeBytes int64, secrets map[string]string) error {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return err
+	}
+	// convert size from bytes to the quota unit of the realm
+	sizeGBStr := strconv.FormatFloat(unit.FromBytes(sizeBytes), 'f', 2, 64)
 
-	llog.V(5).Info("ExpandVolume executes:", "command", strings.Join([]string{"volume", "set", "soft-quota", volumeName, sizeGBStr}, " "))
-	_, err := p.pancli.RunCommand(secrets, "volume", "set", "soft-quota", volumeName, sizeGBStr)
+	unlock, err := p.realmLocks.lock(ctx, secrets)
 	if err != nil {
 		return err
 	}
+	defer unlock()
+
+	cmd := []string{"volume", "set", "soft-quota", volumeName, sizeGBStr}
+
+	requestLogger(ctx).V(5).Info("ExpandVolume executes:", "command", strings.Join(cmd, " "))
+	sizeGB, _ := strconv.ParseFloat(sizeGBStr, 64)
+	err = p.runMutation(ctx, secrets, mutation{
+		operation: "ExpandVolume",
+		cmd:       cmd,
+		applied:   p.volumeExpanded(ctx, volumeName, sizeGB, secrets),
+	})
+	if err != nil {
+		return quotaLimitError(err, unit)
+	}
 
 	return nil
 }
@@ -355,15 +893,21 @@ func (p *PancliSSHClient) ExpandVolume(volumeName string, sizeBytes int64, secre
 //
 // Parameters:
 //
+//	ctx     - The context of the request.
 //	secrets - Map of authentication secrets.
 //
 // Returns:
 //
 //	*utils.VolumeList - The parsed volume list.
 //	error             - Error if retrieval or parsing fails.
-func (p *PancliSSHClient) ListVolumes(secrets map[string]string) (*utils.VolumeList, error) {
-	llog.V(5).Info("ListVolumes executes:", "command", strings.Join([]string{"pasxml", "volumes"}, " "))
-	out, err := p.pancli.RunCommand(secrets, "pasxml", "volumes")
+func (p *PancliSSHClient) ListVolumes(ctx context.Context, secrets map[string]string) (*utils.VolumeList, error) {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	requestLogger(ctx).V(5).Info("ListVolumes executes:", "command", strings.Join([]string{"pasxml", "volumes"}, " "))
+	out, err := p.pancli.RunCommand(ctx, secrets, "pasxml", "volumes")
 	if err != nil {
 		return nil, err
 	}
@@ -377,6 +921,10 @@ func (p *PancliSSHClient) ListVolumes(secrets map[string]string) (*utils.VolumeL
 		return nil, ErrorInvalidArgument
 	}
 
+	for i := range vols.Volumes {
+		vols.Volumes[i].QuotaUnit = unit
+	}
+
 	return vols, nil
 }
 
@@ -385,6 +933,7 @@ func (p *PancliSSHClient) ListVolumes(secrets map[string]string) (*utils.VolumeL
 //
 // Parameters:
 //
+//	ctx        - The context of the request.
 //	volumeName - The name of the volume to retrieve.
 //	secrets    - Map of authentication secrets.
 //
@@ -392,9 +941,14 @@ func (p *PancliSSHClient) ListVolumes(secrets map[string]string) (*utils.VolumeL
 //
 //	*utils.Volume - The parsed volume object.
 //	error         - Error if retrieval or parsing fails.
-func (p *PancliSSHClient) GetVolume(volumeName string, secrets map[string]string) (*utils.Volume, error) {
-	llog.V(5).Info("GetVolume executes:", "command", strings.Join([]string{"pasxml", "volumes", "volume", volumeName}, " "))
-	out, err := p.pancli.RunCommand(secrets, "pasxml", "volumes", "volume", volumeName)
+func (p *PancliSSHClient) GetVolume(ctx context.Context, volumeName string, secrets map[string]string) (*utils.Volume, error) {
+	unit, err := realmQuotaUnit(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	requestLogger(ctx).V(5).Info("GetVolume executes:", "command", strings.Join([]string{"pasxml", "volumes", "volume", volumeName}, " "))
+	out, err := p.pancli.RunCommand(ctx, secrets, "pasxml", "volumes", "volume", volumeName)
 	if err != nil {
 		return nil, err
 	}
@@ -412,5 +966,6 @@ func (p *PancliSSHClient) GetVolume(volumeName string, secrets map[string]string
 		return nil, ErrorNotFound
 	}
 
+	vols.Volumes[0].QuotaUnit = unit
 	return &vols.Volumes[0], nil
 }
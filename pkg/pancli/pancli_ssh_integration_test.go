@@ -0,0 +1,299 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli/sshtest"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// newRealmServer starts an in-process realm SSH server answering the commands of
+// testdata/realm_ssh.json.
+func newRealmServer(t *testing.T, opts ...sshtest.Option) *sshtest.Server {
+	fixtures, err := sshtest.LoadFixtures("testdata/realm_ssh.json")
+	require.NoError(t, err)
+
+	server := sshtest.NewServer(t, opts...)
+	server.HandleFixtures(fixtures)
+	return server
+}
+
+// newServerSSHClient returns an SSHClient connecting to the server for any realm address.
+func newServerSSHClient(server *sshtest.Server) *SSHClient {
+	client := NewSSHClient()
+	client.dial = func(network, _ string, config *ssh.ClientConfig) (*ssh.Client, error) {
+		return dialSSH(network, server.Addr(), config)
+	}
+	return client
+}
+
+// realmSecrets returns the secrets of the test realm with the given credentials.
+func realmSecrets(credentials map[string]string) map[string]string {
+	secrets := map[string]string{
+		utils.RealmConnectionContext.RealmAddress: "realm.example.com",
+		utils.RealmConnectionContext.Username:     "admin",
+	}
+	for k, v := range credentials {
+		secrets[k] = v
+	}
+	return secrets
+}
+
+// TestPancliSSHClientIntegration runs the volume operations over SSH against the realm
+// fixtures, covering command quoting and the parsing of realm output and exit statuses.
+func TestPancliSSHClientIntegration(t *testing.T) {
+	server := newRealmServer(t, sshtest.WithPassword("admin", "secret"))
+	panfs := NewPancliSSHClient(newServerSSHClient(server))
+	secrets := realmSecrets(map[string]string{utils.RealmConnectionContext.Password: "secret"})
+
+	t.Run("CreateVolume", func(t *testing.T) {
+		params, err := NewVolumeCreateParamsBuilder().SetSoftBytes(1 << 30).SetHardBytes(2 << 30).SetBladeset("Set 1").Build()
+		require.NoError(t, err)
+
+		vol, err := panfs.CreateVolume(t.Context(), "pvc-1", params, secrets)
+		require.NoError(t, err)
+		assert.Equal(t, "372", vol.ID)
+		assert.Equal(t, utils.VolumeStateOnline, vol.State)
+		assert.Equal(t, int64(1<<30), vol.GetSoftQuotaBytes())
+
+		commands := server.Commands()
+		require.NotEmpty(t, commands)
+		assert.Contains(t, commands[len(commands)-2], `bladeset "Set 1"`)
+		assert.Equal(t, "pasxml volumes volume pvc-1", commands[len(commands)-1])
+	})
+
+	t.Run("ListVolumes", func(t *testing.T) {
+		vols, err := panfs.ListVolumes(t.Context(), secrets)
+		require.NoError(t, err)
+		require.Len(t, vols.Volumes, 2)
+		assert.Equal(t, utils.VolumeName("pvc-2"), vols.Volumes[1].Name)
+	})
+
+	t.Run("GetDirectory", func(t *testing.T) {
+		dir, err := panfs.GetDirectory(t.Context(), "pvc-1", "dir-1", secrets)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1<<30), dir.GetHardQuotaBytes())
+
+		_, err = panfs.GetDirectory(t.Context(), "pvc-1", "dir-2", secrets)
+		assert.ErrorIs(t, err, ErrorNotFound)
+	})
+
+	t.Run("GetVolumeStats", func(t *testing.T) {
+		stats, err := panfs.GetVolumeStats(t.Context(), secrets)
+		require.NoError(t, err)
+		require.Len(t, stats.Volumes, 1)
+		assert.Equal(t, utils.VolumeName("pvc-1"), stats.Volumes[0].Name)
+		assert.Equal(t, 2048.0, stats.Volumes[0].WriteBytes)
+		assert.Equal(t, 250.0, stats.Volumes[0].WriteLatencyUs)
+	})
+
+	t.Run("ExpandAndDeleteVolume", func(t *testing.T) {
+		assert.NoError(t, panfs.ExpandVolume(t.Context(), "pvc-1", 2<<30, secrets))
+		assert.NoError(t, panfs.DeleteVolume(t.Context(), "pvc-1", secrets))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := panfs.GetVolume(t.Context(), "missing", secrets)
+		assert.ErrorIs(t, err, ErrorNotFound)
+
+		// errors reported with a successful exit status are parsed from the output
+		assert.ErrorIs(t, panfs.DeleteVolume(t.Context(), "missing", secrets), ErrorNotFound)
+
+		_, err = panfs.CreateVolume(t.Context(), "existing", VolumeCreateParams{}, secrets)
+		assert.ErrorIs(t, err, ErrorAlreadyExist)
+
+		assert.ErrorIs(t, panfs.DeleteVolume(t.Context(), "unreachable", secrets), ErrorUnavailable)
+		assert.ErrorIs(t, panfs.DeleteVolume(t.Context(), "silent", secrets), ErrorInternal)
+	})
+
+	t.Run("ConnectionLostAfterCommand", func(t *testing.T) {
+		// the deletion is confirmed by reading the volume instead of running the command again
+		assert.NoError(t, panfs.DeleteVolume(t.Context(), "lost", secrets))
+		commands := server.Commands()
+		assert.Equal(t, []string{"volume delete -f lost", "pasxml volumes volume lost"}, commands[len(commands)-2:])
+	})
+
+	// all commands share the cached connection
+	assert.Equal(t, 1, server.Connections())
+}
+
+// TestPancliSSHClientBanner verifies that realms printing a login banner before the pasxml
+// output are parsed, even if the banner reads like an error message.
+func TestPancliSSHClientBanner(t *testing.T) {
+	server := sshtest.NewServer(t, sshtest.WithPassword("admin", "secret"))
+	server.Handle("pasxml volumes volume pvc-1", sshtest.Response{
+		Output: "Unauthorized access is not found acceptable <monitored>\n" +
+			`<pasxml version="6.0.0"><volumes><volume id="372"><name>/pvc-1</name><state>Online</state></volume></volumes></pasxml>` + "\n",
+	})
+	server.Handle("pasxml volumes volume pvc-2", sshtest.Response{Output: "Welcome <admin>\n<pasxml version=\"6.0.0\"><volumes>"})
+	panfs := NewPancliSSHClient(newServerSSHClient(server))
+	secrets := realmSecrets(map[string]string{utils.RealmConnectionContext.Password: "secret"})
+
+	vol, err := panfs.GetVolume(t.Context(), "pvc-1", secrets)
+	require.NoError(t, err)
+	assert.Equal(t, "372", vol.ID)
+
+	_, err = panfs.GetVolume(t.Context(), "pvc-2", secrets)
+	assert.ErrorContains(t, err, `unexpected realm output before the pasxml document: "Welcome <admin>"`)
+}
+
+// TestSSHClientAuthentication verifies the authentication methods of the SSH client
+// against servers accepting a single method each.
+func TestSSHClientAuthentication(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte("passphrase"))
+	require.NoError(t, err)
+	privateKey := string(pem.EncodeToMemory(block))
+
+	tests := []struct {
+		name        string
+		option      sshtest.Option
+		credentials map[string]string
+		wantErr     error
+	}{
+		{
+			name:        "Password",
+			option:      sshtest.WithPassword("admin", "secret"),
+			credentials: map[string]string{utils.RealmConnectionContext.Password: "secret"},
+		},
+		{
+			name:        "KeyboardInteractive",
+			option:      sshtest.WithKeyboardInteractive("admin", "secret"),
+			credentials: map[string]string{utils.RealmConnectionContext.Password: "secret"},
+		},
+		{
+			name:   "PublicKey",
+			option: sshtest.WithPublicKey("admin", signer.PublicKey()),
+			credentials: map[string]string{
+				utils.RealmConnectionContext.PrivateKey:           privateKey,
+				utils.RealmConnectionContext.PrivateKeyPassphrase: "passphrase",
+			},
+		},
+		{
+			name:        "WrongPassword",
+			option:      sshtest.WithPassword("admin", "secret"),
+			credentials: map[string]string{utils.RealmConnectionContext.Password: "wrong"},
+			wantErr:     ErrorUnauthenticated,
+		},
+		{
+			name:        "PasswordForPublicKeyServer",
+			option:      sshtest.WithPublicKey("admin", signer.PublicKey()),
+			credentials: map[string]string{utils.RealmConnectionContext.Password: "secret"},
+			wantErr:     ErrorUnauthenticated,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newRealmServer(t, tc.option)
+			client := newServerSSHClient(server)
+
+			err := client.VerifyCredentials(realmSecrets(tc.credentials))
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			_, err = NewPancliSSHClient(client).GetVolume(t.Context(), "pvc-1", realmSecrets(tc.credentials))
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestSSHClientForbidPasswordAuth verifies that passwords are neither accepted nor offered
+// once password authentication is forbidden.
+func TestSSHClientForbidPasswordAuth(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(key, "")
+	require.NoError(t, err)
+	privateKey := string(pem.EncodeToMemory(block))
+
+	server := newRealmServer(t, sshtest.WithPassword("admin", "secret"))
+	client := newServerSSHClient(server)
+	WithForbidPasswordAuth(true)(client)
+
+	err = client.VerifyCredentials(realmSecrets(map[string]string{utils.RealmConnectionContext.Password: "secret"}))
+	assert.ErrorIs(t, err, ErrorUnauthenticated)
+	assert.ErrorIs(t, err, utils.ErrPasswordAuthForbidden)
+	assert.Equal(t, 0, server.Connections())
+
+	// the password accepted by the server is not offered next to the private key
+	err = client.VerifyCredentials(realmSecrets(map[string]string{
+		utils.RealmConnectionContext.Password:   "secret",
+		utils.RealmConnectionContext.PrivateKey: privateKey,
+	}))
+	assert.ErrorIs(t, err, ErrorUnauthenticated)
+	assert.NotErrorIs(t, err, utils.ErrPasswordAuthForbidden)
+}
+
+// TestSSHClientTimeouts verifies that an unresponsive realm fails the connection once the
+// timeout expires, while slow commands on an established connection still complete unless
+// the request ends first.
+func TestSSHClientTimeouts(t *testing.T) {
+	secrets := realmSecrets(map[string]string{utils.RealmConnectionContext.Password: "secret"})
+
+	t.Run("Handshake", func(t *testing.T) {
+		server := newRealmServer(t, sshtest.WithPassword("admin", "secret"), sshtest.WithHandshakeDelay(time.Minute))
+		client := newServerSSHClient(server)
+		client.timeout = 100 * time.Millisecond
+
+		start := time.Now()
+		_, err := client.RunCommand(t.Context(), secrets, "pasxml", "volumes")
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), 10*time.Second)
+		assert.Empty(t, server.Commands())
+	})
+
+	t.Run("SlowCommand", func(t *testing.T) {
+		server := sshtest.NewServer(t, sshtest.WithPassword("admin", "secret"))
+		server.Handle("pasxml volumes", sshtest.Response{Output: "<pasxml><volumes></volumes></pasxml>", Delay: 300 * time.Millisecond})
+		client := newServerSSHClient(server)
+		client.timeout = 100 * time.Millisecond
+
+		out, err := client.RunCommand(t.Context(), secrets, "pasxml", "volumes")
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "<volumes>")
+	})
+	t.Run("RequestDeadline", func(t *testing.T) {
+		server := sshtest.NewServer(t, sshtest.WithPassword("admin", "secret"))
+		server.Handle("pasxml volumes", sshtest.Response{Output: "<pasxml><volumes></volumes></pasxml>", Delay: time.Minute})
+		client := newServerSSHClient(server)
+		ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := client.RunCommand(ctx, secrets, "pasxml", "volumes")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, err, ErrorOutcomeUnknown)
+		assert.Less(t, time.Since(start), 10*time.Second)
+	})
+}
@@ -12,17 +12,27 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+//go:build !nodeonly
+
 package pancli
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
 	"encoding/xml"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli/fake"
 	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli/mock"
 	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/ssh"
 )
 
 const (
@@ -51,6 +61,7 @@ var (
 			Name:    "Set 1",
 		},
 		Encryption: "none",
+		QuotaUnit:  utils.QuotaUnitGiB,
 	}
 )
 
@@ -76,7 +87,7 @@ func TestCreateVolume(t *testing.T) {
 			validVolumeResponse,
 			func() {
 				// expect create volume command
-				runnerMock.EXPECT().RunCommand(
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
 					gomock.Any(),
 					"volume", "create", validVolumeName, `bladeset "Set 1"`,
 				).Times(1).Return([]byte{}, nil)
@@ -85,7 +96,7 @@ func TestCreateVolume(t *testing.T) {
 				genPasXML, _ := validVolumeResponse.MarshalVolumeToPasXML()
 
 				// then get volume details
-				runnerMock.EXPECT().RunCommand(
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
 					gomock.Any(),
 					"pasxml", "volumes", "volume", validVolumeName,
 				).Times(1).Return(genPasXML, nil)
@@ -99,12 +110,12 @@ func TestCreateVolume(t *testing.T) {
 			nil,
 			func() {
 				// expect create volume command to fail
-				runnerMock.EXPECT().RunCommand(
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
 					gomock.Any(),
 					"volume", "create", validVolumeName,
 				).Times(1).Return(nil, fmt.Errorf("create failed"))
 				// no need to call get volume details
-				runnerMock.EXPECT().RunCommand(
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
 					gomock.Any(),
 					gomock.Any(),
 				).Times(0)
@@ -118,12 +129,12 @@ func TestCreateVolume(t *testing.T) {
 			nil,
 			func() {
 				// expect create volume command to fail with already exists error
-				runnerMock.EXPECT().RunCommand(
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
 					gomock.Any(),
 					"volume", "create", validVolumeName,
 				).Times(1).Return(nil, fmt.Errorf("%w: %s", ErrorAlreadyExist, validVolumeName))
 				// no need to call get volume details
-				runnerMock.EXPECT().RunCommand(
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
 					gomock.Any(),
 					gomock.Any(),
 				).Times(0)
@@ -139,13 +150,13 @@ func TestCreateVolume(t *testing.T) {
 			nil,
 			func() {
 				// expect create volume command
-				runnerMock.EXPECT().RunCommand(
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
 					gomock.Any(),
 					"volume", "create", validVolumeName, `bladeset "Set 1"`,
 				).Times(1).Return([]byte{}, nil)
 
 				// then get volume details
-				runnerMock.EXPECT().RunCommand(
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
 					gomock.Any(),
 					"pasxml", "volumes", "volume", validVolumeName,
 				).Times(1).Return([]byte("<invalid xml>"), fmt.Errorf("xml syntax error"))
@@ -167,10 +178,11 @@ func TestCreateVolume(t *testing.T) {
 					XMLName: xml.Name{Local: "bladesetName"},
 				},
 				Encryption: "aes-xts-256",
+				QuotaUnit:  utils.QuotaUnitGiB,
 			},
 			func() {
 				// expect create volume command
-				runnerMock.EXPECT().RunCommand(
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
 					gomock.Any(),
 					"volume", "create", validVolumeName, "encryption on",
 				).Times(1).Return([]byte{}, nil)
@@ -183,7 +195,7 @@ func TestCreateVolume(t *testing.T) {
 				}).MarshalVolumeToPasXML()
 
 				// then get volume details
-				runnerMock.EXPECT().RunCommand(
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
 					gomock.Any(),
 					"pasxml", "volumes", "volume", validVolumeName,
 				).Times(1).Return(genPasXML, nil)
@@ -199,17 +211,71 @@ func TestCreateVolume(t *testing.T) {
 			nil,
 			func() {
 				// expect create volume command
-				runnerMock.EXPECT().RunCommand(
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
 					gomock.Any(),
 					"volume", "create", validVolumeName, "encryption on",
 				).Times(1).Return([]byte{}, nil)
 				// then get volume details
-				runnerMock.EXPECT().RunCommand(
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
 					gomock.Any(),
 					"pasxml", "volumes", "volume", validVolumeName,
 				).Times(1).Return([]byte("<invalid xml>"), fmt.Errorf("xml syntax error"))
 			},
 		},
+		{
+			"HardQuotaUnsupportedTolerated",
+			validVolumeName,
+			VolumeCreateParams{
+				utils.VolumeParameters.GetSCKey("soft"):                     "1.00",
+				utils.VolumeParameters.GetSCKey("hard"):                     "2.00",
+				utils.VolumeParameters.GetSCKey("tolerateMissingHardQuota"): "true",
+			},
+			nil,
+			&utils.Volume{
+				XMLName:           xml.Name{Local: "volume"},
+				Name:              validVolumeName,
+				ID:                "372",
+				State:             utils.VolumeStateOnline,
+				Soft:              1,
+				Bset:              utils.Bladeset{XMLName: xml.Name{Local: "bladesetName"}},
+				HardQuotaDegraded: true,
+				QuotaUnit:         utils.QuotaUnitGiB,
+			},
+			func() {
+				// expect create volume command with hard quota to fail
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
+					gomock.Any(),
+					"volume", "create", validVolumeName, gomock.Any(), gomock.Any(),
+				).Times(1).Return(nil, fmt.Errorf("%w: hard quota is not supported", ErrorInvalidArgument))
+
+				// then retry without hard quota
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
+					gomock.Any(),
+					"volume", "create", validVolumeName, "soft 1.00",
+				).Times(1).Return([]byte{}, nil)
+
+				genPasXML, _ := (&utils.Volume{ID: "372", Name: validVolumeName, State: utils.VolumeStateOnline, Soft: 1}).MarshalVolumeToPasXML()
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
+					gomock.Any(),
+					"pasxml", "volumes", "volume", validVolumeName,
+				).Times(1).Return(genPasXML, nil)
+			},
+		},
+		{
+			"HardQuotaUnsupportedNotTolerated",
+			validVolumeName,
+			VolumeCreateParams{
+				utils.VolumeParameters.GetSCKey("hard"): "2.00",
+			},
+			fmt.Errorf("%w: hard quota is not supported", ErrorInvalidArgument),
+			nil,
+			func() {
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
+					gomock.Any(),
+					"volume", "create", validVolumeName, "hard 2.00",
+				).Times(1).Return(nil, fmt.Errorf("%w: hard quota is not supported", ErrorInvalidArgument))
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -218,9 +284,9 @@ func TestCreateVolume(t *testing.T) {
 				tc.mockFunc()
 			}
 			panfs := PancliSSHClient{
-				runnerMock,
+				pancli: runnerMock,
 			}
-			vol, err := panfs.CreateVolume(tc.volName, tc.params, defaultSecrets)
+			vol, err := panfs.CreateVolume(t.Context(), tc.volName, tc.params, defaultSecrets)
 			if tc.expectedErr != nil {
 				assert.EqualError(t, err, tc.expectedErr.Error(), "unexpected error for test case: %s", tc.name)
 			} else {
@@ -247,7 +313,7 @@ func TestDeleteVolume(t *testing.T) {
 			nil,
 			func() {
 				// expect delete volume command
-				runnerMock.EXPECT().RunCommand(
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
 					gomock.Any(),
 					"volume", "delete", "-f", validVolumeName,
 				).Times(1).Return([]byte{}, nil)
@@ -259,7 +325,7 @@ func TestDeleteVolume(t *testing.T) {
 			fmt.Errorf("delete failed"),
 			func() {
 				// expect delete volume command to fail
-				runnerMock.EXPECT().RunCommand(
+				runnerMock.EXPECT().RunCommand(gomock.Any(),
 					gomock.Any(),
 					"volume", "delete", "-f", validVolumeName,
 				).Times(1).Return(nil, fmt.Errorf("delete failed"))
@@ -272,9 +338,9 @@ func TestDeleteVolume(t *testing.T) {
 				tc.mockFunc()
 			}
 			panfs := PancliSSHClient{
-				runnerMock,
+				pancli: runnerMock,
 			}
-			err := panfs.DeleteVolume(tc.volName, defaultSecrets)
+			err := panfs.DeleteVolume(t.Context(), tc.volName, defaultSecrets)
 			if tc.expectedErr != nil {
 				assert.EqualError(t, err, tc.expectedErr.Error(), "unexpected error for test case: %s", tc.name)
 			} else {
@@ -313,8 +379,8 @@ func TestGetOptionalParameters(t *testing.T) {
 		{
 			name: "SoftAndHard",
 			params: VolumeCreateParams{
-				utils.VolumeParameters.GetSCKey("soft"): "1073741824", // 1GB
-				utils.VolumeParameters.GetSCKey("hard"): "2147483648", // 2GB
+				utils.VolumeParameters.GetSCKey("soft"): "1.00",
+				utils.VolumeParameters.GetSCKey("hard"): "2.00",
 			},
 			want: []string{"soft 1.00", "hard 2.00"},
 		},
@@ -361,8 +427,8 @@ func TestGetOptionalParameters(t *testing.T) {
 				utils.VolumeParameters.GetSCKey("bladeset"):    "Set 2",
 				utils.VolumeParameters.GetSCKey("recovery"):    "99",
 				utils.VolumeParameters.GetSCKey("efsa"):        "file-unavailable",
-				utils.VolumeParameters.GetSCKey("soft"):        "3221225472", // 3GB
-				utils.VolumeParameters.GetSCKey("hard"):        "4294967296", // 4GB
+				utils.VolumeParameters.GetSCKey("soft"):        "3.00",
+				utils.VolumeParameters.GetSCKey("hard"):        "4.00",
 				utils.VolumeParameters.GetSCKey("volservice"):  "0x02",
 				utils.VolumeParameters.GetSCKey("layout"):      "RAID5",
 				utils.VolumeParameters.GetSCKey("maxwidth"):    "12",
@@ -407,3 +473,219 @@ func TestGetOptionalParameters(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePrivateKey(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	plainBlock, err := ssh.MarshalPrivateKey(key, "")
+	assert.NoError(t, err)
+	plain := string(pem.EncodeToMemory(plainBlock))
+
+	encryptedBlock, err := ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte("secret"))
+	assert.NoError(t, err)
+	encrypted := string(pem.EncodeToMemory(encryptedBlock))
+
+	tests := []struct {
+		name       string
+		key        string
+		passphrase string
+		wantErr    string
+	}{
+		{name: "PlainKey", key: plain},
+		{name: "PlainKeyPassphraseIgnored", key: plain, passphrase: "unused"},
+		{name: "EncryptedOpenSSHKey", key: encrypted, passphrase: "secret"},
+		{name: "EncryptedKeyMissingPassphrase", key: encrypted, wantErr: "is encrypted but private_key_passphrase is not provided"},
+		{name: "EncryptedKeyIncorrectPassphrase", key: encrypted, passphrase: "wrong", wantErr: "incorrect private_key_passphrase"},
+		{name: "CorruptKey", key: "not a key", wantErr: "is corrupt or in an unsupported format"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			signer, err := ParsePrivateKey(tc.key, tc.passphrase)
+			if tc.wantErr != "" {
+				assert.ErrorIs(t, err, ErrorUnauthenticated)
+				assert.ErrorContains(t, err, tc.wantErr)
+				assert.Nil(t, signer)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, signer)
+		})
+	}
+}
+
+func TestCreateVolumeVerifyRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	runnerMock := mock.NewMockSSHRunner(ctrl)
+	panfs := NewPancliSSHClient(runnerMock, WithCreateVerifyRetry(3, time.Millisecond))
+	notFound := fmt.Errorf("%w: No volume with name %s", ErrorNotFound, validVolumeName)
+
+	t.Run("VisibleAfterRetry", func(t *testing.T) {
+		before := testutil.ToFloat64(metrics.CreateVolumeVerifyRetries.WithLabelValues("success"))
+		genPasXML, _ := validVolumeResponse.MarshalVolumeToPasXML()
+		gomock.InOrder(
+			runnerMock.EXPECT().RunCommand(gomock.Any(), gomock.Any(), "volume", "create", validVolumeName).Times(1).Return([]byte{}, nil),
+			runnerMock.EXPECT().RunCommand(gomock.Any(), gomock.Any(), "pasxml", "volumes", "volume", validVolumeName).Times(2).Return(nil, notFound),
+			runnerMock.EXPECT().RunCommand(gomock.Any(), gomock.Any(), "pasxml", "volumes", "volume", validVolumeName).Times(1).Return(genPasXML, nil),
+		)
+
+		vol, err := panfs.CreateVolume(t.Context(), validVolumeName, VolumeCreateParams{}, defaultSecrets)
+		assert.NoError(t, err)
+		assert.Equal(t, validVolumeName, string(vol.Name))
+		assert.Equal(t, before+1, testutil.ToFloat64(metrics.CreateVolumeVerifyRetries.WithLabelValues("success")))
+	})
+
+	t.Run("NotVisibleWithinAttempts", func(t *testing.T) {
+		before := testutil.ToFloat64(metrics.CreateVolumeVerifyRetries.WithLabelValues("failure"))
+		runnerMock.EXPECT().RunCommand(gomock.Any(), gomock.Any(), "volume", "create", validVolumeName).Times(1).Return([]byte{}, nil)
+		runnerMock.EXPECT().RunCommand(gomock.Any(), gomock.Any(), "pasxml", "volumes", "volume", validVolumeName).Times(3).Return(nil, notFound)
+
+		_, err := panfs.CreateVolume(t.Context(), validVolumeName, VolumeCreateParams{}, defaultSecrets)
+		assert.ErrorIs(t, err, ErrorNotFound)
+		assert.Equal(t, before+1, testutil.ToFloat64(metrics.CreateVolumeVerifyRetries.WithLabelValues("failure")))
+	})
+
+	t.Run("OnlineAfterCreating", func(t *testing.T) {
+		creating := *validVolumeResponse
+		creating.State = utils.VolumeStateCreating
+		creatingPasXML, _ := creating.MarshalVolumeToPasXML()
+		genPasXML, _ := validVolumeResponse.MarshalVolumeToPasXML()
+		gomock.InOrder(
+			runnerMock.EXPECT().RunCommand(gomock.Any(), gomock.Any(), "volume", "create", validVolumeName).Times(1).Return([]byte{}, nil),
+			runnerMock.EXPECT().RunCommand(gomock.Any(), gomock.Any(), "pasxml", "volumes", "volume", validVolumeName).Times(1).Return(creatingPasXML, nil),
+			runnerMock.EXPECT().RunCommand(gomock.Any(), gomock.Any(), "pasxml", "volumes", "volume", validVolumeName).Times(1).Return(genPasXML, nil),
+		)
+
+		vol, err := panfs.CreateVolume(t.Context(), validVolumeName, VolumeCreateParams{}, defaultSecrets)
+		assert.NoError(t, err)
+		assert.Equal(t, utils.VolumeStateOnline, vol.State)
+	})
+}
+
+// TestCreateVolumeScripted runs a degraded volume creation followed by polling of the created
+// volume against a scripted realm session.
+func TestCreateVolumeScripted(t *testing.T) {
+	creating := &utils.Volume{ID: "372", Name: validVolumeName, State: utils.VolumeStateCreating, Soft: 1}
+	online := &utils.Volume{ID: "372", Name: validVolumeName, State: utils.VolumeStateOnline, Soft: 1}
+	creatingPasXML, _ := creating.MarshalVolumeToPasXML()
+	onlinePasXML, _ := online.MarshalVolumeToPasXML()
+
+	runner := fake.NewRunner(t)
+	runner.Expect("volume create "+validVolumeName+" *").Return("", fmt.Errorf("%w: hard quota is not supported", ErrorInvalidArgument))
+	runner.Expect("volume create " + validVolumeName + " soft 1.00")
+	runner.Expect("pasxml volumes volume "+validVolumeName).Return("", fmt.Errorf("%w: No volume with name %s", ErrorNotFound, validVolumeName))
+	runner.Expect("pasxml volumes volume "+validVolumeName).Return(string(creatingPasXML), nil)
+	runner.Expect("pasxml volumes volume "+validVolumeName).Return(string(onlinePasXML), nil)
+
+	panfs := NewPancliSSHClient(runner, WithCreateVerifyRetry(5, time.Millisecond))
+	vol, err := panfs.CreateVolume(t.Context(), validVolumeName, VolumeCreateParams{
+		utils.VolumeParameters.GetSCKey("soft"):                     "1.00",
+		utils.VolumeParameters.GetSCKey("hard"):                     "2.00",
+		utils.VolumeParameters.GetSCKey("tolerateMissingHardQuota"): "true",
+	}, defaultSecrets)
+
+	assert.NoError(t, err)
+	assert.Equal(t, utils.VolumeStateOnline, vol.State)
+	assert.True(t, vol.HardQuotaDegraded)
+	assert.Len(t, runner.Calls(), 5)
+}
+
+func TestQuotaUnit(t *testing.T) {
+	secrets := map[string]string{
+		utils.RealmConnectionContext.RealmAddress: "testrealm",
+		utils.RealmConnectionContext.QuotaUnit:    string(utils.QuotaUnitGB),
+	}
+	online := &utils.Volume{ID: "373", Name: validVolumeName, State: utils.VolumeStateOnline, Soft: 1.07, Hard: 2.15}
+	onlinePasXML, _ := online.MarshalVolumeToPasXML()
+
+	t.Run("CreateAndExpand", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("volume create " + validVolumeName + " *")
+		runner.Expect("pasxml volumes volume "+validVolumeName).Return(string(onlinePasXML), nil)
+		runner.Expect("volume set soft-quota " + validVolumeName + " 2.15")
+
+		panfs := NewPancliSSHClient(runner)
+		params, err := NewVolumeCreateParamsBuilder().SetSoftBytes(1 << 30).SetHardBytes(2 << 30).Build()
+		assert.NoError(t, err)
+
+		vol, err := panfs.CreateVolume(t.Context(), validVolumeName, params, secrets)
+		assert.NoError(t, err)
+		assert.Contains(t, runner.Calls()[0], "soft 1.07")
+		assert.Contains(t, runner.Calls()[0], "hard 2.15")
+		assert.Equal(t, utils.QuotaUnitGB, vol.QuotaUnit)
+		assert.Equal(t, int64(1070000000), vol.GetSoftQuotaBytes())
+
+		assert.NoError(t, panfs.ExpandVolume(t.Context(), validVolumeName, 2<<30, secrets))
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		panfs := NewPancliSSHClient(fake.NewRunner(t))
+		_, err := panfs.GetVolume(t.Context(), validVolumeName, map[string]string{utils.RealmConnectionContext.QuotaUnit: "TB"})
+		assert.ErrorIs(t, err, ErrorInvalidArgument)
+		assert.ErrorContains(t, err, `quota unit "TB" must be one of: [GiB GB]`)
+	})
+}
+
+func TestCommandName(t *testing.T) {
+	assert.Equal(t, "volume create", commandName([]string{"volume", "create", validVolumeName, "soft", "1.00"}))
+	assert.Equal(t, "pasxml volumes", commandName([]string{"pasxml", "volumes", "volume", validVolumeName}))
+	assert.Equal(t, "pasxml volumes", commandName([]string{"pasxml", "volumes"}))
+}
+
+func TestExpandVolumeQuotaLimit(t *testing.T) {
+	runner := fake.NewRunner(t)
+	runner.Expect("volume set soft-quota "+validVolumeName+" 2048.00").
+		Return("", parseErrorString("Soft quota 2048.00 exceeds the maximum of 1024.00 GB"))
+
+	err := NewPancliSSHClient(runner).ExpandVolume(t.Context(), validVolumeName, 2048<<30, defaultSecrets)
+	assert.ErrorIs(t, err, ErrorOutOfRange)
+
+	var limitErr *QuotaLimitError
+	if assert.ErrorAs(t, err, &limitErr) {
+		assert.Equal(t, int64(1024<<30), limitErr.MaxBytes)
+	}
+}
+
+func TestModifyVolume(t *testing.T) {
+	params := VolumeModifyParams{
+		utils.VolumeParameters.GetSCKey("hard"):        "20.00",
+		utils.VolumeParameters.GetSCKey("efsa"):        "retry",
+		utils.VolumeParameters.GetSCKey("recovery"):    "2",
+		utils.VolumeParameters.GetSCKey("description"): "team data",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect(`volume set description `+validVolumeName+` "team data"`).Return("", nil)
+		runner.Expect("volume set recoverypriority "+validVolumeName+" 2").Return("", nil)
+		runner.Expect("volume set efsa "+validVolumeName+" retry").Return("", nil)
+		runner.Expect("volume set hard-quota "+validVolumeName+" 20.00").Return("", nil)
+
+		assert.NoError(t, NewPancliSSHClient(runner).ModifyVolume(t.Context(), validVolumeName, params, defaultSecrets))
+	})
+
+	t.Run("HardQuotaLimit", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("volume set hard-quota "+validVolumeName+" 2048.00").
+			Return("", parseErrorString("Hard quota 2048.00 exceeds the maximum of 1024.00 GB"))
+
+		err := NewPancliSSHClient(runner).ModifyVolume(t.Context(), validVolumeName,
+			VolumeModifyParams{utils.VolumeParameters.GetSCKey("hard"): "2048.00"}, defaultSecrets)
+		var limitErr *QuotaLimitError
+		if assert.ErrorAs(t, err, &limitErr) {
+			assert.Equal(t, int64(1024<<30), limitErr.MaxBytes)
+		}
+	})
+
+	t.Run("VolumeNotFound", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("volume set efsa "+validVolumeName+" retry").
+			Return("", parseErrorString("Volume "+validVolumeName+" not found"))
+
+		err := NewPancliSSHClient(runner).ModifyVolume(t.Context(), validVolumeName,
+			VolumeModifyParams{utils.VolumeParameters.GetSCKey("efsa"): "retry"}, defaultSecrets)
+		assert.ErrorIs(t, err, ErrorNotFound)
+		assert.ErrorContains(t, err, "failed to set efsa of volume")
+	})
+}
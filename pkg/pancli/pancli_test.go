@@ -0,0 +1,39 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pancli
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestLogger(t *testing.T) {
+	previous := llog
+	t.Cleanup(func() { SetLogger(previous) })
+
+	var lines []string
+	SetLogger(funcr.New(func(_, args string) { lines = append(lines, args) }, funcr.Options{}))
+
+	requestLogger(t.Context()).Info("without request")
+	requestLogger(utils.ContextWithRequestID(t.Context(), "abc123")).Info("with request")
+
+	assert.Equal(t, []string{
+		`"level"=0 "msg"="without request"`,
+		`"level"=0 "msg"="with request" "request_id"="abc123"`,
+	}, lines)
+}
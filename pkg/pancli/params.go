@@ -0,0 +1,258 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pancli
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// VolumeCreateParams represents the parameters for creating a volume.
+// Keys are storage class parameter keys (see utils.VolumeParameters.GetSCKey).
+// Soft and hard quotas are stored in gigabytes, formatted as expected by pancli.
+// Use VolumeCreateParamsBuilder to construct a valid set of parameters.
+type VolumeCreateParams map[string]string
+
+// SoftGB returns the soft quota in gigabytes, or 0 if it is not set.
+func (p VolumeCreateParams) SoftGB() float64 {
+	return p.quotaGB("soft")
+}
+
+// HardGB returns the hard quota in gigabytes, or 0 if it is not set.
+func (p VolumeCreateParams) HardGB() float64 {
+	return p.quotaGB("hard")
+}
+
+// Bladeset returns the bladeset the volume is created in, empty for the default bladeset.
+func (p VolumeCreateParams) Bladeset() string {
+	return p[utils.VolumeParameters.GetSCKey("bladeset")]
+}
+
+// Description returns the description of the volume.
+func (p VolumeCreateParams) Description() string {
+	return p[utils.VolumeParameters.GetSCKey("description")]
+}
+
+// Encrypted reports whether an encrypted volume is requested.
+func (p VolumeCreateParams) Encrypted() bool {
+	return p[utils.VolumeParameters.GetSCKey("encryption")] == "on"
+}
+
+// TolerateMissingHardQuota reports whether the volume may be created without its hard
+// quota on realms which do not support setting it.
+func (p VolumeCreateParams) TolerateMissingHardQuota() bool {
+	tolerate, _ := strconv.ParseBool(p[utils.VolumeParameters.GetSCKey("tolerateMissingHardQuota")])
+	return tolerate
+}
+
+// inQuotaUnit returns the parameters with the quotas converted from GiB to the quota unit of the realm.
+func (p VolumeCreateParams) inQuotaUnit(unit utils.QuotaUnit) VolumeCreateParams {
+	if unit == utils.QuotaUnitGiB {
+		return p
+	}
+
+	params := make(VolumeCreateParams, len(p))
+	for k, v := range p {
+		params[k] = v
+	}
+	for _, name := range []string{"soft", "hard"} {
+		key := utils.VolumeParameters.GetSCKey(name)
+		if _, ok := p[key]; ok {
+			params[key] = fmt.Sprintf("%.2f", unit.FromBytes(utils.GBToBytes(p.quotaGB(name))))
+		}
+	}
+	return params
+}
+
+// withoutHardQuota returns a copy of the parameters with the hard quota removed.
+func (p VolumeCreateParams) withoutHardQuota() VolumeCreateParams {
+	params := make(VolumeCreateParams, len(p))
+	for k, v := range p {
+		params[k] = v
+	}
+	delete(params, utils.VolumeParameters.GetSCKey("hard"))
+	return params
+}
+
+// quotaGB parses the quota stored under the given parameter name.
+func (p VolumeCreateParams) quotaGB(name string) float64 {
+	size, err := strconv.ParseFloat(p[utils.VolumeParameters.GetSCKey(name)], 64)
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+// VolumeCreateParamsBuilder builds VolumeCreateParams, validating values and converting
+// sizes to the units expected by pancli. Errors are accumulated and reported by Build.
+type VolumeCreateParamsBuilder struct {
+	params VolumeCreateParams
+	errs   []error
+}
+
+// NewVolumeCreateParamsBuilder creates a builder with unlimited soft and hard quotas.
+//
+// Returns:
+//
+//	*VolumeCreateParamsBuilder - The initialized builder.
+func NewVolumeCreateParamsBuilder() *VolumeCreateParamsBuilder {
+	b := &VolumeCreateParamsBuilder{
+		params: make(VolumeCreateParams),
+	}
+	return b.SetSoftBytes(0).SetHardBytes(0)
+}
+
+// SetParameters copies the supported storage class parameters into the builder.
+// Unsupported keys, empty values and encryption values other than "on" are skipped.
+//
+// Parameters:
+//
+//	params - The storage class parameters of the request.
+//
+// Returns:
+//
+//	*VolumeCreateParamsBuilder - The builder, for chaining.
+func (b *VolumeCreateParamsBuilder) SetParameters(params map[string]string) *VolumeCreateParamsBuilder {
+	for key, value := range params {
+		switch key {
+		case utils.VolumeParameters.GetSCKey("soft"), utils.VolumeParameters.GetSCKey("hard"):
+			// quotas are derived from the capacity range only
+			continue
+		case utils.VolumeParameters.GetSCKey("encryption"):
+			b.SetEncryption(value == "on")
+		default:
+			b.set(key, value)
+		}
+	}
+	return b
+}
+
+// SetSoftBytes sets the soft quota of the volume.
+//
+// Parameters:
+//
+//	sizeBytes - The soft quota in bytes, 0 means unlimited.
+//
+// Returns:
+//
+//	*VolumeCreateParamsBuilder - The builder, for chaining.
+func (b *VolumeCreateParamsBuilder) SetSoftBytes(sizeBytes int64) *VolumeCreateParamsBuilder {
+	return b.setQuota("soft", sizeBytes)
+}
+
+// SetHardBytes sets the hard quota of the volume.
+//
+// Parameters:
+//
+//	sizeBytes - The hard quota in bytes, 0 means unlimited.
+//
+// Returns:
+//
+//	*VolumeCreateParamsBuilder - The builder, for chaining.
+func (b *VolumeCreateParamsBuilder) SetHardBytes(sizeBytes int64) *VolumeCreateParamsBuilder {
+	return b.setQuota("hard", sizeBytes)
+}
+
+// SetBladeset sets the bladeset the volume is created in.
+func (b *VolumeCreateParamsBuilder) SetBladeset(name string) *VolumeCreateParamsBuilder {
+	return b.set(utils.VolumeParameters.GetSCKey("bladeset"), name)
+}
+
+// SetLayout sets the RAID layout of the volume.
+func (b *VolumeCreateParamsBuilder) SetLayout(layout string) *VolumeCreateParamsBuilder {
+	return b.set(utils.VolumeParameters.GetSCKey("layout"), layout)
+}
+
+// SetDescription sets the description of the volume.
+func (b *VolumeCreateParamsBuilder) SetDescription(description string) *VolumeCreateParamsBuilder {
+	return b.set(utils.VolumeParameters.GetSCKey("description"), description)
+}
+
+// SetEncryption requests an encrypted volume. Encryption is off unless requested.
+func (b *VolumeCreateParamsBuilder) SetEncryption(enabled bool) *VolumeCreateParamsBuilder {
+	key := utils.VolumeParameters.GetSCKey("encryption")
+	if !enabled {
+		delete(b.params, key)
+		return b
+	}
+	return b.set(key, "on")
+}
+
+// Build validates the collected parameters and returns them.
+//
+// Returns:
+//
+//	VolumeCreateParams - The volume creation parameters.
+//	error              - Error if any of the values is invalid.
+func (b *VolumeCreateParamsBuilder) Build() (VolumeCreateParams, error) {
+	errs := b.errs
+
+	soft, hard := b.params.SoftGB(), b.params.HardGB()
+	if hard > 0 && soft > hard {
+		errs = append(errs, fmt.Errorf("soft quota (%.2f GB) exceeds hard quota (%.2f GB)", soft, hard))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	params := make(VolumeCreateParams, len(b.params))
+	for k, v := range b.params {
+		params[k] = v
+	}
+	return params, nil
+}
+
+// set stores a supported, non-empty parameter value.
+func (b *VolumeCreateParamsBuilder) set(key, value string) *VolumeCreateParamsBuilder {
+	// only fully qualified storage class keys are supported
+	if value == "" || utils.VolumeParameters.GetSCKey(key) != key {
+		return b
+	}
+	b.params[key] = value
+	return b
+}
+
+// setQuota validates a quota and stores it converted to gigabytes.
+func (b *VolumeCreateParamsBuilder) setQuota(name string, sizeBytes int64) *VolumeCreateParamsBuilder {
+	if sizeBytes < 0 {
+		b.errs = append(b.errs, fmt.Errorf("%s quota (%d) cannot be less than zero", name, sizeBytes))
+		return b
+	}
+	b.params[utils.VolumeParameters.GetSCKey(name)] = fmt.Sprintf("%.2f", utils.BytesToGB(sizeBytes))
+	return b
+}
+
+// MutableVolumeParameters are the names of the volume parameters which can be changed on
+// existing volumes, in the order they are applied.
+var MutableVolumeParameters = []string{"description", "recovery", "efsa", "hard"}
+
+// VolumeModifyParams represents the changes of the mutable parameters of an existing volume,
+// see MutableVolumeParameters. Keys are storage class parameter keys, the hard quota is stored
+// in gigabytes like in VolumeCreateParams.
+type VolumeModifyParams map[string]string
+
+// HardGB returns the hard quota in gigabytes, and whether it is changed. 0 means unlimited.
+func (p VolumeModifyParams) HardGB() (float64, bool) {
+	value, ok := p[utils.VolumeParameters.GetSCKey("hard")]
+	if !ok {
+		return 0, false
+	}
+	size, _ := strconv.ParseFloat(value, 64)
+	return size, true
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pancli
+
+import (
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeCreateParamsBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		build   func() *VolumeCreateParamsBuilder
+		want    VolumeCreateParams
+		wantErr string
+	}{
+		{
+			name:  "Defaults",
+			build: NewVolumeCreateParamsBuilder,
+			want: VolumeCreateParams{
+				utils.VolumeParameters.GetSCKey("soft"): "0.00",
+				utils.VolumeParameters.GetSCKey("hard"): "0.00",
+			},
+		},
+		{
+			name: "QuotasConvertedToGB",
+			build: func() *VolumeCreateParamsBuilder {
+				return NewVolumeCreateParamsBuilder().SetSoftBytes(1073741824).SetHardBytes(2147483648)
+			},
+			want: VolumeCreateParams{
+				utils.VolumeParameters.GetSCKey("soft"): "1.00",
+				utils.VolumeParameters.GetSCKey("hard"): "2.00",
+			},
+		},
+		{
+			name: "StorageClassParameters",
+			build: func() *VolumeCreateParamsBuilder {
+				return NewVolumeCreateParamsBuilder().SetParameters(map[string]string{
+					utils.VolumeParameters.GetSCKey("bladeset"):   "Set 1",
+					utils.VolumeParameters.GetSCKey("layout"):     "RAID6",
+					utils.VolumeParameters.GetSCKey("encryption"): "off",
+					utils.VolumeParameters.GetSCKey("soft"):       "1073741824",
+					utils.VolumeParameters.GetSCKey("efsa"):       "",
+					"bladeset":                                    "Set 2",
+					"unsupported":                                 "value",
+				})
+			},
+			want: VolumeCreateParams{
+				utils.VolumeParameters.GetSCKey("bladeset"): "Set 1",
+				utils.VolumeParameters.GetSCKey("layout"):   "RAID6",
+				utils.VolumeParameters.GetSCKey("soft"):     "0.00",
+				utils.VolumeParameters.GetSCKey("hard"):     "0.00",
+			},
+		},
+		{
+			name: "TypedSetters",
+			build: func() *VolumeCreateParamsBuilder {
+				return NewVolumeCreateParamsBuilder().
+					SetBladeset("Set 3").
+					SetLayout("RAID10").
+					SetDescription("test volume").
+					SetEncryption(true)
+			},
+			want: VolumeCreateParams{
+				utils.VolumeParameters.GetSCKey("bladeset"):    "Set 3",
+				utils.VolumeParameters.GetSCKey("layout"):      "RAID10",
+				utils.VolumeParameters.GetSCKey("description"): "test volume",
+				utils.VolumeParameters.GetSCKey("encryption"):  "on",
+				utils.VolumeParameters.GetSCKey("soft"):        "0.00",
+				utils.VolumeParameters.GetSCKey("hard"):        "0.00",
+			},
+		},
+		{
+			name: "NegativeQuota",
+			build: func() *VolumeCreateParamsBuilder {
+				return NewVolumeCreateParamsBuilder().SetSoftBytes(-1)
+			},
+			wantErr: "soft quota (-1) cannot be less than zero",
+		},
+		{
+			name: "SoftExceedsHard",
+			build: func() *VolumeCreateParamsBuilder {
+				return NewVolumeCreateParamsBuilder().SetSoftBytes(2147483648).SetHardBytes(1073741824)
+			},
+			wantErr: "soft quota (2.00 GB) exceeds hard quota (1.00 GB)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.build().Build()
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestVolumeCreateParamsQuotas(t *testing.T) {
+	params := VolumeCreateParams{
+		utils.VolumeParameters.GetSCKey("soft"): "1.50",
+		utils.VolumeParameters.GetSCKey("hard"): "invalid",
+	}
+	assert.Equal(t, 1.5, params.SoftGB())
+	assert.Equal(t, float64(0), params.HardGB())
+}
+
+func TestVolumeCreateParamsAccessors(t *testing.T) {
+	params, err := NewVolumeCreateParamsBuilder().SetBladeset("Set 2").SetDescription("team volume").SetEncryption(true).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "Set 2", params.Bladeset())
+	assert.Equal(t, "team volume", params.Description())
+	assert.True(t, params.Encrypted())
+
+	assert.False(t, VolumeCreateParams{}.Encrypted())
+}
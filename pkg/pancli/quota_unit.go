@@ -0,0 +1,39 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pancli
+
+import (
+	"fmt"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// realmQuotaUnit returns the quota unit configured for the realm in the secrets.
+//
+// Parameters:
+//
+//	secrets - Map of authentication secrets.
+//
+// Returns:
+//
+//	utils.QuotaUnit - The quota unit of the realm, GiB if not configured.
+//	error           - ErrorInvalidArgument if the configured unit is not supported.
+func realmQuotaUnit(secrets map[string]string) (utils.QuotaUnit, error) {
+	unit, err := utils.ParseQuotaUnit(secrets[utils.RealmConnectionContext.QuotaUnit])
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %v", ErrorInvalidArgument, utils.RealmConnectionContext.QuotaUnit, err)
+	}
+	return unit, nil
+}
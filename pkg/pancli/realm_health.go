@@ -0,0 +1,112 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pancli
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// realmAddressRetryAfter is how long an unreachable realm address is tried only after
+// all other addresses of the realm.
+const realmAddressRetryAfter = 30 * time.Second
+
+// realmHealth tracks which addresses of realms configured with several director
+// addresses are reachable. The zero value is ready to use.
+type realmHealth struct {
+	// key is the configured realm value, value is the address last connected to.
+	active map[string]string
+	// key is a realm address, value is the time the last connection to it failed.
+	failures map[string]time.Time
+	sync.Mutex
+}
+
+// order returns the addresses in the order they should be tried: the active address
+// first, then the other addresses in the configured order, with addresses which
+// failed recently moved to the end.
+//
+// Parameters:
+//
+//	realm     - The configured realm value.
+//	addresses - The parsed realm addresses.
+//
+// Returns:
+//
+//	[]string - The addresses to try.
+func (h *realmHealth) order(realm string, addresses []string) []string {
+	h.Lock()
+	defer h.Unlock()
+
+	recentlyFailed := func(address string) bool {
+		failed, ok := h.failures[address]
+		return ok && time.Since(failed) < realmAddressRetryAfter
+	}
+
+	ordered := make([]string, 0, len(addresses))
+	var failed []string
+	for _, address := range addresses {
+		switch {
+		case address == h.active[realm]:
+			ordered = append([]string{address}, ordered...)
+		case recentlyFailed(address):
+			failed = append(failed, address)
+		default:
+			ordered = append(ordered, address)
+		}
+	}
+	return append(ordered, failed...)
+}
+
+// succeeded records a successful connection to the realm address.
+func (h *realmHealth) succeeded(realm, address string) {
+	h.Lock()
+	defer h.Unlock()
+
+	if h.active == nil {
+		h.active = make(map[string]string)
+	}
+	h.active[realm] = address
+	delete(h.failures, address)
+}
+
+// failed records a failed connection to the realm address.
+func (h *realmHealth) failed(realm, address string) {
+	h.Lock()
+	defer h.Unlock()
+
+	if h.failures == nil {
+		h.failures = make(map[string]time.Time)
+	}
+	h.failures[address] = time.Now()
+	if h.active[realm] == address {
+		delete(h.active, realm)
+	}
+}
+
+// unhealthy returns the realm addresses which failed recently.
+func (h *realmHealth) unhealthy() []string {
+	h.Lock()
+	defer h.Unlock()
+
+	addresses := []string{}
+	for address, failed := range h.failures {
+		if time.Since(failed) < realmAddressRetryAfter {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Strings(addresses)
+	return addresses
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// loopbackSSHDial returns a dial function connecting to an in-process SSH server for the
+// reachable addresses and failing for all others. Dialed addresses are recorded.
+func loopbackSSHDial(t *testing.T, reachable map[string]bool, dialed *[]string) func(string, string, *ssh.ClientConfig) (*ssh.Client, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostKey, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+
+	serverConfig := &ssh.ServerConfig{NoClientAuth: true}
+	serverConfig.AddHostKey(hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, chans, reqs, err := ssh.NewServerConn(conn, serverConfig)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				for ch := range chans {
+					_ = ch.Reject(ssh.Prohibited, "not supported")
+				}
+			}()
+		}
+	}()
+
+	return func(network, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+		*dialed = append(*dialed, addr)
+		if !reachable[addr] {
+			return nil, errors.New("connection refused")
+		}
+		return ssh.Dial(network, listener.Addr().String(), config)
+	}
+}
+
+// TestSSHClientRealmFailover verifies that the addresses of a realm are tried in order
+// and the last reachable address is preferred until it fails.
+func TestSSHClientRealmFailover(t *testing.T) {
+	reachable := map[string]bool{"10.0.0.2:22": true, "[fd00::3]:22": true}
+	var dialed []string
+
+	s := NewSSHClient()
+	s.dial = loopbackSSHDial(t, reachable, &dialed)
+
+	secrets := map[string]string{
+		utils.RealmConnectionContext.Username:     "testuser",
+		utils.RealmConnectionContext.Password:     "testpass",
+		utils.RealmConnectionContext.RealmAddress: "10.0.0.1, 10.0.0.2,fd00::3",
+	}
+
+	// the first address is down, the second one is used
+	client, err := s.getSSHConnection(secrets)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1:22", "10.0.0.2:22"}, dialed)
+	assert.Equal(t, []string{"10.0.0.1"}, s.DebugState()["unreachable_realm_addresses"])
+
+	// a live connection is reused
+	dialed = nil
+	_, err = s.getSSHConnection(secrets)
+	require.NoError(t, err)
+	assert.Empty(t, dialed)
+
+	// the reachable address is preferred after reconnecting
+	_ = client.Close()
+	reachable["10.0.0.1:22"] = true
+	dialed = nil
+	client, err = s.getSSHConnection(secrets)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.2:22"}, dialed)
+
+	// the active address fails, the recently failed one is tried last
+	_ = client.Close()
+	reachable["10.0.0.2:22"] = false
+	dialed = nil
+	_, err = s.getSSHConnection(secrets)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.2:22", "[fd00::3]:22"}, dialed)
+
+	// all addresses down
+	s = NewSSHClient()
+	s.dial = loopbackSSHDial(t, map[string]bool{}, &dialed)
+	_, err = s.getSSHConnection(secrets)
+	assert.ErrorContains(t, err, "failed to connect to any of the realm addresses")
+	assert.ErrorContains(t, err, "10.0.0.1: connection refused")
+	assert.ErrorContains(t, err, "fd00::3: connection refused")
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pancli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// realmLocks serializes mutating commands per realm for realms which cannot
+// handle concurrent volume operations. The zero value is ready to use.
+type realmLocks struct {
+	// key is the realm address, value is a channel with capacity 1 guarding mutating
+	// commands, so waiting for it can be cancelled.
+	locks map[string]chan struct{}
+	sync.Mutex
+}
+
+// lock acquires the realm mutex if serialization is requested in the secrets.
+//
+// Parameters:
+//
+//	ctx     - The context of the request, the wait ends when it is done.
+//	secrets - Map of authentication secrets.
+//
+// Returns:
+//
+//	func() - Function releasing the lock; a no-op if no lock was taken.
+//	error  - Error wrapping the context error if the request ended while waiting.
+func (r *realmLocks) lock(ctx context.Context, secrets map[string]string) (func(), error) {
+	serialize, _ := strconv.ParseBool(secrets[utils.RealmConnectionContext.SerializeOperations])
+	if !serialize {
+		return func() {}, nil
+	}
+
+	realm := secrets[utils.RealmConnectionContext.RealmAddress]
+
+	r.Lock()
+	if r.locks == nil {
+		r.locks = make(map[string]chan struct{})
+	}
+	mu, ok := r.locks[realm]
+	if !ok {
+		mu = make(chan struct{}, 1)
+		r.locks[realm] = mu
+	}
+	r.Unlock()
+
+	select {
+	case mu <- struct{}{}:
+		return func() { <-mu }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for the serialized operations of realm %s: %w", realm, ctx.Err())
+	}
+}
+
+// realms returns the realms which had mutating commands serialized so far.
+func (r *realmLocks) realms() []string {
+	r.Lock()
+	defer r.Unlock()
+
+	realms := make([]string, 0, len(r.locks))
+	for realm := range r.locks {
+		realms = append(realms, realm)
+	}
+	sort.Strings(realms)
+	return realms
+}
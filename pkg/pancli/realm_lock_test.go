@@ -0,0 +1,92 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pancli
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRealmLocks(t *testing.T) {
+	// run starts concurrent critical sections and returns the peak concurrency observed.
+	run := func(locks *realmLocks, secrets func(i int) map[string]string) int32 {
+		var active, peak atomic.Int32
+		var wg sync.WaitGroup
+		for i := range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				unlock, err := locks.lock(t.Context(), secrets(i))
+				if !assert.NoError(t, err) {
+					return
+				}
+				defer unlock()
+
+				n := active.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				active.Add(-1)
+			}()
+		}
+		wg.Wait()
+		return peak.Load()
+	}
+
+	secrets := func(realm, serialize string) map[string]string {
+		return map[string]string{
+			utils.RealmConnectionContext.RealmAddress:        realm,
+			utils.RealmConnectionContext.SerializeOperations: serialize,
+		}
+	}
+
+	t.Run("SerializedRealm", func(t *testing.T) {
+		peak := run(&realmLocks{}, func(int) map[string]string { return secrets("realm-a", "true") })
+		assert.Equal(t, int32(1), peak)
+	})
+
+	t.Run("NotSerialized", func(t *testing.T) {
+		peak := run(&realmLocks{}, func(int) map[string]string { return secrets("realm-a", "") })
+		assert.Greater(t, peak, int32(1))
+	})
+
+	t.Run("DifferentRealmsRunConcurrently", func(t *testing.T) {
+		realms := []string{"realm-a", "realm-b"}
+		peak := run(&realmLocks{}, func(i int) map[string]string { return secrets(realms[i%2], "true") })
+		assert.LessOrEqual(t, peak, int32(2))
+	})
+
+	t.Run("WaitCancelled", func(t *testing.T) {
+		locks := &realmLocks{}
+		unlock, err := locks.lock(t.Context(), secrets("realm-a", "true"))
+		assert.NoError(t, err)
+		defer unlock()
+
+		ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+		defer cancel()
+		_, err = locks.lock(ctx, secrets("realm-a", "true"))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
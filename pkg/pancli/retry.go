@@ -0,0 +1,170 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+)
+
+// Defaults of the retries of realm commands failing transiently.
+const (
+	DefaultRetryAttempts = 3
+	DefaultRetryBackoff  = 200 * time.Millisecond
+)
+
+// maxRetryBackoff bounds the delay between retries of a realm command.
+const maxRetryBackoff = 5 * time.Second
+
+// RetryRunner retries realm commands failing transiently, e.g. because the realm is
+// unavailable or the SSH connection was reset, with exponential backoff and jitter. Commands
+// which may have run on the realm are retried only if they are read-only, mutations are
+// verified by PancliSSHClient instead. Retries stop before the deadline of the context of the
+// request, and once the request is cancelled.
+type RetryRunner struct {
+	runner   SSHRunner
+	attempts int
+	backoff  time.Duration
+	// sleep waits between attempts unless the context is done first, replaced in tests
+	sleep func(context.Context, time.Duration) error
+}
+
+// NewRetryRunner wraps a runner with retries of transient failures.
+//
+// Parameters:
+//
+//	runner   - The runner of the realm commands.
+//	attempts - The maximum number of attempts of a command, 1 disables retries.
+//	backoff  - The delay before the first retry, doubled for each further retry.
+//
+// Returns:
+//
+//	*RetryRunner - The retrying runner.
+func NewRetryRunner(runner SSHRunner, attempts int, backoff time.Duration) *RetryRunner {
+	return &RetryRunner{
+		runner:   runner,
+		attempts: attempts,
+		backoff:  backoff,
+		sleep:    sleepContext,
+	}
+}
+
+// RunCommand runs the command, retrying transient failures.
+//
+// Parameters:
+//
+//	ctx     - The context of the request, retries stop before its deadline.
+//	secrets - Map of authentication secrets.
+//	args    - Command-line arguments to execute.
+//
+// Returns:
+//
+//	[]byte - Command output.
+//	error  - The error of the last attempt.
+func (r *RetryRunner) RunCommand(ctx context.Context, secrets map[string]string, args ...string) ([]byte, error) {
+	deadline, hasDeadline := ctx.Deadline()
+	backoff := r.backoff
+
+	for attempt := 1; ; attempt++ {
+		output, err := r.runner.RunCommand(ctx, secrets, args...)
+		if err == nil || attempt >= r.attempts || ctx.Err() != nil || !retryable(err, args) {
+			return output, err
+		}
+
+		// full jitter between half and all of the backoff spreads retries of concurrent requests
+		delay := backoff/2 + rand.N(backoff/2+1)
+		if hasDeadline && time.Until(deadline) < 2*delay {
+			requestLogger(ctx).V(4).Info("not retrying realm command, the request deadline is too close", "command", commandName(args), "attempt", attempt, "error", err.Error())
+			return output, err
+		}
+
+		metrics.RealmCommandRetries.WithLabelValues(commandName(args)).Inc()
+		requestLogger(ctx).V(2).Info("realm command failed transiently, retrying", "command", commandName(args), "attempt", attempt, "delay", delay.String(), "error", err.Error())
+		if sleepErr := r.sleep(ctx, delay); sleepErr != nil {
+			return output, err
+		}
+		backoff = min(2*backoff, maxRetryBackoff)
+	}
+}
+
+// sleepContext waits for the duration, or until the context is done.
+//
+// Parameters:
+//
+//	ctx - The context of the request.
+//	d   - The duration to wait.
+//
+// Returns:
+//
+//	error - The context error if the context is done before the duration elapsed.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// DebugState returns the state of the wrapped runner for debugging, if it exposes one.
+//
+// Returns:
+//
+//	map[string]any - The runner state, nil if the runner has none.
+func (r *RetryRunner) DebugState() map[string]any {
+	if runner, ok := r.runner.(interface{ DebugState() map[string]any }); ok {
+		return runner.DebugState()
+	}
+	return nil
+}
+
+// retryable reports whether a failed command can be retried.
+//
+// Parameters:
+//
+//	err  - The error of the command.
+//	args - Command-line arguments of the command.
+//
+// Returns:
+//
+//	bool - True for failures before the command reached the realm and for unavailable
+//	       realms, and for any connection failure of read-only commands.
+func retryable(err error, args []string) bool {
+	if errors.Is(err, ErrorOutcomeUnknown) {
+		// the realm may have run the command, only reading commands are safe to repeat
+		return len(args) > 0 && args[0] == "pasxml"
+	}
+	if errors.Is(err, ErrorUnavailable) {
+		return true
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	// includes failures of the SSH handshake, which wrap the network error
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
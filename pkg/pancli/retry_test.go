@@ -0,0 +1,131 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli/fake"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRetryRunner returns a RetryRunner recording its delays instead of sleeping.
+func newTestRetryRunner(runner SSHRunner, attempts int) (*RetryRunner, *[]time.Duration) {
+	var delays []time.Duration
+	r := NewRetryRunner(runner, attempts, 100*time.Millisecond)
+	r.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return r, &delays
+}
+
+// TestRetryRunner verifies which failures are retried and the backoff between attempts.
+func TestRetryRunner(t *testing.T) {
+	secrets := map[string]string{"realm_ip": "realm"}
+	errReset := fmt.Errorf("read tcp: %w", syscall.ECONNRESET)
+	errLost := fmt.Errorf("%w: %w: session closed", ErrorOutcomeUnknown, ErrorUnavailable)
+
+	t.Run("TransientFailure", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("volume delete -f pvc-1").Return("", ErrorUnavailable)
+		runner.Expect("volume delete -f pvc-1").Return("", errReset)
+		runner.Expect("volume delete -f pvc-1").Return("deleted successfully", nil)
+		retries := metrics.RealmCommandRetries.WithLabelValues("volume delete")
+		before := testutil.ToFloat64(retries)
+
+		r, delays := newTestRetryRunner(runner, 3)
+		out, err := r.RunCommand(t.Context(), secrets, "volume", "delete", "-f", "pvc-1")
+		require.NoError(t, err)
+		assert.Equal(t, "deleted successfully", string(out))
+
+		// exponential backoff with jitter between half and all of the backoff
+		require.Len(t, *delays, 2)
+		assert.InDelta(t, 75*time.Millisecond, (*delays)[0], float64(25*time.Millisecond))
+		assert.InDelta(t, 150*time.Millisecond, (*delays)[1], float64(50*time.Millisecond))
+		assert.Equal(t, before+2, testutil.ToFloat64(retries))
+	})
+
+	t.Run("AttemptsExhausted", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("pasxml volumes").Return("", io.EOF).Times(2)
+
+		r, _ := newTestRetryRunner(runner, 2)
+		_, err := r.RunCommand(t.Context(), secrets, "pasxml", "volumes")
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("PermanentFailure", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("volume create pvc-1").Return("", ErrorAlreadyExist)
+
+		r, delays := newTestRetryRunner(runner, 3)
+		_, err := r.RunCommand(t.Context(), secrets, "volume", "create", "pvc-1")
+		assert.ErrorIs(t, err, ErrorAlreadyExist)
+		assert.Empty(t, *delays)
+		assert.False(t, retryable(errors.New("ssh: unable to authenticate"), nil))
+	})
+
+	t.Run("OutcomeUnknown", func(t *testing.T) {
+		// reading commands are repeated, mutations are left to the outcome verification
+		runner := fake.NewRunner(t)
+		runner.Expect("pasxml volumes volume pvc-1").Return("", errLost)
+		runner.Expect("pasxml volumes volume pvc-1").Return("<pasxml/>", nil)
+		runner.Expect("volume delete -f pvc-1").Return("", errLost)
+
+		r, _ := newTestRetryRunner(runner, 3)
+		_, err := r.RunCommand(t.Context(), secrets, "pasxml", "volumes", "volume", "pvc-1")
+		assert.NoError(t, err)
+		_, err = r.RunCommand(t.Context(), secrets, "volume", "delete", "-f", "pvc-1")
+		assert.ErrorIs(t, err, ErrorOutcomeUnknown)
+	})
+
+	t.Run("RequestDeadline", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("pasxml volumes").Return("", ErrorUnavailable)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+		r, delays := newTestRetryRunner(runner, 3)
+		_, err := r.RunCommand(ctx, secrets, "pasxml", "volumes")
+		assert.ErrorIs(t, err, ErrorUnavailable)
+		assert.Empty(t, *delays)
+	})
+
+	t.Run("RequestCancelled", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("pasxml volumes").Return("", ErrorUnavailable)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		r := NewRetryRunner(runner, 3, time.Minute)
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, err := r.RunCommand(ctx, secrets, "pasxml", "volumes")
+		assert.ErrorIs(t, err, ErrorUnavailable)
+		assert.Less(t, time.Since(start), 10*time.Second)
+	})
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// CreateSnapshot creates a snapshot of a volume and returns the created snapshot object.
+// Runs the snapshot create command and retrieves the snapshot details.
+//
+// Parameters:
+//
+//	ctx          - The context of the request.
+//	volumeName   - The name of the volume to snapshot.
+//	snapshotName - The name of the snapshot to create.
+//	secrets      - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.Snapshot - The created snapshot object.
+//	error           - ErrorAlreadyExist if the volume already has a snapshot with the name,
+//	                  ErrorNotFound if the volume does not exist, or other errors if creation
+//	                  or retrieval fails.
+func (p *PancliSSHClient) CreateSnapshot(ctx context.Context, volumeName, snapshotName string, secrets map[string]string) (*utils.Snapshot, error) {
+	cmd := []string{"snapshot", "create", volumeName, snapshotName}
+
+	requestLogger(ctx).V(5).Info("CreateSnapshot executes:", "command", strings.Join(cmd, " "))
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return nil, err
+	}
+	err = p.runMutation(ctx, secrets, mutation{
+		operation: "CreateSnapshot",
+		cmd:       cmd,
+		applied:   p.snapshotCreated(ctx, volumeName, snapshotName, secrets),
+	})
+	unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.getSnapshot(ctx, volumeName, snapshotName, secrets)
+}
+
+// CreateVolumeFromSnapshot creates a volume with the content of a snapshot and returns the
+// created volume object. Runs the volume creation command with the snapshot as source and
+// retrieves the volume details.
+//
+// Parameters:
+//
+//	ctx          - The context of the request.
+//	volumeName   - The name of the volume to create.
+//	sourceVolume - The name of the snapshotted volume.
+//	snapshotName - The name of the snapshot to restore.
+//	params       - The volume creation parameters.
+//	secrets      - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.Volume - The created volume object.
+//	error         - ErrorNotFound if the snapshot does not exist, or other errors if creation
+//	                or retrieval fails.
+func (p *PancliSSHClient) CreateVolumeFromSnapshot(ctx context.Context, volumeName, sourceVolume, snapshotName string, params VolumeCreateParams, secrets map[string]string) (*utils.Volume, error) {
+	return p.createVolume(ctx, volumeName, params, []string{"from-snapshot", sourceVolume, snapshotName}, secrets)
+}
+
+// DeleteSnapshot deletes a snapshot of a volume.
+//
+// Parameters:
+//
+//	ctx          - The context of the request.
+//	volumeName   - The name of the snapshotted volume.
+//	snapshotName - The name of the snapshot to delete.
+//	secrets      - Map of authentication secrets.
+//
+// Returns:
+//
+//	error - ErrorNotFound if the snapshot does not exist, or other errors if deletion fails.
+func (p *PancliSSHClient) DeleteSnapshot(ctx context.Context, volumeName, snapshotName string, secrets map[string]string) error {
+	cmd := []string{"snapshot", "delete", "-f", volumeName, snapshotName}
+
+	unlock, err := p.realmLocks.lock(ctx, secrets)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	requestLogger(ctx).V(5).Info("DeleteSnapshot executes:", "command", strings.Join(cmd, " "))
+	return p.runMutation(ctx, secrets, mutation{
+		operation: "DeleteSnapshot",
+		cmd:       cmd,
+		applied:   p.snapshotDeleted(ctx, volumeName, snapshotName, secrets),
+	})
+}
+
+// ListSnapshots retrieves the snapshots of a volume, or of all volumes, and returns them as a
+// SnapshotList object. Runs the pasxml snapshots command and parses the output.
+//
+// Parameters:
+//
+//	ctx        - The context of the request.
+//	volumeName - The name of the volume whose snapshots are listed, empty for all volumes.
+//	secrets    - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.SnapshotList - The parsed snapshot list.
+//	error               - ErrorNotFound if the volume does not exist, or other errors if
+//	                      retrieval or parsing fails.
+func (p *PancliSSHClient) ListSnapshots(ctx context.Context, volumeName string, secrets map[string]string) (*utils.SnapshotList, error) {
+	cmd := []string{"pasxml", "snapshots"}
+	if volumeName != "" {
+		cmd = append(cmd, "volume", volumeName)
+	}
+
+	requestLogger(ctx).V(5).Info("ListSnapshots executes:", "command", strings.Join(cmd, " "))
+	out, err := p.pancli.RunCommand(ctx, secrets, cmd...)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := utils.ParseListSnapshots(out)
+	if err != nil {
+		return nil, fmt.Errorf("ListSnapshots: Cannot parse pancli response: %v", err)
+	}
+
+	return snapshots, nil
+}
+
+// getSnapshot reads the details of a single snapshot.
+//
+// Parameters:
+//
+//	ctx          - The context of the request.
+//	volumeName   - The name of the snapshotted volume.
+//	snapshotName - The name of the snapshot.
+//	secrets      - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.Snapshot - The snapshot object.
+//	error           - ErrorNotFound if the snapshot does not exist, or other errors if retrieval fails.
+func (p *PancliSSHClient) getSnapshot(ctx context.Context, volumeName, snapshotName string, secrets map[string]string) (*utils.Snapshot, error) {
+	snapshots, err := p.ListSnapshots(ctx, volumeName, secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range snapshots.Snapshots {
+		if snapshots.Snapshots[i].Name == snapshotName {
+			return &snapshots.Snapshots[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: snapshot %s of volume %s", ErrorNotFound, snapshotName, volumeName)
+}
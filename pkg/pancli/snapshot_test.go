@@ -0,0 +1,126 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli/fake"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const snapshotsPasXML = `<pasxml version="6.0.0">
+    <snapshots>
+        <snapshot id="12">
+            <name>snapshot-1</name>
+            <volumeName>/validVolumeName</volumeName>
+            <creationTime>1700000000</creationTime>
+        </snapshot>
+        <snapshot id="13">
+            <name>snapshot-2</name>
+            <volumeName>/validVolumeName</volumeName>
+            <creationTime>1700000100</creationTime>
+        </snapshot>
+    </snapshots>
+</pasxml>`
+
+func TestCreateSnapshot(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("snapshot create " + validVolumeName + " snapshot-2")
+		runner.Expect("pasxml snapshots volume "+validVolumeName).Return(snapshotsPasXML, nil)
+
+		snapshot, err := NewPancliSSHClient(runner).CreateSnapshot(t.Context(), validVolumeName, "snapshot-2", defaultSecrets)
+		require.NoError(t, err)
+		assert.Equal(t, "13", snapshot.ID)
+		assert.Equal(t, validVolumeName+"@snapshot-2", snapshot.SnapshotID())
+	})
+
+	t.Run("AlreadyExists", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("snapshot create "+validVolumeName+" snapshot-1").Return("", fmt.Errorf("%w: snapshot already exists", ErrorAlreadyExist))
+
+		_, err := NewPancliSSHClient(runner).CreateSnapshot(t.Context(), validVolumeName, "snapshot-1", defaultSecrets)
+		assert.ErrorIs(t, err, ErrorAlreadyExist)
+	})
+
+	t.Run("NotListed", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("snapshot create " + validVolumeName + " snapshot-3")
+		runner.Expect("pasxml snapshots volume "+validVolumeName).Return(snapshotsPasXML, nil)
+
+		_, err := NewPancliSSHClient(runner).CreateSnapshot(t.Context(), validVolumeName, "snapshot-3", defaultSecrets)
+		assert.ErrorIs(t, err, ErrorNotFound)
+	})
+}
+
+func TestCreateVolumeFromSnapshot(t *testing.T) {
+	online := &utils.Volume{ID: "374", Name: "restored", State: utils.VolumeStateOnline, Soft: 1}
+	onlinePasXML, _ := online.MarshalVolumeToPasXML()
+
+	t.Run("Success", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("volume create restored * from-snapshot " + validVolumeName + " snapshot-1")
+		runner.Expect("pasxml volumes volume restored").Return(string(onlinePasXML), nil)
+
+		vol, err := NewPancliSSHClient(runner).CreateVolumeFromSnapshot(t.Context(), "restored", validVolumeName, "snapshot-1", VolumeCreateParams{
+			utils.VolumeParameters.GetSCKey("soft"): "1.00",
+		}, defaultSecrets)
+		require.NoError(t, err)
+		assert.Equal(t, "374", vol.ID)
+		assert.Contains(t, runner.Calls()[0], "soft 1.00")
+	})
+
+	t.Run("SnapshotNotFound", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("volume create restored from-snapshot "+validVolumeName+" snapshot-3").Return("", fmt.Errorf("%w: snapshot-3", ErrorNotFound))
+
+		_, err := NewPancliSSHClient(runner).CreateVolumeFromSnapshot(t.Context(), "restored", validVolumeName, "snapshot-3", VolumeCreateParams{}, defaultSecrets)
+		assert.ErrorIs(t, err, ErrorNotFound)
+	})
+}
+
+func TestDeleteSnapshot(t *testing.T) {
+	runner := fake.NewRunner(t)
+	runner.Expect("snapshot delete -f " + validVolumeName + " snapshot-1")
+	runner.Expect("snapshot delete -f "+validVolumeName+" snapshot-1").Return("", fmt.Errorf("%w: snapshot-1", ErrorNotFound))
+
+	panfs := NewPancliSSHClient(runner)
+	assert.NoError(t, panfs.DeleteSnapshot(t.Context(), validVolumeName, "snapshot-1", defaultSecrets))
+	assert.ErrorIs(t, panfs.DeleteSnapshot(t.Context(), validVolumeName, "snapshot-1", defaultSecrets), ErrorNotFound)
+}
+
+func TestListSnapshots(t *testing.T) {
+	runner := fake.NewRunner(t)
+	runner.Expect("pasxml snapshots").Return(snapshotsPasXML, nil)
+	runner.Expect("pasxml snapshots volume "+validVolumeName).Return("invalid", nil)
+
+	panfs := NewPancliSSHClient(runner)
+	list, err := panfs.ListSnapshots(t.Context(), "", defaultSecrets)
+	require.NoError(t, err)
+	assert.Len(t, list.Snapshots, 2)
+
+	_, err = panfs.ListSnapshots(t.Context(), validVolumeName, defaultSecrets)
+	assert.ErrorContains(t, err, "Cannot parse pancli response")
+}
+
+func TestParseSnapshotListOutput(t *testing.T) {
+	assert.NoError(t, parseErrorString(snapshotsPasXML))
+}
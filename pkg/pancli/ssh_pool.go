@@ -0,0 +1,402 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/metrics"
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHPoolConfig configures the SSH connections of an SSHClient. Commands on a realm share one
+// connection, each command running in a session of its own.
+type SSHPoolConfig struct {
+	// MaxSessions bounds the concurrent sessions per realm, 0 for no limit. Realms refuse
+	// sessions beyond the MaxSessions setting of their SSH server, 10 by default.
+	MaxSessions int
+	// IdleTimeout closes connections without sessions for this long, 0 keeps them open.
+	IdleTimeout time.Duration
+	// KeepaliveInterval is the interval of the keepalive checks and the idle expiry of open
+	// connections, 0 disables both.
+	KeepaliveInterval time.Duration
+	// ReconnectAttempts is the number of attempts to reconnect a connection found dead by a
+	// keepalive check, 0 leaves reconnecting to the next command.
+	ReconnectAttempts int
+	// ReconnectBackoff is the delay before the first reconnect attempt, doubled for each
+	// further attempt up to maxReconnectBackoff.
+	ReconnectBackoff time.Duration
+}
+
+// DefaultSSHPoolConfig is the SSH connection pool configuration unless configured.
+var DefaultSSHPoolConfig = SSHPoolConfig{
+	MaxSessions:       10,
+	IdleTimeout:       10 * time.Minute,
+	KeepaliveInterval: 30 * time.Second,
+	ReconnectAttempts: 3,
+	ReconnectBackoff:  time.Second,
+}
+
+// maxReconnectBackoff bounds the delay between reconnect attempts.
+const maxReconnectBackoff = 30 * time.Second
+
+// WithSSHPool configures the SSH connections to realms.
+//
+// Parameters:
+//
+//	config - The pool configuration.
+//
+// Returns:
+//
+//	SSHClientOption - The client option.
+func WithSSHPool(config SSHPoolConfig) SSHClientOption {
+	return func(s *SSHClient) {
+		s.pool = config
+	}
+}
+
+// ValidateSSHPoolConfig checks the pool configuration, e.g. as configured by command line flags.
+//
+// Parameters:
+//
+//	config - The pool configuration.
+//
+// Returns:
+//
+//	error - Error if a setting is negative.
+func ValidateSSHPoolConfig(config SSHPoolConfig) error {
+	switch {
+	case config.MaxSessions < 0:
+		return fmt.Errorf("invalid SSH max sessions %d: must not be negative", config.MaxSessions)
+	case config.IdleTimeout < 0:
+		return fmt.Errorf("invalid SSH idle timeout %s: must not be negative", config.IdleTimeout)
+	case config.KeepaliveInterval < 0:
+		return fmt.Errorf("invalid SSH keepalive interval %s: must not be negative", config.KeepaliveInterval)
+	case config.ReconnectAttempts < 0:
+		return fmt.Errorf("invalid SSH reconnect attempts %d: must not be negative", config.ReconnectAttempts)
+	case config.ReconnectBackoff < 0:
+		return fmt.Errorf("invalid SSH reconnect backoff %s: must not be negative", config.ReconnectBackoff)
+	}
+	return nil
+}
+
+// realmConn is the pooled connection to a realm. Entries stay in the pool when their
+// connection is closed, so the session limit and the configuration outlive reconnects.
+// All fields are guarded by the mutex of the SSHClient.
+type realmConn struct {
+	// client is the open connection, nil while disconnected
+	client *ssh.Client
+	// config of the last connection, used to reconnect it
+	config *ssh.ClientConfig
+	// slots bounds the concurrent sessions, nil for no limit
+	slots chan struct{}
+	// sessions is the number of running sessions
+	sessions int
+	// lastUsed is the time the last session ended
+	lastUsed time.Time
+	// reconnecting is set while the keepalive goroutine reconnects the connection
+	reconnecting bool
+}
+
+// realmConn returns the pool entry of the realm, creating it if needed. The caller must
+// hold the lock of the SSHClient.
+func (s *SSHClient) realmConn(realm string) *realmConn {
+	if s.clients == nil {
+		s.clients = make(map[string]*realmConn)
+	}
+	rc, ok := s.clients[realm]
+	if !ok {
+		rc = &realmConn{}
+		if s.pool.MaxSessions > 0 {
+			rc.slots = make(chan struct{}, s.pool.MaxSessions)
+		}
+		s.clients[realm] = rc
+	}
+	return rc
+}
+
+// dropConnection removes the open connection of a pool entry. The caller must hold the
+// lock of the SSHClient and close the returned connection.
+func (rc *realmConn) dropConnection() *ssh.Client {
+	client := rc.client
+	if client != nil {
+		rc.client = nil
+		metrics.RealmSSHConnections.Dec()
+	}
+	return client
+}
+
+// acquireSession waits for a free session of the realm. Waiting is bounded by the connection
+// timeout, so a realm saturated by slow commands fails new commands instead of queuing them
+// forever.
+//
+// Parameters:
+//
+//	ctx   - The context of the request, the wait ends when it is done.
+//	realm - The configured realm value.
+//
+// Returns:
+//
+//	func() - Releases the session.
+//	error  - ErrorUnavailable if no session becomes free in time.
+func (s *SSHClient) acquireSession(ctx context.Context, realm string) (func(), error) {
+	s.Lock()
+	rc := s.realmConn(realm)
+	s.Unlock()
+
+	if rc.slots != nil {
+		select {
+		case rc.slots <- struct{}{}:
+		default:
+			timeout := s.timeout
+			if timeout == 0 {
+				timeout = sshConnectTimeout
+			}
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+
+			select {
+			case rc.slots <- struct{}{}:
+				metrics.RealmSSHSessionWaits.WithLabelValues("acquired").Inc()
+			case <-timer.C:
+				metrics.RealmSSHSessionWaits.WithLabelValues("timeout").Inc()
+				return nil, fmt.Errorf("%w: all %d SSH sessions to realm %s are busy", ErrorUnavailable, cap(rc.slots), realm)
+			case <-ctx.Done():
+				metrics.RealmSSHSessionWaits.WithLabelValues("cancelled").Inc()
+				return nil, fmt.Errorf("waiting for an SSH session to realm %s: %w", realm, ctx.Err())
+			}
+		}
+	}
+
+	s.Lock()
+	rc.sessions++
+	s.Unlock()
+
+	return func() {
+		s.Lock()
+		rc.sessions--
+		rc.lastUsed = time.Now()
+		s.Unlock()
+		if rc.slots != nil {
+			<-rc.slots
+		}
+	}, nil
+}
+
+// startKeepalive starts the goroutine checking the pooled connections, once per client.
+// The caller must hold the lock of the SSHClient.
+func (s *SSHClient) startKeepalive() {
+	if s.pool.KeepaliveInterval <= 0 || s.keepaliveStarted {
+		return
+	}
+	s.keepaliveStarted = true
+	if s.done == nil {
+		s.done = make(chan struct{})
+	}
+
+	go func(done <-chan struct{}) {
+		ticker := time.NewTicker(s.pool.KeepaliveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.maintainConnections(time.Now())
+			case <-done:
+				return
+			}
+		}
+	}(s.done)
+}
+
+// maintainConnections closes idle connections and sends a keepalive request on the others.
+// Connections which do not answer are closed and reconnected in the background.
+//
+// Parameters:
+//
+//	now - The current time.
+func (s *SSHClient) maintainConnections(now time.Time) {
+	type probe struct {
+		realm  string
+		rc     *realmConn
+		client *ssh.Client
+	}
+	var idle []*ssh.Client
+	var probes []probe
+
+	s.Lock()
+	for realm, rc := range s.clients {
+		if rc.client == nil {
+			continue
+		}
+		if s.pool.IdleTimeout > 0 && rc.sessions == 0 && now.Sub(rc.lastUsed) >= s.pool.IdleTimeout {
+			llog.V(4).Info("closing idle SSH connection", "realm", realm, "idle", now.Sub(rc.lastUsed).String())
+			idle = append(idle, rc.dropConnection())
+			continue
+		}
+		probes = append(probes, probe{realm: realm, rc: rc, client: rc.client})
+	}
+	s.Unlock()
+
+	for _, client := range idle {
+		_ = client.Close()
+	}
+
+	for _, p := range probes {
+		err := s.keepalive(p.client)
+		if err == nil {
+			continue
+		}
+		llog.Info("SSH connection to realm lost", "realm", p.realm, "error", err.Error())
+
+		s.Lock()
+		// a command may have replaced the connection meanwhile
+		if p.rc.client != p.client {
+			s.Unlock()
+			continue
+		}
+		p.rc.dropConnection()
+		reconnect := s.pool.ReconnectAttempts > 0 && p.rc.config != nil && !p.rc.reconnecting
+		p.rc.reconnecting = reconnect
+		s.Unlock()
+
+		_ = p.client.Close()
+		if reconnect {
+			go s.reconnect(p.realm, p.rc)
+		}
+	}
+}
+
+// keepalive sends a keepalive request on the connection and waits for the reply, bounded
+// by the connection timeout.
+//
+// Parameters:
+//
+//	client - The connection.
+//
+// Returns:
+//
+//	error - Error if the request fails or is not answered in time.
+func (s *SSHClient) keepalive(client *ssh.Client) error {
+	timeout := s.timeout
+	if timeout == 0 {
+		timeout = sshConnectTimeout
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		// servers answer unknown requests with a failure, which proves the connection alive
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("no keepalive reply within %s", timeout)
+	}
+}
+
+// reconnect connects a lost connection again, with exponential backoff between attempts.
+// A connection opened meanwhile by a command is kept.
+//
+// Parameters:
+//
+//	realm - The configured realm value.
+//	rc    - The pool entry of the realm.
+func (s *SSHClient) reconnect(realm string, rc *realmConn) {
+	s.Lock()
+	config := rc.config
+	s.Unlock()
+	defer func() {
+		s.Lock()
+		rc.reconnecting = false
+		s.Unlock()
+	}()
+
+	backoff := s.pool.ReconnectBackoff
+	for attempt := 1; attempt <= s.pool.ReconnectAttempts; attempt++ {
+		select {
+		case <-time.After(backoff):
+		case <-s.done:
+			return
+		}
+
+		s.Lock()
+		connected := rc.client != nil
+		s.Unlock()
+		if connected {
+			return
+		}
+
+		client, err := s.connect(realm, config)
+		if err != nil {
+			metrics.RealmSSHReconnects.WithLabelValues("error").Inc()
+			llog.V(4).Info("failed to reconnect to realm", "realm", realm, "attempt", attempt, "error", err.Error())
+			backoff = min(2*backoff, maxReconnectBackoff)
+			continue
+		}
+
+		metrics.RealmSSHReconnects.WithLabelValues("success").Inc()
+		s.Lock()
+		if rc.client != nil || s.closed() {
+			s.Unlock()
+			_ = client.Close()
+			return
+		}
+		rc.client = client
+		rc.lastUsed = time.Now()
+		metrics.RealmSSHConnections.Inc()
+		s.Unlock()
+		llog.Info("reconnected to realm", "realm", realm, "attempt", attempt)
+		return
+	}
+	llog.Info("giving up reconnecting to realm, the next command connects again", "realm", realm, "attempts", s.pool.ReconnectAttempts)
+}
+
+// closed reports whether Close was called. The caller must hold the lock of the SSHClient.
+func (s *SSHClient) closed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops the keepalive goroutine and closes all connections. The client must not be
+// used afterwards.
+//
+// Returns:
+//
+//	error - Always nil.
+func (s *SSHClient) Close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.done != nil && !s.closed() {
+		close(s.done)
+	}
+	for _, rc := range s.clients {
+		if client := rc.dropConnection(); client != nil {
+			_ = client.Close()
+		}
+	}
+	return nil
+}
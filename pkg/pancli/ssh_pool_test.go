@@ -0,0 +1,144 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli/sshtest"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPoolTestClient returns an SSHClient connecting to the server with the pool configuration
+// and a short timeout.
+func newPoolTestClient(server *sshtest.Server, config SSHPoolConfig) *SSHClient {
+	client := newServerSSHClient(server)
+	client.pool = config
+	client.timeout = 200 * time.Millisecond
+	return client
+}
+
+// TestSSHPoolMaxSessions verifies that commands beyond the session limit of a realm wait for
+// a free session and fail once the connection timeout expires.
+func TestSSHPoolMaxSessions(t *testing.T) {
+	secrets := realmSecrets(map[string]string{utils.RealmConnectionContext.Password: "secret"})
+	server := sshtest.NewServer(t, sshtest.WithPassword("admin", "secret"))
+	server.Handle("pasxml volumes", sshtest.Response{Output: "<pasxml><volumes></volumes></pasxml>", Delay: 500 * time.Millisecond})
+	server.Handle("pasxml volumes volume *", sshtest.Response{Output: "<pasxml><volumes></volumes></pasxml>"})
+
+	run := func(client *SSHClient, n int) []error {
+		errs := make([]error, n)
+		var wg sync.WaitGroup
+		for i := range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[i] = client.RunCommand(t.Context(), secrets, "pasxml", "volumes")
+			}()
+		}
+		wg.Wait()
+		return errs
+	}
+
+	t.Run("Limited", func(t *testing.T) {
+		client := newPoolTestClient(server, SSHPoolConfig{MaxSessions: 1})
+		// connect first, so both commands compete for the session only
+		_, err := client.RunCommand(t.Context(), secrets, "pasxml", "volumes", "volume", "pvc-1")
+		require.NoError(t, err)
+
+		errs := run(client, 2)
+		failed := 0
+		for _, err := range errs {
+			if err != nil {
+				failed++
+				assert.ErrorIs(t, err, ErrorUnavailable)
+				assert.ErrorContains(t, err, "SSH sessions to realm realm.example.com are busy")
+			}
+		}
+		assert.Equal(t, 1, failed)
+	})
+
+	t.Run("Unlimited", func(t *testing.T) {
+		client := newPoolTestClient(server, SSHPoolConfig{})
+		for _, err := range run(client, 3) {
+			assert.NoError(t, err)
+		}
+	})
+}
+
+// TestSSHPoolIdleTimeout verifies that connections without sessions are closed once idle,
+// and that the next command connects again.
+func TestSSHPoolIdleTimeout(t *testing.T) {
+	secrets := realmSecrets(map[string]string{utils.RealmConnectionContext.Password: "secret"})
+	server := newRealmServer(t, sshtest.WithPassword("admin", "secret"))
+	client := newPoolTestClient(server, SSHPoolConfig{IdleTimeout: time.Minute})
+
+	_, err := client.RunCommand(t.Context(), secrets, "pasxml", "volumes")
+	require.NoError(t, err)
+
+	client.maintainConnections(time.Now())
+	assert.Equal(t, []string{"realm.example.com"}, client.DebugState()["ssh_connections"])
+
+	client.maintainConnections(time.Now().Add(time.Minute))
+	assert.Empty(t, client.DebugState()["ssh_connections"])
+
+	_, err = client.RunCommand(t.Context(), secrets, "pasxml", "volumes")
+	require.NoError(t, err)
+	assert.Equal(t, 2, server.Connections())
+}
+
+// TestSSHPoolKeepalive verifies that connections lost while idle are detected by the
+// keepalive goroutine and reconnected in the background.
+func TestSSHPoolKeepalive(t *testing.T) {
+	secrets := realmSecrets(map[string]string{utils.RealmConnectionContext.Password: "secret"})
+	server := newRealmServer(t, sshtest.WithPassword("admin", "secret"))
+	client := newPoolTestClient(server, SSHPoolConfig{
+		KeepaliveInterval: 20 * time.Millisecond,
+		ReconnectAttempts: 3,
+		ReconnectBackoff:  time.Millisecond,
+	})
+	t.Cleanup(func() { _ = client.Close() })
+
+	_, err := client.RunCommand(t.Context(), secrets, "pasxml", "volumes")
+	require.NoError(t, err)
+	require.Equal(t, 1, server.Connections())
+
+	server.DropConnections()
+	assert.Eventually(t, func() bool { return server.Connections() == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		connections, _ := client.DebugState()["ssh_connections"].([]string)
+		return len(connections) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the command uses the reconnected connection
+	_, err = client.RunCommand(t.Context(), secrets, "pasxml", "volumes")
+	require.NoError(t, err)
+	assert.Equal(t, 2, server.Connections())
+}
+
+// TestValidateSSHPoolConfig verifies that negative pool settings are rejected.
+func TestValidateSSHPoolConfig(t *testing.T) {
+	assert.NoError(t, ValidateSSHPoolConfig(DefaultSSHPoolConfig))
+	assert.NoError(t, ValidateSSHPoolConfig(SSHPoolConfig{}))
+	assert.ErrorContains(t, ValidateSSHPoolConfig(SSHPoolConfig{MaxSessions: -1}), "max sessions")
+	assert.ErrorContains(t, ValidateSSHPoolConfig(SSHPoolConfig{IdleTimeout: -time.Second}), "idle timeout")
+	assert.ErrorContains(t, ValidateSSHPoolConfig(SSHPoolConfig{ReconnectBackoff: -time.Second}), "reconnect backoff")
+}
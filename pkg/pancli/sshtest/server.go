@@ -0,0 +1,426 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sshtest provides an in-process SSH server emulating the pancli of a realm, for
+// integration tests of the SSH client including authentication, error parsing and timeouts.
+package sshtest
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Response is the emulated response of the realm to a command.
+type Response struct {
+	// Output is the combined output of the command.
+	Output string `json:"output"`
+	// ExitStatus is the exit status of the command, 0 by default.
+	ExitStatus int `json:"exit_status"`
+	// Delay is the time the command runs before responding.
+	Delay time.Duration `json:"-"`
+	// Disconnect closes the session without an exit status, emulating a connection lost
+	// after the realm ran the command.
+	Disconnect bool `json:"disconnect"`
+}
+
+// Fixture is a command handled by the server and the response to it, as loaded from a
+// fixture file.
+type Fixture struct {
+	// Command is the glob pattern of the command, where "*" matches any text.
+	Command string `json:"command"`
+	Response
+}
+
+// LoadFixtures reads a JSON file with a list of fixtures.
+//
+// Parameters:
+//
+//	path - The path of the fixture file.
+//
+// Returns:
+//
+//	[]Fixture - The fixtures in file order.
+//	error     - Error if the file cannot be read or parsed.
+func LoadFixtures(path string) ([]Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []Fixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures %s: %w", path, err)
+	}
+	return fixtures, nil
+}
+
+// handler is a command pattern and its response.
+type handler struct {
+	pattern  *regexp.Regexp
+	response Response
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithPassword accepts password authentication of the user.
+//
+// Parameters:
+//
+//	user     - The user name.
+//	password - The password of the user.
+//
+// Returns:
+//
+//	Option - The server option.
+func WithPassword(user, password string) Option {
+	return func(s *Server) {
+		s.config.PasswordCallback = func(conn ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if conn.User() == user && string(pass) == password {
+				return nil, nil
+			}
+			return nil, errors.New("invalid password")
+		}
+	}
+}
+
+// WithKeyboardInteractive accepts keyboard-interactive authentication of the user, asking
+// a single password question.
+//
+// Parameters:
+//
+//	user     - The user name.
+//	password - The expected answer.
+//
+// Returns:
+//
+//	Option - The server option.
+func WithKeyboardInteractive(user, password string) Option {
+	return func(s *Server) {
+		s.config.KeyboardInteractiveCallback = func(conn ssh.ConnMetadata, challenge ssh.KeyboardInteractiveChallenge) (*ssh.Permissions, error) {
+			answers, err := challenge(user, "", []string{"Password: "}, []bool{false})
+			if err != nil {
+				return nil, err
+			}
+			if conn.User() == user && len(answers) == 1 && answers[0] == password {
+				return nil, nil
+			}
+			return nil, errors.New("invalid answer")
+		}
+	}
+}
+
+// WithPublicKey accepts public key authentication of the user.
+//
+// Parameters:
+//
+//	user - The user name.
+//	key  - The authorized public key of the user.
+//
+// Returns:
+//
+//	Option - The server option.
+func WithPublicKey(user string, key ssh.PublicKey) Option {
+	return func(s *Server) {
+		s.config.PublicKeyCallback = func(conn ssh.ConnMetadata, offered ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == user && bytes.Equal(offered.Marshal(), key.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unauthorized key")
+		}
+	}
+}
+
+// WithHandshakeDelay delays the SSH handshake of new connections, e.g. to emulate an
+// unresponsive realm.
+//
+// Parameters:
+//
+//	delay - The time to wait before the handshake.
+//
+// Returns:
+//
+//	Option - The server option.
+func WithHandshakeDelay(delay time.Duration) Option {
+	return func(s *Server) {
+		s.handshakeDelay = delay
+	}
+}
+
+// Server is an in-process SSH server answering exec requests with the responses of the
+// first matching handler. Commands without a handler fail the test. Without an
+// authentication option any client is accepted. All commands are recorded. Server is safe
+// for concurrent use.
+type Server struct {
+	t              testing.TB
+	listener       net.Listener
+	config         *ssh.ServerConfig
+	hostKey        ssh.PublicKey
+	handshakeDelay time.Duration
+	done           chan struct{}
+	handlers       []handler
+	commands       []string
+	connections    int
+	open           map[*ssh.ServerConn]struct{}
+	sync.Mutex
+}
+
+// NewServer starts a server listening on a random local port. It is stopped when the
+// test finishes.
+//
+// Parameters:
+//
+//	t    - The test using the server.
+//	opts - The server options.
+//
+// Returns:
+//
+//	*Server - The running server without handlers.
+func NewServer(t testing.TB, opts ...Option) *Server {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate host key: %v", err)
+	}
+	hostKey, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("failed to create host key signer: %v", err)
+	}
+
+	s := &Server{
+		t:       t,
+		config:  &ssh.ServerConfig{},
+		hostKey: hostKey.PublicKey(),
+		done:    make(chan struct{}),
+		open:    make(map[*ssh.ServerConn]struct{}),
+	}
+	s.config.AddHostKey(hostKey)
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.config.PasswordCallback == nil && s.config.KeyboardInteractiveCallback == nil && s.config.PublicKeyCallback == nil {
+		s.config.NoClientAuth = true
+	}
+
+	s.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(s.close)
+
+	go s.serve()
+	return s
+}
+
+// Addr returns the address the server listens on.
+//
+// Returns:
+//
+//	string - The "host:port" address.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// HostKey returns the public host key of the server.
+//
+// Returns:
+//
+//	ssh.PublicKey - The host key presented to clients.
+func (s *Server) HostKey() ssh.PublicKey {
+	return s.hostKey
+}
+
+// Handle responds to commands matching the pattern. Handlers are matched in the order
+// they were added.
+//
+// Parameters:
+//
+//	command  - The glob pattern of the command, where "*" matches any text.
+//	response - The response to matching commands.
+func (s *Server) Handle(command string, response Response) {
+	quoted := regexp.QuoteMeta(command)
+	pattern := regexp.MustCompile("^" + strings.ReplaceAll(quoted, `\*`, ".*") + "$")
+
+	s.Lock()
+	defer s.Unlock()
+	s.handlers = append(s.handlers, handler{pattern: pattern, response: response})
+}
+
+// HandleFixtures adds a handler for each of the fixtures.
+//
+// Parameters:
+//
+//	fixtures - The fixtures, e.g. loaded with LoadFixtures.
+func (s *Server) HandleFixtures(fixtures []Fixture) {
+	for _, fixture := range fixtures {
+		s.Handle(fixture.Command, fixture.Response)
+	}
+}
+
+// Commands returns the commands run so far, including unhandled ones.
+//
+// Returns:
+//
+//	[]string - The commands in order.
+func (s *Server) Commands() []string {
+	s.Lock()
+	defer s.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+// Connections returns the number of authenticated connections accepted so far.
+//
+// Returns:
+//
+//	int - The number of connections.
+func (s *Server) Connections() int {
+	s.Lock()
+	defer s.Unlock()
+	return s.connections
+}
+
+// DropConnections closes all open connections, e.g. to emulate a restarted realm.
+func (s *Server) DropConnections() {
+	s.Lock()
+	defer s.Unlock()
+	for conn := range s.open {
+		_ = conn.Close()
+	}
+}
+
+// close stops the server.
+func (s *Server) close() {
+	close(s.done)
+	_ = s.listener.Close()
+}
+
+// serve accepts connections until the server is stopped.
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn runs the SSH protocol on an accepted connection.
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	if s.handshakeDelay > 0 {
+		select {
+		case <-time.After(s.handshakeDelay):
+		case <-s.done:
+			return
+		}
+	}
+
+	sshConn, chans, reqs, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		return
+	}
+	defer func() { _ = sshConn.Close() }()
+
+	s.Lock()
+	s.connections++
+	s.open[sshConn] = struct{}{}
+	s.Unlock()
+	defer func() {
+		s.Lock()
+		delete(s.open, sshConn)
+		s.Unlock()
+	}()
+
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go s.serveSession(channel, requests)
+	}
+}
+
+// serveSession answers the exec request of a session.
+func (s *Server) serveSession(channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer func() { _ = channel.Close() }()
+
+	for req := range requests {
+		if req.Type != "exec" {
+			if req.WantReply {
+				_ = req.Reply(false, nil)
+			}
+			continue
+		}
+
+		var payload struct{ Command string }
+		if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		_ = req.Reply(true, nil)
+
+		response := s.respond(payload.Command)
+		if response.Delay > 0 {
+			select {
+			case <-time.After(response.Delay):
+			case <-s.done:
+				return
+			}
+		}
+
+		_, _ = channel.Write([]byte(response.Output))
+		if response.Disconnect {
+			return
+		}
+		status := struct{ Status uint32 }{uint32(response.ExitStatus)}
+		_, _ = channel.SendRequest("exit-status", false, ssh.Marshal(&status))
+		return
+	}
+}
+
+// respond records the command and returns the response of the first matching handler.
+func (s *Server) respond(command string) Response {
+	s.Lock()
+	defer s.Unlock()
+	s.commands = append(s.commands, command)
+
+	for _, h := range s.handlers {
+		if h.pattern.MatchString(command) {
+			return h.response
+		}
+	}
+
+	s.t.Errorf("unexpected command %q", command)
+	return Response{Output: fmt.Sprintf("Error: unknown command %q", command), ExitStatus: 127}
+}
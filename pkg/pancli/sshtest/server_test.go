@@ -0,0 +1,74 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshtest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestServer(t *testing.T) {
+	server := NewServer(t)
+	server.Handle("volume create *", Response{Output: "created successfully"})
+	server.Handle("volume delete *", Response{Output: "failed", ExitStatus: 3})
+	server.Handle("volume set *", Response{Disconnect: true})
+
+	client, err := ssh.Dial("tcp", server.Addr(), &ssh.ClientConfig{User: "admin", HostKeyCallback: ssh.InsecureIgnoreHostKey()})
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+
+	run := func(command string) ([]byte, error) {
+		session, err := client.NewSession()
+		require.NoError(t, err)
+		defer func() { _ = session.Close() }()
+		return session.CombinedOutput(command)
+	}
+
+	out, err := run(`volume create pvc-1 bladeset "Set 1"`)
+	assert.NoError(t, err)
+	assert.Equal(t, "created successfully", string(out))
+
+	out, err = run("volume delete -f pvc-1")
+	var exitErr *ssh.ExitError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 3, exitErr.ExitStatus())
+	assert.Equal(t, "failed", string(out))
+
+	_, err = run("volume set soft-quota pvc-1 2.00")
+	var missingErr *ssh.ExitMissingError
+	assert.True(t, errors.As(err, &missingErr))
+
+	assert.Equal(t, []string{`volume create pvc-1 bladeset "Set 1"`, "volume delete -f pvc-1", "volume set soft-quota pvc-1 2.00"}, server.Commands())
+	assert.Equal(t, 1, server.Connections())
+}
+
+func TestLoadFixtures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"command": "pasxml volumes", "output": "<volumes/>", "exit_status": 1}]`), 0o600))
+
+	fixtures, err := LoadFixtures(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Fixture{{Command: "pasxml volumes", Response: Response{Output: "<volumes/>", ExitStatus: 1}}}, fixtures)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{`), 0o600))
+	_, err = LoadFixtures(path)
+	assert.ErrorContains(t, err, "failed to parse fixtures")
+}
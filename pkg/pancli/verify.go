@@ -0,0 +1,61 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"errors"
+	"fmt"
+)
+
+// VerifyCredentials opens a new SSH connection to the realm and authenticates with the
+// given secrets. Cached connections are neither used nor updated, so the check always
+// exercises the credentials, e.g. when validating a rotated secret.
+//
+// Parameters:
+//
+//	secrets - Map of authentication secrets.
+//
+// Returns:
+//
+//	error - ErrorUnauthenticated if the credentials are rejected, ErrorUnavailable if the
+//	        realm cannot be reached, ErrorInternal otherwise.
+func (s *SSHClient) VerifyCredentials(secrets map[string]string) error {
+	probe := &SSHClient{
+		clients:            make(map[string]*realmConn),
+		dial:               s.dial,
+		timeout:            s.timeout,
+		knownHostsFile:     s.knownHostsFile,
+		strictHostKeys:     s.strictHostKeys,
+		forbidPasswordAuth: s.forbidPasswordAuth,
+	}
+
+	if _, err := probe.getSSHConnection(secrets); err != nil {
+		return classifyConnectionError(err)
+	}
+	return probe.Close()
+}
+
+// classifyConnectionError wraps an SSH connection error into the matching pancli error.
+func classifyConnectionError(err error) error {
+	if errors.Is(err, ErrorUnauthenticated) || errors.Is(err, ErrorUnavailable) {
+		return err
+	}
+	if classified := parseErrorString(err.Error()); classified != nil {
+		return classified
+	}
+	return fmt.Errorf("%w: %s", ErrorInternal, err)
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+// TestSSHClientVerifyCredentials verifies that credentials are checked on a new connection
+// and connection errors are classified.
+func TestSSHClientVerifyCredentials(t *testing.T) {
+	secrets := func(address string) map[string]string {
+		return map[string]string{
+			utils.RealmConnectionContext.Username:     "testuser",
+			utils.RealmConnectionContext.Password:     "testpass",
+			utils.RealmConnectionContext.RealmAddress: address,
+		}
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		var dialed []string
+		s := NewSSHClient()
+		s.dial = loopbackSSHDial(t, map[string]bool{"10.0.0.1:22": true}, &dialed)
+
+		assert.NoError(t, s.VerifyCredentials(secrets("10.0.0.1")))
+		assert.NoError(t, s.VerifyCredentials(secrets("10.0.0.1")))
+		assert.Len(t, dialed, 2, "each check must open a new connection")
+		assert.Empty(t, s.DebugState()["ssh_connections"])
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		var dialed []string
+		s := NewSSHClient()
+		s.dial = loopbackSSHDial(t, nil, &dialed)
+
+		err := s.VerifyCredentials(secrets("10.0.0.1"))
+		assert.ErrorIs(t, err, ErrorUnavailable)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		s := NewSSHClient()
+		s.dial = func(string, string, *ssh.ClientConfig) (*ssh.Client, error) {
+			return nil, errors.New("ssh: handshake failed: ssh: unable to authenticate, attempted methods [none password]")
+		}
+
+		err := s.VerifyCredentials(secrets("10.0.0.1"))
+		assert.ErrorIs(t, err, ErrorUnauthenticated)
+	})
+
+	t.Run("MissingCredentials", func(t *testing.T) {
+		s := NewSSHClient()
+		err := s.VerifyCredentials(map[string]string{utils.RealmConnectionContext.RealmAddress: "10.0.0.1"})
+		assert.ErrorIs(t, err, ErrorInternal)
+	})
+}
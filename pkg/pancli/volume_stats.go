@@ -0,0 +1,53 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+)
+
+// GetVolumeStats reads the performance counters of all volumes of the realm.
+// Runs the pasxml volumestats command and parses the output.
+//
+// Parameters:
+//
+//	ctx     - The context of the request.
+//	secrets - Map of authentication secrets.
+//
+// Returns:
+//
+//	*utils.VolumeStatsList - The performance counters of the volumes.
+//	error                  - Error if the command fails, see IsUnsupportedCommand for realms
+//	                         without performance counters, or if parsing fails.
+func (p *PancliSSHClient) GetVolumeStats(ctx context.Context, secrets map[string]string) (*utils.VolumeStatsList, error) {
+	cmd := []string{"pasxml", "volumestats"}
+	requestLogger(ctx).V(5).Info("GetVolumeStats executes:", "command", strings.Join(cmd, " "))
+	out, err := p.pancli.RunCommand(ctx, secrets, cmd...)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := utils.ParseVolumeStats(out)
+	if err != nil {
+		return nil, fmt.Errorf("GetVolumeStats: Cannot parse pancli response: %v", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeonly
+
+package pancli
+
+import (
+	"testing"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/pancli/fake"
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVolumeStats(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("pasxml volumestats").Return(`<pasxml version="6.0.0"><volumeStats>
+			<volume id="3"><name>/pvc-1</name><readBytes>1024</readBytes><writeLatencyUs>250</writeLatencyUs></volume>
+		</volumeStats></pasxml>`, nil)
+
+		stats, err := NewPancliSSHClient(runner).GetVolumeStats(t.Context(), defaultSecrets)
+		require.NoError(t, err)
+		require.Len(t, stats.Volumes, 1)
+		assert.Equal(t, utils.VolumeName("pvc-1"), stats.Volumes[0].Name)
+		assert.Equal(t, 1024.0, stats.Volumes[0].ReadBytes)
+		assert.Equal(t, 250.0, stats.Volumes[0].WriteLatencyUs)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("pasxml volumestats").Return("", parseErrorString("Unknown command: volumestats"))
+
+		_, err := NewPancliSSHClient(runner).GetVolumeStats(t.Context(), defaultSecrets)
+		assert.True(t, IsUnsupportedCommand(err))
+	})
+
+	t.Run("InvalidOutput", func(t *testing.T) {
+		runner := fake.NewRunner(t)
+		runner.Expect("pasxml volumestats").Return("<pasxml><volumeStats>", nil)
+
+		_, err := NewPancliSSHClient(runner).GetVolumeStats(t.Context(), defaultSecrets)
+		assert.ErrorContains(t, err, "Cannot parse pancli response")
+	})
+}
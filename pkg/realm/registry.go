@@ -0,0 +1,199 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package realm provides the registry of the PanFS realms served by the driver, so storage
+// classes can select a realm by name instead of carrying its connection settings in their
+// secrets.
+package realm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"sort"
+
+	"github.com/panasasinc/panfs-container-storage-interface-oss/pkg/utils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+)
+
+// SecretKey is the key of the registry configuration in a Kubernetes Secret.
+const SecretKey = "realms.yaml"
+
+// ErrUnknownRealm is returned by Registry.Get for realms which are not registered.
+var ErrUnknownRealm = errors.New("unknown realm")
+
+// Realm is a realm registered by name.
+type Realm struct {
+	// Name is the name storage classes select the realm with.
+	Name string `json:"name"`
+	// Secrets are the connection settings of the realm, with the keys of the realm secret,
+	// see utils.RealmConnectionContext, e.g. realm_ip, user and password, or a
+	// credentials_handle resolved by the credential provider of the driver.
+	Secrets map[string]string `json:"secrets"`
+}
+
+// config is the registry configuration file.
+type config struct {
+	Realms []Realm `json:"realms"`
+}
+
+// Registry holds the connection settings of several realms by name. Registry is read-only
+// once loaded and safe for concurrent use.
+type Registry struct {
+	realms map[string]Realm
+}
+
+// Parse reads a registry configuration in YAML or JSON format, e.g.:
+//
+//	realms:
+//	- name: realm-a
+//	  secrets:
+//	    realm_ip: realm-a.example.com
+//	    user: admin
+//	    password: secret
+//
+// Parameters:
+//
+//	data - The configuration.
+//
+// Returns:
+//
+//	*Registry - The registry.
+//	error     - Error if the configuration cannot be parsed or a realm is invalid.
+func Parse(data []byte) (*Registry, error) {
+	var cfg config
+	if err := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096).Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse realm registry: %w", err)
+	}
+
+	r := &Registry{realms: make(map[string]Realm, len(cfg.Realms))}
+	for i, realm := range cfg.Realms {
+		if errs := validation.IsDNS1123Label(realm.Name); len(errs) != 0 {
+			return nil, fmt.Errorf("invalid name %q of realm %d: %v", realm.Name, i+1, errs)
+		}
+		if _, ok := r.realms[realm.Name]; ok {
+			return nil, fmt.Errorf("realm %q is registered twice", realm.Name)
+		}
+		if err := validateSecrets(realm.Secrets); err != nil {
+			return nil, fmt.Errorf("invalid secrets of realm %q: %w", realm.Name, err)
+		}
+		r.realms[realm.Name] = realm
+	}
+	return r, nil
+}
+
+// validateSecrets checks the connection settings of a realm. Settings referencing a
+// credential handle are resolved and validated per request.
+func validateSecrets(secrets map[string]string) error {
+	if secrets[utils.RealmConnectionContext.CredentialsHandle] != "" {
+		return nil
+	}
+	_, err := utils.ParseRealmSecrets(secrets)
+	return err
+}
+
+// LoadFile reads a registry configuration file, e.g. mounted from a Secret.
+//
+// Parameters:
+//
+//	path - The path of the file.
+//
+// Returns:
+//
+//	*Registry - The registry.
+//	error     - Error if the file cannot be read or parsed.
+func LoadFile(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// LoadSecret reads the registry configuration from the SecretKey key of a Kubernetes Secret.
+//
+// Parameters:
+//
+//	ctx       - The context of the request.
+//	client    - The Kubernetes client.
+//	namespace - The namespace of the Secret.
+//	name      - The name of the Secret.
+//
+// Returns:
+//
+//	*Registry - The registry.
+//	error     - Error if the Secret cannot be read or its configuration cannot be parsed.
+func LoadSecret(ctx context.Context, client kubernetes.Interface, namespace, name string) (*Registry, error) {
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("realm registry secret %s/%s not found", namespace, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read realm registry secret %s/%s: %w", namespace, name, err)
+	}
+
+	data, ok := secret.Data[SecretKey]
+	if !ok {
+		return nil, fmt.Errorf("realm registry secret %s/%s has no %s key", namespace, name, SecretKey)
+	}
+	r, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("secret %s/%s: %w", namespace, name, err)
+	}
+	return r, nil
+}
+
+// Get returns a registered realm.
+//
+// Parameters:
+//
+//	name - The name of the realm.
+//
+// Returns:
+//
+//	Realm - The realm with a copy of its connection settings.
+//	error - ErrUnknownRealm if no realm is registered with the name.
+func (r *Registry) Get(name string) (Realm, error) {
+	realm, ok := r.realms[name]
+	if !ok {
+		return Realm{}, fmt.Errorf("%w %q", ErrUnknownRealm, name)
+	}
+	realm.Secrets = maps.Clone(realm.Secrets)
+	return realm, nil
+}
+
+// Names returns the names of the registered realms.
+//
+// Returns:
+//
+//	[]string - The sorted names.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.realms))
+	for name := range r.realms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
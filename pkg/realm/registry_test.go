@@ -0,0 +1,109 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testConfig = `
+realms:
+- name: realm-a
+  secrets:
+    realm_ip: realm-a.example.com
+    user: admin
+    password: secret
+- name: realm-b
+  secrets:
+    credentials_handle: panfs/realm-b
+`
+
+func TestParse(t *testing.T) {
+	r, err := Parse([]byte(testConfig))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"realm-a", "realm-b"}, r.Names())
+
+	realm, err := r.Get("realm-a")
+	require.NoError(t, err)
+	assert.Equal(t, "realm-a.example.com", realm.Secrets["realm_ip"])
+
+	// the settings of the registry are not changed through returned realms
+	realm.Secrets["realm_ip"] = "other"
+	realm, _ = r.Get("realm-a")
+	assert.Equal(t, "realm-a.example.com", realm.Secrets["realm_ip"])
+
+	_, err = r.Get("realm-c")
+	assert.ErrorIs(t, err, ErrUnknownRealm)
+
+	// JSON is accepted as well
+	r, err = Parse([]byte(`{"realms": [{"name": "realm-a", "secrets": {"realm_ip": "10.0.0.1", "user": "admin", "api_token": "token"}}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"realm-a"}, r.Names())
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := map[string]string{
+		"InvalidName":    `{"realms": [{"name": "Realm_A", "secrets": {"credentials_handle": "a"}}]}`,
+		"Duplicate":      `{"realms": [{"name": "a", "secrets": {"credentials_handle": "a"}}, {"name": "a", "secrets": {"credentials_handle": "b"}}]}`,
+		"MissingRealmIP": `{"realms": [{"name": "a", "secrets": {"user": "admin", "password": "secret"}}]}`,
+		"Malformed":      `realms: [`,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SecretKey)
+	require.NoError(t, os.WriteFile(path, []byte(testConfig), 0o600))
+
+	r, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Len(t, r.Names(), 2)
+
+	_, err = LoadFile(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLoadSecret(t *testing.T) {
+	client := fake.NewClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "realms", Namespace: "csi-panfs"},
+			Data:       map[string][]byte{SecretKey: []byte(testConfig)},
+		},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: "csi-panfs"}},
+	)
+
+	r, err := LoadSecret(t.Context(), client, "csi-panfs", "realms")
+	require.NoError(t, err)
+	assert.Len(t, r.Names(), 2)
+
+	_, err = LoadSecret(t.Context(), client, "csi-panfs", "empty")
+	assert.ErrorContains(t, err, "has no realms.yaml key")
+
+	_, err = LoadSecret(t.Context(), client, "csi-panfs", "missing")
+	assert.ErrorContains(t, err, "not found")
+}
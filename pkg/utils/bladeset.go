@@ -0,0 +1,61 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import "encoding/xml"
+
+// BladesetList represents the XML structure returned by the `pasxml bladesets` command.
+type BladesetList struct {
+	XMLName   xml.Name           `xml:"pasxml"`
+	Version   string             `xml:"version,attr"`
+	Bladesets []BladesetCapacity `xml:"bladesets>bladeset"`
+}
+
+// BladesetCapacity represents the capacity of a single bladeset in the PanFS system.
+type BladesetCapacity struct {
+	XMLName     xml.Name `xml:"bladeset"`
+	ID          string   `xml:"id,attr"`
+	Name        string   `xml:"name"`
+	TotalGB     float64  `xml:"totalGB"`
+	AvailableGB float64  `xml:"availableGB"`
+
+	// QuotaUnit is the unit of TotalGB and AvailableGB as reported by the realm, GiB if not set.
+	QuotaUnit QuotaUnit `xml:"-"`
+}
+
+// GetAvailableBytes returns the free space of the bladeset in bytes.
+func (b *BladesetCapacity) GetAvailableBytes() int64 {
+	return b.QuotaUnit.ToBytes(b.AvailableGB)
+}
+
+// ParseListBladesets parses the XML output of the `pasxml bladesets` command.
+//
+// Parameters:
+//
+//	bladesets - The XML output of the command.
+//
+// Returns:
+//
+//	*BladesetList - The parsed bladeset list.
+//	error         - Error if the output cannot be parsed.
+func ParseListBladesets(bladesets []byte) (*BladesetList, error) {
+	var res BladesetList
+
+	err := UnmarshalPasxml(bladesets, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListBladesets(t *testing.T) {
+	out := []byte(`<pasxml version="6.0.0">
+    <bladesets>
+        <bladeset id="1">
+            <name>Set 1</name>
+            <totalGB>1024.00</totalGB>
+            <availableGB>512.50</availableGB>
+        </bladeset>
+    </bladesets>
+</pasxml>`)
+
+	list, err := ParseListBladesets(out)
+	require.NoError(t, err)
+	require.Len(t, list.Bladesets, 1)
+	bladeset := list.Bladesets[0]
+	assert.Equal(t, "Set 1", bladeset.Name)
+	assert.Equal(t, int64(512.5*(1<<30)), bladeset.GetAvailableBytes())
+
+	bladeset.QuotaUnit = QuotaUnitGB
+	assert.Equal(t, int64(512.5e9), bladeset.GetAvailableBytes())
+
+	_, err = ParseListBladesets([]byte("not xml"))
+	assert.Error(t, err)
+}
@@ -44,8 +44,33 @@ var VolumeParameters = VolumeParametersData{
 	"encryption":  "encryption %s",
 	"soft":        "soft %v", // softQuotaGB
 	"hard":        "hard %v", // hardQuotaGB
+
+	// driver-only parameters, not passed to pancli
+	"tolerateMissingHardQuota": "",
+	"profile":                  "", // mount profile
+	"cacheMode":                "", // node-local cache of the PanFS client, see driver.CacheModeNone
+	"verifyMount":              "", // IO verification after publish, see driver.WithMountVerification
+	"targetDirMode":            "", // mode of created target directories, see driver.WithTargetDirPermissions
+	"targetDirUID":             "", // owner of created target directories
+	"targetDirGID":             "", // group of created target directories
+	"reconcileCapacity":        "", // reconciliation of existing volumes, see driver.ReconcileCapacityExpand
+	"minCapacity":              "", // minimum volume size of the storage class
+	"roundUpCapacity":          "", // round requests below the minimum volume size up to it
+	"credentials":              "", // handle of the realm credentials, see driver.WithCredentialProvider
+	"protectionTier":           "", // protection tier tagged in the volume description, see ProtectionTierTagPrefix
+	"parentVolume":             "", // volume holding the volumes provisioned as directories, see MakeDirectoryVolumeID
+	"realm":                    "", // name of the realm in the realm registry, see driver.WithRealmRegistry
+	"truncateDescription":      "", // truncation of descriptions exceeding the realm limit, see driver.StringParameterLimits
 }
 
+// HardQuotaDegradedContextKey is the volume context key set when a volume was created
+// without its hard quota because the realm does not support it.
+const HardQuotaDegradedContextKey = VendorPrefix + "hardQuotaDegraded"
+
+// VolumeNameContextKey is the volume context key holding the name of the CreateVolume
+// request of volumes whose volume id is a mapped name, see driver.VolumeNameMapper.
+const VolumeNameContextKey = VendorPrefix + "volumeName"
+
 // GetSCKey retrieves the storage class parameter key for a given context parameter key
 func (c VolumeParametersData) GetSCKey(k string) string {
 	short := strings.TrimPrefix(k, VendorPrefix)
@@ -72,12 +97,34 @@ var RealmConnectionContext = struct {
 	Password             string
 	PrivateKey           string
 	PrivateKeyPassphrase string
+	APIToken             string
+	HostKey              string
 	KMIPConfigData       string
+	KMIPCABundle         string
+	KMIPClientCert       string
+	KMIPClientKey        string
+	SerializeOperations  string
+	CompressOutput       string
+	QuotaUnit            string
+	CredentialsHandle    string
+	RealmName            string
+	RealmVersion         string
 }{
 	RealmAddress:         "realm_ip",
 	Username:             "user",
 	Password:             "password",
 	PrivateKey:           "private_key",
 	PrivateKeyPassphrase: "private_key_passphrase",
+	APIToken:             "api_token",
+	HostKey:              "host_key",
 	KMIPConfigData:       "kmip_config_data",
+	KMIPCABundle:         "kmip_ca_bundle",
+	KMIPClientCert:       "kmip_client_cert",
+	KMIPClientKey:        "kmip_client_key",
+	SerializeOperations:  "serializeOperations",
+	CompressOutput:       "compressOutput",
+	QuotaUnit:            "quotaUnit",
+	CredentialsHandle:    "credentials_handle",
+	RealmName:            "realm_name",
+	RealmVersion:         "realmVersion",
 }
@@ -0,0 +1,127 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// directoryIDSeparator separates the parent volume and directory names in the ID of a
+// directory volume. The ID is the path of the directory in the realm, so the node plugin
+// mounts it like a volume.
+const directoryIDSeparator = "/"
+
+// DirectoryList represents the XML structure returned by the `pasxml directories` command.
+type DirectoryList struct {
+	XMLName     xml.Name    `xml:"pasxml"`
+	Version     string      `xml:"version,attr"`
+	Directories []Directory `xml:"directories>directory"`
+}
+
+// Directory represents a directory with a quota in a PanFS volume, provisioned as a volume of
+// its own in the subdirectory mode.
+type Directory struct {
+	XMLName    xml.Name   `xml:"directory"`
+	Name       string     `xml:"name"`
+	VolumeName VolumeName `xml:"volumeName"`
+	Hard       float64    `xml:"hardQuotaGB"`
+
+	// QuotaUnit is the unit of Hard as reported by the realm, GiB if not set.
+	QuotaUnit QuotaUnit `xml:"-"`
+}
+
+// GetHardQuotaBytes returns the directory quota in bytes.
+func (d *Directory) GetHardQuotaBytes() int64 {
+	return d.QuotaUnit.ToBytes(d.Hard)
+}
+
+// VolumeID returns the CSI volume ID of the directory.
+func (d *Directory) VolumeID() string {
+	return MakeDirectoryVolumeID(string(d.VolumeName), d.Name)
+}
+
+// MakeDirectoryVolumeID builds the CSI volume ID of a directory volume.
+//
+// Parameters:
+//
+//	parentVolume - The name of the volume holding the directory.
+//	directory    - The name of the directory.
+//
+// Returns:
+//
+//	string - The volume ID, "<parent volume>/<directory>".
+func MakeDirectoryVolumeID(parentVolume, directory string) string {
+	return parentVolume + directoryIDSeparator + directory
+}
+
+// ParseDirectoryVolumeID splits the CSI volume ID of a directory volume into the parent volume
+// and directory names. IDs of realm volumes and absolute paths, e.g. the volume handles of
+// statically provisioned volumes, are not directory volume IDs.
+//
+// Parameters:
+//
+//	id - The volume ID.
+//
+// Returns:
+//
+//	string - The name of the parent volume.
+//	string - The name of the directory.
+//	bool   - True if the ID is the ID of a directory volume.
+func ParseDirectoryVolumeID(id string) (string, string, bool) {
+	parentVolume, directory, ok := strings.Cut(id, directoryIDSeparator)
+	if !ok || parentVolume == "" || directory == "" || strings.Contains(directory, directoryIDSeparator) {
+		return "", "", false
+	}
+	return parentVolume, directory, true
+}
+
+// ValidateDirectoryName checks that a name is a valid name of a directory volume, i.e. a
+// single path element.
+//
+// Parameters:
+//
+//	name - The name of the directory.
+//
+// Returns:
+//
+//	error - Error if the name is empty, "." or "..", or contains a path separator.
+func ValidateDirectoryName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.Contains(name, directoryIDSeparator) {
+		return fmt.Errorf("invalid directory name %q: must be a single path element", name)
+	}
+	return nil
+}
+
+// ParseListDirectories parses the XML output from the `pasxml directories` command.
+//
+// Parameters:
+//
+//	directories - The XML byte slice containing the directory list.
+//
+// Returns:
+//
+//	*DirectoryList - The parsed DirectoryList structure.
+//	error          - Error if parsing fails.
+func ParseListDirectories(directories []byte) (*DirectoryList, error) {
+	var res DirectoryList
+
+	err := UnmarshalPasxml(directories, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
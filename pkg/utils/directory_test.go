@@ -0,0 +1,69 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectoryVolumeID(t *testing.T) {
+	id := MakeDirectoryVolumeID("shared", "pvc-1")
+	assert.Equal(t, "shared/pvc-1", id)
+
+	parentVolume, directory, ok := ParseDirectoryVolumeID(id)
+	assert.True(t, ok)
+	assert.Equal(t, "shared", parentVolume)
+	assert.Equal(t, "pvc-1", directory)
+
+	for _, other := range []string{"", "pvc-1", "/static-volume", "/static-volume/dir1", "shared/", "shared/a/b"} {
+		_, _, ok := ParseDirectoryVolumeID(other)
+		assert.False(t, ok, other)
+	}
+}
+
+func TestValidateDirectoryName(t *testing.T) {
+	assert.NoError(t, ValidateDirectoryName("pvc-1"))
+	for _, invalid := range []string{"", ".", "..", "a/b"} {
+		assert.Error(t, ValidateDirectoryName(invalid), invalid)
+	}
+}
+
+func TestParseListDirectories(t *testing.T) {
+	out := []byte(`<pasxml version="6.0.0">
+    <directories>
+        <directory>
+            <name>pvc-1</name>
+            <volumeName>/shared</volumeName>
+            <hardQuotaGB>2.5</hardQuotaGB>
+        </directory>
+    </directories>
+</pasxml>`)
+
+	list, err := ParseListDirectories(out)
+	require.NoError(t, err)
+	require.Len(t, list.Directories, 1)
+	directory := list.Directories[0]
+	assert.Equal(t, "shared/pvc-1", directory.VolumeID())
+	assert.Equal(t, int64(2.5*(1<<30)), directory.GetHardQuotaBytes())
+
+	directory.QuotaUnit = QuotaUnitGB
+	assert.Equal(t, int64(2.5e9), directory.GetHardQuotaBytes())
+
+	_, err = ParseListDirectories([]byte("not xml"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+// EFSAMode is the Extended File System Availability mode of a PanFS volume. It selects how
+// clients react to files which are temporarily unavailable, e.g. during a failover.
+type EFSAMode string
+
+// EFSA modes accepted by the efsa volume parameter.
+const (
+	// EFSAModeRetry makes clients retry IO until the file is available again.
+	EFSAModeRetry EFSAMode = "retry"
+	// EFSAModeFileUnavailable makes IO on unavailable files fail with an error.
+	EFSAModeFileUnavailable EFSAMode = "file-unavailable"
+)
+
+// EFSAModes lists all EFSA modes accepted by the efsa volume parameter.
+var EFSAModes = []EFSAMode{
+	EFSAModeRetry,
+	EFSAModeFileUnavailable,
+}
+
+// IsValid reports whether the mode is one of EFSAModes.
+func (m EFSAMode) IsValid() bool {
+	for _, mode := range EFSAModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
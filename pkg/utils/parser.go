@@ -15,7 +15,9 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/xml"
+	"fmt"
 	"strings"
 )
 
@@ -56,24 +58,32 @@ type Bladeset struct {
 
 // Volume represents a single volume in the PanFS system.
 type Volume struct {
-	XMLName    xml.Name   `xml:"volume"`
-	ID         string     `xml:"id,attr"`
-	Name       VolumeName `xml:"name"`
-	State      string     `xml:"state"`
-	Soft       float64    `xml:"softQuotaGB"`
-	Hard       float64    `xml:"hardQuotaGB"`
-	Bset       Bladeset   `xml:"bladesetName"`
-	Encryption string     `xml:"encryption"`
+	XMLName    xml.Name    `xml:"volume"`
+	ID         string      `xml:"id,attr"`
+	Name       VolumeName  `xml:"name"`
+	State      VolumeState `xml:"state"`
+	Soft       float64     `xml:"softQuotaGB"`
+	Hard       float64     `xml:"hardQuotaGB"`
+	Bset       Bladeset    `xml:"bladesetName"`
+	Encryption string      `xml:"encryption"`
+	// Description is the description of the volume, including the idempotency token of the
+	// request which created it and its protection tier, if any.
+	Description string `xml:"description,omitempty"`
+
+	// HardQuotaDegraded is set when the volume was created without the requested hard quota.
+	HardQuotaDegraded bool `xml:"-"`
+	// QuotaUnit is the unit of Soft and Hard as reported by the realm, GiB if not set.
+	QuotaUnit QuotaUnit `xml:"-"`
 }
 
 // GetSoftQuotaBytes returns the soft quota in bytes.
 func (v *Volume) GetSoftQuotaBytes() int64 {
-	return GBToBytes(v.Soft)
+	return v.QuotaUnit.ToBytes(v.Soft)
 }
 
 // GetHardQuotaBytes returns the hard quota in bytes.
 func (v *Volume) GetHardQuotaBytes() int64 {
-	return GBToBytes(v.Hard)
+	return v.QuotaUnit.ToBytes(v.Hard)
 }
 
 // GetEncryptionMode returns the encryption mode of the volume.
@@ -111,6 +121,12 @@ func (v *Volume) VolumeContext() map[string]string {
 	if v.Encryption != "" {
 		params[VolumeParameters.GetSCKey("encryption")] = v.GetEncryptionMode()
 	}
+	if v.HardQuotaDegraded {
+		params[HardQuotaDegradedContextKey] = "true"
+	}
+	if tier := ProtectionTier(v.Description); tier != "" {
+		params[VolumeParameters.GetSCKey("protectionTier")] = tier
+	}
 	return params
 }
 
@@ -127,9 +143,98 @@ func (v *Volume) VolumeContext() map[string]string {
 func ParseListVolumes(volumes []byte) (*VolumeList, error) {
 	var res VolumeList
 
-	err := xml.Unmarshal(volumes, &res)
+	err := UnmarshalPasxml(volumes, &res)
 	if err != nil {
 		return nil, err
 	}
 	return &res, nil
 }
+
+// maxNoiseInError bounds the unexpected realm output quoted in parse errors.
+const maxNoiseInError = 200
+
+// UnmarshalPasxml decodes the pasxml document in the output of a realm command. Text before
+// and after the document, e.g. a login banner or message of the day printed by the realm, is
+// ignored.
+//
+// Parameters:
+//
+//	output - The output of the command.
+//	v      - Pointer to the value to decode into.
+//
+// Returns:
+//
+//	error - Error if the output has no pasxml document or the document cannot be decoded,
+//	        quoting the unexpected output before the document.
+func UnmarshalPasxml(output []byte, v any) error {
+	document, prefix := extractPasxml(output)
+	if document == nil {
+		if noise := strings.TrimSpace(string(output)); noise != "" {
+			return fmt.Errorf("no pasxml document in realm output %q", truncateNoise(noise))
+		}
+		return xml.Unmarshal(output, v)
+	}
+
+	if err := xml.Unmarshal(document, v); err != nil {
+		if noise := strings.TrimSpace(string(prefix)); noise != "" {
+			return fmt.Errorf("%w (unexpected realm output before the pasxml document: %q)", err, truncateNoise(noise))
+		}
+		return err
+	}
+	return nil
+}
+
+// TrimPasxmlNoise removes text around a complete pasxml document in the output of a realm
+// command, e.g. a login banner, so it is not mistaken for an error message of the realm.
+//
+// Parameters:
+//
+//	output - The output of the command.
+//
+// Returns:
+//
+//	[]byte - The pasxml document, or the output unchanged if it has no complete document.
+func TrimPasxmlNoise(output []byte) []byte {
+	document, _ := extractPasxml(output)
+	if document == nil || !bytes.HasSuffix(document, []byte("</pasxml>")) {
+		return output
+	}
+	return document
+}
+
+// extractPasxml locates the pasxml document in the output of a realm command.
+//
+// Parameters:
+//
+//	output - The output of the command.
+//
+// Returns:
+//
+//	[]byte - The document from its XML declaration or root element to the last closing
+//	         root tag, or to the end of the output if the closing tag is missing. Nil if the
+//	         output has no pasxml root element.
+//	[]byte - The output before the document.
+func extractPasxml(output []byte) ([]byte, []byte) {
+	start := bytes.Index(output, []byte("<pasxml"))
+	if start < 0 {
+		return nil, output
+	}
+	// keep the XML declaration of the document, if any
+	if decl := bytes.LastIndex(output[:start], []byte("<?xml")); decl >= 0 {
+		start = decl
+	}
+
+	end := len(output)
+	if closing := bytes.LastIndex(output[start:], []byte("</pasxml>")); closing >= 0 {
+		end = start + closing + len("</pasxml>")
+	}
+	return output[start:end], output[:start]
+}
+
+// truncateNoise shortens unexpected realm output for error messages.
+func truncateNoise(noise string) string {
+	if len(noise) > maxNoiseInError {
+		return noise[:maxNoiseInError] + "..."
+	}
+	return noise
+}
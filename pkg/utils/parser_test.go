@@ -0,0 +1,59 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseListVolumesWithNoise verifies that login banners and other text around the
+// pasxml document are ignored, and reported when the output cannot be parsed.
+func TestParseListVolumesWithNoise(t *testing.T) {
+	document := `<pasxml version="6.0.0"><volumes><volume id="1"><name>/home</name></volume></volumes></pasxml>`
+
+	tests := []struct {
+		name    string
+		output  string
+		wantErr string
+	}{
+		{name: "Plain", output: document},
+		{name: "Banner", output: "*** Authorized <users> only & monitored ***\n" + document},
+		{name: "Declaration", output: "Last login: today\n<?xml version=\"1.0\"?>\n" + document},
+		{name: "TrailingNoise", output: document + "\nlogout <bye>"},
+		{name: "MissingClosingTag", output: "Welcome\n" + `<pasxml version="6.0.0"><volumes></volumes>`, wantErr: `unexpected realm output before the pasxml document: "Welcome"`},
+		{name: "NoDocument", output: "Welcome to the realm\n", wantErr: `no pasxml document in realm output "Welcome to the realm"`},
+		{name: "LongNoise", output: strings.Repeat("x", 500), wantErr: strings.Repeat("x", maxNoiseInError) + `..."`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			list, err := ParseListVolumes([]byte(tc.output))
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, list.Volumes, 1)
+			assert.Equal(t, VolumeName("home"), list.Volumes[0].Name)
+		})
+	}
+
+	_, err := ParseListVolumes(nil)
+	assert.Error(t, err)
+}
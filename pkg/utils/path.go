@@ -0,0 +1,109 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ErrEmptyPath is returned by ValidateAbsolutePath for empty paths.
+var ErrEmptyPath = errors.New("path must not be empty")
+
+// CleanPath returns the shortest equivalent form of a node path, e.g. a target or staging
+// path passed by kubelet, with the separators of the platform. Trailing separators and
+// "." and ".." elements are removed.
+//
+// Parameters:
+//
+//	path - The path, with "/" or platform separators.
+//
+// Returns:
+//
+//	string - The cleaned path, empty for an empty path.
+func CleanPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	return filepath.Clean(filepath.FromSlash(path))
+}
+
+// ValidateAbsolutePath checks that a node path is an absolute path and returns it cleaned.
+//
+// Parameters:
+//
+//	path - The path to validate.
+//
+// Returns:
+//
+//	string - The cleaned path.
+//	error  - ErrEmptyPath for empty paths, or an error if the path is relative or contains
+//	         a NUL character.
+func ValidateAbsolutePath(path string) (string, error) {
+	if path == "" {
+		return "", ErrEmptyPath
+	}
+	if strings.ContainsRune(path, 0) {
+		return "", fmt.Errorf("invalid path %q: must not contain NUL characters", path)
+	}
+	cleaned := CleanPath(path)
+	if !filepath.IsAbs(cleaned) {
+		return "", fmt.Errorf("invalid path %q: must be an absolute path", path)
+	}
+	return cleaned, nil
+}
+
+// PathWithin reports whether a path is the base path or lies below it, comparing the
+// cleaned paths element by element, so "/a/bc" is not within "/a/b".
+//
+// Parameters:
+//
+//	base - The base path.
+//	path - The path to check.
+//
+// Returns:
+//
+//	bool - True if the path is the base path or below it.
+func PathWithin(base, path string) bool {
+	rel, err := filepath.Rel(CleanPath(base), CleanPath(path))
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// SamePath reports whether two paths refer to the same location, e.g. a target path and the
+// path of an existing mount point. The cleaned paths are compared first; existing paths are
+// also compared with symbolic links resolved.
+//
+// Parameters:
+//
+//	a - The first path.
+//	b - The second path.
+//
+// Returns:
+//
+//	bool - True if the paths refer to the same location.
+func SamePath(a, b string) bool {
+	a, b = CleanPath(a), CleanPath(b)
+	if a == b {
+		return true
+	}
+	resolvedA, errA := filepath.EvalSymlinks(a)
+	resolvedB, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && resolvedA == resolvedB
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAbsolutePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr string
+	}{
+		{name: "Clean", path: "/var/lib/kubelet/pods/uid/volumes/mount", want: "/var/lib/kubelet/pods/uid/volumes/mount"},
+		{name: "TrailingSeparator", path: "/var/lib/kubelet/plugins/staging/", want: "/var/lib/kubelet/plugins/staging"},
+		{name: "DotElements", path: "/var/lib//kubelet/./pods/../plugins", want: "/var/lib/kubelet/plugins"},
+		{name: "Empty", path: "", wantErr: ErrEmptyPath.Error()},
+		{name: "Relative", path: "var/lib/kubelet", wantErr: "must be an absolute path"},
+		{name: "NUL", path: "/var/lib\x00/kubelet", wantErr: "must not contain NUL characters"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ValidateAbsolutePath(tc.path)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestPathWithin(t *testing.T) {
+	assert.True(t, PathWithin("/var/lib/kubelet", "/var/lib/kubelet"))
+	assert.True(t, PathWithin("/var/lib/kubelet/", "/var/lib/kubelet/pods/uid"))
+	assert.False(t, PathWithin("/var/lib/kubelet", "/var/lib/kubelet-other"))
+	assert.False(t, PathWithin("/var/lib/kubelet", "/var/lib/kubelet/pods/../../other"))
+	assert.False(t, PathWithin("/var/lib/kubelet", "/var/lib"))
+}
+
+func TestSamePath(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	require.NoError(t, os.Mkdir(target, 0o755))
+	link := filepath.Join(dir, "link")
+	require.NoError(t, os.Symlink(target, link))
+
+	assert.True(t, SamePath(target, target+"/"))
+	assert.True(t, SamePath(target, filepath.Join(dir, "other", "..", "target")))
+	assert.True(t, SamePath(target, link))
+	assert.False(t, SamePath(target, filepath.Join(dir, "missing")))
+	assert.False(t, SamePath("/missing/a", "/missing/b"))
+}
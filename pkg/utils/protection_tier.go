@@ -0,0 +1,67 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"strings"
+)
+
+// ProtectionTierTagPrefix prefixes the protection tier appended to the description of
+// volumes created with the protectionTier parameter, e.g. "csi-tier:gold". The tier is kept
+// by the realm, so replication tooling can select volumes by tier without Kubernetes access.
+const ProtectionTierTagPrefix = "csi-tier:"
+
+// ProtectionTier returns the protection tier tagged in a volume description.
+//
+// Parameters:
+//
+//	description - The description of the volume.
+//
+// Returns:
+//
+//	string - The protection tier, empty if the description is not tagged.
+func ProtectionTier(description string) string {
+	for _, field := range strings.Fields(description) {
+		if tier, ok := strings.CutPrefix(field, ProtectionTierTagPrefix); ok {
+			return tier
+		}
+	}
+	return ""
+}
+
+// TagProtectionTier returns the description with the protection tier tagged, replacing the
+// tier it was tagged with before.
+//
+// Parameters:
+//
+//	description - The description of the volume.
+//	tier        - The protection tier, an empty tier removes the tag.
+//
+// Returns:
+//
+//	string - The tagged description.
+func TagProtectionTier(description, tier string) string {
+	fields := strings.Fields(description)
+	tagged := make([]string, 0, len(fields)+1)
+	for _, field := range fields {
+		if !strings.HasPrefix(field, ProtectionTierTagPrefix) {
+			tagged = append(tagged, field)
+		}
+	}
+	if tier != "" {
+		tagged = append(tagged, ProtectionTierTagPrefix+tier)
+	}
+	return strings.Join(tagged, " ")
+}
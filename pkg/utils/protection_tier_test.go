@@ -0,0 +1,34 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtectionTier(t *testing.T) {
+	assert.Equal(t, "gold", ProtectionTier("team data csi-tier:gold csi-token:3f9c2a7d51e08b46"))
+	assert.Equal(t, "", ProtectionTier("team data"))
+	assert.Equal(t, "", ProtectionTier(""))
+
+	assert.Equal(t, "team data csi-tier:gold", TagProtectionTier("team data", "gold"))
+	assert.Equal(t, "csi-tier:silver", TagProtectionTier("csi-tier:gold", "silver"))
+	assert.Equal(t, "team data", TagProtectionTier("team csi-tier:gold data", ""))
+
+	vol := Volume{Description: "csi-tier:gold"}
+	assert.Equal(t, map[string]string{VolumeParameters.GetSCKey("protectionTier"): "gold"}, vol.VolumeContext())
+}
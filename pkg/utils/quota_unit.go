@@ -0,0 +1,72 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import "fmt"
+
+// QuotaUnit is the unit a realm expects and reports volume quotas in. Realm versions differ
+// in whether a quota of 1 means 1 GiB or 1 GB, which is a difference of about 7%.
+type QuotaUnit string
+
+// Quota units supported by the driver.
+const (
+	// QuotaUnitGiB is 2^30 bytes, the unit of most realms and the default.
+	QuotaUnitGiB QuotaUnit = "GiB"
+	// QuotaUnitGB is 10^9 bytes.
+	QuotaUnitGB QuotaUnit = "GB"
+)
+
+// QuotaUnits lists all supported quota units.
+var QuotaUnits = []QuotaUnit{QuotaUnitGiB, QuotaUnitGB}
+
+// ParseQuotaUnit parses the quota unit of a realm. An empty string is QuotaUnitGiB.
+//
+// Parameters:
+//
+//	s - The quota unit, e.g. "GB".
+//
+// Returns:
+//
+//	QuotaUnit - The quota unit.
+//	error     - Error if the unit is not supported.
+func ParseQuotaUnit(s string) (QuotaUnit, error) {
+	if s == "" {
+		return QuotaUnitGiB, nil
+	}
+	for _, unit := range QuotaUnits {
+		if QuotaUnit(s) == unit {
+			return unit, nil
+		}
+	}
+	return "", fmt.Errorf("quota unit %q must be one of: %v", s, QuotaUnits)
+}
+
+// bytes returns the number of bytes per unit. The zero value is QuotaUnitGiB.
+func (u QuotaUnit) bytes() float64 {
+	if u == QuotaUnitGB {
+		return 1e9
+	}
+	return bytesPerGB
+}
+
+// FromBytes converts bytes to the unit.
+func (u QuotaUnit) FromBytes(in int64) float64 {
+	return float64(in) / u.bytes()
+}
+
+// ToBytes converts a size in the unit to bytes.
+func (u QuotaUnit) ToBytes(in float64) int64 {
+	return int64(in * u.bytes())
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuotaUnit(t *testing.T) {
+	unit, err := ParseQuotaUnit("")
+	assert.NoError(t, err)
+	assert.Equal(t, QuotaUnitGiB, unit)
+
+	unit, err = ParseQuotaUnit("GB")
+	assert.NoError(t, err)
+	assert.Equal(t, QuotaUnitGB, unit)
+
+	_, err = ParseQuotaUnit("gb")
+	assert.EqualError(t, err, `quota unit "gb" must be one of: [GiB GB]`)
+}
+
+func TestQuotaUnitConversion(t *testing.T) {
+	assert.Equal(t, float64(1), QuotaUnitGiB.FromBytes(1073741824))
+	assert.Equal(t, float64(1), QuotaUnitGB.FromBytes(1000000000))
+	assert.Equal(t, int64(2684354560), QuotaUnitGiB.ToBytes(2.5))
+	assert.Equal(t, int64(2500000000), QuotaUnitGB.ToBytes(2.5))
+
+	// the zero value is GiB
+	assert.Equal(t, int64(1073741824), QuotaUnit("").ToBytes(1))
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// RealmSSHPort is the port of the realm SSH server.
+const RealmSSHPort = "22"
+
+// ParseRealmAddress validates a realm address and returns its host. The address may be
+// an IPv4 address, an IPv6 address with or without brackets, or a hostname.
+//
+// Parameters:
+//
+//	address - The realm address, e.g. "10.0.0.1", "[fd00::1]" or "realm.example.com".
+//
+// Returns:
+//
+//	string - The host without brackets.
+//	error  - Error if the address is empty or invalid.
+func ParseRealmAddress(address string) (string, error) {
+	host := strings.TrimSpace(address)
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		host = host[1 : len(host)-1]
+		if addr, err := netip.ParseAddr(host); err != nil || !addr.Is6() {
+			return "", fmt.Errorf("invalid realm address %q: brackets are only allowed around IPv6 addresses", address)
+		}
+	}
+	if host == "" {
+		return "", fmt.Errorf("realm address must not be empty")
+	}
+
+	if _, err := netip.ParseAddr(host); err == nil {
+		return host, nil
+	}
+	if errs := validation.IsDNS1123Subdomain(strings.ToLower(host)); len(errs) != 0 {
+		return "", fmt.Errorf("invalid realm address %q: %s", address, strings.Join(errs, ", "))
+	}
+	return host, nil
+}
+
+// ParseRealmAddresses validates a comma-separated list of realm addresses, e.g. the
+// addresses of several directors of the same realm.
+//
+// Parameters:
+//
+//	addresses - The realm addresses, e.g. "10.0.0.1,10.0.0.2".
+//
+// Returns:
+//
+//	[]string - The trimmed addresses in the configured order.
+//	error    - Error if the list is empty or any of the addresses is invalid.
+func ParseRealmAddresses(addresses string) ([]string, error) {
+	var list []string
+	for _, address := range strings.Split(addresses, ",") {
+		address = strings.TrimSpace(address)
+		if _, err := ParseRealmAddress(address); err != nil {
+			return nil, err
+		}
+		list = append(list, address)
+	}
+	return list, nil
+}
+
+// RealmSSHAddress returns the address of the realm SSH server to dial.
+//
+// Parameters:
+//
+//	address - The realm address.
+//
+// Returns:
+//
+//	string - The "host:port" address, with brackets around IPv6 hosts.
+//	error  - Error if the realm address is invalid.
+func RealmSSHAddress(address string) (string, error) {
+	host, err := ParseRealmAddress(address)
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, RealmSSHPort), nil
+}
+
+// RealmMountSource returns the PanFS mount source of a volume. For a list of realm
+// addresses the first one is used, see RealmMountSources to fall back to the others.
+//
+// Parameters:
+//
+//	address - The realm address or comma-separated list of addresses.
+//	volume  - The volume name.
+//
+// Returns:
+//
+//	string - The mount source "panfs://<realm>/<volume>", with brackets around IPv6 hosts.
+//	error  - Error if a realm address is invalid.
+func RealmMountSource(address, volume string) (string, error) {
+	sources, err := RealmMountSources(address, volume)
+	if err != nil {
+		return "", err
+	}
+	return sources[0], nil
+}
+
+// RealmMountSources returns the PanFS mount sources of a volume, one for each realm address
+// in the configured order.
+//
+// Parameters:
+//
+//	address - The realm address or comma-separated list of addresses.
+//	volume  - The volume name.
+//
+// Returns:
+//
+//	[]string - The mount sources "panfs://<realm>/<volume>", with brackets around IPv6 hosts.
+//	error    - Error if a realm address is invalid.
+func RealmMountSources(address, volume string) ([]string, error) {
+	addresses, err := ParseRealmAddresses(address)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		host, err := ParseRealmAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		sources = append(sources, fmt.Sprintf("panfs://%s/%s", host, volume))
+	}
+	return sources, nil
+}
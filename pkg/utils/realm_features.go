@@ -0,0 +1,43 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+// RealmFeatures are the optional features supported by a realm. Realm versions differ in
+// the commands they support, so the driver detects them instead of assuming them.
+type RealmFeatures struct {
+	// Snapshots reports support of volume snapshots, required for snapshots and clones.
+	Snapshots bool
+	// Capacity reports support of the bladeset capacity listing.
+	Capacity bool
+}
+
+// AllRealmFeatures is the feature set of a realm supporting all optional features.
+var AllRealmFeatures = RealmFeatures{Snapshots: true, Capacity: true}
+
+// Intersect returns the features supported by both realms.
+//
+// Parameters:
+//
+//	other - The features of the other realm.
+//
+// Returns:
+//
+//	RealmFeatures - The common features.
+func (f RealmFeatures) Intersect(other RealmFeatures) RealmFeatures {
+	return RealmFeatures{
+		Snapshots: f.Snapshots && other.Snapshots,
+		Capacity:  f.Capacity && other.Capacity,
+	}
+}
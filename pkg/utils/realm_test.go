@@ -0,0 +1,107 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRealmAddress(t *testing.T) {
+	tests := []struct {
+		name       string
+		address    string
+		wantSSH    string
+		wantSource string
+		wantErr    string
+	}{
+		{
+			name:       "IPv4",
+			address:    "10.0.0.1",
+			wantSSH:    "10.0.0.1:22",
+			wantSource: "panfs://10.0.0.1/vol",
+		},
+		{
+			name:       "IPv6",
+			address:    "fd00::1",
+			wantSSH:    "[fd00::1]:22",
+			wantSource: "panfs://[fd00::1]/vol",
+		},
+		{
+			name:       "BracketedIPv6",
+			address:    "[fd00::1]",
+			wantSSH:    "[fd00::1]:22",
+			wantSource: "panfs://[fd00::1]/vol",
+		},
+		{
+			name:       "Hostname",
+			address:    " Realm.example.com ",
+			wantSSH:    "Realm.example.com:22",
+			wantSource: "panfs://Realm.example.com/vol",
+		},
+		{
+			name:    "Empty",
+			address: "",
+			wantErr: "realm address must not be empty",
+		},
+		{
+			name:    "BracketedIPv4",
+			address: "[10.0.0.1]",
+			wantErr: "brackets are only allowed around IPv6 addresses",
+		},
+		{
+			name:    "WithPort",
+			address: "realm:2222",
+			wantErr: `invalid realm address "realm:2222"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ssh, err := RealmSSHAddress(tc.address)
+			source, sourceErr := RealmMountSource(tc.address, "vol")
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				assert.ErrorContains(t, sourceErr, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, sourceErr)
+			assert.Equal(t, tc.wantSSH, ssh)
+			assert.Equal(t, tc.wantSource, source)
+		})
+	}
+}
+
+func TestParseRealmAddresses(t *testing.T) {
+	addresses, err := ParseRealmAddresses("10.0.0.1, fd00::1 ,realm.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1", "fd00::1", "realm.example.com"}, addresses)
+
+	source, err := RealmMountSource("fd00::1,10.0.0.1", "vol")
+	assert.NoError(t, err)
+	assert.Equal(t, "panfs://[fd00::1]/vol", source)
+
+	sources, err := RealmMountSources("fd00::1,10.0.0.1", "vol")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"panfs://[fd00::1]/vol", "panfs://10.0.0.1/vol"}, sources)
+
+	_, err = ParseRealmAddresses("10.0.0.1,")
+	assert.EqualError(t, err, "realm address must not be empty")
+
+	_, err = ParseRealmAddresses("10.0.0.1,realm:2222")
+	assert.ErrorContains(t, err, `invalid realm address "realm:2222"`)
+}
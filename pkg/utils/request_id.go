@@ -0,0 +1,72 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"regexp"
+)
+
+// MaxRequestIDLength is the maximum length of request IDs accepted from clients.
+const MaxRequestIDLength = 64
+
+// validRequestID matches request IDs accepted from clients, which are logged as they are.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]+$`)
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of the context carrying the request ID, which is
+// logged as request_id by the driver, the realm clients and the mounter.
+//
+// Parameters:
+//
+//	ctx       - The parent context.
+//	requestID - The ID correlating the log lines of the request.
+//
+// Returns:
+//
+//	context.Context - The context with the request ID.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID of the context.
+//
+// Parameters:
+//
+//	ctx - The context of the request.
+//
+// Returns:
+//
+//	string - The request ID.
+//	bool   - False if the context has no request ID.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey{}).(string)
+	return requestID, ok
+}
+
+// ValidRequestID reports whether a request ID received from a client may be used, i.e. is
+// at most MaxRequestIDLength characters of letters, digits, '.', '_', ':' and '-'.
+//
+// Parameters:
+//
+//	requestID - The request ID.
+//
+// Returns:
+//
+//	bool - True if the request ID is valid.
+func ValidRequestID(requestID string) bool {
+	return len(requestID) <= MaxRequestIDLength && validRequestID.MatchString(requestID)
+}
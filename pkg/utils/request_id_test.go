@@ -0,0 +1,54 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDContext(t *testing.T) {
+	_, ok := RequestIDFromContext(context.Background())
+	assert.False(t, ok)
+
+	requestID, ok := RequestIDFromContext(ContextWithRequestID(context.Background(), "abc123"))
+	assert.True(t, ok)
+	assert.Equal(t, "abc123", requestID)
+}
+
+func TestValidRequestID(t *testing.T) {
+	tests := []struct {
+		requestID string
+		valid     bool
+	}{
+		{"0123456789abcdef", true},
+		{"6f1c2b0e-8f3a-4c1d-9b7e-2a5d4c3b1a0f", true},
+		{"job.42:attempt_1", true},
+		{strings.Repeat("a", MaxRequestIDLength), true},
+		{strings.Repeat("a", MaxRequestIDLength+1), false},
+		{"", false},
+		{"with space", false},
+		{"line\nbreak", false},
+		{`quote"`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.requestID, func(t *testing.T) {
+			assert.Equal(t, tt.valid, ValidRequestID(tt.requestID))
+		})
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+)
+
+// RedactedValue replaces the values of sensitive secrets in logs and debug output.
+const RedactedValue = "***stripped***"
+
+// ErrPasswordAuthForbidden is returned by ValidateKeyAuth for secrets which authenticate
+// with a password only while password authentication is forbidden.
+var ErrPasswordAuthForbidden = errors.New("password authentication is forbidden")
+
+// nonSensitiveSecrets are the secret keys whose values are safe to log. The values of all
+// other keys, including unknown ones, are redacted.
+var nonSensitiveSecrets = map[string]bool{
+	RealmConnectionContext.RealmAddress:        true,
+	RealmConnectionContext.Username:            true,
+	RealmConnectionContext.HostKey:             true,
+	RealmConnectionContext.SerializeOperations: true,
+	RealmConnectionContext.CompressOutput:      true,
+	RealmConnectionContext.QuotaUnit:           true,
+	RealmConnectionContext.CredentialsHandle:   true,
+	RealmConnectionContext.RealmName:           true,
+	RealmConnectionContext.RealmVersion:        true,
+}
+
+// RealmSecrets are the realm connection secrets of a request, parsed once from the secrets
+// map of the CSI request or the credential provider. The String and MarshalLog methods
+// redact the password, private key, API token and KMIP data, so RealmSecrets can be
+// logged safely.
+type RealmSecrets struct {
+	// Realm is the configured comma-separated list of realm addresses.
+	Realm string
+	// Addresses are the parsed realm addresses, empty if the realm is invalid.
+	Addresses []string
+	// Username is the user authenticating with the realm.
+	Username string
+	// Password is the password of the user.
+	Password string
+	// PrivateKey is the PEM encoded SSH private key of the user.
+	PrivateKey string
+	// PrivateKeyPassphrase is the passphrase of the private key.
+	PrivateKeyPassphrase string
+	// APIToken is the token authenticating with the REST realm provider.
+	APIToken string
+	// HostKey are the trusted SSH host keys of the realm.
+	HostKey string
+	// KMIPConfigData is the KMIP configuration of encrypted volumes.
+	KMIPConfigData string
+	// KMIPCABundle is the CA bundle of the KMIP server.
+	KMIPCABundle string
+	// KMIPClientCert is the client certificate authenticating with the KMIP server.
+	KMIPClientCert string
+	// KMIPClientKey is the key of the KMIP client certificate.
+	KMIPClientKey string
+	// CredentialsHandle is the handle of the credential provider the secrets were read from.
+	CredentialsHandle string
+
+	// values are all secrets, including keys without a field, e.g. the realm options.
+	values map[string]string
+}
+
+// NewRealmSecrets parses the secrets map without validating it, e.g. for realm providers
+// which validate the credentials they support themselves.
+//
+// Parameters:
+//
+//	secrets - Map of secret keys and values.
+//
+// Returns:
+//
+//	RealmSecrets - The parsed secrets.
+func NewRealmSecrets(secrets map[string]string) RealmSecrets {
+	s := RealmSecrets{
+		Realm:                secrets[RealmConnectionContext.RealmAddress],
+		Username:             secrets[RealmConnectionContext.Username],
+		Password:             secrets[RealmConnectionContext.Password],
+		PrivateKey:           secrets[RealmConnectionContext.PrivateKey],
+		PrivateKeyPassphrase: secrets[RealmConnectionContext.PrivateKeyPassphrase],
+		APIToken:             secrets[RealmConnectionContext.APIToken],
+		HostKey:              secrets[RealmConnectionContext.HostKey],
+		KMIPConfigData:       secrets[RealmConnectionContext.KMIPConfigData],
+		KMIPCABundle:         secrets[RealmConnectionContext.KMIPCABundle],
+		KMIPClientCert:       secrets[RealmConnectionContext.KMIPClientCert],
+		KMIPClientKey:        secrets[RealmConnectionContext.KMIPClientKey],
+		CredentialsHandle:    secrets[RealmConnectionContext.CredentialsHandle],
+		values:               maps.Clone(secrets),
+	}
+	if addresses, err := ParseRealmAddresses(s.Realm); err == nil {
+		s.Addresses = addresses
+	}
+	return s
+}
+
+// ParseRealmSecrets parses and validates the secrets of a request. The secrets must hold
+// a valid realm address, a user and a password, private key or API token.
+//
+// Parameters:
+//
+//	secrets - Map of secret keys and values.
+//
+// Returns:
+//
+//	RealmSecrets - The parsed secrets.
+//	error        - Error if required secrets are missing or invalid.
+func ParseRealmSecrets(secrets map[string]string) (RealmSecrets, error) {
+	if secrets == nil {
+		return RealmSecrets{}, fmt.Errorf("secrets must be provided")
+	}
+	s := NewRealmSecrets(secrets)
+	if err := s.Validate(); err != nil {
+		return RealmSecrets{}, err
+	}
+	return s, nil
+}
+
+// Validate checks that the secrets hold a valid realm address, a user and a password,
+// private key or API token.
+//
+// Returns:
+//
+//	error - Error if required secrets are missing or invalid.
+func (s RealmSecrets) Validate() error {
+	if !s.Has(RealmConnectionContext.RealmAddress) {
+		return fmt.Errorf("missing %s in secrets", RealmConnectionContext.RealmAddress)
+	}
+	if _, err := ParseRealmAddresses(s.Realm); err != nil {
+		return fmt.Errorf("invalid %s in secrets: %w", RealmConnectionContext.RealmAddress, err)
+	}
+	if !s.Has(RealmConnectionContext.Username) {
+		return fmt.Errorf("missing %s in secrets", RealmConnectionContext.Username)
+	}
+
+	// the API token only authenticates with the REST realm provider
+	if s.Password == "" && s.PrivateKey == "" && s.APIToken == "" {
+		return fmt.Errorf("no valid authentication credentials provided in secrets, either password, public key or API token is required")
+	}
+	return nil
+}
+
+// ValidateKeyAuth checks that the secrets authenticate without a password, e.g. once
+// key-based authentication is rolled out to all realms. The secrets must hold a private
+// key, or an API token for the REST realm provider.
+//
+// Returns:
+//
+//	error - ErrPasswordAuthForbidden with migration instructions if the secrets hold no
+//	        private key or API token.
+func (s RealmSecrets) ValidateKeyAuth() error {
+	if s.PrivateKey != "" || s.APIToken != "" {
+		return nil
+	}
+	return fmt.Errorf("%w: add %s (and %s if the key is encrypted) to the secret, or %s for the REST realm provider, "+
+		"authorize the key for user %q on the realm, then remove %s from the secret",
+		ErrPasswordAuthForbidden, RealmConnectionContext.PrivateKey, RealmConnectionContext.PrivateKeyPassphrase,
+		RealmConnectionContext.APIToken, s.Username, RealmConnectionContext.Password)
+}
+
+// Has reports whether the secrets hold the key, even with an empty value.
+//
+// Parameters:
+//
+//	key - The secret key, see RealmConnectionContext.
+//
+// Returns:
+//
+//	bool - True if the key is present.
+func (s RealmSecrets) Has(key string) bool {
+	_, ok := s.values[key]
+	return ok
+}
+
+// Get returns the value of a secret key, e.g. of a realm option without a field.
+//
+// Parameters:
+//
+//	key - The secret key, see RealmConnectionContext.
+//
+// Returns:
+//
+//	string - The value, empty if the key is missing.
+func (s RealmSecrets) Get(key string) string {
+	return s.values[key]
+}
+
+// Map returns the secrets as a map, e.g. for realm providers taking the secrets map.
+//
+// Returns:
+//
+//	map[string]string - A copy of the parsed secrets map.
+func (s RealmSecrets) Map() map[string]string {
+	return maps.Clone(s.values)
+}
+
+// Redacted returns the secrets as a map with the values of all sensitive keys replaced
+// by RedactedValue. Only the realm address, user, host key, credentials handle and realm
+// options are kept.
+//
+// Returns:
+//
+//	map[string]string - The redacted copy of the secrets map.
+func (s RealmSecrets) Redacted() map[string]string {
+	return RedactSecrets(s.values)
+}
+
+// String returns the redacted secrets in a stable order.
+//
+// Returns:
+//
+//	string - The redacted secrets, e.g. "password=***stripped*** realm_ip=10.0.0.1".
+func (s RealmSecrets) String() string {
+	redacted := s.Redacted()
+	keys := make([]string, 0, len(redacted))
+	for key := range redacted {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+redacted[key])
+	}
+	return strings.Join(pairs, " ")
+}
+
+// MarshalLog returns the redacted secrets for structured loggers, so the secrets can be
+// passed as a log value.
+//
+// Returns:
+//
+//	any - The redacted secrets map.
+func (s RealmSecrets) MarshalLog() any {
+	return s.Redacted()
+}
+
+// RedactSecrets returns a copy of a secrets map with the values of all sensitive keys
+// replaced by RedactedValue, including keys unknown to the driver.
+//
+// Parameters:
+//
+//	secrets - Map of secret keys and values.
+//
+// Returns:
+//
+//	map[string]string - The redacted copy, nil for a nil map.
+func RedactSecrets(secrets map[string]string) map[string]string {
+	if secrets == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(secrets))
+	for key, value := range secrets {
+		if !nonSensitiveSecrets[key] {
+			value = RedactedValue
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseRealmSecretsRealmAddress verifies that IPv4, IPv6 and hostname realm addresses are accepted.
+func TestParseRealmSecretsRealmAddress(t *testing.T) {
+	tests := map[string]bool{
+		"10.11.12.13":       true,
+		"fd00::1":           true,
+		"[fd00::1]":         true,
+		"realm.example.com": true,
+		"":                  false,
+		"realm:22":          false,
+		"[10.11.12.13]":     false,
+	}
+
+	for realm, valid := range tests {
+		_, err := ParseRealmSecrets(map[string]string{
+			RealmConnectionContext.RealmAddress: realm,
+			RealmConnectionContext.Username:     "dummy",
+			RealmConnectionContext.Password:     "dummy",
+		})
+		if valid && err != nil {
+			t.Errorf("realm %q: unexpected error: %v", realm, err)
+		}
+		if !valid && (err == nil || !strings.Contains(err.Error(), "invalid realm_ip in secrets")) {
+			t.Errorf("realm %q: expected invalid realm error, got: %v", realm, err)
+		}
+	}
+}
+
+// TestParseRealmSecretsCredentials verifies that a password, private key or API token is required.
+func TestParseRealmSecretsCredentials(t *testing.T) {
+	tests := map[string]bool{
+		RealmConnectionContext.Password:   true,
+		RealmConnectionContext.PrivateKey: true,
+		RealmConnectionContext.APIToken:   true,
+		RealmConnectionContext.QuotaUnit:  false,
+	}
+
+	for key, valid := range tests {
+		_, err := ParseRealmSecrets(map[string]string{
+			RealmConnectionContext.RealmAddress: "10.11.12.13",
+			RealmConnectionContext.Username:     "dummy",
+			key:                                 "dummy",
+		})
+		if valid && err != nil {
+			t.Errorf("%s: unexpected error: %v", key, err)
+		}
+		if !valid && (err == nil || !strings.Contains(err.Error(), "no valid authentication credentials")) {
+			t.Errorf("%s: expected missing credentials error, got: %v", key, err)
+		}
+	}
+}
+
+// TestParseRealmSecretsMissing verifies the errors of missing secrets and keys.
+func TestParseRealmSecretsMissing(t *testing.T) {
+	_, err := ParseRealmSecrets(nil)
+	assert.EqualError(t, err, "secrets must be provided")
+
+	_, err = ParseRealmSecrets(map[string]string{RealmConnectionContext.Username: "admin"})
+	assert.EqualError(t, err, "missing realm_ip in secrets")
+
+	_, err = ParseRealmSecrets(map[string]string{RealmConnectionContext.RealmAddress: "10.0.0.1"})
+	assert.EqualError(t, err, "missing user in secrets")
+
+	// an empty user is accepted as long as the key is present
+	_, err = ParseRealmSecrets(map[string]string{
+		RealmConnectionContext.RealmAddress: "10.0.0.1",
+		RealmConnectionContext.Username:     "",
+		RealmConnectionContext.APIToken:     "token",
+	})
+	assert.NoError(t, err)
+}
+
+// TestRealmSecrets verifies the parsed fields and that Map returns a copy of all secrets.
+func TestRealmSecrets(t *testing.T) {
+	secrets := map[string]string{
+		RealmConnectionContext.RealmAddress:         "10.0.0.1, 10.0.0.2",
+		RealmConnectionContext.Username:             "admin",
+		RealmConnectionContext.PrivateKey:           "key",
+		RealmConnectionContext.PrivateKeyPassphrase: "passphrase",
+		RealmConnectionContext.QuotaUnit:            "GiB",
+	}
+
+	s, err := ParseRealmSecrets(secrets)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, s.Addresses)
+	assert.Equal(t, "admin", s.Username)
+	assert.Equal(t, "key", s.PrivateKey)
+	assert.Equal(t, "passphrase", s.PrivateKeyPassphrase)
+	assert.Empty(t, s.Password)
+	assert.Equal(t, "GiB", s.Get(RealmConnectionContext.QuotaUnit))
+	assert.True(t, s.Has(RealmConnectionContext.QuotaUnit))
+	assert.False(t, s.Has(RealmConnectionContext.Password))
+
+	m := s.Map()
+	assert.Equal(t, secrets, m)
+	m[RealmConnectionContext.Username] = "other"
+	assert.Equal(t, "admin", s.Map()[RealmConnectionContext.Username])
+
+	// invalid realms are left to Validate
+	assert.Empty(t, NewRealmSecrets(map[string]string{RealmConnectionContext.RealmAddress: "realm:22"}).Addresses)
+}
+
+// TestRealmSecretsRedacted verifies that credentials, KMIP data and unknown keys never
+// appear in the redacted forms of the secrets.
+func TestRealmSecretsRedacted(t *testing.T) {
+	s := NewRealmSecrets(map[string]string{
+		RealmConnectionContext.RealmAddress:         "10.0.0.1",
+		RealmConnectionContext.Username:             "admin",
+		RealmConnectionContext.Password:             "secret-password",
+		RealmConnectionContext.PrivateKey:           "secret-key",
+		RealmConnectionContext.PrivateKeyPassphrase: "secret-passphrase",
+		RealmConnectionContext.APIToken:             "secret-token",
+		RealmConnectionContext.KMIPConfigData:       "secret-kmip",
+		RealmConnectionContext.KMIPClientKey:        "secret-kmip-key",
+		RealmConnectionContext.QuotaUnit:            "GiB",
+		"unknown":                                   "secret-unknown",
+	})
+
+	redacted := s.Redacted()
+	assert.Equal(t, "10.0.0.1", redacted[RealmConnectionContext.RealmAddress])
+	assert.Equal(t, "admin", redacted[RealmConnectionContext.Username])
+	assert.Equal(t, "GiB", redacted[RealmConnectionContext.QuotaUnit])
+	assert.Equal(t, RedactedValue, redacted[RealmConnectionContext.Password])
+	assert.Equal(t, RedactedValue, redacted["unknown"])
+
+	for _, out := range []string{s.String(), fmt.Sprintf("%v", s), fmt.Sprintf("%v", s.MarshalLog())} {
+		assert.NotContains(t, out, "secret-")
+		assert.Contains(t, out, "realm_ip")
+	}
+	assert.Equal(t, "api_token=***stripped*** kmip_client_key=***stripped*** kmip_config_data=***stripped*** "+
+		"password=***stripped*** private_key=***stripped*** private_key_passphrase=***stripped*** "+
+		"quotaUnit=GiB realm_ip=10.0.0.1 unknown=***stripped*** user=admin", s.String())
+
+	assert.Nil(t, RedactSecrets(nil))
+}
+
+// TestRealmSecretsValidateKeyAuth verifies that only secrets with a private key or API token
+// pass when password authentication is forbidden.
+func TestRealmSecretsValidateKeyAuth(t *testing.T) {
+	base := map[string]string{
+		RealmConnectionContext.RealmAddress: "10.0.0.1",
+		RealmConnectionContext.Username:     "admin",
+		RealmConnectionContext.Password:     "secret",
+	}
+
+	err := NewRealmSecrets(base).ValidateKeyAuth()
+	assert.ErrorIs(t, err, ErrPasswordAuthForbidden)
+	assert.ErrorContains(t, err, `add private_key (and private_key_passphrase if the key is encrypted) to the secret`)
+	assert.ErrorContains(t, err, `authorize the key for user "admin" on the realm, then remove password from the secret`)
+
+	for _, key := range []string{RealmConnectionContext.PrivateKey, RealmConnectionContext.APIToken} {
+		secrets := map[string]string{key: "value"}
+		for k, v := range base {
+			secrets[k] = v
+		}
+		assert.NoError(t, NewRealmSecrets(secrets).ValidateKeyAuth(), key)
+	}
+}
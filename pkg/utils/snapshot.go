@@ -0,0 +1,106 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// snapshotIDSeparator separates the volume and snapshot names in a snapshot ID. PanFS snapshot
+// names are unique per volume only, so the ID carries both.
+const snapshotIDSeparator = "@"
+
+// SnapshotList represents the XML structure returned by the `pasxml snapshots` command.
+type SnapshotList struct {
+	XMLName   xml.Name   `xml:"pasxml"`
+	Version   string     `xml:"version,attr"`
+	Snapshots []Snapshot `xml:"snapshots>snapshot"`
+}
+
+// Snapshot represents a single snapshot of a PanFS volume.
+type Snapshot struct {
+	XMLName    xml.Name   `xml:"snapshot"`
+	ID         string     `xml:"id,attr"`
+	Name       string     `xml:"name"`
+	VolumeName VolumeName `xml:"volumeName"`
+	// CreationTime is the creation time of the snapshot in seconds since the epoch.
+	CreationTime int64 `xml:"creationTime"`
+}
+
+// SnapshotID returns the CSI snapshot ID of the snapshot.
+func (s *Snapshot) SnapshotID() string {
+	return MakeSnapshotID(string(s.VolumeName), s.Name)
+}
+
+// CreatedAt returns the creation time of the snapshot.
+func (s *Snapshot) CreatedAt() time.Time {
+	return time.Unix(s.CreationTime, 0)
+}
+
+// MakeSnapshotID builds the CSI snapshot ID of a snapshot.
+//
+// Parameters:
+//
+//	volumeName   - The name of the snapshotted volume.
+//	snapshotName - The name of the snapshot.
+//
+// Returns:
+//
+//	string - The snapshot ID, "<volume>@<snapshot>".
+func MakeSnapshotID(volumeName, snapshotName string) string {
+	return volumeName + snapshotIDSeparator + snapshotName
+}
+
+// ParseSnapshotID splits a CSI snapshot ID into the volume and snapshot names.
+//
+// Parameters:
+//
+//	id - The snapshot ID, "<volume>@<snapshot>".
+//
+// Returns:
+//
+//	string - The name of the snapshotted volume.
+//	string - The name of the snapshot.
+//	error  - Error if the ID is not a valid snapshot ID.
+func ParseSnapshotID(id string) (string, string, error) {
+	volumeName, snapshotName, ok := strings.Cut(id, snapshotIDSeparator)
+	if !ok || volumeName == "" || snapshotName == "" {
+		return "", "", fmt.Errorf("invalid snapshot id %q: expected <volume>%s<snapshot>", id, snapshotIDSeparator)
+	}
+	return volumeName, snapshotName, nil
+}
+
+// ParseListSnapshots parses the XML output from the `pasxml snapshots` command.
+//
+// Parameters:
+//
+//	snapshots - The XML byte slice containing the snapshot list.
+//
+// Returns:
+//
+//	*SnapshotList - The parsed SnapshotList structure.
+//	error         - Error if parsing fails.
+func ParseListSnapshots(snapshots []byte) (*SnapshotList, error) {
+	var res SnapshotList
+
+	err := UnmarshalPasxml(snapshots, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotID(t *testing.T) {
+	id := MakeSnapshotID("pvc-1", "snapshot-1")
+	assert.Equal(t, "pvc-1@snapshot-1", id)
+
+	volumeName, snapshotName, err := ParseSnapshotID(id)
+	assert.NoError(t, err)
+	assert.Equal(t, "pvc-1", volumeName)
+	assert.Equal(t, "snapshot-1", snapshotName)
+
+	for _, invalid := range []string{"", "pvc-1", "@snapshot-1", "pvc-1@"} {
+		_, _, err := ParseSnapshotID(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParseListSnapshots(t *testing.T) {
+	out := []byte(`<pasxml version="6.0.0">
+    <snapshots>
+        <snapshot id="12">
+            <name>snapshot-1</name>
+            <volumeName>/pvc-1</volumeName>
+            <creationTime>1700000000</creationTime>
+        </snapshot>
+    </snapshots>
+</pasxml>`)
+
+	list, err := ParseListSnapshots(out)
+	require.NoError(t, err)
+	require.Len(t, list.Snapshots, 1)
+	snapshot := list.Snapshots[0]
+	assert.Equal(t, "pvc-1@snapshot-1", snapshot.SnapshotID())
+	assert.Equal(t, time.Unix(1700000000, 0), snapshot.CreatedAt())
+
+	_, err = ParseListSnapshots([]byte("not xml"))
+	assert.Error(t, err)
+}
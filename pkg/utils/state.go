@@ -0,0 +1,78 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// VolumeState is the state of a PanFS volume as reported by pasxml.
+type VolumeState string
+
+// Known volume states. States not known to the driver are parsed as VolumeStateUnknown.
+const (
+	VolumeStateOnline   VolumeState = "Online"
+	VolumeStateOffline  VolumeState = "Offline"
+	VolumeStateCreating VolumeState = "Creating"
+	VolumeStateDeleting VolumeState = "Deleting"
+	VolumeStateUnknown  VolumeState = "Unknown"
+)
+
+var knownVolumeStates = []VolumeState{
+	VolumeStateOnline,
+	VolumeStateOffline,
+	VolumeStateCreating,
+	VolumeStateDeleting,
+}
+
+// ParseVolumeState parses a volume state, ignoring case and surrounding whitespace.
+//
+// Parameters:
+//
+//	s - The state string, e.g. "Online".
+//
+// Returns:
+//
+//	VolumeState - The known state, or VolumeStateUnknown.
+func ParseVolumeState(s string) VolumeState {
+	s = strings.TrimSpace(s)
+	for _, state := range knownVolumeStates {
+		if strings.EqualFold(s, string(state)) {
+			return state
+		}
+	}
+	return VolumeStateUnknown
+}
+
+// IsUsable reports whether the volume can be mounted and used by workloads.
+func (s VolumeState) IsUsable() bool {
+	return s == VolumeStateOnline
+}
+
+// IsTransient reports whether the volume is expected to leave the state on its own.
+func (s VolumeState) IsTransient() bool {
+	return s == VolumeStateCreating || s == VolumeStateDeleting
+}
+
+// UnmarshalXML implements Unmarshaler interface to parse the state of a volume.
+func (s *VolumeState) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var content string
+	if err := d.DecodeElement(&content, &start); err != nil {
+		return err
+	}
+	*s = ParseVolumeState(content)
+	return nil
+}
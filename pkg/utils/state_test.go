@@ -0,0 +1,53 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVolumeState(t *testing.T) {
+	tests := map[string]VolumeState{
+		"Online":    VolumeStateOnline,
+		" online\n": VolumeStateOnline,
+		"OFFLINE":   VolumeStateOffline,
+		"Creating":  VolumeStateCreating,
+		"Deleting":  VolumeStateDeleting,
+		"Degraded":  VolumeStateUnknown,
+		"":          VolumeStateUnknown,
+	}
+	for input, want := range tests {
+		assert.Equal(t, want, ParseVolumeState(input), "input %q", input)
+	}
+
+	assert.True(t, VolumeStateOnline.IsUsable())
+	assert.False(t, VolumeStateCreating.IsUsable())
+	assert.True(t, VolumeStateCreating.IsTransient())
+	assert.False(t, VolumeStateOffline.IsTransient())
+}
+
+func TestVolumeStateUnmarshalXML(t *testing.T) {
+	list, err := ParseListVolumes([]byte(`<pasxml version="6.0.0"><volumes>
+		<volume id="1"><name>/home</name><state>Online</state></volume>
+		<volume id="2"><name>/new</name><state> creating </state></volume>
+		<volume id="3"><name>/odd</name><state>Rebuilding</state></volume>
+	</volumes></pasxml>`))
+	assert.NoError(t, err)
+	assert.Equal(t, VolumeStateOnline, list.Volumes[0].State)
+	assert.Equal(t, VolumeStateCreating, list.Volumes[1].State)
+	assert.Equal(t, VolumeStateUnknown, list.Volumes[2].State)
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import "encoding/xml"
+
+// VolumeStatsList represents the XML structure returned by the `pasxml volumestats` command.
+type VolumeStatsList struct {
+	XMLName xml.Name      `xml:"pasxml"`
+	Version string        `xml:"version,attr"`
+	Volumes []VolumeStats `xml:"volumeStats>volume"`
+}
+
+// VolumeStats represents the performance counters of a single volume in the PanFS system.
+// The byte and operation counters grow monotonically until the realm restarts, the latencies
+// are averages over the sampling interval of the realm.
+type VolumeStats struct {
+	XMLName        xml.Name   `xml:"volume"`
+	ID             string     `xml:"id,attr"`
+	Name           VolumeName `xml:"name"`
+	ReadBytes      float64    `xml:"readBytes"`
+	WriteBytes     float64    `xml:"writeBytes"`
+	ReadOps        float64    `xml:"readOps"`
+	WriteOps       float64    `xml:"writeOps"`
+	ReadLatencyUs  float64    `xml:"readLatencyUs"`
+	WriteLatencyUs float64    `xml:"writeLatencyUs"`
+}
+
+// ParseVolumeStats parses the XML output of the `pasxml volumestats` command.
+//
+// Parameters:
+//
+//	stats - The XML output of the command.
+//
+// Returns:
+//
+//	*VolumeStatsList - The parsed performance counters.
+//	error            - Error if the output cannot be parsed.
+func ParseVolumeStats(stats []byte) (*VolumeStatsList, error) {
+	var res VolumeStatsList
+
+	err := UnmarshalPasxml(stats, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 VDURA Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVolumeStats(t *testing.T) {
+	out := []byte(`<pasxml version="6.0.0">
+    <volumeStats>
+        <volume id="3">
+            <name>/pvc-1</name>
+            <readBytes>1048576</readBytes>
+            <writeBytes>2097152</writeBytes>
+            <readOps>16</readOps>
+            <writeOps>32</writeOps>
+            <readLatencyUs>150.5</readLatencyUs>
+            <writeLatencyUs>300</writeLatencyUs>
+        </volume>
+    </volumeStats>
+</pasxml>`)
+
+	list, err := ParseVolumeStats(out)
+	require.NoError(t, err)
+	require.Len(t, list.Volumes, 1)
+	assert.Equal(t, VolumeStats{
+		XMLName:        list.Volumes[0].XMLName,
+		ID:             "3",
+		Name:           "pvc-1",
+		ReadBytes:      1 << 20,
+		WriteBytes:     2 << 20,
+		ReadOps:        16,
+		WriteOps:       32,
+		ReadLatencyUs:  150.5,
+		WriteLatencyUs: 300,
+	}, list.Volumes[0])
+
+	_, err = ParseVolumeStats([]byte("not xml"))
+	assert.Error(t, err)
+}